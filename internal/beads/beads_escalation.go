@@ -27,6 +27,8 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	DeliveryStatus     string // External delivery results (e.g., "email=sent,sms=failed")
+	DeliveredAt        string // When external delivery last ran (empty if never)
 }
 
 
@@ -97,6 +99,13 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	// Delivery fields are only written once external actions have run,
+	// so escalations routed to bead/mail only keep their original shape.
+	if fields.DeliveryStatus != "" {
+		lines = append(lines, fmt.Sprintf("delivery_status: %s", fields.DeliveryStatus))
+		lines = append(lines, fmt.Sprintf("delivered_at: %s", fields.DeliveredAt))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -152,6 +161,10 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery_status":
+			fields.DeliveryStatus = value
+		case "delivered_at":
+			fields.DeliveredAt = value
 		}
 	}
 
@@ -264,6 +277,35 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	return err
 }
 
+// RecordEscalationDelivery stores the outcome of external notification
+// delivery (email, SMS, Slack, webhook, log) on an escalation bead.
+// status is a compact "channel=status" list; a "delivery-failed" label is
+// added when any channel failed so undelivered pages are easy to find.
+func (b *Beads) RecordEscalationDelivery(id, status string, failed bool) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", id)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	fields.DeliveryStatus = status
+	fields.DeliveredAt = time.Now().Format(time.RFC3339)
+
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	opts := UpdateOptions{Description: &description}
+	if failed {
+		opts.AddLabels = []string{"delivery-failed"}
+	} else if HasLabel(issue, "delivery-failed") {
+		opts.RemoveLabels = []string{"delivery-failed"}
+	}
+	return b.Update(id, opts)
+}
+
 // GetEscalationBead retrieves an escalation bead by ID.
 // Returns nil if not found.
 func (b *Beads) GetEscalationBead(id string) (*Issue, *EscalationFields, error) {
@@ -110,6 +110,31 @@ func TestFormatEscalationDescription(t *testing.T) {
 				"last_reescalated_by: deacon",
 			},
 		},
+		{
+			name:  "delivery fields omitted until delivery runs",
+			title: "Mail only",
+			fields: &EscalationFields{
+				Severity:    "medium",
+				EscalatedBy: "gastown/witness",
+				EscalatedAt: "2024-01-15T10:00:00Z",
+			},
+			notIn: []string{"delivery_status:", "delivered_at:"},
+		},
+		{
+			name:  "delivery fields",
+			title: "Paged",
+			fields: &EscalationFields{
+				Severity:       "critical",
+				EscalatedBy:    "gastown/deacon",
+				EscalatedAt:    "2024-01-15T10:00:00Z",
+				DeliveryStatus: "email=sent,sms=failed",
+				DeliveredAt:    "2024-01-15T10:00:05Z",
+			},
+			want: []string{
+				"delivery_status: email=sent,sms=failed",
+				"delivered_at: 2024-01-15T10:00:05Z",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,6 +282,8 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 		ReescalationCount: 1,
 		LastReescalatedAt: "2024-06-15T11:30:00Z",
 		LastReescalatedBy: "deacon",
+		DeliveryStatus:    "email=sent,log=sent",
+		DeliveredAt:       "2024-06-15T12:00:03Z",
 	}
 
 	formatted := FormatEscalationDescription("Escalation: Agent stuck", original)
@@ -298,6 +325,12 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	if parsed.LastReescalatedBy != original.LastReescalatedBy {
 		t.Errorf("LastReescalatedBy: got %q, want %q", parsed.LastReescalatedBy, original.LastReescalatedBy)
 	}
+	if parsed.DeliveryStatus != original.DeliveryStatus {
+		t.Errorf("DeliveryStatus: got %q, want %q", parsed.DeliveryStatus, original.DeliveryStatus)
+	}
+	if parsed.DeliveredAt != original.DeliveredAt {
+		t.Errorf("DeliveredAt: got %q, want %q", parsed.DeliveredAt, original.DeliveredAt)
+	}
 }
 
 func TestBumpSeverity(t *testing.T) {
@@ -107,45 +107,35 @@ func resolveBeadsDirWithDepth(beadsDir string, maxDepth int) string {
 	return resolveBeadsDirWithDepth(resolved, maxDepth-1)
 }
 
-// cleanBeadsRuntimeFiles removes gitignored runtime files from a .beads directory
-// while preserving tracked files (formulas/, README.md, config.yaml, .gitignore).
-// This is safe to call even if the directory doesn't exist.
-func cleanBeadsRuntimeFiles(beadsDir string) error {
-	if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
-		return nil // Nothing to clean
-	}
-
-	// Runtime files/patterns that are gitignored and safe to remove
-	runtimePatterns := []string{
-		// Daemon runtime
-		"daemon.lock", "daemon.log", "daemon.pid", "bd.sock",
-		// Sync state
-		"last-touched", "metadata.json",
-		// Version tracking
-		".local_version",
-		// Redirect file (we're about to recreate it)
-		"redirect",
-		// Runtime directories
-		"mq",
-	}
+// runtimePatterns are the gitignored runtime files/patterns in a .beads
+// directory that are safe to remove.
+var runtimePatterns = []string{
+	// Daemon runtime
+	"daemon.lock", "daemon.log", "daemon.pid", "bd.sock",
+	// Sync state
+	"last-touched", "metadata.json",
+	// Version tracking
+	".local_version",
+	// Redirect file (we're about to recreate it)
+	"redirect",
+	// Runtime directories
+	"mq",
+}
 
-	var firstErr error
+// runtimeFiles returns the gitignored runtime files present in a .beads
+// directory: the files SetupRedirect removes before writing the redirect.
+// Tracked files (formulas/, README.md, config.yaml, .gitignore) are never
+// included.
+func runtimeFiles(beadsDir string) []string {
+	var files []string
 	for _, pattern := range runtimePatterns {
 		matches, err := filepath.Glob(filepath.Join(beadsDir, pattern))
 		if err != nil {
-			if firstErr == nil {
-				firstErr = err
-			}
 			continue
 		}
-		for _, match := range matches {
-			if err := os.RemoveAll(match); err != nil && firstErr == nil {
-				firstErr = err
-			}
-		}
+		files = append(files, matches...)
 	}
-
-	return firstErr
+	return files
 }
 
 // ComputeRedirectTarget computes the expected redirect target for a worktree.
@@ -251,6 +241,35 @@ func ComputeRedirectTarget(townRoot, worktreePath string) (string, error) {
 	return redirectPath, nil
 }
 
+// RedirectSetup lists the changes SetupRedirect makes to a worktree, for
+// callers that apply and record the changes themselves (gt doctor --fix).
+type RedirectSetup struct {
+	Target   string   // Content of the redirect file, without the newline
+	Remove   []string // Removed first: a stale .beads file, or runtime files in .beads/
+	Redirect string   // Path of the redirect file
+}
+
+// PlanRedirect computes the changes SetupRedirect would make to worktreePath
+// without making them.
+func PlanRedirect(townRoot, worktreePath string) (*RedirectSetup, error) {
+	target, err := ComputeRedirectTarget(townRoot, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	beadsDir := filepath.Join(worktreePath, ".beads")
+	setup := &RedirectSetup{Target: target, Redirect: filepath.Join(beadsDir, "redirect")}
+
+	// If .beads exists as a file (not directory), it is removed instead.
+	// This can happen with stale state from previous failed operations or
+	// unusual clone state. MkdirAll would fail with "file exists" in this case.
+	if info, err := os.Stat(beadsDir); err == nil && !info.IsDir() {
+		setup.Remove = []string{beadsDir}
+	} else {
+		setup.Remove = runtimeFiles(beadsDir)
+	}
+	return setup, nil
+}
+
 // SetupRedirect creates a .beads/redirect file for a worktree to point to the rig's shared beads.
 // This is used by crew, polecats, and refinery worktrees to share the rig's beads database.
 //
@@ -266,10 +285,11 @@ func ComputeRedirectTarget(townRoot, worktreePath string) (string, error) {
 // Safety: This function refuses to create redirects in the canonical beads location
 // (mayor/rig) to prevent circular redirect chains.
 func SetupRedirect(townRoot, worktreePath string) error {
-	redirectPath, err := ComputeRedirectTarget(townRoot, worktreePath)
+	setup, err := PlanRedirect(townRoot, worktreePath)
 	if err != nil {
 		return err
 	}
+	redirectPath := setup.Target
 
 	// Warn only when using mayor fallback WITHOUT a redirect file.
 	// When rig/.beads/redirect exists pointing to mayor/rig/.beads, that's the
@@ -288,30 +308,25 @@ func SetupRedirect(townRoot, worktreePath string) error {
 		}
 	}
 
-	// Clean up runtime files in .beads/ but preserve tracked files (formulas/, README.md, etc.)
-	worktreeBeadsDir := filepath.Join(worktreePath, ".beads")
-
-	// Handle edge case: if .beads exists as a file (not directory), remove it.
-	// This can happen with stale state from previous failed operations or
-	// unusual clone state. MkdirAll would fail with "file exists" in this case.
-	if info, err := os.Stat(worktreeBeadsDir); err == nil && !info.IsDir() {
-		if err := os.Remove(worktreeBeadsDir); err != nil {
-			return fmt.Errorf("removing stale .beads file: %w", err)
+	// Clean up a stale .beads file, or runtime files in .beads/ while
+	// preserving tracked files (formulas/, README.md, etc.)
+	var firstErr error
+	for _, path := range setup.Remove {
+		if err := os.RemoveAll(path); err != nil && firstErr == nil {
+			firstErr = err
 		}
 	}
-
-	if err := cleanBeadsRuntimeFiles(worktreeBeadsDir); err != nil {
-		return fmt.Errorf("cleaning runtime files: %w", err)
+	if firstErr != nil {
+		return fmt.Errorf("cleaning runtime files: %w", firstErr)
 	}
 
 	// Create .beads directory if it doesn't exist
-	if err := os.MkdirAll(worktreeBeadsDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(setup.Redirect), 0755); err != nil {
 		return fmt.Errorf("creating .beads dir: %w", err)
 	}
 
 	// Create redirect file
-	redirectFile := filepath.Join(worktreeBeadsDir, "redirect")
-	if err := os.WriteFile(redirectFile, []byte(redirectPath+"\n"), 0644); err != nil {
+	if err := os.WriteFile(setup.Redirect, []byte(redirectPath+"\n"), 0644); err != nil {
 		return fmt.Errorf("creating redirect file: %w", err)
 	}
 
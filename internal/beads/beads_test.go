@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -647,6 +648,10 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+
+		MergeStrategy: "merge-commit",
+		FailedTests:   []string{"pkg.TestA", "pkg.TestB/sub"},
+		GateArtifacts: "/town/gastown/.runtime/refinery/gates/gt-mr1",
 	}
 
 	// Format to string
@@ -660,7 +665,7 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		t.Fatal("round-trip parse returned nil")
 	}
 
-	if *parsed != *original {
+	if !reflect.DeepEqual(parsed, original) {
 		t.Errorf("round-trip mismatch:\ngot  %+v\nwant %+v", parsed, original)
 	}
 }
@@ -977,7 +982,7 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 		t.Fatal("round-trip parse returned nil")
 	}
 
-	if *parsed != *original {
+	if !reflect.DeepEqual(parsed, original) {
 		t.Errorf("round-trip mismatch:\ngot  %+v\nwant %+v", parsed, original)
 	}
 }
@@ -17,6 +17,8 @@ type AttachmentFields struct {
 	AttachedAt       string // ISO 8601 timestamp when attached
 	AttachedArgs     string // Natural language args passed via gt sling --args (no-tmux mode)
 	DispatchedBy     string // Agent ID that dispatched this work (for completion notification)
+	DispatchedAgent  string // Agent preset the polecat ran with
+	FailedAgent      string // Agent preset of the last attempt that failed (for model escalation on retry)
 	NoMerge          bool   // If true, gt done skips merge queue (for upstream PRs/human review)
 	Mode             string // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
@@ -69,6 +71,12 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "dispatched_by", "dispatched-by", "dispatchedby":
 			fields.DispatchedBy = value
 			hasFields = true
+		case "dispatched_agent", "dispatched-agent", "dispatchedagent":
+			fields.DispatchedAgent = value
+			hasFields = true
+		case "failed_agent", "failed-agent", "failedagent":
+			fields.FailedAgent = value
+			hasFields = true
 		case "no_merge", "no-merge", "nomerge":
 			fields.NoMerge = strings.ToLower(value) == "true"
 			hasFields = true
@@ -117,6 +125,12 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.DispatchedBy != "" {
 		lines = append(lines, "dispatched_by: "+fields.DispatchedBy)
 	}
+	if fields.DispatchedAgent != "" {
+		lines = append(lines, "dispatched_agent: "+fields.DispatchedAgent)
+	}
+	if fields.FailedAgent != "" {
+		lines = append(lines, "failed_agent: "+fields.FailedAgent)
+	}
 	if fields.NoMerge {
 		lines = append(lines, "no_merge: true")
 	}
@@ -157,6 +171,12 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"dispatched_by":     true,
 		"dispatched-by":     true,
 		"dispatchedby":      true,
+		"dispatched_agent":  true,
+		"dispatched-agent":  true,
+		"dispatchedagent":   true,
+		"failed_agent":      true,
+		"failed-agent":      true,
+		"failedagent":       true,
 		"no_merge":          true,
 		"no-merge":          true,
 		"nomerge":           true,
@@ -224,10 +244,112 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 // ConvoyFields holds the structured fields for a convoy bead.
 // These fields are stored as key: value lines in the issue description.
 type ConvoyFields struct {
-	Owner      string // Convoy owner address (e.g., "mayor/")
-	Notify     string // Additional notification address
-	Molecule   string // Associated molecule/swarm ID
-	Merge      string // Merge strategy
+	Owner    string // Convoy owner address (e.g., "mayor/")
+	Notify   string // Additional notification address
+	Molecule string // Associated molecule/swarm ID
+	Merge    string // Merge strategy
+
+	// Subscriptions are structured notification subscriptions, one
+	// "Subscribe:" line each (see ConvoySubscription).
+	Subscriptions []ConvoySubscription
+}
+
+// Convoy events that subscriptions can fire on.
+const (
+	ConvoyEventLanded    = "landed"    // all tracked issues done (auto-close or gt convoy land)
+	ConvoyEventClosed    = "closed"    // closed manually with gt convoy close
+	ConvoyEventStranded  = "stranded"  // ready work with no workers, or stuck
+	ConvoyEventSynthesis = "synthesis" // synthesis completed (gt synthesis close)
+)
+
+// Convoy subscription channels.
+const (
+	ConvoyChannelMail     = "mail"     // gt mail to an agent address
+	ConvoyChannelNudge    = "nudge"    // gt nudge to an agent session
+	ConvoyChannelEscalate = "escalate" // escalation delivery channels for a severity
+)
+
+// ConvoySubscription is a notification subscription on a convoy, stored as
+//
+//	Subscribe: <channel>:<target> [on <event>,<event>...]
+//
+// e.g. "Subscribe: nudge:gastown/witness on stranded". For the escalate
+// channel, target is a severity whose configured external actions (email,
+// SMS, Slack, ...) receive the message. Without "on", the subscription
+// fires on terminal events (landed, closed, synthesis) but not stranded,
+// which recurs while a convoy stays stuck.
+type ConvoySubscription struct {
+	Channel string
+	Target  string
+	Events  []string
+}
+
+var validConvoyEvents = map[string]bool{
+	ConvoyEventLanded:    true,
+	ConvoyEventClosed:    true,
+	ConvoyEventStranded:  true,
+	ConvoyEventSynthesis: true,
+}
+
+// ParseConvoySubscription parses a subscription spec such as
+// "mail:mayor/" or "escalate:high on stranded,landed". Escalation targets
+// are severities; callers check them against the escalation config.
+func ParseConvoySubscription(spec string) (ConvoySubscription, error) {
+	var sub ConvoySubscription
+	spec = strings.TrimSpace(spec)
+
+	target := spec
+	if idx := strings.Index(spec, " on "); idx != -1 {
+		target = strings.TrimSpace(spec[:idx])
+		for _, ev := range strings.Split(spec[idx+len(" on "):], ",") {
+			ev = strings.ToLower(strings.TrimSpace(ev))
+			if ev == "" {
+				continue
+			}
+			if !validConvoyEvents[ev] {
+				return sub, fmt.Errorf("unknown convoy event %q in subscription %q", ev, spec)
+			}
+			sub.Events = append(sub.Events, ev)
+		}
+		if len(sub.Events) == 0 {
+			return sub, fmt.Errorf("subscription %q has no events after \"on\"", spec)
+		}
+	}
+
+	channel, addr, ok := strings.Cut(target, ":")
+	if !ok || strings.TrimSpace(addr) == "" {
+		return sub, fmt.Errorf("invalid subscription %q (want <channel>:<target>)", spec)
+	}
+	sub.Channel = strings.ToLower(strings.TrimSpace(channel))
+	sub.Target = strings.TrimSpace(addr)
+	switch sub.Channel {
+	case ConvoyChannelMail, ConvoyChannelNudge, ConvoyChannelEscalate:
+	default:
+		return sub, fmt.Errorf("unknown subscription channel %q (want mail, nudge, or escalate)", sub.Channel)
+	}
+	return sub, nil
+}
+
+// String formats the subscription in the form ParseConvoySubscription reads.
+func (s ConvoySubscription) String() string {
+	out := s.Channel + ":" + s.Target
+	if len(s.Events) > 0 {
+		out += " on " + strings.Join(s.Events, ",")
+	}
+	return out
+}
+
+// Matches reports whether the subscription fires on the given event.
+func (s ConvoySubscription) Matches(event string) bool {
+	if len(s.Events) == 0 {
+		return event != ConvoyEventStranded
+	}
+	for _, ev := range s.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
 }
 
 // ParseConvoyFields extracts convoy fields from an issue's description.
@@ -270,6 +392,13 @@ func ParseConvoyFields(issue *Issue) *ConvoyFields {
 		case "merge":
 			fields.Merge = value
 			hasFields = true
+		case "subscribe":
+			// Malformed lines are skipped rather than failing the whole
+			// convoy; they were validated when written.
+			if sub, err := ParseConvoySubscription(value); err == nil {
+				fields.Subscriptions = append(fields.Subscriptions, sub)
+				hasFields = true
+			}
 		}
 	}
 
@@ -315,6 +444,9 @@ func FormatConvoyFields(fields *ConvoyFields) string {
 	if fields.Molecule != "" {
 		lines = append(lines, "Molecule: "+fields.Molecule)
 	}
+	for _, sub := range fields.Subscriptions {
+		lines = append(lines, "Subscribe: "+sub.String())
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -329,10 +461,11 @@ func SetConvoyFields(issue *Issue, fields *ConvoyFields) string {
 
 	// Known convoy field keys (lowercase)
 	convoyKeys := map[string]bool{
-		"owner":    true,
-		"notify":   true,
-		"merge":    true,
-		"molecule": true,
+		"owner":     true,
+		"notify":    true,
+		"merge":     true,
+		"molecule":  true,
+		"subscribe": true,
 	}
 
 	// Collect non-convoy lines from existing description
@@ -392,6 +525,14 @@ type MRFields struct {
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
 
+	// MergeStrategy overrides the rig's merge_queue.merge_strategy for this
+	// MR: "squash", "merge-commit", or "rebase". Empty uses the rig default.
+	MergeStrategy string
+
+	// Gate report fields (set by the refinery when quality gates fail)
+	FailedTests   []string // Failed test IDs parsed from gate output
+	GateArtifacts string   // Directory with full gate logs and report.json
+
 	// Conflict resolution fields (for priority scoring)
 	RetryCount      int    // Number of conflict-resolution cycles
 	LastConflictSHA string // SHA of main when conflict occurred
@@ -457,6 +598,19 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "agent_bead", "agent-bead", "agentbead":
 			fields.AgentBead = value
 			hasFields = true
+		case "merge_strategy", "merge-strategy", "mergestrategy":
+			fields.MergeStrategy = value
+			hasFields = true
+		case "failed_tests", "failed-tests", "failedtests":
+			for _, id := range strings.Split(value, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					fields.FailedTests = append(fields.FailedTests, id)
+				}
+			}
+			hasFields = true
+		case "gate_artifacts", "gate-artifacts", "gateartifacts":
+			fields.GateArtifacts = value
+			hasFields = true
 		case "retry_count", "retry-count", "retrycount":
 			if n, err := parseIntField(value); err == nil {
 				fields.RetryCount = n
@@ -523,6 +677,15 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.AgentBead != "" {
 		lines = append(lines, "agent_bead: "+fields.AgentBead)
 	}
+	if fields.MergeStrategy != "" {
+		lines = append(lines, "merge_strategy: "+fields.MergeStrategy)
+	}
+	if len(fields.FailedTests) > 0 {
+		lines = append(lines, "failed_tests: "+strings.Join(fields.FailedTests, ", "))
+	}
+	if fields.GateArtifacts != "" {
+		lines = append(lines, "gate_artifacts: "+fields.GateArtifacts)
+	}
 	if fields.RetryCount > 0 {
 		lines = append(lines, fmt.Sprintf("retry_count: %d", fields.RetryCount))
 	}
@@ -552,38 +715,47 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 
 	// Known MR field keys (lowercase)
 	mrKeys := map[string]bool{
-		"branch":             true,
-		"target":             true,
-		"source_issue":       true,
-		"source-issue":       true,
-		"sourceissue":        true,
-		"worker":             true,
-		"rig":                true,
-		"merge_commit":       true,
-		"merge-commit":       true,
-		"mergecommit":        true,
-		"close_reason":       true,
-		"close-reason":       true,
-		"closereason":        true,
-		"agent_bead":         true,
-		"agent-bead":         true,
-		"agentbead":          true,
-		"retry_count":        true,
-		"retry-count":        true,
-		"retrycount":         true,
-		"last_conflict_sha":  true,
-		"last-conflict-sha":  true,
-		"lastconflictsha":    true,
-		"conflict_task_id":   true,
-		"conflict-task-id":   true,
-		"conflicttaskid":     true,
-		"convoy_id":          true,
-		"convoy-id":          true,
-		"convoyid":           true,
-		"convoy":             true,
-		"convoy_created_at":  true,
-		"convoy-created-at":  true,
-		"convoycreatedat":    true,
+		"branch":            true,
+		"target":            true,
+		"source_issue":      true,
+		"source-issue":      true,
+		"sourceissue":       true,
+		"worker":            true,
+		"rig":               true,
+		"merge_commit":      true,
+		"merge-commit":      true,
+		"mergecommit":       true,
+		"close_reason":      true,
+		"close-reason":      true,
+		"closereason":       true,
+		"agent_bead":        true,
+		"agent-bead":        true,
+		"agentbead":         true,
+		"merge_strategy":    true,
+		"merge-strategy":    true,
+		"mergestrategy":     true,
+		"failed_tests":      true,
+		"failed-tests":      true,
+		"failedtests":       true,
+		"gate_artifacts":    true,
+		"gate-artifacts":    true,
+		"gateartifacts":     true,
+		"retry_count":       true,
+		"retry-count":       true,
+		"retrycount":        true,
+		"last_conflict_sha": true,
+		"last-conflict-sha": true,
+		"lastconflictsha":   true,
+		"conflict_task_id":  true,
+		"conflict-task-id":  true,
+		"conflicttaskid":    true,
+		"convoy_id":         true,
+		"convoy-id":         true,
+		"convoyid":          true,
+		"convoy":            true,
+		"convoy_created_at": true,
+		"convoy-created-at": true,
+		"convoycreatedat":   true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -828,3 +1000,16 @@ func ExpandRolePattern(pattern, townRoot, rig, name, role, prefix string) string
 	result = strings.ReplaceAll(result, "{prefix}", prefix)
 	return result
 }
+
+// RecordFailedDispatch marks the bead's last dispatch as failed: the agent
+// it was dispatched with becomes FailedAgent, so the next sling can escalate
+// past it. Returns the new description, or "" if the bead records no
+// dispatched agent.
+func RecordFailedDispatch(issue *Issue) string {
+	fields := ParseAttachmentFields(issue)
+	if fields == nil || fields.DispatchedAgent == "" {
+		return ""
+	}
+	fields.FailedAgent = fields.DispatchedAgent
+	return SetAttachmentFields(issue, fields)
+}
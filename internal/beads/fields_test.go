@@ -227,6 +227,36 @@ func TestConvoyFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestDispatchedAgentRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Fix the lint.\n\ndispatched_by: mayor/\ndispatched_agent: claude-haiku"}
+	fields := ParseAttachmentFields(issue)
+	if fields == nil || fields.DispatchedAgent != "claude-haiku" {
+		t.Fatalf("ParseAttachmentFields = %+v, want DispatchedAgent claude-haiku", fields)
+	}
+
+	fields.DispatchedAgent = "claude-sonnet"
+	desc := SetAttachmentFields(issue, fields)
+	if strings.Count(desc, "dispatched_agent:") != 1 || !strings.Contains(desc, "dispatched_agent: claude-sonnet") {
+		t.Errorf("SetAttachmentFields did not replace dispatched_agent, got:\n%s", desc)
+	}
+}
+
+func TestRecordFailedDispatch(t *testing.T) {
+	issue := &Issue{Description: "Fix the lint.\n\ndispatched_agent: claude-haiku"}
+	desc := RecordFailedDispatch(issue)
+	fields := ParseAttachmentFields(&Issue{Description: desc})
+	if fields == nil || fields.FailedAgent != "claude-haiku" || fields.DispatchedAgent != "claude-haiku" {
+		t.Fatalf("after RecordFailedDispatch, fields = %+v", fields)
+	}
+	if !strings.Contains(desc, "Fix the lint.") {
+		t.Errorf("description body lost:\n%s", desc)
+	}
+
+	if got := RecordFailedDispatch(&Issue{Description: "No dispatch yet."}); got != "" {
+		t.Errorf("RecordFailedDispatch without dispatched_agent = %q, want empty", got)
+	}
+}
+
 func TestConvoyOwnedFalseNotFormatted(t *testing.T) {
 	fields := &AttachmentFields{
 		ConvoyID:    "hq-cv-xyz",
@@ -403,6 +433,84 @@ func TestSetConvoyFieldsWithMixedContent(t *testing.T) {
 	}
 }
 
+func TestParseConvoySubscription(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    ConvoySubscription
+		wantErr bool
+	}{
+		{spec: "mail:mayor/", want: ConvoySubscription{Channel: "mail", Target: "mayor/"}},
+		{spec: "nudge:gastown/witness on stranded", want: ConvoySubscription{Channel: "nudge", Target: "gastown/witness", Events: []string{"stranded"}}},
+		{spec: " Escalate:high on landed, Synthesis ", want: ConvoySubscription{Channel: "escalate", Target: "high", Events: []string{"landed", "synthesis"}}},
+		{spec: "mayor/", wantErr: true},
+		{spec: "mail:", wantErr: true},
+		{spec: "pager:ops", wantErr: true},
+		{spec: "mail:mayor/ on exploded", wantErr: true},
+		{spec: "mail:mayor/ on ,", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseConvoySubscription(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConvoySubscription(%q) err = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("ParseConvoySubscription(%q) = %q, want %q", tt.spec, got.String(), tt.want.String())
+			}
+		})
+	}
+}
+
+func TestConvoySubscriptionMatches(t *testing.T) {
+	all := ConvoySubscription{Channel: "mail", Target: "mayor/"}
+	for _, ev := range []string{ConvoyEventLanded, ConvoyEventClosed, ConvoyEventSynthesis} {
+		if !all.Matches(ev) {
+			t.Errorf("subscription without events should match %s", ev)
+		}
+	}
+	if all.Matches(ConvoyEventStranded) {
+		t.Error("subscription without events should not match stranded")
+	}
+
+	stranded := ConvoySubscription{Channel: "nudge", Target: "deacon/", Events: []string{ConvoyEventStranded}}
+	if !stranded.Matches(ConvoyEventStranded) || stranded.Matches(ConvoyEventLanded) {
+		t.Errorf("explicit events not honored: %+v", stranded)
+	}
+}
+
+func TestConvoySubscriptionsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Convoy tracking 2 issues\nOwner: mayor/"}
+	fields := ParseConvoyFields(issue)
+	fields.Subscriptions = []ConvoySubscription{
+		{Channel: "mail", Target: "ops/"},
+		{Channel: "escalate", Target: "high", Events: []string{"stranded", "landed"}},
+	}
+	desc := SetConvoyFields(issue, fields)
+	if !strings.Contains(desc, "Subscribe: escalate:high on stranded,landed") {
+		t.Errorf("missing Subscribe line, got:\n%s", desc)
+	}
+
+	parsed := ParseConvoyFields(&Issue{Description: desc + "\nSubscribe: bogus"})
+	if parsed.Owner != "mayor/" {
+		t.Errorf("Owner: got %q, want mayor/", parsed.Owner)
+	}
+	if len(parsed.Subscriptions) != 2 {
+		t.Fatalf("Subscriptions: got %d, want 2 (malformed line skipped)", len(parsed.Subscriptions))
+	}
+	if parsed.Subscriptions[1].String() != "escalate:high on stranded,landed" {
+		t.Errorf("Subscriptions[1] = %q", parsed.Subscriptions[1].String())
+	}
+
+	// Re-setting replaces, never duplicates, subscription lines.
+	again := SetConvoyFields(&Issue{Description: desc}, parsed)
+	if n := strings.Count(again, "Subscribe:"); n != 2 {
+		t.Errorf("got %d Subscribe lines after re-set, want 2:\n%s", n, again)
+	}
+}
+
 // --- ParseAgentFields (not covered in beads_test.go) ---
 
 func TestParseAgentFields_AllFields(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -107,7 +108,15 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			return cap, nil
 		},
 		QueryPending: func() ([]capacity.PendingBead, error) {
-			return getReadySlingContexts(townRoot)
+			pending, err := getReadySlingContexts(townRoot)
+			if err != nil {
+				return nil, err
+			}
+			// Token budgets apply back-pressure: over-budget work stays scheduled.
+			pending = holdOverBudget(townRoot, settings.Budgets, pending)
+			// Scheduling policies order the rest; rig quotas hold back more.
+			ordered, _ := capacity.Schedule(schedulerCfg, pending, countActivePolecatsByRig(), time.Now())
+			return ordered, nil
 		},
 		Execute: func(b capacity.PendingBead) error {
 			result, err := dispatchSingleBead(b, townRoot, actor)
@@ -342,9 +351,9 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 		return nil, nil
 	}
 
-	// 2. Build readyWorkIDs set from bd ready across all dirs
+	// 2. Build readyWork map from bd ready across all dirs
 	// (work beads live in rig-local DBs, so we need to check all dirs)
-	readyWorkIDs, readyErr := listReadyWorkBeadIDsWithError(townRoot)
+	readyWork, readyErr := listReadyWorkBeadsWithError(townRoot)
 	if readyErr != nil {
 		return nil, readyErr
 	}
@@ -379,7 +388,8 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 		}
 
 		// Only include if work bead is ready (unblocked)
-		if !readyWorkIDs[fields.WorkBeadID] {
+		work, ready := readyWork[fields.WorkBeadID]
+		if !ready {
 			continue
 		}
 
@@ -397,6 +407,8 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 			Description: ctx.Description,
 			Labels:      ctx.Labels,
 			Context:     fields,
+			Priority:    work.Priority,
+			DueAt:       work.DueAt,
 		})
 	}
 
@@ -482,10 +494,16 @@ func listAllSlingContexts(townRoot string) ([]*beads.Issue, error) {
 	return townBeads.ListOpenSlingContexts()
 }
 
-// listReadyWorkBeadIDsWithError returns a set of work bead IDs that are unblocked.
+// readyWorkBead holds the scheduling fields of an unblocked work bead.
+type readyWorkBead struct {
+	Priority int
+	DueAt    time.Time
+}
+
+// listReadyWorkBeadsWithError returns the unblocked work beads by ID.
 // Returns an error only when ALL dirs fail (partial success is acceptable).
-func listReadyWorkBeadIDsWithError(townRoot string) (map[string]bool, error) {
-	readyIDs := make(map[string]bool)
+func listReadyWorkBeadsWithError(townRoot string) (map[string]readyWorkBead, error) {
+	ready := make(map[string]readyWorkBead)
 	dirs := beadsSearchDirs(townRoot)
 	failCount := 0
 	var lastErr error
@@ -501,26 +519,33 @@ func listReadyWorkBeadIDsWithError(townRoot string) (map[string]bool, error) {
 			continue
 		}
 		var readyBeads []struct {
-			ID string `json:"id"`
+			ID       string `json:"id"`
+			Priority int    `json:"priority"`
+			DueAt    string `json:"due_at,omitempty"`
 		}
 		if err := json.Unmarshal(readyOut, &readyBeads); err == nil {
 			for _, b := range readyBeads {
-				readyIDs[b.ID] = true
+				work := readyWorkBead{Priority: b.Priority}
+				if b.DueAt != "" {
+					work.DueAt, _ = time.Parse(time.RFC3339, b.DueAt)
+				}
+				ready[b.ID] = work
 			}
 		}
 	}
 	if failCount == len(dirs) && failCount > 0 {
 		return nil, fmt.Errorf("all %d bd ready queries failed (last: %w)", failCount, lastErr)
 	}
-	return readyIDs, nil
+	return ready, nil
 }
 
 // listReadyWorkBeadIDs returns a set of work bead IDs that are unblocked.
 // Convenience wrapper that ignores errors (used by listScheduledBeads for display).
 func listReadyWorkBeadIDs(townRoot string) map[string]bool {
-	ids, _ := listReadyWorkBeadIDsWithError(townRoot)
-	if ids == nil {
-		return make(map[string]bool)
+	ready, _ := listReadyWorkBeadsWithError(townRoot)
+	ids := make(map[string]bool, len(ready))
+	for id := range ready {
+		ids[id] = true
 	}
 	return ids
 }
@@ -180,6 +180,77 @@ func runConfigCostTier(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Model-routing subcommand
+
+var configModelRoutingFormula string
+
+var configModelRoutingCmd = &cobra.Command{
+	Use:   "model-routing [bead-id]",
+	Short: "Show per-bead model routing rules",
+	Long: `Show the model routing rules that pick a polecat's agent per bead.
+
+Rules live in settings/config.json under "model_routing". The first rule
+whose conditions all match a bead picks the agent; unmatched beads use the
+polecat role's normal agent (see cost-tier). When a bead is slung again
+after a failed attempt (the witness reset it from a dead polecat), the
+escalation ladder moves it to the next stronger agent.
+
+  "model_routing": {
+    "rules": [
+      {"name": "chores", "labels": ["lint", "typo"], "agent": "claude-haiku"},
+      {"name": "small", "max_estimate": 30, "agent": "claude-sonnet"},
+      {"name": "design", "types": ["epic", "feature"], "agent": "claude"}
+    ],
+    "escalation": ["claude-haiku", "claude-sonnet", "claude"]
+  }
+
+Conditions: labels (any of), types (any of), formulas (glob patterns),
+min_estimate/max_estimate (bead estimated_minutes).
+
+With a bead ID, shows which agent gt sling would pick for it.
+
+Examples:
+  gt config model-routing            # Show rules and escalation ladder
+  gt config model-routing gt-abc     # Explain the choice for a bead`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigModelRouting,
+}
+
+func runConfigModelRouting(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := config.ValidateModelRouting(townSettings.ModelRouting); err != nil {
+		return fmt.Errorf("invalid model_routing: %w", err)
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Model routing:")
+		fmt.Println(config.FormatModelRoutes(townSettings.ModelRouting))
+		return nil
+	}
+
+	beadID := args[0]
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return err
+	}
+	agent, reason := routeSlingAgent(townRoot, resolveFormula(configModelRoutingFormula, false), info, "")
+	if agent == "" {
+		rigName := resolveRigForBead(townRoot, beadID)
+		fmt.Printf("%s: %s (polecat default, no rule matched)\n", beadID, style.Bold.Render(dispatchedAgentName(townRoot, rigName, "")))
+		return nil
+	}
+	fmt.Printf("%s: %s (%s)\n", beadID, style.Bold.Render(agent), reason)
+	return nil
+}
+
 // Default-agent subcommand
 
 var configDefaultAgentCmd = &cobra.Command{
@@ -610,6 +681,11 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode: -1 = direct (default), N > 0 = deferred
   scheduler.batch_size        Beads per heartbeat (default: 1)
   scheduler.spawn_delay       Delay between spawns (default: 0s)
+  scheduler.policies          Dispatch order: comma-separated priority, deadline,
+                              convoy-fair, aging ("" = enqueue order)
+  scheduler.aging_interval    Wait per priority level gained by aging (default: 4h)
+  scheduler.due_soon          Due-date window for the deadline policy (default: 24h)
+  scheduler.rig_quota.<rig>   Max concurrent polecats for one rig (0 = no quota)
   maintenance.window          Maintenance window start time in HH:MM (e.g., "03:00")
   maintenance.interval        How often: "daily", "weekly", "monthly", or duration
   maintenance.threshold       Commit count threshold (default: 1000)
@@ -631,6 +707,8 @@ Examples:
   gt config set cli_theme dark
   gt config set default_agent claude
   gt config set scheduler.max_polecats 5
+  gt config set scheduler.policies priority,deadline,convoy-fair
+  gt config set scheduler.rig_quota.gastown 3
   gt config set maintenance.window 03:00
   gt config set maintenance.interval daily
   gt config set lifecycle.reaper.delete_age 336h
@@ -653,6 +731,10 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode (-1 = direct, N > 0 = deferred)
   scheduler.batch_size        Beads per heartbeat
   scheduler.spawn_delay       Delay between spawns
+  scheduler.policies          Dispatch ordering policies
+  scheduler.aging_interval    Wait per priority level gained by aging
+  scheduler.due_soon          Due-date window for the deadline policy
+  scheduler.rig_quota.<rig>   Max concurrent polecats for one rig
   maintenance.window          Maintenance window start time (HH:MM)
   maintenance.interval        How often: daily, weekly, monthly, or duration
   maintenance.threshold       Commit count threshold
@@ -749,6 +831,35 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 		townSettings.Scheduler.SpawnDelay = value
 
+	case "scheduler.policies":
+		var policies []string
+		for _, p := range strings.Split(value, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				policies = append(policies, p)
+			}
+		}
+		if err := capacity.ValidatePolicies(policies); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		townSettings.Scheduler.Policies = policies
+
+	case "scheduler.aging_interval", "scheduler.due_soon":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid value for %s: expected positive Go duration (e.g. 4h)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		if key == "scheduler.aging_interval" {
+			townSettings.Scheduler.AgingInterval = value
+		} else {
+			townSettings.Scheduler.DueSoon = value
+		}
+
 	case "maintenance.window", "maintenance.interval", "maintenance.threshold":
 		return setMaintenanceConfig(townRoot, key, value)
 
@@ -756,7 +867,25 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return setLifecycleConfig(townRoot, key, value)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		if rig, ok := strings.CutPrefix(key, "scheduler.rig_quota."); ok && rig != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid value for %s: expected non-negative integer (0 = no quota)", key)
+			}
+			if townSettings.Scheduler == nil {
+				townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+			}
+			if n == 0 {
+				delete(townSettings.Scheduler.RigQuotas, rig)
+			} else {
+				if townSettings.Scheduler.RigQuotas == nil {
+					townSettings.Scheduler.RigQuotas = make(map[string]int)
+				}
+				townSettings.Scheduler.RigQuotas[rig] = n
+			}
+			break
+		}
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.policies\n  scheduler.aging_interval\n  scheduler.due_soon\n  scheduler.rig_quota.<rig>\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
@@ -823,6 +952,17 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		}
 		value = scfg.GetSpawnDelay().String()
 
+	case "scheduler.policies":
+		if townSettings.Scheduler != nil {
+			value = strings.Join(townSettings.Scheduler.Policies, ",")
+		}
+
+	case "scheduler.aging_interval":
+		value = townSettings.Scheduler.GetAgingInterval().String()
+
+	case "scheduler.due_soon":
+		value = townSettings.Scheduler.GetDueSoon().String()
+
 	case "maintenance.window", "maintenance.interval", "maintenance.threshold":
 		return getMaintenanceConfig(townRoot, key)
 
@@ -830,7 +970,11 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return getLifecycleConfig(townRoot, key)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		if rig, ok := strings.CutPrefix(key, "scheduler.rig_quota."); ok && rig != "" {
+			fmt.Println(townSettings.Scheduler.GetRigQuota(rig))
+			return nil
+		}
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.policies\n  scheduler.aging_interval\n  scheduler.due_soon\n  scheduler.rig_quota.<rig>\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	fmt.Println(value)
@@ -1179,6 +1323,7 @@ func parseBool(s string) (bool, error) {
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
+	configModelRoutingCmd.Flags().StringVar(&configModelRoutingFormula, "formula", "", "Formula the bead would be slung with (default: mol-polecat-work)")
 
 	// Add agent subcommands
 	configAgentCmd := &cobra.Command{
@@ -1198,6 +1343,7 @@ config values such as the default AI model or provider.`,
 	// Add subcommands to config
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configCostTierCmd)
+	configCmd.AddCommand(configModelRoutingCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
 	configCmd.AddCommand(configAgentEmailDomainCmd)
 	configCmd.AddCommand(configSetCmd)
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	convoyops "github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -64,25 +65,27 @@ func looksLikeIssueID(s string) bool {
 
 // Convoy command flags
 var (
-	convoyMolecule     string
-	convoyNotify       string
-	convoyOwner        string
-	convoyOwned        bool
-	convoyMerge        string
-	convoyStatusJSON   bool
-	convoyListJSON     bool
-	convoyListStatus   string
-	convoyListAll      bool
-	convoyListTree     bool
-	convoyInteractive  bool
-	convoyStrandedJSON bool
-	convoyCloseReason  string
-	convoyCloseNotify  string
-	convoyCloseForce   bool
-	convoyCheckDryRun  bool
-	convoyLandForce    bool
-	convoyLandKeep     bool
-	convoyLandDryRun   bool
+	convoyMolecule       string
+	convoyNotify         string
+	convoyOwner          string
+	convoyOwned          bool
+	convoyMerge          string
+	convoySubscribe      []string
+	convoyStatusJSON     bool
+	convoyListJSON       bool
+	convoyListStatus     string
+	convoyListAll        bool
+	convoyListTree       bool
+	convoyInteractive    bool
+	convoyStrandedJSON   bool
+	convoyStrandedNotify bool
+	convoyCloseReason    string
+	convoyCloseNotify    string
+	convoyCloseForce     bool
+	convoyCheckDryRun    bool
+	convoyLandForce      bool
+	convoyLandKeep       bool
+	convoyLandDryRun     bool
 )
 
 const (
@@ -211,6 +214,15 @@ The --merge flag sets the merge strategy for all work in the convoy:
   mr      Create merge-request bead, refinery processes (default)
   local   Keep on feature branch (for upstream PRs, human review)
 
+The --subscribe flag (repeatable) adds a structured subscription, stored
+on the convoy bead, in the form "<channel>:<target> [on <events>]":
+  mail:<address>       Mail with a summary of tracked legs and outputs
+  nudge:<address>      One-line nudge into the agent's session
+  escalate:<severity>  External channels routed for that severity
+                       (email, SMS, Slack, webhook) in escalation.json
+Events: landed, closed, stranded, synthesis. Without "on", a
+subscription fires on everything except stranded.
+
 Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
@@ -218,7 +230,9 @@ Examples:
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
   gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
   gt convoy create --owned "Manual deploy" gt-abc           # caller-managed lifecycle
-  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery`,
+  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery
+  gt convoy create "Release" gt-abc --subscribe "nudge:mayor/ on landed,stranded"
+  gt convoy create "Release" gt-abc --subscribe escalate:high`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -297,9 +311,14 @@ A convoy is "stranded" when:
 Use this to detect convoys that need feeding or cleanup. The Deacon patrol
 runs this periodically and dispatches dogs to feed stranded convoys.
 
+The query is read-only. With --notify it also tells subscribers of the
+"stranded" event about each convoy, at most once every 4 hours per convoy.
+The daemon's convoy scan passes --notify; other callers shouldn't.
+
 Examples:
   gt convoy stranded              # Show stranded convoys
-  gt convoy stranded --json       # Machine-readable output for automation`,
+  gt convoy stranded --json       # Machine-readable output for automation
+  gt convoy stranded --json --notify  # Also notify stranded subscribers`,
 	RunE: runConvoyStranded,
 }
 
@@ -356,6 +375,7 @@ func init() {
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
 	convoyCreateCmd.Flags().BoolVar(&convoyOwned, "owned", false, "Mark convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	convoyCreateCmd.Flags().StringArrayVar(&convoySubscribe, "subscribe", nil, "Subscribe to convoy events: <mail|nudge|escalate>:<target> [on <events>] (repeatable)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -374,6 +394,7 @@ func init() {
 
 	// Stranded flags
 	convoyStrandedCmd.Flags().BoolVar(&convoyStrandedJSON, "json", false, "Output as JSON")
+	convoyStrandedCmd.Flags().BoolVar(&convoyStrandedNotify, "notify", false, "Notify stranded subscribers of newly stranded convoys")
 
 	// Close flags
 	convoyCloseCmd.Flags().StringVar(&convoyCloseReason, "reason", "", "Reason for closing the convoy")
@@ -423,6 +444,11 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	subscriptions, err := parseSubscriptionFlags(convoySubscribe)
+	if err != nil {
+		return fmt.Errorf("invalid --subscribe: %w", err)
+	}
+
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
 	if looksLikeIssueID(name) {
@@ -464,6 +490,8 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		Notify:   convoyNotify,
 		Merge:    convoyMerge,
 		Molecule: convoyMolecule,
+
+		Subscriptions: subscriptions,
 	}
 	description = beads.SetConvoyFields(&beads.Issue{Description: description}, convoyFieldValues)
 
@@ -537,6 +565,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMerge != "" {
 		fmt.Printf("  Merge:    %s\n", convoyMerge)
 	}
+	for _, sub := range subscriptions {
+		fmt.Printf("  Subscribe: %s\n", sub.String())
+	}
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
@@ -760,6 +791,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	_ = events.LogFeed(events.TypeConvoyClosed, detectSender(), events.ConvoyClosedPayload(convoyID, convoy.Title, reason))
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -872,6 +904,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	_ = events.LogFeed(events.TypeConvoyClosed, detectSender(), events.ConvoyClosedPayload(convoyID, convoy.Title, reason))
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -904,10 +937,17 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	if convoyCloseNotify != "" {
 		sendCloseNotification(convoyCloseNotify, convoyID, convoy.Title, reason)
 	} else {
-		// Check if convoy has a notify address in description
-		notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
+		notifyMayorSession(townBeads, convoyID, convoy.Title)
 	}
 
+	// Notify owner, notify address, and subscribers stored on the convoy
+	notifyConvoyEvent(townBeads, convoyEvent{
+		Event:    beads.ConvoyEventClosed,
+		ConvoyID: convoyID,
+		Title:    convoy.Title,
+		Detail:   reason,
+	})
+
 	return nil
 }
 
@@ -1054,6 +1094,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n%s Landed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	_ = events.LogFeed(events.TypeConvoyClosed, detectSender(), events.ConvoyClosedPayload(convoyID, convoy.Title, reason))
 	fmt.Printf("  Reason: %s\n", reason)
 	if len(tracked) > 0 {
 		closedCount := len(tracked) - len(openIssues)
@@ -1180,6 +1221,12 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Tell subscribers about newly stranded convoys (rate-limited per convoy).
+	// Opt-in: the scan is polled, and a query shouldn't send mail.
+	if convoyStrandedNotify {
+		notifyStrandedConvoys(townBeads, stranded)
+	}
+
 	if convoyStrandedJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -1480,6 +1527,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			_ = events.LogFeed(events.TypeConvoyClosed, detectSender(), events.ConvoyClosedPayload(convoy.ID, convoy.Title, reason))
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
@@ -1489,37 +1537,15 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	return closed, nil
 }
 
-// notifyConvoyCompletion sends notifications to owner and any notify addresses.
+// notifyConvoyCompletion notifies the owner, notify address, and landed
+// subscribers of a convoy, then pushes a nudge to the Mayor if configured.
 func notifyConvoyCompletion(townBeads, convoyID, title string) {
-	// Get convoy description to find owner and notify addresses
-	showArgs := []string{"show", convoyID, "--json"}
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return
-	}
-
-	var convoys []struct {
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
-		return
-	}
-
-	// ZFC: Use typed accessor instead of parsing description text
-	fields := beads.ParseConvoyFields(&beads.Issue{Description: convoys[0].Description})
-	for _, addr := range fields.NotificationAddresses() {
-		mailArgs := []string{"mail", "send", addr,
-			"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
-			"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
-		mailCmd := exec.Command("gt", mailArgs...)
-		if err := mailCmd.Run(); err != nil {
-			style.PrintWarning("could not notify %s: %v", addr, err)
-		}
-	}
+	notifyConvoyEvent(townBeads, convoyEvent{
+		Event:    beads.ConvoyEventLanded,
+		ConvoyID: convoyID,
+		Title:    title,
+		Detail:   "All tracked issues are now closed.",
+	})
 
 	// Push notification to active Mayor session if configured
 	notifyMayorSession(townBeads, convoyID, title)
@@ -1964,11 +1990,11 @@ func formatConvoyStatus(status string) string {
 
 // trackedIssueInfo holds info about an issue being tracked by a convoy.
 type trackedIssueInfo struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	Type      string `json:"dependency_type"`
-	IssueType string `json:"issue_type"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Type      string   `json:"dependency_type"`
+	IssueType string   `json:"issue_type"`
 	Blocked   bool     `json:"blocked,omitempty"`    // True if issue currently has blockers
 	Assignee  string   `json:"assignee,omitempty"`   // Assigned agent (e.g., gastown/polecats/goose)
 	Labels    []string `json:"labels,omitempty"`     // Bead labels (propagated from trackedDependency)
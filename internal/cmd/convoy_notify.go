@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// convoyStrandedNotifyInterval is the minimum time between stranded
+// notifications for the same convoy. gt convoy stranded runs on every
+// deacon patrol; subscribers should hear about a stuck convoy once, not
+// every few minutes.
+const convoyStrandedNotifyInterval = 4 * time.Hour
+
+// convoyLegExcerptLen caps how much of each leg output file is quoted in
+// a notification body.
+const convoyLegExcerptLen = 400
+
+// convoyEvent is something that happened to a convoy, delivered to its
+// subscribers.
+type convoyEvent struct {
+	Event    string // beads.ConvoyEvent*
+	ConvoyID string
+	Title    string
+	Detail   string // close reason, stranded summary, etc.
+}
+
+var (
+	convoyNotifyEvent  string
+	convoyNotifyTitle  string
+	convoyNotifyDetail string
+)
+
+var convoyNotifyCmd = &cobra.Command{
+	Use:    "notify <convoy-id>",
+	Short:  "Deliver a convoy event to its subscribers",
+	Hidden: true, // Raised by the refinery, which can't call into cmd
+	Long: `Deliver a convoy event to the convoy's subscribers, exactly as if gt had
+raised it itself. The refinery uses this when it auto-closes a convoy so its
+landed notices honour subscriptions and escalation routing.
+
+Examples:
+  gt convoy notify hq-cv-abc --event landed --title "Auth rewrite"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyNotify,
+}
+
+func init() {
+	convoyNotifyCmd.Flags().StringVar(&convoyNotifyEvent, "event", "", "Event to deliver: landed, closed, stranded, or synthesis")
+	convoyNotifyCmd.Flags().StringVar(&convoyNotifyTitle, "title", "", "Convoy title for the subject line")
+	convoyNotifyCmd.Flags().StringVar(&convoyNotifyDetail, "detail", "", "Event detail (close reason, stranded summary, etc.)")
+	_ = convoyNotifyCmd.MarkFlagRequired("event")
+	convoyCmd.AddCommand(convoyNotifyCmd)
+}
+
+func runConvoyNotify(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	convoyID := args[0]
+	title := convoyNotifyTitle
+	if title == "" {
+		title = convoyID
+	}
+
+	switch convoyNotifyEvent {
+	case beads.ConvoyEventLanded:
+		// Same path as gt convoy check and land, Mayor nudge included.
+		notifyConvoyCompletion(townBeads, convoyID, title)
+	case beads.ConvoyEventClosed, beads.ConvoyEventStranded, beads.ConvoyEventSynthesis:
+		notifyConvoyEvent(townBeads, convoyEvent{
+			Event:    convoyNotifyEvent,
+			ConvoyID: convoyID,
+			Title:    title,
+			Detail:   convoyNotifyDetail,
+		})
+	default:
+		return fmt.Errorf("unknown convoy event %q", convoyNotifyEvent)
+	}
+	return nil
+}
+
+// convoySubject returns the one-line subject for an event.
+func (ev convoyEvent) subject() string {
+	switch ev.Event {
+	case beads.ConvoyEventLanded:
+		return fmt.Sprintf("🚚 Convoy landed: %s", ev.Title)
+	case beads.ConvoyEventClosed:
+		return fmt.Sprintf("🚚 Convoy closed: %s", ev.Title)
+	case beads.ConvoyEventStranded:
+		return fmt.Sprintf("⚠ Convoy stranded: %s", ev.Title)
+	case beads.ConvoyEventSynthesis:
+		return fmt.Sprintf("🔬 Convoy synthesis complete: %s", ev.Title)
+	default:
+		return fmt.Sprintf("🚚 Convoy %s: %s", ev.Event, ev.Title)
+	}
+}
+
+// parseSubscriptionFlags validates --subscribe flag values.
+func parseSubscriptionFlags(specs []string) ([]beads.ConvoySubscription, error) {
+	var subs []beads.ConvoySubscription
+	for _, spec := range specs {
+		sub, err := beads.ParseConvoySubscription(spec)
+		if err != nil {
+			return nil, err
+		}
+		if sub.Channel == beads.ConvoyChannelEscalate && !config.IsValidSeverity(sub.Target) {
+			return nil, fmt.Errorf("invalid escalation severity %q in subscription %q", sub.Target, spec)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// mergeSubscriptions appends subscriptions not already present.
+func mergeSubscriptions(existing, add []beads.ConvoySubscription) []beads.ConvoySubscription {
+	seen := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		seen[s.String()] = true
+	}
+	for _, s := range add {
+		if !seen[s.String()] {
+			existing = append(existing, s)
+			seen[s.String()] = true
+		}
+	}
+	return existing
+}
+
+// convoySubscribers returns the subscriptions that fire for an event.
+// The legacy Owner and Notify fields act as mail subscriptions on terminal
+// events, so convoys created before subscriptions keep their notifications.
+// Duplicate channel/target pairs are collapsed.
+func convoySubscribers(fields *beads.ConvoyFields, event string) []beads.ConvoySubscription {
+	if fields == nil {
+		return nil
+	}
+	var out []beads.ConvoySubscription
+	seen := make(map[string]bool)
+	add := func(sub beads.ConvoySubscription) {
+		key := sub.Channel + ":" + sub.Target
+		if !sub.Matches(event) || seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, sub)
+	}
+	for _, addr := range fields.NotificationAddresses() {
+		add(beads.ConvoySubscription{Channel: beads.ConvoyChannelMail, Target: addr})
+	}
+	for _, sub := range fields.Subscriptions {
+		add(sub)
+	}
+	return out
+}
+
+// notifyConvoyEvent delivers a convoy event to every matching subscriber:
+// mail gets the full summary, nudges get the subject line, and escalate
+// subscriptions go out over the external channels routed for their
+// severity in settings/escalation.json. Delivery failures are warnings;
+// they never fail the operation that raised the event.
+func notifyConvoyEvent(townBeads string, ev convoyEvent) {
+	description, err := readConvoyDescription(townBeads, ev.ConvoyID)
+	if err != nil {
+		return
+	}
+	fields := beads.ParseConvoyFields(&beads.Issue{Description: description})
+	subs := convoySubscribers(fields, ev.Event)
+	if len(subs) == 0 {
+		return
+	}
+
+	body := buildConvoyEventBody(townBeads, ev, description)
+	subject := ev.subject()
+
+	for _, sub := range subs {
+		var err error
+		switch sub.Channel {
+		case beads.ConvoyChannelMail:
+			err = exec.Command("gt", "mail", "send", sub.Target, "-s", subject, "-m", body).Run()
+		case beads.ConvoyChannelNudge:
+			msg := fmt.Sprintf("%s — %s. Run 'gt convoy status %s' for details.", subject, ev.ConvoyID, ev.ConvoyID)
+			err = exec.Command("gt", "nudge", sub.Target, "-m", msg).Run()
+		case beads.ConvoyChannelEscalate:
+			err = escalateConvoyEvent(filepath.Dir(townBeads), sub.Target, ev, subject, body)
+		}
+		if err != nil {
+			style.PrintWarning("could not notify %s: %v", sub.String(), err)
+		}
+	}
+}
+
+// escalateConvoyEvent sends an event over the external escalation actions
+// (email, SMS, Slack, webhook, log) routed for a severity.
+func escalateConvoyEvent(townRoot, severity string, ev convoyEvent, subject, body string) error {
+	cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+	notifiers, skipped := notify.FromEscalationConfig(townRoot, cfg, cfg.GetRouteForSeverity(severity))
+	if len(notifiers) == 0 {
+		if len(skipped) > 0 {
+			return fmt.Errorf("no deliverable channels for %s: %s", severity, skipped[0].Error)
+		}
+		return fmt.Errorf("no external channels routed for severity %s", severity)
+	}
+
+	n := &notify.Notification{
+		ID:        ev.ConvoyID,
+		Severity:  severity,
+		Subject:   subject,
+		Body:      body,
+		Source:    "convoy:" + ev.Event,
+		From:      "gt convoy",
+		Timestamp: time.Now().UTC(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	results := notify.NewDispatcherFromConfig(townRoot, cfg).Deliver(ctx, notifiers, n)
+	var failed []string
+	for _, r := range results {
+		if r.Status != notify.StatusSent {
+			failed = append(failed, fmt.Sprintf("%s=%s", r.Channel, r.Status))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("escalation delivery: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// readConvoyDescription returns a convoy bead's description.
+func readConvoyDescription(townBeads, convoyID string) (string, error) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return "", err
+	}
+	var convoys []struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+		return "", fmt.Errorf("parsing convoy %s", convoyID)
+	}
+	return convoys[0].Description, nil
+}
+
+// addConvoySubscriptions appends subscriptions to an existing convoy bead.
+func addConvoySubscriptions(townBeads, convoyID string, subs []beads.ConvoySubscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	description, err := readConvoyDescription(townBeads, convoyID)
+	if err != nil {
+		return fmt.Errorf("reading convoy %s: %w", convoyID, err)
+	}
+	issue := &beads.Issue{Description: description}
+	fields := beads.ParseConvoyFields(issue)
+	if fields == nil {
+		fields = &beads.ConvoyFields{}
+	}
+	fields.Subscriptions = mergeSubscriptions(fields.Subscriptions, subs)
+	updated := beads.SetConvoyFields(issue, fields)
+	if updated == description {
+		return nil
+	}
+	if out, err := BdCmd("update", convoyID, "--description="+updated).
+		Dir(townBeads).WithAutoCommit().
+		CombinedOutput(); err != nil {
+		return fmt.Errorf("bd update %s --description: %w\noutput: %s", convoyID, err, out)
+	}
+	return nil
+}
+
+// buildConvoyEventBody renders the notification body: what happened, the
+// tracked legs with their status, and excerpts of any leg output files
+// written by the convoy's formula.
+func buildConvoyEventBody(townBeads string, ev convoyEvent, description string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\n", ev.subject())
+	fmt.Fprintf(&sb, "Convoy: %s\n", ev.ConvoyID)
+	fmt.Fprintf(&sb, "Event:  %s\n", ev.Event)
+	if ev.Detail != "" {
+		fmt.Fprintf(&sb, "Detail: %s\n", ev.Detail)
+	}
+
+	meta := &ConvoyMeta{ID: ev.ConvoyID, Title: ev.Title}
+	parseConvoyMetaFields(meta, description)
+	if tracked, err := getTrackedIssues(townBeads, ev.ConvoyID); err == nil {
+		for _, t := range tracked {
+			meta.LegIssues = append(meta.LegIssues, t.ID)
+		}
+	}
+	legs, _, _ := collectLegOutputs(meta, loadConvoyFormula(meta))
+
+	if len(legs) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(summarizeLegOutputs(legs))
+	}
+	fmt.Fprintf(&sb, "\nDetails: gt convoy status %s\n", ev.ConvoyID)
+	return sb.String()
+}
+
+// loadConvoyFormula loads the formula named in convoy metadata, or nil.
+func loadConvoyFormula(meta *ConvoyMeta) *formula.Formula {
+	path := meta.FormulaPath
+	if path == "" && meta.Formula != "" {
+		path, _ = findFormula(meta.Formula)
+	}
+	if path == "" {
+		return nil
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// summarizeLegOutputs renders legs as a status list with short excerpts of
+// their output files.
+func summarizeLegOutputs(legs []LegOutput) string {
+	sorted := append([]LegOutput(nil), legs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].LegID < sorted[j].LegID })
+
+	done := 0
+	for _, leg := range sorted {
+		if leg.Status == "closed" {
+			done++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Legs: %d/%d complete\n", done, len(sorted))
+	for _, leg := range sorted {
+		icon := "○"
+		if leg.Status == "closed" {
+			icon = "✓"
+		}
+		fmt.Fprintf(&sb, "  %s %s: %s [%s]\n", icon, leg.LegID, leg.Title, leg.Status)
+		if leg.HasFile {
+			fmt.Fprintf(&sb, "      output: %s\n", leg.FilePath)
+			if excerpt := legExcerpt(leg.Content); excerpt != "" {
+				for _, line := range strings.Split(excerpt, "\n") {
+					fmt.Fprintf(&sb, "      │ %s\n", line)
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// legExcerpt returns the start of a leg output, trimmed to a few hundred
+// bytes on a line boundary where possible.
+func legExcerpt(content string) string {
+	content = strings.TrimSpace(content)
+	if len(content) <= convoyLegExcerptLen {
+		return content
+	}
+	cut := content[:convoyLegExcerptLen]
+	if idx := strings.LastIndex(cut, "\n"); idx > convoyLegExcerptLen/2 {
+		cut = cut[:idx]
+	}
+	return strings.TrimRight(cut, " \n") + "\n…"
+}
+
+// convoyNotifyState records when stranded notifications were last sent,
+// persisted at <town>/.runtime/convoy-notify.json.
+type convoyNotifyState struct {
+	Stranded map[string]time.Time `json:"stranded"`
+}
+
+func convoyNotifyStatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "convoy-notify.json")
+}
+
+// notifyStrandedConvoys notifies subscribers of newly stranded convoys.
+// A convoy is re-announced only after convoyStrandedNotifyInterval, or
+// after it stops being stranded and strands again.
+// The state file is locked for the whole pass, so overlapping scans can't
+// both decide the same convoy is due.
+func notifyStrandedConvoys(townBeads string, stranded []strandedConvoyInfo) {
+	townRoot := filepath.Dir(townBeads)
+	path := convoyNotifyStatePath(townRoot)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		style.PrintWarning("could not create runtime directory: %v", err)
+		return
+	}
+	fileLock := flock.New(path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		style.PrintWarning("could not lock convoy notification state: %v", err)
+		return
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	var state convoyNotifyState
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+
+	due, next := strandedNotificationsDue(state.Stranded, stranded, time.Now().UTC())
+	for _, s := range due {
+		notifyConvoyEvent(townBeads, convoyEvent{
+			Event:    beads.ConvoyEventStranded,
+			ConvoyID: s.ID,
+			Title:    s.Title,
+			Detail:   strandedDetail(s),
+		})
+	}
+
+	state.Stranded = next
+	if err := util.EnsureDirAndWriteJSON(path, state); err != nil {
+		style.PrintWarning("could not save convoy notification state: %v", err)
+	}
+}
+
+// strandedNotificationsDue decides which stranded convoys to announce and
+// returns the updated last-notified map. Convoys no longer stranded are
+// dropped from the map so a later stranding is announced right away.
+func strandedNotificationsDue(last map[string]time.Time, stranded []strandedConvoyInfo, now time.Time) ([]strandedConvoyInfo, map[string]time.Time) {
+	next := make(map[string]time.Time, len(stranded))
+	var due []strandedConvoyInfo
+	for _, s := range stranded {
+		if at, ok := last[s.ID]; ok && now.Sub(at) < convoyStrandedNotifyInterval {
+			next[s.ID] = at
+			continue
+		}
+		due = append(due, s)
+		next[s.ID] = now
+	}
+	return due, next
+}
+
+func strandedDetail(s strandedConvoyInfo) string {
+	switch {
+	case s.TrackedCount == 0:
+		return "empty convoy (0 tracked issues)"
+	case s.ReadyCount == 0:
+		return fmt.Sprintf("stuck: %d tracked issues, none ready", s.TrackedCount)
+	default:
+		return fmt.Sprintf("%d of %d tracked issues ready with no worker: %s",
+			s.ReadyCount, s.TrackedCount, strings.Join(s.ReadyIssues, ", "))
+	}
+}
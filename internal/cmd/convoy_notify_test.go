@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestConvoySubscribers(t *testing.T) {
+	fields := &beads.ConvoyFields{
+		Owner:  "mayor/",
+		Notify: "ops/",
+		Subscriptions: []beads.ConvoySubscription{
+			{Channel: "mail", Target: "mayor/"}, // duplicate of owner
+			{Channel: "nudge", Target: "mayor/", Events: []string{"stranded", "landed"}},
+			{Channel: "escalate", Target: "high", Events: []string{"synthesis"}},
+		},
+	}
+
+	tests := []struct {
+		event string
+		want  []string
+	}{
+		{"landed", []string{"mail:mayor/", "mail:ops/", "nudge:mayor/ on stranded,landed"}},
+		{"closed", []string{"mail:mayor/", "mail:ops/"}},
+		{"stranded", []string{"nudge:mayor/ on stranded,landed"}},
+		{"synthesis", []string{"mail:mayor/", "mail:ops/", "escalate:high on synthesis"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			var got []string
+			for _, s := range convoySubscribers(fields, tt.event) {
+				got = append(got, s.String())
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("convoySubscribers(%s) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+
+	if subs := convoySubscribers(nil, "landed"); subs != nil {
+		t.Errorf("convoySubscribers(nil) = %v, want nil", subs)
+	}
+}
+
+func TestParseSubscriptionFlags_Severity(t *testing.T) {
+	subs, err := parseSubscriptionFlags([]string{"mail:mayor/", "escalate:high on stranded"})
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("parseSubscriptionFlags() = %v, %v", subs, err)
+	}
+	if _, err := parseSubscriptionFlags([]string{"escalate:urgent"}); err == nil {
+		t.Error("parseSubscriptionFlags accepted unknown severity \"urgent\"")
+	}
+}
+
+func TestMergeSubscriptions(t *testing.T) {
+	existing := []beads.ConvoySubscription{{Channel: "mail", Target: "mayor/"}}
+	add := []beads.ConvoySubscription{
+		{Channel: "mail", Target: "mayor/"},
+		{Channel: "mail", Target: "mayor/", Events: []string{"synthesis"}},
+	}
+	got := mergeSubscriptions(existing, add)
+	if len(got) != 2 || got[1].String() != "mail:mayor/ on synthesis" {
+		t.Errorf("mergeSubscriptions = %v", got)
+	}
+}
+
+func TestSummarizeLegOutputs(t *testing.T) {
+	legs := []LegOutput{
+		{LegID: "security", Title: "Security review", Status: "open"},
+		{LegID: "perf", Title: "Perf review", Status: "closed", HasFile: true,
+			FilePath: "/reviews/r1/perf.md", Content: "# Perf\n\nNo regressions.\n"},
+	}
+	got := summarizeLegOutputs(legs)
+
+	for _, want := range []string{
+		"Legs: 1/2 complete",
+		"✓ perf: Perf review [closed]",
+		"output: /reviews/r1/perf.md",
+		"│ No regressions.",
+		"○ security: Security review [open]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "perf") > strings.Index(got, "security") {
+		t.Errorf("legs not sorted by ID:\n%s", got)
+	}
+}
+
+func TestLegExcerpt(t *testing.T) {
+	if got := legExcerpt("  short\n"); got != "short" {
+		t.Errorf("legExcerpt(short) = %q", got)
+	}
+	long := strings.Repeat("line of findings\n", 60)
+	got := legExcerpt(long)
+	if len(got) > convoyLegExcerptLen+len("\n…") || !strings.HasSuffix(got, "\n…") {
+		t.Errorf("legExcerpt(long) = %d bytes, suffix %q", len(got), got[len(got)-5:])
+	}
+	if strings.Contains(strings.TrimSuffix(got, "\n…"), "line of f\n") {
+		t.Error("legExcerpt should cut on a line boundary")
+	}
+}
+
+func TestStrandedNotificationsDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	last := map[string]time.Time{
+		"hq-cv-recent": now.Add(-time.Hour),
+		"hq-cv-old":    now.Add(-convoyStrandedNotifyInterval - time.Minute),
+		"hq-cv-gone":   now.Add(-time.Hour),
+	}
+	stranded := []strandedConvoyInfo{
+		{ID: "hq-cv-recent"},
+		{ID: "hq-cv-old"},
+		{ID: "hq-cv-new"},
+	}
+
+	due, next := strandedNotificationsDue(last, stranded, now)
+
+	var ids []string
+	for _, s := range due {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "hq-cv-old,hq-cv-new" {
+		t.Errorf("due = %v, want [hq-cv-old hq-cv-new]", ids)
+	}
+	if !next["hq-cv-recent"].Equal(last["hq-cv-recent"]) {
+		t.Error("recently notified convoy should keep its timestamp")
+	}
+	if !next["hq-cv-new"].Equal(now) || !next["hq-cv-old"].Equal(now) {
+		t.Error("notified convoys should be stamped with now")
+	}
+	if _, ok := next["hq-cv-gone"]; ok {
+		t.Error("convoy no longer stranded should be pruned")
+	}
+}
+
+func TestStrandedDetail(t *testing.T) {
+	tests := []struct {
+		in   strandedConvoyInfo
+		want string
+	}{
+		{strandedConvoyInfo{}, "empty convoy (0 tracked issues)"},
+		{strandedConvoyInfo{TrackedCount: 3}, "stuck: 3 tracked issues, none ready"},
+		{strandedConvoyInfo{TrackedCount: 3, ReadyCount: 2, ReadyIssues: []string{"gt-a", "gt-b"}},
+			"2 of 3 tracked issues ready with no worker: gt-a, gt-b"},
+	}
+	for _, tt := range tests {
+		if got := strandedDetail(tt.in); got != tt.want {
+			t.Errorf("strandedDetail(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,6 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -17,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -187,29 +187,6 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
 // TokenUsage aggregates token usage across a session.
 type TokenUsage struct {
 	Model                    string
@@ -749,51 +726,22 @@ func findLatestTranscript(projectDir string) (string, error) {
 
 // parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
 func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
 	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
+	err := quota.ReadTranscript(transcriptPath, func(e quota.TranscriptEntry) {
 		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
+		if usage.Model == "" && e.Model != "" {
+			usage.Model = e.Model
 		}
 
 		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
+		usage.InputTokens += int(e.Usage.InputTokens)
+		usage.CacheCreationInputTokens += int(e.Usage.CacheCreationInputTokens)
+		usage.CacheReadInputTokens += int(e.Usage.CacheReadInputTokens)
+		usage.OutputTokens += int(e.Usage.OutputTokens)
+	})
+	if err != nil {
 		return nil, err
 	}
-
 	return usage, nil
 }
 
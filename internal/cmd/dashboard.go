@@ -34,6 +34,10 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+A read-only JSON API for tools that poll the town is served under /api/v1:
+convoys, mail, rigs, polecats, merge-queue, escalations and hooks. Lists are
+paginated (?limit=, ?cursor=) and carry ETags for If-None-Match polling.
+
 Example:
   gt dashboard                    # Start on default port 8080
   gt dashboard --port 3000        # Start on port 3000
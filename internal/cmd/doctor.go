@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -17,6 +18,8 @@ var (
 	doctorRestartSessions bool
 	doctorNoStart         bool
 	doctorSlow            string
+	doctorParallel        int
+	doctorDryRun          bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -109,12 +112,37 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
 
 Use --fix to attempt automatic fixes for issues that support it.
+Use --dry-run with --fix to print each fix's planned actions without applying them.
 Use --no-start with --fix to suppress starting the daemon and agents.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Checks run concurrently (--parallel, default 8) and are reported in order.
+Checks that depend on a failing check (e.g. database checks when the Dolt
+server is unreachable) are skipped. --fix runs checks one at a time.
+
+Fixes record the files they remove or overwrite in a journal under
+.runtime/doctor/<run-id>/. Use 'gt doctor undo <run-id>' to restore them.
+Checks whose fix cannot be previewed are listed as opaque by --dry-run and
+cannot be undone. Journals of the last 10 fix runs are kept.`,
 	RunE: runDoctor,
 }
 
+var doctorUndoCmd = &cobra.Command{
+	Use:   "undo [run-id]",
+	Short: "Revert the file changes of a doctor --fix run",
+	Long: `Revert the changes recorded by a 'gt doctor --fix' run.
+
+Removed files and directories are restored and overwritten files get their
+previous content back. Files changed since the fix are left alone. Actions
+that are not file changes (killed sessions, re-created worktrees) are
+reported but only undone when they recorded how (e.g. a branch checkout).
+
+Without a run ID, lists the recorded fix runs.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctorUndo,
+}
+
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
@@ -124,10 +152,17 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().IntVarP(&doctorParallel, "parallel", "j", doctor.DefaultParallelism, "Maximum checks to run at once (1 = sequential)")
+	doctorCmd.Flags().BoolVar(&doctorDryRun, "dry-run", false, "With --fix, show planned fix actions without applying them")
+	doctorCmd.AddCommand(doctorUndoCmd)
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorDryRun && !doctorFix {
+		return fmt.Errorf("--dry-run requires --fix")
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -141,10 +176,15 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		Verbose:         doctorVerbose,
 		RestartSessions: doctorRestartSessions,
 		NoStart:         doctorNoStart,
+		DryRun:          doctorDryRun,
+	}
+	if doctorFix && !doctorDryRun {
+		ctx.Journal = doctor.NewJournal(townRoot)
 	}
 
 	// Create doctor and register checks
 	d := doctor.NewDoctor()
+	d.SetParallelism(doctorParallel)
 
 	// Register workspace-level checks first (fundamental)
 	d.RegisterAll(doctor.WorkspaceChecks()...)
@@ -286,6 +326,22 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Print summary (checks were already printed during streaming)
 	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+	if n := ctx.Journal.Len(); n > 0 {
+		opaque := ctx.Journal.Opaque()
+		if n > opaque {
+			fmt.Printf("\nRecorded %d fix action(s). Undo with: gt doctor undo %s\n", n-opaque, ctx.Journal.RunID)
+		} else {
+			fmt.Println()
+		}
+		if opaque > 0 {
+			fmt.Printf("%s %d fix(es) ran without recording their changes and cannot be undone.\n", style.WarningPrefix, opaque)
+		}
+	}
+	if ctx.Journal != nil {
+		if err := doctor.PruneJournals(townRoot, doctor.JournalRetention); err != nil {
+			style.PrintWarning("pruning old fix journals: %v", err)
+		}
+	}
 
 	// Exit with error code if there are errors
 	if report.HasErrors() {
@@ -294,3 +350,49 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runDoctorUndo(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		journals, err := doctor.ListJournals(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing fix runs: %w", err)
+		}
+		if len(journals) == 0 {
+			fmt.Println("No recorded fix runs.")
+			return nil
+		}
+		fmt.Printf("%s\n\n", style.Bold.Render("Recorded fix runs"))
+		for _, j := range journals {
+			fmt.Printf("  %s  %d action(s)", style.Bold.Render(j.RunID), len(j.Entries))
+			if j.Undone != nil {
+				fmt.Printf("  %s", style.Dim.Render("(undone)"))
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	j, err := doctor.LoadJournal(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	result, err := j.Undo()
+	if err != nil {
+		return err
+	}
+	for _, r := range result.Restored {
+		fmt.Printf("%s Undid %s\n", style.SuccessPrefix, r)
+	}
+	for _, s := range result.Skipped {
+		fmt.Printf("%s Skipped %s\n", style.WarningPrefix, s)
+	}
+	if len(result.Restored) == 0 && len(result.Skipped) == 0 {
+		fmt.Println("Nothing to undo.")
+	}
+	return nil
+}
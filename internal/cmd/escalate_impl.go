@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -128,8 +130,17 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Deliver external notification actions (email:, sms:, slack, webhook, log)
+	delivery := executeExternalActions(townRoot, actions, escalationConfig, &notify.Notification{
+		ID:        issue.ID,
+		Severity:  severity,
+		Subject:   description,
+		Body:      formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+		Source:    escalateSource,
+		From:      agentID,
+		Timestamp: time.Now(),
+	})
+	recordDelivery(bd, issue.ID, delivery)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -151,6 +162,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(delivery) > 0 {
+			result["delivery"] = delivery
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -394,6 +408,17 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			delivery := executeExternalActions(townRoot, actions, escalationConfig, &notify.Notification{
+				ID:          result.ID,
+				Severity:    result.NewSeverity,
+				Subject:     result.Title,
+				Body:        formatReescalationMailBody(result, reescalatedBy),
+				From:        reescalatedBy,
+				Timestamp:   time.Now(),
+				Reescalated: true,
+			})
+			recordDelivery(bd, result.ID, delivery)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -505,6 +530,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"deliveryStatus": fields.DeliveryStatus,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -531,6 +557,9 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if fields.DeliveryStatus != "" {
+		fmt.Printf("  Delivery: %s (%s)\n", fields.DeliveryStatus, fields.DeliveredAt)
+	}
 
 	return nil
 }
@@ -553,41 +582,62 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
+// executeExternalActions delivers external notification actions (email:, sms:,
+// slack, webhook, log) with retries and rate limiting, prints a line per
+// channel, and returns the per-channel results. Actions without a configured
+// contact or transport are reported as skipped.
+func executeExternalActions(townRoot string, actions []string, cfg *config.EscalationConfig, n *notify.Notification) []notify.Result {
+	notifiers, results := notify.FromEscalationConfig(townRoot, cfg, actions)
+	if len(notifiers) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		dispatcher := notify.NewDispatcherFromConfig(townRoot, cfg)
+		results = append(results, dispatcher.Deliver(ctx, notifiers, n)...)
+	}
 
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
+	for _, r := range results {
+		switch r.Status {
+		case notify.StatusSent:
+			fmt.Printf("  %s %s delivered to %s\n", channelEmoji(r.Channel), r.Channel, r.Target)
+		case notify.StatusRateLimited:
+			style.PrintWarning("%s delivery to %s suppressed: rate limit reached", r.Channel, r.Target)
+		case notify.StatusSkipped:
+			style.PrintWarning("%s action skipped: %s (see settings/escalation.json)", r.Channel, r.Error)
+		default:
+			style.PrintWarning("%s delivery to %s failed after %d attempt(s): %s", r.Channel, r.Target, r.Attempts, r.Error)
+		}
+	}
+	return results
+}
 
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
+// recordDelivery stores external delivery results on the escalation bead.
+func recordDelivery(bd *beads.Beads, beadID string, results []notify.Result) {
+	if len(results) == 0 {
+		return
+	}
+	failed := false
+	for _, r := range results {
+		if r.Status != notify.StatusSent {
+			failed = true
+			break
 		}
 	}
+	if err := bd.RecordEscalationDelivery(beadID, notify.FormatStatus(results), failed); err != nil {
+		style.PrintWarning("failed to record delivery status on %s: %v", beadID, err)
+	}
+}
+
+func channelEmoji(channel string) string {
+	switch channel {
+	case notify.ChannelEmail:
+		return "📧"
+	case notify.ChannelSMS:
+		return "📱"
+	case notify.ChannelSlack, notify.ChannelWebhook:
+		return "💬"
+	default:
+		return "📝"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
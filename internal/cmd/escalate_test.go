@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,37 +335,48 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	// executeExternalActions never returns errors; it reports one result per
+	// external action so the caller can record delivery on the bead.
+	var slackHits int
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackHits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
 
 	tests := []struct {
 		name    string
 		actions []string
 		cfg     *config.EscalationConfig
+		want    map[string]string // channel -> status
 	}{
 		{
 			name:    "no external actions",
 			actions: []string{"bead", "mail:mayor"},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{},
 		},
 		{
 			name:    "email action without contact",
 			actions: []string{"email:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{"email": notify.StatusSkipped},
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp transport",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail: "test@example.com",
 				},
 			},
+			want: map[string]string{"email": notify.StatusSkipped},
 		},
 		{
 			name:    "sms action without contact",
 			actions: []string{"sms:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{"sms": notify.StatusSkipped},
 		},
 		{
 			name:    "sms action with contact",
@@ -372,25 +386,29 @@ func TestExecuteExternalActions(t *testing.T) {
 					HumanSMS: "+15551234567",
 				},
 			},
+			want: map[string]string{"sms": notify.StatusSkipped},
 		},
 		{
 			name:    "slack action without webhook",
 			actions: []string{"slack"},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{"slack": notify.StatusSkipped},
 		},
 		{
 			name:    "slack action with webhook",
 			actions: []string{"slack"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want: map[string]string{"slack": notify.StatusSent},
 		},
 		{
 			name:    "log action",
 			actions: []string{"log"},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{"log": notify.StatusSent},
 		},
 		{
 			name:    "all external actions combined",
@@ -399,23 +417,43 @@ func TestExecuteExternalActions(t *testing.T) {
 				Contacts: config.EscalationContacts{
 					HumanEmail:   "test@example.com",
 					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want: map[string]string{
+				"email": notify.StatusSkipped,
+				"sms":   notify.StatusSkipped,
+				"slack": notify.StatusSent,
+				"log":   notify.StatusSent,
+			},
 		},
 		{
 			name:    "empty actions",
 			actions: []string{},
 			cfg:     &config.EscalationConfig{},
+			want:    map[string]string{},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			townRoot := t.TempDir()
+			n := &notify.Notification{ID: "hq-test", Severity: "high", Subject: "Test escalation", Timestamp: time.Now()}
+			results := executeExternalActions(townRoot, tt.actions, tt.cfg, n)
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d results, want %d: %+v", len(results), len(tt.want), results)
+			}
+			for _, r := range results {
+				if r.Status != tt.want[r.Channel] {
+					t.Errorf("%s: status = %q, want %q (%s)", r.Channel, r.Status, tt.want[r.Channel], r.Error)
+				}
+			}
 		})
 	}
+
+	if slackHits != 2 {
+		t.Errorf("slack webhook hit %d times, want 2", slackHits)
+	}
 }
 
 func TestRunEscalateValidation(t *testing.T) {
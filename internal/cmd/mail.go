@@ -33,6 +33,13 @@ var (
 	mailReplyMessage  string
 	mailStdin         bool // Read message body from stdin
 
+	// Attachment and payload flags
+	mailAttach         []string // --attach files
+	mailPayload        string   // --payload JSON or @file
+	mailPayloadSchema  string
+	mailReadAttachment string // read: --attachment name
+	mailReadOutput     string // read: --output file for --attachment
+
 	// Search flags
 	mailSearchFrom    string
 	mailSearchSubject bool
@@ -135,6 +142,13 @@ Examples:
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
 
+  # Attach files (stored beside the mail beads, max 1 MiB each):
+  gt mail send mayor/ -s "Gate logs" -m "See attached" --attach gate.log --attach report.json
+
+  # Typed JSON payload for handlers (inline or @file):
+  gt mail send gastown/refinery -s "MERGE_READY Toast" -m "Ready" \
+    --payload-schema gastown.protocol.merge_ready.v1 --payload @merge.json
+
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
   Message with 'quotes' and "quotes" and $variables.
@@ -173,9 +187,14 @@ var mailReadCmd = &cobra.Command{
 You can specify a message by its ID or by its numeric index from the inbox.
 The index corresponds to the number shown in 'gt mail inbox' (1-based).
 
+Payloads and attachments are listed after the body. Use --attachment to
+print an attachment's content, or --output to save it to a file.
+
 Examples:
   gt mail read hq-abc123    # Read by message ID
   gt mail read 3            # Read the 3rd message in inbox
+  gt mail read hq-abc123 --attachment gate.log
+  gt mail read hq-abc123 --attachment report.json -o report.json
 
 Use 'gt mail inbox' to list messages and their IDs.
 Use 'gt mail mark-read' to mark messages as read.`,
@@ -471,6 +490,9 @@ func init() {
 	mailSendCmd.Flags().StringVar(&mailTo, "to", "", "Recipient address (alternative to positional argument)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringArrayVar(&mailAttach, "attach", nil, "Attach a file (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailPayload, "payload", "", "Typed JSON payload (inline JSON or @file)")
+	mailSendCmd.Flags().StringVar(&mailPayloadSchema, "payload-schema", "", "Schema name of --payload (required with --payload)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -482,6 +504,8 @@ func init() {
 
 	// Read flags
 	mailReadCmd.Flags().BoolVar(&mailReadJSON, "json", false, "Output as JSON")
+	mailReadCmd.Flags().StringVar(&mailReadAttachment, "attachment", "", "Print the named attachment instead of the message")
+	mailReadCmd.Flags().StringVarP(&mailReadOutput, "output", "o", "", "Write the --attachment content to a file")
 
 	// Check flags
 	mailCheckCmd.Flags().BoolVar(&mailCheckInject, "inject", false, "Output format for Claude Code hooks")
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mailbridge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var mailBridgeJSON bool
+
+var mailBridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Relay mail to and from an external email inbox",
+	Long: `Relay mail for the overseer (or chosen mailboxes) to a real email inbox.
+
+Unread mail in the bridged mailboxes is sent over SMTP, once per message.
+Replies to those emails are read over IMAP and delivered back into the
+original thread, from the bridged mailbox to the original sender. Only
+replies from allow-listed addresses are delivered, and only when the
+receiving server authenticated the sender (DKIM, SPF or DMARC pass in
+Authentication-Results): the From header alone can be forged.
+
+The bridge is configured in config/messaging.json under "bridge":
+
+  "bridge": {
+    "enabled": true,
+    "mailboxes": ["overseer"],
+    "email_to": "me@example.com",
+    "smtp": {"host": "smtp.example.com", "from": "gastown@example.com",
+             "username": "gastown@example.com", "password_env": "GT_SMTP_PASSWORD"},
+    "imap": {"host": "imap.example.com", "username": "gastown@example.com",
+             "password_env": "GT_IMAP_PASSWORD"},
+    "allow_from": ["me@example.com"]
+  }
+
+Enable the daemon's mail_bridge patrol to sync periodically.
+
+Examples:
+  gt mail bridge sync
+  gt mail bridge status`,
+	RunE: requireSubcommand,
+}
+
+var mailBridgeSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Relay new mail and ingest new replies once",
+	Args:  cobra.NoArgs,
+	RunE:  runMailBridgeSync,
+}
+
+var mailBridgeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show mail bridge configuration and state",
+	Args:  cobra.NoArgs,
+	RunE:  runMailBridgeStatus,
+}
+
+func init() {
+	mailBridgeSyncCmd.Flags().BoolVar(&mailBridgeJSON, "json", false, "Output as JSON")
+	mailBridgeStatusCmd.Flags().BoolVar(&mailBridgeJSON, "json", false, "Output as JSON")
+
+	mailBridgeCmd.AddCommand(mailBridgeSyncCmd)
+	mailBridgeCmd.AddCommand(mailBridgeStatusCmd)
+
+	mailCmd.AddCommand(mailBridgeCmd)
+}
+
+func runMailBridgeSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bridge, err := mailbridge.NewFromTown(townRoot)
+	if err != nil {
+		if errors.Is(err, mailbridge.ErrNotEnabled) {
+			return fmt.Errorf("%w (configure \"bridge\" in config/messaging.json)", err)
+		}
+		return err
+	}
+
+	res, err := bridge.Sync(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if mailBridgeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	fmt.Printf("%s Relayed %d message(s) to %s, delivered %d repl(ies)\n",
+		style.Bold.Render("✓"), len(res.Relayed), bridge.EmailTo(), len(res.Replies))
+	for _, r := range res.Rejected {
+		fmt.Printf("  %s %s\n", style.Dim.Render("skipped"), r)
+	}
+	for _, e := range res.Errors {
+		fmt.Printf("  %s %s\n", style.Warning.Render("error"), e)
+	}
+	if len(res.Errors) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runMailBridgeStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := mailbridge.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	bridge, err := mailbridge.NewFromTown(townRoot)
+	enabled := err == nil
+	if err != nil && !errors.Is(err, mailbridge.ErrNotEnabled) {
+		return err
+	}
+
+	if mailBridgeJSON {
+		out := struct {
+			Enabled   bool              `json:"enabled"`
+			Mailboxes []string          `json:"mailboxes,omitempty"`
+			EmailTo   string            `json:"email_to,omitempty"`
+			State     *mailbridge.State `json:"state"`
+		}{Enabled: enabled, State: state}
+		if enabled {
+			out.Mailboxes = bridge.Mailboxes()
+			out.EmailTo = bridge.EmailTo()
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if !enabled {
+		fmt.Println("Mail bridge: disabled")
+	} else {
+		fmt.Printf("Mail bridge: enabled\n")
+		fmt.Printf("  Mailboxes: %s\n", strings.Join(bridge.Mailboxes(), ", "))
+		fmt.Printf("  Email to:  %s\n", bridge.EmailTo())
+	}
+	lastSync := "never"
+	if !state.LastSync.IsZero() {
+		lastSync = state.LastSync.Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("  Last sync: %s\n", lastSync)
+	fmt.Printf("  Relayed:   %d message(s) tracked\n", len(state.Relayed))
+	fmt.Printf("  Replies:   %d delivered\n", len(state.Ingested))
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,32 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 )
 
+// writeMailAttachment writes a message attachment to output, or to stdout
+// when output is empty.
+func writeMailAttachment(msg *mail.Message, name, output string) error {
+	a, ok := msg.FindAttachment(name)
+	if !ok {
+		return fmt.Errorf("message %s has no attachment %q", msg.ID, name)
+	}
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	data, err := mail.NewRouter(workDir).AttachmentStore().Get(a)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil { //nolint:gosec // G306: user-requested output file
+		return fmt.Errorf("writing attachment: %w", err)
+	}
+	fmt.Printf("%s Wrote %s (%s) to %s\n", style.Bold.Render("✓"), a.Name, formatBytes(a.Size), output)
+	return nil
+}
+
 // getMailbox returns the mailbox for the given address.
 func getMailbox(address string) (*mail.Mailbox, error) {
 	// All mail uses town beads (two-level architecture)
@@ -167,6 +194,11 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting message: %w", err)
 	}
 
+	// --attachment prints an attachment rather than the message
+	if mailReadAttachment != "" {
+		return writeMailAttachment(msg, mailReadAttachment, mailReadOutput)
+	}
+
 	// Mark as read when viewed (adds "read" label, does not close/archive).
 	// Handoff messages are preserved via the hook mechanism, so marking
 	// read here is safe — hooked mail is found via gt hook, not the inbox.
@@ -219,6 +251,23 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s\n", msg.Body)
 	}
 
+	if msg.Payload != nil {
+		fmt.Printf("\n%s %s\n", style.Bold.Render("Payload:"), msg.Payload.Schema)
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, msg.Payload.Data, "  ", "  "); err == nil {
+			fmt.Printf("  %s\n", pretty.String())
+		} else {
+			fmt.Printf("  %s\n", msg.Payload.Data)
+		}
+	}
+	if len(msg.Attachments) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Attachments:"))
+		for _, a := range msg.Attachments {
+			fmt.Printf("  %s  %s\n", a.Name, style.Dim.Render(fmt.Sprintf("(%s, %s)", formatBytes(a.Size), a.MediaType)))
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("gt mail read %s --attachment <name>", msg.ID)))
+	}
+
 	// Ack after output (non-fatal).
 	if ackErr := mailbox.AcknowledgeDeliveries(address, []*mail.Message{msg}); ackErr != nil {
 		fmt.Fprintf(os.Stderr, "gt mail read: delivery ack failed: %v\n", ackErr)
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Mail rules command flags
+var (
+	mailRulesJSON     bool
+	mailRuleFrom      string
+	mailRuleSubject   string
+	mailRuleType      string
+	mailRulePriority  string
+	mailRuleLabels    []string
+	mailRuleThread    string
+	mailRuleAction    string
+	mailRuleTarget    string
+	mailRuleDisabled  bool
+	mailRuleTestFrom  string
+	mailRuleTestSubj  string
+	mailRuleTestType  string
+	mailRuleTestPrio  string
+	mailRuleTestThrd  string
+	mailRuleTestLabel []string
+)
+
+var mailRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage per-mailbox mail rules",
+	Long: `Manage mail rules: per-mailbox filters applied when mail is delivered.
+
+A rule matches on sender, subject, type, priority, labels and thread, and
+applies one action. Rules of a mailbox are evaluated in order; the first
+match wins. Rules keyed by a wildcard address (e.g. */witness) apply to
+every matching mailbox, after the mailbox's own rules.
+
+Actions:
+  archive          File straight into the mailbox archive (never in the inbox)
+  mark-read        Deliver already marked read
+  forward          Deliver to --target instead
+  escalate         Deliver as urgent; also copy to --target if set
+  nudge            Deliver as a nudge if the recipient has a live session
+  attach-molecule  Attach the molecule named in the body to the recipient's hook
+
+Rules are stored in config/messaging.json under "rules".
+
+Examples:
+  gt mail rules list
+  gt mail rules add mayor/ patrol-noise --subject '^PATROL' --action archive
+  gt mail rules add mayor/ merged --subject '^MERGED ' --action mark-read
+  gt mail rules add '*/witness' help-up --subject '^HELP' --action escalate --target mayor/
+  gt mail rules test mayor/ --from gastown/witness --subject "PATROL: all clear"
+  gt mail rules remove mayor/ patrol-noise`,
+	RunE: requireSubcommand,
+}
+
+var mailRulesListCmd = &cobra.Command{
+	Use:   "list [address]",
+	Short: "List mail rules",
+	Long:  "List mail rules for all mailboxes, or the rules that apply to one mailbox.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runMailRulesList,
+}
+
+var mailRulesAddCmd = &cobra.Command{
+	Use:   "add <address> <name>",
+	Short: "Add a mail rule",
+	Long: `Add a rule to a mailbox. The rule is appended after existing rules;
+a rule with the same name is replaced in place.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMailRulesAdd,
+}
+
+var mailRulesRemoveCmd = &cobra.Command{
+	Use:   "remove <address> <name>",
+	Short: "Remove a mail rule",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runMailRulesRemove,
+}
+
+var mailRulesTestCmd = &cobra.Command{
+	Use:   "test <address>",
+	Short: "Show which rule a message would match",
+	Long: `Evaluate a mailbox's rules against a sample message without sending it.
+
+Label matching sees the labels mail is created with, e.g. thread:<id>
+(from --thread) and from:<address> (from --from).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailRulesTest,
+}
+
+func init() {
+	mailRulesListCmd.Flags().BoolVar(&mailRulesJSON, "json", false, "Output as JSON")
+
+	mailRulesAddCmd.Flags().StringVar(&mailRuleFrom, "from", "", "Match sender address pattern ('*' matches one segment)")
+	mailRulesAddCmd.Flags().StringVar(&mailRuleSubject, "subject", "", "Match subject (case-insensitive regexp)")
+	mailRulesAddCmd.Flags().StringVar(&mailRuleType, "type", "", "Match message type (task, scavenge, notification, reply)")
+	mailRulesAddCmd.Flags().StringVar(&mailRulePriority, "priority", "", "Match priority (urgent, high, normal, low)")
+	mailRulesAddCmd.Flags().StringArrayVar(&mailRuleLabels, "label", nil, "Match label (repeatable, all must match)")
+	mailRulesAddCmd.Flags().StringVar(&mailRuleThread, "thread", "", "Match thread ID")
+	mailRulesAddCmd.Flags().StringVar(&mailRuleAction, "action", "", "Action: "+strings.Join(config.MailRuleActions, ", "))
+	mailRulesAddCmd.Flags().StringVar(&mailRuleTarget, "target", "", "Target address for forward/escalate")
+	mailRulesAddCmd.Flags().BoolVar(&mailRuleDisabled, "disabled", false, "Add the rule disabled")
+	_ = mailRulesAddCmd.MarkFlagRequired("action")
+
+	mailRulesTestCmd.Flags().StringVar(&mailRuleTestFrom, "from", "", "Sender address")
+	mailRulesTestCmd.Flags().StringVar(&mailRuleTestSubj, "subject", "", "Subject")
+	mailRulesTestCmd.Flags().StringVar(&mailRuleTestType, "type", "notification", "Message type")
+	mailRulesTestCmd.Flags().StringVar(&mailRuleTestPrio, "priority", "normal", "Priority (urgent, high, normal, low)")
+	mailRulesTestCmd.Flags().StringVar(&mailRuleTestThrd, "thread", "", "Thread ID")
+	mailRulesTestCmd.Flags().StringArrayVar(&mailRuleTestLabel, "label", nil, "Extra label (repeatable)")
+
+	mailRulesCmd.AddCommand(mailRulesListCmd)
+	mailRulesCmd.AddCommand(mailRulesAddCmd)
+	mailRulesCmd.AddCommand(mailRulesRemoveCmd)
+	mailRulesCmd.AddCommand(mailRulesTestCmd)
+
+	mailCmd.AddCommand(mailRulesCmd)
+
+	mail.RegisterRuleAction(config.MailRuleAttachMolecule, attachMoleculeRuleAction)
+}
+
+// loadMessagingConfigForRules loads the town's messaging config, creating an
+// empty one if missing.
+func loadMessagingConfigForRules() (string, *config.MessagingConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := config.MessagingConfigPath(townRoot)
+	cfg, err := config.LoadOrCreateMessagingConfig(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = make(map[string][]config.MailRule)
+	}
+	return path, cfg, nil
+}
+
+// findRuleKey returns the rules key for an address, matching keys by
+// normalized identity so "mayor" and "mayor/" name the same mailbox.
+func findRuleKey(cfg *config.MessagingConfig, address string) string {
+	identity := mail.AddressToIdentity(address)
+	for key := range cfg.Rules {
+		if mail.AddressToIdentity(key) == identity {
+			return key
+		}
+	}
+	return address
+}
+
+func runMailRulesList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadMessagingConfigForRules()
+	if err != nil {
+		return err
+	}
+
+	rules := cfg.Rules
+	if len(args) == 1 {
+		rules = map[string][]config.MailRule{args[0]: mail.RulesFor(cfg, args[0])}
+	}
+
+	if mailRulesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rules)
+	}
+
+	keys := make([]string, 0, len(rules))
+	for key, list := range rules {
+		if len(list) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		fmt.Println("No mail rules configured")
+		return nil
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MAILBOX\tRULE\tMATCH\tACTION")
+	for _, key := range keys {
+		for _, rule := range rules[key] {
+			action := rule.Action
+			if rule.Target != "" {
+				action += " → " + rule.Target
+			}
+			name := rule.Name
+			if rule.Disabled {
+				name += " (disabled)"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key, name, describeRuleMatch(rule.Match), action)
+		}
+	}
+	return w.Flush()
+}
+
+// describeRuleMatch formats a rule's match conditions for display.
+func describeRuleMatch(m config.MailRuleMatch) string {
+	var parts []string
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	add("from", m.From)
+	add("subject", m.Subject)
+	add("type", m.Type)
+	add("priority", m.Priority)
+	add("thread", m.Thread)
+	for _, l := range m.Labels {
+		add("label", l)
+	}
+	if len(parts) == 0 {
+		return "(all mail)"
+	}
+	return strings.Join(parts, " ")
+}
+
+func runMailRulesAdd(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMessagingConfigForRules()
+	if err != nil {
+		return err
+	}
+
+	rule := config.MailRule{
+		Name: args[1],
+		Match: config.MailRuleMatch{
+			From:     mailRuleFrom,
+			Subject:  mailRuleSubject,
+			Type:     mailRuleType,
+			Priority: mailRulePriority,
+			Labels:   mailRuleLabels,
+			Thread:   mailRuleThread,
+		},
+		Action:   mailRuleAction,
+		Target:   mailRuleTarget,
+		Disabled: mailRuleDisabled,
+	}
+
+	key := findRuleKey(cfg, args[0])
+	rules := cfg.Rules[key]
+	replaced := false
+	for i := range rules {
+		if rules[i].Name == rule.Name {
+			rules[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		rules = append(rules, rule)
+	}
+	cfg.Rules[key] = rules
+
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return err
+	}
+
+	verb := "Added"
+	if replaced {
+		verb = "Updated"
+	}
+	fmt.Printf("%s %s rule %s for %s: %s → %s\n", style.Bold.Render("✓"), verb, rule.Name, key, describeRuleMatch(rule.Match), rule.Action)
+	return nil
+}
+
+func runMailRulesRemove(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMessagingConfigForRules()
+	if err != nil {
+		return err
+	}
+
+	key := findRuleKey(cfg, args[0])
+	rules := cfg.Rules[key]
+	kept := rules[:0]
+	for _, rule := range rules {
+		if rule.Name != args[1] {
+			kept = append(kept, rule)
+		}
+	}
+	if len(kept) == len(rules) {
+		return fmt.Errorf("no rule %q for %s", args[1], args[0])
+	}
+	if len(kept) == 0 {
+		delete(cfg.Rules, key)
+	} else {
+		cfg.Rules[key] = kept
+	}
+
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed rule %s for %s\n", style.Bold.Render("✓"), args[1], key)
+	return nil
+}
+
+func runMailRulesTest(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadMessagingConfigForRules()
+	if err != nil {
+		return err
+	}
+
+	msg := &mail.Message{
+		From:     mailRuleTestFrom,
+		To:       args[0],
+		Subject:  mailRuleTestSubj,
+		Type:     mail.ParseMessageType(mailRuleTestType),
+		Priority: mail.Priority(mailRuleTestPrio),
+		ThreadID: mailRuleTestThrd,
+	}
+	labels := []string{"gt:message", "from:" + msg.From}
+	if msg.ThreadID != "" {
+		labels = append(labels, "thread:"+msg.ThreadID)
+	}
+	labels = append(labels, mailRuleTestLabel...)
+
+	rule := mail.MatchRule(mail.RulesFor(cfg, args[0]), msg, labels)
+	if rule == nil {
+		fmt.Printf("No rule matches; the message is delivered to %s's inbox\n", args[0])
+		return nil
+	}
+	action := rule.Action
+	if rule.Target != "" {
+		action += " → " + rule.Target
+	}
+	fmt.Printf("%s Matches rule %s: %s\n", style.Bold.Render("✓"), rule.Name, action)
+	return nil
+}
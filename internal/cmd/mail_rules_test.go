@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDescribeRuleMatch(t *testing.T) {
+	tests := []struct {
+		match config.MailRuleMatch
+		want  string
+	}{
+		{config.MailRuleMatch{}, "(all mail)"},
+		{config.MailRuleMatch{From: "*/witness", Subject: "^PATROL"}, "from=*/witness subject=^PATROL"},
+		{config.MailRuleMatch{Priority: "low", Labels: []string{"a", "b"}}, "priority=low label=a label=b"},
+	}
+	for _, tt := range tests {
+		if got := describeRuleMatch(tt.match); got != tt.want {
+			t.Errorf("describeRuleMatch(%+v) = %q, want %q", tt.match, got, tt.want)
+		}
+	}
+}
+
+func TestFindRuleKey(t *testing.T) {
+	cfg := &config.MessagingConfig{Rules: map[string][]config.MailRule{
+		"mayor/":    {{Name: "x"}},
+		"*/witness": {{Name: "y"}},
+	}}
+	if got := findRuleKey(cfg, "mayor"); got != "mayor/" {
+		t.Errorf("findRuleKey(mayor) = %q, want existing key mayor/", got)
+	}
+	if got := findRuleKey(cfg, "gastown/witness"); got != "gastown/witness" {
+		t.Errorf("findRuleKey(gastown/witness) = %q, want new key", got)
+	}
+}
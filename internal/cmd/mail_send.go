@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -102,6 +105,11 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Attach typed payload and files
+	if err := addMailEnvelope(msg, workDir); err != nil {
+		return err
+	}
+
 	// Suppress router-side notification when --no-notify is passed.
 	// Otherwise the router handles idle-aware notification per-recipient,
 	// which also works correctly for fan-out (groups, lists, channels).
@@ -230,6 +238,66 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// addMailEnvelope sets the message's payload from --payload/--payload-schema
+// and stores --attach files in the town's attachment store.
+func addMailEnvelope(msg *mail.Message, workDir string) error {
+	if mailPayload == "" && mailPayloadSchema != "" {
+		return fmt.Errorf("--payload-schema requires --payload")
+	}
+	if mailPayload != "" {
+		if mailPayloadSchema == "" {
+			return fmt.Errorf("--payload requires --payload-schema")
+		}
+		data := []byte(mailPayload)
+		if path, ok := strings.CutPrefix(mailPayload, "@"); ok {
+			var err error
+			if data, err = os.ReadFile(path); err != nil { //nolint:gosec // G304: path is from the user's own flag
+				return fmt.Errorf("reading payload: %w", err)
+			}
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return fmt.Errorf("invalid --payload JSON: %w", err)
+		}
+		if compact.Len() > mail.MaxPayloadSize {
+			return fmt.Errorf("payload is %d bytes (max %d)", compact.Len(), mail.MaxPayloadSize)
+		}
+		msg.Payload = &mail.Payload{Schema: mailPayloadSchema, Data: compact.Bytes()}
+	}
+
+	if len(mailAttach) == 0 {
+		return nil
+	}
+	if len(mailAttach) > mail.MaxAttachments {
+		return fmt.Errorf("too many attachments: %d (max %d)", len(mailAttach), mail.MaxAttachments)
+	}
+	store := mail.NewRouter(workDir).AttachmentStore()
+	var total int64
+	for _, path := range mailAttach {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("attachment: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("attachment %s is a directory", path)
+		}
+		if total += info.Size(); info.Size() > mail.MaxAttachmentSize || total > mail.MaxAttachmentsSize {
+			return fmt.Errorf("attachment %s exceeds size limit (%s per file, %s per message)",
+				path, formatBytes(mail.MaxAttachmentSize), formatBytes(mail.MaxAttachmentsSize))
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the user's own flag
+		if err != nil {
+			return fmt.Errorf("reading attachment: %w", err)
+		}
+		a, err := store.Put(filepath.Base(path), data)
+		if err != nil {
+			return err
+		}
+		msg.Attachments = append(msg.Attachments, a)
+	}
+	return nil
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -77,8 +78,34 @@ func runMoleculeAttachFromMail(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a beads workspace: %w", err)
 	}
 
-	b := beads.New(workDir)
+	hookBead, issue, err := attachMoleculeToHook(beads.New(workDir), agentIdentity, moleculeID)
+	if err != nil {
+		return err
+	}
+
+	// Mark mail as read
+	if err := mailbox.MarkRead(mailID); err != nil {
+		// Non-fatal: log warning but don't fail
+		style.PrintWarning("could not mark mail as read: %v", err)
+	}
+
+	// Output success
+	attachment := beads.ParseAttachmentFields(issue)
+	fmt.Printf("%s Attached molecule from mail\n", style.Bold.Render("✓"))
+	fmt.Printf("  Mail: %s\n", mailID)
+	fmt.Printf("  Hook: %s\n", hookBead.ID)
+	fmt.Printf("  Molecule: %s\n", moleculeID)
+	if attachment != nil && attachment.AttachedAt != "" {
+		fmt.Printf("  Attached at: %s\n", attachment.AttachedAt)
+	}
+	fmt.Printf("\n%s Run 'gt hook' to see progress\n", style.Dim.Render("Hint:"))
+
+	return nil
+}
 
+// attachMoleculeToHook attaches a molecule to the agent's hook (its first
+// pinned bead) and returns the hook and the updated issue.
+func attachMoleculeToHook(b *beads.Beads, agentIdentity, moleculeID string) (*beads.Issue, *beads.Issue, error) {
 	// Find the agent's pinned bead (hook)
 	pinnedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusPinned,
@@ -86,46 +113,46 @@ func runMoleculeAttachFromMail(cmd *cobra.Command, args []string) error {
 		Priority: -1,
 	})
 	if err != nil {
-		return fmt.Errorf("listing pinned beads: %w", err)
+		return nil, nil, fmt.Errorf("listing pinned beads: %w", err)
 	}
 
 	if len(pinnedBeads) == 0 {
-		return fmt.Errorf("no pinned bead found for agent %s - create one first", agentIdentity)
+		return nil, nil, fmt.Errorf("no pinned bead found for agent %s - create one first", agentIdentity)
 	}
 
 	// Use the first pinned bead as the hook
 	hookBead := pinnedBeads[0]
 
 	// Check if molecule exists
-	_, err = b.Show(moleculeID)
-	if err != nil {
-		return fmt.Errorf("molecule %s not found: %w", moleculeID, err)
+	if _, err := b.Show(moleculeID); err != nil {
+		return nil, nil, fmt.Errorf("molecule %s not found: %w", moleculeID, err)
 	}
 
 	// Attach the molecule to the hook
 	issue, err := b.AttachMolecule(hookBead.ID, moleculeID)
 	if err != nil {
-		return fmt.Errorf("attaching molecule: %w", err)
+		return nil, nil, fmt.Errorf("attaching molecule: %w", err)
 	}
+	return hookBead, issue, nil
+}
 
-	// Mark mail as read
-	if err := mailbox.MarkRead(mailID); err != nil {
-		// Non-fatal: log warning but don't fail
-		style.PrintWarning("could not mark mail as read: %v", err)
+// attachMoleculeRuleAction implements the attach-molecule mail rule action:
+// it attaches the molecule named in the message body to the recipient's hook.
+// Town-level agents hook in town beads, rig agents in their rig's beads.
+func attachMoleculeRuleAction(townRoot, recipient string, msg *mail.Message) error {
+	moleculeID := extractMoleculeIDFromMail(msg.Body)
+	if moleculeID == "" {
+		return fmt.Errorf("no attached_molecule field found in mail body")
 	}
-
-	// Output success
-	attachment := beads.ParseAttachmentFields(issue)
-	fmt.Printf("%s Attached molecule from mail\n", style.Bold.Render("✓"))
-	fmt.Printf("  Mail: %s\n", mailID)
-	fmt.Printf("  Hook: %s\n", hookBead.ID)
-	fmt.Printf("  Molecule: %s\n", moleculeID)
-	if attachment != nil && attachment.AttachedAt != "" {
-		fmt.Printf("  Attached at: %s\n", attachment.AttachedAt)
+	if townRoot == "" {
+		return fmt.Errorf("no town root")
 	}
-	fmt.Printf("\n%s Run 'gt hook' to see progress\n", style.Dim.Render("Hint:"))
-
-	return nil
+	beadsDir := townRoot
+	if rig, _, ok := strings.Cut(strings.TrimSuffix(recipient, "/"), "/"); ok && rig != "" {
+		beadsDir = filepath.Join(townRoot, rig, "mayor", "rig")
+	}
+	_, _, err := attachMoleculeToHook(beads.New(beadsDir), recipient, moleculeID)
+	return err
 }
 
 // extractMoleculeIDFromMail extracts a molecule ID from a mail message body.
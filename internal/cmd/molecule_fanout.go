@@ -0,0 +1,841 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Fan-out worker modes (--workers on gt mol step done).
+const (
+	// FanoutWorkersSession runs each extra parallel step in its own tmux
+	// session, in the same working directory and identity as the owner.
+	// The sessions share one worktree, so it only suits steps that don't
+	// touch the same files.
+	FanoutWorkersSession = "session"
+	// FanoutWorkersPolecat slings each extra parallel step to a fresh
+	// polecat, with its own worktree and identity. This is the default.
+	FanoutWorkersPolecat = "polecat"
+	// FanoutWorkersNone keeps the old behavior: mark the steps in_progress and
+	// continue with the first one, leaving the rest for someone to pick up.
+	FanoutWorkersNone = "none"
+)
+
+// Fan-out failure policies (--on-failure on gt mol step done).
+const (
+	// FanoutFailFast fails the group as soon as one step fails and cancels
+	// the remaining workers.
+	FanoutFailFast = "fail-fast"
+	// FanoutCollect lets every step run to completion and reports all
+	// failures together at join time.
+	FanoutCollect = "collect"
+)
+
+// Fan-out member statuses.
+const (
+	fanoutRunning   = "running"
+	fanoutDone      = "done"
+	fanoutFailed    = "failed"
+	fanoutCancelled = "cancelled"
+)
+
+// Fan-out group states, derived from member statuses.
+const (
+	fanoutGroupRunning = "running"
+	fanoutGroupJoined  = "joined"
+	fanoutGroupFailed  = "failed"
+)
+
+// fanoutWorkerSelf marks the step the owning agent continues with itself.
+const fanoutWorkerSelf = "self"
+
+// fanoutPollInterval is how often gt mol step join re-checks the group.
+// This is a var (not const) so tests can shorten it.
+var fanoutPollInterval = 5 * time.Second
+
+// fanoutMember is one parallel step in a fan-out group.
+type fanoutMember struct {
+	StepID    string    `json:"step_id"`
+	Title     string    `json:"title,omitempty"`
+	Worker    string    `json:"worker"`            // "self", "session", or "polecat"
+	Session   string    `json:"session,omitempty"` // tmux session running the step
+	Agent     string    `json:"agent,omitempty"`   // polecat agent ID (polecat workers)
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// fanoutGroup tracks the parallel steps dispatched from one fan-out point of
+// a molecule, so they can be joined back when all complete or one fails.
+// Persisted at <town>/.runtime/fanout/<molecule-id>.json.
+type fanoutGroup struct {
+	MoleculeID   string          `json:"molecule_id"`
+	Owner        string          `json:"owner"`                   // agent that fanned out
+	OwnerSession string          `json:"owner_session,omitempty"` // nudged on join/failure
+	Workers      string          `json:"workers"`
+	Policy       string          `json:"policy"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Members      []*fanoutMember `json:"members"`
+}
+
+// member returns the group member for a step, or nil.
+func (g *fanoutGroup) member(stepID string) *fanoutMember {
+	for _, m := range g.Members {
+		if m.StepID == stepID {
+			return m
+		}
+	}
+	return nil
+}
+
+// state derives the group state from its members and failure policy.
+// Under fail-fast, a single failure fails the group immediately; under
+// collect, the group stays running until every member is terminal.
+func (g *fanoutGroup) state() string {
+	pending, failed := 0, 0
+	for _, m := range g.Members {
+		switch m.Status {
+		case fanoutRunning:
+			pending++
+		case fanoutFailed:
+			failed++
+		}
+	}
+	if failed > 0 && (g.Policy != FanoutCollect || pending == 0) {
+		return fanoutGroupFailed
+	}
+	if pending > 0 {
+		return fanoutGroupRunning
+	}
+	return fanoutGroupJoined
+}
+
+// failures returns the failed members.
+func (g *fanoutGroup) failures() []*fanoutMember {
+	var out []*fanoutMember
+	for _, m := range g.Members {
+		if m.Status == fanoutFailed {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// setStatus records a terminal status for a member. Terminal statuses are
+// never overwritten, so a late "done" can't mask a cancellation.
+func (m *fanoutMember) setStatus(status, errMsg string) bool {
+	if m.Status != fanoutRunning {
+		return false
+	}
+	m.Status = status
+	m.Error = errMsg
+	m.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+var fanoutUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// fanoutGroupPath returns the state file path for a molecule's fan-out group.
+func fanoutGroupPath(townRoot, moleculeID string) string {
+	return filepath.Join(townRoot, ".runtime", "fanout", fanoutUnsafeChars.ReplaceAllString(moleculeID, "_")+".json")
+}
+
+// fanoutSessionName returns the tmux session name for a session worker.
+func fanoutSessionName(ownerSession, stepID string) string {
+	base := ownerSession
+	if base == "" {
+		base = "gt"
+	}
+	return base + "-fan-" + fanoutUnsafeChars.ReplaceAllString(stepID, "-")
+}
+
+// loadFanoutGroup reads a molecule's fan-out group. Returns nil, nil if the
+// molecule has no active group.
+func loadFanoutGroup(townRoot, moleculeID string) (*fanoutGroup, error) {
+	data, err := os.ReadFile(fanoutGroupPath(townRoot, moleculeID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading fan-out group: %w", err)
+	}
+	var g fanoutGroup
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parsing fan-out group: %w", err)
+	}
+	return &g, nil
+}
+
+// updateFanoutGroup applies fn to a molecule's fan-out group under an
+// exclusive file lock and writes the result back. Workers finish
+// concurrently, so every read-modify-write goes through here.
+// Returns nil, nil if the molecule has no active group.
+func updateFanoutGroup(townRoot, moleculeID string, fn func(g *fanoutGroup) error) (*fanoutGroup, error) {
+	path := fanoutGroupPath(townRoot, moleculeID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating fan-out dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking fan-out group: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	g, err := loadFanoutGroup(townRoot, moleculeID)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	if err := fn(g); err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteJSON(path, g); err != nil {
+		return nil, fmt.Errorf("writing fan-out group: %w", err)
+	}
+	return g, nil
+}
+
+// saveFanoutGroup writes a new fan-out group, replacing any previous one
+// for the molecule.
+func saveFanoutGroup(townRoot string, g *fanoutGroup) error {
+	path := fanoutGroupPath(townRoot, g.MoleculeID)
+	if err := util.EnsureDirAndWriteJSON(path, g); err != nil {
+		return fmt.Errorf("writing fan-out group: %w", err)
+	}
+	return nil
+}
+
+// removeFanoutGroup deletes a molecule's fan-out group once it has joined.
+func removeFanoutGroup(townRoot, moleculeID string) {
+	path := fanoutGroupPath(townRoot, moleculeID)
+	_ = os.Remove(path)
+	_ = os.Remove(path + ".lock")
+}
+
+// fanoutOptions carries the fan-out flags from gt mol step done.
+type fanoutOptions struct {
+	Workers string
+	Policy  string
+}
+
+func (o fanoutOptions) validate() error {
+	switch o.Workers {
+	case FanoutWorkersSession, FanoutWorkersPolecat, FanoutWorkersNone:
+	default:
+		return fmt.Errorf("invalid --workers %q (want %s, %s, or %s)", o.Workers, FanoutWorkersSession, FanoutWorkersPolecat, FanoutWorkersNone)
+	}
+	switch o.Policy {
+	case FanoutFailFast, FanoutCollect:
+	default:
+		return fmt.Errorf("invalid --on-failure %q (want %s or %s)", o.Policy, FanoutFailFast, FanoutCollect)
+	}
+	return nil
+}
+
+// dispatchFanout dispatches every step but the first to its own worker and
+// records the group. The first step is left for the calling agent.
+func dispatchFanout(townRoot, workDir, moleculeID string, roleInfo RoleInfo, agentID string, steps []*beads.Issue, opts fanoutOptions) (*fanoutGroup, error) {
+	ownerSession := ""
+	if tmux.IsInsideTmux() || os.Getenv("GT_ROLE") != "" {
+		ownerSession, _ = getCurrentTmuxSession()
+	}
+
+	now := time.Now().UTC()
+	g := &fanoutGroup{
+		MoleculeID:   moleculeID,
+		Owner:        agentID,
+		OwnerSession: ownerSession,
+		Workers:      opts.Workers,
+		Policy:       opts.Policy,
+		CreatedAt:    now,
+	}
+	g.Members = append(g.Members, &fanoutMember{
+		StepID:    steps[0].ID,
+		Title:     steps[0].Title,
+		Worker:    fanoutWorkerSelf,
+		Status:    fanoutRunning,
+		UpdatedAt: now,
+	})
+
+	b := beads.New(workDir)
+	rigsToWake := make(map[string]bool)
+
+	// Dispatch sequentially: spawning polecats touches the same repo and
+	// beads database, and starting a session is fast. The steps still run
+	// in parallel once their workers are up.
+	for _, step := range steps[1:] {
+		m := &fanoutMember{
+			StepID:    step.ID,
+			Title:     step.Title,
+			Worker:    opts.Workers,
+			Status:    fanoutRunning,
+			UpdatedAt: now,
+		}
+		g.Members = append(g.Members, m)
+
+		var err error
+		switch opts.Workers {
+		case FanoutWorkersSession:
+			m.Session = fanoutSessionName(ownerSession, step.ID)
+			err = startFanoutSession(b, townRoot, moleculeID, roleInfo, step, m.Session)
+		case FanoutWorkersPolecat:
+			var rig string
+			rig, err = fanoutRig(townRoot, roleInfo, step.ID)
+			if err == nil {
+				var res *SlingResult
+				res, err = executeSling(SlingParams{
+					BeadID:           step.ID,
+					RigName:          rig,
+					Args:             fanoutWorkerArgs(moleculeID, step.ID),
+					NoConvoy:         true,
+					NoBoot:           true,
+					FormulaFailFatal: true,
+					CallerContext:    "mol-fanout",
+					TownRoot:         townRoot,
+				})
+				if err == nil && res.SpawnInfo != nil {
+					m.Agent = res.SpawnInfo.AgentID()
+					m.Session = res.SpawnInfo.SessionName
+					rigsToWake[rig] = true
+				}
+			}
+		}
+
+		if err != nil {
+			m.setStatus(fanoutFailed, "dispatch: "+err.Error())
+			fmt.Printf("  %s %s: dispatch failed: %v\n", style.Error.Render("✗"), step.ID, err)
+			if opts.Policy == FanoutFailFast {
+				break
+			}
+			continue
+		}
+		fmt.Printf("  %s %s → %s\n", style.Bold.Render("⚡"), step.ID, fanoutWorkerLabel(m))
+	}
+
+	// Steps never dispatched because fail-fast tripped are cancelled.
+	for _, step := range steps[len(g.Members):] {
+		g.Members = append(g.Members, &fanoutMember{
+			StepID:    step.ID,
+			Title:     step.Title,
+			Worker:    opts.Workers,
+			Status:    fanoutCancelled,
+			Error:     "not dispatched (fail-fast)",
+			UpdatedAt: now,
+		})
+	}
+
+	for rig := range rigsToWake {
+		wakeRigAgents(rig)
+	}
+
+	if err := saveFanoutGroup(townRoot, g); err != nil {
+		return g, err
+	}
+	if g.state() == fanoutGroupFailed {
+		cancelFanoutWorkers(townRoot, g)
+		_ = saveFanoutGroup(townRoot, g)
+		return g, fanoutFailureError(g)
+	}
+	return g, nil
+}
+
+// startFanoutSession starts a tmux session that runs the agent on one step.
+// The session inherits the owner's identity; GT_FANOUT_STEP tells
+// gt mol step done to join back instead of continuing the molecule.
+func startFanoutSession(b *beads.Beads, townRoot, moleculeID string, roleInfo RoleInfo, step *beads.Issue, sessionName string) error {
+	inProgress := "in_progress"
+	if err := b.Update(step.ID, beads.UpdateOptions{Status: &inProgress}); err != nil {
+		return fmt.Errorf("marking step in_progress: %w", err)
+	}
+
+	rigPath := ""
+	if roleInfo.Rig != "" {
+		rigPath = filepath.Join(townRoot, roleInfo.Rig)
+	}
+	prompt := fanoutWorkerPrompt(moleculeID, step)
+	env := config.AgentEnv(config.AgentEnvConfig{
+		Role:      string(roleInfo.Role),
+		Rig:       roleInfo.Rig,
+		AgentName: roleInfo.Polecat,
+		TownRoot:  townRoot,
+		Prompt:    prompt,
+	})
+	env["GT_FANOUT_MOLECULE"] = moleculeID
+	env["GT_FANOUT_STEP"] = step.ID
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	command := config.BuildStartupCommand(env, rigPath, prompt)
+	return tmux.NewTmux().NewSessionWithCommandAndEnv(sessionName, workDir, command, env)
+}
+
+// fanoutRig picks the rig to sling a polecat worker into: the caller's rig
+// if it has one, otherwise the rig owning the step's prefix.
+func fanoutRig(townRoot string, roleInfo RoleInfo, stepID string) (string, error) {
+	if roleInfo.Rig != "" {
+		return roleInfo.Rig, nil
+	}
+	return resolveRigFromBeadIDs([]string{stepID}, townRoot)
+}
+
+func fanoutWorkerPrompt(moleculeID string, step *beads.Issue) string {
+	return fmt.Sprintf("Fan-out worker for molecule %s. Work ONLY on step %s (%s) — run 'bd show %s' for instructions. "+
+		"When finished run 'gt mol step done %s'. If you cannot complete it, run 'gt mol step fail %s --reason \"<why>\"'.",
+		moleculeID, step.ID, step.Title, step.ID, step.ID, step.ID)
+}
+
+func fanoutWorkerArgs(moleculeID, stepID string) string {
+	return fmt.Sprintf("fan-out step of %s: finish with 'gt mol step done %s' (or 'gt mol step fail %s --reason ...')",
+		moleculeID, stepID, stepID)
+}
+
+func fanoutWorkerLabel(m *fanoutMember) string {
+	switch {
+	case m.Worker == fanoutWorkerSelf:
+		return "self"
+	case m.Agent != "":
+		return m.Agent
+	case m.Session != "":
+		return "session " + m.Session
+	default:
+		return m.Worker
+	}
+}
+
+// isFanoutWorker reports whether the current process is the worker for a
+// member, as opposed to the owner (or a human) closing the step.
+func isFanoutWorker(m *fanoutMember, agentID string) bool {
+	switch m.Worker {
+	case FanoutWorkersSession:
+		return os.Getenv("GT_FANOUT_STEP") == m.StepID
+	case FanoutWorkersPolecat:
+		return m.Agent != "" && m.Agent == agentID
+	}
+	return false
+}
+
+// cancelFanoutWorkers marks every running member cancelled and stops its
+// worker. Session workers are killed; polecats are nudged to stop, since
+// their worktree may hold work worth keeping.
+func cancelFanoutWorkers(townRoot string, g *fanoutGroup) {
+	t := tmux.NewTmux()
+	for _, m := range g.Members {
+		if m.Worker == fanoutWorkerSelf || !m.setStatus(fanoutCancelled, "cancelled (fail-fast)") {
+			continue
+		}
+		switch m.Worker {
+		case FanoutWorkersSession:
+			if m.Session != "" {
+				_ = t.KillSessionWithProcesses(m.Session)
+			}
+		case FanoutWorkersPolecat:
+			if m.Session != "" {
+				_ = nudge.Enqueue(townRoot, m.Session, nudge.QueuedNudge{
+					Sender:   "mol-fanout",
+					Message:  fmt.Sprintf("FANOUT_CANCELLED %s: a sibling step failed; stop work on %s and run 'gt done --status DEFERRED'", g.MoleculeID, m.StepID),
+					Priority: nudge.PriorityUrgent,
+				})
+			}
+		}
+	}
+}
+
+// notifyFanoutOwner tells the owning agent that its group has joined or failed.
+func notifyFanoutOwner(townRoot string, g *fanoutGroup) {
+	if g.OwnerSession == "" {
+		return
+	}
+	var msg, priority string
+	switch g.state() {
+	case fanoutGroupJoined:
+		msg = fmt.Sprintf("FANOUT_JOINED %s: all %d parallel steps complete. Run 'gt mol step join %s' to continue.", g.MoleculeID, len(g.Members), g.MoleculeID)
+		priority = nudge.PriorityNormal
+	case fanoutGroupFailed:
+		msg = fmt.Sprintf("FANOUT_FAILED %s: %s. Run 'gt mol step join %s' for details.", g.MoleculeID, fanoutFailureSummary(g), g.MoleculeID)
+		priority = nudge.PriorityUrgent
+	default:
+		return
+	}
+	if err := nudge.Enqueue(townRoot, g.OwnerSession, nudge.QueuedNudge{
+		Sender:   "mol-fanout",
+		Message:  msg,
+		Priority: priority,
+	}); err != nil {
+		style.PrintWarning("could not notify %s: %v", g.OwnerSession, err)
+	}
+}
+
+func fanoutFailureSummary(g *fanoutGroup) string {
+	var parts []string
+	for _, m := range g.failures() {
+		parts = append(parts, fmt.Sprintf("%s (%s)", m.StepID, m.Error))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func fanoutFailureError(g *fanoutGroup) error {
+	return fmt.Errorf("fan-out for %s failed (%s): %s", g.MoleculeID, g.Policy, fanoutFailureSummary(g))
+}
+
+// recordFanoutResult marks a step's member done or failed and, if that
+// settles the group under its policy, cancels the stragglers and notifies
+// the owner. Returns the updated group and member, or nils if the step is
+// not part of a fan-out.
+func recordFanoutResult(townRoot, moleculeID, stepID, status, errMsg string) (*fanoutGroup, *fanoutMember, error) {
+	var member *fanoutMember
+	var settled bool
+	g, err := updateFanoutGroup(townRoot, moleculeID, func(g *fanoutGroup) error {
+		member = g.member(stepID)
+		if member == nil {
+			return nil
+		}
+		before := g.state()
+		member.setStatus(status, errMsg)
+		if after := g.state(); after != before && after != fanoutGroupRunning {
+			settled = true
+			if after == fanoutGroupFailed {
+				cancelFanoutWorkers(townRoot, g)
+			}
+		}
+		return nil
+	})
+	if err != nil || g == nil || member == nil {
+		return nil, nil, err
+	}
+	if settled {
+		notifyFanoutOwner(townRoot, g)
+	}
+	return g, member, nil
+}
+
+// finishFanoutWorker ends a worker once its step is recorded. Session
+// workers kill their own session; polecat workers hand off through gt done.
+func finishFanoutWorker(g *fanoutGroup, m *fanoutMember, dryRun bool) error {
+	fmt.Printf("\n%s Parallel step %s finished (%s); fan-out group is %s\n",
+		style.Bold.Render("⚡"), m.StepID, m.Status, g.state())
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would stop fan-out worker %s\n", fanoutWorkerLabel(m))
+		return nil
+	}
+
+	switch m.Worker {
+	case FanoutWorkersPolecat:
+		status := "COMPLETED"
+		if m.Status != fanoutDone {
+			status = "DEFERRED"
+		}
+		doneCmd := exec.Command("gt", "done", "--status", status)
+		doneCmd.Stdout = os.Stdout
+		doneCmd.Stderr = os.Stderr
+		return doneCmd.Run()
+	case FanoutWorkersSession:
+		if m.Session != "" {
+			// Killing our own session ends this process too; do it last.
+			return tmux.NewTmux().KillSessionWithProcesses(m.Session)
+		}
+	}
+	return nil
+}
+
+// reconcileFanoutGroup refreshes running members from beads and tmux: a
+// closed step counts as done, and a worker session that exited without
+// closing its step counts as failed.
+func reconcileFanoutGroup(townRoot, moleculeID string, b *beads.Beads) (*fanoutGroup, error) {
+	var settled bool
+	g, err := updateFanoutGroup(townRoot, moleculeID, func(g *fanoutGroup) error {
+		before := g.state()
+		t := tmux.NewTmux()
+		for _, m := range g.Members {
+			if m.Status != fanoutRunning {
+				continue
+			}
+			if issue, err := b.Show(m.StepID); err == nil && issue.Status == "closed" {
+				m.setStatus(fanoutDone, "")
+				continue
+			}
+			if m.Worker != fanoutWorkerSelf && m.Session != "" {
+				if alive, err := t.HasSession(m.Session); err == nil && !alive {
+					m.setStatus(fanoutFailed, "worker session exited without closing the step")
+				}
+			}
+		}
+		if after := g.state(); after != before && after == fanoutGroupFailed {
+			settled = true
+			cancelFanoutWorkers(townRoot, g)
+		}
+		return nil
+	})
+	if settled && g != nil {
+		notifyFanoutOwner(townRoot, g)
+	}
+	return g, err
+}
+
+// --- gt mol step join / gt mol step fail ---
+
+var (
+	fanoutJoinTimeout string
+	fanoutJoinNoWait  bool
+	fanoutFailReason  string
+)
+
+var moleculeStepJoinCmd = &cobra.Command{
+	Use:   "join <molecule-id>",
+	Short: "Wait for a molecule's parallel steps to join",
+	Long: `Wait for the parallel steps of a fan-out to complete.
+
+When 'gt mol step done' finds several ready steps, it dispatches all but the
+first to their own workers (polecats or tmux sessions, see --workers) and
+continues with the first. After finishing that step, the owner runs join to
+wait for the rest.
+
+Join returns when every step is closed (exit 0), or when the group fails
+under its failure policy (exit 1):
+  fail-fast  The first failed step fails the group; other workers are cancelled
+  collect    All steps run to completion; failures are reported together
+
+A step fails when its worker runs 'gt mol step fail', or when a worker
+session exits without closing its step.
+
+Examples:
+  gt mol step join gt-abc                # Wait until the group settles
+  gt mol step join gt-abc --timeout 10m  # Give up after 10 minutes
+  gt mol step join gt-abc --no-wait      # Print group status and exit`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepJoin,
+}
+
+var moleculeStepFailCmd = &cobra.Command{
+	Use:   "fail <step-id>",
+	Short: "Report a step as failed",
+	Long: `Report that a molecule step could not be completed.
+
+If the molecule was instantiated from a formula with control flow and the
+step has retries left (retry = N), the failure is counted and the step stays
+open: it is pinned to you again and, inside tmux, the pane respawns on it
+just as 'gt mol step done' respawns on the next step. Once retries run out
+the step is closed as failed and the molecule advances to any steps whose
+when condition handles the failure (e.g. when = 'steps.deploy == "failed"'),
+fanning out with --workers and --on-failure if several become ready.
+
+For fan-out steps, the step is left open, its fan-out group records the
+failure, and the owner is notified. Under the fail-fast policy the remaining
+workers are cancelled. Run from a fan-out worker, this also ends the worker.
+
+Example:
+  gt mol step fail gt-abc.3 --reason "tests need a database we don't have"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepFail,
+}
+
+func init() {
+	moleculeStepJoinCmd.Flags().StringVar(&fanoutJoinTimeout, "timeout", "", "Maximum time to wait (e.g. 30m); default waits indefinitely")
+	moleculeStepJoinCmd.Flags().BoolVar(&fanoutJoinNoWait, "no-wait", false, "Print the current group status without waiting")
+	moleculeStepJoinCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+
+	moleculeStepFailCmd.Flags().StringVar(&fanoutFailReason, "reason", "", "Why the step failed")
+	moleculeStepFailCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepFailCmd.Flags().StringVar(&moleculeStepWorkers, "workers", FanoutWorkersPolecat, "Where failure-handling steps run if several become ready: session, polecat, or none")
+	moleculeStepFailCmd.Flags().StringVar(&moleculeStepOnFailure, "on-failure", FanoutFailFast, "Fan-out failure policy: fail-fast or collect")
+
+	moleculeStepCmd.AddCommand(moleculeStepJoinCmd)
+	moleculeStepCmd.AddCommand(moleculeStepFailCmd)
+}
+
+func runMoleculeStepJoin(cmd *cobra.Command, args []string) error {
+	moleculeID := args[0]
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding workspace: %w", err)
+	}
+	if townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+	b := beads.New(workDir)
+
+	var deadline time.Time
+	if fanoutJoinTimeout != "" {
+		d, err := time.ParseDuration(fanoutJoinTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid --timeout: %w", err)
+		}
+		deadline = time.Now().Add(d)
+	}
+
+	for {
+		g, err := reconcileFanoutGroup(townRoot, moleculeID, b)
+		if err != nil {
+			return err
+		}
+		if g == nil {
+			return fmt.Errorf("no active fan-out for %s", moleculeID)
+		}
+
+		state := g.state()
+		if state != fanoutGroupRunning || fanoutJoinNoWait {
+			if err := printFanoutGroup(g); err != nil {
+				return err
+			}
+			switch state {
+			case fanoutGroupJoined:
+				removeFanoutGroup(townRoot, moleculeID)
+				if !moleculeJSON {
+					fmt.Printf("\n%s All parallel steps complete. Continue with: gt mol step done <step-id> or gt mol progress %s\n",
+						style.Bold.Render("✓"), moleculeID)
+				}
+				return nil
+			case fanoutGroupFailed:
+				return NewSilentExit(1)
+			}
+			return nil
+		}
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			_ = printFanoutGroup(g)
+			return fmt.Errorf("timed out waiting for fan-out of %s", moleculeID)
+		}
+		time.Sleep(fanoutPollInterval)
+	}
+}
+
+func printFanoutGroup(g *fanoutGroup) error {
+	if moleculeJSON {
+		out := struct {
+			*fanoutGroup
+			State string `json:"state"`
+		}{g, g.state()}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s Fan-out %s (%s, %s): %s\n", style.Bold.Render("⚡"), g.MoleculeID, g.Workers, g.Policy, g.state())
+	for _, m := range g.Members {
+		icon := style.Dim.Render("○")
+		switch m.Status {
+		case fanoutDone:
+			icon = style.Bold.Render("✓")
+		case fanoutFailed:
+			icon = style.Error.Render("✗")
+		case fanoutCancelled:
+			icon = style.Dim.Render("⊘")
+		}
+		line := fmt.Sprintf("  %s %s [%s] %s", icon, m.StepID, fanoutWorkerLabel(m), m.Status)
+		if m.Error != "" {
+			line += ": " + m.Error
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runMoleculeStepFail(cmd *cobra.Command, args []string) error {
+	stepID := args[0]
+
+	fanoutOpts := fanoutOptions{Workers: moleculeStepWorkers, Policy: moleculeStepOnFailure}
+	if err := fanoutOpts.validate(); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding workspace: %w", err)
+	}
+	if townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+
+	b := beads.New(workDir)
+	moleculeID := extractMoleculeIDFromStep(stepID)
+	if moleculeID == "" {
+		step, err := b.Show(stepID)
+		if err != nil {
+			return fmt.Errorf("step not found: %w", err)
+		}
+		moleculeID = step.Parent
+	}
+	if moleculeID == "" {
+		return fmt.Errorf("cannot determine molecule for step %s", stepID)
+	}
+
+	reason := fanoutFailReason
+	if reason == "" {
+		reason = "reported failed"
+	}
+
+	if moleculeStepDryRun {
+		fmt.Printf("[dry-run] Would mark %s failed in %s: %s\n", stepID, moleculeID, reason)
+		_, err := advanceMoleculeRun(b, townRoot, moleculeID, stepID, formula.OutcomeFailed, reason, true)
+		return err
+	}
+
+	// Formula retry policy first: a step with retries left runs again
+	// instead of failing its fan-out group.
+	flow, err := advanceMoleculeRun(b, townRoot, moleculeID, stepID, formula.OutcomeFailed, reason, false)
+	if err != nil {
+		style.PrintWarning("could not advance molecule run: %v", err)
+	}
+	if flow != nil && flow.Retrying {
+		fmt.Printf("%s Step %s failed: %s (retrying, attempt %d)\n", style.Warning.Render("↻"), stepID, reason, flow.Attempt)
+		if g, _ := loadFanoutGroup(townRoot, moleculeID); g != nil {
+			if m := g.member(stepID); m != nil && isFanoutWorker(m, currentAgentID(cwd, townRoot)) {
+				fmt.Println("Retry the step, then run 'gt mol step done' or 'gt mol step fail' again.")
+				return nil
+			}
+		}
+		step, err := b.Show(stepID)
+		if err != nil {
+			return fmt.Errorf("step not found: %w", err)
+		}
+		return handleStepContinue(cwd, townRoot, step, false)
+	}
+
+	g, m, err := recordFanoutResult(townRoot, moleculeID, stepID, fanoutFailed, reason)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		if flow == nil {
+			return fmt.Errorf("step %s is not part of an active fan-out or formula run", stepID)
+		}
+		fmt.Printf("%s Step %s failed: %s\n", style.Error.Render("✗"), stepID, reason)
+		return continueAfterFailure(cwd, townRoot, b, moleculeID, stepID, flow, fanoutOpts)
+	}
+	fmt.Printf("%s Step %s marked failed: %s\n", style.Error.Render("✗"), stepID, reason)
+
+	if isFanoutWorker(m, currentAgentID(cwd, townRoot)) {
+		return finishFanoutWorker(g, m, false)
+	}
+	return nil
+}
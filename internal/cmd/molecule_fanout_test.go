@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestFanoutGroup(policy string, statuses ...string) *fanoutGroup {
+	g := &fanoutGroup{MoleculeID: "gt-mol", Policy: policy, Workers: FanoutWorkersPolecat}
+	for i, s := range statuses {
+		worker := FanoutWorkersPolecat
+		if i == 0 {
+			worker = fanoutWorkerSelf
+		}
+		g.Members = append(g.Members, &fanoutMember{
+			StepID: fmt.Sprintf("gt-mol.%d", i+1),
+			Worker: worker,
+			Status: s,
+		})
+	}
+	return g
+}
+
+func TestFanoutGroupState(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		statuses []string
+		want     string
+	}{
+		{"all running", FanoutFailFast, []string{fanoutRunning, fanoutRunning}, fanoutGroupRunning},
+		{"some done", FanoutFailFast, []string{fanoutDone, fanoutRunning}, fanoutGroupRunning},
+		{"all done", FanoutFailFast, []string{fanoutDone, fanoutDone, fanoutDone}, fanoutGroupJoined},
+		{"fail-fast trips on first failure", FanoutFailFast, []string{fanoutRunning, fanoutFailed, fanoutRunning}, fanoutGroupFailed},
+		{"collect waits for stragglers", FanoutCollect, []string{fanoutRunning, fanoutFailed, fanoutDone}, fanoutGroupRunning},
+		{"collect fails once settled", FanoutCollect, []string{fanoutDone, fanoutFailed, fanoutDone}, fanoutGroupFailed},
+		{"cancelled members count as settled", FanoutFailFast, []string{fanoutDone, fanoutFailed, fanoutCancelled}, fanoutGroupFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestFanoutGroup(tt.policy, tt.statuses...)
+			if got := g.state(); got != tt.want {
+				t.Errorf("state() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFanoutMemberSetStatusIsTerminal(t *testing.T) {
+	m := &fanoutMember{StepID: "gt-mol.2", Status: fanoutRunning}
+	if !m.setStatus(fanoutCancelled, "cancelled (fail-fast)") {
+		t.Fatal("setStatus on running member should succeed")
+	}
+	if m.setStatus(fanoutDone, "") {
+		t.Error("setStatus should not overwrite a terminal status")
+	}
+	if m.Status != fanoutCancelled || m.Error != "cancelled (fail-fast)" {
+		t.Errorf("member = %+v, want cancelled", m)
+	}
+}
+
+func TestFanoutNames(t *testing.T) {
+	if got := fanoutSessionName("gt-gastown-crew-max", "gt-abc.3"); got != "gt-gastown-crew-max-fan-gt-abc-3" {
+		t.Errorf("fanoutSessionName = %q", got)
+	}
+	if got := fanoutSessionName("", "go-wisp-x1"); got != "gt-fan-go-wisp-x1" {
+		t.Errorf("fanoutSessionName without owner = %q", got)
+	}
+	if got := filepath.Base(fanoutGroupPath("/town", "gt-abc/../x")); got != "gt-abc____x.json" {
+		t.Errorf("fanoutGroupPath base = %q", got)
+	}
+}
+
+func TestFanoutOptionsValidate(t *testing.T) {
+	tests := []struct {
+		opts    fanoutOptions
+		wantErr bool
+	}{
+		{fanoutOptions{FanoutWorkersSession, FanoutFailFast}, false},
+		{fanoutOptions{FanoutWorkersPolecat, FanoutCollect}, false},
+		{fanoutOptions{FanoutWorkersNone, FanoutFailFast}, false},
+		{fanoutOptions{"pane", FanoutFailFast}, true},
+		{fanoutOptions{FanoutWorkersSession, "best-effort"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("validate(%+v) err = %v, wantErr %v", tt.opts, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsFanoutWorker(t *testing.T) {
+	t.Setenv("GT_FANOUT_STEP", "gt-mol.2")
+
+	session := &fanoutMember{StepID: "gt-mol.2", Worker: FanoutWorkersSession}
+	if !isFanoutWorker(session, "") {
+		t.Error("session worker with matching GT_FANOUT_STEP should be a worker")
+	}
+	other := &fanoutMember{StepID: "gt-mol.3", Worker: FanoutWorkersSession}
+	if isFanoutWorker(other, "") {
+		t.Error("session member for another step should not be a worker")
+	}
+
+	polecat := &fanoutMember{StepID: "gt-mol.4", Worker: FanoutWorkersPolecat, Agent: "gastown/polecats/Toast"}
+	if !isFanoutWorker(polecat, "gastown/polecats/Toast") {
+		t.Error("polecat with matching agent should be a worker")
+	}
+	if isFanoutWorker(polecat, "gastown/crew/max") {
+		t.Error("owner closing a polecat's step should not be treated as the worker")
+	}
+
+	self := &fanoutMember{StepID: "gt-mol.2", Worker: fanoutWorkerSelf}
+	if isFanoutWorker(self, "") {
+		t.Error("the owner's own step is never a worker step")
+	}
+}
+
+func TestFanoutGroupPersistence(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if g, err := loadFanoutGroup(townRoot, "gt-mol"); err != nil || g != nil {
+		t.Fatalf("loadFanoutGroup(missing) = %v, %v; want nil, nil", g, err)
+	}
+	if g, err := updateFanoutGroup(townRoot, "gt-mol", func(*fanoutGroup) error { return nil }); err != nil || g != nil {
+		t.Fatalf("updateFanoutGroup(missing) = %v, %v; want nil, nil", g, err)
+	}
+
+	g := newTestFanoutGroup(FanoutCollect, fanoutRunning, fanoutRunning, fanoutRunning, fanoutRunning)
+	g.CreatedAt = time.Now().UTC()
+	if err := saveFanoutGroup(townRoot, g); err != nil {
+		t.Fatal(err)
+	}
+
+	// Workers finish concurrently; every result must land.
+	var wg sync.WaitGroup
+	for _, m := range g.Members {
+		wg.Add(1)
+		go func(stepID string) {
+			defer wg.Done()
+			if _, _, err := recordFanoutResult(townRoot, "gt-mol", stepID, fanoutDone, ""); err != nil {
+				t.Errorf("recordFanoutResult(%s): %v", stepID, err)
+			}
+		}(m.StepID)
+	}
+	wg.Wait()
+
+	loaded, err := loadFanoutGroup(townRoot, "gt-mol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.state() != fanoutGroupJoined {
+		t.Errorf("state after all done = %q, want joined", loaded.state())
+	}
+
+	removeFanoutGroup(townRoot, "gt-mol")
+	if _, err := os.Stat(fanoutGroupPath(townRoot, "gt-mol")); !os.IsNotExist(err) {
+		t.Errorf("group file still present after remove: %v", err)
+	}
+}
+
+func TestRecordFanoutResultFailFastCancels(t *testing.T) {
+	townRoot := t.TempDir()
+	g := newTestFanoutGroup(FanoutFailFast, fanoutRunning, fanoutRunning, fanoutRunning)
+	if err := saveFanoutGroup(townRoot, g); err != nil {
+		t.Fatal(err)
+	}
+
+	got, m, err := recordFanoutResult(townRoot, "gt-mol", "gt-mol.2", fanoutFailed, "tests failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.Status != fanoutFailed || m.Error != "tests failed" {
+		t.Fatalf("member = %+v, want failed", m)
+	}
+	if got.state() != fanoutGroupFailed {
+		t.Errorf("state = %q, want failed", got.state())
+	}
+	if s := got.member("gt-mol.3").Status; s != fanoutCancelled {
+		t.Errorf("sibling status = %q, want cancelled", s)
+	}
+	// The owner's own step is never cancelled out from under it.
+	if s := got.member("gt-mol.1").Status; s != fanoutRunning {
+		t.Errorf("owner step status = %q, want running", s)
+	}
+
+	// A late completion doesn't resurrect a cancelled member.
+	got, _, err = recordFanoutResult(townRoot, "gt-mol", "gt-mol.3", fanoutDone, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.member("gt-mol.3").Status; s != fanoutCancelled {
+		t.Errorf("late done changed cancelled member to %q", s)
+	}
+
+	// Steps outside the group are ignored.
+	if g, m, err := recordFanoutResult(townRoot, "gt-mol", "gt-mol.9", fanoutDone, ""); err != nil || g != nil || m != nil {
+		t.Errorf("recordFanoutResult(non-member) = %v, %v, %v; want nils", g, m, err)
+	}
+}
+
+func TestRecordFanoutResultCollect(t *testing.T) {
+	townRoot := t.TempDir()
+	g := newTestFanoutGroup(FanoutCollect, fanoutRunning, fanoutRunning, fanoutRunning)
+	if err := saveFanoutGroup(townRoot, g); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := recordFanoutResult(townRoot, "gt-mol", "gt-mol.2", fanoutFailed, "lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.state() != fanoutGroupRunning || got.member("gt-mol.3").Status != fanoutRunning {
+		t.Fatalf("collect should keep siblings running, got state %q", got.state())
+	}
+
+	for _, id := range []string{"gt-mol.1", "gt-mol.3"} {
+		if got, _, err = recordFanoutResult(townRoot, "gt-mol", id, fanoutDone, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got.state() != fanoutGroupFailed {
+		t.Errorf("state = %q, want failed once all settled", got.state())
+	}
+	if err := fanoutFailureError(got); err == nil || err.Error() != "fan-out for gt-mol failed (collect): gt-mol.2 (lint)" {
+		t.Errorf("fanoutFailureError = %v", err)
+	}
+}
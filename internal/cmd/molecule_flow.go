@@ -0,0 +1,485 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// moleculeRun is the control-flow state of a molecule instantiated from a
+// formula whose steps use when, for_each, retry or timeout. Beads only know
+// about blocking dependencies, so gt mol step done/fail consult this state
+// (via formula.Advance) to skip, retry and time out steps.
+//
+// Stored at <town>/.runtime/molecules/<molecule>.json. Molecules without
+// control flow have no run state and behave exactly as before.
+type moleculeRun struct {
+	MoleculeID string                      `json:"molecule_id"`
+	Formula    string                      `json:"formula"`
+	Vars       map[string]string           `json:"vars,omitempty"`
+	Steps      map[string]*moleculeRunStep `json:"steps"`           // by instantiated step ID
+	Beads      map[string]string           `json:"beads,omitempty"` // step bead ID -> formula step ID
+	CreatedAt  time.Time                   `json:"created_at"`
+}
+
+// moleculeRunStep tracks one formula step of a run.
+type moleculeRunStep struct {
+	Outcome   formula.StepOutcome `json:"outcome,omitempty"`
+	Failures  int                 `json:"failures,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	StartedAt time.Time           `json:"started_at"`
+}
+
+func (r *moleculeRun) step(id string) *moleculeRunStep {
+	if r.Steps == nil {
+		r.Steps = make(map[string]*moleculeRunStep)
+	}
+	s := r.Steps[id]
+	if s == nil {
+		s = &moleculeRunStep{}
+		r.Steps[id] = s
+	}
+	return s
+}
+
+func (r *moleculeRun) outcomes() map[string]formula.StepOutcome {
+	out := make(map[string]formula.StepOutcome, len(r.Steps))
+	for id, s := range r.Steps {
+		if s.Outcome != "" {
+			out[id] = s.Outcome
+		}
+	}
+	return out
+}
+
+// moleculeRunPath returns the state file path for a molecule's run.
+func moleculeRunPath(townRoot, moleculeID string) string {
+	return filepath.Join(townRoot, ".runtime", "molecules", fanoutUnsafeChars.ReplaceAllString(moleculeID, "_")+".json")
+}
+
+// loadMoleculeRun reads a molecule's run state. Returns nil, nil if the
+// molecule has none.
+func loadMoleculeRun(townRoot, moleculeID string) (*moleculeRun, error) {
+	data, err := os.ReadFile(moleculeRunPath(townRoot, moleculeID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading molecule run: %w", err)
+	}
+	var r moleculeRun
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing molecule run: %w", err)
+	}
+	return &r, nil
+}
+
+// updateMoleculeRun applies fn to a molecule's run state under an exclusive
+// file lock and writes the result back (unless fn returns an error).
+// Returns nil, nil if the molecule has no run state.
+func updateMoleculeRun(townRoot, moleculeID string, fn func(r *moleculeRun) error) (*moleculeRun, error) {
+	path := moleculeRunPath(townRoot, moleculeID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating molecules dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking molecule run: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	r, err := loadMoleculeRun(townRoot, moleculeID)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	if err := fn(r); err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteJSON(path, r); err != nil {
+		return nil, fmt.Errorf("writing molecule run: %w", err)
+	}
+	return r, nil
+}
+
+// recordMoleculeRun creates the run state for a molecule just instantiated
+// from formulaName with the given --var values. It is a no-op for formulas
+// without control flow.
+func recordMoleculeRun(townRoot, moleculeID, formulaName string, vars []string) error {
+	f, err := loadMoleculeFormula(formulaName)
+	if err != nil {
+		return err
+	}
+	if !f.HasControlFlow() {
+		return nil
+	}
+	r := &moleculeRun{
+		MoleculeID: moleculeID,
+		Formula:    formulaName,
+		Vars:       parseVarArgs(vars),
+		Steps:      make(map[string]*moleculeRunStep),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := util.EnsureDirAndWriteJSON(moleculeRunPath(townRoot, moleculeID), r); err != nil {
+		return fmt.Errorf("writing molecule run: %w", err)
+	}
+	return nil
+}
+
+// loadMoleculeFormula loads a formula by name from the formula search path,
+// falling back to the embedded formulas.
+func loadMoleculeFormula(name string) (*formula.Formula, error) {
+	if path, err := findFormulaFile(name); err == nil {
+		return parseFormulaFile(path)
+	}
+	content, err := formula.GetEmbeddedFormulaContent(name)
+	if err != nil {
+		return nil, fmt.Errorf("formula %q not found", name)
+	}
+	return formula.Parse(content)
+}
+
+// parseVarArgs converts key=value --var arguments to a map.
+func parseVarArgs(vars []string) map[string]string {
+	out := make(map[string]string, len(vars))
+	for _, v := range vars {
+		if key, val, ok := strings.Cut(v, "="); ok && key != "" {
+			out[key] = val
+		}
+	}
+	return out
+}
+
+// moleculeRunClose is a step bead that the run decided to close.
+type moleculeRunClose struct {
+	Bead   string
+	Step   string
+	Reason string
+}
+
+// moleculeFlow is the outcome of advancing a molecule run.
+type moleculeFlow struct {
+	wf     *formula.Formula // instantiated formula
+	beads  map[string]string
+	ready  []string // instantiated step IDs ready to start
+	closes []moleculeRunClose
+	notes  []string
+
+	// Retrying is set when a failed step still has retries left; the step
+	// stays open and should be attempted again.
+	Retrying bool
+	Attempt  int
+}
+
+// allows reports whether a ready step bead may start. Beads that don't map
+// to a formula step are left to beads' own readiness.
+func (fl *moleculeFlow) allows(beadID string) bool {
+	ref, ok := fl.beads[beadID]
+	if !ok {
+		return true
+	}
+	return slices.ContainsFunc(runMembers(fl.wf, ref), func(id string) bool {
+		return slices.Contains(fl.ready, id)
+	})
+}
+
+// runMembers returns the instantiated steps a bead's step reference stands
+// for: the step itself, or every copy of a for_each step. Beads know nothing
+// about for_each, so a loop is a single bead covering all its copies.
+func runMembers(wf *formula.Formula, ref string) []string {
+	if wf.GetStep(ref) != nil {
+		return []string{ref}
+	}
+	var members []string
+	for _, step := range wf.Steps {
+		if strings.HasPrefix(step.ID, ref+".") {
+			members = append(members, step.ID)
+		}
+	}
+	return members
+}
+
+// filterReady drops ready beads that the run is holding back, e.g. steps
+// whose needs failed without a when condition handling the failure.
+func (fl *moleculeFlow) filterReady(steps []*beads.Issue) []*beads.Issue {
+	if fl == nil {
+		return steps
+	}
+	var out []*beads.Issue
+	for _, s := range steps {
+		if fl.allows(s.ID) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// stepTimeout returns the timeout of the formula step behind a bead.
+func (fl *moleculeFlow) stepTimeout(beadID string) time.Duration {
+	if fl == nil {
+		return 0
+	}
+	for _, id := range runMembers(fl.wf, fl.beads[beadID]) {
+		return fl.wf.GetStep(id).TimeoutDuration()
+	}
+	return 0
+}
+
+// formulaStepField is the description field naming the formula step a step
+// bead was cooked from, e.g. "formula_step: deploy".
+const formulaStepField = "formula_step"
+
+// tagFormulaSteps appends a formulaStepField line to each step description,
+// so the beads bd pours from the formula carry their step IDs.
+func tagFormulaSteps(f *formula.Formula) {
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		desc := strings.TrimRight(step.Description, "\n")
+		if desc != "" {
+			desc += "\n\n"
+		}
+		step.Description = desc + formulaStepField + ": " + step.ID
+	}
+}
+
+// beadFormulaStep returns the formula step ID recorded in a step bead's
+// description, or "" if it has none.
+func beadFormulaStep(issue *beads.Issue) string {
+	for _, line := range strings.Split(issue.Description, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), formulaStepField+":"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// matchStepBeads maps step beads to formula steps by the step ID recorded in
+// each bead (see tagFormulaSteps). f is the formula before instantiation, so
+// a for_each step maps to its single bead. Beads that name no step of f are
+// left to beads' own readiness.
+func matchStepBeads(r *moleculeRun, f *formula.Formula, children []*beads.Issue) {
+	if r.Beads == nil {
+		r.Beads = make(map[string]string)
+	}
+	for _, child := range children {
+		if _, known := r.Beads[child.ID]; known {
+			continue
+		}
+		if ref := beadFormulaStep(child); ref != "" && f.GetStep(ref) != nil {
+			r.Beads[child.ID] = ref
+		}
+	}
+}
+
+// stepBead returns the bead behind an instantiated step, if any.
+func (r *moleculeRun) stepBead(id string) string {
+	for bead, ref := range r.Beads {
+		if ref == id || strings.HasPrefix(id, ref+".") {
+			return bead
+		}
+	}
+	return ""
+}
+
+// planMoleculeRun records the outcome of stepBead (if any), applies
+// timeouts and the retry policy, and advances the run. f is the formula and
+// wf its instantiation for the run. It only changes r; the returned flow
+// lists the beads to close.
+func planMoleculeRun(r *moleculeRun, f, wf *formula.Formula, children []*beads.Issue, stepBead string, outcome formula.StepOutcome, errMsg string, now time.Time) *moleculeFlow {
+	matchStepBeads(r, f, children)
+	fl := &moleculeFlow{wf: wf, beads: r.Beads}
+	closing := make(map[string]bool)
+	closeStep := func(id, reason string) {
+		bead := r.stepBead(id)
+		if bead == "" || bead == stepBead && outcome == formula.OutcomeDone || closing[bead] {
+			return
+		}
+		// A for_each bead closes once every copy has an outcome.
+		for _, m := range runMembers(wf, r.Beads[bead]) {
+			if r.step(m).Outcome == "" {
+				return
+			}
+		}
+		closing[bead] = true
+		fl.closes = append(fl.closes, moleculeRunClose{Bead: bead, Step: r.Beads[bead], Reason: reason})
+	}
+
+	// fail applies the retry policy to a failed attempt. Returns true if the
+	// step may run again.
+	fail := func(step *formula.Step, msg string) bool {
+		s := r.step(step.ID)
+		s.Failures++
+		s.Error = msg
+		if step.CanRetry(s.Failures) {
+			s.StartedAt = now
+			return true
+		}
+		s.Outcome = formula.OutcomeFailed
+		closeStep(step.ID, "failed: "+msg)
+		return false
+	}
+
+	if ref, ok := r.Beads[stepBead]; ok && outcome != "" {
+		for _, id := range runMembers(wf, ref) {
+			step, s := wf.GetStep(id), r.step(id)
+			if s.Outcome != "" {
+				continue
+			}
+			if outcome == formula.OutcomeFailed {
+				if fail(step, errMsg) {
+					fl.Retrying = true
+					fl.Attempt = s.Failures + 1
+				}
+				continue
+			}
+			if timeout := step.TimeoutDuration(); timeout > 0 && !s.StartedAt.IsZero() && now.Sub(s.StartedAt) > timeout {
+				fl.notes = append(fl.notes, fmt.Sprintf("step %s finished after its %s timeout", id, step.Timeout))
+			}
+			s.Outcome = outcome
+		}
+	}
+
+	// Steps closed outside gt mol step done count as done.
+	for _, child := range children {
+		if ref, ok := r.Beads[child.ID]; ok && child.Status == "closed" {
+			for _, id := range runMembers(wf, ref) {
+				if s := r.step(id); s.Outcome == "" {
+					s.Outcome = formula.OutcomeDone
+				}
+			}
+		}
+	}
+
+	// Expire running steps that overran their timeout.
+	for i := range wf.Steps {
+		step := &wf.Steps[i]
+		s := r.Steps[step.ID]
+		timeout := step.TimeoutDuration()
+		if s == nil || s.Outcome != "" || s.StartedAt.IsZero() || timeout == 0 || now.Sub(s.StartedAt) <= timeout {
+			continue
+		}
+		if fail(step, "timed out after "+step.Timeout) {
+			fl.notes = append(fl.notes, fmt.Sprintf("step %s timed out after %s, retry %d/%d", step.ID, step.Timeout, s.Failures, step.Retry))
+		} else {
+			fl.notes = append(fl.notes, fmt.Sprintf("step %s timed out after %s", step.ID, step.Timeout))
+		}
+	}
+
+	// Steps dropped at instantiation, then steps skipped as the run advances.
+	for _, id := range wf.SkippedSteps() {
+		if s := r.step(id); s.Outcome == "" {
+			s.Outcome = formula.OutcomeSkipped
+			closeStep(id, skipReason(f.GetStep(id)))
+		}
+	}
+	ready, skipped := wf.Advance(r.Vars, r.outcomes())
+	for _, id := range skipped {
+		r.step(id).Outcome = formula.OutcomeSkipped
+		closeStep(id, skipReason(wf.GetStep(id)))
+	}
+	for _, id := range ready {
+		if s := r.step(id); s.StartedAt.IsZero() {
+			s.StartedAt = now
+		}
+	}
+	fl.ready = ready
+	return fl
+}
+
+func skipReason(step *formula.Step) string {
+	if step == nil || step.When == "" {
+		return "skipped"
+	}
+	return "skipped: when " + step.When
+}
+
+// advanceMoleculeRun records the outcome of a step bead in its molecule's
+// run and closes the step beads the run skips or fails. Returns nil, nil
+// for molecules without run state.
+func advanceMoleculeRun(b *beads.Beads, townRoot, moleculeID, stepBead string, outcome formula.StepOutcome, errMsg string, dryRun bool) (*moleculeFlow, error) {
+	run, err := loadMoleculeRun(townRoot, moleculeID)
+	if err != nil || run == nil {
+		return nil, err
+	}
+	f, err := loadMoleculeFormula(run.Formula)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := f.Instantiate(run.Vars)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating formula %s: %w", run.Formula, err)
+	}
+	children, err := b.List(beads.ListOptions{Parent: moleculeID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing molecule steps: %w", err)
+	}
+
+	var fl *moleculeFlow
+	plan := func(r *moleculeRun) error {
+		fl = planMoleculeRun(r, f, wf, children, stepBead, outcome, errMsg, time.Now().UTC())
+		return nil
+	}
+	if dryRun {
+		_ = plan(run)
+	} else if _, err := updateMoleculeRun(townRoot, moleculeID, plan); err != nil {
+		return nil, err
+	}
+
+	for _, note := range fl.notes {
+		style.PrintWarning("%s", note)
+	}
+	for _, c := range fl.closes {
+		if dryRun {
+			fmt.Printf("[dry-run] Would close step %s (%s)\n", c.Bead, c.Reason)
+			continue
+		}
+		if err := b.CloseWithReason(c.Reason, c.Bead); err != nil {
+			return nil, fmt.Errorf("closing step %s: %w", c.Bead, err)
+		}
+		fmt.Printf("%s Closed step %s (%s): %s\n", style.Dim.Render("⊘"), c.Bead, c.Step, c.Reason)
+	}
+	return fl, nil
+}
+
+// printStepTimeout shows the timeout of the formula step behind a bead.
+func printStepTimeout(fl *moleculeFlow, step *beads.Issue) {
+	if d := fl.stepTimeout(step.ID); d > 0 {
+		fmt.Printf("  %s Timeout: %s\n", style.Dim.Render("⏱"), d)
+	}
+}
+
+// continueAfterFailure moves a molecule on after a step failed for good:
+// to the steps that handle the failure, or to completion if nothing is left.
+// Several ready handlers fan out with opts.
+func continueAfterFailure(cwd, townRoot string, b *beads.Beads, moleculeID, stepID string, fl *moleculeFlow, opts fanoutOptions) error {
+	readySteps, allComplete, err := findAllReadySteps(b, moleculeID)
+	if err != nil {
+		return fmt.Errorf("finding next steps: %w", err)
+	}
+	readySteps = fl.filterReady(readySteps)
+	switch {
+	case allComplete:
+		return handleMoleculeComplete(cwd, townRoot, moleculeID, false)
+	case len(readySteps) == 0:
+		fmt.Printf("\n%s No ready step handles the failure of %s\n", style.Dim.Render("ℹ"), stepID)
+		fmt.Printf("Run 'gt mol progress %s' to see blocked steps\n", moleculeID)
+		return nil
+	case len(readySteps) > 1:
+		workDir, err := findLocalBeadsDir()
+		if err != nil {
+			return fmt.Errorf("not in a beads workspace: %w", err)
+		}
+		return handleParallelSteps(cwd, townRoot, workDir, moleculeID, readySteps, false, opts)
+	}
+	printStepTimeout(fl, readySteps[0])
+	return handleStepContinue(cwd, townRoot, readySteps[0], false)
+}
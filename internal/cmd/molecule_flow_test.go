@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+const testFlowFormula = `
+formula = "release"
+
+[vars.feature]
+required = true
+
+[vars.targets]
+default = ""
+
+[[steps]]
+id = "implement"
+title = "Implement {{feature}}"
+
+[[steps]]
+id = "deploy"
+title = "Deploy to {{item}}"
+needs = ["implement"]
+for_each = "targets"
+retry = 1
+timeout = "30m"
+
+[[steps]]
+id = "rollback"
+title = "Roll back"
+needs = ["deploy"]
+when = 'steps.deploy == "failed"'
+
+[[steps]]
+id = "announce"
+title = "Announce {{feature}}"
+needs = ["deploy"]
+`
+
+func testFlowRun(t *testing.T) (*moleculeRun, *formula.Formula, *formula.Formula, []*beads.Issue) {
+	t.Helper()
+	f, err := formula.Parse([]byte(testFlowFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"feature": "login", "targets": "staging,prod"}
+	wf, err := f.Instantiate(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := []*beads.Issue{
+		{ID: "mol.1", Title: "Implement login", Description: "Write it.\n\nformula_step: implement", Status: "open"},
+		{ID: "mol.2", Title: "Deploy to {{item}}", Description: "formula_step: deploy", Status: "open"},
+		{ID: "mol.3", Title: "Roll back", Description: "formula_step: rollback", Status: "open"},
+		{ID: "mol.4", Title: "Announce login", Description: "formula_step: announce", Status: "open"},
+	}
+	return &moleculeRun{Formula: "release", Vars: vars}, f, wf, children
+}
+
+func TestPlanMoleculeRun_SkipsUnhandledBranch(t *testing.T) {
+	r, f, wf, children := testFlowRun(t)
+	now := time.Now()
+
+	fl := planMoleculeRun(r, f, wf, children, "", "", "", now)
+	if strings.Join(fl.ready, ",") != "implement" {
+		t.Fatalf("ready = %v", fl.ready)
+	}
+	if r.Beads["mol.2"] != "deploy" || r.Beads["mol.4"] != "announce" {
+		t.Errorf("beads not matched by title: %v", r.Beads)
+	}
+
+	children[0].Status = "closed"
+	fl = planMoleculeRun(r, f, wf, children, "mol.1", formula.OutcomeDone, "", now)
+	if strings.Join(fl.ready, ",") != "deploy.1,deploy.2" || !fl.allows("mol.2") || fl.allows("mol.4") {
+		t.Fatalf("ready = %v", fl.ready)
+	}
+	if fl.stepTimeout("mol.2") != 30*time.Minute {
+		t.Errorf("stepTimeout = %v", fl.stepTimeout("mol.2"))
+	}
+
+	children[1].Status = "closed"
+	fl = planMoleculeRun(r, f, wf, children, "mol.2", formula.OutcomeDone, "", now)
+	if strings.Join(fl.ready, ",") != "announce" {
+		t.Errorf("ready = %v", fl.ready)
+	}
+	if len(fl.closes) != 1 || fl.closes[0].Bead != "mol.3" || !strings.HasPrefix(fl.closes[0].Reason, "skipped: when") {
+		t.Errorf("closes = %+v, want rollback skipped", fl.closes)
+	}
+}
+
+func TestPlanMoleculeRun_RetryThenFail(t *testing.T) {
+	r, f, wf, children := testFlowRun(t)
+	now := time.Now()
+	children[0].Status = "closed"
+	planMoleculeRun(r, f, wf, children, "mol.1", formula.OutcomeDone, "", now)
+
+	fl := planMoleculeRun(r, f, wf, children, "mol.2", formula.OutcomeFailed, "smoke test", now)
+	if !fl.Retrying || fl.Attempt != 2 || len(fl.closes) != 0 {
+		t.Fatalf("first failure should retry: %+v", fl)
+	}
+
+	fl = planMoleculeRun(r, f, wf, children, "mol.2", formula.OutcomeFailed, "smoke test", now)
+	if fl.Retrying {
+		t.Fatal("retries exhausted, should not retry")
+	}
+	if len(fl.closes) != 1 || fl.closes[0].Bead != "mol.2" || fl.closes[0].Reason != "failed: smoke test" {
+		t.Errorf("closes = %+v", fl.closes)
+	}
+	if !fl.allows("mol.3") || fl.allows("mol.4") {
+		t.Errorf("rollback should handle the failure, announce stays blocked: ready = %v", fl.ready)
+	}
+}
+
+func TestPlanMoleculeRun_Timeout(t *testing.T) {
+	r, f, wf, children := testFlowRun(t)
+	start := time.Now()
+	children[0].Status = "closed"
+	planMoleculeRun(r, f, wf, children, "mol.1", formula.OutcomeDone, "", start)
+
+	later := start.Add(31 * time.Minute)
+	fl := planMoleculeRun(r, f, wf, children, "", "", "", later)
+	if got := r.step("deploy.1").Failures; got != 1 {
+		t.Errorf("failures = %d, want 1 after timeout", got)
+	}
+	if !r.step("deploy.1").StartedAt.Equal(later) {
+		t.Error("retry should restart the timeout clock")
+	}
+	if len(fl.notes) != 2 || !strings.Contains(fl.notes[0], "timed out after 30m, retry 1/1") {
+		t.Errorf("notes = %v", fl.notes)
+	}
+
+	planMoleculeRun(r, f, wf, children, "", "", "", later.Add(31*time.Minute))
+	if r.step("deploy.1").Outcome != formula.OutcomeFailed {
+		t.Errorf("outcome = %q, want failed once retries run out", r.step("deploy.1").Outcome)
+	}
+}
+
+func TestMatchStepBeads_ByStepID(t *testing.T) {
+	r, f, _, _ := testFlowRun(t)
+	children := []*beads.Issue{
+		// Renamed after pouring: still the implement step
+		{ID: "mol.1", Title: "Implement login (v2)", Description: "formula_step: implement"},
+		// Same title as a step but no step ID: not a formula step
+		{ID: "mol.9", Title: "Roll back"},
+		{ID: "mol.8", Description: "formula_step: nonexistent"},
+	}
+	matchStepBeads(r, f, children)
+	if len(r.Beads) != 1 || r.Beads["mol.1"] != "implement" {
+		t.Errorf("Beads = %v, want only mol.1 -> implement", r.Beads)
+	}
+
+	tagged := *f
+	tagged.Steps = append([]formula.Step(nil), f.Steps...)
+	tagFormulaSteps(&tagged)
+	for i, step := range tagged.Steps {
+		if got := beadFormulaStep(&beads.Issue{Description: step.Description}); got != step.ID {
+			t.Errorf("tagged step %s reads back as %q", step.ID, got)
+		}
+		if f.Steps[i].Description != "" && !strings.HasPrefix(step.Description, f.Steps[i].Description) {
+			t.Errorf("tagging changed step %s description", step.ID)
+		}
+	}
+}
+
+func TestMoleculeRun_RecordAndUpdate(t *testing.T) {
+	town := t.TempDir()
+	if err := recordMoleculeRun(town, "gt-wisp-1", "mol-polecat-work", []string{"issue=gt-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := loadMoleculeRun(town, "gt-wisp-1"); r != nil {
+		t.Error("formula without control flow should not get a run")
+	}
+
+	path := moleculeRunPath(town, "gt-wisp-2")
+	if r, err := updateMoleculeRun(town, "gt-wisp-2", func(*moleculeRun) error { return nil }); r != nil || err != nil {
+		t.Errorf("update of missing run = %v, %v", r, err)
+	}
+	if !strings.HasSuffix(path, "/.runtime/molecules/gt-wisp-2.json") {
+		t.Errorf("path = %s", path)
+	}
+
+	if got := parseVarArgs([]string{"a=1", "b=x=y", "junk"}); len(got) != 2 || got["b"] != "x=y" {
+		t.Errorf("parseVarArgs = %v", got)
+	}
+}
@@ -6,10 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -29,26 +29,58 @@ This command handles the step-to-step transition for polecats:
 4. If next step exists:
    - Updates the hook to point to the next step
    - Respawns the pane for a fresh session
-5. If molecule complete:
+5. If several steps are ready (fan-out):
+   - Dispatches all but the first to their own workers (--workers)
+   - Continues with the first step
+   - Run 'gt mol step join <molecule-id>' to wait for the others
+6. If molecule complete:
    - Clears the hook
    - Sends POLECAT_DONE to witness
    - Exits the session
 
+Formula control flow:
+  When the molecule comes from a formula whose steps use when, for_each,
+  retry or timeout, the step outcome is recorded in the molecule's run and
+  steps whose when condition is false are closed as skipped. Steps that
+  depend on a failed step only start if their when condition handles it.
+  Use 'gt mol step fail' to report a failed step (retries apply).
+
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
+Fan-out workers:
+  polecat   Each parallel step is slung to a fresh polecat in the rig, with
+            its own worktree and identity (default)
+  session   Each parallel step runs in its own tmux session in your worktree,
+            as you; only for steps that don't touch the same files
+  none      Mark the steps in_progress and continue with the first only
+
+Failure policy (--on-failure):
+  fail-fast  The first failed parallel step cancels the others (default)
+  collect    Let every parallel step finish and report failures at join
+
+When a fan-out worker finishes its step, it records the result in the
+fan-out group and exits instead of continuing the molecule. The owner is
+nudged when the group joins or fails.
+
 Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+  gt mol step done gt-abc.1                     # Complete step 1 of molecule gt-abc
+  gt mol step done gt-abc.1 --workers session   # Fan out to sessions in this worktree
+  gt mol step done gt-abc.1 --on-failure collect`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
-	moleculeStepDryRun bool
+	moleculeStepDryRun    bool
+	moleculeStepWorkers   string
+	moleculeStepOnFailure string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepWorkers, "workers", FanoutWorkersPolecat, "Where parallel steps run on fan-out: polecat, session, or none")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepOnFailure, "on-failure", FanoutFailFast, "Fan-out failure policy: fail-fast or collect")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 }
 
@@ -67,6 +99,11 @@ type StepDoneResult struct {
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	stepID := args[0]
 
+	fanoutOpts := fanoutOptions{Workers: moleculeStepWorkers, Policy: moleculeStepOnFailure}
+	if err := fanoutOpts.validate(); err != nil {
+		return err
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
@@ -124,11 +161,39 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
 	}
 
+	// Step 3b: If the step belongs to a fan-out, record it in the group.
+	// Fan-out workers join back here instead of continuing the molecule.
+	var fanout *fanoutGroup
+	var fanoutMember *fanoutMember
+	if moleculeStepDryRun {
+		fanout, err = loadFanoutGroup(townRoot, moleculeID)
+		if fanout != nil {
+			fanoutMember = fanout.member(stepID)
+		}
+	} else {
+		fanout, fanoutMember, err = recordFanoutResult(townRoot, moleculeID, stepID, fanoutDone, "")
+	}
+	if err != nil {
+		style.PrintWarning("could not update fan-out group: %v", err)
+	}
+
+	// Step 3c: Advance the molecule's control flow (when/for_each/retry/timeout).
+	// Steps whose conditions turn out false are closed as skipped here.
+	flow, err := advanceMoleculeRun(b, townRoot, moleculeID, stepID, formula.OutcomeDone, "", moleculeStepDryRun)
+	if err != nil {
+		style.PrintWarning("could not advance molecule run: %v", err)
+	}
+
+	if fanoutMember != nil && isFanoutWorker(fanoutMember, currentAgentID(cwd, townRoot)) {
+		return finishFanoutWorker(fanout, fanoutMember, moleculeStepDryRun)
+	}
+
 	// Step 4: Find all ready steps (supports fan-out pattern)
 	readySteps, allComplete, err := findAllReadySteps(b, moleculeID)
 	if err != nil {
 		return fmt.Errorf("finding next steps: %w", err)
 	}
+	readySteps = flow.filterReady(readySteps)
 
 	if allComplete {
 		result.Complete = true
@@ -159,15 +224,29 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	// Step 5: Handle next action
 	switch result.Action {
 	case "continue":
+		printStepTimeout(flow, readySteps[0])
 		return handleStepContinue(cwd, townRoot, readySteps[0], moleculeStepDryRun)
 
 	case "parallel":
-		return handleParallelSteps(cwd, townRoot, workDir, readySteps, moleculeStepDryRun)
+		return handleParallelSteps(cwd, townRoot, workDir, moleculeID, readySteps, moleculeStepDryRun, fanoutOpts)
 
 	case "done":
+		if fanout != nil && !moleculeStepDryRun {
+			removeFanoutGroup(townRoot, moleculeID)
+		}
 		return handleMoleculeComplete(cwd, townRoot, moleculeID, moleculeStepDryRun)
 
 	case "no_more_ready":
+		if fanout != nil {
+			switch fanout.state() {
+			case fanoutGroupRunning:
+				fmt.Printf("\n%s Waiting on parallel steps of %s\n", style.Dim.Render("ℹ"), moleculeID)
+				fmt.Printf("Run 'gt mol step join %s' to wait for them\n", moleculeID)
+				return nil
+			case fanoutGroupFailed:
+				return fanoutFailureError(fanout)
+			}
+		}
 		fmt.Printf("\n%s All remaining steps are blocked - waiting on dependencies\n",
 			style.Dim.Render("ℹ"))
 		fmt.Printf("Run 'gt mol progress %s' to see blocked steps\n", moleculeID)
@@ -346,27 +425,58 @@ func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool
 	return t.RespawnPane(pane, restartCmd)
 }
 
-// handleParallelSteps handles executing multiple steps concurrently (fan-out pattern).
-// This function spawns goroutines to execute each step in parallel and waits for all to complete.
-func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun bool) error {
+// handleParallelSteps handles a fan-out: several steps became ready at once.
+// All but the first are dispatched to their own workers (tmux sessions or
+// polecats) and tracked as a fan-out group; the calling agent continues with
+// the first step and later joins the group with 'gt mol step join'.
+func handleParallelSteps(cwd, townRoot, workDir, moleculeID string, steps []*beads.Issue, dryRun bool, opts fanoutOptions) error {
 	fmt.Printf("\n%s Fan-out: %d parallel steps ready\n", style.Bold.Render("⚡"), len(steps))
 	for i, step := range steps {
 		fmt.Printf("  %d. %s: %s\n", i+1, step.ID, step.Title)
 	}
 
 	if dryRun {
-		fmt.Printf("\n[dry-run] Would execute %d steps in parallel\n", len(steps))
+		if opts.Workers == FanoutWorkersNone {
+			fmt.Printf("\n[dry-run] Would mark %d steps in_progress and continue with %s\n", len(steps), steps[0].ID)
+		} else {
+			fmt.Printf("\n[dry-run] Would dispatch %d steps to %s workers (%s) and continue with %s\n",
+				len(steps)-1, opts.Workers, opts.Policy, steps[0].ID)
+		}
 		return nil
 	}
 
-	// For parallel execution, we use goroutines with a WaitGroup
-	// Each step is executed by running its commands in sequence
-	// For now, we execute them sequentially but mark them all as in_progress first
-	// TODO: True parallel execution requires spawning subagents or separate tmux panes
+	if opts.Workers == FanoutWorkersNone {
+		return handleParallelStepsInline(cwd, townRoot, steps)
+	}
 
-	fmt.Printf("\n%s Executing parallel steps...\n", style.Bold.Render("🔄"))
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return fmt.Errorf("detecting role: %w", err)
+	}
+	agentID := buildAgentIdentity(RoleContext{
+		Role:     roleInfo.Role,
+		Rig:      roleInfo.Rig,
+		Polecat:  roleInfo.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  cwd,
+	})
+
+	fmt.Printf("\n%s Dispatching %d parallel steps (%s workers, %s)...\n",
+		style.Bold.Render("🔄"), len(steps)-1, opts.Workers, opts.Policy)
+
+	if _, err := dispatchFanout(townRoot, workDir, moleculeID, roleInfo, agentID, steps, opts); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s When %s is done, run: gt mol step join %s\n", style.Dim.Render("ℹ"), steps[0].ID, moleculeID)
+	fmt.Printf("\n%s Continuing with first parallel step: %s\n", style.Bold.Render("→"), steps[0].ID)
+	return handleStepContinue(cwd, townRoot, steps[0], dryRun)
+}
 
-	// Mark all steps as in_progress
+// handleParallelStepsInline is the --workers=none fan-out: mark every ready
+// step in_progress and continue with the first. The others are left for
+// other agents or manual execution.
+func handleParallelStepsInline(cwd, townRoot string, steps []*beads.Issue) error {
 	gitRoot, err := getGitRoot()
 	if err != nil {
 		return fmt.Errorf("finding git root: %w", err)
@@ -381,48 +491,28 @@ func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun b
 		}
 	}
 
-	// Execute steps concurrently using goroutines
-	// Note: This is simplified - each step's "execution" just marks it complete
-	// In practice, the agent (witness/deacon) needs to actually do the work described in step.Description
-	// For true parallel execution, this would spawn separate tmux panes or Task subagents
-
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(steps))
-
-	for _, step := range steps {
-		wg.Add(1)
-		go func(s *beads.Issue) {
-			defer wg.Done()
-
-			// Execute the step by closing it
-			// In a real implementation, the agent would process the step description
-			// For now, we just mark it as requiring manual execution
-			fmt.Printf("  %s Step %s ready for parallel execution\n", style.Dim.Render("→"), s.ID)
-		}(step)
-	}
-
-	wg.Wait()
-	close(errChan)
-
-	// Check for errors
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
 	fmt.Printf("\n%s All parallel steps marked as in_progress\n", style.Bold.Render("✓"))
 	fmt.Printf("%s Execute each step and close with: gt mol step done <step-id>\n", style.Dim.Render("ℹ"))
 	fmt.Printf("%s Once all parallel steps are closed, the gather step will become ready\n", style.Dim.Render("ℹ"))
 
-	// For the current agent, pick the first step to continue with
-	// Other steps can be picked up by other agents or run manually
-	if len(steps) > 0 {
-		fmt.Printf("\n%s Continuing with first parallel step: %s\n", style.Bold.Render("→"), steps[0].ID)
-		return handleStepContinue(cwd, townRoot, steps[0], dryRun)
-	}
+	fmt.Printf("\n%s Continuing with first parallel step: %s\n", style.Bold.Render("→"), steps[0].ID)
+	return handleStepContinue(cwd, townRoot, steps[0], false)
+}
 
-	return nil
+// currentAgentID returns the agent identity of the calling process, or ""
+// if it can't be determined.
+func currentAgentID(cwd, townRoot string) string {
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return ""
+	}
+	return buildAgentIdentity(RoleContext{
+		Role:     roleInfo.Role,
+		Rig:      roleInfo.Rig,
+		Polecat:  roleInfo.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  cwd,
+	})
 }
 
 // handleMoleculeComplete handles when a molecule is complete.
@@ -23,6 +23,7 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitStrategy  string
 
 	// Retry flags
 	mqRetryNow bool
@@ -93,6 +94,11 @@ Target branch auto-detection:
 
 This ensures batch work on epics automatically flows to integration branches.
 
+Merge strategy:
+  The Refinery lands the MR using the rig's merge_queue.merge_strategy
+  (squash, merge-commit, or rebase). --merge-strategy records a per-MR
+  override on the MR bead.
+
 Polecat auto-cleanup:
   When run from a polecat work branch (polecat/<worker>/<issue>), this command
   automatically triggers polecat shutdown after submitting the MR. The polecat
@@ -106,6 +112,7 @@ Examples:
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
+  gt mq submit --merge-strategy squash   # Override the rig's merge strategy
   gt mq submit --no-cleanup              # Submit without auto-cleanup`,
 	RunE: runMqSubmit,
 }
@@ -308,6 +315,7 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitStrategy, "merge-strategy", "", "Merge strategy for this MR: squash, merge-commit, or rebase (default: rig setting)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ flakes command flags
+var (
+	mqFlakesJSON        bool
+	mqFlakesQuarantined bool
+	mqFlakesReason      string
+)
+
+var mqFlakesCmd = &cobra.Command{
+	Use:   "flakes <rig>",
+	Short: "List flaky tests and the quarantine",
+	Long: `Show the rig's flaky test history recorded by the refinery.
+
+A failure counts as a flake when the same tree passes on retry, or when an
+MR that failed passes later at the same branch head. Tests that flake
+repeatedly are quarantined automatically (merge_queue.flake_quarantine_threshold,
+default 3) or by hand with 'gt mq flakes quarantine'.
+
+When a gate fails and every failed test is quarantined, the refinery re-runs
+the gate (quarantine_mode "retry", the default) or ignores the failures
+("skip"). Other failures are never retried this way. Gate commands see the
+quarantined test IDs in $GT_QUARANTINED_TESTS.
+
+Examples:
+  gt mq flakes gastown
+  gt mq flakes gastown --quarantined
+  gt mq flakes quarantine gastown example.com/pkg.TestSlow --reason "races on CI"
+  gt mq flakes release gastown example.com/pkg.TestSlow`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFlakes,
+}
+
+var mqFlakesQuarantineCmd = &cobra.Command{
+	Use:   "quarantine <rig> <test-id>...",
+	Short: "Quarantine flaky tests",
+	Long: `Add tests to the rig's quarantine list.
+
+Test IDs are "package.TestName" as shown by 'gt mq flakes' and
+'gt mq status' (for JUnit reports, "classname.name").`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMQFlakesQuarantine,
+}
+
+var mqFlakesReleaseCmd = &cobra.Command{
+	Use:   "release <rig> <test-id>...",
+	Short: "Release tests from quarantine",
+	Long: `Remove tests from the rig's quarantine list.
+
+Their flake count starts over, so a test that keeps flaking is
+quarantined again once it reaches the threshold anew. The rest of its
+history is kept.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMQFlakesRelease,
+}
+
+func init() {
+	mqFlakesCmd.Flags().BoolVar(&mqFlakesJSON, "json", false, "Output as JSON")
+	mqFlakesCmd.Flags().BoolVar(&mqFlakesQuarantined, "quarantined", false, "Show only quarantined tests")
+	mqFlakesQuarantineCmd.Flags().StringVarP(&mqFlakesReason, "reason", "r", "", "Why the test is quarantined")
+
+	mqFlakesCmd.AddCommand(mqFlakesQuarantineCmd)
+	mqFlakesCmd.AddCommand(mqFlakesReleaseCmd)
+	mqCmd.AddCommand(mqFlakesCmd)
+}
+
+// flakeStoreForRig opens the flake database of a rig.
+func flakeStoreForRig(rigName string) (*refinery.FlakeStore, error) {
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	return refinery.NewFlakeStore(refinery.FlakeDBPath(r.Path)), nil
+}
+
+func runMQFlakes(cmd *cobra.Command, args []string) error {
+	store, err := flakeStoreForRig(args[0])
+	if err != nil {
+		return err
+	}
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+	if mqFlakesQuarantined {
+		var filtered []*refinery.FlakeRecord
+		for _, r := range records {
+			if r.Quarantined {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+
+	if mqFlakesJSON {
+		if records == nil {
+			records = []*refinery.FlakeRecord{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("%s No flaky tests recorded for %s\n", style.Dim.Render("○"), args[0])
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Flaky tests: %s", args[0])))
+	for _, r := range records {
+		fmt.Println(formatFlakeRecord(r, time.Now()))
+	}
+	return nil
+}
+
+// formatFlakeRecord renders one test's flake history for the list view.
+func formatFlakeRecord(r *refinery.FlakeRecord, now time.Time) string {
+	var sb strings.Builder
+	icon := "○"
+	if r.Quarantined {
+		icon = style.Warning.Render("⊘")
+	}
+	fmt.Fprintf(&sb, "  %s %s\n", icon, r.Test)
+	stats := fmt.Sprintf("flakes: %d  failures: %d", r.Flakes, r.Failures)
+	if !r.LastFlake.IsZero() {
+		stats += fmt.Sprintf("  last flake: %s ago (%s)", formatFlakeAge(now.Sub(r.LastFlake)), r.LastKind)
+	}
+	fmt.Fprintf(&sb, "    %s", style.Dim.Render(stats))
+	if len(r.MRs) > 0 {
+		fmt.Fprintf(&sb, "\n    %s", style.Dim.Render("MRs: "+strings.Join(r.MRs, ", ")))
+	}
+	if r.Quarantined {
+		detail := "quarantined"
+		if r.QuarantinedBy != "" {
+			detail += " by " + r.QuarantinedBy
+		}
+		if r.QuarantineReason != "" {
+			detail += ": " + r.QuarantineReason
+		}
+		fmt.Fprintf(&sb, "\n    %s", style.Warning.Render(detail))
+	}
+	return sb.String()
+}
+
+func formatFlakeAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func runMQFlakesQuarantine(cmd *cobra.Command, args []string) error {
+	store, err := flakeStoreForRig(args[0])
+	if err != nil {
+		return err
+	}
+	by := detectSender()
+	for _, test := range args[1:] {
+		if err := store.Quarantine(test, by, mqFlakesReason); err != nil {
+			return fmt.Errorf("quarantining %s: %w", test, err)
+		}
+		fmt.Printf("%s Quarantined %s\n", style.Bold.Render("✓"), test)
+	}
+	return nil
+}
+
+func runMQFlakesRelease(cmd *cobra.Command, args []string) error {
+	store, err := flakeStoreForRig(args[0])
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, test := range args[1:] {
+		if err := store.Release(test); err != nil {
+			if errors.Is(err, refinery.ErrUnknownTest) {
+				missing = append(missing, test)
+				continue
+			}
+			return fmt.Errorf("releasing %s: %w", test, err)
+		}
+		fmt.Printf("%s Released %s from quarantine\n", style.Bold.Render("✓"), test)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("not in flake database: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestFormatFlakeRecord(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &refinery.FlakeRecord{
+		Test:             "example.com/pkg.TestSlow",
+		Failures:         4,
+		Flakes:           3,
+		MRs:              []string{"gt-mr1", "gt-mr2"},
+		LastFlake:        now.Add(-2 * time.Hour),
+		LastKind:         refinery.FlakeRetryPass,
+		Quarantined:      true,
+		QuarantinedBy:    "refinery",
+		QuarantineReason: "auto: flaked 3 times",
+	}
+	got := formatFlakeRecord(r, now)
+	for _, want := range []string{
+		"example.com/pkg.TestSlow",
+		"flakes: 3  failures: 4",
+		"last flake: 2h ago (retry-pass)",
+		"MRs: gt-mr1, gt-mr2",
+		"quarantined by refinery: auto: flaked 3 times",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatFlakeRecord missing %q:\n%s", want, got)
+		}
+	}
+
+	plain := formatFlakeRecord(&refinery.FlakeRecord{Test: "p.TestA", Failures: 1}, now)
+	if strings.Contains(plain, "quarantined") || strings.Contains(plain, "last flake") {
+		t.Errorf("unexpected details for a plain record:\n%s", plain)
+	}
+}
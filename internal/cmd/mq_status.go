@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -34,6 +35,11 @@ type MRStatusOutput struct {
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
 
+	// Gate report from the last failed gate run
+	FailedTests   []string               `json:"failed_tests,omitempty"`
+	GateArtifacts string                 `json:"gate_artifacts,omitempty"`
+	Gates         []refinery.GateSummary `json:"gates,omitempty"`
+
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
 	Blocks    []DependencyInfo `json:"blocks,omitempty"`
@@ -95,6 +101,20 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.FailedTests = mrFields.FailedTests
+		output.GateArtifacts = mrFields.GateArtifacts
+	}
+
+	// The bead carries a capped list; report.json has every gate and test.
+	var gateReport *refinery.GateReport
+	if output.GateArtifacts != "" {
+		if report, err := refinery.LoadGateReport(output.GateArtifacts); err == nil {
+			gateReport = report
+			output.Gates = report.Gates
+			if ids := report.FailedTestIDs(); len(ids) > 0 {
+				output.FailedTests = ids
+			}
+		}
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -127,11 +147,11 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
-	return printMqStatus(issue, mrFields)
+	return printMqStatus(issue, mrFields, gateReport)
 }
 
 // printMqStatus prints detailed MR status in human-readable format.
-func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
+func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields, gateReport *refinery.GateReport) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("📋 Merge Request:"), issue.ID)
 	fmt.Printf("   %s\n\n", issue.Title)
@@ -186,6 +206,19 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		}
 	}
 
+	// Gate report (last failed gate run)
+	if gateReport != nil {
+		printGateReport(gateReport)
+	} else if mrFields != nil && len(mrFields.FailedTests) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Failed Tests"))
+		for _, id := range mrFields.FailedTests {
+			fmt.Printf("   ✗ %s\n", id)
+		}
+		if mrFields.GateArtifacts != "" {
+			fmt.Printf("   %s\n", style.Dim.Render("Logs: "+mrFields.GateArtifacts))
+		}
+	}
+
 	// Dependencies (what this MR is waiting on)
 	if len(issue.Dependencies) > 0 {
 		fmt.Printf("\n%s\n", style.Bold.Render("Waiting On"))
@@ -225,6 +258,40 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 	return nil
 }
 
+// mqStatusTestOutputLines caps the output lines shown per failed test.
+const mqStatusTestOutputLines = 8
+
+// printGateReport prints each gate's outcome, its failed tests with an
+// output excerpt, and where the full logs live.
+func printGateReport(report *refinery.GateReport) {
+	fmt.Printf("\n%s %s\n", style.Bold.Render("Gate Report"),
+		style.Dim.Render(fmt.Sprintf("(%s)", report.CreatedAt.Local().Format(time.RFC3339))))
+	for _, g := range report.Gates {
+		icon := "✓"
+		if !g.Success {
+			icon = "✗"
+		}
+		fmt.Printf("   %s %s %s\n", icon, g.Name, style.Dim.Render(fmt.Sprintf("(%v)", g.Elapsed.Truncate(time.Millisecond))))
+		for _, ft := range g.FailedTests {
+			fmt.Printf("      ✗ %s\n", ft.ID())
+			lines := strings.Split(ft.Output, "\n")
+			if len(lines) > mqStatusTestOutputLines {
+				lines = lines[len(lines)-mqStatusTestOutputLines:]
+			}
+			for _, line := range lines {
+				if strings.TrimSpace(line) != "" {
+					fmt.Printf("        %s\n", style.Dim.Render(line))
+				}
+			}
+		}
+		for _, log := range []string{g.StdoutLog, g.StderrLog, g.ReportFile} {
+			if log != "" {
+				fmt.Printf("      %s\n", style.Dim.Render(log))
+			}
+		}
+	}
+}
+
 // formatStatus formats the status with appropriate styling.
 func formatStatus(status string) string {
 	switch status {
@@ -314,20 +381,26 @@ func getDescriptionWithoutMRFields(description string) string {
 
 	// Known MR field keys (lowercase)
 	mrKeys := map[string]bool{
-		"branch":       true,
-		"target":       true,
-		"source_issue": true,
-		"source-issue": true,
-		"sourceissue":  true,
-		"worker":       true,
-		"rig":          true,
-		"merge_commit": true,
-		"merge-commit": true,
-		"mergecommit":  true,
-		"close_reason": true,
-		"close-reason": true,
-		"closereason":  true,
-		"type":         true,
+		"branch":         true,
+		"target":         true,
+		"source_issue":   true,
+		"source-issue":   true,
+		"sourceissue":    true,
+		"worker":         true,
+		"rig":            true,
+		"merge_commit":   true,
+		"merge-commit":   true,
+		"mergecommit":    true,
+		"close_reason":   true,
+		"close-reason":   true,
+		"closereason":    true,
+		"failed_tests":   true,
+		"failed-tests":   true,
+		"failedtests":    true,
+		"gate_artifacts": true,
+		"gate-artifacts": true,
+		"gateartifacts":  true,
+		"type":           true,
 	}
 
 	var lines []string
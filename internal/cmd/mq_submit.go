@@ -76,6 +76,10 @@ func parseBranchName(branch string) branchInfo {
 }
 
 func runMqSubmit(cmd *cobra.Command, args []string) error {
+	if mqSubmitStrategy != "" && !config.IsValidMergeStrategy(mqSubmitStrategy) {
+		return fmt.Errorf("invalid --merge-strategy %q: must be squash, merge-commit, or rebase", mqSubmitStrategy)
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -209,6 +213,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	if mqSubmitStrategy != "" {
+		description += fmt.Sprintf("\nmerge_strategy: %s", mqSubmitStrategy)
+	}
 
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
@@ -247,6 +254,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
+	if mqSubmitStrategy != "" {
+		fmt.Printf("  Strategy: %s\n", mqSubmitStrategy)
+	}
 	fmt.Printf("  Priority: P%d\n", priority)
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
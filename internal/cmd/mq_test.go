@@ -131,6 +131,11 @@ func TestGetDescriptionWithoutMRFields(t *testing.T) {
 			description: "branch: polecat/Nux/gt-xyz\nSome custom notes\ntarget: main",
 			want:        "Some custom notes",
 		},
+		{
+			name:        "gate report fields",
+			description: "branch: polecat/Nux/gt-xyz\nfailed_tests: pkg.TestA, pkg.TestB\ngate_artifacts: /tmp/gates/gt-mr1\nSome custom notes",
+			want:        "Some custom notes",
+		},
 		{
 			name:        "no MR fields",
 			description: "Just a regular description\nWith multiple lines",
@@ -311,6 +311,7 @@ func TestBuildRefineryPatrolVars_BoolFormat(t *testing.T) {
 		TestCommand:                      "make test",
 		BuildCommand:                     "make build",
 		DeleteMergedBranches:             &falseVal2,
+		MergeStrategy:                    config.MergeStrategyMergeCommit,
 	}
 	settings := config.RigSettings{
 		Type:       "rig-settings",
@@ -349,6 +350,9 @@ func TestBuildRefineryPatrolVars_BoolFormat(t *testing.T) {
 	if got := varMap["test_command"]; got != "make test" {
 		t.Errorf("test_command = %q, want %q", got, "make test")
 	}
+	if got := varMap["merge_strategy"]; got != "merge-commit" {
+		t.Errorf("merge_strategy = %q, want %q", got, "merge-commit")
+	}
 	if got := varMap["setup_command"]; got != "npm ci" {
 		t.Errorf("setup_command = %q, want %q", got, "npm ci")
 	}
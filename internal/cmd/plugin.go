@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -74,6 +76,7 @@ var pluginShowCmd = &cobra.Command{
 	Long: `Show detailed information about a plugin.
 
 Displays the plugin's configuration, gate settings, and instructions.
+For cron gates, also shows when the daemon will next run the plugin.
 
 Examples:
   gt plugin show rebuild-gt
@@ -90,6 +93,9 @@ var pluginRunCmd = &cobra.Command{
 By default, checks if the gate would allow execution and informs you
 if it wouldn't. Use --force to bypass gate checks.
 
+Executable plugins (execution.entrypoint set) run their entrypoint here
+and record its result. Other plugins print their instructions.
+
 Examples:
   gt plugin run rebuild-gt              # Run if gate allows
   gt plugin run rebuild-gt --force      # Bypass gate check
@@ -263,10 +269,30 @@ func printPluginSummary(p *plugin.Plugin) {
 	}
 }
 
+// printPluginNextRun prints when a cron-gated plugin next runs, counting
+// from its last recorded run or dispatch as the daemon does.
+func printPluginNextRun(p *plugin.Plugin, townRoot string) {
+	gates, err := plugin.NewGateEvaluator(townRoot)
+	if err != nil {
+		fmt.Printf("  Next run: %s\n", style.Dim.Render(err.Error()))
+		return
+	}
+	next, ok, err := gates.NextRun(p)
+	switch {
+	case err != nil:
+		fmt.Printf("  Next run: %s\n", style.Warning.Render(err.Error()))
+	case !ok:
+	case !next.After(time.Now()):
+		fmt.Printf("  Next run: %s\n", style.Warning.Render(fmt.Sprintf("due now (missed %s)", next.Format("2006-01-02 15:04 MST"))))
+	default:
+		fmt.Printf("  Next run: %s (in %s)\n", next.Format("2006-01-02 15:04 MST"), formatDuration(time.Until(next)))
+	}
+}
+
 func runPluginShow(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	scanner, _, err := getPluginScanner()
+	scanner, townRoot, err := getPluginScanner()
 	if err != nil {
 		return err
 	}
@@ -280,7 +306,7 @@ func runPluginShow(cmd *cobra.Command, args []string) error {
 		return outputPluginShowJSON(p)
 	}
 
-	return outputPluginShowText(p)
+	return outputPluginShowText(p, townRoot)
 }
 
 func outputPluginShowJSON(p *plugin.Plugin) error {
@@ -289,7 +315,7 @@ func outputPluginShowJSON(p *plugin.Plugin) error {
 	return enc.Encode(p)
 }
 
-func outputPluginShowText(p *plugin.Plugin) error {
+func outputPluginShowText(p *plugin.Plugin, townRoot string) error {
 	fmt.Printf("%s %s\n", style.Bold.Render("Plugin:"), p.Name)
 	fmt.Printf("%s %s\n", style.Bold.Render("Path:"), p.Path)
 
@@ -320,9 +346,13 @@ func outputPluginShowText(p *plugin.Plugin) error {
 		if p.Gate.Check != "" {
 			fmt.Printf("  Check: %s\n", p.Gate.Check)
 		}
+		if p.Gate.Timeout != "" {
+			fmt.Printf("  Timeout: %s\n", p.Gate.Timeout)
+		}
 		if p.Gate.On != "" {
 			fmt.Printf("  On: %s\n", p.Gate.On)
 		}
+		printPluginNextRun(p, townRoot)
 	} else {
 		fmt.Printf("  Type: manual (no gate section)\n")
 	}
@@ -341,6 +371,9 @@ func outputPluginShowText(p *plugin.Plugin) error {
 	if p.Execution != nil {
 		fmt.Println()
 		fmt.Printf("%s\n", style.Bold.Render("Execution:"))
+		if p.Execution.Entrypoint != "" {
+			fmt.Printf("  Entrypoint: %s (run by the daemon)\n", p.Execution.Entrypoint)
+		}
 		if p.Execution.Timeout != "" {
 			fmt.Printf("  Timeout: %s\n", p.Execution.Timeout)
 		}
@@ -410,6 +443,8 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		}
 		if !gateOpen {
 			fmt.Printf("%s %s (use --force to override)\n", style.Warning.Render("Gate closed:"), gateReason)
+		} else if p.IsExecutable() {
+			fmt.Printf("%s Would run %s\n", style.Success.Render("Gate open:"), p.EntrypointPath())
 		} else {
 			fmt.Printf("%s Would execute plugin instructions\n", style.Success.Render("Gate open:"))
 		}
@@ -430,6 +465,11 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s\n", style.Dim.Render("(gate bypassed with --force)"))
 	}
 	fmt.Println()
+
+	if p.IsExecutable() {
+		return runExecutablePlugin(townRoot, p)
+	}
+
 	fmt.Printf("%s\n", style.Bold.Render("Instructions:"))
 	fmt.Println(p.Instructions)
 
@@ -450,6 +490,44 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runExecutablePlugin runs a plugin's entrypoint and records its result.
+func runExecutablePlugin(townRoot string, p *plugin.Plugin) error {
+	recorder := plugin.NewRecorder(townRoot)
+	last, err := recorder.GetLastRun(p.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: reading last run: %v\n", err)
+	}
+
+	res, err := plugin.Exec(context.Background(), p, plugin.NewExecContext(townRoot, p, "manual", last))
+	if err != nil {
+		return err
+	}
+
+	switch res.Result {
+	case plugin.ResultSuccess:
+		fmt.Printf("%s %s\n", style.Success.Render("✓ success"), res.Summary)
+	case plugin.ResultSkipped:
+		fmt.Printf("%s %s\n", style.Dim.Render("○ skipped"), res.Summary)
+	default:
+		fmt.Printf("%s %s\n", style.Error.Render("✗ failure"), res.Summary)
+	}
+	if res.Details != "" {
+		fmt.Println(res.Details)
+	}
+
+	beadID, err := recorder.RecordRun(res.RunRecord(p))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", err)
+	} else {
+		fmt.Printf("\n%s Recorded run: %s\n", style.Dim.Render("●"), beadID)
+	}
+
+	if res.Result == plugin.ResultFailure {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
 func runPluginHistory(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -134,8 +134,9 @@ func showFormulaSteps(formulaName, label string) {
 }
 
 // showFormulaStepsFull renders formula steps with full descriptions.
-// Used for polecat work formulas where step details are the primary instructions.
-func showFormulaStepsFull(formulaName string) {
+// vars are the molecule's formula vars (nil if unknown); they decide which
+// conditional steps apply and expand for_each steps.
+func showFormulaStepsFull(formulaName string, vars map[string]string) {
 	content, err := formula.GetEmbeddedFormulaContent(formulaName)
 	if err != nil {
 		style.PrintWarning("could not load formula %s: %v", formulaName, err)
@@ -147,20 +148,72 @@ func showFormulaStepsFull(formulaName string) {
 		style.PrintWarning("could not parse formula %s: %v", formulaName, err)
 		return
 	}
+	if f, err = f.Instantiate(vars); err != nil {
+		style.PrintWarning("could not instantiate formula %s: %v", formulaName, err)
+		return
+	}
 
 	if len(f.Steps) == 0 {
 		return
 	}
 
+	render := func(text string) string {
+		if vars == nil {
+			return text
+		}
+		return f.Render(text, vars)
+	}
+
 	fmt.Println()
 	fmt.Printf("**Formula Checklist** (%d steps from %s):\n\n", len(f.Steps), formulaName)
 	for i, step := range f.Steps {
-		fmt.Printf("### Step %d: %s\n\n", i+1, step.Title)
+		fmt.Printf("### Step %d: %s\n\n", i+1, render(step.Title))
+		if notes := stepFlowNotes(step); notes != "" {
+			fmt.Printf("_%s_\n\n", notes)
+		}
 		if step.Description != "" {
-			fmt.Println(step.Description)
+			fmt.Println(render(step.Description))
 			fmt.Println()
 		}
 	}
+	if skipped := f.SkippedSteps(); len(skipped) > 0 {
+		fmt.Printf("Skipped (condition not met): %s\n", strings.Join(skipped, ", "))
+	}
+	// Inline steps have no beads for gt mol step done/fail to track, so
+	// only var conditions (applied above) are enforced.
+	if f.HasControlFlow() {
+		fmt.Println("Retries, timeouts and step-outcome conditions above are not tracked for")
+		fmt.Println("inline steps: retry a failing step yourself, and skip steps whose condition")
+		fmt.Println("doesn't hold.")
+	}
+}
+
+// stepFlowNotes summarizes a step's when/retry/timeout policy for the
+// checklist, e.g. "Only if: steps.deploy == \"failed\" · Retries: 2".
+func stepFlowNotes(step formula.Step) string {
+	var notes []string
+	if step.When != "" {
+		notes = append(notes, "Only if: "+step.When)
+	}
+	if step.Retry > 0 {
+		notes = append(notes, fmt.Sprintf("Retries: %d", step.Retry))
+	}
+	if step.Timeout != "" {
+		notes = append(notes, "Timeout: "+step.Timeout)
+	}
+	return strings.Join(notes, " · ")
+}
+
+// moleculeRunVars returns the formula vars recorded for a molecule, or nil.
+func moleculeRunVars(townRoot, moleculeID string) map[string]string {
+	if townRoot == "" || moleculeID == "" {
+		return nil
+	}
+	run, err := loadMoleculeRun(townRoot, moleculeID)
+	if err != nil || run == nil {
+		return nil
+	}
+	return run.Vars
 }
 
 // truncateDescription truncates a multi-line description to a single line summary.
@@ -324,5 +377,8 @@ func buildRefineryPatrolVars(ctx RoleContext) []string {
 		vars = append(vars, fmt.Sprintf("build_command=%s", mq.BuildCommand))
 	}
 	vars = append(vars, fmt.Sprintf("delete_merged_branches=%t", mq.IsDeleteMergedBranchesEnabled()))
+	if mq.MergeStrategy != "" {
+		vars = append(vars, fmt.Sprintf("merge_strategy=%s", mq.MergeStrategy))
+	}
 	return vars
 }
@@ -45,7 +45,9 @@ Commands:
   gt quota status            Show account quota status
   gt quota scan              Detect rate-limited sessions
   gt quota rotate            Swap blocked sessions to available accounts
-  gt quota clear             Mark account(s) as available again`,
+  gt quota clear             Mark account(s) as available again
+  gt quota usage             Show token usage, budgets and forecasts
+  gt quota budget            Set per-account or per-rig token budgets`,
 }
 
 var quotaStatusCmd = &cobra.Command{
@@ -64,13 +66,14 @@ Examples:
 
 // QuotaStatusItem represents an account in status output.
 type QuotaStatusItem struct {
-	Handle    string `json:"handle"`
-	Email     string `json:"email"`
-	Status    string `json:"status"`
-	LimitedAt string `json:"limited_at,omitempty"`
-	ResetsAt  string `json:"resets_at,omitempty"`
-	LastUsed  string `json:"last_used,omitempty"`
-	IsDefault bool   `json:"is_default"`
+	Handle        string `json:"handle"`
+	Email         string `json:"email"`
+	Status        string `json:"status"`
+	LimitedAt     string `json:"limited_at,omitempty"`
+	ResetsAt      string `json:"resets_at,omitempty"`
+	CooldownUntil string `json:"cooldown_until,omitempty"`
+	LastUsed      string `json:"last_used,omitempty"`
+	IsDefault     bool   `json:"is_default"`
 }
 
 func runQuotaStatus(cmd *cobra.Command, args []string) error {
@@ -127,13 +130,14 @@ func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaSta
 			status = string(config.QuotaStatusAvailable)
 		}
 		items = append(items, QuotaStatusItem{
-			Handle:    handle,
-			Email:     acct.Email,
-			Status:    status,
-			LimitedAt: qs.LimitedAt,
-			ResetsAt:  qs.ResetsAt,
-			LastUsed:  qs.LastUsed,
-			IsDefault: handle == acctCfg.Default,
+			Handle:        handle,
+			Email:         acct.Email,
+			Status:        status,
+			LimitedAt:     qs.LimitedAt,
+			ResetsAt:      qs.ResetsAt,
+			CooldownUntil: qs.CooldownUntil,
+			LastUsed:      qs.LastUsed,
+			IsDefault:     handle == acctCfg.Default,
 		})
 	}
 	enc := json.NewEncoder(os.Stdout)
@@ -177,6 +181,9 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 		case config.QuotaStatusCooldown:
 			badge = style.Warning.Render("cooldown")
 			limited++
+			if qs.CooldownUntil != "" {
+				badge += style.Dim.Render(" (until " + formatCooldownUntil(qs.CooldownUntil) + ")")
+			}
 		default:
 			badge = style.Dim.Render("unknown")
 		}
@@ -324,9 +331,10 @@ func printScanText(results []quota.ScanResult) error {
 
 // Rotate command flags
 var (
-	rotateDryRun bool
-	rotateFrom   string
-	rotateIdle   bool
+	rotateDryRun   bool
+	rotateFrom     string
+	rotateIdle     bool
+	rotateCooldown bool
 )
 
 var quotaRotateCmd = &cobra.Command{
@@ -341,6 +349,10 @@ Use --from to preemptively rotate sessions using a specific account before
 it hits its rate limit. This is useful for switching idle sessions while
 it's not disruptive.
 
+Use --cooldown to put each exhausted account in cooldown until the reset
+time shown in its session (or 5h if none is shown), so it isn't rotated back
+in before then. The daemon's quota_rotation patrol runs rotate this way.
+
 The rotation process:
   1. Scans all Gas Town sessions for rate-limit indicators
   2. Selects available accounts (LRU order)
@@ -352,6 +364,7 @@ Examples:
   gt quota rotate                    # Rotate all blocked sessions
   gt quota rotate --from work        # Preemptively rotate sessions on 'work' account
   gt quota rotate --from work --idle # Only rotate idle sessions on 'work' account
+  gt quota rotate --cooldown         # Rotate and cool down exhausted accounts
   gt quota rotate --dry-run          # Show plan without executing
   gt quota rotate --json             # JSON output`,
 	RunE: runQuotaRotate,
@@ -554,6 +567,12 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if rotateCooldown && rotateFrom == "" {
+		if err := coolDownRotatedAccounts(mgr, plan, results); err != nil {
+			style.PrintWarning("could not record account cooldowns: %v", err)
+		}
+	}
+
 	if quotaJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -563,6 +582,48 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// coolDownRotatedAccounts puts the accounts that rate-limited sessions were
+// rotated away from in cooldown until their reset time. Only confirmed
+// rotations count, so a stale limit message in a session we couldn't rotate
+// doesn't shrink the pool.
+func coolDownRotatedAccounts(mgr *quota.Manager, plan *quota.RotatePlan, results []quota.RotateResult) error {
+	limited := make(map[string]quota.ScanResult, len(plan.LimitedSessions))
+	for _, r := range plan.LimitedSessions {
+		limited[r.Session] = r
+	}
+	now := time.Now()
+	return mgr.WithLock(func() error {
+		state, err := mgr.Load()
+		if err != nil {
+			return err
+		}
+		for _, res := range results {
+			scan, ok := limited[res.Session]
+			if !res.Rotated || !ok || scan.AccountHandle == "" || scan.AccountHandle == res.NewAccount {
+				continue
+			}
+			if state.Accounts[scan.AccountHandle].Status == config.QuotaStatusCooldown {
+				continue // another session on the same account already did
+			}
+			mgr.StartCooldown(state, scan.AccountHandle, scan.ResetsAt, now, quota.DefaultCooldown)
+			if !quotaJSON {
+				fmt.Printf(" %s %s cooling down until %s\n", style.ArrowPrefix, scan.AccountHandle,
+					formatCooldownUntil(state.Accounts[scan.AccountHandle].CooldownUntil))
+			}
+		}
+		return mgr.SaveUnlocked(state)
+	})
+}
+
+// formatCooldownUntil renders an RFC3339 cooldown deadline in local time.
+func formatCooldownUntil(until string) string {
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return until
+	}
+	return t.Local().Format("Jan 2 15:04")
+}
+
 var quotaClearCmd = &cobra.Command{
 	Use:   "clear [handle...]",
 	Short: "Mark account(s) as available again",
@@ -775,11 +836,20 @@ func init() {
 	quotaRotateCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
 	quotaRotateCmd.Flags().StringVar(&rotateFrom, "from", "", "Preemptively rotate sessions using this account")
 	quotaRotateCmd.Flags().BoolVar(&rotateIdle, "idle", false, "Only rotate sessions at the idle prompt (skip busy agents)")
+	quotaRotateCmd.Flags().BoolVar(&rotateCooldown, "cooldown", false, "Put exhausted accounts in cooldown until their reset time")
+
+	quotaUsageCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
+
+	quotaBudgetCmd.Flags().BoolVar(&budgetRig, "rig", false, "Set the budget of a rig instead of an account")
+	quotaBudgetCmd.Flags().StringVar(&budgetDaily, "daily", "", "Daily token budget (e.g. 20M, 0 to remove)")
+	quotaBudgetCmd.Flags().StringVar(&budgetWeekly, "weekly", "", "Weekly token budget (e.g. 100M, 0 to remove)")
 
 	quotaCmd.AddCommand(quotaStatusCmd)
 	quotaCmd.AddCommand(quotaScanCmd)
 	quotaCmd.AddCommand(quotaRotateCmd)
 	quotaCmd.AddCommand(quotaClearCmd)
+	quotaCmd.AddCommand(quotaUsageCmd)
+	quotaCmd.AddCommand(quotaBudgetCmd)
 
 	rootCmd.AddCommand(quotaCmd)
 }
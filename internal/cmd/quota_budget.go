@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Budget command flags
+var (
+	budgetRig    bool
+	budgetDaily  string
+	budgetWeekly string
+)
+
+var quotaUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show token usage, budgets and exhaustion forecasts",
+	Long: `Show token consumption per account and per rig, measured from the same
+Claude Code transcripts gt costs reads.
+
+For every configured budget, shows usage this period (day or Monday-based
+week, local time), the burn rate over the last hour, and when the budget
+runs out at that rate. Cache reads are not counted against budgets.
+
+Examples:
+  gt quota usage           # Text output
+  gt quota usage --json    # JSON output`,
+	RunE: runQuotaUsage,
+}
+
+var quotaBudgetCmd = &cobra.Command{
+	Use:   "budget <account|rig>",
+	Short: "Set per-account or per-rig token budgets",
+	Long: `Set daily and weekly token budgets for an account or a rig.
+
+Budgets are stored in settings/config.json under "budgets". Work whose
+account or rig has used up its budget, or is projected to within the
+lookahead (default 1h) at the current burn rate, is not dispatched. With
+the capacity scheduler enabled (scheduler.max_polecats > 0), held work stays
+scheduled and dispatches once usage allows; with direct dispatch, gt sling
+refuses to spawn a polecat unless --force is given.
+
+Counts accept k and M suffixes. A count of 0 removes that cap. Without
+--daily or --weekly, shows the current budget.
+
+Examples:
+  gt quota budget work --daily 20M --weekly 100M
+  gt quota budget gastown --rig --daily 5M
+  gt quota budget work --weekly 0        # Remove the weekly cap`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuotaBudget,
+}
+
+// quotaUsageItem is one account or rig in `gt quota usage --json`.
+type quotaUsageItem struct {
+	Scope     string           `json:"scope"`
+	Name      string           `json:"name"`
+	Today     int64            `json:"today"`
+	ThisWeek  int64            `json:"this_week"`
+	Forecasts []quota.Forecast `json:"forecasts,omitempty"`
+}
+
+func runQuotaUsage(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	now := time.Now()
+	ledger, err := collectTownUsage(townRoot, now)
+	if err != nil {
+		return err
+	}
+	items := usageItems(ledger, settings.Budgets, now)
+
+	if quotaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println(style.Dim.Render("No token usage recorded this week"))
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Token Usage"))
+	lastScope := ""
+	for _, item := range items {
+		if item.Scope != lastScope {
+			if lastScope != "" {
+				fmt.Println()
+			}
+			fmt.Printf("%s\n", style.Bold.Render(strings.ToUpper(item.Scope[:1])+item.Scope[1:]+"s"))
+			lastScope = item.Scope
+		}
+		fmt.Printf("  %-20s today %8s   week %8s\n", item.Name, formatTokenCount(item.Today), formatTokenCount(item.ThisWeek))
+		for _, f := range item.Forecasts {
+			fmt.Printf("    %-7s %s\n", f.Period, describeForecast(f, now))
+		}
+	}
+	return nil
+}
+
+// usageItems groups a ledger into per-account and per-rig usage, including
+// every budgeted name even if it has no usage yet.
+func usageItems(ledger *quota.UsageLedger, budgets *config.BudgetConfig, now time.Time) []quotaUsageItem {
+	forecasts := ledger.Forecasts(budgets, now)
+	dayStart, _ := quota.PeriodBounds(quota.PeriodDaily, now)
+	weekStart, _ := quota.PeriodBounds(quota.PeriodWeekly, now)
+
+	var items []quotaUsageItem
+	add := func(scope string, samples map[string][]quota.UsageSample, limits map[string]*config.TokenBudget) {
+		names := slices.Collect(maps.Keys(samples))
+		for name := range limits {
+			if _, ok := samples[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			item := quotaUsageItem{Scope: scope, Name: name}
+			for _, s := range samples[name] {
+				if !s.Time.Before(dayStart) {
+					item.Today += s.Tokens
+				}
+				if !s.Time.Before(weekStart) {
+					item.ThisWeek += s.Tokens
+				}
+			}
+			for _, f := range forecasts {
+				if f.Scope == scope && f.Name == name {
+					item.Forecasts = append(item.Forecasts, f)
+				}
+			}
+			items = append(items, item)
+		}
+	}
+	var accountLimits, rigLimits map[string]*config.TokenBudget
+	if budgets != nil {
+		accountLimits, rigLimits = budgets.Accounts, budgets.Rigs
+	}
+	add("account", ledger.Accounts, accountLimits)
+	add("rig", ledger.Rigs, rigLimits)
+	return items
+}
+
+// describeForecast renders a forecast as "1.2M/5M (24%), 300k/h, lasts the day".
+func describeForecast(f quota.Forecast, now time.Time) string {
+	pct := float64(f.Used) / float64(f.Budget) * 100
+	s := fmt.Sprintf("%s/%s (%.0f%%), %s/h", formatTokenCount(f.Used), formatTokenCount(f.Budget), pct, formatTokenCount(int64(f.BurnPerHour)))
+	switch {
+	case f.Exceeded():
+		return s + ", " + style.Error.Render("exhausted") + " until " + f.ResetsAt.Format("Mon 15:04")
+	case !f.ExhaustsAt.IsZero():
+		return s + ", " + style.Warning.Render("runs out in "+formatDuration(f.ExhaustsAt.Sub(now)))
+	case f.Period == quota.PeriodWeekly:
+		return s + ", lasts the week"
+	default:
+		return s + ", lasts the day"
+	}
+}
+
+func runQuotaBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name := args[0]
+	scope := "account"
+	if budgetRig {
+		scope = "rig"
+		rigs, err := townRigNames(townRoot)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(rigs, name) {
+			return fmt.Errorf("rig %q not found", name)
+		}
+	} else {
+		acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+		if err != nil || acctCfg.GetAccount(name) == nil {
+			return fmt.Errorf("account %q not found (use --rig for a rig budget)", name)
+		}
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Budgets == nil {
+		settings.Budgets = &config.BudgetConfig{}
+	}
+	limits := &settings.Budgets.Accounts
+	if budgetRig {
+		limits = &settings.Budgets.Rigs
+	}
+
+	b := (*limits)[name]
+	if !cmd.Flags().Changed("daily") && !cmd.Flags().Changed("weekly") {
+		if b == nil {
+			fmt.Printf("No budget for %s %s\n", scope, name)
+			return nil
+		}
+		fmt.Printf("%s %s: daily %s, weekly %s\n", scope, style.Bold.Render(name), formatBudgetLimit(b.DailyTokens), formatBudgetLimit(b.WeeklyTokens))
+		return nil
+	}
+
+	if b == nil {
+		b = &config.TokenBudget{}
+	}
+	if cmd.Flags().Changed("daily") {
+		if b.DailyTokens, err = parseTokenCount(budgetDaily); err != nil {
+			return fmt.Errorf("invalid --daily: %w", err)
+		}
+	}
+	if cmd.Flags().Changed("weekly") {
+		if b.WeeklyTokens, err = parseTokenCount(budgetWeekly); err != nil {
+			return fmt.Errorf("invalid --weekly: %w", err)
+		}
+	}
+	if *limits == nil {
+		*limits = make(map[string]*config.TokenBudget)
+	}
+	if b.DailyTokens == 0 && b.WeeklyTokens == 0 {
+		delete(*limits, name)
+	} else {
+		(*limits)[name] = b
+	}
+
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Budget for %s %s: daily %s, weekly %s\n", style.Success.Render("✓"), scope, name,
+		formatBudgetLimit(b.DailyTokens), formatBudgetLimit(b.WeeklyTokens))
+	return nil
+}
+
+// holdOverBudget drops pending beads whose account or rig is out of token
+// budget, or projected to run out within the lookahead. Held beads stay
+// scheduled for a later cycle. If usage can't be read, beads whose account
+// or rig has a budget are held (budgets are hard caps); the rest dispatch.
+func holdOverBudget(townRoot string, budgets *config.BudgetConfig, pending []capacity.PendingBead) []capacity.PendingBead {
+	ready, held := splitOverBudget(townRoot, budgets, pending)
+	for _, b := range pending {
+		if reason, ok := held[b.WorkBeadID]; ok {
+			fmt.Printf("%s Holding %s: %s\n", style.Dim.Render("⏸"), b.WorkBeadID, reason)
+		}
+	}
+	return ready
+}
+
+// splitOverBudget is holdOverBudget without output: it returns the beads
+// within budget and, keyed by work bead ID, why each other bead is held.
+func splitOverBudget(townRoot string, budgets *config.BudgetConfig, pending []capacity.PendingBead) ([]capacity.PendingBead, map[string]string) {
+	held := make(map[string]string)
+	if budgets == nil || len(pending) == 0 || (len(budgets.Accounts) == 0 && len(budgets.Rigs) == 0) {
+		return pending, held
+	}
+	now := time.Now()
+	ledger, usageErr := collectTownUsage(townRoot, now)
+	defaultAccount := ""
+	if acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot)); err == nil {
+		defaultAccount = acctCfg.Default
+	}
+
+	var ready []capacity.PendingBead
+	for _, b := range pending {
+		account := defaultAccount
+		if b.Context != nil && b.Context.Account != "" {
+			account = b.Context.Account
+		}
+		if usageErr != nil {
+			if budgets.Accounts[account] != nil || budgets.Rigs[b.TargetRig] != nil {
+				held[b.WorkBeadID] = fmt.Sprintf("token usage unavailable: %v", usageErr)
+				continue
+			}
+		} else if err := ledger.CheckDispatch(budgets, account, b.TargetRig, now); err != nil {
+			held[b.WorkBeadID] = err.Error()
+			continue
+		}
+		ready = append(ready, b)
+	}
+	return ready, held
+}
+
+// checkSlingBudget refuses a direct dispatch of beadID to rigName when the
+// account or rig is over budget, applying the same check the scheduler runs
+// on pending beads. An empty account means the default account.
+func checkSlingBudget(townRoot, beadID, rigName, account string) error {
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	bead := capacity.PendingBead{
+		WorkBeadID: beadID,
+		TargetRig:  rigName,
+		Context:    &capacity.SlingContextFields{Account: account},
+	}
+	if _, held := splitOverBudget(townRoot, settings.Budgets, []capacity.PendingBead{bead}); held[beadID] != "" {
+		return fmt.Errorf("over token budget: %s (use --force to override)", held[beadID])
+	}
+	return nil
+}
+
+// collectTownUsage reads the token usage needed to forecast budgets at now.
+func collectTownUsage(townRoot string, now time.Time) (*quota.UsageLedger, error) {
+	acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil {
+		acctCfg = nil // no accounts registered: rig usage only
+	}
+	rigs, err := townRigNames(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := quota.CollectUsage(townRoot, acctCfg, rigs, quota.UsageSince(now))
+	if err != nil {
+		return nil, fmt.Errorf("reading token usage: %w", err)
+	}
+	for _, err := range ledger.Unreadable {
+		style.PrintWarning("skipping transcript: %v", err)
+	}
+	return ledger, nil
+}
+
+// townRigNames returns the names of the rigs registered in the town.
+func townRigNames(townRoot string) ([]string, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	return slices.Sorted(maps.Keys(rigsConfig.Rigs)), nil
+}
+
+// parseTokenCount parses a token count like "2500000", "500k" or "20M".
+func parseTokenCount(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult, s = 1_000, s[:len(s)-1]
+	case strings.HasSuffix(s, "m"), strings.HasSuffix(s, "M"):
+		mult, s = 1_000_000, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("expected a token count like 500k or 20M, got %q", value)
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// formatTokenCount renders a token count compactly: 950, 12.5k, 3.2M.
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(n)/1_000_000, 'f', 1, 64), ".0") + "M"
+	case n >= 1_000:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(n)/1_000, 'f', 1, 64), ".0") + "k"
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+func formatBudgetLimit(n int64) string {
+	if n == 0 {
+		return "none"
+	}
+	return formatTokenCount(n)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestParseTokenCount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"2500000", 2500000, false},
+		{"500k", 500000, false},
+		{"20M", 20000000, false},
+		{"1.5m", 1500000, false},
+		{"0", 0, false},
+		{"", 0, true},
+		{"-5k", 0, true},
+		{"lots", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTokenCount(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseTokenCount(%q) = %d, %v; want %d, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	tests := map[int64]string{950: "950", 12500: "12.5k", 3000: "3k", 3_240_000: "3.2M", 20_000_000: "20M"}
+	for in, want := range tests {
+		if got := formatTokenCount(in); got != want {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCheckSlingBudget_UsageUnavailable(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Budgets = &config.BudgetConfig{
+		Rigs: map[string]*config.TokenBudget{"gastown": {DailyTokens: 1_000_000}},
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	// A corrupt rigs.json makes usage unreadable.
+	if err := os.MkdirAll(filepath.Join(townRoot, constants.DirMayor), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(constants.MayorRigsPath(townRoot), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkSlingBudget(townRoot, "gt-abc", "gastown", ""); err == nil {
+		t.Error("budgeted rig: expected dispatch to be refused when usage is unavailable")
+	}
+	if err := checkSlingBudget(townRoot, "gt-abc", "beads", ""); err != nil {
+		t.Errorf("unbudgeted rig: unexpected error: %v", err)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -17,11 +19,12 @@ import (
 )
 
 var (
-	schedulerStatusJSON bool
-	schedulerListJSON   bool
-	schedulerClearBead  string
-	schedulerRunBatch   int
-	schedulerRunDryRun  bool
+	schedulerStatusJSON    bool
+	schedulerStatusExplain bool
+	schedulerListJSON      bool
+	schedulerClearBead     string
+	schedulerRunBatch      int
+	schedulerRunDryRun     bool
 )
 
 var schedulerCmd = &cobra.Command{
@@ -40,14 +43,29 @@ Subcommands:
 
 Config:
   gt config set scheduler.max_polecats 5    # Enable deferred dispatch
-  gt config set scheduler.max_polecats -1   # Direct dispatch (default)`,
+  gt config set scheduler.max_polecats -1   # Direct dispatch (default)
+  gt config set scheduler.policies priority,deadline,convoy-fair,aging
+  gt config set scheduler.rig_quota.gastown 3
+  gt quota budget work --daily 20M          # Hold work that would exceed a token budget`,
 	RunE: requireSubcommand,
 }
 
 var schedulerStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show scheduler state: pending, capacity, active polecats",
-	RunE:  runSchedulerStatus,
+	Long: `Show scheduler state: pending, capacity, active polecats.
+
+With --explain, also show the order the next dispatch cycle would use and
+why: each ready bead's rank with the policy reasons behind it (priority,
+due date, aging, convoy round), and the beads held back by rig quotas or
+token budgets.
+
+Policies are set with gt config set scheduler.policies:
+  priority     Higher-priority work beads first (P0 before P4)
+  deadline     Overdue and soon-due beads first (scheduler.due_soon, default 24h)
+  convoy-fair  Round-robin across convoys so one convoy can't starve others
+  aging        Waiting beads gain a priority level per scheduler.aging_interval (default 4h)`,
+	RunE: runSchedulerStatus,
 }
 
 var schedulerListCmd = &cobra.Command{
@@ -95,6 +113,7 @@ but can be run ad-hoc. Useful for testing or when the daemon is not running.
 func init() {
 	// Status flags
 	schedulerStatusCmd.Flags().BoolVar(&schedulerStatusJSON, "json", false, "Output as JSON")
+	schedulerStatusCmd.Flags().BoolVar(&schedulerStatusExplain, "explain", false, "Explain the next dispatch order and held beads")
 
 	// List flags
 	schedulerListCmd.Flags().BoolVar(&schedulerListJSON, "json", false, "Output as JSON")
@@ -144,15 +163,24 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 
 	activePolecats := countActivePolecats()
 
+	var explain *dispatchExplanation
+	if schedulerStatusExplain {
+		explain, err = explainDispatch(townRoot)
+		if err != nil {
+			return err
+		}
+	}
+
 	if schedulerStatusJSON {
 		out := struct {
-			Paused         bool               `json:"paused"`
-			PausedBy       string             `json:"paused_by,omitempty"`
-			ScheduledTotal int                `json:"queued_total"`
-			ScheduledReady int                `json:"queued_ready"`
-			ActivePolecats int                `json:"active_polecats"`
-			LastDispatchAt string             `json:"last_dispatch_at,omitempty"`
-			Beads          []scheduledBeadInfo `json:"beads"`
+			Paused         bool                 `json:"paused"`
+			PausedBy       string               `json:"paused_by,omitempty"`
+			ScheduledTotal int                  `json:"queued_total"`
+			ScheduledReady int                  `json:"queued_ready"`
+			ActivePolecats int                  `json:"active_polecats"`
+			LastDispatchAt string               `json:"last_dispatch_at,omitempty"`
+			Beads          []scheduledBeadInfo  `json:"beads"`
+			Explain        *dispatchExplanation `json:"explain,omitempty"`
 		}{
 			Paused:         state.Paused,
 			PausedBy:       state.PausedBy,
@@ -160,6 +188,7 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 			ActivePolecats: activePolecats,
 			LastDispatchAt: state.LastDispatchAt,
 			Beads:          scheduled,
+			Explain:        explain,
 		}
 		for _, b := range scheduled {
 			if !b.Blocked {
@@ -190,9 +219,102 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Last dispatch: %s (%d beads)\n", state.LastDispatchAt, state.LastDispatchCount)
 	}
 
+	if explain != nil {
+		printDispatchExplanation(explain)
+	}
+
 	return nil
 }
 
+// dispatchExplanation is the order the next dispatch cycle would use.
+type dispatchExplanation struct {
+	Policies  []string            `json:"policies,omitempty"`
+	Capacity  int                 `json:"capacity"` // free polecat slots
+	BatchSize int                 `json:"batch_size"`
+	Decisions []capacity.Decision `json:"decisions"`
+}
+
+// explainDispatch ranks the ready beads the way dispatchScheduledWork
+// would, including beads held back by token budgets, without dispatching.
+func explainDispatch(townRoot string) (*dispatchExplanation, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	schedulerCfg := settings.Scheduler
+	if schedulerCfg == nil {
+		schedulerCfg = capacity.DefaultSchedulerConfig()
+	}
+
+	pending, err := getReadySlingContexts(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ready, overBudget := splitOverBudget(townRoot, settings.Budgets, pending)
+
+	activeByRig := countActivePolecatsByRig()
+	_, decisions := capacity.Schedule(schedulerCfg, ready, activeByRig, time.Now())
+	for _, b := range pending {
+		if reason, ok := overBudget[b.WorkBeadID]; ok {
+			decisions = append(decisions, capacity.Decision{
+				WorkBeadID:        b.WorkBeadID,
+				TargetRig:         b.TargetRig,
+				Priority:          b.Priority,
+				EffectivePriority: b.Priority,
+				Held:              "token budget: " + reason,
+			})
+		}
+	}
+
+	active := 0
+	for _, n := range activeByRig {
+		active += n
+	}
+	return &dispatchExplanation{
+		Policies:  schedulerCfg.Policies,
+		Capacity:  max(0, schedulerCfg.GetMaxPolecats()-active),
+		BatchSize: schedulerCfg.GetBatchSize(),
+		Decisions: decisions,
+	}, nil
+}
+
+// printDispatchExplanation prints the ranked dispatch order and held beads.
+func printDispatchExplanation(e *dispatchExplanation) {
+	policies := "fifo (enqueue order)"
+	if len(e.Policies) > 0 {
+		policies = strings.Join(e.Policies, ", ")
+	}
+	fmt.Printf("  Policies:  %s\n", policies)
+
+	next := min(e.Capacity, e.BatchSize)
+	fmt.Printf("\n%s (%d free slot(s), batch %d)\n", style.Bold.Render("Dispatch order"), e.Capacity, e.BatchSize)
+	var held []capacity.Decision
+	ranked := 0
+	for _, d := range e.Decisions {
+		if d.Held != "" {
+			held = append(held, d)
+			continue
+		}
+		ranked++
+		marker := ""
+		if d.Rank <= next {
+			marker = style.Success.Render("  ← next")
+		}
+		fmt.Printf("  %3d. %s → %s  %s%s\n", d.Rank, d.WorkBeadID, d.TargetRig,
+			style.Dim.Render(strings.Join(d.Reasons, " · ")), marker)
+	}
+	if ranked == 0 {
+		fmt.Println("  (no ready beads)")
+	}
+
+	if len(held) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Held"))
+		for _, d := range held {
+			fmt.Printf("  %s → %s  %s\n", d.WorkBeadID, d.TargetRig, style.Warning.Render(d.Held))
+		}
+	}
+}
+
 func runSchedulerList(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -483,13 +605,22 @@ func beadsSearchDirs(townRoot string) []string {
 
 // countActivePolecats counts all running polecats across all rigs in the town.
 func countActivePolecats() int {
+	count := 0
+	for _, n := range countActivePolecatsByRig() {
+		count += n
+	}
+	return count
+}
+
+// countActivePolecatsByRig counts running polecats per rig.
+func countActivePolecatsByRig() map[string]int {
+	counts := make(map[string]int)
 	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
 	out, err := listCmd.Output()
 	if err != nil {
-		return 0
+		return counts
 	}
 
-	count := 0
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		if line == "" {
 			continue
@@ -499,8 +630,8 @@ func countActivePolecats() int {
 			continue
 		}
 		if identity.Role == session.RolePolecat {
-			count++
+			counts[identity.Rig]++
 		}
 	}
-	return count
+	return counts
 }
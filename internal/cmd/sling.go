@@ -611,12 +611,23 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	// Per-bead model routing applies only when a polecat is spawned; an
+	// existing polecat keeps the agent it runs with.
+	agent, routeReason := slingAgent, ""
+	if slingSpawnsPolecat(target) {
+		routeFormula := formulaName
+		if routeFormula == "" && !slingHookRawBead {
+			routeFormula = resolveFormula(slingFormula, false) // auto-applied below for polecats
+		}
+		agent, routeReason = routeSlingAgent(townRoot, routeFormula, info, slingAgent)
+		printModelRoute(agent, routeReason)
+	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
 		Create:     slingCreate,
 		Account:    slingAccount,
-		Agent:      slingAgent,
+		Agent:      agent,
 		NoBoot:     slingNoBoot,
 		HookBead:   beadID,
 		BeadID:     beadID,
@@ -872,6 +883,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		AttachedFormula:  formulaName,
 		NoMerge:          slingNoMerge,
 	}
+	if newPolecatInfo != nil {
+		fieldUpdates.DispatchedAgent = dispatchedAgentName(townRoot, newPolecatInfo.RigName, agent)
+	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
@@ -212,12 +212,23 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		}
 	}
 
-	// 3. Spawn polecat (via spawnPolecatForSling)
+	// Token budgets: the scheduler already held over-budget beads before
+	// planning, so only direct dispatch checks here.
+	if !explicitForce && params.CallerContext != "scheduler-dispatch" {
+		if err := checkSlingBudget(townRoot, params.BeadID, params.RigName, params.Account); err != nil {
+			result.ErrMsg = "over token budget"
+			return result, err
+		}
+	}
+
+	// 3. Spawn polecat (via spawnPolecatForSling), routing the model per bead
+	agent, routeReason := routeSlingAgent(townRoot, params.FormulaName, info, params.Agent)
+	printModelRoute(agent, routeReason)
 	spawnOpts := SlingSpawnOptions{
 		Force:      params.Force,
 		Account:    params.Account,
 		HookBead:   params.BeadID,
-		Agent:      params.Agent,
+		Agent:      agent,
 		BaseBranch: params.BaseBranch,
 		// Create is always true for rig targets: executeSling only handles
 		// rig-targeted dispatch (batch sling + queue dispatch), where a fresh
@@ -321,6 +332,7 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 	// 10. Store fields in bead (dispatcher, args, attached_molecule, no_merge, mode)
 	fieldUpdates := beadFieldUpdates{
 		Dispatcher:       actor,
+		DispatchedAgent:  dispatchedAgentName(townRoot, params.RigName, agent),
 		Args:             params.Args,
 		AttachedMolecule: attachedMoleculeID,
 		AttachedFormula:  params.FormulaName,
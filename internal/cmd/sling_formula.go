@@ -133,9 +133,20 @@ func runSlingFormula(args []string) error {
 		formulaWorkDir = townRoot
 	}
 
+	// Composed and control-flow formulas are handed to bd already resolved,
+	// since bd cooks formulas as written.
+	cookName, cookCleanup, err := resolveCookFormula(formulaName, formulaWorkDir)
+	if err != nil {
+		rollbackSpawned("")
+		return err
+	}
+	if cookCleanup != nil {
+		defer cookCleanup()
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	if err := BdCmd("cook", formulaName).
+	if err := BdCmd("cook", cookName).
 		Dir(formulaWorkDir).
 		WithGTRoot(townRoot).
 		Run(); err != nil {
@@ -145,7 +156,7 @@ func runSlingFormula(args []string) error {
 
 	// Step 2: Create wisp instance (ephemeral)
 	fmt.Printf("  Creating wisp...\n")
-	wispArgs := []string{"mol", "wisp", formulaName}
+	wispArgs := []string{"mol", "wisp", cookName}
 	for _, v := range slingVars {
 		wispArgs = append(wispArgs, "--var", v)
 	}
@@ -204,6 +215,12 @@ func runSlingFormula(args []string) error {
 		fmt.Printf("%s Args stored in bead (durable)\n", style.Bold.Render("✓"))
 	}
 
+	// Formulas with when/for_each/retry/timeout steps get a run state that
+	// gt mol step done/fail advance.
+	if err := recordMoleculeRun(townRoot, wispRootID, formulaName, slingVars); err != nil {
+		style.PrintWarning("could not record formula run: %v", err)
+	}
+
 	// Start delayed dog session now that hook is set
 	// This ensures dog sees the hook when gt prime runs on session start
 	if delayedDogInfo != nil {
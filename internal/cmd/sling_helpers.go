@@ -82,6 +82,7 @@ type beadInfo struct {
 	Labels       []string         `json:"labels,omitempty"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 	IssueType    string           `json:"issue_type,omitempty"`
+	EstimatedMin int              `json:"estimated_minutes,omitempty"`
 }
 
 // isDeferredBead checks whether a bead should be rejected from slinging because
@@ -244,6 +245,7 @@ func getBeadInfo(beadID string) (*beadInfo, error) {
 // eliminating the race condition where concurrent writers could overwrite each other's fields.
 type beadFieldUpdates struct {
 	Dispatcher       string // Agent that dispatched the work
+	DispatchedAgent  string // Agent preset the polecat runs with
 	Args             string // Natural language instructions
 	AttachedMolecule string // Wisp root ID
 	AttachedFormula  string // Formula name (e.g., "mol-polecat-work") for inline step display
@@ -295,6 +297,9 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.Dispatcher != "" {
 		fields.DispatchedBy = updates.Dispatcher
 	}
+	if updates.DispatchedAgent != "" {
+		fields.DispatchedAgent = updates.DispatchedAgent
+	}
 	if updates.Args != "" {
 		fields.AttachedArgs = updates.Args
 	}
@@ -713,22 +718,35 @@ type FormulaOnBeadResult struct {
 //   - extraVars: additional --var values supplied by the user
 //
 // Returns the wisp root ID which should be hooked.
-func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot string, skipCook bool, extraVars []string) (_ *FormulaOnBeadResult, retErr error) {
+func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot string, skipCook bool, extraVars []string) (result *FormulaOnBeadResult, retErr error) {
 	defer func() { telemetry.RecordFormulaInstantiate(context.Background(), formulaName, beadID, retErr) }()
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	// Composed and control-flow formulas are handed to bd already resolved,
+	// since bd cooks formulas as written.
+	resolvedFormula, formulaCleanup, err := resolveCookFormula(formulaName, formulaWorkDir)
+	if err != nil {
+		return nil, err
+	}
+	if formulaCleanup != nil {
+		defer formulaCleanup()
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	// If cook fails, retry with the embedded formula extracted to a temp file.
 	// This handles non-gastown rigs that don't have formulas provisioned on disk.
 	// See gt-oir.
-	resolvedFormula := formulaName
-	var formulaCleanup func()
 	if !skipCook {
-		if err := BdCmd("cook", formulaName).
+		if err := BdCmd("cook", resolvedFormula).
 			Dir(formulaWorkDir).
 			WithGTRoot(townRoot).
-				Run(); err != nil {
+			Run(); err != nil {
+			// A resolved formula is already a complete file; there is no
+			// embedded copy that would fare better.
+			if resolvedFormula != formulaName {
+				return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
+			}
 			// Retry with embedded formula
 			resolvedFormula, formulaCleanup = resolveFormulaToTempFile(formulaName)
 			if formulaCleanup != nil {
@@ -755,6 +773,17 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	formulaVars = append(formulaVars, extraVars...)
 	formulaVars = ensureFormulaRequiredVars(formulaName, formulaVars)
 
+	// Formulas with when/for_each/retry/timeout steps get a run state keyed
+	// by the wisp root, used for the prime checklist and step advancement.
+	defer func() {
+		if retErr != nil || result == nil {
+			return
+		}
+		if err := recordMoleculeRun(townRoot, result.WispRootID, formulaName, formulaVars); err != nil {
+			style.PrintWarning("could not record formula run: %v", err)
+		}
+	}()
+
 	// Step 2: Create wisp with feature and issue variables from bead.
 	// Use resolvedFormula which may be a temp file path if the embedded fallback was used.
 	// Root-only: don't materialize child step wisps — agents read inline steps from embedded formula.
@@ -939,12 +968,19 @@ func ensureFormulaRequiredVars(formulaName string, vars []string) []string {
 // townRoot is required for GT_ROOT so bd can find town-level formulas.
 // Falls back to embedded formula extraction if bd can't find the formula on disk.
 func CookFormula(formulaName, workDir, townRoot string) error {
-	err := BdCmd("cook", formulaName).
+	composed, composedCleanup, err := resolveCookFormula(formulaName, workDir)
+	if err != nil {
+		return err
+	}
+	if composedCleanup != nil {
+		defer composedCleanup()
+	}
+	err = BdCmd("cook", composed).
 		Dir(workDir).
 		WithGTRoot(townRoot).
 		Run()
-	if err == nil {
-		return nil
+	if err == nil || composed != formulaName {
+		return err
 	}
 	// Retry with embedded formula extracted to temp file
 	resolved, cleanup := resolveFormulaToTempFile(formulaName)
@@ -983,6 +1019,54 @@ func resolveFormulaToTempFile(formulaName string) (resolved string, cleanup func
 	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }
 }
 
+// resolveCookFormula returns the formula to hand to bd cook, which cooks
+// formulas as written. A formula that uses extends, include, compose or call
+// steps is written to a temp file with its composition applied, and one with
+// control flow has each step tagged with its formula step ID (see
+// tagFormulaSteps) so gt mol step done/fail can map step beads back to
+// steps. Formulas gt can't find, or that need neither, are returned by name.
+func resolveCookFormula(formulaName, workDir string) (resolved string, cleanup func(), err error) {
+	path := filepath.Join(workDir, ".beads", "formulas", formulaName+".formula.toml")
+	if _, statErr := os.Stat(path); statErr != nil {
+		path, _ = findFormulaFile(formulaName)
+	}
+	var f *formula.Formula
+	if path != "" {
+		f, err = formula.ParseFile(path)
+	} else {
+		content, embedErr := formula.GetEmbeddedFormulaContent(formulaName)
+		if embedErr != nil {
+			return formulaName, nil, nil
+		}
+		f, err = formula.Parse(content)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("formula %s: %w", formulaName, err)
+	}
+	if !f.Composed() && !f.HasControlFlow() {
+		return formulaName, nil, nil
+	}
+	if f.HasControlFlow() {
+		tagFormulaSteps(f)
+	}
+
+	data, err := f.Flat()
+	if err != nil {
+		return "", nil, err
+	}
+	tmpFile, err := os.CreateTemp("", "gt-formula-*.formula.toml")
+	if err != nil {
+		return "", nil, fmt.Errorf("writing resolved formula: %w", err)
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return "", nil, fmt.Errorf("writing resolved formula: %w", err)
+	}
+	tmpFile.Close()
+	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }, nil
+}
+
 // isHookedAgentDeadFn is a seam for tests. Production uses isHookedAgentDead.
 var isHookedAgentDeadFn = isHookedAgentDead
 
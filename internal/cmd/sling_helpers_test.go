@@ -166,3 +166,66 @@ func TestIsSlingConfigError(t *testing.T) {
 		})
 	}
 }
+
+// TestResolveCookFormula verifies composed formulas reach bd flattened and
+// control-flow formulas with step IDs tagged, while plain formulas are still
+// passed by name.
+func TestResolveCookFormula(t *testing.T) {
+	workDir := t.TempDir()
+	formulasDir := filepath.Join(workDir, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	composed := `formula = "rig-ship"
+extends = ["shiny"]
+
+[[steps]]
+id = "announce"
+title = "Announce {{feature}}"
+needs = ["submit"]
+`
+	if err := os.WriteFile(filepath.Join(formulasDir, "rig-ship.formula.toml"), []byte(composed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, cleanup, err := resolveCookFormula("rig-ship", workDir)
+	if err != nil {
+		t.Fatalf("resolveCookFormula() error: %v", err)
+	}
+	if cleanup == nil || resolved == "rig-ship" {
+		t.Fatalf("composed formula should be written to a temp file, got %q", resolved)
+	}
+	defer cleanup()
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`id = "design"`, `id = "announce"`, `formula = "rig-ship"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("flattened formula missing %s:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "extends") {
+		t.Errorf("flattened formula still extends:\n%s", data)
+	}
+
+	if err := os.WriteFile(filepath.Join(formulasDir, "release.formula.toml"), []byte(testFlowFormula), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resolved, cleanup, err = resolveCookFormula("release", workDir)
+	if err != nil || cleanup == nil {
+		t.Fatalf("control-flow formula = (%q, cleanup %v, %v), want temp file", resolved, cleanup != nil, err)
+	}
+	defer cleanup()
+	data, err = os.ReadFile(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "formula_step: deploy") {
+		t.Errorf("control-flow formula steps not tagged:\n%s", data)
+	}
+
+	if resolved, cleanup, err := resolveCookFormula("mol-polecat-work", workDir); err != nil || cleanup != nil || resolved != "mol-polecat-work" {
+		t.Errorf("plain formula = (%q, cleanup %v, %v), want passed by name", resolved, cleanup != nil, err)
+	}
+}
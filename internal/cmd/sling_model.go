@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// routeSlingAgent picks the agent for a polecat spawned to work a bead.
+// An explicit --agent always wins. Otherwise the town's model_routing rules
+// match on the bead's labels, type, estimate and formula, and a bead whose
+// last attempt failed (the witness records failed_agent when it resets an
+// abandoned bead) escalates past the agent that failed. Re-slinging for any
+// other reason keeps the routed agent. Returns the agent override
+// to spawn with ("" = the polecat role's normal agent) and why it was chosen.
+func routeSlingAgent(townRoot, formula string, info *beadInfo, explicit string) (agent, reason string) {
+	if explicit != "" || info == nil {
+		return explicit, ""
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.ModelRouting == nil {
+		return "", ""
+	}
+	if err := config.ValidateModelRouting(settings.ModelRouting); err != nil {
+		fmt.Printf("%s Ignoring model_routing: %v\n", style.Dim.Render("Warning:"), err)
+		return "", ""
+	}
+
+	failed := ""
+	if fields := beads.ParseAttachmentFields(&beads.Issue{Description: info.Description}); fields != nil {
+		failed = fields.FailedAgent
+	}
+	return settings.ModelRouting.RouteModel(config.BeadTraits{
+		Labels:           info.Labels,
+		Type:             info.IssueType,
+		Formula:          formula,
+		EstimatedMinutes: info.EstimatedMin,
+	}, failed)
+}
+
+// slingSpawnsPolecat reports whether slinging to target spawns a new
+// polecat: a rig always does, and a polecat only when its session is gone
+// and resolveTarget replaces it. An existing polecat keeps its agent.
+func slingSpawnsPolecat(target string) bool {
+	if _, isRig := IsRigName(target); isRig {
+		return true
+	}
+	if !isPolecatTarget(target) {
+		return false
+	}
+	_, _, _, err := resolveTargetAgentFn(target)
+	return err != nil
+}
+
+// dispatchedAgentName returns the agent a new polecat in rigName runs with,
+// recorded on the bead so a failed attempt can be escalated from.
+func dispatchedAgentName(townRoot, rigName, override string) string {
+	if override != "" {
+		return override
+	}
+	name, _ := config.ResolveRoleAgentName("polecat", townRoot, filepath.Join(townRoot, rigName))
+	return name
+}
+
+// printModelRoute reports a routed agent choice in sling output.
+func printModelRoute(agent, reason string) {
+	if reason != "" {
+		fmt.Printf("  %s Model: %s (%s)\n", style.Bold.Render("→"), agent, reason)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRouteSlingAgent(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.ModelRouting = &config.ModelRoutingConfig{
+		Rules:      []config.ModelRoute{{Name: "chores", Labels: []string{"lint"}, Agent: "claude-haiku"}},
+		Escalation: []string{"claude-haiku", "claude-sonnet", "claude"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		info      *beadInfo
+		explicit  string
+		wantAgent string
+	}{
+		{"rule match", &beadInfo{Labels: []string{"lint"}, Status: "open"}, "", "claude-haiku"},
+		{"explicit agent wins", &beadInfo{Labels: []string{"lint"}}, "codex", "codex"},
+		{"no match uses default", &beadInfo{IssueType: "bug"}, "", ""},
+		{"re-sling without failure keeps rule", &beadInfo{
+			Labels:      []string{"lint"},
+			Description: "Fix lint\n\ndispatched_by: mayor/\ndispatched_agent: claude-haiku",
+		}, "", "claude-haiku"},
+		{"failed attempt escalates", &beadInfo{
+			Labels:      []string{"lint"},
+			Description: "Fix lint\n\ndispatched_by: mayor/\ndispatched_agent: claude-haiku\nfailed_agent: claude-haiku",
+		}, "", "claude-sonnet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, _ := routeSlingAgent(townRoot, "mol-polecat-work", tt.info, tt.explicit)
+			if agent != tt.wantAgent {
+				t.Errorf("routeSlingAgent() agent = %q, want %q", agent, tt.wantAgent)
+			}
+		})
+	}
+}
+
+func TestRouteSlingAgent_NoRouting(t *testing.T) {
+	if agent, reason := routeSlingAgent(t.TempDir(), "", &beadInfo{Labels: []string{"lint"}}, ""); agent != "" || reason != "" {
+		t.Errorf("routeSlingAgent() without config = (%q, %q), want empty", agent, reason)
+	}
+}
+
+func TestSlingSpawnsPolecat(t *testing.T) {
+	prevFn := resolveTargetAgentFn
+	t.Cleanup(func() { resolveTargetAgentFn = prevFn })
+
+	alive := map[string]bool{"gastown/polecats/nux": true}
+	resolveTargetAgentFn = func(target string) (string, string, string, error) {
+		if alive[target] {
+			return target, "%1", t.TempDir(), nil
+		}
+		return "", "", "", errors.New("no session")
+	}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"gastown/polecats/nux", false},
+		{"gastown/polecats/furiosa", true},
+		{"gastown/crew/max", false},
+	}
+	for _, tt := range tests {
+		if got := slingSpawnsPolecat(tt.target); got != tt.want {
+			t.Errorf("slingSpawnsPolecat(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
@@ -200,6 +200,11 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 				return nil, err
 			}
 		}
+		if !opts.Force {
+			if err := checkSlingBudget(townRoot, opts.BeadID, rigName, opts.Account); err != nil {
+				return nil, err
+			}
+		}
 		if opts.DryRun {
 			fmt.Printf("Would spawn fresh polecat in rig '%s'\n", rigName)
 			result.Agent = fmt.Sprintf("%s/polecats/<new>", rigName)
@@ -243,6 +248,11 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 						return nil, err
 					}
 				}
+				if !opts.Force {
+					if err := checkSlingBudget(opts.TownRoot, opts.BeadID, rigName, opts.Account); err != nil {
+						return nil, err
+					}
+				}
 				fmt.Printf("Target polecat has no active session, spawning fresh polecat in rig '%s'...\n", rigName)
 				spawnOpts := SlingSpawnOptions{
 					Force:      opts.Force,
@@ -19,10 +19,11 @@ import (
 
 // Synthesis command flags
 var (
-	synthesisRig      string
-	synthesisDryRun   bool
-	synthesisForce    bool
-	synthesisReviewID string
+	synthesisRig       string
+	synthesisDryRun    bool
+	synthesisForce     bool
+	synthesisReviewID  string
+	synthesisSubscribe []string
 )
 
 var synthesisCmd = &cobra.Command{
@@ -62,7 +63,9 @@ Options:
   --rig=NAME      Target rig for synthesis polecat (default: current)
   --review-id=ID  Override review ID for output paths
   --force         Start synthesis even if some legs incomplete
-  --dry-run       Show what would happen without executing`,
+  --dry-run       Show what would happen without executing
+  --subscribe=SUB Add a convoy subscription (repeatable), e.g.
+                  "mail:mayor/ on synthesis" or "escalate:medium"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSynthesisStart,
 }
@@ -86,7 +89,8 @@ var synthesisCloseCmd = &cobra.Command{
 	Short: "Close convoy after synthesis",
 	Long: `Close a convoy after synthesis is complete.
 
-This marks the convoy as complete and triggers any configured notifications.`,
+This marks the convoy as complete and notifies the convoy's owner, notify
+address, and synthesis subscribers with a summary of leg outputs.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSynthesisClose,
 }
@@ -97,6 +101,7 @@ func init() {
 	synthesisStartCmd.Flags().BoolVar(&synthesisDryRun, "dry-run", false, "Preview execution")
 	synthesisStartCmd.Flags().BoolVar(&synthesisForce, "force", false, "Start even if legs incomplete")
 	synthesisStartCmd.Flags().StringVar(&synthesisReviewID, "review-id", "", "Override review ID")
+	synthesisStartCmd.Flags().StringArrayVar(&synthesisSubscribe, "subscribe", nil, "Subscribe to convoy events: <mail|nudge|escalate>:<target> [on <events>] (repeatable)")
 
 	// Add subcommands
 	synthesisCmd.AddCommand(synthesisStartCmd)
@@ -131,6 +136,11 @@ type ConvoyMeta struct {
 func runSynthesisStart(cmd *cobra.Command, args []string) error {
 	convoyID := args[0]
 
+	subscriptions, err := parseSubscriptionFlags(synthesisSubscribe)
+	if err != nil {
+		return fmt.Errorf("invalid --subscribe: %w", err)
+	}
+
 	// Get convoy metadata
 	meta, err := getConvoyMeta(convoyID)
 	if err != nil {
@@ -218,9 +228,26 @@ func runSynthesisStart(cmd *cobra.Command, args []string) error {
 		if f != nil && f.Synthesis != nil {
 			fmt.Printf("  Synthesis: %s\n", f.Synthesis.Title)
 		}
+		for _, sub := range subscriptions {
+			fmt.Printf("  Subscribe: %s\n", sub.String())
+		}
 		return nil
 	}
 
+	// Store subscriptions on the convoy so synthesis close can fire them
+	if len(subscriptions) > 0 {
+		townBeads, err := getTownBeadsDir()
+		if err != nil {
+			return err
+		}
+		if err := addConvoySubscriptions(townBeads, convoyID, subscriptions); err != nil {
+			return fmt.Errorf("storing subscriptions: %w", err)
+		}
+		for _, sub := range subscriptions {
+			fmt.Printf("  Subscribed: %s\n", sub.String())
+		}
+	}
+
 	// Create synthesis bead
 	synthesisID, err := createSynthesisBead(convoyID, meta, f, legOutputs, reviewID)
 	if err != nil {
@@ -332,6 +359,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("reading convoy '%s': %w", convoyID, err)
 	}
 	var convoys []struct {
+		Title  string `json:"title"`
 		Status string `json:"status"`
 	}
 	if err := json.Unmarshal(showOut.Bytes(), &convoys); err != nil || len(convoys) == 0 {
@@ -364,8 +392,12 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Convoy closed: %s\n", style.Bold.Render("✓"), convoyID)
 
-	// TODO: Trigger notification if configured
-	// Parse description for "Notify: <address>" and send mail
+	notifyConvoyEvent(townBeads, convoyEvent{
+		Event:    beads.ConvoyEventSynthesis,
+		ConvoyID: convoyID,
+		Title:    convoys[0].Title,
+		Detail:   "synthesis complete",
+	})
 
 	return nil
 }
@@ -411,7 +443,23 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 	}
 
 	// Look for structured fields in description
-	for _, line := range strings.Split(convoy.Description, "\n") {
+	parseConvoyMetaFields(meta, convoy.Description)
+
+	// Get tracked leg issues
+	tracked, err := getTrackedIssues(townBeads, convoyID)
+	if err != nil {
+		return nil, fmt.Errorf("getting tracked issues for convoy %s: %w", convoyID, err)
+	}
+	for _, t := range tracked {
+		meta.LegIssues = append(meta.LegIssues, t.ID)
+	}
+
+	return meta, nil
+}
+
+// parseConvoyMetaFields fills formula and review ID from a convoy description.
+func parseConvoyMetaFields(meta *ConvoyMeta, description string) {
+	for _, line := range strings.Split(description, "\n") {
 		line = strings.TrimSpace(line)
 		if colonIdx := strings.Index(line, ":"); colonIdx != -1 {
 			key := strings.ToLower(strings.TrimSpace(line[:colonIdx]))
@@ -426,17 +474,6 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 			}
 		}
 	}
-
-	// Get tracked leg issues
-	tracked, err := getTrackedIssues(townBeads, convoyID)
-	if err != nil {
-		return nil, fmt.Errorf("getting tracked issues for convoy %s: %w", convoyID, err)
-	}
-	for _, t := range tracked {
-		meta.LegIssues = append(meta.LegIssues, t.ID)
-	}
-
-	return meta, nil
 }
 
 // collectLegOutputs gathers outputs from all convoy legs.
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 var (
 	wlJoinHandle      string
 	wlJoinDisplayName string
+	wlJoinRemote      string
 )
 
 var wlCmd = &cobra.Command{
@@ -26,9 +28,10 @@ var wlCmd = &cobra.Command{
 	RunE:    requireSubcommand,
 	Long: `Manage Wasteland federation — join communities, post work, earn reputation.
 
-The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
-sovereign fork of a shared commons database containing the wanted board
-(open work), rig registry, and validated completions.
+The Wasteland is a federation of Gas Towns via DoltHub or a self-hosted
+dolt remote. Each rig has a sovereign fork of a shared commons database
+containing the wanted board (open work), rig registry, and validated
+completions.
 
 Getting started:
   gt wl join steveyegge/wl-commons   # Join the default wasteland
@@ -42,29 +45,40 @@ var wlJoinCmd = &cobra.Command{
 	Long: `Join a wasteland community by forking its shared commons database.
 
 This command:
-  1. Forks the upstream commons to your DoltHub org
+  1. Forks the upstream commons to your org (DoltHub or --remote)
   2. Clones the fork locally
   3. Registers your rig in the rigs table
   4. Pushes the registration to your fork
   5. Saves wasteland configuration locally
 
-The upstream argument is a DoltHub path like 'steveyegge/wl-commons'.
+The upstream argument is an org/db path like 'steveyegge/wl-commons'.
 
-Required environment variables:
+Required environment variables (DoltHub):
   DOLTHUB_TOKEN  - Your DoltHub API token
   DOLTHUB_ORG    - Your DoltHub organization name
 
+Self-hosted remotes:
+  --remote (or GT_DOLT_REMOTE) federates without DoltHub:
+    file:///srv/dolt-remotes          file remotes, as <dir>/<org>/<db>
+    http://doltsrv:50051              dolt sql-server remotesapi, as <org>_<db>
+    gs://bucket/wl/{org}/{db}         any dolt remote URL template
+  The fork org is GT_DOLT_ORG, then DOLTHUB_ORG, then "gastown" (the same
+  org Dolt backups use). Towns sharing a remote should each set GT_DOLT_ORG.
+  No token is needed; file forks are copies, other forks are pushed.
+
 Examples:
   gt wl join steveyegge/wl-commons
   gt wl join steveyegge/wl-commons --handle my-rig
-  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"`,
+  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"
+  gt wl join corp/wl-commons --remote file:///mnt/dolt --handle team-a`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWlJoin,
 }
 
 func init() {
-	wlJoinCmd.Flags().StringVar(&wlJoinHandle, "handle", "", "Rig handle for registration (default: DoltHub org)")
+	wlJoinCmd.Flags().StringVar(&wlJoinHandle, "handle", "", "Rig handle for registration (default: the fork org)")
 	wlJoinCmd.Flags().StringVar(&wlJoinDisplayName, "display-name", "", "Display name for the rig registry")
+	wlJoinCmd.Flags().StringVar(&wlJoinRemote, "remote", "", "Remote backend: dolthub, file:///path, http://host:port or a URL template (default: $GT_DOLT_REMOTE)")
 
 	wlCmd.AddCommand(wlJoinCmd)
 	rootCmd.AddCommand(wlCmd)
@@ -79,15 +93,9 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Require DoltHub credentials
-	token := doltserver.DoltHubToken()
-	if token == "" {
-		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
-	}
-
-	forkOrg := doltserver.DoltHubOrg()
-	if forkOrg == "" {
-		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+	remote, forkOrg, token, err := wlJoinRemoteBackend(wlJoinRemote)
+	if err != nil {
+		return err
 	}
 
 	// Find town root
@@ -134,12 +142,12 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	ownerEmail := townCfg.Owner
 	gtVersion := "dev"
 
-	svc := wasteland.NewService()
+	svc := wasteland.NewServiceForRemote(remote)
 	svc.OnProgress = func(step string) {
 		fmt.Printf("  %s\n", step)
 	}
 
-	fmt.Printf("Joining wasteland %s (fork to %s/%s)...\n", upstream, forkOrg, upstream[strings.Index(upstream, "/")+1:])
+	fmt.Printf("Joining wasteland %s (fork to %s/%s on %s)...\n", upstream, forkOrg, upstream[strings.Index(upstream, "/")+1:], remote.Describe())
 	cfg, err := svc.Join(upstream, forkOrg, token, handle, displayName, ownerEmail, gtVersion, townRoot)
 	if err != nil {
 		return err
@@ -152,3 +160,33 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl browse  — browse the wanted board"))
 	return nil
 }
+
+// wlJoinRemoteBackend resolves the remote backend for a join (flag, then
+// GT_DOLT_REMOTE) with the fork org and token it needs. DoltHub requires
+// DOLTHUB_TOKEN and DOLTHUB_ORG; self-hosted remotes need no token and fork
+// to Remote.Org, the same org Dolt backups use.
+func wlJoinRemoteBackend(spec string) (remote doltserver.Remote, forkOrg, token string, err error) {
+	if spec == "" {
+		spec = os.Getenv(doltserver.EnvDoltRemote)
+	}
+	remote, err = doltserver.ParseRemote(spec)
+	if err != nil {
+		return remote, "", "", err
+	}
+
+	if !remote.IsDoltHub() {
+		return remote, remote.Org(), "", nil
+	}
+
+	// Require DoltHub credentials
+	token = doltserver.DoltHubToken()
+	if token == "" {
+		return remote, "", "", fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens\nor use a self-hosted remote with --remote")
+	}
+
+	forkOrg = doltserver.DoltHubOrg()
+	if forkOrg == "" {
+		return remote, "", "", fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+	}
+	return remote, forkOrg, token, nil
+}
@@ -1,15 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -20,6 +24,7 @@ var (
 	wlBrowsePriority int
 	wlBrowseLimit    int
 	wlBrowseJSON     bool
+	wlBrowseLocal    bool
 )
 
 var wlBrowseCmd = &cobra.Command{
@@ -29,8 +34,10 @@ var wlBrowseCmd = &cobra.Command{
 	RunE:  runWLBrowse,
 	Long: `Browse the Wasteland wanted board (hop/wl-commons).
 
-Uses the clone-then-discard pattern: clones the commons database to a
-temporary directory, queries it, then deletes the clone.
+Uses the clone-then-discard pattern: clones the joined commons to a
+temporary directory, queries it, then deletes the clone. With --local,
+reads the town's local commons through the Dolt server instead (the board
+gt wl post/claim/done write to), without cloning.
 
 EXAMPLES:
   gt wl browse                          # All open wanted items
@@ -39,7 +46,8 @@ EXAMPLES:
   gt wl browse --status claimed         # Claimed items
   gt wl browse --priority 0             # Critical priority only
   gt wl browse --limit 5               # Show 5 items
-  gt wl browse --json                   # JSON output`,
+  gt wl browse --json                   # JSON output
+  gt wl browse --local                  # Browse the town's local commons`,
 }
 
 func init() {
@@ -49,15 +57,41 @@ func init() {
 	wlBrowseCmd.Flags().IntVar(&wlBrowsePriority, "priority", -1, "Filter by priority (0=critical, 2=medium, 4=backlog)")
 	wlBrowseCmd.Flags().IntVar(&wlBrowseLimit, "limit", 50, "Maximum items to display")
 	wlBrowseCmd.Flags().BoolVar(&wlBrowseJSON, "json", false, "Output as JSON")
+	wlBrowseCmd.Flags().BoolVar(&wlBrowseLocal, "local", false, "Browse the town's local commons instead of cloning the upstream one")
 
 	wlCmd.AddCommand(wlBrowseCmd)
 }
 
 func runWLBrowse(cmd *cobra.Command, args []string) error {
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	filter := BrowseFilter{
+		Status:   wlBrowseStatus,
+		Project:  wlBrowseProject,
+		Type:     wlBrowseType,
+		Priority: wlBrowsePriority,
+		Limit:    wlBrowseLimit,
+	}
+
+	if wlBrowseLocal {
+		store := doltserver.NewWLCommons(townRoot)
+		if !store.DatabaseExists(doltserver.WLCommonsDB) {
+			return fmt.Errorf("no local commons database %q (gt wl post creates it; omit --local to browse upstream)", doltserver.WLCommonsDB)
+		}
+		items, err := store.ListWanted(doltserver.WantedFilter(filter))
+		if err != nil {
+			return fmt.Errorf("querying local commons: %w", err)
+		}
+		if wlBrowseJSON {
+			return writeWLBrowseJSON(os.Stdout, items)
+		}
+		printWLBrowseTable(items)
+		return nil
+	}
+
 	doltPath, err := exec.LookPath("dolt")
 	if err != nil {
 		return fmt.Errorf("dolt not found in PATH — install from https://docs.dolthub.com/introduction/installation")
@@ -71,25 +105,29 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 
 	commonsOrg := "hop"
 	commonsDB := "wl-commons"
+	backend := doltserver.DoltHubRemote
+	// Browse the joined wasteland's commons, on its remote backend.
+	if cfg, err := wasteland.LoadConfig(townRoot); err == nil {
+		if org, db, err := wasteland.ParseUpstream(cfg.Upstream); err == nil {
+			commonsOrg, commonsDB = org, db
+		}
+		if backend, err = cfg.RemoteBackend(); err != nil {
+			return err
+		}
+	}
 	cloneDir := filepath.Join(tmpDir, commonsDB)
 
 	remote := fmt.Sprintf("%s/%s", commonsOrg, commonsDB)
 	fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
 
-	cloneCmd := exec.Command(doltPath, "clone", remote, cloneDir)
+	cloneCmd := exec.Command(doltPath, "clone", backend.URL(commonsOrg, commonsDB), cloneDir)
 	cloneCmd.Stderr = os.Stderr
 	if err := cloneCmd.Run(); err != nil {
-		return fmt.Errorf("cloning %s: %w\nEnsure the database exists on DoltHub: https://www.dolthub.com/%s", remote, err, remote)
+		return fmt.Errorf("cloning %s: %w\nEnsure the database exists on %s", remote, err, backend.Describe())
 	}
 	fmt.Printf("%s Cloned successfully\n\n", style.Bold.Render("✓"))
 
-	query := buildBrowseQuery(BrowseFilter{
-		Status:   wlBrowseStatus,
-		Project:  wlBrowseProject,
-		Type:     wlBrowseType,
-		Priority: wlBrowsePriority,
-		Limit:    wlBrowseLimit,
-	})
+	query := buildBrowseQuery(filter)
 
 	if wlBrowseJSON {
 		sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "json")
@@ -103,6 +141,7 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 }
 
 // BrowseFilter holds filter parameters for building a browse query.
+// It converts to doltserver.WantedFilter.
 type BrowseFilter struct {
 	Status   string
 	Project  string
@@ -154,7 +193,23 @@ func renderWLBrowseTable(doltPath, cloneDir, query string) error {
 		return nil
 	}
 
-	tbl := style.NewTable(
+	tbl := newWLBrowseTable()
+	for _, row := range rows[1:] {
+		if len(row) < 8 {
+			continue
+		}
+		pri := wlFormatPriority(row[4])
+		tbl.AddRow(row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7])
+	}
+
+	fmt.Printf("Wanted items (%d):\n\n", len(rows)-1)
+	fmt.Print(tbl.Render())
+
+	return nil
+}
+
+func newWLBrowseTable() *style.Table {
+	return style.NewTable(
 		style.Column{Name: "ID", Width: 12},
 		style.Column{Name: "TITLE", Width: 40},
 		style.Column{Name: "PROJECT", Width: 12},
@@ -164,19 +219,55 @@ func renderWLBrowseTable(doltPath, cloneDir, query string) error {
 		style.Column{Name: "STATUS", Width: 10},
 		style.Column{Name: "EFFORT", Width: 8},
 	)
+}
 
-	for _, row := range rows[1:] {
-		if len(row) < 8 {
-			continue
-		}
-		pri := wlFormatPriority(row[4])
-		tbl.AddRow(row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7])
+// printWLBrowseTable renders wanted items read from the local commons.
+func printWLBrowseTable(items []*doltserver.WantedItem) {
+	if len(items) == 0 {
+		fmt.Println("No wanted items found matching your filters.")
+		return
 	}
-
-	fmt.Printf("Wanted items (%d):\n\n", len(rows)-1)
+	tbl := newWLBrowseTable()
+	for _, item := range items {
+		tbl.AddRow(item.ID, item.Title, item.Project, item.Type, wlFormatPriority(strconv.Itoa(item.Priority)),
+			item.PostedBy, item.Status, item.EffortLevel)
+	}
+	fmt.Printf("Wanted items (%d):\n\n", len(items))
 	fmt.Print(tbl.Render())
+}
 
-	return nil
+// wlBrowseRow is one wanted item in --json output. It matches the columns
+// and shape of dolt's JSON result format used by the upstream path.
+type wlBrowseRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Project     string `json:"project,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Priority    int    `json:"priority"`
+	PostedBy    string `json:"posted_by,omitempty"`
+	Status      string `json:"status"`
+	EffortLevel string `json:"effort_level,omitempty"`
+}
+
+func writeWLBrowseJSON(w io.Writer, items []*doltserver.WantedItem) error {
+	rows := make([]wlBrowseRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, wlBrowseRow{
+			ID:          item.ID,
+			Title:       item.Title,
+			Project:     item.Project,
+			Type:        item.Type,
+			Priority:    item.Priority,
+			PostedBy:    item.PostedBy,
+			Status:      item.Status,
+			EffortLevel: item.EffortLevel,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Rows []wlBrowseRow `json:"rows"`
+	}{rows})
 }
 
 func wlParseCSV(data string) [][]string {
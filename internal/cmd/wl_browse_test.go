@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 func TestWlParseCSV_Empty(t *testing.T) {
@@ -193,3 +197,25 @@ func TestBuildBrowseQuery_EscapesSQL(t *testing.T) {
 	}
 }
 
+func TestWriteWLBrowseJSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	items := []*doltserver.WantedItem{{ID: "w-1", Title: "Fix it", Project: "gastown", Priority: 0, Status: "open"}}
+	if err := writeWLBrowseJSON(&buf, items); err != nil {
+		t.Fatalf("writeWLBrowseJSON() error: %v", err)
+	}
+	var got struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(got.Rows) != 1 || got.Rows[0]["id"] != "w-1" || got.Rows[0]["priority"] != float64(0) {
+		t.Errorf("rows = %v", got.Rows)
+	}
+
+	buf.Reset()
+	if err := writeWLBrowseJSON(&buf, nil); err != nil || !strings.Contains(buf.String(), `"rows": []`) {
+		t.Errorf("empty output = %q, %v; want empty rows array", buf.String(), err)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -90,3 +91,33 @@ func TestWlSyncNoArgs(t *testing.T) {
 		t.Errorf("sync should accept 0 arguments: %v", err)
 	}
 }
+
+func TestWlJoinRemoteBackend(t *testing.T) {
+	t.Setenv("GT_DOLT_REMOTE", "")
+	t.Setenv("GT_DOLT_ORG", "")
+	t.Setenv("DOLTHUB_ORG", "")
+	t.Setenv("DOLTHUB_TOKEN", "")
+
+	// DoltHub still requires credentials.
+	if _, _, _, err := wlJoinRemoteBackend(""); err == nil || !strings.Contains(err.Error(), "DOLTHUB_TOKEN") {
+		t.Errorf("DoltHub without token: err = %v", err)
+	}
+
+	// Self-hosted needs no token and forks to the same default org as
+	// Dolt backups.
+	remote, org, token, err := wlJoinRemoteBackend("file:///srv/dolt")
+	if err != nil {
+		t.Fatalf("file remote: %v", err)
+	}
+	if remote.IsDoltHub() || org != remote.Org() || org == "" || token != "" {
+		t.Errorf("got remote=%+v org=%q token=%q", remote, org, token)
+	}
+
+	// GT_DOLT_REMOTE is the default; GT_DOLT_ORG names the fork org.
+	t.Setenv("GT_DOLT_REMOTE", "http://doltsrv:50051")
+	t.Setenv("GT_DOLT_ORG", "corp")
+	remote, org, _, err = wlJoinRemoteBackend("")
+	if err != nil || remote.URL(org, "wl-commons") != "http://doltsrv:50051/corp_wl-commons" {
+		t.Errorf("env remote: %+v org=%q err=%v", remote, org, err)
+	}
+}
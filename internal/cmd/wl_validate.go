@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	wlValidateReject      bool
+	wlValidateQuality     float64
+	wlValidateReliability float64
+	wlValidateConfidence  float64
+	wlValidateSeverity    string
+	wlValidateSkills      []string
+	wlValidateMessage     string
+)
+
+var wlReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "List completions awaiting validation",
+	Long: `List completions awaiting validation in the local wl-commons database.
+
+Completions you can validate are those for wanted items you posted, or any
+completion once your rig reaches trust level 2. Trust is recomputed from
+verified stamps, not read from the rigs table. Your own completions are
+never yours to validate.
+
+Examples:
+  gt wl review
+  gt wl validate c-abc123`,
+	Args: cobra.NoArgs,
+	RunE: runWlReview,
+}
+
+var wlValidateCmd = &cobra.Command{
+	Use:   "validate <completion-id>",
+	Short: "Validate a completion and issue a signed stamp",
+	Long: `Validate a completion by issuing a stamp: a signed assessment of the
+completer's work.
+
+Accepting marks the completion validated and its wanted item completed.
+--reject sends the wanted item back to 'claimed' so the claimer can submit
+new evidence. Either way a stamp is recorded.
+
+Stamps are hash-chained per author: each records the hash of your previous
+stamp, and is signed with your town's ed25519 key (mayor/wasteland.key,
+created on first use; the public half is published in the rigs table).
+Editing any stamp afterwards breaks the chain, which 'gt wl verify' detects.
+
+Valence is scored on quality and reliability, each from -1 to 1. Accepts
+default to 1, rejects to -1. Severity weights the stamp's effect on
+reputation: leaf (1x), branch (2x), root (4x).
+
+After the stamp is recorded, the completer's trust level and badges are
+recomputed from all verified stamps.
+
+Examples:
+  gt wl validate c-abc123
+  gt wl validate c-abc123 --quality 0.7 --skill go --skill dolt -m 'clean fix'
+  gt wl validate c-abc123 --severity branch --confidence 0.8
+  gt wl validate c-abc123 --reject -m 'tests fail on main'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlValidate,
+}
+
+func init() {
+	wlValidateCmd.Flags().BoolVar(&wlValidateReject, "reject", false, "Reject the completion")
+	wlValidateCmd.Flags().Float64Var(&wlValidateQuality, "quality", 0, "Quality valence, -1 to 1 (default 1, or -1 with --reject)")
+	wlValidateCmd.Flags().Float64Var(&wlValidateReliability, "reliability", 0, "Reliability valence, -1 to 1 (default 1, or -1 with --reject)")
+	wlValidateCmd.Flags().Float64Var(&wlValidateConfidence, "confidence", 1, "How confident you are in the assessment, 0 to 1")
+	wlValidateCmd.Flags().StringVar(&wlValidateSeverity, "severity", wasteland.SeverityLeaf, "Stamp weight: leaf, branch or root")
+	wlValidateCmd.Flags().StringSliceVar(&wlValidateSkills, "skill", nil, "Skill tag demonstrated by the work (repeatable)")
+	wlValidateCmd.Flags().StringVarP(&wlValidateMessage, "message", "m", "", "Review message")
+
+	wlCmd.AddCommand(wlReviewCmd)
+	wlCmd.AddCommand(wlValidateCmd)
+}
+
+// wlCommonsForRig resolves the town, its rig handle and the local commons
+// database shared by the stamp commands.
+func wlCommonsForRig() (townRoot, rigHandle string, err error) {
+	townRoot, err = workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	wlCfg, err := wasteland.LoadConfig(townRoot)
+	if err != nil {
+		return "", "", fmt.Errorf("loading wasteland config: %w", err)
+	}
+
+	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return "", "", fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+	return townRoot, wlCfg.RigHandle, nil
+}
+
+func runWlReview(cmd *cobra.Command, args []string) error {
+	townRoot, rigHandle, err := wlCommonsForRig()
+	if err != nil {
+		return err
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	pending, err := store.ListPendingCompletions()
+	if err != nil {
+		return fmt.Errorf("listing completions: %w", err)
+	}
+	if len(pending) == 0 {
+		fmt.Println("No completions awaiting validation.")
+		return nil
+	}
+
+	trust, err := verifiedTrustLevel(store, rigHandle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Completions awaiting validation (%d):\n\n", style.Bold.Render("●"), len(pending))
+	for _, c := range pending {
+		marker := " "
+		if canValidate(c, rigHandle, trust) == nil {
+			marker = style.Bold.Render("→")
+		}
+		fmt.Printf("%s %s  %s  %s\n", marker, c.ID, c.WantedID, c.WantedTitle)
+		fmt.Printf("    by %s, posted by %s, %s\n", c.CompletedBy, c.PostedBy, c.CompletedAt.Format("2006-01-02 15:04"))
+		if c.Evidence != "" {
+			fmt.Printf("    evidence: %s\n", c.Evidence)
+		}
+	}
+	fmt.Printf("\n%s marks completions you can validate (trust level %d).\n", style.Bold.Render("→"), trust)
+	return nil
+}
+
+func runWlValidate(cmd *cobra.Command, args []string) error {
+	townRoot, rigHandle, err := wlCommonsForRig()
+	if err != nil {
+		return err
+	}
+
+	opts := validateOptions{
+		Reject:      wlValidateReject,
+		Quality:     valenceFlag(cmd, "quality", wlValidateQuality, wlValidateReject),
+		Reliability: valenceFlag(cmd, "reliability", wlValidateReliability, wlValidateReject),
+		Confidence:  wlValidateConfidence,
+		Severity:    wlValidateSeverity,
+		Skills:      wlValidateSkills,
+		Message:     wlValidateMessage,
+	}
+
+	key, err := wasteland.LoadOrCreateSigningKey(townRoot)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := wasteland.LockStampChain(townRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	store := doltserver.NewWLCommons(townRoot)
+	stamp, rep, err := validateCompletion(store, key, rigHandle, args[0], opts, time.Now())
+	if err != nil {
+		return err
+	}
+
+	verdict := "Validated"
+	if opts.Reject {
+		verdict = "Rejected"
+	}
+	fmt.Printf("%s %s completion %s\n", style.Bold.Render("✓"), verdict, args[0])
+	fmt.Printf("  Stamp: %s\n", stamp.ID)
+	fmt.Printf("  Subject: %s\n", stamp.Subject)
+	fmt.Printf("  Block hash: %s\n", stamp.BlockHash)
+	if rep != nil {
+		fmt.Printf("  %s trust level: %d (score %.2f)\n", rep.Handle, rep.TrustLevel, rep.Score)
+		if len(rep.Badges) > 0 {
+			fmt.Printf("  Badges: %s\n", wasteland.BadgeTypes(rep.Badges))
+		}
+	}
+	return nil
+}
+
+// validateOptions holds the assessment a validator gives a completion.
+type validateOptions struct {
+	Reject      bool
+	Quality     float64
+	Reliability float64
+	Confidence  float64
+	Severity    string
+	Skills      []string
+	Message     string
+}
+
+// valenceFlag returns a valence flag's value, defaulting to full approval,
+// or full disapproval for rejections, when the flag wasn't given.
+func valenceFlag(cmd *cobra.Command, name string, v float64, reject bool) float64 {
+	if cmd.Flags().Changed(name) {
+		return v
+	}
+	if reject {
+		return -1
+	}
+	return 1
+}
+
+func (o validateOptions) check() error {
+	for name, v := range map[string]float64{"quality": o.Quality, "reliability": o.Reliability} {
+		if v < -1 || v > 1 {
+			return fmt.Errorf("--%s must be between -1 and 1, got %g", name, v)
+		}
+	}
+	if o.Confidence < 0 || o.Confidence > 1 {
+		return fmt.Errorf("--confidence must be between 0 and 1, got %g", o.Confidence)
+	}
+	switch o.Severity {
+	case wasteland.SeverityLeaf, wasteland.SeverityBranch, wasteland.SeverityRoot:
+	default:
+		return fmt.Errorf("--severity must be leaf, branch or root, got %q", o.Severity)
+	}
+	mean := (o.Quality + o.Reliability) / 2
+	if o.Reject && mean > 0 {
+		return fmt.Errorf("a rejection cannot carry positive valence")
+	}
+	if !o.Reject && mean <= 0 {
+		return fmt.Errorf("accepting needs positive valence; use --reject to reject")
+	}
+	return nil
+}
+
+// canValidate reports why rigHandle may not validate c, or nil if it may.
+func canValidate(c *doltserver.Completion, rigHandle string, trustLevel int) error {
+	switch {
+	case c.ValidatedBy != "":
+		return fmt.Errorf("completion %s was already validated by %s", c.ID, c.ValidatedBy)
+	case c.CompletedBy == rigHandle:
+		return fmt.Errorf("cannot validate your own completion %s", c.ID)
+	case c.PostedBy == rigHandle, trustLevel >= wasteland.ValidatorTrustLevel:
+		return nil
+	}
+	return fmt.Errorf("only the poster (%s) or a rig with trust level %d+ can validate %s (yours: %d)",
+		c.PostedBy, wasteland.ValidatorTrustLevel, c.ID, trustLevel)
+}
+
+// validateCompletion contains the testable business logic for validating a
+// completion: it checks the validator may validate, issues a stamp linked
+// to the validator's chain and signed with key, settles the completion and
+// recomputes the completer's reputation. The returned reputation is nil if
+// the stamp was recorded but updating reputation failed. Callers must hold
+// wasteland.LockStampChain so concurrent validations can't fork the chain.
+func validateCompletion(store doltserver.WLStampStore, key ed25519.PrivateKey, rigHandle, completionID string, opts validateOptions, now time.Time) (*doltserver.Stamp, *wasteland.Reputation, error) {
+	if err := opts.check(); err != nil {
+		return nil, nil, err
+	}
+
+	c, err := store.QueryCompletion(completionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying completion: %w", err)
+	}
+
+	rig, err := store.QueryRig(rigHandle)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying rig %s: %w", rigHandle, err)
+	}
+	// The stored trust_level is writable by anyone with commons access;
+	// only stamps that verify can make a rig a validator.
+	trust := 0
+	if c.PostedBy != rigHandle {
+		if trust, err = verifiedTrustLevel(store, rigHandle); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := canValidate(c, rigHandle, trust); err != nil {
+		return nil, nil, err
+	}
+
+	pub := wasteland.PublicKeyHex(key)
+	switch {
+	case rig == nil || rig.PublicKey == "":
+		if err := store.PublishRigKey(rigHandle, pub); err != nil {
+			return nil, nil, err
+		}
+	case rig.PublicKey != pub:
+		return nil, nil, fmt.Errorf("rig %s has a different signing key published than mayor/wasteland.key; stamps signed with it would not verify", rigHandle)
+	}
+
+	chain, err := store.ListStamps(rigHandle)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing your stamps: %w", err)
+	}
+
+	valence := map[string]float64{"quality": opts.Quality, "reliability": opts.Reliability}
+	stamp := wasteland.NewCompletionStamp(rigHandle, c, valence, opts.Confidence, opts.Severity,
+		normalizeSkills(opts.Skills), opts.Message, now)
+	wasteland.SealStamp(stamp, wasteland.ChainTip(chain), key)
+
+	if err := store.RecordValidation(stamp, !opts.Reject); err != nil {
+		return nil, nil, err
+	}
+
+	rep, err := refreshReputation(store, c.CompletedBy)
+	if err != nil {
+		// The stamp is recorded; reputation catches up on the next
+		// validation or 'gt wl reputation --apply'.
+		style.PrintWarning("updating reputation: %v", err)
+		return stamp, nil, nil
+	}
+	return stamp, rep, nil
+}
+
+// verifiedTrustLevel returns handle's trust level recomputed from verified
+// stamps.
+func verifiedTrustLevel(store doltserver.WLStampStore, handle string) (int, error) {
+	rep, _, err := computeReputation(store, handle)
+	if err != nil {
+		return 0, fmt.Errorf("computing trust for %s: %w", handle, err)
+	}
+	return rep.TrustLevel, nil
+}
+
+// refreshReputation recomputes handle's reputation from verified stamps and
+// stores it.
+func refreshReputation(store doltserver.WLStampStore, handle string) (*wasteland.Reputation, error) {
+	rep, _, err := computeReputation(store, handle)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.UpdateReputation(handle, rep.TrustLevel, rep.Badges); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// computeReputation verifies every stamp chain and derives handle's
+// reputation from the stamps that pass.
+func computeReputation(store doltserver.WLStampStore, handle string) (*wasteland.Reputation, *wasteland.ChainReport, error) {
+	report, stamps, err := verifyStampChains(store)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wasteland.ComputeReputation(handle, stamps, report), report, nil
+}
+
+// verifyStampChains loads every stamp and published key and verifies the
+// chains.
+func verifyStampChains(store doltserver.WLStampStore) (*wasteland.ChainReport, []*doltserver.Stamp, error) {
+	stamps, err := store.ListStamps("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing stamps: %w", err)
+	}
+	rigs, err := store.ListRigs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing rigs: %w", err)
+	}
+	keys := make(map[string]string, len(rigs))
+	for _, r := range rigs {
+		keys[r.Handle] = r.PublicKey
+	}
+	return wasteland.VerifyChains(stamps, keys), stamps, nil
+}
+
+func normalizeSkills(skills []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, s := range skills {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
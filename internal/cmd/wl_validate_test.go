@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// fakeWLStampStore is an in-memory WLStampStore for cmd package tests.
+type fakeWLStampStore struct {
+	mu          sync.Mutex
+	completions map[string]*doltserver.Completion
+	stamps      []*doltserver.Stamp
+	rigs        map[string]*doltserver.RigRecord
+	badges      map[string]*doltserver.Badge
+
+	RecordValidationErr error
+}
+
+func newFakeWLStampStore() *fakeWLStampStore {
+	return &fakeWLStampStore{
+		completions: make(map[string]*doltserver.Completion),
+		rigs:        make(map[string]*doltserver.RigRecord),
+		badges:      make(map[string]*doltserver.Badge),
+	}
+}
+
+func (f *fakeWLStampStore) QueryCompletion(id string) (*doltserver.Completion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.completions[id]
+	if !ok {
+		return nil, fmt.Errorf("completion %q not found", id)
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *fakeWLStampStore) ListPendingCompletions() ([]*doltserver.Completion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*doltserver.Completion
+	for _, c := range f.completions {
+		if c.ValidatedBy == "" {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWLStampStore) ListStamps(author string) ([]*doltserver.Stamp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*doltserver.Stamp
+	for _, s := range f.stamps {
+		if author == "" || s.Author == author {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWLStampStore) QueryRig(handle string) (*doltserver.RigRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r, ok := f.rigs[handle]; ok {
+		cp := *r
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeWLStampStore) ListRigs() ([]*doltserver.RigRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*doltserver.RigRecord
+	for _, r := range f.rigs {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (f *fakeWLStampStore) PublishRigKey(handle, publicKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.rigs[handle]
+	if !ok {
+		r = &doltserver.RigRecord{Handle: handle}
+		f.rigs[handle] = r
+	}
+	r.PublicKey = publicKey
+	return nil
+}
+
+func (f *fakeWLStampStore) RecordValidation(stamp *doltserver.Stamp, accepted bool) error {
+	if f.RecordValidationErr != nil {
+		return f.RecordValidationErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.completions[stamp.ContextID]
+	if !ok || c.ValidatedBy != "" {
+		return fmt.Errorf("completion %s already validated or does not exist", stamp.ContextID)
+	}
+	f.stamps = append(f.stamps, stamp)
+	if accepted {
+		c.ValidatedBy = stamp.Author
+		c.StampID = stamp.ID
+		c.BlockHash = stamp.BlockHash
+	} else {
+		delete(f.completions, c.ID)
+	}
+	return nil
+}
+
+func (f *fakeWLStampStore) UpdateReputation(handle string, trustLevel int, badges []*doltserver.Badge) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.rigs[handle]
+	if !ok {
+		return fmt.Errorf("rig %q not registered", handle)
+	}
+	r.TrustLevel = trustLevel
+	for _, b := range badges {
+		if _, held := f.badges[b.ID]; !held {
+			f.badges[b.ID] = b
+		}
+	}
+	return nil
+}
+
+func (f *fakeWLStampStore) addCompletion(id, postedBy, completedBy string) {
+	f.completions[id] = &doltserver.Completion{
+		ID: id, WantedID: "w-" + id, PostedBy: postedBy, CompletedBy: completedBy,
+	}
+	for _, h := range []string{postedBy, completedBy} {
+		if _, ok := f.rigs[h]; !ok {
+			f.rigs[h] = &doltserver.RigRecord{Handle: h}
+		}
+	}
+}
+
+func testSigningKey(seed byte) ed25519.PrivateKey {
+	s := make([]byte, ed25519.SeedSize)
+	s[0] = seed
+	return ed25519.NewKeyFromSeed(s)
+}
+
+func acceptOpts() validateOptions {
+	return validateOptions{Quality: 1, Reliability: 1, Confidence: 1, Severity: wasteland.SeverityLeaf}
+}
+
+func TestValidateCompletion_Accept(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLStampStore()
+	store.addCompletion("c-1", "poster", "worker")
+	key := testSigningKey(1)
+	opts := acceptOpts()
+	opts.Skills = []string{" Go ", "go", "dolt"}
+
+	stamp, rep, err := validateCompletion(store, key, "poster", "c-1", opts, time.Now())
+	if err != nil {
+		t.Fatalf("validateCompletion() error: %v", err)
+	}
+	if stamp.Subject != "worker" || stamp.Author != "poster" || stamp.ContextID != "c-1" {
+		t.Errorf("stamp = %+v", stamp)
+	}
+	if stamp.PrevStampHash != "" {
+		t.Errorf("first stamp PrevStampHash = %q, want empty", stamp.PrevStampHash)
+	}
+	if strings.Join(stamp.SkillTags, ",") != "go,dolt" {
+		t.Errorf("SkillTags = %v", stamp.SkillTags)
+	}
+	if store.rigs["poster"].PublicKey != wasteland.PublicKeyHex(key) {
+		t.Error("validator's public key not published")
+	}
+	if c := store.completions["c-1"]; c.ValidatedBy != "poster" || c.BlockHash != stamp.BlockHash {
+		t.Errorf("completion not settled: %+v", c)
+	}
+	if rep == nil || rep.TrustLevel != 1 || store.rigs["worker"].TrustLevel != 1 {
+		t.Errorf("reputation = %+v, stored trust %d", rep, store.rigs["worker"].TrustLevel)
+	}
+	if _, ok := store.badges[wasteland.BadgeID("worker", wasteland.BadgeFirstValidation)]; !ok {
+		t.Error("first-validation badge not awarded")
+	}
+}
+
+func TestValidateCompletion_ChainsStamps(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLStampStore()
+	store.addCompletion("c-1", "poster", "worker")
+	store.addCompletion("c-2", "poster", "other")
+	key := testSigningKey(1)
+
+	first, _, err := validateCompletion(store, key, "poster", "c-1", acceptOpts(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := validateCompletion(store, key, "poster", "c-2", acceptOpts(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.PrevStampHash != first.BlockHash {
+		t.Errorf("second stamp links to %q, want %q", second.PrevStampHash, first.BlockHash)
+	}
+
+	report, err := verifyStamps(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Err() != nil {
+		t.Errorf("chain problems: %v", report.Problems)
+	}
+
+	// Tampering with the first stamp is detected.
+	store.stamps[0].Valence["quality"] = 0.1
+	report, _ = verifyStamps(store, "poster")
+	if report.Err() == nil {
+		t.Error("tampered stamp not detected")
+	}
+}
+
+func TestValidateCompletion_Reject(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLStampStore()
+	store.addCompletion("c-1", "poster", "worker")
+	opts := validateOptions{Reject: true, Quality: -1, Reliability: -0.5, Confidence: 1, Severity: wasteland.SeverityLeaf}
+
+	_, rep, err := validateCompletion(store, testSigningKey(1), "poster", "c-1", opts, time.Now())
+	if err != nil {
+		t.Fatalf("validateCompletion() error: %v", err)
+	}
+	if _, ok := store.completions["c-1"]; ok {
+		t.Error("rejected completion should be removed")
+	}
+	if rep == nil || rep.Negative != 1 || rep.TrustLevel != 0 {
+		t.Errorf("reputation = %+v", rep)
+	}
+}
+
+func TestValidateCompletion_Denied(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		setup   func(*fakeWLStampStore)
+		rig     string
+		opts    func(*validateOptions)
+		wantErr string
+	}{
+		{name: "own completion", rig: "worker", wantErr: "your own completion"},
+		{name: "not poster, untrusted", rig: "stranger", wantErr: "trust level"},
+		{name: "unknown completion", rig: "poster", wantErr: "not found", setup: func(f *fakeWLStampStore) {
+			delete(f.completions, "c-1")
+		}},
+		{name: "already validated", rig: "poster", wantErr: "already validated", setup: func(f *fakeWLStampStore) {
+			f.completions["c-1"].ValidatedBy = "someone"
+		}},
+		{name: "key mismatch", rig: "poster", wantErr: "different signing key", setup: func(f *fakeWLStampStore) {
+			f.rigs["poster"].PublicKey = wasteland.PublicKeyHex(testSigningKey(9))
+		}},
+		{name: "accept with negative valence", rig: "poster", wantErr: "--reject", opts: func(o *validateOptions) {
+			o.Quality, o.Reliability = -1, 0
+		}},
+		{name: "reject with positive valence", rig: "poster", wantErr: "positive valence", opts: func(o *validateOptions) {
+			o.Reject = true
+		}},
+		{name: "bad severity", rig: "poster", wantErr: "--severity", opts: func(o *validateOptions) {
+			o.Severity = "trunk"
+		}},
+		{name: "confidence out of range", rig: "poster", wantErr: "--confidence", opts: func(o *validateOptions) {
+			o.Confidence = 2
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := newFakeWLStampStore()
+			store.addCompletion("c-1", "poster", "worker")
+			if tt.setup != nil {
+				tt.setup(store)
+			}
+			opts := acceptOpts()
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+			_, _, err := validateCompletion(store, testSigningKey(1), tt.rig, "c-1", opts, time.Now())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(store.stamps) != 0 {
+				t.Error("no stamp should be recorded")
+			}
+		})
+	}
+}
+
+func TestValidateCompletion_TrustedValidator(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLStampStore()
+	now := time.Now()
+
+	// veteran earns trust from two posters' verified root stamps.
+	opts := acceptOpts()
+	opts.Severity = wasteland.SeverityRoot
+	for i, poster := range []string{"alpha", "bravo"} {
+		id := fmt.Sprintf("c-earn-%d", i)
+		store.addCompletion(id, poster, "veteran")
+		if _, _, err := validateCompletion(store, testSigningKey(byte(10+i)), poster, id, opts, now); err != nil {
+			t.Fatalf("earning stamp from %s: %v", poster, err)
+		}
+	}
+
+	store.addCompletion("c-1", "poster", "worker")
+	if _, _, err := validateCompletion(store, testSigningKey(1), "veteran", "c-1", acceptOpts(), now); err != nil {
+		t.Errorf("trusted rig should be able to validate: %v", err)
+	}
+}
+
+func TestValidateCompletion_StoredTrustLevelIgnored(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLStampStore()
+	store.addCompletion("c-1", "poster", "worker")
+	// A trust level written straight into the rigs table, with no stamps
+	// behind it, must not make a rig a validator.
+	store.rigs["forger"] = &doltserver.RigRecord{Handle: "forger", TrustLevel: wasteland.ValidatorTrustLevel}
+
+	_, _, err := validateCompletion(store, testSigningKey(1), "forger", "c-1", acceptOpts(), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "trust level") {
+		t.Fatalf("validateCompletion() error = %v, want trust level refusal", err)
+	}
+	if len(store.stamps) != 0 {
+		t.Error("no stamp should be recorded")
+	}
+}
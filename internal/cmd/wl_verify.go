@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+var wlReputationApply bool
+
+var wlVerifyCmd = &cobra.Command{
+	Use:   "verify [handle]",
+	Short: "Verify the integrity of stamp chains",
+	Long: `Verify every rig's stamp chain in the local wl-commons database, or only
+the given rig's.
+
+Each stamp's block hash must match its content, its signature must verify
+against the author's published public key, and the author's stamps must
+form a single unbroken, unforked chain. Stamps that fail, and any stamps
+chained after them, don't count toward reputation.
+
+Exits non-zero if any problem is found.
+
+Examples:
+  gt wl verify
+  gt wl verify my-rig`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlVerify,
+}
+
+var wlReputationCmd = &cobra.Command{
+	Use:   "reputation [handle]",
+	Short: "Show a rig's reputation derived from verified stamps",
+	Long: `Compute a rig's reputation (default: your own) from the verified stamps
+it has received.
+
+Each stamp contributes its mean valence × confidence × severity weight
+(leaf 1, branch 2, root 4) to the score. Trust levels:
+
+  1  positive score
+  2  score ≥ 5 from at least 2 rigs (may validate any completion)
+  3  score ≥ 20 from at least 3 rigs
+
+Badges: first-validation, validated-10, validated-50, and skill:<tag> for
+3+ positive stamps tagged with a skill.
+
+--apply stores the trust level and awards the badges, as 'gt wl validate'
+does after each stamp.
+
+Examples:
+  gt wl reputation
+  gt wl reputation other-rig --apply`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlReputation,
+}
+
+func init() {
+	wlReputationCmd.Flags().BoolVar(&wlReputationApply, "apply", false, "Store the computed trust level and badges")
+
+	wlCmd.AddCommand(wlVerifyCmd)
+	wlCmd.AddCommand(wlReputationCmd)
+}
+
+func runWlVerify(cmd *cobra.Command, args []string) error {
+	townRoot, _, err := wlCommonsForRig()
+	if err != nil {
+		return err
+	}
+	handle := ""
+	if len(args) > 0 {
+		handle = args[0]
+	}
+
+	report, err := verifyStamps(doltserver.NewWLCommons(townRoot), handle)
+	if err != nil {
+		return err
+	}
+
+	authors := make([]string, 0, len(report.Stamps))
+	for a := range report.Stamps {
+		authors = append(authors, a)
+	}
+	sort.Strings(authors)
+	if len(authors) == 0 {
+		fmt.Println("No stamps to verify.")
+		return nil
+	}
+
+	problems := make(map[string][]wasteland.ChainProblem)
+	for _, p := range report.Problems {
+		problems[p.Author] = append(problems[p.Author], p)
+	}
+	for _, a := range authors {
+		if len(problems[a]) == 0 {
+			fmt.Printf("%s %s: %d stamp(s), chain intact\n", style.Success.Render("✓"), a, report.Stamps[a])
+			continue
+		}
+		fmt.Printf("%s %s: %d stamp(s), %d problem(s)\n", style.Error.Render("✗"), a, report.Stamps[a], len(problems[a]))
+		for _, p := range problems[a] {
+			if p.StampID != "" {
+				fmt.Printf("    %s: %s\n", p.StampID, p.Problem)
+			} else {
+				fmt.Printf("    %s\n", p.Problem)
+			}
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// verifyStamps verifies stamp chains, limited to handle's chain if set.
+func verifyStamps(store doltserver.WLStampStore, handle string) (*wasteland.ChainReport, error) {
+	if handle == "" {
+		report, _, err := verifyStampChains(store)
+		return report, err
+	}
+
+	stamps, err := store.ListStamps(handle)
+	if err != nil {
+		return nil, fmt.Errorf("listing stamps: %w", err)
+	}
+	rig, err := store.QueryRig(handle)
+	if err != nil {
+		return nil, fmt.Errorf("querying rig %s: %w", handle, err)
+	}
+	keys := map[string]string{}
+	if rig != nil {
+		keys[handle] = rig.PublicKey
+	}
+	return wasteland.VerifyChains(stamps, keys), nil
+}
+
+func runWlReputation(cmd *cobra.Command, args []string) error {
+	townRoot, handle, err := wlCommonsForRig()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		handle = args[0]
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	var rep *wasteland.Reputation
+	if wlReputationApply {
+		rep, err = refreshReputation(store, handle)
+	} else {
+		rep, _, err = computeReputation(store, handle)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("●"), rep.Handle)
+	fmt.Printf("  Trust level: %d\n", rep.TrustLevel)
+	fmt.Printf("  Score: %.2f\n", rep.Score)
+	fmt.Printf("  Stamps: %d positive, %d negative, from %d rig(s)\n", rep.Positive, rep.Negative, rep.Authors)
+	if len(rep.Badges) > 0 {
+		fmt.Printf("  Badges: %s\n", wasteland.BadgeTypes(rep.Badges))
+	}
+	if wlReputationApply {
+		fmt.Printf("%s Reputation stored\n", style.Bold.Render("✓"))
+	}
+	return nil
+}
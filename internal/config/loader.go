@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -225,6 +228,9 @@ func validateRigSettings(c *RigSettings) error {
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeStrategy indicates an invalid merge_strategy.
+var ErrInvalidMergeStrategy = errors.New("invalid merge_strategy")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -233,6 +239,12 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	// Validate merge_strategy
+	if c.MergeStrategy != "" && !IsValidMergeStrategy(c.MergeStrategy) {
+		return fmt.Errorf("%w: got '%s', want '%s', '%s', or '%s'",
+			ErrInvalidMergeStrategy, c.MergeStrategy, MergeStrategySquash, MergeStrategyMergeCommit, MergeStrategyRebase)
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
@@ -893,6 +905,9 @@ func validateMessagingConfig(c *MessagingConfig) error {
 	if c.NudgeChannels == nil {
 		c.NudgeChannels = make(map[string][]string)
 	}
+	if c.Rules == nil {
+		c.Rules = make(map[string][]MailRule)
+	}
 
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
@@ -931,6 +946,61 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	// Validate mail rules
+	for mailbox, rules := range c.Rules {
+		seen := make(map[string]bool, len(rules))
+		for _, rule := range rules {
+			if err := validateMailRule(rule); err != nil {
+				return fmt.Errorf("mail rule %s/%s: %w", mailbox, rule.Name, err)
+			}
+			if seen[rule.Name] {
+				return fmt.Errorf("mail rule %s/%s: duplicate rule name", mailbox, rule.Name)
+			}
+			seen[rule.Name] = true
+		}
+	}
+
+	if c.Bridge != nil && c.Bridge.Enabled {
+		if err := validateMailBridge(c.Bridge); err != nil {
+			return fmt.Errorf("mail bridge: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateMailBridge checks an enabled mail bridge config.
+func validateMailBridge(b *MailBridgeConfig) error {
+	if b.SMTP == nil || b.SMTP.Host == "" || b.SMTP.From == "" {
+		return fmt.Errorf("%w: smtp.host and smtp.from", ErrMissingField)
+	}
+	if b.IMAP != nil && (b.IMAP.Host == "" || b.IMAP.Username == "") {
+		return fmt.Errorf("%w: imap.host and imap.username", ErrMissingField)
+	}
+	for _, mailbox := range b.Mailboxes {
+		if strings.TrimSpace(mailbox) == "" {
+			return fmt.Errorf("%w: mailboxes cannot contain an empty address", ErrMissingField)
+		}
+	}
+	return nil
+}
+
+// validateMailRule checks a single mail rule.
+func validateMailRule(rule MailRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name", ErrMissingField)
+	}
+	if !slices.Contains(MailRuleActions, rule.Action) {
+		return fmt.Errorf("unknown action %q (valid: %s)", rule.Action, strings.Join(MailRuleActions, ", "))
+	}
+	if rule.Action == MailRuleForward && rule.Target == "" {
+		return fmt.Errorf("%w: forward requires a target", ErrMissingField)
+	}
+	if rule.Match.Subject != "" {
+		if _, err := regexp.Compile(rule.Match.Subject); err != nil {
+			return fmt.Errorf("invalid subject pattern: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1163,6 +1233,42 @@ func ValidateAgentConfig(agentName string, townSettings *TownSettings, rigSettin
 	return nil
 }
 
+// ValidateModelRouting checks model routing rules and the escalation ladder.
+// Agent names are resolved at spawn time, where rig-level agents are known.
+func ValidateModelRouting(c *ModelRoutingConfig) error {
+	if c == nil {
+		return nil
+	}
+	for i, r := range c.Rules {
+		name := r.label(i)
+		if r.Agent == "" {
+			return fmt.Errorf("%w: model_routing rule %s: agent", ErrMissingField, name)
+		}
+		for _, p := range r.Formulas {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("model_routing rule %s: invalid formula pattern %q: %w", name, p, err)
+			}
+		}
+		if r.MinEstimate < 0 || r.MaxEstimate < 0 {
+			return fmt.Errorf("model_routing rule %s: estimates must be non-negative", name)
+		}
+		if r.MaxEstimate > 0 && r.MinEstimate > r.MaxEstimate {
+			return fmt.Errorf("model_routing rule %s: min_estimate %d exceeds max_estimate %d", name, r.MinEstimate, r.MaxEstimate)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, a := range c.Escalation {
+		if a == "" {
+			return fmt.Errorf("model_routing escalation: empty agent name")
+		}
+		if seen[a] {
+			return fmt.Errorf("model_routing escalation: %q listed twice", a)
+		}
+		seen[a] = true
+	}
+	return nil
+}
+
 // lookupAgentConfigIfExists looks up an agent by name but returns nil if not found
 // (instead of falling back to default). Used for validation.
 func lookupAgentConfigIfExists(name string, townSettings *TownSettings, rigSettings *RigSettings) *RuntimeConfig {
@@ -2402,9 +2508,69 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if d := c.Delivery; d != nil {
+		if d.RetryBackoff != "" {
+			if _, err := time.ParseDuration(d.RetryBackoff); err != nil {
+				return fmt.Errorf("invalid delivery.retry_backoff: %w", err)
+			}
+		}
+		if d.MaxAttempts != nil && *d.MaxAttempts < 1 {
+			return fmt.Errorf("%w: delivery.max_attempts must be at least 1", ErrMissingField)
+		}
+		for channel, rl := range d.RateLimits {
+			if rl.Max < 1 {
+				return fmt.Errorf("%w: delivery.rate_limits.%s.max must be at least 1", ErrMissingField, channel)
+			}
+			if _, err := time.ParseDuration(rl.Window); err != nil {
+				return fmt.Errorf("invalid delivery.rate_limits.%s.window: %w", channel, err)
+			}
+		}
+		if d.SMTP != nil && (d.SMTP.Host == "" || d.SMTP.From == "") {
+			return fmt.Errorf("%w: delivery.smtp requires host and from", ErrMissingField)
+		}
+		if d.SMSGateway != nil && d.SMSGateway.URL == "" {
+			return fmt.Errorf("%w: delivery.sms_gateway requires url", ErrMissingField)
+		}
+	}
+
 	return nil
 }
 
+// GetDeliveryLogFile returns the absolute path of the escalation log file.
+// Defaults to <townRoot>/logs/escalations.log.
+func (c *EscalationConfig) GetDeliveryLogFile(townRoot string) string {
+	path := filepath.Join("logs", "escalations.log")
+	if c.Delivery != nil && c.Delivery.LogFile != "" {
+		path = c.Delivery.LogFile
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(townRoot, path)
+}
+
+// GetDeliveryMaxAttempts returns how many times a delivery is attempted.
+// Returns 3 if not configured.
+func (c *EscalationConfig) GetDeliveryMaxAttempts() int {
+	if c.Delivery == nil || c.Delivery.MaxAttempts == nil {
+		return 3
+	}
+	return *c.Delivery.MaxAttempts
+}
+
+// GetDeliveryRetryBackoff returns the initial delay between delivery attempts.
+// Returns 2 seconds if not configured or invalid.
+func (c *EscalationConfig) GetDeliveryRetryBackoff() time.Duration {
+	if c.Delivery == nil || c.Delivery.RetryBackoff == "" {
+		return 2 * time.Second
+	}
+	d, err := time.ParseDuration(c.Delivery.RetryBackoff)
+	if err != nil {
+		return 2 * time.Second
+	}
+	return d
+}
+
 // GetStaleThreshold returns the stale threshold as a time.Duration.
 // Returns 4 hours if not configured or invalid.
 func (c *EscalationConfig) GetStaleThreshold() time.Duration {
@@ -328,6 +328,28 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: MergeStrategyMergeCommit,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: "octopus",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -778,6 +800,82 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid mail rules",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{
+					"mayor/": {
+						{Name: "noise", Match: MailRuleMatch{Subject: "^PATROL"}, Action: MailRuleArchive},
+						{Name: "help", Match: MailRuleMatch{Subject: "^HELP"}, Action: MailRuleForward, Target: "overseer"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "mail rule with unknown action",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Name: "x", Action: "delete"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mail rule forward without target",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Name: "x", Action: MailRuleForward}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mail rule with bad subject pattern",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Name: "x", Match: MailRuleMatch{Subject: "("}, Action: MailRuleArchive}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate mail rule names",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{"mayor/": {
+					{Name: "x", Action: MailRuleArchive},
+					{Name: "x", Action: MailRuleMarkRead},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid mail bridge",
+			config: &MessagingConfig{
+				Version: 1,
+				Bridge: &MailBridgeConfig{
+					Enabled: true,
+					SMTP:    &SMTPConfig{Host: "smtp.example.com", From: "gastown@example.com"},
+					IMAP:    &IMAPConfig{Host: "imap.example.com", Username: "gastown@example.com"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "mail bridge without smtp",
+			config: &MessagingConfig{
+				Version: 1,
+				Bridge:  &MailBridgeConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "disabled mail bridge is not validated",
+			config: &MessagingConfig{
+				Version: 1,
+				Bridge:  &MailBridgeConfig{},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3891,6 +3989,63 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "valid delivery",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Delivery: &EscalationDelivery{
+					SMTP:         &SMTPConfig{Host: "smtp.example.com", From: "gt@example.com"},
+					SMSGateway:   &SMSGatewayConfig{URL: "https://sms.example.com"},
+					MaxAttempts:  intPtr(2),
+					RetryBackoff: "1s",
+					RateLimits:   map[string]RateLimitConfig{"sms": {Max: 5, Window: "1h"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid delivery retry backoff",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{RetryBackoff: "soon"},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.retry_backoff",
+		},
+		{
+			name: "zero delivery max attempts",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{MaxAttempts: intPtr(0)},
+			},
+			wantErr: true,
+			errMsg:  "delivery.max_attempts must be at least 1",
+		},
+		{
+			name: "invalid rate limit window",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Delivery: &EscalationDelivery{
+					RateLimits: map[string]RateLimitConfig{"sms": {Max: 1, Window: "daily"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.rate_limits.sms.window",
+		},
+		{
+			name: "smtp missing host",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{SMTP: &SMTPConfig{From: "gt@example.com"}},
+			},
+			wantErr: true,
+			errMsg:  "delivery.smtp requires host and from",
+		},
 	}
 
 	for _, tt := range tests {
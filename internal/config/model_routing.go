@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ModelRoutingConfig picks the agent (and so the model) for each polecat from
+// the bead it is slung. Cost tiers map whole roles to models; routing refines
+// the polecat role per bead, e.g. haiku for lint fixes, opus for design work.
+//
+// Example (settings/config.json):
+//
+//	"model_routing": {
+//	  "rules": [
+//	    {"name": "chores", "labels": ["lint", "typo"], "agent": "claude-haiku"},
+//	    {"name": "small", "max_estimate": 30, "agent": "claude-sonnet"},
+//	    {"name": "design", "types": ["epic", "feature"], "agent": "claude"}
+//	  ],
+//	  "escalation": ["claude-haiku", "claude-sonnet", "claude"]
+//	}
+type ModelRoutingConfig struct {
+	// Rules are checked in order; the first matching rule picks the agent.
+	// When no rule matches, the polecat role's normal agent is used.
+	Rules []ModelRoute `json:"rules,omitempty"`
+
+	// Escalation lists agents from cheapest to strongest. When a bead is
+	// re-dispatched after an attempt with one of these agents failed (its
+	// polecat died or was abandoned without finishing), the next stronger
+	// agent is used (unless the rules already pick a stronger one).
+	Escalation []string `json:"escalation,omitempty"`
+}
+
+// ModelRoute is one routing rule. All set conditions must hold; within a
+// list, any entry matches. A rule with no conditions matches every bead.
+type ModelRoute struct {
+	// Name identifies the rule in sling output and gt config model-routing.
+	Name string `json:"name,omitempty"`
+
+	// Labels matches beads carrying any of these labels.
+	Labels []string `json:"labels,omitempty"`
+
+	// Types matches beads of any of these issue types (task, bug, feature, ...).
+	Types []string `json:"types,omitempty"`
+
+	// Formulas matches the formula applied at sling time. Entries are
+	// path.Match patterns, e.g. "mol-polecat-*".
+	Formulas []string `json:"formulas,omitempty"`
+
+	// MinEstimate and MaxEstimate bound the bead's estimated_minutes
+	// (inclusive). Beads without an estimate never match a size bound.
+	MinEstimate int `json:"min_estimate,omitempty"`
+	MaxEstimate int `json:"max_estimate,omitempty"`
+
+	// Agent is the agent preset or custom agent to run the polecat with.
+	Agent string `json:"agent"`
+}
+
+// BeadTraits are the bead properties routing rules match on.
+type BeadTraits struct {
+	Labels           []string
+	Type             string
+	Formula          string
+	EstimatedMinutes int
+}
+
+// Matches reports whether the rule applies to a bead.
+func (r *ModelRoute) Matches(b BeadTraits) bool {
+	if len(r.Labels) > 0 && !slices.ContainsFunc(r.Labels, func(l string) bool { return slices.Contains(b.Labels, l) }) {
+		return false
+	}
+	if len(r.Types) > 0 && !slices.Contains(r.Types, b.Type) {
+		return false
+	}
+	if len(r.Formulas) > 0 && !slices.ContainsFunc(r.Formulas, func(p string) bool {
+		ok, _ := path.Match(p, b.Formula)
+		return ok
+	}) {
+		return false
+	}
+	if r.MinEstimate > 0 || r.MaxEstimate > 0 {
+		if b.EstimatedMinutes <= 0 {
+			return false
+		}
+		if r.MinEstimate > 0 && b.EstimatedMinutes < r.MinEstimate {
+			return false
+		}
+		if r.MaxEstimate > 0 && b.EstimatedMinutes > r.MaxEstimate {
+			return false
+		}
+	}
+	return true
+}
+
+// label returns the rule's name for display, or its position if unnamed.
+func (r *ModelRoute) label(i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// RouteModel picks the agent for a polecat working on a bead. failedAgent
+// is the agent of the bead's last failed attempt, or empty if none failed.
+// Re-dispatching a bead for any other reason doesn't escalate. Returns an
+// empty agent when neither a rule nor escalation applies, meaning the role's
+// normal agent should be used. reason explains the choice for sling output.
+func (c *ModelRoutingConfig) RouteModel(b BeadTraits, failedAgent string) (agent, reason string) {
+	if c == nil {
+		return "", ""
+	}
+	for i := range c.Rules {
+		if c.Rules[i].Matches(b) {
+			agent = c.Rules[i].Agent
+			reason = "rule " + c.Rules[i].label(i)
+			break
+		}
+	}
+
+	prev := slices.Index(c.Escalation, failedAgent)
+	if failedAgent == "" || prev < 0 {
+		return agent, reason
+	}
+	// Retry: never go below the agent that already failed, and step up one
+	// rung when there is one. An agent off the ladder counts as weakest.
+	target := min(prev+1, len(c.Escalation)-1)
+	if slices.Index(c.Escalation, agent) < target {
+		agent = c.Escalation[target]
+		if target > prev {
+			reason = fmt.Sprintf("escalated from %s after failed attempt", failedAgent)
+		} else {
+			reason = fmt.Sprintf("retry with strongest agent after %s failed", failedAgent)
+		}
+	}
+	return agent, reason
+}
+
+// FormatModelRoutes returns a table of routing rules and the escalation ladder.
+func FormatModelRoutes(c *ModelRoutingConfig) string {
+	if c == nil || (len(c.Rules) == 0 && len(c.Escalation) == 0) {
+		return "  (no model routing configured)"
+	}
+	var lines []string
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		var conds []string
+		if len(r.Labels) > 0 {
+			conds = append(conds, "labels="+strings.Join(r.Labels, "|"))
+		}
+		if len(r.Types) > 0 {
+			conds = append(conds, "types="+strings.Join(r.Types, "|"))
+		}
+		if len(r.Formulas) > 0 {
+			conds = append(conds, "formulas="+strings.Join(r.Formulas, "|"))
+		}
+		if r.MinEstimate > 0 {
+			conds = append(conds, fmt.Sprintf("estimate>=%dm", r.MinEstimate))
+		}
+		if r.MaxEstimate > 0 {
+			conds = append(conds, fmt.Sprintf("estimate<=%dm", r.MaxEstimate))
+		}
+		if len(conds) == 0 {
+			conds = append(conds, "any bead")
+		}
+		lines = append(lines, fmt.Sprintf("  %-10s %-40s → %s", r.label(i), strings.Join(conds, " "), r.Agent))
+	}
+	if len(c.Escalation) > 0 {
+		lines = append(lines, "  escalation: "+strings.Join(c.Escalation, " → "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouteModel(t *testing.T) {
+	routing := &ModelRoutingConfig{
+		Rules: []ModelRoute{
+			{Name: "chores", Labels: []string{"lint", "typo"}, Agent: "claude-haiku"},
+			{Name: "small", MaxEstimate: 30, Agent: "claude-sonnet"},
+			{Name: "design", Types: []string{"epic", "feature"}, Agent: "claude"},
+			{Name: "review", Formulas: []string{"mol-review-*"}, Agent: "claude-sonnet"},
+		},
+		Escalation: []string{"claude-haiku", "claude-sonnet", "claude"},
+	}
+
+	tests := []struct {
+		name       string
+		bead       BeadTraits
+		failed     string
+		wantAgent  string
+		wantReason string
+	}{
+		{"label match", BeadTraits{Labels: []string{"ci", "lint"}, Type: "task"}, "", "claude-haiku", "rule chores"},
+		{"first rule wins", BeadTraits{Labels: []string{"typo"}, Type: "feature", EstimatedMinutes: 10}, "", "claude-haiku", "rule chores"},
+		{"small estimate", BeadTraits{Type: "task", EstimatedMinutes: 30}, "", "claude-sonnet", "rule small"},
+		{"large estimate falls through", BeadTraits{Type: "feature", EstimatedMinutes: 240}, "", "claude", "rule design"},
+		{"no estimate never matches size", BeadTraits{Type: "bug"}, "", "", ""},
+		{"formula glob", BeadTraits{Type: "bug", Formula: "mol-review-pr"}, "", "claude-sonnet", "rule review"},
+		{"escalate on retry", BeadTraits{Labels: []string{"lint"}}, "claude-haiku", "claude-sonnet", "escalated from claude-haiku after failed attempt"},
+		{"escalate unmatched bead", BeadTraits{Type: "bug"}, "claude-sonnet", "claude", "escalated from claude-sonnet after failed attempt"},
+		{"stay at strongest", BeadTraits{Labels: []string{"lint"}}, "claude", "claude", "retry with strongest agent after claude failed"},
+		{"rule already stronger", BeadTraits{Type: "epic"}, "claude-haiku", "claude", "rule design"},
+		{"failed agent off ladder", BeadTraits{Labels: []string{"lint"}}, "gemini", "claude-haiku", "rule chores"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, reason := routing.RouteModel(tt.bead, tt.failed)
+			if agent != tt.wantAgent || reason != tt.wantReason {
+				t.Errorf("RouteModel() = (%q, %q), want (%q, %q)", agent, reason, tt.wantAgent, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRouteModel_NilConfig(t *testing.T) {
+	var routing *ModelRoutingConfig
+	if agent, reason := routing.RouteModel(BeadTraits{Labels: []string{"lint"}}, "claude-haiku"); agent != "" || reason != "" {
+		t.Errorf("nil config routed to (%q, %q)", agent, reason)
+	}
+}
+
+func TestValidateModelRouting(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing *ModelRoutingConfig
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"valid", &ModelRoutingConfig{
+			Rules:      []ModelRoute{{Labels: []string{"lint"}, Agent: "claude-haiku"}, {MinEstimate: 10, MaxEstimate: 60, Agent: "claude-sonnet"}},
+			Escalation: []string{"claude-haiku", "claude"},
+		}, ""},
+		{"missing agent", &ModelRoutingConfig{Rules: []ModelRoute{{Name: "chores", Labels: []string{"lint"}}}}, "rule chores: agent"},
+		{"bad formula pattern", &ModelRoutingConfig{Rules: []ModelRoute{{Formulas: []string{"mol-["}, Agent: "claude"}}}, "invalid formula pattern"},
+		{"inverted estimates", &ModelRoutingConfig{Rules: []ModelRoute{{MinEstimate: 60, MaxEstimate: 10, Agent: "claude"}}}, "exceeds max_estimate"},
+		{"duplicate rung", &ModelRoutingConfig{Escalation: []string{"claude", "claude"}}, "listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateModelRouting(tt.routing)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateModelRouting() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateModelRouting() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -89,6 +89,10 @@ type TownSettings struct {
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
 
+	// ModelRouting picks the polecat agent per bead (labels, type, formula,
+	// estimate) and escalates to a stronger agent when a bead is retried.
+	ModelRouting *ModelRoutingConfig `json:"model_routing,omitempty"`
+
 	// Scheduler configures the capacity scheduler for polecat dispatch.
 	Scheduler *capacity.SchedulerConfig `json:"scheduler,omitempty"`
 
@@ -96,6 +100,32 @@ type TownSettings struct {
 	// These were previously hardcoded as Go constants throughout the codebase.
 	// All values are optional — omitted values use compiled-in defaults.
 	Operational *OperationalConfig `json:"operational,omitempty"`
+
+	// Budgets caps token consumption per account and per rig. The capacity
+	// scheduler stops dispatching polecats whose account or rig is projected
+	// to exceed its budget.
+	Budgets *BudgetConfig `json:"budgets,omitempty"`
+}
+
+// BudgetConfig configures token budgets (settings/config.json "budgets").
+type BudgetConfig struct {
+	// Lookahead is how far ahead the scheduler projects usage at the current
+	// burn rate when deciding whether to dispatch. Default: "1h".
+	Lookahead string `json:"lookahead,omitempty"`
+
+	// Accounts maps account handles (from mayor/accounts.json) to budgets.
+	Accounts map[string]*TokenBudget `json:"accounts,omitempty"`
+
+	// Rigs maps rig names to budgets. A rig's usage is the usage of every
+	// session working under the rig's directory, across all accounts.
+	Rigs map[string]*TokenBudget `json:"rigs,omitempty"`
+}
+
+// TokenBudget is a token cap per calendar day and per week (Monday-based,
+// local time). Cache reads are not counted. Zero means no cap.
+type TokenBudget struct {
+	DailyTokens  int64 `json:"daily_tokens,omitempty"`
+	WeeklyTokens int64 `json:"weekly_tokens,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -1080,6 +1110,13 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy selects how MRs land on their target branch:
+	// "squash" (one commit, message from the branch head), "merge-commit"
+	// (--no-ff merge with Issue/Polecat trailers), or "rebase" (rebase and
+	// fast-forward, preserving commits). An MR bead's merge_strategy field
+	// overrides this per MR. Empty means DefaultMergeStrategy.
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -1123,6 +1160,26 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge strategy constants.
+const (
+	MergeStrategySquash      = "squash"
+	MergeStrategyMergeCommit = "merge-commit"
+	MergeStrategyRebase      = "rebase"
+
+	// DefaultMergeStrategy is used when neither the rig nor the MR sets one.
+	// The refinery patrol formula's merge_strategy default must match.
+	DefaultMergeStrategy = MergeStrategySquash
+)
+
+// IsValidMergeStrategy reports whether s is a known merge strategy.
+func IsValidMergeStrategy(s string) bool {
+	switch s {
+	case MergeStrategySquash, MergeStrategyMergeCommit, MergeStrategyRebase:
+		return true
+	}
+	return false
+}
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -1268,6 +1325,14 @@ type AccountQuotaState struct {
 	LimitedAt string             `json:"limited_at,omitempty"` // RFC3339 when limit was detected
 	ResetsAt  string             `json:"resets_at,omitempty"`  // Human-readable reset time from provider (e.g. "7pm (America/Los_Angeles)")
 	LastUsed  string             `json:"last_used,omitempty"`  // RFC3339 when account was last assigned to a session
+
+	// CooldownUntil is the RFC3339 time a cooldown account becomes available again.
+	CooldownUntil string `json:"cooldown_until,omitempty"`
+
+	// LastLimited is the RFC3339 time of the most recent rate limit. Unlike
+	// LimitedAt it survives clearing, so rotation can prefer accounts that
+	// were limited longest ago.
+	LastLimited string `json:"last_limited,omitempty"`
 }
 
 // CurrentQuotaVersion is the current schema version for QuotaState.
@@ -1298,6 +1363,130 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Rules are per-mailbox filters evaluated when mail is delivered, keyed by
+	// recipient address. Keys support wildcards: "*/witness" applies to every
+	// rig's witness. The first matching rule of a mailbox wins.
+	// Example: {"mayor/": [{"name": "patrol-noise", "match": {"subject": "^PATROL"}, "action": "archive"}]}
+	Rules map[string][]MailRule `json:"rules,omitempty"`
+
+	// Bridge relays mail for chosen mailboxes to an external email inbox
+	// over SMTP and ingests email replies back into their threads over IMAP.
+	// Optional; nil or disabled means no bridge.
+	Bridge *MailBridgeConfig `json:"bridge,omitempty"`
+}
+
+// Mail rule actions.
+const (
+	MailRuleArchive        = "archive"         // file straight into the mailbox archive
+	MailRuleMarkRead       = "mark-read"       // deliver already read
+	MailRuleForward        = "forward"         // deliver to Target instead
+	MailRuleEscalate       = "escalate"        // deliver as urgent interrupt, copy to Target if set
+	MailRuleNudge          = "nudge"           // deliver as a nudge when the recipient has a live session
+	MailRuleAttachMolecule = "attach-molecule" // attach the molecule named in the body to the recipient's hook
+)
+
+// MailRuleActions lists the valid mail rule actions.
+var MailRuleActions = []string{
+	MailRuleArchive, MailRuleMarkRead, MailRuleForward,
+	MailRuleEscalate, MailRuleNudge, MailRuleAttachMolecule,
+}
+
+// MailRule matches incoming mail for a mailbox and applies an action.
+type MailRule struct {
+	// Name identifies the rule within its mailbox.
+	Name string `json:"name"`
+
+	// Match selects the messages the rule applies to.
+	Match MailRuleMatch `json:"match"`
+
+	// Action is one of MailRuleActions.
+	Action string `json:"action"`
+
+	// Target is the address for forward (required) and escalate (optional).
+	Target string `json:"target,omitempty"`
+
+	// Disabled rules are kept but not evaluated.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// MailRuleMatch is the condition of a mail rule. Every set field must match;
+// an empty match matches all mail.
+type MailRuleMatch struct {
+	// From is a sender address pattern ('*' matches one path segment).
+	From string `json:"from,omitempty"`
+
+	// Subject is a case-insensitive regular expression.
+	Subject string `json:"subject,omitempty"`
+
+	// Type is a message type (task, scavenge, notification, reply).
+	Type string `json:"type,omitempty"`
+
+	// Priority is a message priority (urgent, high, normal, low).
+	Priority string `json:"priority,omitempty"`
+
+	// Labels must all be present on the message bead (e.g. "thread:abc").
+	Labels []string `json:"labels,omitempty"`
+
+	// Thread is a thread ID.
+	Thread string `json:"thread,omitempty"`
+}
+
+// MailBridgeConfig configures the email bridge. Unread mail in the bridged
+// mailboxes is sent to EmailTo; replies to those emails are read from the
+// IMAP mailbox and delivered back into the original thread.
+type MailBridgeConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Mailboxes whose mail is relayed. Entries may be mailbox addresses,
+	// @groups or list:<name>. Default: ["overseer"].
+	Mailboxes []string `json:"mailboxes,omitempty"`
+
+	// EmailTo is the inbox relayed mail is sent to.
+	// Default: the overseer's email from mayor/overseer.json.
+	EmailTo string `json:"email_to,omitempty"`
+
+	// SMTP sends relayed mail. SMTP.From is the bridge's address, and
+	// should be the IMAP account so that replies come back to the bridge.
+	SMTP *SMTPConfig `json:"smtp"`
+
+	// IMAP is polled for replies. Without it the bridge only relays.
+	IMAP *IMAPConfig `json:"imap,omitempty"`
+
+	// AllowFrom lists the email addresses whose replies are accepted.
+	// Replies from anyone else are ignored. Default: [EmailTo].
+	// Matching the From header is not authentication: a reply must also
+	// pass DKIM, SPF or DMARC for the From domain, as recorded in the
+	// receiving server's Authentication-Results header.
+	AllowFrom []string `json:"allow_from,omitempty"`
+
+	// TrustFromHeader accepts AllowFrom matches without checking sender
+	// authentication. The From header is trivially forged, so only set this
+	// for a private server that adds no Authentication-Results headers.
+	TrustFromHeader bool `json:"trust_from_header,omitempty"`
+}
+
+// IMAPConfig describes the IMAP account the mail bridge reads replies from.
+type IMAPConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"` // default: 993 (143 when insecure)
+
+	Username string `json:"username"`
+
+	// PasswordEnv names the environment variable holding the IMAP password,
+	// so the secret never lives in config/messaging.json.
+	PasswordEnv string `json:"password_env,omitempty"`
+
+	// Mailbox is the folder replies arrive in (default: INBOX).
+	Mailbox string `json:"mailbox,omitempty"`
+
+	// AuthServID is the authserv-id of the server that receives replies
+	// (e.g. "mx.google.com"). Only Authentication-Results headers it added
+	// are trusted. Default: the topmost Authentication-Results header.
+	AuthServID string `json:"auth_serv_id,omitempty"`
+
+	// Insecure connects without TLS. Only for local servers and test stand-ins.
+	Insecure bool `json:"insecure,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
@@ -1349,12 +1538,18 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "webhook"     → POST JSON to contacts.webhook_url
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Delivery configures the transports used by external notification
+	// actions (SMTP server, SMS gateway, log file, retries, rate limits).
+	// Optional: actions whose transport is not configured are skipped.
+	Delivery *EscalationDelivery `json:"delivery,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1372,6 +1567,63 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+	WebhookURL   string `json:"webhook_url,omitempty"`   // generic JSON webhook for webhook action
+}
+
+// EscalationDelivery configures transports for external escalation actions.
+type EscalationDelivery struct {
+	// SMTP is the mail server used by email:<target> actions.
+	SMTP *SMTPConfig `json:"smtp,omitempty"`
+
+	// SMSGateway is the HTTP gateway used by sms:<target> actions.
+	SMSGateway *SMSGatewayConfig `json:"sms_gateway,omitempty"`
+
+	// LogFile is the append-only escalation log written by the log action.
+	// Relative paths are resolved against the town root.
+	// Default: "logs/escalations.log"
+	LogFile string `json:"log_file,omitempty"`
+
+	// MaxAttempts is how many times a failed delivery is tried per channel.
+	// Default: 3
+	MaxAttempts *int `json:"max_attempts,omitempty"`
+
+	// RetryBackoff is the initial delay between attempts; it doubles after
+	// each failure. Format: Go duration string. Default: "2s"
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+
+	// RateLimits caps deliveries per channel ("email", "sms", "slack",
+	// "webhook", "log"). Channels without an entry are not rate limited.
+	RateLimits map[string]RateLimitConfig `json:"rate_limits,omitempty"`
+}
+
+// SMTPConfig describes an SMTP relay for escalation email.
+type SMTPConfig struct {
+	Host string `json:"host"`           // SMTP server hostname
+	Port int    `json:"port,omitempty"` // default: 587
+	From string `json:"from"`           // envelope and header sender
+
+	// Username for SMTP AUTH PLAIN. Leave empty for unauthenticated relays.
+	Username string `json:"username,omitempty"`
+
+	// PasswordEnv names the environment variable holding the SMTP password,
+	// so the secret never lives in settings/escalation.json.
+	PasswordEnv string `json:"password_env,omitempty"`
+}
+
+// SMSGatewayConfig describes an HTTP SMS gateway.
+// The gateway receives a JSON POST of {"to": "<number>", "body": "<text>"}.
+type SMSGatewayConfig struct {
+	URL string `json:"url"`
+
+	// TokenEnv names the environment variable holding a bearer token
+	// sent as the Authorization header. Optional.
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// RateLimitConfig limits how many deliveries a channel makes per window.
+type RateLimitConfig struct {
+	Max    int    `json:"max"`    // deliveries allowed per window
+	Window string `json:"window"` // Go duration string (e.g., "1h")
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
@@ -0,0 +1,715 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// remoteTmuxSocket is the tmux socket used on remote machines. Towns always
+// run on the "default" socket (see session.InitRegistry), so remote towns do too.
+const remoteTmuxSocket = "default"
+
+// maxSessionsPerClient bounds concurrent SSH channels on one pooled client.
+// OpenSSH's MaxSessions defaults to 10; stay under it so callers block
+// instead of getting "administratively prohibited" errors.
+const maxSessionsPerClient = 8
+
+// sshDialTimeout bounds TCP connect plus SSH handshake.
+const sshDialTimeout = 15 * time.Second
+
+// SSHConnection implements Connection for a remote machine over SSH.
+//
+// All operations run as shell commands on the remote host over a pooled
+// *ssh.Client: one TCP connection per user@host, multiplexing a bounded
+// number of concurrent sessions. A dead client is dropped from the pool
+// and redialed on the next operation.
+type SSHConnection struct {
+	machine   *Machine
+	user      string
+	addr      string
+	config    *ssh.ClientConfig
+	agentSock string // ssh-agent socket, dialed per handshake; "" = none
+}
+
+// NewSSHConnection creates a connection for an ssh machine.
+// Authentication uses Machine.KeyPath when set, plus any keys offered by a
+// running ssh-agent. Host keys are verified against ~/.ssh/known_hosts.
+// No network I/O happens until the first operation.
+func NewSSHConnection(m *Machine) (*SSHConnection, error) {
+	if m.Host == "" {
+		return nil, fmt.Errorf("ssh machine %s requires host", m.Name)
+	}
+
+	var auths []ssh.AuthMethod
+	if m.KeyPath != "" {
+		keyData, err := os.ReadFile(util.ExpandHome(m.KeyPath)) //nolint:gosec // G304: key path comes from the machine registry
+		if err != nil {
+			return nil, fmt.Errorf("reading ssh key for %s: %w", m.Name, err)
+		}
+		signer, err := ssh.ParsePrivateKey(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ssh key %s: %w", m.KeyPath, err)
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	}
+	agentSock := os.Getenv("SSH_AUTH_SOCK")
+	if agentSock != "" {
+		conn, err := net.Dial("unix", agentSock)
+		if err != nil {
+			agentSock = ""
+		} else {
+			_ = conn.Close()
+		}
+	}
+	if len(auths) == 0 && agentSock == "" {
+		return nil, fmt.Errorf("ssh machine %s: no key_path configured and no ssh-agent available", m.Name)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("finding home directory: %w", err)
+	}
+	hostKeys, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("loading known_hosts (connect once with ssh to record the host key): %w", err)
+	}
+
+	c, err := newSSHConnection(m, &ssh.ClientConfig{
+		Auth:            auths,
+		HostKeyCallback: hostKeys,
+		Timeout:         sshDialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.agentSock = agentSock
+	return c, nil
+}
+
+// newSSHConnection builds a connection with an explicit client config.
+// The User field is filled in from Machine.Host.
+func newSSHConnection(m *Machine, config *ssh.ClientConfig) (*SSHConnection, error) {
+	userName, addr, err := parseSSHHost(m.Host)
+	if err != nil {
+		return nil, fmt.Errorf("ssh machine %s: %w", m.Name, err)
+	}
+	cfg := *config
+	cfg.User = userName
+	return &SSHConnection{
+		machine: m,
+		user:    userName,
+		addr:    addr,
+		config:  &cfg,
+	}, nil
+}
+
+// parseSSHHost splits "user@host[:port]" into a user and dial address.
+// The user defaults to the local user and the port to 22.
+func parseSSHHost(host string) (userName, addr string, err error) {
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		userName, host = host[:at], host[at+1:]
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("invalid host %q", host)
+	}
+	if userName == "" {
+		if u, uerr := user.Current(); uerr == nil {
+			userName = u.Username
+		}
+	}
+	if _, _, splitErr := net.SplitHostPort(host); splitErr != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	return userName, host, nil
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Close drops this connection's pooled client. Other SSHConnections to the
+// same user@host share the client and will transparently redial.
+func (c *SSHConnection) Close() error {
+	return defaultSSHPool.drop(c.poolKey())
+}
+
+// ReadFile reads the named remote file.
+func (c *SSHConnection) ReadFile(p string) ([]byte, error) {
+	p = c.resolve(p)
+	stdout, stderr, err := c.run("cat -- "+shellQuote(p), nil)
+	if err != nil {
+		return nil, c.mapFileError(err, stderr, p, "read")
+	}
+	return stdout, nil
+}
+
+// WriteFile writes data to the named remote file.
+// Like os.WriteFile, perm applies only when the file is created.
+func (c *SSHConnection) WriteFile(p string, data []byte, perm fs.FileMode) error {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("if [ -e %s ]; then cat > %s; else (umask 077 && cat > %s) && chmod %o %s; fi",
+		q, q, q, perm.Perm(), q)
+	_, stderr, err := c.run(script, data)
+	if err != nil {
+		return c.mapFileError(err, stderr, p, "write")
+	}
+	return nil
+}
+
+// MkdirAll creates a remote directory and all parent directories.
+func (c *SSHConnection) MkdirAll(p string, perm fs.FileMode) error {
+	p = c.resolve(p)
+	_, stderr, err := c.run(fmt.Sprintf("mkdir -p -m %o -- %s", perm.Perm(), shellQuote(p)), nil)
+	if err != nil {
+		return c.mapFileError(err, stderr, p, "mkdir")
+	}
+	return nil
+}
+
+// Remove removes the named remote file or empty directory.
+// A missing path is not an error, matching LocalConnection.
+func (c *SSHConnection) Remove(p string) error {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("if [ -d %s ] && [ ! -L %s ]; then rmdir -- %s; else rm -f -- %s; fi", q, q, q, q)
+	_, stderr, err := c.run(script, nil)
+	if err != nil {
+		return c.mapFileError(err, stderr, p, "remove")
+	}
+	return nil
+}
+
+// RemoveAll removes the named remote path and any children.
+func (c *SSHConnection) RemoveAll(p string) error {
+	p = c.resolve(p)
+	_, stderr, err := c.run("rm -rf -- "+shellQuote(p), nil)
+	if err != nil {
+		return c.mapFileError(err, stderr, p, "remove")
+	}
+	return nil
+}
+
+// Stat returns file info for the named remote file, following symlinks.
+// Works with both GNU and BSD stat. The flavor is probed up front rather
+// than falling back on failure, so a real error (not a directory,
+// permission denied) is reported as such instead of as a missing file.
+func (c *SSHConnection) Stat(p string) (FileInfo, error) {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("if stat -c %%s / >/dev/null 2>&1; then stat -L -c '%%s %%f %%Y' -- %s; else stat -L -f '%%z %%Xp %%m' -- %s; fi", q, q)
+	stdout, stderr, err := c.run(script, nil)
+	if err != nil {
+		return nil, c.mapFileError(err, stderr, p, "stat")
+	}
+	return parseStatOutput(path.Base(p), string(stdout))
+}
+
+// parseStatOutput parses "<size> <hex st_mode> <unix mtime>".
+func parseStatOutput(name, out string) (FileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output %q", strings.TrimSpace(out))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat size: %w", err)
+	}
+	rawMode, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mode: %w", err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mtime: %w", err)
+	}
+	mode := modeFromUnix(uint32(rawMode))
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// modeFromUnix converts a raw st_mode to an fs.FileMode.
+func modeFromUnix(raw uint32) fs.FileMode {
+	mode := fs.FileMode(raw & 0777)
+	switch raw & 0170000 {
+	case 0040000:
+		mode |= fs.ModeDir
+	case 0120000:
+		mode |= fs.ModeSymlink
+	case 0010000:
+		mode |= fs.ModeNamedPipe
+	case 0140000:
+		mode |= fs.ModeSocket
+	case 0020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0060000:
+		mode |= fs.ModeDevice
+	}
+	if raw&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if raw&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if raw&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// Glob returns the names of all remote files matching the pattern.
+// Pattern syntax is the same as filepath.Glob.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	pattern = c.resolve(pattern)
+	script := fmt.Sprintf(`for f in %s; do if [ -e "$f" ] || [ -L "$f" ]; then printf '%%s\n' "$f"; fi; done`, globQuote(pattern))
+	stdout, stderr, err := c.run(script, nil)
+	if err != nil {
+		return nil, c.mapFileError(err, stderr, pattern, "glob")
+	}
+	matches := splitLines(string(stdout))
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the remote path exists.
+func (c *SSHConnection) Exists(p string) (bool, error) {
+	p = c.resolve(p)
+	_, stderr, err := c.run("test -e "+shellQuote(p), nil)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitStatus() == 1 {
+		return false, nil
+	}
+	return false, c.mapFileError(err, stderr, p, "stat")
+}
+
+// Exec runs a remote command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.runCombined(commandLine(cmd, args))
+}
+
+// ExecDir runs a remote command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.runCombined("cd " + shellQuote(c.resolve(dir)) + " && " + commandLine(cmd, args))
+}
+
+// ExecEnv runs a remote command with additional environment variables.
+// Variables are passed through env(1) rather than SSH "env" requests,
+// which most sshd configurations reject (AcceptEnv).
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{"env"}
+	for _, k := range keys {
+		parts = append(parts, shellQuote(k+"="+env[k]))
+	}
+	return c.runCombined(strings.Join(parts, " ") + " " + commandLine(cmd, args))
+}
+
+// TmuxNewSession creates a new tmux session on the remote machine.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", c.resolve(dir))
+	}
+	if _, err := c.tmux(args...); err != nil {
+		return err
+	}
+	// Match tmux.NewSession: let the window follow the attaching client's size.
+	_, _ = c.tmux("set-option", "-wt", name, "window-size", "latest")
+	return nil
+}
+
+// TmuxKillSession terminates a remote tmux session and the processes in it.
+// Pane processes and their children get SIGTERM before the session is killed,
+// mirroring tmux.KillSessionWithProcesses on the local side.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	tmuxCmd := commandLine("tmux", []string{"-u", "-L", remoteTmuxSocket})
+	target := shellQuote("=" + name)
+	script := fmt.Sprintf(`for p in $(%s list-panes -s -t %s -F '#{pane_pid}' 2>/dev/null); do pkill -TERM -P "$p" 2>/dev/null; kill -TERM "$p" 2>/dev/null; done; %s kill-session -t %s`,
+		tmuxCmd, target, tmuxCmd, target)
+	_, stderr, err := c.run(script, nil)
+	if err != nil {
+		err = c.mapTmuxError(err, stderr)
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// TmuxSendKeys sends keys to a remote tmux session followed by Enter.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	if _, err := c.tmux("send-keys", "-t", session, "-l", keys); err != nil {
+		return err
+	}
+	// Same debounce as tmux.SendKeys so Enter doesn't race the paste.
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	_, err := c.tmux("send-keys", "-t", session, "Enter")
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	out, err := c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.tmux("has-session", "-t", "="+name)
+	if err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if errors.Is(err, tmux.ErrNoServer) {
+			return nil, nil // No server = no sessions
+		}
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// tmux runs a tmux subcommand on the remote town socket.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	all := append([]string{"-u", "-L", remoteTmuxSocket}, args...)
+	stdout, stderr, err := c.run(commandLine("tmux", all), nil)
+	if err != nil {
+		return "", c.mapTmuxError(err, stderr)
+	}
+	return string(stdout), nil
+}
+
+// mapTmuxError maps remote tmux stderr onto the tmux package sentinels.
+func (c *SSHConnection) mapTmuxError(err error, stderr []byte) error {
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) {
+		return err // already a ConnectionError
+	}
+	msg := strings.TrimSpace(string(stderr))
+	switch {
+	case strings.Contains(msg, "no server running"),
+		strings.Contains(msg, "error connecting to"),
+		strings.Contains(msg, "server exited unexpectedly"):
+		return tmux.ErrNoServer
+	case strings.Contains(msg, "duplicate session"):
+		return tmux.ErrSessionExists
+	case strings.Contains(msg, "session not found"),
+		strings.Contains(msg, "can't find session"):
+		return tmux.ErrSessionNotFound
+	case strings.Contains(msg, "command not found"):
+		return &ConnectionError{Op: "tmux", Machine: c.machine.Name, Err: fmt.Errorf("tmux not installed on remote: %s", msg)}
+	case msg != "":
+		return fmt.Errorf("remote tmux: %s", msg)
+	}
+	return fmt.Errorf("remote tmux: %w", err)
+}
+
+// mapFileError converts a failed remote file command into the connection
+// error types used by LocalConnection.
+func (c *SSHConnection) mapFileError(err error, stderr []byte, p, op string) error {
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) {
+		return err // already a ConnectionError
+	}
+	msg := strings.TrimSpace(string(stderr))
+	switch {
+	case strings.Contains(msg, "No such file or directory"):
+		return &NotFoundError{Path: p}
+	case strings.Contains(msg, "Permission denied"), strings.Contains(msg, "Operation not permitted"):
+		return &PermissionError{Path: p, Op: op}
+	case msg != "":
+		return fmt.Errorf("%s %s: %s", op, p, msg)
+	}
+	return fmt.Errorf("%s %s: %w", op, p, err)
+}
+
+// resolve makes relative paths relative to the remote town root.
+func (c *SSHConnection) resolve(p string) string {
+	if p == "" || path.IsAbs(p) || c.machine.TownPath == "" {
+		return p
+	}
+	return path.Join(c.machine.TownPath, p)
+}
+
+// runCombined runs a command line and returns interleaved stdout and stderr.
+func (c *SSHConnection) runCombined(cmdline string) ([]byte, error) {
+	var out syncBuffer
+	err := c.withSession(func(s *ssh.Session) error {
+		s.Stdout = &out
+		s.Stderr = &out
+		return s.Run(cmdline)
+	})
+	return out.buf.Bytes(), err
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent stdout and stderr
+// copiers of an ssh.Session.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// run runs a command line, optionally feeding stdin, and returns stdout and
+// stderr separately. A non-zero exit yields an *ssh.ExitError; transport
+// failures yield a *ConnectionError.
+func (c *SSHConnection) run(cmdline string, stdin []byte) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	err := c.withSession(func(s *ssh.Session) error {
+		s.Stdout = &stdout
+		s.Stderr = &stderr
+		if stdin != nil {
+			s.Stdin = bytes.NewReader(stdin)
+		}
+		return s.Run(cmdline)
+	})
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// withSession runs fn on a fresh session from the pooled client.
+// If the pooled client turns out to be dead, it is redialed once.
+func (c *SSHConnection) withSession(fn func(*ssh.Session) error) error {
+	key := c.poolKey()
+	for attempt := 0; ; attempt++ {
+		pc, err := defaultSSHPool.get(key, c.dial)
+		if err != nil {
+			return &ConnectionError{Op: "connect", Machine: c.machine.Name, Err: err}
+		}
+
+		pc.sem <- struct{}{}
+		session, err := pc.client.NewSession()
+		if err != nil {
+			<-pc.sem
+			_ = defaultSSHPool.dropClient(key, pc)
+			if attempt == 0 {
+				continue
+			}
+			return &ConnectionError{Op: "session", Machine: c.machine.Name, Err: err}
+		}
+
+		err = fn(session)
+		_ = session.Close()
+		<-pc.sem
+
+		var exitErr *ssh.ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			return err
+		}
+		// ExitMissingError and I/O errors mean the transport died mid-command.
+		// Don't retry: the command may have had side effects.
+		_ = defaultSSHPool.dropClient(key, pc)
+		return &ConnectionError{Op: "exec", Machine: c.machine.Name, Err: err}
+	}
+}
+
+func (c *SSHConnection) poolKey() string {
+	return c.user + "@" + c.addr
+}
+
+// dial opens a new client. The ssh-agent, if any, is only needed to sign
+// during the handshake, so its connection is closed once dialing is done.
+func (c *SSHConnection) dial() (*ssh.Client, error) {
+	config := c.config
+	if c.agentSock != "" {
+		if conn, err := net.Dial("unix", c.agentSock); err == nil {
+			defer conn.Close()
+			cfg := *config
+			cfg.Auth = append(append([]ssh.AuthMethod(nil), config.Auth...),
+				ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			config = &cfg
+		}
+	}
+	return ssh.Dial("tcp", c.addr, config)
+}
+
+// pooledClient is a shared SSH client with a session semaphore.
+type pooledClient struct {
+	client *ssh.Client
+	sem    chan struct{}
+}
+
+// sshPool shares SSH clients across SSHConnections to the same user@host.
+type sshPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+}
+
+var defaultSSHPool = &sshPool{clients: make(map[string]*pooledClient)}
+
+// get returns the pooled client for key, dialing one if there is none.
+// Dialing happens outside the lock so a slow or unreachable host doesn't
+// stall operations on other machines. If two callers dial the same key at
+// once, the first to finish wins and the other closes its client.
+func (p *sshPool) get(key string, dial func() (*ssh.Client, error)) (*pooledClient, error) {
+	p.mu.Lock()
+	pc, ok := p.clients[key]
+	p.mu.Unlock()
+	if ok {
+		return pc, nil
+	}
+
+	client, err := dial()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pc, ok := p.clients[key]; ok {
+		_ = client.Close()
+		return pc, nil
+	}
+	pc = &pooledClient{
+		client: client,
+		sem:    make(chan struct{}, maxSessionsPerClient),
+	}
+	p.clients[key] = pc
+	return pc, nil
+}
+
+// dropClient removes pc from the pool if it is still the current client for key.
+func (p *sshPool) dropClient(key string, pc *pooledClient) error {
+	p.mu.Lock()
+	if cur, ok := p.clients[key]; ok && cur == pc {
+		delete(p.clients, key)
+	}
+	p.mu.Unlock()
+	return pc.client.Close()
+}
+
+func (p *sshPool) drop(key string) error {
+	p.mu.Lock()
+	pc, ok := p.clients[key]
+	delete(p.clients, key)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return pc.client.Close()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commandLine quotes a command and its arguments for a POSIX shell.
+func commandLine(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// globQuote escapes a filepath.Glob pattern for a POSIX shell so that only
+// the glob metacharacters *, ? and [...] are interpreted.
+func globQuote(pattern string) string {
+	runes := []rune(pattern)
+	var b strings.Builder
+	inBracket := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			// filepath.Glob escape: the next character is literal.
+			i++
+			b.WriteByte('\\')
+			b.WriteRune(runes[i])
+		case (r == '*' || r == '?') && !inBracket:
+			b.WriteRune(r)
+		case r == '[' && !inBracket:
+			inBracket = true
+			b.WriteRune(r)
+			// filepath.Glob negates with '^'; sh uses '!'.
+			if i+1 < len(runes) && runes[i+1] == '^' {
+				i++
+				b.WriteByte('!')
+			}
+		case r == ']' && inBracket:
+			inBracket = false
+			b.WriteRune(r)
+		case r == '-' && inBracket:
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '/', r == '.', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// splitLines splits command output into non-empty lines.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
@@ -0,0 +1,534 @@
+package connection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer is a loopback SSH server that runs exec requests with the
+// local /bin/sh, so SSHConnection can be exercised end to end against the
+// local filesystem.
+type testSSHServer struct {
+	addr  string
+	conns atomic.Int32
+}
+
+func startTestSSHServer(t *testing.T) (*testSSHServer, *ssh.ClientConfig) {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPub := clientSigner.PublicKey().Marshal()
+
+	serverCfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientPub) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	serverCfg.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &testSSHServer{addr: ln.Addr().String()}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			srv.conns.Add(1)
+			go srv.serveConn(nc, serverCfg)
+		}
+	}()
+
+	return srv, &ssh.ClientConfig{
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientSigner)},
+		HostKeyCallback: ssh.FixedHostKey(hostSigner.PublicKey()),
+	}
+}
+
+func (s *testSSHServer) serveConn(nc net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "session only")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer ch.Close()
+			for req := range chReqs {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				var payload struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+					_ = req.Reply(false, nil)
+					return
+				}
+				_ = req.Reply(true, nil)
+
+				cmd := exec.Command("sh", "-c", payload.Command)
+				cmd.Stdin = ch
+				cmd.Stdout = ch
+				cmd.Stderr = ch.Stderr()
+				status := uint32(0)
+				if err := cmd.Run(); err != nil {
+					var exitErr *exec.ExitError
+					if errors.As(err, &exitErr) {
+						status = uint32(exitErr.ExitCode())
+					} else {
+						status = 127
+					}
+				}
+				_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
+
+func newTestSSHConnection(t *testing.T, townPath string) (*SSHConnection, *testSSHServer) {
+	t.Helper()
+	srv, cfg := startTestSSHServer(t)
+	m := &Machine{Name: "buildbox", Type: "ssh", Host: "gt@" + srv.addr, TownPath: townPath}
+	c, err := newSSHConnection(m, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, srv
+}
+
+func TestSSHConnection_FileOps(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := newTestSSHConnection(t, dir)
+
+	if c.IsLocal() || c.Name() != "buildbox" {
+		t.Errorf("Name/IsLocal = %q/%v", c.Name(), c.IsLocal())
+	}
+
+	nested := filepath.Join(dir, "a b", "c")
+	if err := c.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	file := filepath.Join(nested, "it's.txt")
+	content := []byte("line one\nline 'two' $HOME\n")
+	if err := c.WriteFile(file, content, 0640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := c.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("ReadFile = %q, want %q", got, content)
+	}
+
+	fi, err := c.Stat(file)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Name() != "it's.txt" || fi.Size() != int64(len(content)) || fi.IsDir() || fi.Mode().Perm() != 0640 {
+		t.Errorf("Stat = %+v", fi)
+	}
+
+	di, err := c.Stat(nested)
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !di.IsDir() || !di.Mode().IsDir() {
+		t.Errorf("Stat dir = %+v", di)
+	}
+
+	// Relative paths resolve against the machine's TownPath.
+	if ok, err := c.Exists("a b/c/it's.txt"); err != nil || !ok {
+		t.Errorf("Exists(relative) = %v, %v", ok, err)
+	}
+	if ok, err := c.Exists(filepath.Join(dir, "nope")); err != nil || ok {
+		t.Errorf("Exists(missing) = %v, %v", ok, err)
+	}
+
+	if err := c.Remove(file); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := c.Remove(file); err != nil {
+		t.Errorf("Remove(missing) should be nil, got %v", err)
+	}
+	if err := c.Remove(nested); err != nil {
+		t.Errorf("Remove(empty dir): %v", err)
+	}
+	if err := c.RemoveAll(filepath.Join(dir, "a b")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a b")); !os.IsNotExist(err) {
+		t.Errorf("RemoveAll left directory behind: %v", err)
+	}
+}
+
+func TestSSHConnection_ErrorMapping(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := newTestSSHConnection(t, dir)
+
+	_, err := c.ReadFile(filepath.Join(dir, "missing.txt"))
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("ReadFile(missing) = %v, want NotFoundError", err)
+	}
+
+	_, err = c.Stat(filepath.Join(dir, "missing.txt"))
+	if !errors.As(err, &nf) {
+		t.Errorf("Stat(missing) = %v, want NotFoundError", err)
+	}
+
+	// A path under a regular file fails with ENOTDIR, which is not "missing".
+	if err := os.WriteFile(filepath.Join(dir, "plain"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Stat(filepath.Join(dir, "plain", "child")); err == nil || errors.As(err, &nf) {
+		t.Errorf("Stat(under file) = %v, want a non-NotFound error", err)
+	}
+
+	if os.Geteuid() != 0 {
+		locked := filepath.Join(dir, "locked")
+		if err := os.WriteFile(locked, []byte("x"), 0000); err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.ReadFile(locked)
+		var pe *PermissionError
+		if !errors.As(err, &pe) || pe.Op != "read" {
+			t.Errorf("ReadFile(unreadable) = %v, want PermissionError", err)
+		}
+	}
+}
+
+func TestSSHConnection_ConnectionError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close() // nothing listening now
+
+	_, cfg := startTestSSHServer(t)
+	c, err := newSSHConnection(&Machine{Name: "gone", Type: "ssh", Host: "gt@" + addr}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Exec("true")
+	var ce *ConnectionError
+	if !errors.As(err, &ce) || ce.Op != "connect" || ce.Machine != "gone" {
+		t.Errorf("Exec on dead host = %v, want ConnectionError{Op: connect}", err)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := newTestSSHConnection(t, dir)
+
+	out, err := c.Exec("echo", "hello world", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "hello world it's" {
+		t.Errorf("Exec = %q", out)
+	}
+
+	out, err = c.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEvalSymlinks(t, dir) {
+		t.Errorf("ExecDir pwd = %q, want %q", out, dir)
+	}
+
+	out, err = c.ExecEnv(map[string]string{"GT_TEST_VAR": "a b", "GT_OTHER": "x"}, "sh", "-c", `echo "$GT_TEST_VAR/$GT_OTHER"`)
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "a b/x" {
+		t.Errorf("ExecEnv = %q", out)
+	}
+
+	out, err = c.Exec("sh", "-c", "echo oops >&2; exit 3")
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 3 {
+		t.Errorf("Exec failing command err = %v, want exit status 3", err)
+	}
+	if !strings.Contains(string(out), "oops") {
+		t.Errorf("combined output should include stderr, got %q", out)
+	}
+}
+
+func TestSSHConnection_Glob(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := newTestSSHConnection(t, dir)
+
+	for _, name := range []string{"a.json", "b.json", "c.txt", "with space.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := c.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	want, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Glob = %v, want %v", got, want)
+	}
+
+	got, err = c.Glob(filepath.Join(dir, "*.none"))
+	if err != nil || len(got) != 0 {
+		t.Errorf("Glob(no match) = %v, %v", got, err)
+	}
+
+	if _, err := c.Glob("[bad"); err == nil {
+		t.Error("Glob with malformed pattern should fail")
+	}
+}
+
+func TestSSHConnection_PoolsClient(t *testing.T) {
+	c, srv := newTestSSHConnection(t, t.TempDir())
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.Exec("true"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := srv.conns.Load(); n != 1 {
+		t.Errorf("server saw %d TCP connections, want 1 (pooled)", n)
+	}
+
+	// After Close the next operation transparently redials.
+	_ = c.Close()
+	if _, err := c.Exec("true"); err != nil {
+		t.Fatalf("Exec after Close: %v", err)
+	}
+	if n := srv.conns.Load(); n != 2 {
+		t.Errorf("server saw %d TCP connections, want 2 after redial", n)
+	}
+}
+
+func TestSSHConnection_Tmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	c, _ := newTestSSHConnection(t, t.TempDir())
+	name := fmt.Sprintf("gt-ssh-test-%d", os.Getpid())
+
+	if err := c.TmuxNewSession(name, t.TempDir()); err != nil {
+		t.Skipf("cannot start tmux server here: %v", err)
+	}
+	defer func() { _ = c.TmuxKillSession(name) }()
+
+	if ok, err := c.TmuxHasSession(name); err != nil || !ok {
+		t.Fatalf("TmuxHasSession = %v, %v", ok, err)
+	}
+	sessions, err := c.TmuxListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range sessions {
+		found = found || s == name
+	}
+	if !found {
+		t.Errorf("TmuxListSessions = %v, missing %s", sessions, name)
+	}
+
+	if err := c.TmuxSendKeys(name, "echo ssh-marker-$((40+2))"); err != nil {
+		t.Fatalf("TmuxSendKeys: %v", err)
+	}
+	var pane string
+	for i := 0; i < 50; i++ {
+		pane, err = c.TmuxCapturePane(name, 20)
+		if err == nil && strings.Contains(pane, "ssh-marker-42") {
+			break
+		}
+		_, _ = c.Exec("sleep", "0.1")
+	}
+	if !strings.Contains(pane, "ssh-marker-42") {
+		t.Errorf("TmuxCapturePane = %q, want command output", pane)
+	}
+
+	if err := c.TmuxKillSession(name); err != nil {
+		t.Fatalf("TmuxKillSession: %v", err)
+	}
+	if ok, _ := c.TmuxHasSession(name); ok {
+		t.Error("session still exists after kill")
+	}
+	if err := c.TmuxKillSession(name); err != nil {
+		t.Errorf("TmuxKillSession(missing) should be nil, got %v", err)
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	tests := []struct {
+		host, user, addr string
+	}{
+		{"deploy@build.example.com", "deploy", "build.example.com:22"},
+		{"deploy@build.example.com:2222", "deploy", "build.example.com:2222"},
+		{"deploy@[::1]:2222", "deploy", "[::1]:2222"},
+		{"deploy@::1", "deploy", "[::1]:22"},
+	}
+	for _, tt := range tests {
+		u, addr, err := parseSSHHost(tt.host)
+		if err != nil || u != tt.user || addr != tt.addr {
+			t.Errorf("parseSSHHost(%q) = %q, %q, %v; want %q, %q", tt.host, u, addr, err, tt.user, tt.addr)
+		}
+	}
+	if _, _, err := parseSSHHost("deploy@"); err == nil {
+		t.Error("parseSSHHost(deploy@) should fail")
+	}
+}
+
+func TestParseStatOutput(t *testing.T) {
+	fi, err := parseStatOutput("rigs.json", "1234 81a4 1700000000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != 1234 || fi.Mode() != 0644 || fi.IsDir() || fi.ModTime().Unix() != 1700000000 {
+		t.Errorf("regular file = %+v", fi)
+	}
+
+	fi, err = parseStatOutput("rig", "4096 41ed 1700000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() || fi.Mode() != fs.ModeDir|0755 {
+		t.Errorf("directory = %+v (mode %v)", fi, fi.Mode())
+	}
+
+	if _, err := parseStatOutput("x", "garbage"); err == nil {
+		t.Error("expected error for malformed stat output")
+	}
+}
+
+func TestGlobQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"/town/*.json", "/town/*.json"},
+		{"/my town/a?.md", `/my\ town/a?.md`},
+		{"/t/[abc]-x", `/t/[abc]\-x`},
+		{"/t/[^a-z]", "/t/[!a-z]"},
+		{"/t/$(rm -rf)", `/t/\$\(rm\ \-rf\)`},
+		{`/t/\*literal`, `/t/\*literal`},
+	}
+	for _, tt := range tests {
+		if got := globQuote(tt.in); got != tt.want {
+			t.Errorf("globQuote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryConnection_SSH(t *testing.T) {
+	r, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Machine{Name: "box", Type: "ssh", Host: "gt@box.invalid", KeyPath: filepath.Join(t.TempDir(), "missing_key")}); err != nil {
+		t.Fatal(err)
+	}
+	// A missing key is reported when the connection is built, not as
+	// "not yet implemented".
+	_, err = r.Connection("box")
+	if err == nil || !strings.Contains(err.Error(), "reading ssh key") {
+		t.Errorf("Connection(box) err = %v, want key read error", err)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, p string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
+
+func TestSSHPool_DialOutsideLock(t *testing.T) {
+	p := &sshPool{clients: make(map[string]*pooledClient)}
+	release := make(chan struct{})
+	dialing := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.get("slow", func() (*ssh.Client, error) {
+			close(dialing)
+			<-release
+			return nil, errors.New("unreachable")
+		})
+		done <- err
+	}()
+	<-dialing
+
+	// A dial to another host must not wait for the slow one.
+	got := make(chan error, 1)
+	go func() {
+		_, err := p.get("other", func() (*ssh.Client, error) { return nil, errors.New("refused") })
+		got <- err
+	}()
+	select {
+	case err := <-got:
+		if err == nil || err.Error() != "refused" {
+			t.Errorf("get(other) = %v, want refused", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("get(other) blocked behind a dial to another host")
+	}
+
+	close(release)
+	if err := <-done; err == nil {
+		t.Error("get(slow) should return the dial error")
+	}
+	if len(p.clients) != 0 {
+		t.Errorf("failed dials left %d pooled clients", len(p.clients))
+	}
+}
@@ -358,9 +358,11 @@ func (m *ConvoyManager) scan() {
 	}
 }
 
-// findStranded runs `gt convoy stranded --json` and parses the output.
+// findStranded runs `gt convoy stranded --json --notify` and parses the
+// output. This scan is the one place stranded subscribers are notified from;
+// the notification is rate-limited per convoy inside gt.
 func (m *ConvoyManager) findStranded() ([]strandedConvoyInfo, error) {
-	cmd := exec.CommandContext(m.ctx, m.gtPath, "convoy", "stranded", "--json")
+	cmd := exec.CommandContext(m.ctx, m.gtPath, "convoy", "stranded", "--json", "--notify")
 	cmd.Dir = m.townRoot
 	util.SetProcessGroup(cmd)
 	var stdout, stderr bytes.Buffer
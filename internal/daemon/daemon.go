@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -60,6 +61,11 @@ type Daemon struct {
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
 
+	// Executable plugins currently running, by plugin name. They run in
+	// background goroutines, so heartbeats must not start a second copy.
+	execPluginsMu sync.Mutex
+	execPlugins   map[string]bool
+
 	// Deacon startup tracking: prevents race condition where newly started
 	// sessions are immediately killed by the heartbeat check.
 	// See: https://github.com/steveyegge/gastown/issues/567
@@ -87,6 +93,11 @@ type Daemon struct {
 	otelProvider *telemetry.Provider
 	metrics      *daemonMetrics
 
+	// metricsServer serves Prometheus /metrics when metrics.listen (or
+	// GT_METRICS_LISTEN) is set; eventTail feeds it town event counts.
+	metricsServer *http.Server
+	eventTail     *eventTail
+
 	// jsonlPushFailures tracks consecutive git push failures for JSONL backup.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	jsonlPushFailures int
@@ -208,8 +219,13 @@ func New(config *Config) (*Daemon, error) {
 	}
 
 	// Initialize OpenTelemetry (best-effort — telemetry failure never blocks startup).
-	// Activate by setting GT_OTEL_METRICS_URL and/or GT_OTEL_LOGS_URL.
-	otelProvider, otelErr := telemetry.Init(ctx, "gastown-daemon", "")
+	// Activate by setting GT_OTEL_METRICS_URL and/or GT_OTEL_LOGS_URL, or
+	// metrics.listen / GT_METRICS_LISTEN for a Prometheus scrape endpoint.
+	var otelOpts []telemetry.Option
+	if metricsListen(patrolConfig) != "" {
+		otelOpts = append(otelOpts, telemetry.WithPrometheus())
+	}
+	otelProvider, otelErr := telemetry.Init(ctx, "gastown-daemon", "", otelOpts...)
 	if otelErr != nil {
 		logger.Printf("Warning: telemetry init failed: %v", otelErr)
 	}
@@ -219,7 +235,7 @@ func New(config *Config) (*Daemon, error) {
 		if err != nil {
 			logger.Printf("Warning: failed to register daemon metrics: %v", err)
 			dm = nil
-		} else {
+		} else if otelProvider.OTLPEnabled() {
 			metricsURL := os.Getenv(telemetry.EnvMetricsURL)
 			if metricsURL == "" {
 				metricsURL = telemetry.DefaultMetricsURL
@@ -246,6 +262,7 @@ func New(config *Config) (*Daemon, error) {
 		restartTracker: restartTracker,
 		otelProvider:   otelProvider,
 		metrics:        dm,
+		eventTail:      newEventTail(config.TownRoot),
 	}, nil
 }
 
@@ -299,6 +316,10 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Announce startup on the events feed; plugin event gates with
+	// on = "startup" fire from this.
+	_ = events.LogFeedTo(d.config.TownRoot, events.TypeDaemonStart, "daemon", nil)
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
@@ -461,6 +482,51 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Scheduled maintenance ticker started (check interval %v, window %s)", interval, window)
 	}
 
+	// Start quota rotation ticker if configured.
+	// Rotates rate-limited sessions to healthy accounts so the town keeps
+	// working overnight when accounts hit their limits.
+	var quotaRotationTicker *time.Ticker
+	var quotaRotationChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "quota_rotation") {
+		interval := quotaRotationInterval(d.patrolConfig)
+		quotaRotationTicker = time.NewTicker(interval)
+		quotaRotationChan = quotaRotationTicker.C
+		defer quotaRotationTicker.Stop()
+		d.logger.Printf("Quota rotation ticker started (interval %v)", interval)
+	}
+
+	// Start mail bridge ticker if configured.
+	// Relays bridged mail to email and delivers email replies to their threads.
+	var mailBridgeTicker *time.Ticker
+	var mailBridgeChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "mail_bridge") {
+		interval := mailBridgeInterval(d.patrolConfig)
+		mailBridgeTicker = time.NewTicker(interval)
+		mailBridgeChan = mailBridgeTicker.C
+		defer mailBridgeTicker.Stop()
+		d.logger.Printf("Mail bridge ticker started (interval %v)", interval)
+	}
+
+	// Start the Prometheus endpoint and town gauge ticker if configured.
+	// Gauges are refreshed on their own interval rather than per scrape so
+	// a busy scraper can't multiply bd calls.
+	var townGaugeTicker *time.Ticker
+	var townGaugeChan <-chan time.Time
+	if listen := metricsListen(d.patrolConfig); listen != "" && d.metrics != nil {
+		srv, err := d.startMetricsServer(listen)
+		if err != nil {
+			d.logger.Printf("Warning: metrics endpoint disabled: %v", err)
+		} else {
+			d.metricsServer = srv
+			interval := townGaugeInterval(d.patrolConfig)
+			townGaugeTicker = time.NewTicker(interval)
+			townGaugeChan = townGaugeTicker.C
+			defer townGaugeTicker.Stop()
+			d.refreshTownGauges()
+			d.logger.Printf("Serving metrics on http://%s/metrics (gauges every %v)", listen, interval)
+		}
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -548,6 +614,27 @@ func (d *Daemon) Run() error {
 				d.runScheduledMaintenance()
 			}
 
+		case <-quotaRotationChan:
+			// Quota rotation — scans sessions for rate limits, moves blocked
+			// sessions to available accounts, cools down exhausted ones.
+			if !d.isShutdownInProgress() {
+				d.rotateQuota()
+			}
+
+		case <-mailBridgeChan:
+			// Mail bridge — relays bridged mail over SMTP and ingests
+			// allow-listed email replies over IMAP.
+			if !d.isShutdownInProgress() {
+				d.syncMailBridge()
+			}
+
+		case <-townGaugeChan:
+			// Town gauges — merge queue depth, active polecats, open
+			// escalations — for the Prometheus endpoint.
+			if !d.isShutdownInProgress() {
+				d.refreshTownGauges()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -1409,6 +1496,8 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		}
 	}
 
+	d.shutdownMetricsServer()
+
 	// Flush and stop OTel providers (5s deadline to avoid blocking shutdown).
 	if d.otelProvider != nil {
 		shutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
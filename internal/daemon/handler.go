@@ -192,8 +192,8 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager, daemonCf
 	}
 }
 
-// dispatchPlugins scans for plugins, evaluates cooldown gates, and dispatches
-// eligible plugins to idle dogs.
+// dispatchPlugins scans for plugins, evaluates their gates, runs eligible
+// executable plugins directly and dispatches the rest to idle dogs.
 func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsConfig *config.RigsConfig) {
 	// Get rig names for scanner
 	var rigNames []string
@@ -214,25 +214,55 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 		return
 	}
 
-	recorder := plugin.NewRecorder(d.config.TownRoot)
+	gates, err := plugin.NewGateEvaluator(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Handler: failed to load plugin gate state: %v", err)
+		return
+	}
+	if err := gates.PollEvents(); err != nil {
+		d.logger.Printf("Handler: failed to read events for plugin gates: %v", err)
+	}
+	defer func() {
+		if err := gates.Save(); err != nil {
+			d.logger.Printf("Handler: failed to save plugin gate state: %v", err)
+		}
+	}()
+
+	// Plugins a dog is already running are not dispatched again until it
+	// finishes, whatever their gate says.
+	running := make(map[string]bool)
+	if dogs, err := mgr.List(); err == nil {
+		for _, dg := range dogs {
+			if dg.State == dog.StateWorking {
+				running[dg.Work] = true
+			}
+		}
+	}
+
 	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
 
 	for _, p := range plugins {
-		// Only dispatch plugins with cooldown gates.
-		if p.Gate == nil || p.Gate.Type != plugin.GateCooldown {
+		workDesc := fmt.Sprintf("plugin:%s", p.Name)
+		if running[workDesc] || d.execPluginRunning(p.Name) {
 			continue
 		}
 
-		// Evaluate cooldown: skip if plugin ran recently.
-		if p.Gate.Duration != "" {
-			count, err := recorder.CountRunsSince(p.Name, p.Gate.Duration)
-			if err != nil {
-				d.logger.Printf("Handler: error checking cooldown for plugin %s: %v", p.Name, err)
-				continue
-			}
-			if count > 0 {
-				continue // Still in cooldown
+		gate, err := gates.Evaluate(p)
+		if err != nil {
+			d.logger.Printf("Handler: error evaluating gate for plugin %s: %v", p.Name, err)
+			continue
+		}
+		if !gate.Open {
+			continue
+		}
+
+		// Executable plugins need no agent session.
+		if p.IsExecutable() {
+			if d.startExecPlugin(p, gate.Reason) {
+				gates.MarkDispatched(p)
+				d.logger.Printf("Handler: running plugin %s (%s)", p.Name, gate.Reason)
 			}
+			continue
 		}
 
 		// Find an idle dog.
@@ -247,7 +277,6 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 		}
 
 		// Assign work and start session.
-		workDesc := fmt.Sprintf("plugin:%s", p.Name)
 		if err := mgr.AssignWork(idleDog.Name, workDesc); err != nil {
 			d.logger.Printf("Handler: failed to assign work to dog %s: %v", idleDog.Name, err)
 			continue
@@ -278,7 +307,8 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 			// Session is already started — dog will find no mail and idle out.
 		}
 
-		d.logger.Printf("Handler: dispatched plugin %s to dog %s", p.Name, idleDog.Name)
+		gates.MarkDispatched(p)
+		d.logger.Printf("Handler: dispatched plugin %s to dog %s (%s)", p.Name, idleDog.Name, gate.Reason)
 	}
 }
 
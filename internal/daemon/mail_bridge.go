@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mailbridge"
+)
+
+const (
+	// defaultMailBridgeInterval is how often the daemon syncs the mail
+	// bridge. Short enough that an emailed answer reaches its thread within
+	// minutes.
+	defaultMailBridgeInterval = 2 * time.Minute
+
+	// mailBridgeTimeout bounds one `gt mail bridge sync` run.
+	mailBridgeTimeout = 3 * time.Minute
+)
+
+// MailBridgeConfig holds configuration for the mail_bridge patrol.
+// This patrol relays bridged mail to email and ingests email replies using
+// the bridge configured in config/messaging.json.
+type MailBridgeConfig struct {
+	// Enabled controls whether the bridge is synced.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to sync, as a string (e.g., "2m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// mailBridgeInterval returns the configured sync interval, or the default (2m).
+func mailBridgeInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MailBridge != nil {
+		if config.Patrols.MailBridge.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.MailBridge.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultMailBridgeInterval
+}
+
+// syncMailBridge runs `gt mail bridge sync --json` and logs what moved.
+func (d *Daemon) syncMailBridge() {
+	ctx, cancel := context.WithTimeout(d.ctx, mailBridgeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "mail", "bridge", "sync", "--json") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		d.logger.Printf("mail_bridge: gt mail bridge sync failed: %v", err)
+		return
+	}
+
+	for _, line := range summarizeMailBridge(output) {
+		d.logger.Printf("mail_bridge: %s", line)
+	}
+}
+
+// summarizeMailBridge turns `gt mail bridge sync --json` output into log
+// lines. A sync that moved nothing and hit no errors logs nothing.
+func summarizeMailBridge(output []byte) []string {
+	var res mailbridge.Result
+	if err := json.Unmarshal(output, &res); err != nil {
+		return []string{fmt.Sprintf("unparseable sync output: %v", err)}
+	}
+	var lines []string
+	if len(res.Relayed) > 0 || len(res.Replies) > 0 {
+		lines = append(lines, fmt.Sprintf("relayed %d message(s), delivered %d repl(ies)", len(res.Relayed), len(res.Replies)))
+	}
+	for _, r := range res.Rejected {
+		lines = append(lines, "skipped "+r)
+	}
+	for _, e := range res.Errors {
+		lines = append(lines, "error: "+e)
+	}
+	return lines
+}
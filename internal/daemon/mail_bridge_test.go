@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMailBridgeOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "mail_bridge") {
+		t.Error("mail_bridge should be disabled without config")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{MailBridge: &MailBridgeConfig{Enabled: true, IntervalStr: "30s"}}}
+	if !IsPatrolEnabled(cfg, "mail_bridge") {
+		t.Error("mail_bridge should be enabled when configured")
+	}
+	if got := mailBridgeInterval(cfg); got.String() != "30s" {
+		t.Errorf("mailBridgeInterval() = %v, want 30s", got)
+	}
+	if got := mailBridgeInterval(nil); got != defaultMailBridgeInterval {
+		t.Errorf("mailBridgeInterval(nil) = %v, want default", got)
+	}
+}
+
+func TestSummarizeMailBridge(t *testing.T) {
+	if lines := summarizeMailBridge([]byte(`{}`)); len(lines) != 0 {
+		t.Errorf("idle sync logged %v", lines)
+	}
+
+	output := []byte(`{"relayed":["hq-1","hq-2"],"replies":["hq-3"],"rejected":["uid 4: sender x@y is not allowed"],"errors":["relaying hq-5: timeout"]}`)
+	got := strings.Join(summarizeMailBridge(output), "\n")
+	for _, want := range []string{"relayed 2 message(s), delivered 1 repl(ies)", "skipped uid 4", "error: relaying hq-5"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+
+	if lines := summarizeMailBridge([]byte("not json")); len(lines) != 1 || !strings.Contains(lines[0], "unparseable") {
+		t.Errorf("bad output summary = %v", lines)
+	}
+}
@@ -30,6 +30,16 @@ type daemonMetrics struct {
 	doltLatencyMs      float64
 	doltDiskBytes      int64
 	doltHealthy        int64 // 1 = healthy, 0 = unhealthy
+
+	// eventsTotal counts town feed events (sling, mail, nudge, done, ...)
+	// written by every gt process, labeled by event type.
+	eventsTotal metric.Int64Counter
+
+	// townMu protects town gauge values written by refreshTownGauges.
+	townMu          sync.RWMutex
+	mergeQueueDepth map[string]int64 // by rig
+	activePolecats  map[string]int64 // by rig
+	openEscalations map[string]int64 // by severity
 }
 
 // newDaemonMetrics registers all daemon OTel instruments against the global
@@ -115,6 +125,53 @@ func newDaemonMetrics() (*daemonMetrics, error) {
 		return nil, err
 	}
 
+	dm.eventsTotal, err = m.Int64Counter("gastown.events.total",
+		metric.WithDescription("Town feed events written by gt processes, by event type"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Town gauges — refreshed periodically by refreshTownGauges.
+	mqGauge, err := m.Int64ObservableGauge("gastown.merge_queue.depth",
+		metric.WithDescription("Open merge requests queued for the refinery"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	activeGauge, err := m.Int64ObservableGauge("gastown.polecats.active",
+		metric.WithDescription("Running polecat sessions"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	escalationGauge, err := m.Int64ObservableGauge("gastown.escalations.open",
+		metric.WithDescription("Open escalations"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		dm.townMu.RLock()
+		defer dm.townMu.RUnlock()
+		for rig, n := range dm.mergeQueueDepth {
+			o.ObserveInt64(mqGauge, n, metric.WithAttributes(attribute.String("rig", rig)))
+		}
+		for rig, n := range dm.activePolecats {
+			o.ObserveInt64(activeGauge, n, metric.WithAttributes(attribute.String("rig", rig)))
+		}
+		for severity, n := range dm.openEscalations {
+			o.ObserveInt64(escalationGauge, n, metric.WithAttributes(attribute.String("severity", severity)))
+		}
+		return nil
+	}, mqGauge, activeGauge, escalationGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	return dm, nil
 }
 
@@ -164,3 +221,32 @@ func (dm *daemonMetrics) updateDoltHealth(conns, maxConns int64, latencyMs float
 	dm.doltDiskBytes = diskBytes
 	dm.doltHealthy = healthyInt
 }
+
+// recordEvents adds feed event counts, keyed by event type.
+func (dm *daemonMetrics) recordEvents(ctx context.Context, counts map[string]int64) {
+	if dm == nil {
+		return
+	}
+	for typ, n := range counts {
+		dm.eventsTotal.Add(ctx, n, metric.WithAttributes(attribute.String("type", typ)))
+	}
+}
+
+// updateTownGauges stores the latest town snapshot for observable gauges.
+// A nil map leaves the previous values in place (its source failed to read).
+func (dm *daemonMetrics) updateTownGauges(mergeQueue, active, escalations map[string]int64) {
+	if dm == nil {
+		return
+	}
+	dm.townMu.Lock()
+	defer dm.townMu.Unlock()
+	if mergeQueue != nil {
+		dm.mergeQueueDepth = mergeQueue
+	}
+	if active != nil {
+		dm.activePolecats = active
+	}
+	if escalations != nil {
+		dm.openEscalations = escalations
+	}
+}
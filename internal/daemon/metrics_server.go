@@ -0,0 +1,241 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+const (
+	// EnvMetricsListen overrides metrics.listen in daemon.json, e.g.
+	// GT_METRICS_LISTEN=127.0.0.1:9464.
+	EnvMetricsListen = "GT_METRICS_LISTEN"
+
+	// defaultTownGaugeInterval is how often merge queue depth, active
+	// polecats and open escalations are recomputed. Each refresh lists beads
+	// once per rig, so it runs well below a typical scrape interval.
+	defaultTownGaugeInterval = time.Minute
+)
+
+// MetricsConfig configures the daemon's Prometheus endpoint (daemon.json "metrics").
+//
+//	"metrics": {"listen": "127.0.0.1:9464"}
+type MetricsConfig struct {
+	// Listen is the address to serve /metrics on. Empty disables the endpoint.
+	Listen string `json:"listen,omitempty"`
+
+	// RefreshStr is how often town gauges are recomputed (default "1m").
+	RefreshStr string `json:"refresh,omitempty"`
+}
+
+// metricsListen returns the /metrics listen address, or "" if disabled.
+// GT_METRICS_LISTEN takes precedence over daemon.json.
+func metricsListen(config *DaemonPatrolConfig) string {
+	if addr := os.Getenv(EnvMetricsListen); addr != "" {
+		return addr
+	}
+	if config != nil && config.Metrics != nil {
+		return config.Metrics.Listen
+	}
+	return ""
+}
+
+// townGaugeInterval returns the configured town gauge refresh interval.
+func townGaugeInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Metrics != nil && config.Metrics.RefreshStr != "" {
+		if d, err := time.ParseDuration(config.Metrics.RefreshStr); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultTownGaugeInterval
+}
+
+// startMetricsServer serves the telemetry provider's Prometheus exposition
+// on listen. Binding happens synchronously so a bad address is reported at
+// startup rather than lost in a goroutine.
+func (d *Daemon) startMetricsServer(listen string) (*http.Server, error) {
+	handler := d.otelProvider.MetricsHandler()
+	if handler == nil {
+		return nil, errors.New("telemetry has no Prometheus reader")
+	}
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Count feed events up to now so counters are current at scrape time.
+		d.metrics.recordEvents(r.Context(), d.eventTail.read())
+		handler.ServeHTTP(w, r)
+	}))
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("metrics: server stopped: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+// refreshTownGauges recomputes the town gauges and counts new feed events.
+// Each source is best-effort: a failure keeps its previous values.
+func (d *Daemon) refreshTownGauges() {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.recordEvents(d.ctx, d.eventTail.read())
+
+	rigs := d.getKnownRigs()
+
+	var active map[string]int64
+	if sessions, err := d.tmux.ListSessions(); err != nil {
+		d.logger.Printf("metrics: listing sessions: %v", err)
+	} else {
+		active = countPolecatSessions(sessions, rigs)
+	}
+
+	mergeQueue := make(map[string]int64, len(rigs))
+	for _, name := range rigs {
+		r := &rig.Rig{Name: name, Path: filepath.Join(d.config.TownRoot, name)}
+		items, err := refinery.NewManager(r).Queue()
+		if err != nil {
+			d.logger.Printf("metrics: reading merge queue of %s: %v", name, err)
+			continue
+		}
+		mergeQueue[name] = int64(len(items))
+	}
+
+	var escalations map[string]int64
+	if issues, err := beads.New(filepath.Join(d.config.TownRoot, ".beads")).ListEscalations(); err != nil {
+		d.logger.Printf("metrics: listing escalations: %v", err)
+	} else {
+		escalations = countBySeverity(issues)
+	}
+
+	d.metrics.updateTownGauges(mergeQueue, active, escalations)
+}
+
+// countPolecatSessions counts running polecat sessions per rig. Known rigs
+// with no polecats report 0 so their series don't disappear.
+func countPolecatSessions(sessions, rigs []string) map[string]int64 {
+	counts := make(map[string]int64, len(rigs))
+	for _, r := range rigs {
+		counts[r] = 0
+	}
+	for _, name := range sessions {
+		identity, err := session.ParseSessionName(name)
+		if err != nil || identity.Role != session.RolePolecat {
+			continue
+		}
+		counts[identity.Rig]++
+	}
+	return counts
+}
+
+// countBySeverity counts escalations by their severity:<level> label.
+func countBySeverity(issues []*beads.Issue) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, issue := range issues {
+		severity := "unknown"
+		for _, l := range issue.Labels {
+			if s, ok := strings.CutPrefix(l, "severity:"); ok {
+				severity = s
+				break
+			}
+		}
+		counts[severity]++
+	}
+	return counts
+}
+
+// eventTail reads town feed events appended since the last read. gt
+// processes are short-lived, so their sling/mail/nudge activity reaches the
+// daemon's counters through the shared events file.
+type eventTail struct {
+	mu     sync.Mutex
+	path   string
+	offset int64
+}
+
+// newEventTail starts tailing the town's events file at its current end,
+// so counters start from zero when the daemon starts.
+func newEventTail(townRoot string) *eventTail {
+	t := &eventTail{path: filepath.Join(townRoot, events.EventsFile)}
+	if info, err := os.Stat(t.path); err == nil {
+		t.offset = info.Size()
+	}
+	return t
+}
+
+// read returns counts of complete events appended since the last read, by
+// type. A file that shrank (rotated or truncated) is read from the start.
+func (t *eventTail) read() map[string]int64 {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() < t.offset {
+		t.offset = 0
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil
+	}
+	// Leave a partially written last line for the next read.
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return nil
+	}
+	t.offset += int64(end + 1)
+
+	counts := make(map[string]int64)
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		var e struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(line, &e) == nil && e.Type != "" {
+			counts[e.Type]++
+		}
+	}
+	return counts
+}
+
+// shutdownMetricsServer stops the /metrics server, if running.
+func (d *Daemon) shutdownMetricsServer() {
+	if d.metricsServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = d.metricsServer.Shutdown(ctx)
+}
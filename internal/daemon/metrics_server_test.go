@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestMetricsListen(t *testing.T) {
+	t.Setenv(EnvMetricsListen, "")
+	if got := metricsListen(nil); got != "" {
+		t.Errorf("metricsListen(nil) = %q, want empty", got)
+	}
+
+	cfg := &DaemonPatrolConfig{Metrics: &MetricsConfig{Listen: "127.0.0.1:9464"}}
+	if got := metricsListen(cfg); got != "127.0.0.1:9464" {
+		t.Errorf("metricsListen(config) = %q", got)
+	}
+
+	t.Setenv(EnvMetricsListen, ":9999")
+	if got := metricsListen(cfg); got != ":9999" {
+		t.Errorf("env should override config, got %q", got)
+	}
+}
+
+func TestTownGaugeInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *DaemonPatrolConfig
+		want   time.Duration
+	}{
+		{"nil config", nil, defaultTownGaugeInterval},
+		{"unset", &DaemonPatrolConfig{Metrics: &MetricsConfig{}}, defaultTownGaugeInterval},
+		{"custom", &DaemonPatrolConfig{Metrics: &MetricsConfig{RefreshStr: "15s"}}, 15 * time.Second},
+		{"invalid", &DaemonPatrolConfig{Metrics: &MetricsConfig{RefreshStr: "soon"}}, defaultTownGaugeInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := townGaugeInterval(tt.config); got != tt.want {
+				t.Errorf("townGaugeInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountPolecatSessions(t *testing.T) {
+	oldRegistry := session.DefaultRegistry()
+	r := session.NewPrefixRegistry()
+	r.Register("gt", "gastown")
+	r.Register("bd", "beads")
+	session.SetDefaultRegistry(r)
+	defer session.SetDefaultRegistry(oldRegistry)
+
+	sessions := []string{"gt-Toast", "gt-nux", "gt-witness", "gt-refinery", "hq-mayor", "bd-furiosa", "scratch"}
+	got := countPolecatSessions(sessions, []string{"gastown", "beads", "idle"})
+
+	want := map[string]int64{"gastown": 2, "beads": 1, "idle": 0}
+	for rig, n := range want {
+		if got[rig] != n {
+			t.Errorf("active[%s] = %d, want %d (all: %v)", rig, got[rig], n, got)
+		}
+	}
+}
+
+func TestCountBySeverity(t *testing.T) {
+	issues := []*beads.Issue{
+		{Labels: []string{"gt:escalation", "severity:high"}},
+		{Labels: []string{"gt:escalation", "severity:high"}},
+		{Labels: []string{"severity:critical"}},
+		{Labels: []string{"gt:escalation"}},
+	}
+	got := countBySeverity(issues)
+	want := map[string]int64{"high": 2, "critical": 1, "unknown": 1}
+	if len(got) != len(want) {
+		t.Fatalf("countBySeverity() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("countBySeverity()[%s] = %d, want %d", k, got[k], v)
+		}
+	}
+}
+
+func TestEventTail(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	appendLines := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Events written before the daemon starts are not counted.
+	appendLines(`{"type":"sling"}` + "\n")
+	tail := newEventTail(townRoot)
+	if got := tail.read(); len(got) != 0 {
+		t.Errorf("pre-existing events counted: %v", got)
+	}
+
+	// A trailing partial line waits for its newline.
+	appendLines(`{"type":"sling"}` + "\n" + `{"type":"mail"}` + "\n" + "not json\n" + `{"type":"nudge"`)
+	got := tail.read()
+	if got["sling"] != 1 || got["mail"] != 1 || len(got) != 2 {
+		t.Errorf("first read = %v, want sling:1 mail:1", got)
+	}
+	appendLines("}\n")
+	if got := tail.read(); got["nudge"] != 1 || len(got) != 1 {
+		t.Errorf("second read = %v, want nudge:1", got)
+	}
+
+	// Truncation restarts from the beginning.
+	if err := os.WriteFile(path, []byte(`{"type":"done"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := tail.read(); got["done"] != 1 {
+		t.Errorf("read after truncate = %v, want done:1", got)
+	}
+
+	var nilTail *eventTail
+	if got := nilTail.read(); got != nil {
+		t.Errorf("nil tail read = %v", got)
+	}
+}
+
+func TestDaemonMetrics_TownGauges(t *testing.T) {
+	dm, err := newDaemonMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm.recordEvents(context.Background(), map[string]int64{"sling": 3})
+
+	dm.updateTownGauges(map[string]int64{"gastown": 4}, map[string]int64{"gastown": 2}, map[string]int64{"high": 1})
+	// A nil map keeps the last good snapshot.
+	dm.updateTownGauges(nil, map[string]int64{"gastown": 5}, nil)
+
+	dm.townMu.RLock()
+	defer dm.townMu.RUnlock()
+	if dm.mergeQueueDepth["gastown"] != 4 {
+		t.Errorf("mergeQueueDepth = %v, want gastown:4", dm.mergeQueueDepth)
+	}
+	if dm.activePolecats["gastown"] != 5 {
+		t.Errorf("activePolecats = %v, want gastown:5", dm.activePolecats)
+	}
+	if dm.openEscalations["high"] != 1 {
+		t.Errorf("openEscalations = %v, want high:1", dm.openEscalations)
+	}
+}
@@ -23,6 +23,8 @@ func TestDaemonMetrics_NilReceiver(t *testing.T) {
 	dm.recordHeartbeat(ctx)
 	dm.recordRestart(ctx, "deacon")
 	dm.updateDoltHealth(5, 100, 2.5, 1024, true)
+	dm.recordEvents(ctx, map[string]int64{"sling": 1})
+	dm.updateTownGauges(nil, nil, nil)
 }
 
 func TestDaemonMetrics_RecordHeartbeat(t *testing.T) {
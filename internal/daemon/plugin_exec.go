@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// execPluginRunning reports whether an executable plugin is still running.
+func (d *Daemon) execPluginRunning(name string) bool {
+	d.execPluginsMu.Lock()
+	defer d.execPluginsMu.Unlock()
+	return d.execPlugins[name]
+}
+
+// startExecPlugin runs an executable plugin in the background so a slow
+// entrypoint can't stall the heartbeat. Returns false if it is already
+// running.
+func (d *Daemon) startExecPlugin(p *plugin.Plugin, trigger string) bool {
+	d.execPluginsMu.Lock()
+	if d.execPlugins[p.Name] {
+		d.execPluginsMu.Unlock()
+		return false
+	}
+	if d.execPlugins == nil {
+		d.execPlugins = make(map[string]bool)
+	}
+	d.execPlugins[p.Name] = true
+	d.execPluginsMu.Unlock()
+
+	go func() {
+		defer func() {
+			d.execPluginsMu.Lock()
+			delete(d.execPlugins, p.Name)
+			d.execPluginsMu.Unlock()
+		}()
+		d.runExecPlugin(p, trigger)
+	}()
+	return true
+}
+
+// runExecPlugin runs a plugin's entrypoint, records the run on the ledger
+// and escalates failures when the plugin asks for it.
+func (d *Daemon) runExecPlugin(p *plugin.Plugin, trigger string) {
+	recorder := plugin.NewRecorder(d.config.TownRoot)
+	last, err := recorder.GetLastRun(p.Name)
+	if err != nil {
+		d.logger.Printf("Handler: plugin %s: reading last run: %v", p.Name, err)
+	}
+
+	res, err := plugin.Exec(d.ctx, p, plugin.NewExecContext(d.config.TownRoot, p, trigger, last))
+	if err != nil {
+		res = &plugin.ExecResult{Result: plugin.ResultFailure, Summary: err.Error()}
+	}
+	if d.ctx.Err() != nil {
+		// Killed by daemon shutdown; the next daemon reruns it when due.
+		return
+	}
+	d.logger.Printf("Handler: plugin %s finished: %s %s", p.Name, res.Result, res.Summary)
+
+	if _, err := recorder.RecordRun(res.RunRecord(p)); err != nil {
+		d.logger.Printf("Handler: plugin %s: recording run: %v", p.Name, err)
+	}
+
+	if res.Result == plugin.ResultFailure && p.Execution.NotifyOnFailure {
+		d.escalatePluginFailure(p, res)
+	}
+}
+
+// escalatePluginFailure raises an escalation for a failed executable plugin,
+// as a dog would for an agent-run plugin.
+func (d *Daemon) escalatePluginFailure(p *plugin.Plugin, res *plugin.ExecResult) {
+	severity := p.Execution.Severity
+	if severity == "" {
+		severity = "medium"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "escalate", //nolint:gosec // G204: args are constructed internally
+		fmt.Sprintf("Plugin FAILED: %s", p.Name),
+		"--severity", severity,
+		"--source", "plugin:"+p.Name,
+		"--reason", res.Summary,
+	)
+	cmd.Dir = d.config.TownRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Handler: plugin %s: escalation failed: %v (%s)", p.Name, err, strings.TrimSpace(string(output)))
+	}
+}
@@ -0,0 +1,20 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+func TestStartExecPlugin_SkipsRunning(t *testing.T) {
+	d := testDaemon()
+	d.execPlugins = map[string]bool{"beads-backup": true}
+
+	p := &plugin.Plugin{Name: "beads-backup", Execution: &plugin.Execution{Entrypoint: "run.sh"}}
+	if d.startExecPlugin(p, "cooldown") {
+		t.Error("startExecPlugin started a plugin that is already running")
+	}
+	if !d.execPluginRunning("beads-backup") || d.execPluginRunning("rebuild-gt") {
+		t.Errorf("execPluginRunning mismatch: %v", d.execPlugins)
+	}
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+const (
+	// defaultQuotaRotationInterval is how often the daemon scans sessions for
+	// rate limits. Short enough that a blocked polecat waits minutes, not
+	// hours, for a fresh account.
+	defaultQuotaRotationInterval = 5 * time.Minute
+
+	// quotaRotationTimeout bounds one `gt quota rotate` run. Restarting a
+	// session takes several seconds; rotations run concurrently.
+	quotaRotationTimeout = 3 * time.Minute
+)
+
+// QuotaRotationConfig holds configuration for the quota_rotation patrol.
+// This patrol scans sessions for rate limits and rotates blocked sessions
+// to healthy accounts from mayor/accounts.json, putting exhausted accounts
+// in cooldown until their reset time.
+type QuotaRotationConfig struct {
+	// Enabled controls whether automatic rotation runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to scan, as a string (e.g., "5m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// quotaRotationInterval returns the configured scan interval, or the default (5m).
+func quotaRotationInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.QuotaRotation != nil {
+		if config.Patrols.QuotaRotation.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.QuotaRotation.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultQuotaRotationInterval
+}
+
+// rotateQuota runs `gt quota rotate --cooldown` so rate-limited sessions
+// resume on another account without waiting for a human. Rotation needs at
+// least two registered accounts; with fewer the patrol is a no-op.
+func (d *Daemon) rotateQuota() {
+	acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(d.config.TownRoot))
+	if err != nil || len(acctCfg.Accounts) < 2 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, quotaRotationTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "quota", "rotate", "--cooldown", "--json") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		d.logger.Printf("quota_rotation: gt quota rotate failed: %v", err)
+		return
+	}
+
+	for _, line := range summarizeRotation(output) {
+		d.logger.Printf("quota_rotation: %s", line)
+	}
+}
+
+// summarizeRotation turns `gt quota rotate --json` output into log lines,
+// one per session that was rotated or failed to rotate.
+func summarizeRotation(output []byte) []string {
+	var results []quota.RotateResult
+	if err := json.Unmarshal(output, &results); err != nil {
+		return []string{fmt.Sprintf("unparseable rotate output: %v", err)}
+	}
+	var lines []string
+	for _, r := range results {
+		switch {
+		case r.Rotated:
+			from := r.OldAccount
+			if from == "" {
+				from = "(unknown)"
+			}
+			lines = append(lines, fmt.Sprintf("rotated %s from %s to %s", r.Session, from, r.NewAccount))
+		case r.Error != "":
+			session := r.Session
+			if session == "" {
+				session = "rotation"
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", session, r.Error))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuotaRotationInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *DaemonPatrolConfig
+		want   time.Duration
+	}{
+		{"nil config", nil, defaultQuotaRotationInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{QuotaRotation: &QuotaRotationConfig{Enabled: true}}}, defaultQuotaRotationInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{QuotaRotation: &QuotaRotationConfig{IntervalStr: "90s"}}}, 90 * time.Second},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{QuotaRotation: &QuotaRotationConfig{IntervalStr: "often"}}}, defaultQuotaRotationInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quotaRotationInterval(tt.config); got != tt.want {
+				t.Errorf("quotaRotationInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaRotationOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "quota_rotation") {
+		t.Error("quota_rotation should be disabled without config")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{QuotaRotation: &QuotaRotationConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "quota_rotation") {
+		t.Error("quota_rotation should be enabled when configured")
+	}
+}
+
+func TestSummarizeRotation(t *testing.T) {
+	output := []byte(`[
+		{"session":"gt-crew-max","old_account":"work","new_account":"personal","rotated":true},
+		{"session":"gt-witness","new_account":"personal","rotated":true},
+		{"session":"gt-crew-joe","new_account":"personal","error":"respawn failed"},
+		{"session":"gt-mayor","new_account":"personal"}
+	]`)
+	got := summarizeRotation(output)
+	want := []string{
+		"rotated gt-crew-max from work to personal",
+		"rotated gt-witness from (unknown) to personal",
+		"gt-crew-joe: respawn failed",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("summarizeRotation() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := summarizeRotation([]byte("not json")); len(got) != 1 {
+		t.Errorf("unparseable output should produce one line, got %q", got)
+	}
+}
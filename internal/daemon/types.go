@@ -127,6 +127,8 @@ type PatrolsConfig struct {
 	CompactorDog           *CompactorDogConfig            `json:"compactor_dog,omitempty"`
 	ScheduledMaintenance   *ScheduledMaintenanceConfig    `json:"scheduled_maintenance,omitempty"`
 	RestartTracker         *RestartTrackerConfig          `json:"restart_tracker,omitempty"`
+	QuotaRotation          *QuotaRotationConfig           `json:"quota_rotation,omitempty"`
+	MailBridge             *MailBridgeConfig              `json:"mail_bridge,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -200,6 +202,8 @@ type DaemonPatrolConfig struct {
 	// Propagated to all sessions spawned by the daemon and read by gt up/mayor attach.
 	// Example: {"GT_DOLT_PORT": "43211"}
 	Env       map[string]string `json:"env,omitempty"`
+	// Metrics configures the Prometheus /metrics endpoint (off by default).
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -286,6 +290,18 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.ScheduledMaintenance.Enabled
 	}
+	if patrol == "quota_rotation" {
+		if config == nil || config.Patrols == nil || config.Patrols.QuotaRotation == nil {
+			return false
+		}
+		return config.Patrols.QuotaRotation.Enabled
+	}
+	if patrol == "mail_bridge" {
+		if config == nil || config.Patrols == nil || config.Patrols.MailBridge == nil {
+			return false
+		}
+		return config.Patrols.MailBridge.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
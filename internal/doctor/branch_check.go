@@ -89,32 +89,44 @@ func (c *BranchCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
-// Fix switches all off-main directories to their expected branch.
-// Uses the rig's default_branch if configured, otherwise "main".
-func (c *BranchCheck) Fix(ctx *CheckContext) error {
-	if len(c.offMainDirs) == 0 {
-		return nil
-	}
+// PlanFix plans switching each off-main directory to its expected branch
+// and pulling. The checkout is undone by checking out the previous branch.
+func (c *BranchCheck) PlanFix(ctx *CheckContext) (*FixPlan, error) {
+	plan := &FixPlan{Check: c.Name()}
 
-	var lastErr error
 	for _, dir := range c.offMainDirs {
 		targetBranch := c.expectedBranch(ctx.TownRoot, dir)
-
-		if err := c.checkoutWithWorktreeRetry(dir, targetBranch); err != nil {
-			lastErr = err
-			continue
+		rel := c.relativePath(ctx.TownRoot, dir)
+
+		checkout := plan.RunStep(dir, fmt.Sprintf("git checkout %s in %s", targetBranch, rel), func() error {
+			return c.checkoutWithWorktreeRetry(dir, targetBranch)
+		})
+		if previous, err := c.getCurrentBranch(dir); err == nil && previous != "HEAD" {
+			checkout.UndoCommand = []string{"git", "checkout", previous}
+			checkout.UndoDir = dir
 		}
 
 		// git pull --rebase
-		cmd := exec.Command("git", "pull", "--rebase")
-		cmd.Dir = dir
-		if err := cmd.Run(); err != nil {
+		plan.RunStep(dir, "git pull --rebase in "+rel, func() error {
+			cmd := exec.Command("git", "pull", "--rebase")
+			cmd.Dir = dir
 			// Pull failure is not fatal, just warn
-			continue
-		}
+			_ = cmd.Run()
+			return nil
+		})
 	}
 
-	return lastErr
+	return plan, nil
+}
+
+// Fix switches all off-main directories to their expected branch.
+// Uses the rig's default_branch if configured, otherwise "main".
+func (c *BranchCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(ctx.Journal)
 }
 
 // checkoutWithWorktreeRetry attempts git checkout, and if it fails because the
@@ -11,16 +11,27 @@ import (
 
 // Doctor manages and executes health checks.
 type Doctor struct {
-	checks []Check
+	checks      []Check
+	parallelism int
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
 func NewDoctor() *Doctor {
 	return &Doctor{
-		checks: make([]Check, 0),
+		checks:      make([]Check, 0),
+		parallelism: DefaultParallelism,
 	}
 }
 
+// SetParallelism sets how many checks RunStreaming may run at once.
+// Values below 1 run checks one at a time.
+func (d *Doctor) SetParallelism(n int) {
+	if n < 1 {
+		n = 1
+	}
+	d.parallelism = n
+}
+
 // Register adds a check to the doctor's check list.
 func (d *Doctor) Register(check Check) {
 	d.checks = append(d.checks, check)
@@ -47,56 +58,60 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 }
 
 // RunStreaming executes all registered checks with optional real-time output.
+// Independent checks run concurrently (see SetParallelism); a check waits for
+// the checks it DependsOn and is skipped if any of them fails. Results are
+// reported in registration order regardless of completion order.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
 	report := NewReport()
+	p := d.plan()
 
-	for _, check := range d.checks {
-		// Stream: print check name before running
-		if w != nil {
-			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
+	results := make([]*CheckResult, len(p.checks))
+	done := make([]chan struct{}, len(p.checks))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	parallelism := d.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+
+	// Start in dependency order so the semaphore is handed out roughly in
+	// the order results are needed.
+	for _, i := range p.order {
+		if p.cyclic[i] {
+			results[i] = p.blocked(i, results)
+			close(done[i])
+			continue
 		}
+		go func(i int) {
+			defer close(done[i])
+			for _, dep := range p.deps[i] {
+				<-done[dep]
+			}
+			if r := p.blocked(i, results); r != nil {
+				results[i] = r
+				return
+			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = runCheck(p.checks[i], ctx)
+		}(i)
+	}
 
-		start := time.Now()
-		result := check.Run(ctx)
-		result.Elapsed = time.Since(start)
-
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
+	for i, check := range p.checks {
+		// Stream: print check name while waiting for it
+		if w != nil {
+			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
 		}
+		<-done[i]
+		result := results[i]
 
 		// Stream: overwrite line with result
 		if w != nil {
-			var statusIcon string
-			switch result.Status {
-			case StatusOK:
-				statusIcon = ui.RenderPassIcon()
-			case StatusWarning:
-				statusIcon = ui.RenderWarnIcon()
-			case StatusError:
-				statusIcon = ui.RenderFailIcon()
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
-			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
-			}
-			fmt.Fprintln(w)
+			streamResult(w, report, result, slowThreshold)
 		}
 
 		report.Add(result)
@@ -105,6 +120,42 @@ func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 	return report
 }
 
+// streamResult overwrites the in-progress line for a check with its result.
+func streamResult(w io.Writer, report *Report, result *CheckResult, slowThreshold time.Duration) {
+	var statusIcon string
+	switch {
+	case result.Fixed:
+		statusIcon = ui.RenderFixIcon()
+	case result.Skipped:
+		statusIcon = ui.RenderSkipIcon()
+	case result.Status == StatusOK:
+		statusIcon = ui.RenderPassIcon()
+	case result.Status == StatusWarning:
+		statusIcon = ui.RenderWarnIcon()
+	case result.Status == StatusError:
+		statusIcon = ui.RenderFailIcon()
+	}
+	// Check if slow (hourglass replaces spaces to maintain alignment)
+	// Fix icon (🔧) is double-width, so use one less padding space
+	isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
+	slowIndicator := "  "
+	if result.Fixed {
+		slowIndicator = " "
+	}
+	if isSlow {
+		report.Summary.Slow++
+		slowIndicator = "⏳"
+	}
+	fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
+	if result.Message != "" {
+		fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
+	}
+	if isSlow {
+		fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+	}
+	fmt.Fprintln(w)
+}
+
 // Fix runs all checks with auto-fix enabled where possible.
 // It first runs the check, then if it fails and can be fixed, attempts the fix.
 func (d *Doctor) Fix(ctx *CheckContext) *Report {
@@ -123,28 +174,48 @@ func safeFixCheck(check Check, ctx *CheckContext) (retErr error) {
 	return check.Fix(ctx)
 }
 
+// fixCheck applies a check's fix. A fix without a plan can't journal its
+// changes, so the journal records that it ran and can't be undone.
+func fixCheck(check Check, ctx *CheckContext) error {
+	err := safeFixCheck(check, ctx)
+	if _, planned := check.(FixPlanner); !planned {
+		if jerr := ctx.Journal.RecordOpaque(check.Name(), err); jerr != nil && err == nil {
+			err = fmt.Errorf("saving fix journal: %w", jerr)
+		}
+	}
+	return err
+}
+
 // FixStreaming runs all checks with auto-fix and optional real-time output.
+// Fixes change shared state, so checks run one at a time, in dependency
+// order; a check is skipped if a prerequisite still fails after its fix.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
 	report := NewReport()
+	p := d.plan()
+	results := make([]*CheckResult, len(p.checks))
+
+	for _, i := range p.order {
+		check := p.checks[i]
 
-	for _, check := range d.checks {
 		// Stream: print check name before running
 		if w != nil {
 			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
 		}
 
-		start := time.Now()
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
+		if result := p.blocked(i, results); result != nil {
+			results[i] = result
+			if w != nil {
+				streamResult(w, report, result, slowThreshold)
+			}
+			report.Add(result)
+			continue
 		}
 
+		start := time.Now()
+		result := runCheck(check, ctx)
+
 		// Attempt fix if check failed and is fixable
 		if result.Status != StatusOK && check.CanFix() {
 			// Stream: show the problem with fixing indicator (all on same line)
@@ -160,20 +231,23 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 				if result.Message != "" {
 					fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
 				}
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+				if ctx.DryRun {
+					fmt.Fprintf(w, "%s", ui.RenderMuted(" (planning fix)..."))
+				} else {
+					fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+				}
 			}
 
-			err := safeFixCheck(check, ctx)
-			if err == nil {
-				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
+			if ctx.DryRun {
+				// Dry run: record the plan, printed below the result
+				if plan, err := planFix(check, ctx); err != nil {
+					result.Details = append(result.Details, "Fix plan failed: "+err.Error())
+				} else if !plan.Empty() {
+					result.Plan = plan
 				}
+			} else if err := fixCheck(check, ctx); err == nil {
+				// Re-run check to verify fix worked
+				result = runCheck(check, ctx)
 				// Update message to indicate fix was applied
 				if result.Status == StatusOK {
 					result.Message = result.Message + " (fixed)"
@@ -193,40 +267,15 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 
 		// Stream: overwrite line with final result
 		if w != nil {
-			var statusIcon string
-			if result.Fixed {
-				statusIcon = ui.RenderFixIcon()
-			} else {
-				switch result.Status {
-				case StatusOK:
-					statusIcon = ui.RenderPassIcon()
-				case StatusWarning:
-					statusIcon = ui.RenderWarnIcon()
-				case StatusError:
-					statusIcon = ui.RenderFailIcon()
+			streamResult(w, report, result, slowThreshold)
+			if result.Plan != nil {
+				for _, line := range result.Plan.Lines(ctx.TownRoot) {
+					fmt.Fprintf(w, "       %s %s\n", ui.RenderMuted("would"), line)
 				}
 			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			// Fix icon (🔧) is double-width, so use one less padding space
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if result.Fixed {
-				slowIndicator = " "
-			}
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
-			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
-			}
-			fmt.Fprintln(w)
 		}
 
+		results[i] = result
 		report.Add(result)
 	}
 
@@ -238,7 +287,13 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string   // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string // Checks that must pass before this one runs
+}
+
+// DependsOn returns the names of checks that must pass before this one runs.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
 }
 
 // Category returns the check's category for grouping in output.
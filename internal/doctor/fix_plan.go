@@ -0,0 +1,189 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ActionKind identifies what a FixAction does.
+type ActionKind string
+
+const (
+	// ActionRemove deletes a file or directory. The journal keeps it so it
+	// can be restored.
+	ActionRemove ActionKind = "remove"
+	// ActionWrite creates or overwrites a file. The journal keeps the
+	// previous content.
+	ActionWrite ActionKind = "write"
+	// ActionRun performs a non-file change (git, tmux, dolt). It can only
+	// be undone if it declares an UndoCommand.
+	ActionRun ActionKind = "run"
+	// ActionOpaque is the whole fix of a check without a plan. Its changes
+	// are not recorded and cannot be undone.
+	ActionOpaque ActionKind = "opaque"
+)
+
+// FixAction is one concrete step of a FixPlan.
+type FixAction struct {
+	Kind        ActionKind
+	Description string      // What the action does, for dry-run output
+	Path        string      // File or directory affected (remove, write)
+	Content     []byte      // New file content (write)
+	Mode        os.FileMode // File mode for write (default 0644)
+	Run         func() error
+
+	// UndoCommand reverts a run action (e.g. git checkout of the previous
+	// branch), run in UndoDir. Empty means the action cannot be undone.
+	UndoCommand []string
+	UndoDir     string
+
+	// Group ties dependent actions together: if one fails, the rest of its
+	// group is skipped. Actions with an empty group are independent.
+	Group string
+}
+
+// FixPlan is the set of actions a check's fix would take.
+type FixPlan struct {
+	Check     string
+	Actions   []FixAction
+	Unfixable []string // Problems the plan cannot fix; Apply reports them as errors
+	Opaque    bool     // The check has no plan; its Fix runs as a whole
+}
+
+// FixPlanner is implemented by fixable checks that can describe their fix
+// as concrete actions before applying it. Their Fix applies PlanFix.
+type FixPlanner interface {
+	PlanFix(ctx *CheckContext) (*FixPlan, error)
+}
+
+// planFix returns the check's fix plan, or an opaque plan if the check
+// doesn't implement FixPlanner.
+func planFix(check Check, ctx *CheckContext) (*FixPlan, error) {
+	if p, ok := check.(FixPlanner); ok {
+		return p.PlanFix(ctx)
+	}
+	return &FixPlan{Check: check.Name(), Opaque: true}, nil
+}
+
+// Remove adds an action deleting path.
+func (p *FixPlan) Remove(group, path, description string) {
+	p.Actions = append(p.Actions, FixAction{Kind: ActionRemove, Path: path, Description: description, Group: group})
+}
+
+// Write adds an action writing content to path.
+func (p *FixPlan) Write(group, path string, content []byte, mode os.FileMode, description string) {
+	p.Actions = append(p.Actions, FixAction{Kind: ActionWrite, Path: path, Content: content, Mode: mode, Description: description, Group: group})
+}
+
+// RunStep adds a non-file action.
+func (p *FixPlan) RunStep(group, description string, run func() error) *FixAction {
+	p.Actions = append(p.Actions, FixAction{Kind: ActionRun, Description: description, Run: run, Group: group})
+	return &p.Actions[len(p.Actions)-1]
+}
+
+// Empty reports whether the plan has nothing to do.
+func (p *FixPlan) Empty() bool {
+	return !p.Opaque && len(p.Actions) == 0 && len(p.Unfixable) == 0
+}
+
+// Lines describes the plan for dry-run output, with paths relative to
+// townRoot where possible.
+func (p *FixPlan) Lines(townRoot string) []string {
+	if p.Opaque {
+		return []string{fmt.Sprintf("%-6s run the check's fix (actions not previewable) [not undoable]", ActionOpaque)}
+	}
+	var lines []string
+	for _, a := range p.Actions {
+		switch a.Kind {
+		case ActionRemove, ActionWrite:
+			line := fmt.Sprintf("%-6s %s", a.Kind, relToTown(townRoot, a.Path))
+			if a.Description != "" {
+				line += " (" + a.Description + ")"
+			}
+			lines = append(lines, line)
+		default:
+			line := fmt.Sprintf("%-6s %s", a.Kind, a.Description)
+			if len(a.UndoCommand) == 0 {
+				line += " [not undoable]"
+			}
+			lines = append(lines, line)
+		}
+	}
+	for _, u := range p.Unfixable {
+		lines = append(lines, "cannot fix: "+u)
+	}
+	return lines
+}
+
+// Apply performs the plan's actions in order, recording them in j if it is
+// non-nil. A failed action skips the rest of its group; other actions still
+// run. Returns the action errors and unfixable problems joined.
+func (p *FixPlan) Apply(j *Journal) error {
+	if p.Opaque {
+		return fmt.Errorf("%s: opaque plan cannot be applied", p.Check)
+	}
+	var errs []error
+	failed := make(map[string]bool)
+	for i := range p.Actions {
+		a := &p.Actions[i]
+		if a.Group != "" && failed[a.Group] {
+			continue
+		}
+		if err := j.apply(p.Check, a); err != nil {
+			errs = append(errs, err)
+			if a.Group != "" {
+				failed[a.Group] = true
+			}
+		}
+	}
+	for _, u := range p.Unfixable {
+		errs = append(errs, errors.New(u))
+	}
+	return errors.Join(errs...)
+}
+
+// applyAction performs a without journaling.
+func applyAction(a *FixAction) error {
+	switch a.Kind {
+	case ActionRemove:
+		if err := os.RemoveAll(a.Path); err != nil {
+			return fmt.Errorf("removing %s: %w", a.Path, err)
+		}
+		return nil
+	case ActionWrite:
+		return writeActionFile(a)
+	case ActionRun:
+		return a.Run()
+	default:
+		return fmt.Errorf("unknown fix action %q", a.Kind)
+	}
+}
+
+func writeActionFile(a *FixAction) error {
+	mode := a.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	if err := os.MkdirAll(filepath.Dir(a.Path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(a.Path), err)
+	}
+	if err := os.WriteFile(a.Path, a.Content, mode); err != nil {
+		return fmt.Errorf("writing %s: %w", a.Path, err)
+	}
+	return nil
+}
+
+// relToTown returns path relative to townRoot, or path itself if outside it.
+func relToTown(townRoot, path string) string {
+	if townRoot == "" {
+		return path
+	}
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,444 @@
+package doctor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEntry records one applied fix action with what is needed to undo it.
+type JournalEntry struct {
+	Check       string      `json:"check"`
+	Kind        ActionKind  `json:"kind"`
+	Description string      `json:"description,omitempty"`
+	Path        string      `json:"path,omitempty"`
+	Existed     bool        `json:"existed,omitempty"` // Path existed before the action
+	Backup      string      `json:"backup,omitempty"`  // Before-image, relative to the journal dir
+	Mode        os.FileMode `json:"mode,omitempty"`
+	Written     string      `json:"written,omitempty"` // sha256 of the content written
+	UndoCommand []string    `json:"undo_command,omitempty"`
+	UndoDir     string      `json:"undo_dir,omitempty"`
+	Applied     bool        `json:"applied"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// Journal records the actions of one `gt doctor --fix` run so that
+// `gt doctor undo` can revert them. It is stored under
+// <town>/.runtime/doctor/<run-id>/ and only created once an action is
+// recorded.
+type Journal struct {
+	RunID    string         `json:"run_id"`
+	TownRoot string         `json:"town_root"`
+	Started  time.Time      `json:"started"`
+	Undone   *time.Time     `json:"undone,omitempty"`
+	Entries  []JournalEntry `json:"entries"`
+
+	mu sync.Mutex
+}
+
+// JournalRoot returns the directory holding the fix journals of a town.
+func JournalRoot(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "doctor")
+}
+
+// NewJournal starts a journal for a fix run in townRoot.
+func NewJournal(townRoot string) *Journal {
+	now := time.Now().UTC()
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return &Journal{
+		RunID:    now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		TownRoot: townRoot,
+		Started:  now,
+	}
+}
+
+// LoadJournal reads the journal of a previous fix run.
+func LoadJournal(townRoot, runID string) (*Journal, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) || strings.HasPrefix(runID, ".") {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	data, err := os.ReadFile(filepath.Join(JournalRoot(townRoot), runID, "journal.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no fix run %q (see 'gt doctor undo' for recorded runs)", runID)
+		}
+		return nil, err
+	}
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parsing journal %s: %w", runID, err)
+	}
+	return &j, nil
+}
+
+// ListJournals returns the recorded fix runs of a town, newest first.
+func ListJournals(townRoot string) ([]*Journal, error) {
+	dirs, err := os.ReadDir(JournalRoot(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var journals []*Journal
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		j, err := LoadJournal(townRoot, d.Name())
+		if err != nil {
+			continue
+		}
+		journals = append(journals, j)
+	}
+	sort.Slice(journals, func(a, b int) bool {
+		return journals[a].Started.After(journals[b].Started)
+	})
+	return journals, nil
+}
+
+// JournalRetention is the number of fix runs whose journals are kept.
+const JournalRetention = 10
+
+// PruneJournals removes all but the newest keep fix run journals of a
+// town, along with their before-images.
+func PruneJournals(townRoot string, keep int) error {
+	journals, err := ListJournals(townRoot)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := keep; i < len(journals); i++ {
+		if err := os.RemoveAll(journals[i].Dir()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Dir returns the journal's directory.
+func (j *Journal) Dir() string {
+	return filepath.Join(JournalRoot(j.TownRoot), j.RunID)
+}
+
+// Len returns the number of recorded actions.
+func (j *Journal) Len() int {
+	if j == nil {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.Entries)
+}
+
+// Opaque returns the number of recorded fixes that ran without a plan and
+// so cannot be undone.
+func (j *Journal) Opaque() int {
+	if j == nil {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	n := 0
+	for _, e := range j.Entries {
+		if e.Kind == ActionOpaque {
+			n++
+		}
+	}
+	return n
+}
+
+// RecordOpaque records that a check without a fix plan ran its Fix, whose
+// changes the journal cannot capture. A nil journal records nothing.
+func (j *Journal) RecordOpaque(check string, fixErr error) error {
+	if j == nil || errors.Is(fixErr, ErrSkippedNoStart) {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e := JournalEntry{
+		Check:       check,
+		Kind:        ActionOpaque,
+		Description: "fix by " + check + " (changes not recorded)",
+		Applied:     true,
+	}
+	if fixErr != nil {
+		e.Error = fixErr.Error()
+	}
+	j.Entries = append(j.Entries, e)
+	return j.saveLocked()
+}
+
+// apply performs a fix action, journaling it first so a before-image exists
+// before anything is changed. A nil journal applies without recording.
+func (j *Journal) apply(check string, a *FixAction) error {
+	if j == nil {
+		return applyAction(a)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Entries = append(j.Entries, JournalEntry{
+		Check:       check,
+		Kind:        a.Kind,
+		Description: a.Description,
+		Path:        a.Path,
+		UndoCommand: a.UndoCommand,
+		UndoDir:     a.UndoDir,
+	})
+	e := &j.Entries[len(j.Entries)-1]
+
+	err := j.applyLocked(len(j.Entries), e, a)
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if saveErr := j.saveLocked(); saveErr != nil && err == nil {
+		err = fmt.Errorf("saving fix journal: %w", saveErr)
+	}
+	return err
+}
+
+func (j *Journal) applyLocked(n int, e *JournalEntry, a *FixAction) error {
+	backup := filepath.Join("before", fmt.Sprintf("%04d", n))
+	switch a.Kind {
+	case ActionRemove:
+		info, err := os.Lstat(a.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e.Existed = true
+		e.Mode = info.Mode()
+		if err := os.MkdirAll(filepath.Join(j.Dir(), "before"), 0755); err != nil {
+			return err
+		}
+		// Moving the path into the journal is the removal.
+		if err := movePath(a.Path, filepath.Join(j.Dir(), backup)); err != nil {
+			return fmt.Errorf("removing %s: %w", a.Path, err)
+		}
+		e.Backup = backup
+		e.Applied = true
+		return nil
+
+	case ActionWrite:
+		if info, err := os.Lstat(a.Path); err == nil {
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("writing %s: not a regular file", a.Path)
+			}
+			e.Existed = true
+			e.Mode = info.Mode().Perm()
+			if err := os.MkdirAll(filepath.Join(j.Dir(), "before"), 0755); err != nil {
+				return err
+			}
+			if err := copyFile(a.Path, filepath.Join(j.Dir(), backup), 0600); err != nil {
+				return fmt.Errorf("saving before-image of %s: %w", a.Path, err)
+			}
+			e.Backup = backup
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		// Persist the before-image reference before touching the file.
+		if err := j.saveLocked(); err != nil {
+			return fmt.Errorf("saving fix journal: %w", err)
+		}
+		sum := sha256.Sum256(a.Content)
+		e.Written = hex.EncodeToString(sum[:])
+		if err := writeActionFile(a); err != nil {
+			return err
+		}
+		e.Applied = true
+		return nil
+
+	default:
+		if err := applyAction(a); err != nil {
+			return err
+		}
+		e.Applied = true
+		return nil
+	}
+}
+
+func (j *Journal) saveLocked() error {
+	if err := os.MkdirAll(j.Dir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(j.Dir(), "journal.json"), data, 0644)
+}
+
+// UndoResult reports what Undo did with each journal entry.
+type UndoResult struct {
+	Restored []string
+	Skipped  []string // Entries left alone, with the reason
+}
+
+// Undo reverts the journal's applied actions in reverse order. Files changed
+// since the fix are left alone rather than overwritten, and run actions
+// without an undo command are reported as skipped. A journal can only be
+// undone once.
+func (j *Journal) Undo() (*UndoResult, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.Undone != nil {
+		return nil, fmt.Errorf("fix run %s was already undone at %s", j.RunID, j.Undone.Local().Format(time.RFC3339))
+	}
+
+	res := &UndoResult{}
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := &j.Entries[i]
+		if !e.Applied {
+			continue
+		}
+		label := e.Description
+		if e.Path != "" {
+			label = fmt.Sprintf("%s %s", e.Kind, relToTown(j.TownRoot, e.Path))
+		}
+		if reason := j.undoEntry(e); reason != "" {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("%s: %s", label, reason))
+		} else {
+			res.Restored = append(res.Restored, label)
+		}
+	}
+
+	now := time.Now().UTC()
+	j.Undone = &now
+	if err := j.saveLocked(); err != nil {
+		return res, fmt.Errorf("saving fix journal: %w", err)
+	}
+	return res, nil
+}
+
+// undoEntry reverts one entry, returning why it was skipped if it wasn't.
+func (j *Journal) undoEntry(e *JournalEntry) string {
+	switch e.Kind {
+	case ActionRemove:
+		if _, err := os.Lstat(e.Path); err == nil {
+			return "path exists again, before-image kept at " + filepath.Join(j.Dir(), e.Backup)
+		}
+		if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+			return err.Error()
+		}
+		if err := movePath(filepath.Join(j.Dir(), e.Backup), e.Path); err != nil {
+			return err.Error()
+		}
+		return ""
+
+	case ActionWrite:
+		current, err := fileSHA256(e.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err.Error()
+		}
+		if current != e.Written {
+			return "changed since the fix, left as is"
+		}
+		if !e.Existed {
+			if err := os.Remove(e.Path); err != nil {
+				return err.Error()
+			}
+			return ""
+		}
+		if err := copyFile(filepath.Join(j.Dir(), e.Backup), e.Path, e.Mode); err != nil {
+			return err.Error()
+		}
+		return ""
+
+	case ActionOpaque:
+		return "fix ran without a plan, its changes were not recorded"
+
+	default:
+		if len(e.UndoCommand) == 0 {
+			return "cannot be undone automatically"
+		}
+		cmd := exec.Command(e.UndoCommand[0], e.UndoCommand[1:]...) //nolint:gosec // G204: command recorded by doctor itself
+		cmd.Dir = e.UndoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Sprintf("%s: %v: %s", strings.Join(e.UndoCommand, " "), err, strings.TrimSpace(string(out)))
+		}
+		return ""
+	}
+}
+
+// movePath renames src to dst, falling back to copy and delete across
+// filesystems.
+func movePath(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(dst, mode)
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
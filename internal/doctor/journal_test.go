@@ -0,0 +1,300 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestJournal_ApplyAndUndo(t *testing.T) {
+	town := t.TempDir()
+	staleDir := filepath.Join(town, "rig", ".beads", "mq")
+	writeTestFile(t, filepath.Join(staleDir, "item"), "queued")
+	redirect := filepath.Join(town, "rig", ".beads", "redirect")
+	writeTestFile(t, redirect, "old\n")
+	created := filepath.Join(town, "rig", "crew", "max", ".beads", "redirect")
+
+	plan := &FixPlan{Check: "test"}
+	plan.Remove("", staleDir, "")
+	plan.Write("", redirect, []byte("new\n"), 0644, "")
+	plan.Write("", created, []byte("target\n"), 0644, "")
+
+	j := NewJournal(town)
+	if err := plan.Apply(j); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Error("removed directory still exists")
+	}
+	if got := readTestFile(t, redirect); got != "new\n" {
+		t.Errorf("redirect = %q after fix", got)
+	}
+
+	loaded, err := LoadJournal(town, j.RunID)
+	if err != nil {
+		t.Fatalf("LoadJournal() = %v", err)
+	}
+	res, err := loaded.Undo()
+	if err != nil {
+		t.Fatalf("Undo() = %v", err)
+	}
+	if len(res.Restored) != 3 || len(res.Skipped) != 0 {
+		t.Errorf("Undo() = %+v, want 3 restored", res)
+	}
+	if got := readTestFile(t, filepath.Join(staleDir, "item")); got != "queued" {
+		t.Errorf("restored file = %q", got)
+	}
+	if got := readTestFile(t, redirect); got != "old\n" {
+		t.Errorf("redirect = %q after undo, want before-image", got)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("file created by the fix should be removed by undo")
+	}
+
+	if _, err := loaded.Undo(); err == nil {
+		t.Error("second Undo() should fail")
+	}
+	if again, _ := LoadJournal(town, j.RunID); again.Undone == nil {
+		t.Error("journal not marked undone on disk")
+	}
+}
+
+func TestJournal_UndoSkipsChangedFiles(t *testing.T) {
+	town := t.TempDir()
+	removed := filepath.Join(town, "daemon.log")
+	writeTestFile(t, removed, "log")
+	written := filepath.Join(town, "redirect")
+	writeTestFile(t, written, "old")
+
+	plan := &FixPlan{Check: "test"}
+	plan.Remove("", removed, "")
+	plan.Write("", written, []byte("new"), 0644, "")
+	j := NewJournal(town)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both paths change again after the fix
+	writeTestFile(t, removed, "recreated")
+	writeTestFile(t, written, "edited")
+
+	res, err := j.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Skipped) != 2 || len(res.Restored) != 0 {
+		t.Errorf("Undo() = %+v, want both entries skipped", res)
+	}
+	if got := readTestFile(t, removed); got != "recreated" {
+		t.Errorf("undo overwrote a recreated file: %q", got)
+	}
+	if got := readTestFile(t, written); got != "edited" {
+		t.Errorf("undo overwrote an edited file: %q", got)
+	}
+}
+
+func TestFixPlan_GroupFailureSkipsRestOfGroup(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) func() error {
+		return func() error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+
+	plan := &FixPlan{Check: "test", Unfixable: []string{"no bare repo"}}
+	plan.RunStep("a", "a1", step("a1", errors.New("boom")))
+	plan.RunStep("a", "a2", step("a2", nil))
+	plan.RunStep("b", "b1", step("b1", nil))
+	plan.RunStep("", "c", step("c", nil))
+
+	err := plan.Apply(nil)
+	if got := strings.Join(ran, ","); got != "a1,b1,c" {
+		t.Errorf("ran %s, want a2 skipped after a1 failed", got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "boom") || !strings.Contains(err.Error(), "no bare repo") {
+		t.Errorf("Apply() = %v, want action error and unfixable problem", err)
+	}
+}
+
+func TestJournal_RunActionUndo(t *testing.T) {
+	town := t.TempDir()
+	marker := filepath.Join(town, "marker")
+
+	plan := &FixPlan{Check: "test"}
+	plan.RunStep("", "kill session", func() error { return nil })
+	undoable := plan.RunStep("", "touch marker", func() error { return nil })
+	undoable.UndoCommand = []string{"touch", marker}
+
+	j := NewJournal(town)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+	res, err := j.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Restored) != 1 || len(res.Skipped) != 1 {
+		t.Errorf("Undo() = %+v, want the undo command run and the other action skipped", res)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("undo command did not run")
+	}
+}
+
+func TestListJournals(t *testing.T) {
+	town := t.TempDir()
+	if js, err := ListJournals(town); err != nil || len(js) != 0 {
+		t.Fatalf("ListJournals() on empty town = %v, %v", js, err)
+	}
+
+	j := NewJournal(town)
+	plan := &FixPlan{Check: "test"}
+	plan.Write("", filepath.Join(town, "f"), []byte("x"), 0644, "")
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+	// A journal with no recorded actions is never written
+	NewJournal(town)
+
+	js, err := ListJournals(town)
+	if err != nil || len(js) != 1 || js[0].RunID != j.RunID {
+		t.Errorf("ListJournals() = %v, %v", js, err)
+	}
+	if _, err := LoadJournal(town, "../escape"); err == nil {
+		t.Error("LoadJournal() should reject path-like run IDs")
+	}
+}
+
+func TestFixStreaming_DryRunDoesNotApply(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, "gastown", ".beads")
+	writeTestFile(t, filepath.Join(beadsDir, "redirect"), "../mayor/rig/.beads\n")
+	writeTestFile(t, filepath.Join(beadsDir, "daemon.log"), "log")
+	writeTestFile(t, filepath.Join(beadsDir, "beads.db-wal"), "wal")
+
+	d := NewDoctor()
+	d.Register(NewStaleBeadsRedirectCheck())
+	report := d.Fix(&CheckContext{TownRoot: town, DryRun: true})
+
+	plan := report.Checks[0].Plan
+	if plan == nil {
+		t.Fatal("dry run should attach the fix plan to the result")
+	}
+	var removed []string
+	for _, a := range plan.Actions {
+		if a.Kind == ActionRemove {
+			removed = append(removed, filepath.Base(a.Path))
+		}
+	}
+	if got := strings.Join(removed, ","); got != "beads.db-wal,daemon.log" {
+		t.Errorf("planned removals = %s", got)
+	}
+	if report.Checks[0].Fixed {
+		t.Error("dry run should not report the check as fixed")
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "daemon.log")); err != nil {
+		t.Error("dry run removed a file")
+	}
+	if _, err := os.Stat(JournalRoot(town)); !os.IsNotExist(err) {
+		t.Error("dry run should not write a journal")
+	}
+}
+
+func TestFixStreaming_OpaqueFixRecordedAsNotUndoable(t *testing.T) {
+	town := t.TempDir()
+	check := newMockCheck("opaque", StatusWarning)
+	check.fixable = true
+
+	d := NewDoctor()
+	d.Register(check)
+	j := NewJournal(town)
+	d.Fix(&CheckContext{TownRoot: town, Journal: j})
+
+	if check.fixCount != 1 {
+		t.Fatalf("fix ran %d times, want 1", check.fixCount)
+	}
+	if j.Len() != 1 || j.Opaque() != 1 || j.Entries[0].Kind != ActionOpaque {
+		t.Fatalf("journal entries = %+v, want one opaque entry", j.Entries)
+	}
+
+	loaded, err := LoadJournal(town, j.RunID)
+	if err != nil {
+		t.Fatalf("LoadJournal() = %v", err)
+	}
+	res, err := loaded.Undo()
+	if err != nil {
+		t.Fatalf("Undo() = %v", err)
+	}
+	if len(res.Restored) != 0 || len(res.Skipped) != 1 || !strings.Contains(res.Skipped[0], "not recorded") {
+		t.Errorf("Undo() = %+v, want the opaque fix skipped", res)
+	}
+}
+
+func TestFixStreaming_DryRunListsOpaqueFix(t *testing.T) {
+	check := newMockCheck("opaque", StatusWarning)
+	check.fixable = true
+
+	d := NewDoctor()
+	d.Register(check)
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir(), DryRun: true})
+
+	plan := report.Checks[0].Plan
+	if plan == nil || !plan.Opaque {
+		t.Fatalf("plan = %+v, want opaque", plan)
+	}
+	if lines := plan.Lines(""); len(lines) != 1 || !strings.HasPrefix(lines[0], "opaque") || !strings.Contains(lines[0], "not undoable") {
+		t.Errorf("Lines() = %q", lines)
+	}
+	if check.fixCount != 0 {
+		t.Error("dry run should not run the fix")
+	}
+}
+
+func TestPruneJournals(t *testing.T) {
+	town := t.TempDir()
+	var runs []*Journal
+	for i := 0; i < 4; i++ {
+		j := NewJournal(town)
+		j.RunID = fmt.Sprintf("20260101-00000%d-abcdef", i)
+		j.Started = j.Started.Add(time.Duration(i) * time.Second)
+		if err := j.RecordOpaque("test", nil); err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, j)
+	}
+
+	if err := PruneJournals(town, 2); err != nil {
+		t.Fatalf("PruneJournals() = %v", err)
+	}
+	js, err := ListJournals(town)
+	if err != nil || len(js) != 2 || js[0].RunID != runs[3].RunID || js[1].RunID != runs[2].RunID {
+		t.Errorf("after pruning, journals = %v, %v; want the two newest", js, err)
+	}
+	if _, err := os.Stat(runs[0].Dir()); !os.IsNotExist(err) {
+		t.Error("oldest journal directory not removed")
+	}
+}
@@ -27,6 +27,7 @@ func NewCheckJSONLBloat() *CheckJSONLBloat {
 			CheckName:        "jsonl-bloat",
 			CheckDescription: "Detect stale/bloated issues.jsonl vs live database",
 			CheckCategory:    CategoryCleanup,
+			CheckDependsOn:   []string{"dolt-server-reachable"},
 		},
 	}
 }
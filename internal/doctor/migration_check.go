@@ -370,6 +370,7 @@ func NewDoltOrphanedDatabaseCheck() *DoltOrphanedDatabaseCheck {
 				CheckName:        "dolt-orphaned-databases",
 				CheckDescription: "Detect orphaned databases in .dolt-data/",
 				CheckCategory:    CategoryCleanup,
+				CheckDependsOn:   []string{"dolt-server-reachable"},
 			},
 		},
 	}
@@ -414,14 +415,28 @@ func (c *DoltOrphanedDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// PlanFix plans removing each orphaned database. Dropped databases cannot
+// be restored by undo; the first failure stops the rest.
+func (c *DoltOrphanedDatabaseCheck) PlanFix(ctx *CheckContext) (*FixPlan, error) {
+	plan := &FixPlan{Check: c.Name()}
+	for _, name := range c.orphanNames {
+		plan.RunStep("orphaned-databases", "remove orphaned database "+name, func() error {
+			if err := doltserver.RemoveDatabase(ctx.TownRoot, name, true); err != nil {
+				return fmt.Errorf("removing orphaned database %s: %w", name, err)
+			}
+			return nil
+		})
+	}
+	return plan, nil
+}
+
 // Fix removes orphaned databases.
 func (c *DoltOrphanedDatabaseCheck) Fix(ctx *CheckContext) error {
-	for _, name := range c.orphanNames {
-		if err := doltserver.RemoveDatabase(ctx.TownRoot, name, true); err != nil {
-			return fmt.Errorf("removing orphaned database %s: %w", name, err)
-		}
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
 	}
-	return nil
+	return plan.Apply(ctx.Journal)
 }
 
 // formatBytes returns a human-readable size string.
@@ -40,6 +40,7 @@ func NewNullAssigneeCheck() *NullAssigneeCheck {
 				CheckName:        "null-assignee-steps",
 				CheckDescription: "Check for in_progress beads with NULL assignee (invisible to bd, blocking indefinitely)",
 				CheckCategory:    CategoryCleanup,
+				CheckDependsOn:   []string{"dolt-server-reachable"},
 			},
 		},
 	}
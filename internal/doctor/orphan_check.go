@@ -131,14 +131,11 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
-// Fix kills all orphaned sessions, except crew sessions which are protected.
-func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
-	if len(c.orphanSessions) == 0 {
-		return nil
-	}
-
+// PlanFix plans killing each orphaned session, except crew sessions which
+// are protected. Killed sessions cannot be restored by undo.
+func (c *OrphanSessionCheck) PlanFix(ctx *CheckContext) (*FixPlan, error) {
+	plan := &FixPlan{Check: c.Name()}
 	t := tmux.NewTmux()
-	var lastErr error
 
 	for _, sess := range c.orphanSessions {
 		// SAFEGUARD: Never auto-kill crew sessions.
@@ -146,16 +143,25 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 		if isCrewSession(sess) {
 			continue
 		}
-		// Log pre-death event for crash investigation (before killing)
-		_ = events.LogFeed(events.TypeSessionDeath, sess,
-			events.SessionDeathPayload(sess, "unknown", "orphan cleanup", "gt doctor"))
-		// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-		if err := t.KillSessionWithProcesses(sess); err != nil {
-			lastErr = err
-		}
+		plan.RunStep("", "kill orphaned session "+sess, func() error {
+			// Log pre-death event for crash investigation (before killing)
+			_ = events.LogFeed(events.TypeSessionDeath, sess,
+				events.SessionDeathPayload(sess, "unknown", "orphan cleanup", "gt doctor"))
+			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
+			return t.KillSessionWithProcesses(sess)
+		})
 	}
 
-	return lastErr
+	return plan, nil
+}
+
+// Fix kills all orphaned sessions, except crew sessions which are protected.
+func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(ctx.Journal)
 }
 
 // isCrewSession returns true if the session name matches the crew pattern.
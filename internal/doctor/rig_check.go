@@ -104,6 +104,7 @@ func NewGitExcludeConfiguredCheck() *GitExcludeConfiguredCheck {
 				CheckName:        "git-exclude-configured",
 				CheckDescription: "Check .git/info/exclude has Gas Town directories",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -258,6 +259,7 @@ func NewHooksPathConfiguredCheck() *HooksPathConfiguredCheck {
 				CheckName:        "hooks-path-configured",
 				CheckDescription: "Check core.hooksPath is set for all clones",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
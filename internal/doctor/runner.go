@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultParallelism is how many checks RunStreaming runs at once unless
+// overridden with SetParallelism.
+const DefaultParallelism = 8
+
+// dependentCheck is implemented by checks that only make sense after other
+// checks have passed (e.g. database checks after dolt-server-reachable).
+type dependentCheck interface {
+	DependsOn() []string
+}
+
+// checkPlan orders the registered checks by their dependencies.
+type checkPlan struct {
+	checks []Check
+	deps   [][]int // indexes of each check's registered prerequisites
+	order  []int   // dependency order, otherwise registration order
+	cyclic []bool  // checks on, or depending on, a dependency cycle
+}
+
+// plan resolves check dependencies by name. Dependencies on checks that are
+// not registered (e.g. rig checks without --rig) are ignored.
+func (d *Doctor) plan() *checkPlan {
+	n := len(d.checks)
+	p := &checkPlan{
+		checks: d.checks,
+		deps:   make([][]int, n),
+		cyclic: make([]bool, n),
+	}
+
+	byName := make(map[string]int, n)
+	for i, check := range d.checks {
+		if _, dup := byName[check.Name()]; !dup {
+			byName[check.Name()] = i
+		}
+	}
+	pending := make([]int, n)
+	for i, check := range d.checks {
+		dc, ok := check.(dependentCheck)
+		if !ok {
+			continue
+		}
+		for _, name := range dc.DependsOn() {
+			if j, ok := byName[name]; ok && j != i {
+				p.deps[i] = append(p.deps[i], j)
+				pending[i]++
+			}
+		}
+	}
+
+	// Kahn's algorithm, always taking the earliest-registered ready check so
+	// the order matches registration wherever dependencies allow.
+	placed := make([]bool, n)
+	for len(p.order) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !placed[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		placed[next] = true
+		p.order = append(p.order, next)
+		for i := 0; i < n; i++ {
+			for _, dep := range p.deps[i] {
+				if dep == next {
+					pending[i]--
+				}
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		if !placed[i] {
+			p.cyclic[i] = true
+			p.order = append(p.order, i)
+		}
+	}
+	return p
+}
+
+// blocked returns the result for check i if it must not run: it is part of
+// a dependency cycle, or a prerequisite failed or was itself skipped.
+// Prerequisite results must already be available.
+func (p *checkPlan) blocked(i int, results []*CheckResult) *CheckResult {
+	check := p.checks[i]
+	if p.cyclic[i] {
+		return withCategory(check, &CheckResult{
+			Name:    check.Name(),
+			Status:  StatusError,
+			Message: "not run: dependency cycle",
+			FixHint: "Check the DependsOn declarations of the registered checks",
+		})
+	}
+	var failed []string
+	for _, dep := range p.deps[i] {
+		if r := results[dep]; r != nil && (r.Status == StatusError || r.Skipped) {
+			failed = append(failed, p.checks[dep].Name())
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return withCategory(check, &CheckResult{
+		Name:    check.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("skipped (requires %s)", strings.Join(failed, ", ")),
+		Skipped: true,
+	})
+}
+
+// runCheck runs a single check, timing it and filling in name and category.
+func runCheck(check Check, ctx *CheckContext) *CheckResult {
+	start := time.Now()
+	result := check.Run(ctx)
+	result.Elapsed = time.Since(start)
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	return withCategory(check, result)
+}
+
+// withCategory sets the result's category from the check if it has one.
+func withCategory(check Check, result *CheckResult) *CheckResult {
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
+	return result
+}
@@ -0,0 +1,205 @@
+package doctor
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// funcCheck runs an arbitrary function, for exercising the runner.
+type funcCheck struct {
+	BaseCheck
+	run func() CheckStatus
+}
+
+func newFuncCheck(name string, run func() CheckStatus, deps ...string) *funcCheck {
+	return &funcCheck{
+		BaseCheck: BaseCheck{CheckName: name, CheckDependsOn: deps},
+		run:       run,
+	}
+}
+
+func (f *funcCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{Name: f.CheckName, Status: f.run()}
+}
+
+func withDeps(c *mockCheck, deps ...string) *mockCheck {
+	c.CheckDependsOn = deps
+	return c
+}
+
+func resultNames(r *Report) []string {
+	var names []string
+	for _, c := range r.Checks {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestRunStreaming_RunsIndependentChecksConcurrently(t *testing.T) {
+	// Each check waits until both have started; run sequentially, they
+	// would time out.
+	var started sync.WaitGroup
+	started.Add(2)
+	meet := func() CheckStatus {
+		started.Done()
+		ch := make(chan struct{})
+		go func() { started.Wait(); close(ch) }()
+		select {
+		case <-ch:
+			return StatusOK
+		case <-time.After(5 * time.Second):
+			return StatusError
+		}
+	}
+
+	d := NewDoctor()
+	d.RegisterAll(newFuncCheck("a", meet), newFuncCheck("b", meet))
+	report := d.Run(&CheckContext{})
+	if report.Summary.OK != 2 {
+		t.Errorf("OK = %d, want 2 (checks did not overlap)", report.Summary.OK)
+	}
+}
+
+func TestRunStreaming_BoundsParallelism(t *testing.T) {
+	var running, peak int32
+	work := func() CheckStatus {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return StatusOK
+	}
+
+	d := NewDoctor()
+	d.SetParallelism(2)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		d.Register(newFuncCheck(name, work))
+	}
+	d.Run(&CheckContext{})
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+}
+
+func TestRunStreaming_OrderedOutput(t *testing.T) {
+	slow := func() CheckStatus { time.Sleep(30 * time.Millisecond); return StatusOK }
+	fast := func() CheckStatus { return StatusWarning }
+
+	d := NewDoctor()
+	d.RegisterAll(newFuncCheck("slow", slow), newFuncCheck("fast", fast), newFuncCheck("after", slow, "slow"))
+
+	var buf bytes.Buffer
+	report := d.RunStreaming(&CheckContext{}, &buf, 0)
+
+	if got := strings.Join(resultNames(report), ","); got != "slow,fast,after" {
+		t.Errorf("report order = %s, want registration order", got)
+	}
+	out := buf.String()
+	if i, j := strings.Index(out, "slow"), strings.Index(out, "fast"); i < 0 || j < i {
+		t.Errorf("streamed output out of order:\n%s", out)
+	}
+}
+
+func TestRunStreaming_SkipsDependentsOfFailedChecks(t *testing.T) {
+	ran := map[string]bool{}
+	var mu sync.Mutex
+	check := func(name string, status CheckStatus, deps ...string) Check {
+		return newFuncCheck(name, func() CheckStatus {
+			mu.Lock()
+			ran[name] = true
+			mu.Unlock()
+			return status
+		}, deps...)
+	}
+
+	d := NewDoctor()
+	d.RegisterAll(
+		check("server", StatusError),
+		check("db", StatusOK, "server"),
+		check("tables", StatusOK, "db"),
+		check("warned", StatusWarning),
+		check("after-warning", StatusOK, "warned"),
+		check("missing-dep", StatusOK, "not-registered"),
+	)
+	report := d.Run(&CheckContext{})
+
+	if ran["db"] || ran["tables"] {
+		t.Error("dependents of a failed check should not run")
+	}
+	if !ran["after-warning"] || !ran["missing-dep"] {
+		t.Error("warnings and unregistered dependencies should not block checks")
+	}
+	if report.Summary.Skipped != 2 || report.Summary.Errors != 1 || report.Summary.OK != 2 {
+		t.Errorf("summary = %+v, want 2 skipped, 1 error, 2 ok", report.Summary)
+	}
+	if msg := report.Checks[1].Message; !strings.Contains(msg, "server") {
+		t.Errorf("skip message = %q, want it to name the failed prerequisite", msg)
+	}
+}
+
+func TestRunStreaming_DependencyCycle(t *testing.T) {
+	d := NewDoctor()
+	d.RegisterAll(
+		withDeps(newMockCheck("a", StatusOK), "b"),
+		withDeps(newMockCheck("b", StatusOK), "a"),
+		withDeps(newMockCheck("c", StatusOK), "a"),
+		newMockCheck("d", StatusOK),
+	)
+
+	done := make(chan *Report)
+	go func() { done <- d.Run(&CheckContext{}) }()
+	select {
+	case report := <-done:
+		if report.Summary.Errors != 3 || report.Summary.OK != 1 {
+			t.Errorf("summary = %+v, want cyclic checks reported as errors", report.Summary)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() deadlocked on a dependency cycle")
+	}
+}
+
+func TestPlan_OrdersDependenciesFirst(t *testing.T) {
+	d := NewDoctor()
+	d.RegisterAll(
+		withDeps(newMockCheck("late-dependent", StatusOK), "prereq"),
+		newMockCheck("other", StatusOK),
+		newMockCheck("prereq", StatusOK),
+	)
+	p := d.plan()
+	var order []string
+	for _, i := range p.order {
+		order = append(order, p.checks[i].Name())
+	}
+	if got := strings.Join(order, ","); got != "other,prereq,late-dependent" {
+		t.Errorf("plan order = %s", got)
+	}
+}
+
+func TestFixStreaming_Dependencies(t *testing.T) {
+	fixable := newMockCheck("fixable-prereq", StatusError)
+	fixable.fixable = true
+	broken := newMockCheck("broken-prereq", StatusError)
+	afterFix := withDeps(newMockCheck("after-fix", StatusOK), "fixable-prereq")
+	afterBroken := withDeps(newMockCheck("after-broken", StatusError), "broken-prereq")
+	afterBroken.fixable = true
+
+	d := NewDoctor()
+	d.RegisterAll(fixable, broken, afterFix, afterBroken)
+	report := d.Fix(&CheckContext{})
+
+	if !report.Checks[0].Fixed || report.Checks[2].Skipped {
+		t.Error("a dependent of a fixed check should run")
+	}
+	if !report.Checks[3].Skipped || afterBroken.fixCount != 0 {
+		t.Error("a dependent of a check that is still failing should be skipped, not fixed")
+	}
+}
@@ -149,34 +149,60 @@ func (c *StaleBeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
-// Fix removes stale files from .beads directories that have redirects,
-// and creates/repairs missing or incorrect redirects.
-func (c *StaleBeadsRedirectCheck) Fix(ctx *CheckContext) error {
+// PlanFix lists the stale files to remove and the redirects to write.
+// Each location is planned as its own group, so one failing worktree does
+// not stop the others.
+func (c *StaleBeadsRedirectCheck) PlanFix(ctx *CheckContext) (*FixPlan, error) {
+	plan := &FixPlan{Check: c.Name()}
+
 	// Remove stale files
 	for _, relPath := range c.staleLocations {
 		beadsDir := filepath.Join(ctx.TownRoot, relPath)
-		if err := cleanStaleBeadsFiles(beadsDir); err != nil {
-			return fmt.Errorf("cleaning %s: %w", relPath, err)
+		// Verify redirect exists before cleaning
+		if _, err := os.Stat(filepath.Join(beadsDir, "redirect")); os.IsNotExist(err) {
+			plan.Unfixable = append(plan.Unfixable, fmt.Sprintf("cleaning %s: no redirect file found - refusing to clean", relPath))
+			continue
+		}
+		for _, path := range staleBeadsFiles(beadsDir) {
+			plan.Remove(relPath, path, "stale beads file")
 		}
 	}
 
-	// Create missing redirects
-	for _, issue := range c.missingRedirects {
-		if err := beads.SetupRedirect(issue.townRoot, issue.worktreePath); err != nil {
-			relPath, _ := filepath.Rel(ctx.TownRoot, issue.worktreePath)
-			return fmt.Errorf("creating redirect for %s: %w", relPath, err)
+	// Create missing redirects and fix incorrect ones (same as creating)
+	for _, issue := range append(append([]redirectIssue{}, c.missingRedirects...), c.incorrectRedirects...) {
+		relPath, _ := filepath.Rel(ctx.TownRoot, issue.worktreePath)
+		setup, err := beads.PlanRedirect(issue.townRoot, issue.worktreePath)
+		if err != nil {
+			plan.Unfixable = append(plan.Unfixable, fmt.Sprintf("redirect for %s: %v", relPath, err))
+			continue
 		}
-	}
 
-	// Fix incorrect redirects (same as creating - SetupRedirect overwrites)
-	for _, issue := range c.incorrectRedirects {
-		if err := beads.SetupRedirect(issue.townRoot, issue.worktreePath); err != nil {
-			relPath, _ := filepath.Rel(ctx.TownRoot, issue.worktreePath)
-			return fmt.Errorf("fixing redirect for %s: %w", relPath, err)
+		// The redirect itself is overwritten below, which keeps its
+		// before-image; the rest is removed as SetupRedirect would.
+		for _, path := range setup.Remove {
+			switch path {
+			case setup.Redirect:
+			case filepath.Dir(setup.Redirect):
+				plan.Remove(relPath, path, ".beads is a file")
+			default:
+				plan.Remove(relPath, path, "beads runtime file")
+			}
 		}
+		plan.Write(relPath, setup.Redirect, []byte(setup.Target+"\n"), 0644,
+			"redirect to "+setup.Target)
 	}
 
-	return nil
+	return plan, nil
+}
+
+// Fix removes stale files from .beads directories that have redirects,
+// and creates/repairs missing or incorrect redirects.
+func (c *StaleBeadsRedirectCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(ctx.Journal)
 }
 
 // findRigDirs returns all rig directories in the town.
@@ -300,24 +326,21 @@ func hasRedirectWithStaleFiles(beadsDir string) bool {
 	return false
 }
 
-// cleanStaleBeadsFiles removes stale files from a .beads directory,
-// preserving the redirect file and .gitignore.
-func cleanStaleBeadsFiles(beadsDir string) error {
-	// Verify redirect exists before cleaning
-	redirectPath := filepath.Join(beadsDir, "redirect")
-	if _, err := os.Stat(redirectPath); os.IsNotExist(err) {
-		return fmt.Errorf("no redirect file found - refusing to clean")
-	}
-
-	// Remove files matching stale patterns
+// staleBeadsFiles returns the stale files in a .beads directory, including
+// the mq directory. The redirect file and .gitignore are never included.
+func staleBeadsFiles(beadsDir string) []string {
+	var files []string
+	seen := make(map[string]bool)
 	for _, pattern := range staleFilePatterns {
 		matches, err := filepath.Glob(filepath.Join(beadsDir, pattern))
 		if err != nil {
 			continue
 		}
+		// Patterns overlap (e.g. *.db-* and *.db?*)
 		for _, match := range matches {
-			if err := os.RemoveAll(match); err != nil {
-				return fmt.Errorf("removing %s: %w", filepath.Base(match), err)
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
 			}
 		}
 	}
@@ -325,12 +348,10 @@ func cleanStaleBeadsFiles(beadsDir string) error {
 	// Also remove mq directory if it exists
 	mqDir := filepath.Join(beadsDir, "mq")
 	if _, err := os.Stat(mqDir); err == nil {
-		if err := os.RemoveAll(mqDir); err != nil {
-			return fmt.Errorf("removing mq: %w", err)
-		}
+		files = append(files, mqDir)
 	}
 
-	return nil
+	return files
 }
 
 // verifyRedirectTopology checks that all worktrees in a rig have correct redirects.
@@ -59,11 +59,13 @@ func (s CheckStatus) String() string {
 
 // CheckContext provides context for running checks.
 type CheckContext struct {
-	TownRoot        string // Root directory of the Gas Town workspace
-	RigName         string // Rig name (empty for town-level checks)
-	Verbose         bool   // Enable verbose output
-	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
-	NoStart         bool   // Suppress starting daemon/agents during --fix
+	TownRoot        string   // Root directory of the Gas Town workspace
+	RigName         string   // Rig name (empty for town-level checks)
+	Verbose         bool     // Enable verbose output
+	RestartSessions bool     // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+	NoStart         bool     // Suppress starting daemon/agents during --fix
+	DryRun          bool     // With --fix, print fix plans instead of applying them
+	Journal         *Journal // Records applied fix actions for undo (nil: not recorded)
 }
 
 // RigPath returns the full path to the rig directory.
@@ -88,6 +90,8 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+	Skipped  bool          // True if not run because a prerequisite failed
+	Plan     *FixPlan      // Fix that would be applied (dry-run only)
 }
 
 // Check defines the interface for a health check.
@@ -116,6 +120,7 @@ type ReportSummary struct {
 	Warnings    int
 	Errors      int
 	Fixed       int           // Checks that were auto-fixed
+	Skipped     int           // Checks skipped because a prerequisite failed
 	Slow        int           // Checks that took longer than threshold (counted during Print)
 	SlowestName string        // Name of the slowest check
 	SlowestTime time.Duration // Duration of the slowest check
@@ -141,6 +146,11 @@ func (r *Report) Add(result *CheckResult) {
 	r.Checks = append(r.Checks, result)
 	r.Summary.Total++
 
+	if result.Skipped {
+		r.Summary.Skipped++
+		return
+	}
+
 	switch result.Status {
 	case StatusOK:
 		r.Summary.OK++
@@ -272,12 +282,14 @@ func (r *Report) Print(w io.Writer, verbose bool, slowThreshold time.Duration) {
 // printCheck outputs a single check result with semantic styling.
 func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool, slowThreshold time.Duration) {
 	var statusIcon string
-	switch check.Status {
-	case StatusOK:
+	switch {
+	case check.Skipped:
+		statusIcon = ui.RenderSkipIcon()
+	case check.Status == StatusOK:
 		statusIcon = ui.RenderPassIcon()
-	case StatusWarning:
+	case check.Status == StatusWarning:
 		statusIcon = ui.RenderWarnIcon()
-	case StatusError:
+	case check.Status == StatusError:
 		statusIcon = ui.RenderFailIcon()
 	}
 
@@ -342,6 +354,9 @@ func (r *Report) printSummary(w io.Writer, slowThreshold time.Duration) {
 	if r.Summary.Fixed > 0 {
 		summary += fmt.Sprintf("  🔧 %d fixed", r.Summary.Fixed)
 	}
+	if r.Summary.Skipped > 0 {
+		summary += fmt.Sprintf("  %s %d skipped", ui.RenderSkipIcon(), r.Summary.Skipped)
+	}
 	if slowThreshold > 0 && r.Summary.Slow > 0 {
 		summary += fmt.Sprintf("  ⏳ %d slow (slowest: %s %s)",
 			r.Summary.Slow,
@@ -217,27 +217,21 @@ func (c *WorktreeGitdirCheck) hasGitFile(path string) bool {
 	return !info.IsDir()
 }
 
-// Fix attempts to re-create broken worktrees.
-func (c *WorktreeGitdirCheck) Fix(ctx *CheckContext) error {
-	var lastErr error
+// PlanFix plans re-creating each broken worktree whose .repo.git exists:
+// remove the broken .git file, then git worktree add. Worktrees without a
+// bare repo cannot be fixed here.
+func (c *WorktreeGitdirCheck) PlanFix(ctx *CheckContext) (*FixPlan, error) {
+	plan := &FixPlan{Check: c.Name()}
 
 	for _, bw := range c.brokenWorktrees {
 		if bw.bareRepoPath == "" {
-			lastErr = fmt.Errorf("%s: cannot fix (not a .repo.git worktree)", bw.worktreePath)
+			plan.Unfixable = append(plan.Unfixable, fmt.Sprintf("%s: cannot fix (not a .repo.git worktree)", bw.worktreePath))
 			continue
 		}
 
 		// Check if .repo.git exists
 		if _, err := os.Stat(bw.bareRepoPath); os.IsNotExist(err) {
-			lastErr = fmt.Errorf("%s: cannot fix (.repo.git does not exist, needs re-clone via 'gt rig install')", bw.worktreePath)
-			continue
-		}
-
-		// .repo.git exists but worktree entry is missing - re-create the worktree.
-		// First remove the broken .git file so git worktree add can create a fresh one.
-		gitFile := filepath.Join(bw.worktreePath, ".git")
-		if err := os.Remove(gitFile); err != nil {
-			lastErr = fmt.Errorf("%s: cannot remove broken .git file: %w", bw.worktreePath, err)
+			plan.Unfixable = append(plan.Unfixable, fmt.Sprintf("%s: cannot fix (.repo.git does not exist, needs re-clone via 'gt rig install')", bw.worktreePath))
 			continue
 		}
 
@@ -250,15 +244,31 @@ func (c *WorktreeGitdirCheck) Fix(ctx *CheckContext) error {
 			branch = strings.TrimPrefix(ref, "refs/heads/")
 		}
 
+		// .repo.git exists but worktree entry is missing - re-create the worktree.
+		// First remove the broken .git file so git worktree add can create a fresh one.
+		plan.Remove(bw.worktreePath, filepath.Join(bw.worktreePath, ".git"), "broken .git file")
+
 		// Re-create the worktree
-		cmd = exec.Command("git", "-C", bw.bareRepoPath, "worktree", "add", bw.worktreePath, branch)
-		if output, err := cmd.CombinedOutput(); err != nil {
-			lastErr = fmt.Errorf("%s: failed to re-create worktree: %v (%s)",
-				bw.worktreePath, err, strings.TrimSpace(string(output)))
-		}
+		plan.RunStep(bw.worktreePath, fmt.Sprintf("git -C %s worktree add %s %s", bw.bareRepoPath, bw.worktreePath, branch), func() error {
+			cmd := exec.Command("git", "-C", bw.bareRepoPath, "worktree", "add", bw.worktreePath, branch)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("%s: failed to re-create worktree: %v (%s)",
+					bw.worktreePath, err, strings.TrimSpace(string(output)))
+			}
+			return nil
+		})
 	}
 
-	return lastErr
+	return plan, nil
+}
+
+// Fix attempts to re-create broken worktrees.
+func (c *WorktreeGitdirCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(ctx.Journal)
 }
 
 // isRigDir checks if a directory looks like a rig (has config.json or known subdirectories).
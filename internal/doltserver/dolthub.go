@@ -98,6 +98,12 @@ func CreateDoltHubRepo(org, repo, token string) error {
 // AddRemote adds a DoltHub origin remote to a local Dolt database directory.
 // Skips if an origin remote already exists.
 func AddRemote(dbDir, org, repo string) error {
+	return AddRemoteURL(dbDir, DoltHubRemoteURL(org, repo))
+}
+
+// AddRemoteURL adds an origin remote with the given URL to a local Dolt
+// database directory. Skips if a remote already exists.
+func AddRemoteURL(dbDir, url string) error {
 	// Check if origin already exists
 	existing, err := HasRemote(dbDir)
 	if err != nil {
@@ -107,7 +113,6 @@ func AddRemote(dbDir, org, repo string) error {
 		return nil // Already has a remote
 	}
 
-	url := DoltHubRemoteURL(org, repo)
 	cmd := exec.Command("dolt", "remote", "add", "origin", url)
 	cmd.Dir = dbDir
 	output, err := cmd.CombinedOutput()
@@ -127,15 +132,22 @@ func AddRemote(dbDir, org, repo string) error {
 // error because each step requires the previous to succeed (can't add a
 // remote if repo creation failed, can't push if the remote wasn't added).
 func SetupDoltHubRemote(dbDir, org, dbName, token string) error {
+	return SetupRemote(DoltHubRemote, dbDir, org, dbName, token)
+}
+
+// SetupRemote is SetupDoltHubRemote for any remote backend: it creates the
+// repo on the backend, adds it as origin and does an initial push. token is
+// only used by DoltHub.
+func SetupRemote(remote Remote, dbDir, org, dbName, token string) error {
 	repo := DoltHubRepoName(dbName)
 
-	// Step 1: Create the DoltHub repo
-	if err := CreateDoltHubRepo(org, repo, token); err != nil {
-		return fmt.Errorf("creating DoltHub repo %s/%s: %w", org, repo, err)
+	// Step 1: Create the repo on the backend
+	if err := remote.Create(org, repo, token); err != nil {
+		return fmt.Errorf("creating remote repo %s/%s: %w", org, repo, err)
 	}
 
 	// Step 2: Add the remote locally
-	if err := AddRemote(dbDir, org, repo); err != nil {
+	if err := AddRemoteURL(dbDir, remote.URL(org, repo)); err != nil {
 		return fmt.Errorf("adding remote for %s/%s: %w", org, repo, err)
 	}
 
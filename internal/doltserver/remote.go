@@ -0,0 +1,204 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvDoltRemote selects the remote backend used for Dolt backups and
+// wasteland federation. Unset (or "dolthub") means DoltHub.
+//
+//	file:///srv/dolt-remotes            directory of file remotes
+//	http://doltsrv:50051                dolt sql-server remotesapi
+//	aws://[table:bucket]/{org}/{db}     any dolt remote URL template
+const EnvDoltRemote = "GT_DOLT_REMOTE"
+
+// EnvDoltOrg names the org (namespace) for self-hosted remotes. Falls back
+// to DOLTHUB_ORG.
+const EnvDoltOrg = "GT_DOLT_ORG"
+
+// defaultRemoteOrg namespaces self-hosted remotes when no org is set.
+// Unlike DoltHub, file and server remotes have no accounts to map to.
+const defaultRemoteOrg = "gastown"
+
+// RemoteKind identifies a remote backend.
+type RemoteKind string
+
+const (
+	// RemoteDoltHub is DoltHub (doltremoteapi.dolthub.com), created and
+	// forked through the DoltHub API.
+	RemoteDoltHub RemoteKind = "dolthub"
+
+	// RemoteFile is a directory of dolt file remotes, one per org/db.
+	RemoteFile RemoteKind = "file"
+
+	// RemoteServer is a dolt sql-server's remotesapi endpoint. Its
+	// databases are flat, so org and db are joined as <org>_<db>.
+	RemoteServer RemoteKind = "server"
+
+	// RemoteURL is any other dolt remote URL, given as a template with
+	// {org} and {db} placeholders.
+	RemoteURL RemoteKind = "url"
+)
+
+// Remote is a Dolt remote backend: where databases are pushed and cloned
+// from, addressed by org and database name.
+type Remote struct {
+	Kind RemoteKind
+
+	// Base is the file remote root directory, the server URL, or the URL
+	// template. Empty for DoltHub.
+	Base string
+}
+
+// DoltHubRemote is the default remote backend.
+var DoltHubRemote = Remote{Kind: RemoteDoltHub}
+
+// ParseRemote parses a remote backend spec, as accepted by GT_DOLT_REMOTE:
+//
+//   - "" or "dolthub": DoltHub
+//   - file:///path: file remotes under /path/<org>/<db>
+//   - a URL containing {db}: a template, e.g. gs://bucket/{org}/{db}
+//   - http(s)://host:port: a dolt sql-server, as <url>/<org>_<db>
+//   - any other scheme: <url>/<org>/<db>
+func ParseRemote(spec string) (Remote, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == string(RemoteDoltHub) {
+		return DoltHubRemote, nil
+	}
+	if strings.Contains(spec, "{db}") {
+		return Remote{Kind: RemoteURL, Base: spec}, nil
+	}
+
+	// Split on "://" rather than url.Parse: dolt URLs such as
+	// aws://[table:bucket]/path aren't valid URLs.
+	scheme, rest, ok := strings.Cut(spec, "://")
+	if !ok || scheme == "" || strings.ContainsAny(scheme, " /") {
+		return Remote{}, fmt.Errorf("invalid dolt remote %q: expected dolthub, file:///path, http://host:port or a URL template with {org} and {db}", spec)
+	}
+	base := strings.TrimRight(spec, "/")
+	switch scheme {
+	case "file":
+		if !filepath.IsAbs(rest) {
+			return Remote{}, fmt.Errorf("invalid dolt remote %q: file remotes need an absolute path (file:///path)", spec)
+		}
+		return Remote{Kind: RemoteFile, Base: filepath.Clean(rest)}, nil
+	case "http", "https":
+		if host, _, _ := strings.Cut(rest, "/"); host == "doltremoteapi.dolthub.com" {
+			return DoltHubRemote, nil
+		}
+		return Remote{Kind: RemoteServer, Base: base}, nil
+	default:
+		return Remote{Kind: RemoteURL, Base: base + "/{org}/{db}"}, nil
+	}
+}
+
+// ConfiguredRemote returns the remote backend selected by GT_DOLT_REMOTE.
+func ConfiguredRemote() (Remote, error) {
+	return ParseRemote(os.Getenv(EnvDoltRemote))
+}
+
+// AutoRemote returns the backend, org and token to give new databases a
+// remote on, or ok=false when none is configured. Self-hosted backends are
+// configured by GT_DOLT_REMOTE alone; DoltHub needs DOLTHUB_TOKEN and
+// DOLTHUB_ORG.
+func AutoRemote() (remote Remote, org, token string, ok bool, err error) {
+	remote, err = ConfiguredRemote()
+	if err != nil {
+		return Remote{}, "", "", false, err
+	}
+	org, token = remote.Org(), DoltHubToken()
+	if remote.IsDoltHub() && (org == "" || token == "") {
+		return remote, "", "", false, nil
+	}
+	return remote, org, token, true, nil
+}
+
+// Org returns the org to create remotes under, for Dolt backups and
+// wasteland forks alike: GT_DOLT_ORG, then DOLTHUB_ORG. Self-hosted remotes
+// default to "gastown"; DoltHub has no default and returns "".
+func (r Remote) Org() string {
+	if org := os.Getenv(EnvDoltOrg); org != "" {
+		return org
+	}
+	if org := DoltHubOrg(); org != "" {
+		return org
+	}
+	if r.IsDoltHub() {
+		return ""
+	}
+	return defaultRemoteOrg
+}
+
+// IsDoltHub reports whether r is DoltHub.
+func (r Remote) IsDoltHub() bool {
+	return r.Kind == RemoteDoltHub || r.Kind == ""
+}
+
+// String returns the spec r was parsed from, in canonical form. DoltHub
+// is "" so configs written before self-hosted remotes keep meaning DoltHub.
+func (r Remote) String() string {
+	switch r.Kind {
+	case RemoteFile:
+		return "file://" + filepath.ToSlash(r.Base)
+	case RemoteServer, RemoteURL:
+		return r.Base
+	}
+	return ""
+}
+
+// Describe returns a human-readable name for the backend.
+func (r Remote) Describe() string {
+	switch r.Kind {
+	case RemoteFile:
+		return "file remotes in " + r.Base
+	case RemoteServer:
+		return "dolt server " + r.Base
+	case RemoteURL:
+		return "dolt remote " + r.Base
+	}
+	return "DoltHub"
+}
+
+// URL returns the dolt remote URL for org/db.
+func (r Remote) URL(org, db string) string {
+	switch r.Kind {
+	case RemoteFile:
+		return "file://" + filepath.ToSlash(r.Path(org, db))
+	case RemoteServer:
+		return r.Base + "/" + org + "_" + db
+	case RemoteURL:
+		return strings.NewReplacer("{org}", org, "{db}", db).Replace(r.Base)
+	}
+	return DoltHubRemoteURL(org, db)
+}
+
+// Path returns the directory holding a file remote. Empty for other kinds.
+func (r Remote) Path(org, db string) string {
+	if r.Kind != RemoteFile {
+		return ""
+	}
+	return filepath.Join(r.Base, org, db)
+}
+
+// Create prepares org/db on the backend so it can be pushed to. DoltHub
+// repos are created (private) through the API with token; file remotes
+// get their directory. Server and URL remotes are used as they are: the
+// database or bucket must already accept pushes.
+func (r Remote) Create(org, db, token string) error {
+	switch r.Kind {
+	case RemoteFile:
+		if err := os.MkdirAll(r.Path(org, db), 0755); err != nil {
+			return fmt.Errorf("creating file remote: %w", err)
+		}
+		return nil
+	case RemoteServer, RemoteURL:
+		return nil
+	}
+	if token == "" {
+		return fmt.Errorf("DOLTHUB_TOKEN is required to create DoltHub repos")
+	}
+	return CreateDoltHubRepo(org, db, token)
+}
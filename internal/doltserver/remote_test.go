@@ -0,0 +1,114 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantKind RemoteKind
+		wantURL  string // for org "acme", db "wl-commons"
+		wantErr  bool
+	}{
+		{spec: "", wantKind: RemoteDoltHub, wantURL: "https://doltremoteapi.dolthub.com/acme/wl-commons"},
+		{spec: "dolthub", wantKind: RemoteDoltHub, wantURL: "https://doltremoteapi.dolthub.com/acme/wl-commons"},
+		{spec: "https://doltremoteapi.dolthub.com", wantKind: RemoteDoltHub, wantURL: "https://doltremoteapi.dolthub.com/acme/wl-commons"},
+		{spec: "file:///srv/dolt/", wantKind: RemoteFile, wantURL: "file:///srv/dolt/acme/wl-commons"},
+		{spec: "http://doltsrv:50051", wantKind: RemoteServer, wantURL: "http://doltsrv:50051/acme_wl-commons"},
+		{spec: "gs://bucket/{org}-{db}", wantKind: RemoteURL, wantURL: "gs://bucket/acme-wl-commons"},
+		{spec: "aws://[table:bucket]/remotes", wantKind: RemoteURL, wantURL: "aws://[table:bucket]/remotes/acme/wl-commons"},
+		{spec: "file://relative/path", wantErr: true},
+		{spec: "not a url", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r, err := ParseRemote(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRemote(%q) = %+v, want error", tt.spec, r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRemote(%q) error: %v", tt.spec, err)
+			}
+			if r.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", r.Kind, tt.wantKind)
+			}
+			if got := r.URL("acme", "wl-commons"); got != tt.wantURL {
+				t.Errorf("URL() = %q, want %q", got, tt.wantURL)
+			}
+
+			// String round-trips through ParseRemote.
+			again, err := ParseRemote(r.String())
+			if err != nil || again != r {
+				t.Errorf("ParseRemote(String()) = %+v, %v; want %+v", again, err, r)
+			}
+		})
+	}
+}
+
+func TestRemoteOrg(t *testing.T) {
+	t.Setenv(EnvDoltOrg, "")
+	t.Setenv("DOLTHUB_ORG", "")
+
+	file := Remote{Kind: RemoteFile, Base: "/srv"}
+	if got := file.Org(); got != defaultRemoteOrg {
+		t.Errorf("self-hosted Org() = %q, want %q", got, defaultRemoteOrg)
+	}
+	if got := DoltHubRemote.Org(); got != "" {
+		t.Errorf("DoltHub Org() = %q, want empty", got)
+	}
+
+	t.Setenv("DOLTHUB_ORG", "hub-org")
+	if got := file.Org(); got != "hub-org" {
+		t.Errorf("Org() = %q, want DOLTHUB_ORG fallback", got)
+	}
+	t.Setenv(EnvDoltOrg, "corp")
+	if got := file.Org(); got != "corp" {
+		t.Errorf("Org() = %q, want %s", got, EnvDoltOrg)
+	}
+}
+
+func TestAutoRemote(t *testing.T) {
+	t.Setenv(EnvDoltOrg, "")
+	t.Setenv("DOLTHUB_ORG", "")
+	t.Setenv("DOLTHUB_TOKEN", "")
+
+	t.Setenv(EnvDoltRemote, "")
+	if _, _, _, ok, err := AutoRemote(); ok || err != nil {
+		t.Errorf("DoltHub without credentials: ok=%v err=%v, want not configured", ok, err)
+	}
+
+	t.Setenv(EnvDoltRemote, "file:///srv/dolt")
+	remote, org, token, ok, err := AutoRemote()
+	if err != nil || !ok {
+		t.Fatalf("file remote: ok=%v err=%v", ok, err)
+	}
+	if remote.Kind != RemoteFile || org != defaultRemoteOrg || token != "" {
+		t.Errorf("AutoRemote() = %+v, %q, %q", remote, org, token)
+	}
+
+	t.Setenv(EnvDoltRemote, "::bad")
+	if _, _, _, _, err := AutoRemote(); err == nil {
+		t.Error("expected error for invalid GT_DOLT_REMOTE")
+	}
+}
+
+func TestRemoteCreate_File(t *testing.T) {
+	base := t.TempDir()
+	r := Remote{Kind: RemoteFile, Base: base}
+	if err := r.Create("acme", "beads-gt", ""); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(base, "acme", "beads-gt")); err != nil || !info.IsDir() {
+		t.Errorf("file remote directory not created: %v", err)
+	}
+
+	if err := DoltHubRemote.Create("acme", "beads-gt", ""); err == nil {
+		t.Error("DoltHub Create() without token should fail")
+	}
+}
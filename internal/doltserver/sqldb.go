@@ -0,0 +1,147 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// EnvDoltCLI forces data access through the dolt CLI even when the sql-server
+// is reachable. Set GT_DOLT_CLI=1 to rule out the SQL driver when debugging.
+const EnvDoltCLI = "GT_DOLT_CLI"
+
+const (
+	// sqlConnectTimeout bounds connecting to the sql-server, so callers
+	// fall back to the CLI quickly when it is down.
+	sqlConnectTimeout = 2 * time.Second
+
+	// sqlTimeout bounds one data-access call, matching doltSQLQuery.
+	sqlTimeout = 15 * time.Second
+)
+
+var (
+	sqlPoolsMu sync.Mutex
+	sqlPools   = make(map[string]*sql.DB) // keyed by DSN
+)
+
+// errNothingToCommit is returned by sqlCommitTx when a write changed no rows.
+// Its text matches DOLT_COMMIT's error so isNothingToCommit treats the SQL
+// and CLI paths alike.
+var errNothingToCommit = errors.New("nothing to commit: no rows changed")
+
+// DSN returns a go-sql-driver/mysql DSN for database on the town's dolt
+// sql-server. TIMESTAMP columns scan into time.Time (UTC).
+func (c *Config) DSN(database string) string {
+	cfg := mysql.NewConfig()
+	cfg.User = c.User
+	cfg.Passwd = c.Password
+	cfg.Net = "tcp"
+	cfg.Addr = c.HostPort()
+	cfg.DBName = database
+	cfg.ParseTime = true
+	cfg.Timeout = sqlConnectTimeout
+	cfg.ReadTimeout = sqlTimeout
+	cfg.WriteTimeout = sqlTimeout
+	return cfg.FormatDSN()
+}
+
+// OpenSQL returns a pooled connection to database on the town's dolt
+// sql-server, checked with a ping. Pools are shared per process. It fails
+// when the server is down, the database doesn't exist, or GT_DOLT_CLI is
+// set; callers with a CLI path fall back to it.
+func OpenSQL(townRoot, database string) (*sql.DB, error) {
+	if os.Getenv(EnvDoltCLI) != "" {
+		return nil, fmt.Errorf("%s is set", EnvDoltCLI)
+	}
+	dsn := DefaultConfig(townRoot).DSN(database)
+
+	sqlPoolsMu.Lock()
+	db, ok := sqlPools[dsn]
+	if !ok {
+		var err error
+		db, err = sql.Open("mysql", dsn)
+		if err != nil {
+			sqlPoolsMu.Unlock()
+			return nil, fmt.Errorf("opening mysql connection: %w", err)
+		}
+		db.SetMaxOpenConns(4)
+		db.SetConnMaxIdleTime(time.Minute)
+		sqlPools[dsn] = db
+	}
+	sqlPoolsMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqlConnectTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("connecting to dolt sql-server: %w", err)
+	}
+	return db, nil
+}
+
+// sqlCommitTx runs fn in a transaction and commits its changes to Dolt
+// history with message, retrying transient Dolt errors. fn returns how many
+// rows it changed; zero rolls the transaction back and returns
+// errNothingToCommit, so conditional writes can report unmet preconditions.
+// fn may run more than once.
+func sqlCommitTx(db *sql.DB, message string, fn func(ctx context.Context, tx *sql.Tx) (int64, error)) error {
+	const maxRetries = 3
+	const baseBackoff = 500 * time.Millisecond
+
+	var lastErr error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		lastErr = sqlCommitTxOnce(db, message, fn)
+		if lastErr == nil || !isDoltRetryableError(lastErr) {
+			return lastErr
+		}
+		if attempt < maxRetries {
+			time.Sleep(baseBackoff << (attempt - 1))
+		}
+	}
+	return fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
+}
+
+func sqlCommitTxOnce(db *sql.DB, message string, fn func(ctx context.Context, tx *sql.Tx) (int64, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	changed, err := fn(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		return errNothingToCommit
+	}
+	if _, err := tx.ExecContext(ctx, "CALL DOLT_ADD('-A')"); err != nil {
+		return fmt.Errorf("dolt add: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "CALL DOLT_COMMIT('-m', ?)", message); err != nil {
+		return fmt.Errorf("dolt commit: %w", err)
+	}
+	return tx.Commit()
+}
+
+// execCount runs a statement and returns the rows it affected.
+func execCount(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// nullString maps "" to SQL NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,93 @@
+package doltserver
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestConfigDSN(t *testing.T) {
+	t.Parallel()
+	c := &Config{Host: "10.0.0.5", Port: 3307, User: "root", Password: "p@ss:w/rd"}
+	cfg, err := mysql.ParseDSN(c.DSN(WLCommonsDB))
+	if err != nil {
+		t.Fatalf("ParseDSN() error: %v", err)
+	}
+	if cfg.Addr != "10.0.0.5:3307" || cfg.DBName != WLCommonsDB {
+		t.Errorf("Addr, DBName = %q, %q", cfg.Addr, cfg.DBName)
+	}
+	if cfg.User != "root" || cfg.Passwd != "p@ss:w/rd" {
+		t.Errorf("credentials not round-tripped: %q / %q", cfg.User, cfg.Passwd)
+	}
+	if !cfg.ParseTime || cfg.Timeout != sqlConnectTimeout {
+		t.Errorf("ParseTime=%v Timeout=%v", cfg.ParseTime, cfg.Timeout)
+	}
+}
+
+func TestOpenSQL_CLIOverride(t *testing.T) {
+	t.Setenv(EnvDoltCLI, "1")
+	if _, err := OpenSQL(t.TempDir(), WLCommonsDB); err == nil || !strings.Contains(err.Error(), EnvDoltCLI) {
+		t.Errorf("OpenSQL() error = %v, want %s override", err, EnvDoltCLI)
+	}
+}
+
+func TestOpenSQL_ServerDown(t *testing.T) {
+	// Reserve a port, then close it so nothing is listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	t.Setenv(EnvDoltCLI, "")
+	t.Setenv("GT_DOLT_HOST", "127.0.0.1")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(port))
+	townRoot := t.TempDir()
+
+	start := time.Now()
+	if _, err := OpenSQL(townRoot, WLCommonsDB); err == nil {
+		t.Fatal("OpenSQL() should fail with no server listening")
+	}
+	if elapsed := time.Since(start); elapsed > 2*sqlConnectTimeout {
+		t.Errorf("OpenSQL() took %v, want fast failure", elapsed)
+	}
+	if db := NewWLCommons(townRoot).sqlDB(); db != nil {
+		t.Error("sqlDB() should be nil so WLCommons falls back to the CLI")
+	}
+}
+
+func TestErrNothingToCommit(t *testing.T) {
+	t.Parallel()
+	if !isNothingToCommit(errNothingToCommit) {
+		t.Error("isNothingToCommit(errNothingToCommit) = false; SQL and CLI paths must agree")
+	}
+}
+
+func TestWantedWhere(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		filter    WantedFilter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{WantedFilter{Priority: -1}, "", nil},
+		{WantedFilter{Status: "open", Priority: -1}, " WHERE status=?", []any{"open"}},
+		{
+			WantedFilter{Status: "open", Project: "x' OR '1'='1", Type: "bug", Priority: 0},
+			" WHERE status=? AND project=? AND type=? AND priority=?",
+			[]any{"open", "x' OR '1'='1", "bug", 0},
+		},
+	}
+	for _, tt := range tests {
+		where, args := wantedWhere(tt.filter)
+		if where != tt.wantWhere || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("wantedWhere(%+v) = %q, %v; want %q, %v", tt.filter, where, args, tt.wantWhere, tt.wantArgs)
+		}
+	}
+}
@@ -158,13 +158,18 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 		result.Remote = remoteURL
 
 		if remoteURL == "" {
-			// Auto-setup DoltHub remote if credentials are available.
-			token := DoltHubToken()
-			org := DoltHubOrg()
-			if token != "" && org != "" {
-				if err := SetupDoltHubRemote(dbDir, org, db, token); err != nil {
+			// Auto-setup a remote if a backend is configured: GT_DOLT_REMOTE,
+			// or DoltHub credentials.
+			remote, org, token, ok, err := AutoRemote()
+			if err != nil {
+				result.Error = err
+				results = append(results, result)
+				continue
+			}
+			if ok {
+				if err := SetupRemote(remote, dbDir, org, db, token); err != nil {
 					// Setup failed — skip this database for now.
-					result.Error = fmt.Errorf("auto-setup DoltHub remote: %w", err)
+					result.Error = fmt.Errorf("auto-setup remote on %s: %w", remote.Describe(), err)
 					results = append(results, result)
 					continue
 				}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -35,16 +36,34 @@ type WLCommons struct{ townRoot string }
 // NewWLCommons creates a WLCommonsStore backed by the real Dolt server.
 func NewWLCommons(townRoot string) *WLCommons { return &WLCommons{townRoot: townRoot} }
 
-func (w *WLCommons) EnsureDB() error           { return EnsureWLCommons(w.townRoot) }
+func (w *WLCommons) EnsureDB() error               { return EnsureWLCommons(w.townRoot) }
 func (w *WLCommons) DatabaseExists(db string) bool { return DatabaseExists(w.townRoot, db) }
-func (w *WLCommons) InsertWanted(item *WantedItem) error { return InsertWanted(w.townRoot, item) }
+
+// The remaining methods go through the sql-server when it is reachable and
+// fall back to the dolt CLI when it is not.
+
+func (w *WLCommons) InsertWanted(item *WantedItem) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlInsertWanted(db, item)
+	}
+	return InsertWanted(w.townRoot, item)
+}
 func (w *WLCommons) ClaimWanted(wantedID, rigHandle string) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlClaimWanted(db, wantedID, rigHandle)
+	}
 	return ClaimWanted(w.townRoot, wantedID, rigHandle)
 }
 func (w *WLCommons) SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlSubmitCompletion(db, completionID, wantedID, rigHandle, evidence)
+	}
 	return SubmitCompletion(w.townRoot, completionID, wantedID, rigHandle, evidence)
 }
 func (w *WLCommons) QueryWanted(wantedID string) (*WantedItem, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlQueryWanted(db, wantedID)
+	}
 	return QueryWanted(w.townRoot, wantedID)
 }
 
@@ -97,7 +116,7 @@ func EnsureWLCommons(townRoot string) error {
 	dbDir := filepath.Join(config.DataDir, WLCommonsDB)
 
 	if _, err := os.Stat(filepath.Join(dbDir, ".dolt")); err == nil {
-		return nil
+		return migrateWLCommonsSchema(townRoot)
 	}
 
 	_, created, err := InitRig(townRoot, WLCommonsDB)
@@ -124,7 +143,7 @@ CREATE TABLE IF NOT EXISTS _meta (
     value TEXT
 );
 
-INSERT IGNORE INTO _meta (%s, value) VALUES ('schema_version', '1.0');
+INSERT IGNORE INTO _meta (%s, value) VALUES ('schema_version', '1.1');
 INSERT IGNORE INTO _meta (%s, value) VALUES ('wasteland_name', 'Gas Town Wasteland');
 
 CREATE TABLE IF NOT EXISTS rigs (
@@ -138,7 +157,8 @@ CREATE TABLE IF NOT EXISTS rigs (
     registered_at TIMESTAMP,
     last_seen TIMESTAMP,
     rig_type VARCHAR(16) DEFAULT 'human',
-    parent_rig VARCHAR(255)
+    parent_rig VARCHAR(255),
+    public_key VARCHAR(128)
 );
 
 CREATE TABLE IF NOT EXISTS wanted (
@@ -188,6 +208,7 @@ CREATE TABLE IF NOT EXISTS stamps (
     message TEXT,
     prev_stamp_hash VARCHAR(64),
     block_hash VARCHAR(64),
+    signature VARCHAR(128),
     hop_uri VARCHAR(512),
     created_at TIMESTAMP,
     CHECK (NOT(author = subject))
@@ -211,7 +232,7 @@ CREATE TABLE IF NOT EXISTS chain_meta (
 );
 
 CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('--allow-empty', '-m', 'Initialize wl-commons schema v1.0');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'Initialize wl-commons schema v1.1');
 `, WLCommonsDB,
 		backtickKey(), backtickKey(), backtickKey())
 
@@ -224,11 +245,8 @@ func backtickKey() string {
 
 // InsertWanted inserts a new wanted item into the wl-commons database.
 func InsertWanted(townRoot string, item *WantedItem) error {
-	if item.ID == "" {
-		return fmt.Errorf("wanted item ID cannot be empty")
-	}
-	if item.Title == "" {
-		return fmt.Errorf("wanted item title cannot be empty")
+	if err := checkWantedItem(item); err != nil {
+		return err
 	}
 
 	now := time.Now().UTC().Format("2006-01-02 15:04:05")
@@ -287,6 +305,16 @@ CALL DOLT_COMMIT('-m', 'wl post: %s');
 	return doltSQLScriptWithRetry(townRoot, script)
 }
 
+func checkWantedItem(item *WantedItem) error {
+	if item.ID == "" {
+		return fmt.Errorf("wanted item ID cannot be empty")
+	}
+	if item.Title == "" {
+		return fmt.Errorf("wanted item title cannot be empty")
+	}
+	return nil
+}
+
 // ClaimWanted updates a wanted item's status to claimed.
 // Returns an error if the item does not exist or is not open.
 //
@@ -374,6 +402,79 @@ func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
 	return item, nil
 }
 
+// ListWanted returns wanted items matching filter, highest priority first.
+// Titles are read hex-encoded so CSV parsing stays line-based. The filter
+// is built with the same parameterized WHERE clause as the server path and
+// bound with bindCLIArgs, so no filter value is spliced into the SQL text.
+func ListWanted(townRoot string, filter WantedFilter) ([]*WantedItem, error) {
+	where, args := wantedWhere(filter)
+	query := `SELECT id, HEX(title) AS title, COALESCE(project, '') AS project, COALESCE(type, '') AS type,
+  COALESCE(priority, 0) AS priority, COALESCE(posted_by, '') AS posted_by, COALESCE(claimed_by, '') AS claimed_by,
+  COALESCE(status, '') AS status, COALESCE(effort_level, '') AS effort_level FROM wanted` + where +
+		" ORDER BY priority ASC, created_at DESC, id"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	bound, err := bindCLIArgs(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf("USE %s; %s;", WLCommonsDB, bound))
+	if err != nil {
+		return nil, err
+	}
+	var items []*WantedItem
+	for _, row := range parseSimpleCSV(output) {
+		priority, _ := strconv.Atoi(row["priority"])
+		items = append(items, &WantedItem{
+			ID:          row["id"],
+			Title:       unhex(row["title"]),
+			Project:     row["project"],
+			Type:        row["type"],
+			Priority:    priority,
+			PostedBy:    row["posted_by"],
+			ClaimedBy:   row["claimed_by"],
+			Status:      row["status"],
+			EffortLevel: row["effort_level"],
+		})
+	}
+	return items, nil
+}
+
+// bindCLIArgs fills the ? placeholders of a parameterized query for the
+// dolt CLI, which cannot bind parameters itself. Strings are passed as hex
+// literals converted back to text, so their content never reaches the SQL
+// parser and needs no escaping; integers are formatted directly. The query
+// text itself must not contain a literal '?'.
+func bindCLIArgs(query string, args []any) (string, error) {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] != '?' {
+			b.WriteByte(query[i])
+			continue
+		}
+		if next >= len(args) {
+			return "", fmt.Errorf("binding query: more placeholders than arguments")
+		}
+		switch v := args[next].(type) {
+		case string:
+			fmt.Fprintf(&b, "CONVERT(X'%s' USING utf8mb4)", hex.EncodeToString([]byte(v)))
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		default:
+			return "", fmt.Errorf("binding query: unsupported argument type %T", v)
+		}
+		next++
+	}
+	if next != len(args) {
+		return "", fmt.Errorf("binding query: %d arguments for %d placeholders", len(args), next)
+	}
+	return b.String(), nil
+}
+
 // doltSQLQuery executes a SQL query and returns the raw CSV output.
 func doltSQLQuery(townRoot, query string) (string, error) {
 	config := DefaultConfig(townRoot)
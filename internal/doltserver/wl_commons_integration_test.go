@@ -45,6 +45,59 @@ func TestRealWLCommonsStore_Conformance(t *testing.T) {
 	})
 }
 
+// TestRealWLCommonsStore_ConformanceCLI runs the conformance suite with the
+// SQL driver disabled, covering the dolt CLI fallback.
+func TestRealWLCommonsStore_ConformanceCLI(t *testing.T) {
+	townRoot := startIsolatedDoltContainer(t)
+	t.Setenv(EnvDoltCLI, "1")
+
+	store := NewWLCommons(townRoot)
+	if err := store.EnsureDB(); err != nil {
+		t.Fatalf("EnsureDB() error: %v", err)
+	}
+
+	wlCommonsConformance(t, func(t *testing.T) WLCommonsStore {
+		return NewWLCommons(townRoot)
+	})
+}
+
+// TestRealWLCommons_ListWanted checks that the SQL and CLI paths return the
+// same items for a filter, including values that need quoting.
+func TestRealWLCommons_ListWanted(t *testing.T) {
+	townRoot := startIsolatedDoltContainer(t)
+	store := NewWLCommons(townRoot)
+	if err := store.EnsureDB(); err != nil {
+		t.Fatalf("EnsureDB() error: %v", err)
+	}
+	if store.sqlDB() == nil {
+		t.Fatal("sql-server connection unavailable")
+	}
+	for i, title := range []string{"plain", `it's a "quoted", title\`} {
+		item := &WantedItem{ID: fmt.Sprintf("w-list%d", i), Title: title, Project: "o'brien", Priority: i}
+		if err := store.InsertWanted(item); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+	}
+
+	filter := WantedFilter{Status: "open", Project: "o'brien", Priority: -1}
+	viaSQL, err := store.ListWanted(filter)
+	if err != nil {
+		t.Fatalf("ListWanted() via SQL error: %v", err)
+	}
+	viaCLI, err := ListWanted(townRoot, filter)
+	if err != nil {
+		t.Fatalf("ListWanted() via CLI error: %v", err)
+	}
+	if len(viaSQL) != 2 || len(viaCLI) != 2 {
+		t.Fatalf("got %d (SQL) and %d (CLI) items, want 2", len(viaSQL), len(viaCLI))
+	}
+	for i := range viaSQL {
+		if viaSQL[i].ID != viaCLI[i].ID || viaSQL[i].Title != viaCLI[i].Title {
+			t.Errorf("item %d: SQL %+v, CLI %+v", i, viaSQL[i], viaCLI[i])
+		}
+	}
+}
+
 // TestIsNothingToCommit_RealDolt verifies that isNothingToCommit correctly detects
 // the error produced by DOLT_COMMIT when no changes exist. This pins the detection
 // logic against the actual Dolt error text so that Dolt upgrades that change the
@@ -0,0 +1,450 @@
+// Package doltserver - wl_commons_sql.go provides the wl-commons operations
+// over the dolt sql-server connection (see OpenSQL).
+//
+// Every value is bound as a prepared-statement parameter and rows are scanned
+// into typed structs, so these mirror the CLI implementations in wl_commons.go
+// and wl_stamps.go without string-built SQL or CSV parsing. The WLCommons
+// methods use them when the server is reachable and fall back to the CLI
+// otherwise; error messages match between the two.
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WantedFilter selects wanted items for ListWanted. Empty strings and a
+// negative Priority match everything; Limit <= 0 means no limit.
+type WantedFilter struct {
+	Status   string
+	Project  string
+	Type     string
+	Priority int
+	Limit    int
+}
+
+// sqlDB returns a server connection to the wl-commons database, or nil if
+// the CLI should be used instead.
+func (w *WLCommons) sqlDB() *sql.DB {
+	db, err := OpenSQL(w.townRoot, WLCommonsDB)
+	if err != nil {
+		return nil
+	}
+	return db
+}
+
+// ListWanted returns wanted items matching filter, highest priority first.
+func (w *WLCommons) ListWanted(filter WantedFilter) ([]*WantedItem, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlListWanted(db, filter)
+	}
+	return ListWanted(w.townRoot, filter)
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// sqlQuery runs a read-only query with the standard data-access timeout and
+// calls scan for each row.
+func sqlQuery(db *sql.DB, scan func(rowScanner) error, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("dolt sql query failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("scanning row: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+const wantedColumns = `id, title, description, project, type, priority, tags, posted_by,
+  claimed_by, status, effort_level, sandbox_required FROM wanted`
+
+func scanWanted(row rowScanner) (*WantedItem, error) {
+	var (
+		item                                                      WantedItem
+		desc, project, typ, tags, postedBy, claimedBy, effort, st sql.NullString
+		priority                                                  sql.NullInt64
+		sandbox                                                   sql.NullBool
+	)
+	if err := row.Scan(&item.ID, &item.Title, &desc, &project, &typ, &priority, &tags, &postedBy,
+		&claimedBy, &st, &effort, &sandbox); err != nil {
+		return nil, err
+	}
+	item.Description = desc.String
+	item.Project = project.String
+	item.Type = typ.String
+	item.Priority = int(priority.Int64)
+	item.PostedBy = postedBy.String
+	item.ClaimedBy = claimedBy.String
+	item.Status = st.String
+	item.EffortLevel = effort.String
+	item.SandboxRequired = sandbox.Bool
+	if tags.Valid && tags.String != "" {
+		if err := json.Unmarshal([]byte(tags.String), &item.Tags); err != nil {
+			return nil, fmt.Errorf("decoding tags of %s: %w", item.ID, err)
+		}
+	}
+	return &item, nil
+}
+
+func sqlInsertWanted(db *sql.DB, item *WantedItem) error {
+	if err := checkWantedItem(item); err != nil {
+		return err
+	}
+	var tags any
+	if len(item.Tags) > 0 {
+		data, err := json.Marshal(item.Tags)
+		if err != nil {
+			return fmt.Errorf("encoding tags: %w", err)
+		}
+		tags = string(data)
+	}
+	effort := item.EffortLevel
+	if effort == "" {
+		effort = "medium"
+	}
+	status := item.Status
+	if status == "" {
+		status = "open"
+	}
+	now := time.Now().UTC()
+
+	return sqlCommitTx(db, "wl post: "+item.Title, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		return execCount(ctx, tx, `INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.ID, item.Title, nullString(item.Description), nullString(item.Project), nullString(item.Type),
+			item.Priority, tags, nullString(item.PostedBy), status, effort, now, now)
+	})
+}
+
+func sqlClaimWanted(db *sql.DB, wantedID, rigHandle string) error {
+	err := sqlCommitTx(db, "wl claim: "+wantedID, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		return execCount(ctx, tx, `UPDATE wanted SET claimed_by=?, status='claimed', updated_at=NOW()
+  WHERE id=? AND status='open'`, rigHandle, wantedID)
+	})
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errNothingToCommit) {
+		return fmt.Errorf("wanted item %q is not open or does not exist", wantedID)
+	}
+	return fmt.Errorf("claim failed: %w", err)
+}
+
+func sqlSubmitCompletion(db *sql.DB, completionID, wantedID, rigHandle, evidence string) error {
+	err := sqlCommitTx(db, "wl done: "+wantedID, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		updated, err := execCount(ctx, tx, `UPDATE wanted SET status='in_review', evidence_url=?, updated_at=NOW()
+  WHERE id=? AND status='claimed' AND claimed_by=?`, evidence, wantedID, rigHandle)
+		if err != nil {
+			return 0, err
+		}
+		inserted, err := execCount(ctx, tx, `INSERT IGNORE INTO completions (id, wanted_id, completed_by, evidence, completed_at)
+  SELECT ?, ?, ?, ?, NOW()
+  FROM wanted WHERE id=? AND status='in_review' AND claimed_by=?
+  AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id=?)`,
+			completionID, wantedID, rigHandle, evidence, wantedID, rigHandle, wantedID)
+		return updated + inserted, err
+	})
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errNothingToCommit) {
+		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+	}
+	return fmt.Errorf("completion failed: %w", err)
+}
+
+func sqlQueryWanted(db *sql.DB, wantedID string) (*WantedItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlTimeout)
+	defer cancel()
+
+	item, err := scanWanted(db.QueryRowContext(ctx, "SELECT "+wantedColumns+" WHERE id=?", wantedID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("wanted item %q not found", wantedID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dolt sql query failed: %w", err)
+	}
+	return item, nil
+}
+
+// wantedWhere builds the WHERE clause and arguments for filter.
+func wantedWhere(filter WantedFilter) (string, []any) {
+	var conds []string
+	var args []any
+	for _, c := range []struct{ col, val string }{
+		{"status", filter.Status},
+		{"project", filter.Project},
+		{"type", filter.Type},
+	} {
+		if c.val != "" {
+			conds = append(conds, c.col+"=?")
+			args = append(args, c.val)
+		}
+	}
+	if filter.Priority >= 0 {
+		conds = append(conds, "priority=?")
+		args = append(args, filter.Priority)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func sqlListWanted(db *sql.DB, filter WantedFilter) ([]*WantedItem, error) {
+	where, args := wantedWhere(filter)
+	query := "SELECT " + wantedColumns + where + " ORDER BY priority ASC, created_at DESC, id"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	var items []*WantedItem
+	err := sqlQuery(db, func(row rowScanner) error {
+		item, err := scanWanted(row)
+		if err == nil {
+			items = append(items, item)
+		}
+		return err
+	}, query, args...)
+	return items, err
+}
+
+// sqlCompletionColumns selects a completion joined with its wanted item.
+const sqlCompletionColumns = `c.id, c.wanted_id, w.title, w.posted_by, c.completed_by, c.evidence,
+  c.validated_by, c.stamp_id, c.block_hash, c.completed_at, c.validated_at
+  FROM completions c LEFT JOIN wanted w ON w.id = c.wanted_id`
+
+func scanCompletion(row rowScanner) (*Completion, error) {
+	var (
+		c                                                                Completion
+		title, postedBy, completedBy, evidence, validatedBy, stamp, hash sql.NullString
+		completedAt, validatedAt                                         sql.NullTime
+	)
+	if err := row.Scan(&c.ID, &c.WantedID, &title, &postedBy, &completedBy, &evidence,
+		&validatedBy, &stamp, &hash, &completedAt, &validatedAt); err != nil {
+		return nil, err
+	}
+	c.WantedTitle = title.String
+	c.PostedBy = postedBy.String
+	c.CompletedBy = completedBy.String
+	c.Evidence = evidence.String
+	c.ValidatedBy = validatedBy.String
+	c.StampID = stamp.String
+	c.BlockHash = hash.String
+	c.CompletedAt = completedAt.Time.UTC()
+	c.ValidatedAt = validatedAt.Time.UTC()
+	return &c, nil
+}
+
+func sqlQueryCompletion(db *sql.DB, completionID string) (*Completion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlTimeout)
+	defer cancel()
+
+	c, err := scanCompletion(db.QueryRowContext(ctx, "SELECT "+sqlCompletionColumns+" WHERE c.id=?", completionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("completion %q not found", completionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dolt sql query failed: %w", err)
+	}
+	return c, nil
+}
+
+func sqlListPendingCompletions(db *sql.DB) ([]*Completion, error) {
+	var completions []*Completion
+	err := sqlQuery(db, func(row rowScanner) error {
+		c, err := scanCompletion(row)
+		if err == nil {
+			completions = append(completions, c)
+		}
+		return err
+	}, "SELECT "+sqlCompletionColumns+" WHERE c.validated_by IS NULL ORDER BY c.completed_at, c.id")
+	return completions, err
+}
+
+func sqlListStamps(db *sql.DB, author string) ([]*Stamp, error) {
+	query := `SELECT id, author, subject, valence, confidence, severity, context_id, context_type,
+  skill_tags, message, prev_stamp_hash, block_hash, signature, created_at FROM stamps`
+	var args []any
+	if author != "" {
+		query += " WHERE author=?"
+		args = append(args, author)
+	}
+	query += " ORDER BY created_at, id"
+
+	var stamps []*Stamp
+	err := sqlQuery(db, func(row rowScanner) error {
+		var (
+			st                                                             Stamp
+			valence                                                        []byte
+			confidence                                                     sql.NullFloat64
+			severity, ctxID, ctxType, tags, message, prev, hash, signature sql.NullString
+			created                                                        sql.NullTime
+		)
+		if err := row.Scan(&st.ID, &st.Author, &st.Subject, &valence, &confidence, &severity, &ctxID, &ctxType,
+			&tags, &message, &prev, &hash, &signature, &created); err != nil {
+			return err
+		}
+		st.Confidence = 1
+		if confidence.Valid {
+			st.Confidence = confidence.Float64
+		}
+		st.Severity = "leaf"
+		if severity.Valid {
+			st.Severity = severity.String
+		}
+		st.ContextID = ctxID.String
+		st.ContextType = ctxType.String
+		st.Message = message.String
+		st.PrevStampHash = prev.String
+		st.BlockHash = hash.String
+		st.Signature = signature.String
+		st.CreatedAt = created.Time.UTC()
+		_ = json.Unmarshal(valence, &st.Valence)
+		if tags.Valid && tags.String != "" {
+			_ = json.Unmarshal([]byte(tags.String), &st.SkillTags)
+		}
+		stamps = append(stamps, &st)
+		return nil
+	}, query, args...)
+	return stamps, err
+}
+
+func sqlQueryRigs(db *sql.DB, where string, args ...any) ([]*RigRecord, error) {
+	query := "SELECT handle, public_key, trust_level FROM rigs" + where + " ORDER BY handle"
+
+	var rigs []*RigRecord
+	err := sqlQuery(db, func(row rowScanner) error {
+		var (
+			rig   RigRecord
+			key   sql.NullString
+			level sql.NullInt64
+		)
+		if err := row.Scan(&rig.Handle, &key, &level); err != nil {
+			return err
+		}
+		rig.PublicKey = key.String
+		rig.TrustLevel = int(level.Int64)
+		rigs = append(rigs, &rig)
+		return nil
+	}, query, args...)
+	return rigs, err
+}
+
+func sqlPublishRigKey(db *sql.DB, handle, publicKey string) error {
+	now := time.Now().UTC()
+	err := sqlCommitTx(db, "wl key: "+handle, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		return execCount(ctx, tx, `INSERT INTO rigs (handle, public_key, registered_at, last_seen) VALUES (?, ?, ?, ?)
+  ON DUPLICATE KEY UPDATE public_key=VALUES(public_key)`, handle, publicKey, now, now)
+	})
+	if err != nil && !errors.Is(err, errNothingToCommit) {
+		return fmt.Errorf("publishing rig key: %w", err)
+	}
+	return nil
+}
+
+func sqlRecordValidation(db *sql.DB, stamp *Stamp, accepted bool) error {
+	valence, err := json.Marshal(stamp.Valence)
+	if err != nil {
+		return fmt.Errorf("encoding valence: %w", err)
+	}
+	var tags any
+	if len(stamp.SkillTags) > 0 {
+		data, err := json.Marshal(stamp.SkillTags)
+		if err != nil {
+			return fmt.Errorf("encoding skill tags: %w", err)
+		}
+		tags = string(data)
+	}
+	created := stamp.CreatedAt.UTC()
+	id := stamp.ContextID
+
+	err = sqlCommitTx(db, "wl validate: "+id, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		stamped, err := execCount(ctx, tx, `INSERT INTO stamps (id, author, subject, valence, confidence, severity, context_id, context_type, skill_tags, message, prev_stamp_hash, block_hash, signature, created_at)
+  SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+  FROM completions WHERE id=? AND validated_by IS NULL`,
+			stamp.ID, stamp.Author, stamp.Subject, string(valence), stamp.Confidence, stamp.Severity,
+			id, stamp.ContextType, tags, nullString(stamp.Message), nullString(stamp.PrevStampHash),
+			stamp.BlockHash, stamp.Signature, created, id)
+		if err != nil {
+			return 0, err
+		}
+
+		var settled, removed int64
+		if accepted {
+			settled, err = execCount(ctx, tx, `UPDATE wanted SET status='completed', updated_at=?
+  WHERE status='in_review' AND id=(SELECT wanted_id FROM completions WHERE id=? AND validated_by IS NULL)`, created, id)
+			if err != nil {
+				return 0, err
+			}
+			removed, err = execCount(ctx, tx, `UPDATE completions SET validated_by=?, stamp_id=?, block_hash=?, validated_at=?
+  WHERE id=? AND validated_by IS NULL`, stamp.Author, stamp.ID, stamp.BlockHash, created, id)
+		} else {
+			settled, err = execCount(ctx, tx, `UPDATE wanted SET status='claimed', evidence_url=NULL, updated_at=?
+  WHERE status='in_review' AND id=(SELECT wanted_id FROM completions WHERE id=? AND validated_by IS NULL)`, created, id)
+			if err != nil {
+				return 0, err
+			}
+			removed, err = execCount(ctx, tx, "DELETE FROM completions WHERE id=? AND validated_by IS NULL", id)
+		}
+		return stamped + settled + removed, err
+	})
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errNothingToCommit) {
+		return fmt.Errorf("completion %q is already validated or does not exist", stamp.ContextID)
+	}
+	return fmt.Errorf("validation failed: %w", err)
+}
+
+func sqlUpdateReputation(db *sql.DB, handle string, trustLevel int, badges []*Badge) error {
+	sorted := append([]*Badge(nil), badges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	err := sqlCommitTx(db, "wl reputation: "+handle, func(ctx context.Context, tx *sql.Tx) (int64, error) {
+		changed, err := execCount(ctx, tx, "UPDATE rigs SET trust_level=? WHERE handle=?", trustLevel, handle)
+		if err != nil {
+			return 0, err
+		}
+		stmt, err := tx.PrepareContext(ctx, `INSERT IGNORE INTO badges (id, rig_handle, badge_type, awarded_at, evidence)
+  VALUES (?, ?, ?, ?, ?)`)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
+		for _, b := range sorted {
+			res, err := stmt.ExecContext(ctx, b.ID, b.RigHandle, b.BadgeType, b.AwardedAt.UTC(), nullString(b.Evidence))
+			if err != nil {
+				return 0, err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return 0, err
+			}
+			changed += n
+		}
+		return changed, nil
+	})
+	if err != nil && !errors.Is(err, errNothingToCommit) {
+		return fmt.Errorf("updating reputation for %s: %w", handle, err)
+	}
+	return nil
+}
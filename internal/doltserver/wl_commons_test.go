@@ -164,3 +164,23 @@ func TestGenerateWantedID_Uniqueness(t *testing.T) {
 		seen[id] = true
 	}
 }
+
+func TestBindCLIArgs(t *testing.T) {
+	t.Parallel()
+	where, args := wantedWhere(WantedFilter{Status: `open' OR '1'='1\`, Priority: 2})
+	got, err := bindCLIArgs("SELECT id FROM wanted"+where+" LIMIT ?", append(args, 5))
+	if err != nil {
+		t.Fatalf("bindCLIArgs: %v", err)
+	}
+	want := "SELECT id FROM wanted WHERE status=CONVERT(X'6f70656e27204f52202731273d27315c' USING utf8mb4) AND priority=2 LIMIT 5"
+	if got != want {
+		t.Errorf("bindCLIArgs =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := bindCLIArgs("a=? AND b=?", []any{"x"}); err == nil {
+		t.Error("expected error for missing argument")
+	}
+	if _, err := bindCLIArgs("a=?", []any{"x", "y"}); err == nil {
+		t.Error("expected error for extra argument")
+	}
+}
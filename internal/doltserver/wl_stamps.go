@@ -0,0 +1,433 @@
+// Package doltserver - wl_stamps.go provides wl-commons validation storage:
+// stamps, completion review, rig keys, trust levels and badges.
+package doltserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wlTimeFormat is how wl-commons TIMESTAMP columns are written and read (UTC).
+const wlTimeFormat = "2006-01-02 15:04:05"
+
+// WLStampStore abstracts the wl-commons tables used to validate completions
+// and derive reputation. Text columns that may hold newlines are read
+// hex-encoded so CSV parsing stays line-based.
+type WLStampStore interface {
+	// QueryCompletion returns a completion joined with its wanted item.
+	QueryCompletion(completionID string) (*Completion, error)
+	// ListPendingCompletions returns completions awaiting validation.
+	ListPendingCompletions() ([]*Completion, error)
+	// ListStamps returns the stamps issued by author, or all stamps if
+	// author is empty, oldest first.
+	ListStamps(author string) ([]*Stamp, error)
+	// QueryRig returns a registered rig, or nil if the handle is unknown.
+	QueryRig(handle string) (*RigRecord, error)
+	// ListRigs returns all registered rigs.
+	ListRigs() ([]*RigRecord, error)
+	// PublishRigKey records the rig's stamp-signing public key,
+	// registering the rig if needed.
+	PublishRigKey(handle, publicKey string) error
+	// RecordValidation inserts the stamp and settles its completion:
+	// accepted completions are marked validated and their wanted item
+	// completed; rejected ones are removed so the claimer can resubmit.
+	RecordValidation(stamp *Stamp, accepted bool) error
+	// UpdateReputation stores a rig's derived trust level and awards any
+	// badges it doesn't hold yet.
+	UpdateReputation(handle string, trustLevel int, badges []*Badge) error
+}
+
+// Completion represents a row in the completions table, with the title and
+// poster of its wanted item.
+type Completion struct {
+	ID          string
+	WantedID    string
+	WantedTitle string
+	PostedBy    string
+	CompletedBy string
+	Evidence    string
+	ValidatedBy string
+	StampID     string
+	BlockHash   string
+	CompletedAt time.Time
+	ValidatedAt time.Time
+}
+
+// Stamp represents a row in the stamps table: one rig's signed assessment
+// of another's work. Each stamp links to its author's previous stamp by
+// hash, forming a per-author chain.
+type Stamp struct {
+	ID            string
+	Author        string
+	Subject       string
+	Valence       map[string]float64
+	Confidence    float64
+	Severity      string
+	ContextID     string
+	ContextType   string
+	SkillTags     []string
+	Message       string
+	PrevStampHash string
+	BlockHash     string
+	Signature     string
+	CreatedAt     time.Time
+}
+
+// RigRecord is the validation-relevant part of a rigs row.
+type RigRecord struct {
+	Handle     string
+	PublicKey  string
+	TrustLevel int
+}
+
+// Badge represents a row in the badges table.
+type Badge struct {
+	ID        string
+	RigHandle string
+	BadgeType string
+	Evidence  string
+	AwardedAt time.Time
+}
+
+func (w *WLCommons) QueryCompletion(completionID string) (*Completion, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlQueryCompletion(db, completionID)
+	}
+	return QueryCompletion(w.townRoot, completionID)
+}
+func (w *WLCommons) ListPendingCompletions() ([]*Completion, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlListPendingCompletions(db)
+	}
+	return ListPendingCompletions(w.townRoot)
+}
+func (w *WLCommons) ListStamps(author string) ([]*Stamp, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlListStamps(db, author)
+	}
+	return ListStamps(w.townRoot, author)
+}
+func (w *WLCommons) QueryRig(handle string) (*RigRecord, error) {
+	if db := w.sqlDB(); db != nil {
+		rigs, err := sqlQueryRigs(db, " WHERE handle=?", handle)
+		if err != nil || len(rigs) == 0 {
+			return nil, err
+		}
+		return rigs[0], nil
+	}
+	return QueryRig(w.townRoot, handle)
+}
+func (w *WLCommons) ListRigs() ([]*RigRecord, error) {
+	if db := w.sqlDB(); db != nil {
+		return sqlQueryRigs(db, "")
+	}
+	return ListRigs(w.townRoot)
+}
+func (w *WLCommons) PublishRigKey(handle, publicKey string) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlPublishRigKey(db, handle, publicKey)
+	}
+	return PublishRigKey(w.townRoot, handle, publicKey)
+}
+func (w *WLCommons) RecordValidation(stamp *Stamp, accepted bool) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlRecordValidation(db, stamp, accepted)
+	}
+	return RecordValidation(w.townRoot, stamp, accepted)
+}
+func (w *WLCommons) UpdateReputation(handle string, trustLevel int, badges []*Badge) error {
+	if db := w.sqlDB(); db != nil {
+		return sqlUpdateReputation(db, handle, trustLevel, badges)
+	}
+	return UpdateReputation(w.townRoot, handle, trustLevel, badges)
+}
+
+// migrateWLCommonsSchema upgrades an existing v1.0 wl-commons database to
+// v1.1, which adds stamps.signature and rigs.public_key for signed stamps.
+func migrateWLCommonsSchema(townRoot string) error {
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		"USE %s; SELECT value FROM _meta WHERE %s='schema_version';", WLCommonsDB, backtickKey()))
+	if err != nil {
+		return fmt.Errorf("reading wl-commons schema version: %w", err)
+	}
+	rows := parseSimpleCSV(output)
+	if len(rows) == 0 || rows[0]["value"] != "1.0" {
+		return nil
+	}
+
+	output, err = doltSQLQuery(townRoot, fmt.Sprintf(`SELECT table_name, column_name FROM information_schema.columns
+  WHERE table_schema='%s' AND ((table_name='stamps' AND column_name='signature') OR (table_name='rigs' AND column_name='public_key'));`,
+		WLCommonsDB))
+	if err != nil {
+		return fmt.Errorf("reading wl-commons columns: %w", err)
+	}
+	have := make(map[string]bool)
+	for _, row := range parseSimpleCSV(output) {
+		have[row["table_name"]+"."+row["column_name"]] = true
+	}
+
+	var alters strings.Builder
+	if !have["stamps.signature"] {
+		alters.WriteString("ALTER TABLE stamps ADD COLUMN signature VARCHAR(128);\n")
+	}
+	if !have["rigs.public_key"] {
+		alters.WriteString("ALTER TABLE rigs ADD COLUMN public_key VARCHAR(128);\n")
+	}
+	script := fmt.Sprintf(`USE %s;
+%sUPDATE _meta SET value='1.1' WHERE %s='schema_version';
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', 'Migrate wl-commons schema to v1.1');
+`, WLCommonsDB, alters.String(), backtickKey())
+	if err := doltSQLScriptWithRetry(townRoot, script); err != nil && !isNothingToCommit(err) {
+		return fmt.Errorf("migrating wl-commons schema: %w", err)
+	}
+	return nil
+}
+
+// completionColumns selects a completion joined with its wanted item.
+const completionColumns = `c.id, c.wanted_id, COALESCE(w.title, '') AS title, COALESCE(w.posted_by, '') AS posted_by,
+  COALESCE(c.completed_by, '') AS completed_by, HEX(COALESCE(c.evidence, '')) AS evidence,
+  COALESCE(c.validated_by, '') AS validated_by, COALESCE(c.stamp_id, '') AS stamp_id,
+  COALESCE(c.block_hash, '') AS block_hash, COALESCE(c.completed_at, '') AS completed_at,
+  COALESCE(c.validated_at, '') AS validated_at
+  FROM completions c LEFT JOIN wanted w ON w.id = c.wanted_id`
+
+// QueryCompletion fetches a completion by ID.
+func QueryCompletion(townRoot, completionID string) (*Completion, error) {
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf("USE %s; SELECT %s WHERE c.id='%s';",
+		WLCommonsDB, completionColumns, EscapeSQL(completionID)))
+	if err != nil {
+		return nil, err
+	}
+	rows := parseSimpleCSV(output)
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("completion %q not found", completionID)
+	}
+	return completionFromRow(rows[0]), nil
+}
+
+// ListPendingCompletions returns unvalidated completions, oldest first.
+func ListPendingCompletions(townRoot string) ([]*Completion, error) {
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		"USE %s; SELECT %s WHERE c.validated_by IS NULL ORDER BY c.completed_at, c.id;",
+		WLCommonsDB, completionColumns))
+	if err != nil {
+		return nil, err
+	}
+	var completions []*Completion
+	for _, row := range parseSimpleCSV(output) {
+		completions = append(completions, completionFromRow(row))
+	}
+	return completions, nil
+}
+
+func completionFromRow(row map[string]string) *Completion {
+	return &Completion{
+		ID:          row["id"],
+		WantedID:    row["wanted_id"],
+		WantedTitle: row["title"],
+		PostedBy:    row["posted_by"],
+		CompletedBy: row["completed_by"],
+		Evidence:    unhex(row["evidence"]),
+		ValidatedBy: row["validated_by"],
+		StampID:     row["stamp_id"],
+		BlockHash:   row["block_hash"],
+		CompletedAt: parseWLTime(row["completed_at"]),
+		ValidatedAt: parseWLTime(row["validated_at"]),
+	}
+}
+
+// ListStamps returns stamps by author (all authors if empty), oldest first.
+func ListStamps(townRoot, author string) ([]*Stamp, error) {
+	where := ""
+	if author != "" {
+		where = fmt.Sprintf(" WHERE author='%s'", EscapeSQL(author))
+	}
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(`USE %s; SELECT id, author, subject, HEX(CAST(valence AS CHAR)) AS valence,
+  COALESCE(confidence, 1) AS confidence, COALESCE(severity, 'leaf') AS severity,
+  COALESCE(context_id, '') AS context_id, COALESCE(context_type, '') AS context_type,
+  HEX(COALESCE(CAST(skill_tags AS CHAR), '')) AS skill_tags, HEX(COALESCE(message, '')) AS message,
+  COALESCE(prev_stamp_hash, '') AS prev_stamp_hash, COALESCE(block_hash, '') AS block_hash,
+  COALESCE(signature, '') AS signature, COALESCE(created_at, '') AS created_at
+  FROM stamps%s ORDER BY created_at, id;`, WLCommonsDB, where))
+	if err != nil {
+		return nil, err
+	}
+
+	var stamps []*Stamp
+	for _, row := range parseSimpleCSV(output) {
+		st := &Stamp{
+			ID:            row["id"],
+			Author:        row["author"],
+			Subject:       row["subject"],
+			Severity:      row["severity"],
+			ContextID:     row["context_id"],
+			ContextType:   row["context_type"],
+			Message:       unhex(row["message"]),
+			PrevStampHash: row["prev_stamp_hash"],
+			BlockHash:     row["block_hash"],
+			Signature:     row["signature"],
+			CreatedAt:     parseWLTime(row["created_at"]),
+		}
+		st.Confidence, _ = strconv.ParseFloat(row["confidence"], 64)
+		_ = json.Unmarshal([]byte(unhex(row["valence"])), &st.Valence)
+		if tags := unhex(row["skill_tags"]); tags != "" {
+			_ = json.Unmarshal([]byte(tags), &st.SkillTags)
+		}
+		stamps = append(stamps, st)
+	}
+	return stamps, nil
+}
+
+// QueryRig fetches a rig's key and trust level. Returns nil if not registered.
+func QueryRig(townRoot, handle string) (*RigRecord, error) {
+	rigs, err := queryRigs(townRoot, fmt.Sprintf(" WHERE handle='%s'", EscapeSQL(handle)))
+	if err != nil || len(rigs) == 0 {
+		return nil, err
+	}
+	return rigs[0], nil
+}
+
+// ListRigs returns all registered rigs.
+func ListRigs(townRoot string) ([]*RigRecord, error) {
+	return queryRigs(townRoot, "")
+}
+
+func queryRigs(townRoot, where string) ([]*RigRecord, error) {
+	output, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		"USE %s; SELECT handle, COALESCE(public_key, '') AS public_key, COALESCE(trust_level, 0) AS trust_level FROM rigs%s ORDER BY handle;",
+		WLCommonsDB, where))
+	if err != nil {
+		return nil, err
+	}
+	var rigs []*RigRecord
+	for _, row := range parseSimpleCSV(output) {
+		level, _ := strconv.Atoi(row["trust_level"])
+		rigs = append(rigs, &RigRecord{Handle: row["handle"], PublicKey: row["public_key"], TrustLevel: level})
+	}
+	return rigs, nil
+}
+
+// PublishRigKey sets a rig's public key, registering the handle if the local
+// commons doesn't know it yet.
+func PublishRigKey(townRoot, handle, publicKey string) error {
+	now := time.Now().UTC().Format(wlTimeFormat)
+	script := fmt.Sprintf(`USE %s;
+INSERT INTO rigs (handle, public_key, registered_at, last_seen) VALUES ('%s', '%s', '%s', '%s')
+  ON DUPLICATE KEY UPDATE public_key=VALUES(public_key);
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', 'wl key: %s');
+`, WLCommonsDB, EscapeSQL(handle), EscapeSQL(publicKey), now, now, EscapeSQL(handle))
+	if err := doltSQLScriptWithRetry(townRoot, script); err != nil && !isNothingToCommit(err) {
+		return fmt.Errorf("publishing rig key: %w", err)
+	}
+	return nil
+}
+
+// RecordValidation inserts a stamp and settles the completion it assesses,
+// in one commit. Every statement is conditional on the completion still
+// being unvalidated, so a concurrent validation leaves nothing to commit
+// and is reported as already validated.
+func RecordValidation(townRoot string, stamp *Stamp, accepted bool) error {
+	valence, err := json.Marshal(stamp.Valence)
+	if err != nil {
+		return fmt.Errorf("encoding valence: %w", err)
+	}
+	tags := "NULL"
+	if len(stamp.SkillTags) > 0 {
+		data, err := json.Marshal(stamp.SkillTags)
+		if err != nil {
+			return fmt.Errorf("encoding skill tags: %w", err)
+		}
+		tags = fmt.Sprintf("'%s'", EscapeSQL(string(data)))
+	}
+	created := stamp.CreatedAt.UTC().Format(wlTimeFormat)
+	id := EscapeSQL(stamp.ContextID)
+
+	var settle string
+	if accepted {
+		settle = fmt.Sprintf(`UPDATE wanted SET status='completed', updated_at='%[2]s'
+  WHERE status='in_review' AND id=(SELECT wanted_id FROM completions WHERE id='%[1]s' AND validated_by IS NULL);
+UPDATE completions SET validated_by='%[3]s', stamp_id='%[4]s', block_hash='%[5]s', validated_at='%[2]s'
+  WHERE id='%[1]s' AND validated_by IS NULL;`,
+			id, created, EscapeSQL(stamp.Author), EscapeSQL(stamp.ID), EscapeSQL(stamp.BlockHash))
+	} else {
+		settle = fmt.Sprintf(`UPDATE wanted SET status='claimed', evidence_url=NULL, updated_at='%[2]s'
+  WHERE status='in_review' AND id=(SELECT wanted_id FROM completions WHERE id='%[1]s' AND validated_by IS NULL);
+DELETE FROM completions WHERE id='%[1]s' AND validated_by IS NULL;`, id, created)
+	}
+
+	script := fmt.Sprintf(`USE %s;
+INSERT INTO stamps (id, author, subject, valence, confidence, severity, context_id, context_type, skill_tags, message, prev_stamp_hash, block_hash, signature, created_at)
+  SELECT '%s', '%s', '%s', '%s', %s, '%s', '%s', '%s', %s, %s, %s, '%s', '%s', '%s'
+  FROM completions WHERE id='%s' AND validated_by IS NULL;
+%s
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('-m', 'wl validate: %s');
+`,
+		WLCommonsDB,
+		EscapeSQL(stamp.ID), EscapeSQL(stamp.Author), EscapeSQL(stamp.Subject), EscapeSQL(string(valence)),
+		strconv.FormatFloat(stamp.Confidence, 'f', -1, 64), EscapeSQL(stamp.Severity),
+		id, EscapeSQL(stamp.ContextType), tags, sqlNullable(stamp.Message), sqlNullable(stamp.PrevStampHash),
+		EscapeSQL(stamp.BlockHash), EscapeSQL(stamp.Signature), created,
+		id, settle, id)
+
+	err = doltSQLScriptWithRetry(townRoot, script)
+	if err == nil {
+		return nil
+	}
+	if isNothingToCommit(err) {
+		return fmt.Errorf("completion %q is already validated or does not exist", stamp.ContextID)
+	}
+	return fmt.Errorf("validation failed: %w", err)
+}
+
+// UpdateReputation stores a rig's trust level and awards badges. Badge IDs
+// are deterministic, so re-awarding an existing badge is a no-op.
+func UpdateReputation(townRoot, handle string, trustLevel int, badges []*Badge) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "USE %s;\n", WLCommonsDB)
+	fmt.Fprintf(&sb, "UPDATE rigs SET trust_level=%d WHERE handle='%s';\n", trustLevel, EscapeSQL(handle))
+	sorted := append([]*Badge(nil), badges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for _, b := range sorted {
+		fmt.Fprintf(&sb, "INSERT IGNORE INTO badges (id, rig_handle, badge_type, awarded_at, evidence) VALUES ('%s', '%s', '%s', '%s', %s);\n",
+			EscapeSQL(b.ID), EscapeSQL(b.RigHandle), EscapeSQL(b.BadgeType),
+			b.AwardedAt.UTC().Format(wlTimeFormat), sqlNullable(b.Evidence))
+	}
+	fmt.Fprintf(&sb, "CALL DOLT_ADD('-A');\nCALL DOLT_COMMIT('-m', 'wl reputation: %s');\n", EscapeSQL(handle))
+
+	if err := doltSQLScriptWithRetry(townRoot, sb.String()); err != nil && !isNothingToCommit(err) {
+		return fmt.Errorf("updating reputation for %s: %w", handle, err)
+	}
+	return nil
+}
+
+// sqlNullable quotes s as a SQL string literal, or returns NULL if empty.
+func sqlNullable(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'", EscapeSQL(s))
+}
+
+func unhex(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
+
+func parseWLTime(s string) time.Time {
+	t, err := time.ParseInLocation(wlTimeFormat, s, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
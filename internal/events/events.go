@@ -5,8 +5,10 @@
 package events
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -69,6 +71,10 @@ const (
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
 
+	// Lifecycle events (plugin event gates subscribe to these)
+	TypeDaemonStart  = "daemon_start"
+	TypeConvoyClosed = "convoy_closed"
+
 	// Scheduler events
 	TypeSchedulerEnqueue        = "scheduler_enqueue"         // Bead scheduled for deferred dispatch
 	TypeSchedulerDispatch       = "scheduler_dispatch"        // Bead dispatched from scheduler
@@ -94,6 +100,20 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 	return write(event)
 }
 
+// LogFeedTo writes a feed-visible event to the events log of the given town.
+// Use it from long-running processes whose working directory may not be
+// inside the town (e.g. the daemon).
+func LogFeedTo(townRoot, eventType, actor string, payload map[string]interface{}) error {
+	return writeTo(townRoot, Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: VisibilityFeed,
+	})
+}
+
 // LogFeed is a convenience wrapper for feed-visible events.
 func LogFeed(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityFeed)
@@ -114,7 +134,11 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
+	return writeTo(townRoot, event)
+}
 
+// writeTo appends an event to the events file of the given town.
+func writeTo(townRoot string, event Event) error {
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
 	// Marshal event to JSON
@@ -368,3 +392,55 @@ func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]inter
 		"error": errMsg,
 	}
 }
+
+// ConvoyClosedPayload creates a payload for convoy close events.
+func ConvoyClosedPayload(convoyID, title, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"convoy": convoyID,
+		"title":  title,
+		"reason": reason,
+	}
+}
+
+// ReadFrom reads the events appended to the town's events log after byte
+// offset, returning them with the offset to resume from. Only complete lines
+// are consumed, so a concurrent writer's partial line is picked up next time.
+// If the log is shorter than offset (it was truncated or rotated), reading
+// restarts from the beginning. Malformed lines are skipped.
+func ReadFrom(townRoot string, offset int64) ([]Event, int64, error) {
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, offset, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, offset, fmt.Errorf("stat events file: %w", err)
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("seeking events file: %w", err)
+	}
+
+	var out []Event
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// EOF or a partial trailing line: stop before it.
+			break
+		}
+		offset += int64(len(line))
+		var ev Event
+		if json.Unmarshal(line, &ev) == nil && ev.Type != "" {
+			out = append(out, ev)
+		}
+	}
+	return out, offset, nil
+}
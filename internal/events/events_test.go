@@ -1,6 +1,8 @@
 package events
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -257,3 +259,47 @@ func TestSessionPayload_Minimal(t *testing.T) {
 		t.Error("expected no cwd key when empty")
 	}
 }
+
+func TestReadFrom(t *testing.T) {
+	town := t.TempDir()
+	if evs, off, err := ReadFrom(town, 0); err != nil || len(evs) != 0 || off != 0 {
+		t.Fatalf("missing log = %v, %d, %v", evs, off, err)
+	}
+
+	if err := LogFeedTo(town, TypeDaemonStart, "daemon", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogFeedTo(town, TypeConvoyClosed, "mayor", ConvoyClosedPayload("hq-cv-1", "Ship", "done")); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(town, EventsFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n{\"type\":\"merged\"") // partial trailing line
+	_ = f.Close()
+
+	evs, off, err := ReadFrom(town, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2 || evs[0].Type != TypeDaemonStart || evs[1].Payload["convoy"] != "hq-cv-1" {
+		t.Fatalf("events = %+v", evs)
+	}
+
+	f, _ = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("}\n")
+	_ = f.Close()
+	evs, off, _ = ReadFrom(town, off)
+	if len(evs) != 1 || evs[0].Type != TypeMerged {
+		t.Fatalf("resumed events = %+v", evs)
+	}
+
+	if err := os.WriteFile(path, []byte("{\"type\":\"boot\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if evs, _, _ = ReadFrom(town, off); len(evs) != 1 || evs[0].Type != TypeBoot {
+		t.Errorf("after rotation = %+v, want read from start", evs)
+	}
+}
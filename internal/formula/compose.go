@@ -0,0 +1,598 @@
+package formula
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Loader loads a formula by name for composition. It returns the formula as
+// declared, without resolving its own extends/include/call directives.
+type Loader func(name string) (*Formula, error)
+
+// EmbeddedLoader loads formulas embedded in the binary.
+func EmbeddedLoader(name string) (*Formula, error) {
+	data, err := GetEmbeddedFormulaContent(name)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// DirLoader returns a Loader that looks for <name>.formula.toml in dirs, in
+// order, and falls back to the embedded formulas.
+func DirLoader(dirs ...string) Loader {
+	return func(name string) (*Formula, error) {
+		for _, dir := range dirs {
+			path := filepath.Join(dir, name+".formula.toml")
+			data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted formula directory
+			if err == nil {
+				return decode(data)
+			}
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("reading formula %q: %w", name, err)
+			}
+		}
+		return EmbeddedLoader(name)
+	}
+}
+
+// decode parses formula TOML without resolving composition or validating.
+// Unknown top-level keys are rejected, so a directive the parser doesn't
+// implement fails loudly instead of being ignored.
+func decode(data []byte) (*Formula, error) {
+	var f Formula
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	for _, key := range md.Undecoded() {
+		if len(key) == 1 {
+			return nil, fmt.Errorf("unknown top-level key %q", key[0])
+		}
+	}
+	f.inferType()
+	return &f, nil
+}
+
+// HasComposition returns true if the formula extends or includes other
+// formulas, composes aspects or expansions, or has steps that call a
+// sub-formula.
+func (f *Formula) HasComposition() bool {
+	if len(f.Extends) > 0 || len(f.Include) > 0 {
+		return true
+	}
+	if f.Compose != nil && (len(f.Compose.Aspects) > 0 || len(f.Compose.Expand) > 0) {
+		return true
+	}
+	for _, step := range f.Steps {
+		if step.Formula != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve flattens the formula's composition directives in place, loading
+// referenced formulas with load (EmbeddedLoader if nil):
+//
+//   - extends: the formula inherits the base formulas' steps, legs, templates,
+//     aspects, vars and inputs. Its own items replace inherited items with the
+//     same ID.
+//   - include: like extends, for mixing in a fragment of another formula.
+//   - steps with formula = "name": the step is replaced by the sub-formula's
+//     steps, with IDs prefixed "<step-id>." and {{var}} placeholders filled
+//     from the step's `with` table. Steps that needed the call step need the
+//     sub-formula's final steps instead.
+//   - compose.expand: the target step is replaced by the expansion formula's
+//     templates, with {target}, {target.title} and {target.description}
+//     filled from the step.
+//   - compose.aspects: each aspect formula's advice inserts steps before and
+//     after the workflow steps its target and pointcuts match, with
+//     {step.id}, {step.title} and {step.description} filled from the step.
+//     Expansions are applied first, so advice can target expanded steps.
+//
+// Two inherited formulas defining the same ID is an error, as is a cycle of
+// formulas extending, including or calling each other. Resolve is a no-op
+// once the formula has been resolved.
+func (f *Formula) Resolve(load Loader) error {
+	if f.resolved || !f.HasComposition() {
+		return nil
+	}
+	if load == nil {
+		load = EmbeddedLoader
+	}
+	r := &resolver{load: load, flat: make(map[string]*Formula)}
+	flat, err := r.flatten(f, []string{f.Name})
+	if err != nil {
+		return err
+	}
+
+	f.Type = flat.Type
+	if f.Description == "" {
+		f.Description = flat.Description
+	}
+	f.Inputs = flat.Inputs
+	f.Prompts = flat.Prompts
+	f.Output = flat.Output
+	f.Legs = flat.Legs
+	f.Synthesis = flat.Synthesis
+	f.Steps = flat.Steps
+	f.Vars = flat.Vars
+	f.Template = flat.Template
+	f.Aspects = flat.Aspects
+	f.Advice = flat.Advice
+	f.Pointcuts = flat.Pointcuts
+	f.resolved = true
+	return nil
+}
+
+// Composed reports whether the formula used composition directives that
+// Resolve has flattened.
+func (f *Formula) Composed() bool {
+	return f.resolved
+}
+
+// Flat returns the formula as TOML with its composition directives applied
+// and removed, for tools such as bd cook that read formulas as written.
+func (f *Formula) Flat() ([]byte, error) {
+	if err := f.Resolve(f.loader); err != nil {
+		return nil, err
+	}
+	flat := *f
+	flat.Extends, flat.Include, flat.Compose = nil, nil, nil
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(&flat); err != nil {
+		return nil, fmt.Errorf("encoding formula %q: %w", f.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resolver flattens formulas, caching results by name.
+type resolver struct {
+	load Loader
+	flat map[string]*Formula
+}
+
+// resolve loads and flattens a referenced formula. stack holds the chain of
+// formulas being resolved, for cycle detection.
+func (r *resolver) resolve(name string, stack []string) (*Formula, error) {
+	if slices.Contains(stack, name) {
+		return nil, fmt.Errorf("formula cycle detected: %s", strings.Join(append(stack, name), " -> "))
+	}
+	if f, ok := r.flat[name]; ok {
+		return f, nil
+	}
+	f, err := r.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading formula %q: %w", name, err)
+	}
+	flat, err := r.flatten(f, append(stack, name))
+	if err != nil {
+		return nil, err
+	}
+	r.flat[name] = flat
+	return flat, nil
+}
+
+// flatten returns a copy of f with its composition directives applied.
+func (r *resolver) flatten(f *Formula, stack []string) (*Formula, error) {
+	out := &Formula{
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        f.Type,
+		Version:     f.Version,
+	}
+	steps := newLayer(func(s Step) string { return s.ID }, "step")
+	legs := newLayer(func(l Leg) string { return l.ID }, "leg")
+	templates := newLayer(func(t Template) string { return t.ID }, "template")
+	aspects := newLayer(func(a Aspect) string { return a.ID }, "aspect")
+
+	inherited := append(slices.Clone(f.Extends), f.Include...)
+	for _, name := range inherited {
+		base, err := r.resolve(name, stack)
+		if err != nil {
+			return nil, err
+		}
+		if out.Type == "" {
+			out.Type = base.Type
+		} else if base.Type != out.Type {
+			return nil, fmt.Errorf("%s formula %q cannot extend or include %s formula %q", out.Type, f.Name, base.Type, name)
+		}
+		if out.Description == "" {
+			out.Description = base.Description
+		}
+		if err := steps.inherit(name, base.Steps); err != nil {
+			return nil, err
+		}
+		if err := legs.inherit(name, base.Legs); err != nil {
+			return nil, err
+		}
+		if err := templates.inherit(name, base.Template); err != nil {
+			return nil, err
+		}
+		if err := aspects.inherit(name, base.Aspects); err != nil {
+			return nil, err
+		}
+		out.Advice = append(out.Advice, base.Advice...)
+		out.Pointcuts = append(out.Pointcuts, base.Pointcuts...)
+		out.Inputs = mergeMap(out.Inputs, base.Inputs)
+		out.Prompts = mergeMap(out.Prompts, base.Prompts)
+		out.Vars = mergeMap(out.Vars, base.Vars)
+		if base.Output != nil {
+			out.Output = base.Output
+		}
+		if base.Synthesis != nil {
+			out.Synthesis = base.Synthesis
+		}
+	}
+
+	steps.own(f.Steps)
+	legs.own(f.Legs)
+	templates.own(f.Template)
+	aspects.own(f.Aspects)
+	out.Inputs = mergeMap(out.Inputs, f.Inputs)
+	out.Prompts = mergeMap(out.Prompts, f.Prompts)
+	out.Vars = mergeMap(out.Vars, f.Vars)
+	if f.Output != nil {
+		out.Output = f.Output
+	}
+	if f.Synthesis != nil {
+		out.Synthesis = f.Synthesis
+	}
+
+	out.Steps = steps.items
+	out.Legs = legs.items
+	out.Template = templates.items
+	out.Aspects = aspects.items
+	out.Advice = append(out.Advice, f.Advice...)
+	out.Pointcuts = append(out.Pointcuts, f.Pointcuts...)
+
+	if err := r.expandCalls(out, stack); err != nil {
+		return nil, err
+	}
+	if err := r.compose(out, f.Compose, stack); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// expandCalls replaces each step that calls a sub-formula with the
+// sub-formula's steps. A sub-formula without steps just removes the call
+// step; its dependents inherit its needs.
+func (r *resolver) expandCalls(f *Formula, stack []string) error {
+	var steps []Step
+	sinks := make(map[string][]string) // call step ID -> prefixed final steps
+	called := false
+	for _, call := range f.Steps {
+		if call.Formula == "" {
+			steps = append(steps, call)
+			continue
+		}
+		if call.When != "" || call.ForEach != "" || call.Retry != 0 || call.Timeout != "" {
+			return fmt.Errorf("step %q calls formula %q but has when, for_each, retry or timeout, which a call cannot keep", call.ID, call.Formula)
+		}
+		sub, err := r.resolve(call.Formula, stack)
+		if err != nil {
+			return err
+		}
+		if sub.Type != TypeWorkflow {
+			return fmt.Errorf("step %q calls %s formula %q (only workflow formulas can be called)", call.ID, sub.Type, call.Formula)
+		}
+		values, err := callVars(f, call, sub)
+		if err != nil {
+			return err
+		}
+		called = true
+		if len(sub.Steps) == 0 {
+			sinks[call.ID] = slices.Clone(call.Needs)
+			continue
+		}
+
+		needed := make(map[string]bool)
+		for _, s := range sub.Steps {
+			for _, need := range s.Needs {
+				needed[need] = true
+			}
+		}
+		for _, s := range sub.Steps {
+			s.ID = call.ID + "." + s.ID
+			if len(s.Needs) == 0 {
+				s.Needs = slices.Clone(call.Needs)
+			} else {
+				needs := make([]string, len(s.Needs))
+				for i, need := range s.Needs {
+					needs[i] = call.ID + "." + need
+				}
+				s.Needs = needs
+			}
+			s.Title = substituteVars(s.Title, values)
+			s.Description = substituteVars(s.Description, values)
+			s.Acceptance = substituteVars(s.Acceptance, values)
+			steps = append(steps, s)
+		}
+		for _, s := range sub.Steps {
+			if !needed[s.ID] {
+				sinks[call.ID] = append(sinks[call.ID], call.ID+"."+s.ID)
+			}
+		}
+	}
+	if !called {
+		return nil
+	}
+
+	// Steps that needed a call step now need its final steps.
+	rewireNeeds(steps, sinks)
+	f.Steps = steps
+	return nil
+}
+
+// rewireNeeds replaces each need on a replaced step with the steps standing
+// in for it, dropping duplicate needs.
+func rewireNeeds(steps []Step, replaced map[string][]string) {
+	for i := range steps {
+		var needs []string
+		for _, need := range steps[i].Needs {
+			with, ok := replaced[need]
+			if !ok {
+				with = []string{need}
+			}
+			for _, n := range with {
+				if !slices.Contains(needs, n) {
+					needs = append(needs, n)
+				}
+			}
+		}
+		steps[i].Needs = needs
+	}
+}
+
+// compose applies a workflow's [compose] directives to its flattened steps:
+// expansions first, then aspects.
+func (r *resolver) compose(f *Formula, c *Compose, stack []string) error {
+	if c == nil || (len(c.Expand) == 0 && len(c.Aspects) == 0) {
+		return nil
+	}
+	if f.Type != TypeWorkflow {
+		return fmt.Errorf("%s formula %q cannot use compose (only workflow formulas can)", f.Type, f.Name)
+	}
+	for _, e := range c.Expand {
+		exp, err := r.resolve(e.With, stack)
+		if err != nil {
+			return err
+		}
+		if exp.Type != TypeExpansion {
+			return fmt.Errorf("compose.expand of step %q uses %s formula %q (only expansion formulas can expand a step)", e.Target, exp.Type, e.With)
+		}
+		if err := expandStep(f, e.Target, exp); err != nil {
+			return err
+		}
+	}
+	for _, name := range c.Aspects {
+		aspect, err := r.resolve(name, stack)
+		if err != nil {
+			return err
+		}
+		if aspect.Type != TypeAspect || len(aspect.Advice) == 0 {
+			return fmt.Errorf("compose aspect %q is not an aspect formula with advice", name)
+		}
+		for _, adv := range aspect.Advice {
+			weave(f, adv, aspect.Pointcuts)
+		}
+	}
+	return nil
+}
+
+// expandStep replaces step target with the expansion formula's templates.
+// Templates without needs take the target's needs, and steps that needed
+// the target need the expansion's final templates instead.
+func expandStep(f *Formula, target string, exp *Formula) error {
+	i := slices.IndexFunc(f.Steps, func(s Step) bool { return s.ID == target })
+	if i < 0 {
+		return fmt.Errorf("compose.expand target %q is not a step of formula %q", target, f.Name)
+	}
+	t := f.Steps[i]
+	if t.When != "" || t.ForEach != "" || t.Retry != 0 || t.Timeout != "" {
+		return fmt.Errorf("compose.expand target %q has when, for_each, retry or timeout, which an expansion cannot keep", target)
+	}
+	repl := strings.NewReplacer("{target}", t.ID, "{target.title}", t.Title, "{target.description}", t.Description)
+
+	needed := make(map[string]bool)
+	expanded := make([]Step, 0, len(exp.Template))
+	for _, tmpl := range exp.Template {
+		s := Step{
+			ID:          repl.Replace(tmpl.ID),
+			Title:       repl.Replace(tmpl.Title),
+			Description: repl.Replace(tmpl.Description),
+			Needs:       slices.Clone(t.Needs),
+		}
+		if len(tmpl.Needs) > 0 {
+			s.Needs = nil
+			for _, need := range tmpl.Needs {
+				s.Needs = append(s.Needs, repl.Replace(need))
+				needed[repl.Replace(need)] = true
+			}
+		}
+		expanded = append(expanded, s)
+	}
+	var sinks []string
+	for _, s := range expanded {
+		if !needed[s.ID] {
+			sinks = append(sinks, s.ID)
+		}
+	}
+
+	f.Steps = slices.Replace(f.Steps, i, i+1, expanded...)
+	rewireNeeds(f.Steps, map[string][]string{t.ID: sinks})
+	return nil
+}
+
+// weave inserts an advice's before and after steps around every step it
+// applies to. Before steps run in order ahead of the advised step, after
+// steps in order behind it, and the advised step's dependents wait for its
+// last after step.
+func weave(f *Formula, adv Advice, pointcuts []Pointcut) {
+	if adv.Around == nil {
+		return
+	}
+	advised := func(id string) bool {
+		if ok, _ := path.Match(adv.Target, id); !ok {
+			return false
+		}
+		if len(pointcuts) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(pointcuts, func(p Pointcut) bool {
+			ok, _ := path.Match(p.Glob, id)
+			return ok
+		})
+	}
+	replacer := func(s Step) *strings.Replacer {
+		return strings.NewReplacer("{step.id}", s.ID, "{step.title}", s.Title, "{step.description}", s.Description)
+	}
+	adviceStep := func(tmpl Template, repl *strings.Replacer, needs []string) Step {
+		return Step{
+			ID:          repl.Replace(tmpl.ID),
+			Title:       repl.Replace(tmpl.Title),
+			Description: repl.Replace(tmpl.Description),
+			Needs:       needs,
+		}
+	}
+
+	// Work out each advised step's last after step first, so dependents
+	// can be pointed at it as they are copied.
+	last := make(map[string][]string)
+	for _, s := range f.Steps {
+		if n := len(adv.Around.After); n > 0 && advised(s.ID) {
+			last[s.ID] = []string{replacer(s).Replace(adv.Around.After[n-1].ID)}
+		}
+	}
+	rewireNeeds(f.Steps, last)
+
+	var steps []Step
+	for _, s := range f.Steps {
+		if !advised(s.ID) {
+			steps = append(steps, s)
+			continue
+		}
+		repl := replacer(s)
+		needs := s.Needs
+		for _, tmpl := range adv.Around.Before {
+			b := adviceStep(tmpl, repl, needs)
+			steps = append(steps, b)
+			needs = []string{b.ID}
+		}
+		s.Needs = needs
+		steps = append(steps, s)
+		needs = []string{s.ID}
+		for _, tmpl := range adv.Around.After {
+			a := adviceStep(tmpl, repl, needs)
+			steps = append(steps, a)
+			needs = []string{a.ID}
+		}
+	}
+	f.Steps = steps
+}
+
+// callVars returns the values for a sub-formula's vars from the call step's
+// `with` table, falling back to the sub-formula's defaults. Vars with neither
+// stay as placeholders and are added to the calling formula's vars, so they
+// are supplied when the caller is poured.
+func callVars(f *Formula, call Step, sub *Formula) (map[string]string, error) {
+	for name := range call.With {
+		if _, ok := sub.Vars[name]; !ok {
+			return nil, fmt.Errorf("step %q passes unknown var %q to formula %q", call.ID, name, call.Formula)
+		}
+	}
+	values := make(map[string]string)
+	for name, v := range sub.Vars {
+		if val, ok := call.With[name]; ok {
+			values[name] = val
+			continue
+		}
+		if v.Default != "" {
+			values[name] = v.Default
+			continue
+		}
+		if _, ok := f.Vars[name]; !ok {
+			if f.Vars == nil {
+				f.Vars = make(map[string]Var)
+			}
+			f.Vars[name] = v
+		}
+	}
+	return values, nil
+}
+
+// substituteVars replaces {{name}} placeholders that have a value.
+func substituteVars(text string, values map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(m string) string {
+		if val, ok := values[m[2:len(m)-2]]; ok {
+			return val
+		}
+		return m
+	})
+}
+
+// layer accumulates items of one kind (steps, legs, ...) while flattening.
+// It tracks which formula defined each ID so conflicts can be reported.
+type layer[T any] struct {
+	id     func(T) string
+	kind   string
+	items  []T
+	origin map[string]string
+}
+
+func newLayer[T any](id func(T) string, kind string) *layer[T] {
+	return &layer[T]{id: id, kind: kind, origin: make(map[string]string)}
+}
+
+// inherit adds items from an extended or included formula.
+func (l *layer[T]) inherit(from string, items []T) error {
+	for _, item := range items {
+		id := l.id(item)
+		if prev, ok := l.origin[id]; ok {
+			return fmt.Errorf("%s %q is defined by both %q and %q", l.kind, id, prev, from)
+		}
+		l.origin[id] = from
+		l.items = append(l.items, item)
+	}
+	return nil
+}
+
+// own adds the formula's own items, replacing inherited items in place.
+func (l *layer[T]) own(items []T) {
+	for _, item := range items {
+		id := l.id(item)
+		if _, ok := l.origin[id]; ok {
+			for i := range l.items {
+				if l.id(l.items[i]) == id {
+					l.items[i] = item
+					break
+				}
+			}
+			continue
+		}
+		l.items = append(l.items, item)
+	}
+}
+
+// mergeMap returns dst with src's entries added, src winning on conflicts.
+func mergeMap[V any](dst, src map[string]V) map[string]V {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
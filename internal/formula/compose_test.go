@@ -0,0 +1,598 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mapLoader loads formulas from in-memory TOML sources.
+func mapLoader(sources map[string]string) Loader {
+	return func(name string) (*Formula, error) {
+		src, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("formula %q not found", name)
+		}
+		return decode([]byte(src))
+	}
+}
+
+const composeBase = `
+formula = "base"
+type = "workflow"
+description = "Base workflow"
+
+[vars.feature]
+required = true
+
+[[steps]]
+id = "design"
+title = "Design {{feature}}"
+
+[[steps]]
+id = "implement"
+title = "Implement {{feature}}"
+needs = ["design"]
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["implement"]
+`
+
+const composeReview = `
+formula = "review"
+type = "workflow"
+
+[vars.target]
+required = true
+
+[vars.depth]
+default = "quick"
+
+[[steps]]
+id = "read"
+title = "Read {{target}}"
+
+[[steps]]
+id = "critique"
+title = "Critique {{target}} ({{depth}})"
+needs = ["read"]
+
+[[steps]]
+id = "notes"
+title = "Write notes"
+needs = ["read"]
+`
+
+func TestResolve_Extends(t *testing.T) {
+	load := mapLoader(map[string]string{"base": composeBase})
+	f, err := ParseWithLoader([]byte(`
+formula = "child"
+extends = ["base"]
+
+[[steps]]
+id = "implement"
+title = "Implement {{feature}} carefully"
+needs = ["design"]
+
+[[steps]]
+id = "audit"
+title = "Security audit"
+needs = ["implement"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if f.Type != TypeWorkflow {
+		t.Errorf("Type = %q, want inherited workflow", f.Type)
+	}
+	if f.Description != "Base workflow" {
+		t.Errorf("Description = %q, want inherited", f.Description)
+	}
+	if got := strings.Join(f.GetAllIDs(), ","); got != "design,implement,submit,audit" {
+		t.Errorf("steps = %s", got)
+	}
+	if f.GetStep("implement").Title != "Implement {{feature}} carefully" {
+		t.Errorf("own step should override inherited step, got %q", f.GetStep("implement").Title)
+	}
+	if !f.Vars["feature"].Required {
+		t.Error("vars should be inherited")
+	}
+}
+
+func TestResolve_Include(t *testing.T) {
+	load := mapLoader(map[string]string{
+		"base": composeBase,
+		"lint": `
+formula = "lint"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+`,
+		"other": `
+formula = "other"
+
+[[steps]]
+id = "design"
+title = "Another design"
+`,
+	})
+
+	f, err := ParseWithLoader([]byte(`
+formula = "child"
+extends = ["base"]
+include = ["lint"]
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["implement", "lint"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := strings.Join(f.GetDependencies("submit"), ","); got != "implement,lint" {
+		t.Errorf("submit needs = %s", got)
+	}
+
+	_, err = ParseWithLoader([]byte(`
+formula = "child"
+extends = ["base"]
+include = ["other"]
+`), load)
+	if err == nil || !strings.Contains(err.Error(), `step "design" is defined by both "base" and "other"`) {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestResolve_CallStep(t *testing.T) {
+	load := mapLoader(map[string]string{"review": composeReview})
+	f, err := ParseWithLoader([]byte(`
+formula = "ship"
+
+[vars.feature]
+required = true
+
+[[steps]]
+id = "implement"
+title = "Implement {{feature}}"
+
+[[steps]]
+id = "review"
+formula = "review"
+needs = ["implement"]
+
+[steps.with]
+target = "{{feature}}"
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["review"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "implement,review.read,review.critique,review.notes,submit" {
+		t.Errorf("order = %s", got)
+	}
+
+	read := f.GetStep("review.read")
+	if read == nil || strings.Join(read.Needs, ",") != "implement" {
+		t.Fatalf("sub-formula root should inherit the call step's needs: %+v", read)
+	}
+	if read.Title != "Read {{feature}}" {
+		t.Errorf("with value not substituted: %q", read.Title)
+	}
+	if got := f.GetStep("review.critique").Title; got != "Critique {{feature}} (quick)" {
+		t.Errorf("default not substituted: %q", got)
+	}
+	if got := strings.Join(f.GetDependencies("submit"), ","); got != "review.critique,review.notes" {
+		t.Errorf("submit should need the sub-formula's final steps, got %s", got)
+	}
+	if f.GetStep("review") != nil {
+		t.Error("call step should be replaced by the sub-formula's steps")
+	}
+}
+
+func TestResolve_CallStepErrors(t *testing.T) {
+	load := mapLoader(map[string]string{
+		"review": composeReview,
+		"legs": `
+formula = "legs"
+type = "convoy"
+
+[[legs]]
+id = "a"
+`,
+	})
+
+	tests := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"unknown var", `formula = "review"
+with = { target = "x", typo = "y" }`, `passes unknown var "typo"`},
+		{"not a workflow", `formula = "legs"`, `calls convoy formula "legs"`},
+		{"missing formula", `formula = "nope"`, `loading formula "nope"`},
+		{"when", `formula = "review"
+when = "vars.x == \"y\""`, `has when, for_each, retry or timeout`},
+		{"retry", `formula = "review"
+retry = 2`, `has when, for_each, retry or timeout`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "formula = \"caller\"\n\n[[steps]]\nid = \"call\"\n" + tt.step + "\n"
+			_, err := ParseWithLoader([]byte(data), load)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_UnmappedVarsPropagate(t *testing.T) {
+	load := mapLoader(map[string]string{"review": composeReview})
+	f, err := ParseWithLoader([]byte(`
+formula = "caller"
+
+[[steps]]
+id = "review"
+formula = "review"
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if v, ok := f.Vars["target"]; !ok || !v.Required {
+		t.Errorf("unmapped required var should become a var of the caller, got %+v", f.Vars)
+	}
+	if _, ok := f.Vars["depth"]; ok {
+		t.Error("var with a default should not propagate")
+	}
+}
+
+func TestResolve_CrossFormulaCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		root    string
+		want    string
+	}{
+		{
+			name: "extends",
+			sources: map[string]string{
+				"a": "formula = \"a\"\nextends = [\"b\"]\n",
+				"b": "formula = \"b\"\nextends = [\"a\"]\n",
+			},
+			root: "a",
+			want: "formula cycle detected: a -> b -> a",
+		},
+		{
+			name: "self include",
+			sources: map[string]string{
+				"a": "formula = \"a\"\ninclude = [\"a\"]\n",
+			},
+			root: "a",
+			want: "formula cycle detected: a -> a",
+		},
+		{
+			name: "call through extends",
+			sources: map[string]string{
+				"a": "formula = \"a\"\n[[steps]]\nid = \"x\"\nformula = \"b\"\n",
+				"b": "formula = \"b\"\nextends = [\"c\"]\n",
+				"c": "formula = \"c\"\n[[steps]]\nid = \"y\"\nformula = \"a\"\n",
+			},
+			root: "a",
+			want: "formula cycle detected: a -> b -> c -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithLoader([]byte(tt.sources[tt.root]), mapLoader(tt.sources))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_NestedCallsAndDiamond(t *testing.T) {
+	load := mapLoader(map[string]string{
+		"review": composeReview,
+		"qa": `
+formula = "qa"
+
+[[steps]]
+id = "review"
+formula = "review"
+with = { target = "the build" }
+
+[[steps]]
+id = "signoff"
+title = "Sign off"
+needs = ["review"]
+`,
+	})
+	// The same sub-formula reached twice (directly and through qa) is not a cycle.
+	f, err := ParseWithLoader([]byte(`
+formula = "release"
+
+[[steps]]
+id = "pre"
+formula = "review"
+with = { target = "the plan" }
+
+[[steps]]
+id = "qa"
+formula = "qa"
+needs = ["pre"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	step := f.GetStep("qa.review.read")
+	if step == nil || step.Title != "Read the build" {
+		t.Fatalf("nested call not expanded: %+v", step)
+	}
+	if got := strings.Join(step.Needs, ","); got != "pre.critique,pre.notes" {
+		t.Errorf("nested root needs = %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("qa.signoff"), ","); got != "qa.review.critique,qa.review.notes" {
+		t.Errorf("qa.signoff needs = %s", got)
+	}
+}
+
+func TestParseFile_ResolvesSiblingFormulas(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "base.formula.toml"), []byte(composeBase), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "child.formula.toml")
+	if err := os.WriteFile(path, []byte("formula = \"child\"\nextends = [\"base\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(f.Steps) != 3 {
+		t.Errorf("got %d steps, want 3 inherited from sibling base", len(f.Steps))
+	}
+}
+
+func TestParse_EmbeddedShinyVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		order string
+	}{
+		{"shiny-secure", "design,implement-security-prescan,implement,implement-security-postscan," +
+			"review,test,submit-security-prescan,submit,submit-security-postscan"},
+		{"shiny-enterprise", "design,implement.draft,implement.refine-1,implement.refine-2," +
+			"implement.refine-3,implement.refine-4,review,test,submit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(mustEmbedded(t, tt.name))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			order, err := f.TopologicalSort()
+			if err != nil {
+				t.Fatalf("TopologicalSort failed: %v", err)
+			}
+			if got := strings.Join(order, ","); got != tt.order {
+				t.Errorf("order = %s\nwant    %s", got, tt.order)
+			}
+		})
+	}
+}
+
+const composeAspect = `
+formula = "audit"
+type = "aspect"
+
+[[advice]]
+target = "*"
+[advice.around]
+
+[[advice.around.before]]
+id = "{step.id}-check"
+title = "Check before {step.title}"
+
+[[advice.around.after]]
+id = "{step.id}-scan"
+title = "Scan {step.id}"
+
+[[pointcuts]]
+glob = "implement"
+`
+
+const composeExpansion = `
+formula = "twice"
+type = "expansion"
+
+[[template]]
+id = "{target}.first"
+title = "First: {target.title}"
+
+[[template]]
+id = "{target}.second"
+title = "Second pass"
+needs = ["{target}.first"]
+`
+
+func TestResolve_ComposeAspects(t *testing.T) {
+	load := mapLoader(map[string]string{"base": composeBase, "audit": composeAspect})
+	f, err := ParseWithLoader([]byte(`
+formula = "secure"
+extends = ["base"]
+
+[compose]
+aspects = ["audit"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := strings.Join(f.GetAllIDs(), ","); got != "design,implement-check,implement,implement-scan,submit" {
+		t.Errorf("steps = %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("implement-check"), ","); got != "design" {
+		t.Errorf("before advice should take the advised step's needs, got %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("implement"), ","); got != "implement-check" {
+		t.Errorf("advised step should need its before advice, got %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("submit"), ","); got != "implement-scan" {
+		t.Errorf("dependents should wait for after advice, got %s", got)
+	}
+	if got := f.GetStep("implement-check").Title; got != "Check before Implement {{feature}}" {
+		t.Errorf("advice placeholders not filled: %q", got)
+	}
+}
+
+func TestResolve_ComposeExpand(t *testing.T) {
+	load := mapLoader(map[string]string{"base": composeBase, "twice": composeExpansion})
+	f, err := ParseWithLoader([]byte(`
+formula = "thorough"
+extends = ["base"]
+
+[[compose.expand]]
+target = "implement"
+with = "twice"
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := strings.Join(f.GetAllIDs(), ","); got != "design,implement.first,implement.second,submit" {
+		t.Errorf("steps = %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("implement.first"), ","); got != "design" {
+		t.Errorf("first template should take the target's needs, got %s", got)
+	}
+	if got := strings.Join(f.GetDependencies("submit"), ","); got != "implement.second" {
+		t.Errorf("dependents should need the final template, got %s", got)
+	}
+	if got := f.GetStep("implement.first").Title; got != "First: Implement {{feature}}" {
+		t.Errorf("target placeholders not filled: %q", got)
+	}
+}
+
+func TestResolve_ComposeErrors(t *testing.T) {
+	load := mapLoader(map[string]string{"base": composeBase, "audit": composeAspect, "twice": composeExpansion})
+
+	tests := []struct {
+		name    string
+		compose string
+		want    string
+	}{
+		{"missing target", "[[compose.expand]]\ntarget = \"nope\"\nwith = \"twice\"", `target "nope" is not a step`},
+		{"expand with aspect", "[[compose.expand]]\ntarget = \"implement\"\nwith = \"audit\"", `uses aspect formula "audit"`},
+		{"aspect not aspect", "[compose]\naspects = [\"twice\"]", `"twice" is not an aspect formula`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "formula = \"composer\"\nextends = [\"base\"]\n\n" + tt.compose + "\n"
+			_, err := ParseWithLoader([]byte(data), load)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_EmptySubFormulaRemovesCallStep(t *testing.T) {
+	load := mapLoader(map[string]string{"empty": "formula = \"empty\"\ntype = \"workflow\"\n"})
+	f, err := ParseWithLoader([]byte(`
+formula = "caller"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "noop"
+formula = "empty"
+needs = ["build"]
+
+[[steps]]
+id = "ship"
+title = "Ship"
+needs = ["noop"]
+`), load)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if f.GetStep("noop") != nil {
+		t.Error("call step of an empty sub-formula should be removed")
+	}
+	if got := strings.Join(f.GetDependencies("ship"), ","); got != "build" {
+		t.Errorf("dependents should inherit the call step's needs, got %s", got)
+	}
+}
+
+func TestParse_RejectsUnknownTopLevelKeys(t *testing.T) {
+	_, err := Parse([]byte(`
+formula = "typo"
+
+[[steps]]
+id = "a"
+title = "A"
+
+[composed]
+aspects = ["security-audit"]
+`))
+	if err == nil || !strings.Contains(err.Error(), `unknown top-level key "composed"`) {
+		t.Errorf("error = %v, want unknown top-level key", err)
+	}
+}
+
+func TestFlat_RoundTrips(t *testing.T) {
+	f, err := Parse(mustEmbedded(t, "shiny-enterprise"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	data, err := f.Flat()
+	if err != nil {
+		t.Fatalf("Flat failed: %v", err)
+	}
+	if strings.Contains(string(data), "compose") || strings.Contains(string(data), "extends") {
+		t.Errorf("flat formula still has composition directives:\n%s", data)
+	}
+
+	g, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parsing flat formula: %v", err)
+	}
+	if g.Composed() {
+		t.Error("flat formula should need no composition")
+	}
+	if got, want := strings.Join(g.GetAllIDs(), ","), strings.Join(f.GetAllIDs(), ","); got != want {
+		t.Errorf("flat steps = %s, want %s", got, want)
+	}
+	if !g.Vars["feature"].Required {
+		t.Error("flat formula should keep vars")
+	}
+}
+
+func mustEmbedded(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := GetEmbeddedFormulaContent(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
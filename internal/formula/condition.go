@@ -0,0 +1,360 @@
+package formula
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Condition is a parsed step `when` expression.
+//
+// The language is deliberately small:
+//
+//	vars.NAME                 value of a formula var ("" if unset)
+//	steps.ID                  outcome of a step: "done", "failed", "skipped" or ""
+//	"text"                    string literal
+//	true, false               boolean literals
+//	a == b, a != b            string comparison
+//	!a, a && b, a || b, (a)   boolean logic
+//	contains(a, b)            a contains substring b
+//	matches(a, glob)          any item of the list a matches the glob
+//
+// A value is true unless it is "", "false" or "0". Lists are comma- or
+// newline-separated strings, as used by for_each. steps.ID also accepts the
+// ID of a for_each or call step, whose outcome aggregates its expanded steps.
+type Condition struct {
+	src  string
+	root condNode
+}
+
+// ParseCondition parses a `when` expression.
+func ParseCondition(src string) (*Condition, error) {
+	p := &condParser{src: src}
+	p.next()
+	root, err := p.parseOr()
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", src, err)
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("invalid condition %q: unexpected %q", src, p.tok.text)
+	}
+	return &Condition{src: src, root: root}, nil
+}
+
+// String returns the source expression.
+func (c *Condition) String() string { return c.src }
+
+// ConditionEnv supplies values to a condition.
+type ConditionEnv struct {
+	Vars  map[string]string
+	Steps func(id string) StepOutcome
+}
+
+// Eval evaluates the condition.
+func (c *Condition) Eval(env ConditionEnv) bool {
+	return truthy(c.root.eval(env))
+}
+
+// Refs returns the var names and step IDs the condition references.
+func (c *Condition) Refs() (vars, steps []string) {
+	c.root.refs(&vars, &steps)
+	return vars, steps
+}
+
+func truthy(s string) bool {
+	return s != "" && s != "false" && s != "0"
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// splitList splits a comma- or newline-separated list, dropping blanks.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// --- AST ---
+
+type condNode interface {
+	eval(env ConditionEnv) string
+	refs(vars, steps *[]string)
+}
+
+type litNode string
+
+func (n litNode) eval(ConditionEnv) string { return string(n) }
+func (n litNode) refs(_, _ *[]string)      {}
+
+type varNode string
+
+func (n varNode) eval(env ConditionEnv) string { return env.Vars[string(n)] }
+func (n varNode) refs(vars, _ *[]string)       { *vars = append(*vars, string(n)) }
+
+type stepNode string
+
+func (n stepNode) eval(env ConditionEnv) string {
+	if env.Steps == nil {
+		return ""
+	}
+	return string(env.Steps(string(n)))
+}
+
+func (n stepNode) refs(_, steps *[]string) { *steps = append(*steps, string(n)) }
+
+type notNode struct{ x condNode }
+
+func (n notNode) eval(env ConditionEnv) string { return boolString(!truthy(n.x.eval(env))) }
+func (n notNode) refs(vars, steps *[]string)   { n.x.refs(vars, steps) }
+
+type binNode struct {
+	op   string
+	l, r condNode
+}
+
+func (n binNode) eval(env ConditionEnv) string {
+	switch n.op {
+	case "&&":
+		return boolString(truthy(n.l.eval(env)) && truthy(n.r.eval(env)))
+	case "||":
+		return boolString(truthy(n.l.eval(env)) || truthy(n.r.eval(env)))
+	case "==":
+		return boolString(n.l.eval(env) == n.r.eval(env))
+	default: // "!="
+		return boolString(n.l.eval(env) != n.r.eval(env))
+	}
+}
+
+func (n binNode) refs(vars, steps *[]string) {
+	n.l.refs(vars, steps)
+	n.r.refs(vars, steps)
+}
+
+type callNode struct {
+	fn   string
+	args [2]condNode
+}
+
+func (n callNode) eval(env ConditionEnv) string {
+	a, b := n.args[0].eval(env), n.args[1].eval(env)
+	switch n.fn {
+	case "contains":
+		return boolString(strings.Contains(a, b))
+	default: // "matches"
+		for _, item := range splitList(a) {
+			if ok, _ := path.Match(b, item); ok {
+				return "true"
+			}
+		}
+		return "false"
+	}
+}
+
+func (n callNode) refs(vars, steps *[]string) {
+	n.args[0].refs(vars, steps)
+	n.args[1].refs(vars, steps)
+}
+
+// --- parser ---
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+}
+
+type condParser struct {
+	src string
+	pos int
+	tok token
+	err error
+}
+
+func (p *condParser) next() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF}
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case c == '"':
+		end := strings.IndexByte(p.src[p.pos+1:], '"')
+		if end < 0 {
+			p.err = fmt.Errorf("unterminated string")
+			p.tok = token{kind: tokEOF}
+			return
+		}
+		p.tok = token{kind: tokString, text: p.src[p.pos+1 : p.pos+1+end]}
+		p.pos += end + 2
+	case isIdentChar(c):
+		start := p.pos
+		for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.pos]}
+	default:
+		for _, op := range []string{"==", "!=", "&&", "||", "!", "(", ")", ","} {
+			if strings.HasPrefix(p.src[p.pos:], op) {
+				p.tok = token{kind: tokOp, text: op}
+				p.pos += len(op)
+				return
+			}
+		}
+		p.err = fmt.Errorf("unexpected character %q", c)
+		p.tok = token{kind: tokEOF}
+	}
+}
+
+// isIdentChar allows the characters of step IDs ("review.read", "lint-go").
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func (p *condParser) accept(op string) bool {
+	if p.tok.kind == tokOp && p.tok.text == op {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binNode{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binNode{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.accept("!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	}
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.accept(op) {
+			r, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return binNode{op: op, l: l, r: r}, nil
+		}
+	}
+	return l, nil
+}
+
+func (p *condParser) parsePrimary() (condNode, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokString:
+		p.next()
+		return litNode(tok.text), nil
+	case tokOp:
+		if p.accept("(") {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, fmt.Errorf("missing )")
+			}
+			return x, nil
+		}
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	case tokIdent:
+		p.next()
+		switch {
+		case tok.text == "true" || tok.text == "false":
+			return litNode(tok.text), nil
+		case strings.HasPrefix(tok.text, "vars.") && len(tok.text) > len("vars."):
+			return varNode(strings.TrimPrefix(tok.text, "vars.")), nil
+		case strings.HasPrefix(tok.text, "steps.") && len(tok.text) > len("steps."):
+			return stepNode(strings.TrimPrefix(tok.text, "steps.")), nil
+		case tok.text == "contains" || tok.text == "matches":
+			return p.parseCall(tok.text)
+		}
+		return nil, fmt.Errorf("unknown name %q (use vars.NAME or steps.ID)", tok.text)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return nil, fmt.Errorf("unexpected end of expression")
+}
+
+func (p *condParser) parseCall(fn string) (condNode, error) {
+	if !p.accept("(") {
+		return nil, fmt.Errorf("%s: missing (", fn)
+	}
+	a, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(",") {
+		return nil, fmt.Errorf("%s takes two arguments", fn)
+	}
+	b, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(")") {
+		return nil, fmt.Errorf("%s: missing )", fn)
+	}
+	return callNode{fn: fn, args: [2]condNode{a, b}}, nil
+}
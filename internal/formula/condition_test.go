@@ -0,0 +1,86 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCondition_Eval(t *testing.T) {
+	env := ConditionEnv{
+		Vars: map[string]string{
+			"mode":          "strict",
+			"changed_files": "src/auth/login.go, README.md",
+			"empty":         "",
+			"off":           "false",
+		},
+		Steps: func(id string) StepOutcome {
+			return map[string]StepOutcome{"lint": OutcomeFailed, "test": OutcomeDone}[id]
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`vars.mode == "strict"`, true},
+		{`vars.mode != "strict"`, false},
+		{`vars.mode`, true},
+		{`vars.empty`, false},
+		{`vars.off`, false},
+		{`vars.unset`, false},
+		{`!vars.empty`, true},
+		{`steps.lint == "failed"`, true},
+		{`steps.test == "done" && steps.lint == "done"`, false},
+		{`steps.test == "done" || steps.lint == "done"`, true},
+		{`!(vars.mode == "lax" || vars.empty)`, true},
+		{`contains(vars.changed_files, "auth/")`, true},
+		{`matches(vars.changed_files, "src/auth/*")`, true},
+		{`matches(vars.changed_files, "docs/*")`, false},
+		{`true && !false`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCondition(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCondition: %v", err)
+			}
+			if got := c.Eval(env); got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`vars.mode ==`, "unexpected end"},
+		{`mode == "x"`, `unknown name "mode"`},
+		{`vars.mode == "x`, "unterminated string"},
+		{`(vars.a`, "missing )"},
+		{`contains(vars.a)`, "takes two arguments"},
+		{`vars.a @ vars.b`, "unexpected character"},
+		{`vars.a vars.b`, `unexpected "vars.b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCondition(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition_Refs(t *testing.T) {
+	c, err := ParseCondition(`vars.a == "x" && (steps.review.read == "done" || contains(vars.b, steps.c))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars, steps := c.Refs()
+	if strings.Join(vars, ",") != "a,b" || strings.Join(steps, ",") != "review.read,c" {
+		t.Errorf("Refs() = %v, %v", vars, steps)
+	}
+}
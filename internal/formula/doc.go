@@ -77,6 +77,68 @@
 //	title = "Publish"
 //	needs = ["build"]
 //
+// # Composition
+//
+// Formulas can reuse other formulas. extends inherits a base formula's
+// steps and vars, with the formula's own steps replacing inherited steps of
+// the same ID; include mixes in another formula's steps the same way:
+//
+//	formula = "shiny-secure"
+//	extends = ["shiny"]
+//
+//	[[steps]]
+//	id = "audit"
+//	title = "Security audit"
+//	needs = ["implement"]
+//
+// A step with a formula field calls a workflow formula as a sub-routine. It
+// is replaced by the sub-formula's steps, prefixed with the step ID, and
+// with maps the sub-formula's vars:
+//
+//	[[steps]]
+//	id = "review"
+//	formula = "code-review"
+//	needs = ["implement"]
+//	with = { target = "{{feature}}" }
+//
+// A compose table weaves aspect formulas' advice around matching steps and
+// replaces steps with an expansion formula's templates:
+//
+//	[compose]
+//	aspects = ["security-audit"]
+//
+//	[[compose.expand]]
+//	target = "implement"
+//	with = "rule-of-five"
+//
+// Composition is resolved during parsing (see Formula.Resolve). ParseFile
+// looks up referenced formulas next to the file, then among the embedded
+// formulas; Parse uses the embedded formulas only.
+//
+// # Control Flow
+//
+// Workflow steps can be conditional, looped and retried:
+//
+//	[[steps]]
+//	id = "deploy"
+//	title = "Deploy to {{item}}"
+//	for_each = "targets"
+//	retry = 2
+//	timeout = "30m"
+//
+//	[[steps]]
+//	id = "rollback"
+//	needs = ["deploy"]
+//	when = 'steps.deploy == "failed"'
+//
+// See Condition for the when language. Formula.Instantiate expands for_each
+// steps and drops steps whose var-only condition is false; Formula.Advance
+// decides what runs next from the outcomes of finished steps.
+//
+// gt applies Advance only to molecules whose steps are beads. For root-only
+// wisps, whose steps are rendered inline, only Instantiate applies; retry,
+// timeout and conditions on step outcomes are advisory.
+//
 // # Validation
 //
 // The package performs comprehensive validation:
@@ -85,6 +147,9 @@
 //   - Unique IDs within steps/legs/templates/aspects
 //   - Valid dependency references (needs/depends_on)
 //   - Cycle detection in dependency graphs
+//   - Cycles between formulas that extend, include or call each other
+//   - when conditions that parse and only reference known vars and
+//     steps the step depends on
 //
 // # Cycle Detection
 //
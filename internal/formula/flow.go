@@ -0,0 +1,353 @@
+package formula
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StepOutcome is the result of a step in a running workflow.
+type StepOutcome string
+
+const (
+	// OutcomeDone means the step completed.
+	OutcomeDone StepOutcome = "done"
+	// OutcomeFailed means the step failed and has no retries left.
+	OutcomeFailed StepOutcome = "failed"
+	// OutcomeSkipped means the step's when condition was false.
+	OutcomeSkipped StepOutcome = "skipped"
+)
+
+// TimeoutDuration returns the step's timeout, or 0 if it has none.
+func (s *Step) TimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Timeout) // validated at parse time
+	return d
+}
+
+// CanRetry reports whether a step that has failed the given number of times
+// may run again under its retry policy.
+func (s *Step) CanRetry(failures int) bool {
+	return failures <= s.Retry
+}
+
+// HasControlFlow reports whether any step uses when, for_each, retry or
+// timeout, i.e. whether a run needs Advance rather than plain dependencies.
+func (f *Formula) HasControlFlow() bool {
+	for _, step := range f.Steps {
+		if step.When != "" || step.ForEach != "" || step.Retry > 0 || step.Timeout != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// VarValues returns the formula's var and input defaults overlaid with vars.
+func (f *Formula) VarValues(vars map[string]string) map[string]string {
+	values := make(map[string]string, len(f.Vars)+len(f.Inputs)+len(vars))
+	for name, in := range f.Inputs {
+		if in.Default != "" {
+			values[name] = in.Default
+		}
+	}
+	for name, v := range f.Vars {
+		if v.Default != "" {
+			values[name] = v.Default
+		}
+	}
+	for name, val := range vars {
+		values[name] = val
+	}
+	return values
+}
+
+// Render replaces {{var}} placeholders in text with the formula's var values
+// overlaid with vars. Unknown placeholders are left as is.
+func (f *Formula) Render(text string, vars map[string]string) string {
+	return substituteVars(text, f.VarValues(vars))
+}
+
+// validateControlFlow checks when/for_each/retry/timeout on workflow steps.
+// ids is the set of step IDs.
+func (f *Formula) validateControlFlow(ids map[string]bool) error {
+	ancestors := f.stepAncestors()
+	for _, step := range f.Steps {
+		if step.ForEach != "" && !f.hasVar(step.ForEach) {
+			return fmt.Errorf("step %q: for_each references unknown var %q", step.ID, step.ForEach)
+		}
+		if step.Retry < 0 {
+			return fmt.Errorf("step %q: retry must be >= 0", step.ID)
+		}
+		if step.Timeout != "" {
+			if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("step %q: invalid timeout %q", step.ID, step.Timeout)
+			}
+		}
+		if step.When == "" {
+			continue
+		}
+		cond, err := ParseCondition(step.When)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		vars, steps := cond.Refs()
+		for _, name := range vars {
+			if !f.hasVar(name) {
+				return fmt.Errorf("step %q: when references unknown var %q", step.ID, name)
+			}
+		}
+		for _, ref := range steps {
+			members := stepGroup(ref, ids)
+			if len(members) == 0 {
+				return fmt.Errorf("step %q: when references unknown step %q", step.ID, ref)
+			}
+			for _, m := range members {
+				if !ancestors[step.ID][m] {
+					return fmt.Errorf("step %q: when references step %q, which it does not depend on", step.ID, ref)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Formula) hasVar(name string) bool {
+	if _, ok := f.Vars[name]; ok {
+		return true
+	}
+	_, ok := f.Inputs[name]
+	return ok
+}
+
+// stepAncestors returns, for each step, the set of steps it transitively needs.
+func (f *Formula) stepAncestors() map[string]map[string]bool {
+	needs := make(map[string][]string, len(f.Steps))
+	for _, step := range f.Steps {
+		needs[step.ID] = step.Needs
+	}
+	result := make(map[string]map[string]bool, len(f.Steps))
+	var visit func(id string, into map[string]bool)
+	visit = func(id string, into map[string]bool) {
+		for _, need := range needs[id] {
+			if !into[need] {
+				into[need] = true
+				visit(need, into)
+			}
+		}
+	}
+	for _, step := range f.Steps {
+		set := make(map[string]bool)
+		visit(step.ID, set)
+		result[step.ID] = set
+	}
+	return result
+}
+
+// stepGroup returns the step IDs a condition reference stands for: the step
+// itself, or every step expanded from a for_each or call step of that ID.
+func stepGroup(ref string, ids map[string]bool) []string {
+	if ids[ref] {
+		return []string{ref}
+	}
+	var members []string
+	for id := range ids {
+		if strings.HasPrefix(id, ref+".") {
+			members = append(members, id)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// Instantiate returns a copy of a workflow formula prepared for one run
+// with the given vars (defaults fill in unset vars):
+//
+//   - for_each steps are expanded into one step per list item, with IDs
+//     "<id>.1", "<id>.2", ... and {{item}} replaced by the item. Steps that
+//     needed the for_each step need every copy.
+//   - steps whose when condition only references vars are evaluated now;
+//     if false they are dropped (see SkippedSteps) and their dependents
+//     inherit their needs.
+//
+// Conditions on step outcomes are left for Advance. Non-workflow formulas
+// are returned unchanged.
+func (f *Formula) Instantiate(vars map[string]string) (*Formula, error) {
+	if err := f.Resolve(f.loader); err != nil {
+		return nil, err
+	}
+	out := *f
+	if f.Type != TypeWorkflow {
+		return &out, nil
+	}
+	values := f.VarValues(vars)
+
+	// replaced maps a removed step ID to the IDs that stand in for it in
+	// dependents' needs: its copies, or its own needs if it was dropped.
+	replaced := make(map[string][]string)
+	var steps []Step
+	var skipped []string
+	for _, step := range f.Steps {
+		if step.When != "" {
+			cond, err := ParseCondition(step.When)
+			if err != nil {
+				return nil, fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			if _, refs := cond.Refs(); len(refs) == 0 && !cond.Eval(ConditionEnv{Vars: values}) {
+				replaced[step.ID] = step.Needs
+				skipped = append(skipped, step.ID)
+				continue
+			}
+		}
+		if step.ForEach == "" {
+			steps = append(steps, step)
+			continue
+		}
+		items := splitList(values[step.ForEach])
+		if len(items) == 0 {
+			replaced[step.ID] = step.Needs
+			skipped = append(skipped, step.ID)
+			continue
+		}
+		for i, item := range items {
+			c := step
+			c.ID = step.ID + "." + strconv.Itoa(i+1)
+			c.ForEach = ""
+			itemVars := map[string]string{"item": item}
+			c.Title = substituteVars(step.Title, itemVars)
+			c.Description = substituteVars(step.Description, itemVars)
+			c.Acceptance = substituteVars(step.Acceptance, itemVars)
+			steps = append(steps, c)
+			replaced[step.ID] = append(replaced[step.ID], c.ID)
+		}
+	}
+
+	var expand func(needs []string, into []string) []string
+	expand = func(needs []string, into []string) []string {
+		for _, need := range needs {
+			if r, ok := replaced[need]; ok {
+				into = expand(r, into)
+			} else if !slices.Contains(into, need) {
+				into = append(into, need)
+			}
+		}
+		return into
+	}
+	for i := range steps {
+		steps[i].Needs = expand(steps[i].Needs, nil)
+	}
+
+	out.Steps = steps
+	out.skipped = skipped
+	return &out, nil
+}
+
+// SkippedSteps returns the IDs of steps that Instantiate dropped because
+// their when condition was false or their for_each list was empty.
+func (f *Formula) SkippedSteps() []string {
+	return f.skipped
+}
+
+// Advance decides what can run next in an instantiated workflow, given the
+// outcomes of finished steps. A step is considered once all its needs have
+// an outcome; a failed need blocks it unless its when condition references
+// that step (so a step can handle another's failure). Considered steps whose
+// when condition is false are skipped, which may in turn unblock others.
+//
+// Returns the steps ready to start and the steps newly skipped. Steps
+// already in outcomes are never returned.
+func (f *Formula) Advance(vars map[string]string, outcomes map[string]StepOutcome) (ready, skipped []string) {
+	values := f.VarValues(vars)
+	state := make(map[string]StepOutcome, len(outcomes)+len(f.skipped))
+	for _, id := range f.skipped {
+		state[id] = OutcomeSkipped
+	}
+	for id, o := range outcomes {
+		state[id] = o
+	}
+	ids := make(map[string]bool, len(f.Steps)+len(f.skipped))
+	for _, step := range f.Steps {
+		ids[step.ID] = true
+	}
+	for _, id := range f.skipped {
+		ids[id] = true
+	}
+	outcome := func(ref string) StepOutcome {
+		if o, ok := state[ref]; ok {
+			return o
+		}
+		return aggregateOutcome(stepGroup(ref, ids), state)
+	}
+
+	isReady := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, step := range f.Steps {
+			if state[step.ID] != "" || isReady[step.ID] {
+				continue
+			}
+			var cond *Condition
+			var condSteps []string
+			if step.When != "" {
+				cond, _ = ParseCondition(step.When) // validated at parse time
+				if cond != nil {
+					_, condSteps = cond.Refs()
+				}
+			}
+			if !needsMet(step.Needs, condSteps, outcome) {
+				continue
+			}
+			if cond != nil && !cond.Eval(ConditionEnv{Vars: values, Steps: outcome}) {
+				state[step.ID] = OutcomeSkipped
+				skipped = append(skipped, step.ID)
+				changed = true
+				continue
+			}
+			isReady[step.ID] = true
+			ready = append(ready, step.ID)
+		}
+	}
+	return ready, skipped
+}
+
+// needsMet reports whether every need has an outcome. Failed needs only
+// count when handled, i.e. referenced by the step's when condition.
+func needsMet(needs, handled []string, outcome func(string) StepOutcome) bool {
+	for _, need := range needs {
+		switch outcome(need) {
+		case "":
+			return false
+		case OutcomeFailed:
+			if !slices.ContainsFunc(handled, func(ref string) bool {
+				return ref == need || strings.HasPrefix(need, ref+".")
+			}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// aggregateOutcome combines the outcomes of a step group: pending if any
+// member is pending, failed if any failed, skipped if all were skipped,
+// done otherwise.
+func aggregateOutcome(members []string, state map[string]StepOutcome) StepOutcome {
+	if len(members) == 0 {
+		return ""
+	}
+	result := OutcomeSkipped
+	for _, id := range members {
+		switch state[id] {
+		case "":
+			return ""
+		case OutcomeFailed:
+			result = OutcomeFailed
+		case OutcomeDone:
+			if result == OutcomeSkipped {
+				result = OutcomeDone
+			}
+		}
+	}
+	return result
+}
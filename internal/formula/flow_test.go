@@ -0,0 +1,207 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const flowFormula = `
+formula = "flow"
+
+[vars.changed_files]
+default = ""
+
+[vars.targets]
+default = ""
+
+[[steps]]
+id = "implement"
+title = "Implement"
+
+[[steps]]
+id = "audit"
+title = "Security audit"
+needs = ["implement"]
+when = 'matches(vars.changed_files, "auth/*")'
+
+[[steps]]
+id = "deploy"
+title = "Deploy to {{item}}"
+needs = ["audit"]
+for_each = "targets"
+retry = 2
+timeout = "30m"
+
+[[steps]]
+id = "rollback"
+title = "Roll back"
+needs = ["deploy"]
+when = 'steps.deploy == "failed"'
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["deploy"]
+when = 'steps.deploy == "done"'
+`
+
+func TestValidate_ControlFlow(t *testing.T) {
+	base := "formula = \"f\"\n[vars.v]\ndefault = \"\"\n[[steps]]\nid = \"a\"\n[[steps]]\nid = \"b\"\n"
+	tests := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"bad condition", `when = "vars.v =="`, "invalid condition"},
+		{"unknown var", `when = "vars.nope"`, `unknown var "nope"`},
+		{"unknown step", `when = "steps.nope == \"done\""`, `unknown step "nope"`},
+		{"not a dependency", `when = "steps.a == \"done\""`, "does not depend on"},
+		{"for_each unknown var", `for_each = "nope"`, `for_each references unknown var "nope"`},
+		{"negative retry", `retry = -1`, "retry must be >= 0"},
+		{"bad timeout", `timeout = "soon"`, `invalid timeout "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(base + tt.step + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte(flowFormula)); err != nil {
+		t.Errorf("valid control flow rejected: %v", err)
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("condition false and for_each", func(t *testing.T) {
+		inst, err := f.Instantiate(map[string]string{"changed_files": "README.md", "targets": "staging, prod"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(inst.GetAllIDs(), ","); got != "implement,deploy.1,deploy.2,rollback,announce" {
+			t.Errorf("steps = %s", got)
+		}
+		if got := strings.Join(inst.SkippedSteps(), ","); got != "audit" {
+			t.Errorf("skipped = %s", got)
+		}
+		d2 := inst.GetStep("deploy.2")
+		if d2.Title != "Deploy to prod" || strings.Join(d2.Needs, ",") != "implement" {
+			t.Errorf("deploy.2 = %+v (should inherit audit's needs)", d2)
+		}
+		if d2.Retry != 2 || d2.TimeoutDuration() != 30*time.Minute {
+			t.Errorf("copies should keep retry/timeout: %+v", d2)
+		}
+		if got := strings.Join(inst.GetDependencies("rollback"), ","); got != "deploy.1,deploy.2" {
+			t.Errorf("rollback needs = %s", got)
+		}
+		if len(f.GetAllIDs()) != 5 || f.GetStep("audit") == nil {
+			t.Error("Instantiate must not modify the formula")
+		}
+	})
+
+	t.Run("condition true, empty list", func(t *testing.T) {
+		inst, err := f.Instantiate(map[string]string{"changed_files": "auth/token.go"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(inst.GetAllIDs(), ","); got != "implement,audit,rollback,announce" {
+			t.Errorf("steps = %s", got)
+		}
+		if got := strings.Join(inst.GetDependencies("announce"), ","); got != "audit" {
+			t.Errorf("announce needs = %s", got)
+		}
+	})
+}
+
+func TestAdvance(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"targets": "staging,prod"}
+	inst, err := f.Instantiate(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		outcomes    map[string]StepOutcome
+		wantReady   string
+		wantSkipped string
+	}{
+		{"start", nil, "implement", ""},
+		{"fan out", map[string]StepOutcome{"implement": OutcomeDone}, "deploy.1,deploy.2", ""},
+		{"partial", map[string]StepOutcome{"implement": OutcomeDone, "deploy.1": OutcomeDone}, "deploy.2", ""},
+		{"all done", map[string]StepOutcome{"implement": OutcomeDone, "deploy.1": OutcomeDone, "deploy.2": OutcomeDone},
+			"announce", "rollback"},
+		{"one failed", map[string]StepOutcome{"implement": OutcomeDone, "deploy.1": OutcomeDone, "deploy.2": OutcomeFailed},
+			"rollback", "announce"},
+		{"failure blocks unhandled dependents", map[string]StepOutcome{"implement": OutcomeFailed}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, skipped := inst.Advance(vars, tt.outcomes)
+			if got := strings.Join(ready, ","); got != tt.wantReady {
+				t.Errorf("ready = %s, want %s", got, tt.wantReady)
+			}
+			if got := strings.Join(skipped, ","); got != tt.wantSkipped {
+				t.Errorf("skipped = %s, want %s", got, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestAdvance_SkipCascades(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "cascade"
+
+[vars.fast]
+default = ""
+
+[[steps]]
+id = "build"
+
+[[steps]]
+id = "slow-tests"
+needs = ["build"]
+when = 'steps.build == "done" && !vars.fast'
+
+[[steps]]
+id = "report"
+needs = ["slow-tests"]
+when = 'steps.slow-tests == "skipped"'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"fast": "true"}
+	inst, err := f.Instantiate(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ready, skipped := inst.Advance(vars, map[string]StepOutcome{"build": OutcomeDone})
+	if strings.Join(skipped, ",") != "slow-tests" || strings.Join(ready, ",") != "report" {
+		t.Errorf("ready = %v, skipped = %v", ready, skipped)
+	}
+}
+
+func TestStep_CanRetry(t *testing.T) {
+	s := Step{Retry: 2}
+	for failures, want := range map[int]bool{1: true, 2: true, 3: false} {
+		if got := s.CanRetry(failures); got != want {
+			t.Errorf("CanRetry(%d) = %v, want %v", failures, got, want)
+		}
+	}
+	if (&Step{}).CanRetry(1) {
+		t.Error("no retry policy should not retry")
+	}
+}
@@ -3,9 +3,10 @@ package formula
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"sort"
-
-	"github.com/BurntSushi/toml"
 )
 
 // ParseFile reads and parses a formula.toml file.
@@ -14,24 +15,31 @@ func ParseFile(path string) (*Formula, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading formula file: %w", err)
 	}
-	return Parse(data)
+	// Formulas referenced by extends/include/call are looked up next to
+	// this file first.
+	return ParseWithLoader(data, DirLoader(filepath.Dir(path)))
 }
 
-// Parse parses formula.toml content from bytes.
+// Parse parses formula.toml content from bytes. Formulas referenced by
+// composition directives are loaded from the embedded formulas.
 func Parse(data []byte) (*Formula, error) {
-	var f Formula
-	if _, err := toml.Decode(string(data), &f); err != nil {
-		return nil, fmt.Errorf("parsing TOML: %w", err)
-	}
+	return ParseWithLoader(data, EmbeddedLoader)
+}
 
-	// Infer type from content if not explicitly set
-	f.inferType()
+// ParseWithLoader parses formula.toml content, loading formulas referenced
+// by composition directives with load.
+func ParseWithLoader(data []byte, load Loader) (*Formula, error) {
+	f, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	f.loader = load
 
 	if err := f.Validate(); err != nil {
 		return nil, err
 	}
 
-	return &f, nil
+	return f, nil
 }
 
 // inferType sets the formula type based on content when not explicitly set.
@@ -47,7 +55,7 @@ func (f *Formula) inferType() {
 		f.Type = TypeConvoy
 	} else if len(f.Template) > 0 {
 		f.Type = TypeExpansion
-	} else if len(f.Aspects) > 0 {
+	} else if len(f.Aspects) > 0 || len(f.Advice) > 0 {
 		f.Type = TypeAspect
 	}
 }
@@ -59,6 +67,12 @@ func (f *Formula) Validate() error {
 		return fmt.Errorf("formula field is required")
 	}
 
+	// Flatten extends/include/call so the checks below (including cycle
+	// detection) cover the composed formula.
+	if err := f.Resolve(f.loader); err != nil {
+		return err
+	}
+
 	if !f.Type.IsValid() {
 		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
 	}
@@ -147,7 +161,7 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
-	return nil
+	return f.validateControlFlow(seen)
 }
 
 func (f *Formula) validateExpansion() error {
@@ -185,8 +199,30 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
+	}
+
+	for _, adv := range f.Advice {
+		if adv.Target == "" {
+			return fmt.Errorf("advice missing required target field")
+		}
+		if _, err := path.Match(adv.Target, ""); err != nil {
+			return fmt.Errorf("advice target %q: %w", adv.Target, err)
+		}
+		if adv.Around == nil {
+			continue
+		}
+		for _, tmpl := range append(slices.Clone(adv.Around.Before), adv.Around.After...) {
+			if tmpl.ID == "" {
+				return fmt.Errorf("advice for %q has a step missing required id field", adv.Target)
+			}
+		}
+	}
+	for _, pc := range f.Pointcuts {
+		if _, err := path.Match(pc.Glob, ""); err != nil || pc.Glob == "" {
+			return fmt.Errorf("invalid pointcut glob %q", pc.Glob)
+		}
 	}
 
 	// Check aspect IDs are unique
@@ -265,9 +301,14 @@ func checkDependencyCycles(deps map[string][]string) error {
 }
 
 // TopologicalSort returns steps in dependency order (dependencies before dependents).
-// Only applicable to workflow and expansion formulas.
+// Only applicable to workflow and expansion formulas. Composed formulas are
+// flattened first, so sub-formula steps appear under their prefixed IDs.
 // Returns an error if there are cycles.
 func (f *Formula) TopologicalSort() ([]string, error) {
+	if err := f.Resolve(f.loader); err != nil {
+		return nil, err
+	}
+
 	var items []string
 	var deps map[string][]string
 
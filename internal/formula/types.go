@@ -33,21 +33,38 @@ type Formula struct {
 	Version     int         `toml:"version"`
 
 	// Convoy-specific
-	Inputs    map[string]Input `toml:"inputs"`
+	Inputs    map[string]Input  `toml:"inputs"`
 	Prompts   map[string]string `toml:"prompts"`
 	Output    *Output           `toml:"output"`
 	Legs      []Leg             `toml:"legs"`
 	Synthesis *Synthesis        `toml:"synthesis"`
+	Presets   map[string]Preset `toml:"presets"`
 
 	// Workflow-specific
-	Steps []Step           `toml:"steps"`
-	Vars  map[string]Var   `toml:"vars"`
+	Steps []Step         `toml:"steps"`
+	Vars  map[string]Var `toml:"vars"`
 
 	// Expansion-specific
 	Template []Template `toml:"template"`
 
 	// Aspect-specific (similar to convoy but for analysis)
 	Aspects []Aspect `toml:"aspects"`
+
+	// Aspect-specific: steps woven around other formulas' steps
+	Advice    []Advice   `toml:"advice"`
+	Pointcuts []Pointcut `toml:"pointcuts"`
+
+	// Digest settings for bd mol squash, which reads them from the formula
+	Squash *Squash `toml:"squash"`
+
+	// Composition (see Resolve)
+	Extends []string `toml:"extends"`
+	Include []string `toml:"include"`
+	Compose *Compose `toml:"compose"`
+
+	loader   Loader   // loads formulas referenced by composition directives
+	resolved bool     // composition directives have been flattened
+	skipped  []string // steps dropped by Instantiate
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
@@ -58,6 +75,52 @@ type Aspect struct {
 	Description string `toml:"description"`
 }
 
+// Preset names a subset of a convoy formula's legs.
+type Preset struct {
+	Description string   `toml:"description"`
+	Legs        []string `toml:"legs"`
+}
+
+// Squash configures the digest bd creates when the molecule is squashed.
+type Squash struct {
+	Trigger        string `toml:"trigger"`
+	TemplateType   string `toml:"template_type"`
+	IncludeMetrics bool   `toml:"include_metrics"`
+}
+
+// Advice adds steps around the workflow steps matching Target (a glob over
+// step IDs) when an aspect formula is composed into a workflow.
+type Advice struct {
+	Target string        `toml:"target"`
+	Around *AroundAdvice `toml:"around"`
+}
+
+// AroundAdvice holds the steps inserted before and after an advised step.
+// {step.id}, {step.title} and {step.description} refer to the advised step.
+type AroundAdvice struct {
+	Before []Template `toml:"before"`
+	After  []Template `toml:"after"`
+}
+
+// Pointcut limits where an aspect's advice applies: when an aspect declares
+// pointcuts, only steps matching one of the globs are advised.
+type Pointcut struct {
+	Glob string `toml:"glob"`
+}
+
+// Compose applies aspect and expansion formulas to a workflow's steps.
+type Compose struct {
+	Aspects []string        `toml:"aspects"`
+	Expand  []ComposeExpand `toml:"expand"`
+}
+
+// ComposeExpand replaces step Target with the templates of the expansion
+// formula With.
+type ComposeExpand struct {
+	Target string `toml:"target"`
+	With   string `toml:"with"`
+}
+
 // Input represents an input parameter for a formula.
 type Input struct {
 	Description    string   `toml:"description"`
@@ -94,9 +157,20 @@ type Step struct {
 	ID          string   `toml:"id"`
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
-	Parallel    bool     `toml:"parallel"`   // If true, this step can run concurrently with other parallel steps that share the same needs
-	Acceptance  string   `toml:"acceptance"` // Exit criteria for this step (used by Ralph loop mode)
+	Needs       []string `toml:"needs,omitempty"`
+	Parallel    bool     `toml:"parallel,omitempty"`   // If true, this step can run concurrently with other parallel steps that share the same needs
+	Acceptance  string   `toml:"acceptance,omitempty"` // Exit criteria for this step (used by Ralph loop mode)
+
+	// Sub-formula call: the step is replaced by the named workflow formula's
+	// steps when the formula is resolved. With maps the sub-formula's vars.
+	Formula string            `toml:"formula,omitempty"`
+	With    map[string]string `toml:"with,omitempty"`
+
+	// Control flow (see Instantiate and Advance)
+	When    string `toml:"when,omitempty"`     // Condition over vars and step outcomes; the step is skipped when false
+	ForEach string `toml:"for_each,omitempty"` // Var holding a list; the step runs once per item as {{item}}
+	Retry   int    `toml:"retry,omitzero"`     // Extra attempts allowed after the step fails
+	Timeout string `toml:"timeout,omitempty"`  // Maximum duration of one attempt (e.g. "30m")
 }
 
 // Template represents a template step in an expansion formula.
@@ -104,7 +178,7 @@ type Template struct {
 	ID          string   `toml:"id"`
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	Needs       []string `toml:"needs,omitempty"`
 }
 
 // Var represents a variable definition for formulas.
@@ -112,8 +186,8 @@ type Template struct {
 // and full table syntax ([vars.wisp_type] with description/required/default).
 type Var struct {
 	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
 }
 
 // UnmarshalTOML allows Var to be decoded from either a plain string
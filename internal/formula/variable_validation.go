@@ -68,11 +68,16 @@ func (f *Formula) ValidateTemplateVariables() error {
 	allText.WriteString(f.Description)
 	allText.WriteString("\n")
 
-	// Steps (workflow)
+	// Steps (workflow). {{item}} is bound by for_each, not [vars].
 	for _, step := range f.Steps {
-		allText.WriteString(step.Title)
+		title, description := step.Title, step.Description
+		if step.ForEach != "" {
+			title = strings.ReplaceAll(title, "{{item}}", "")
+			description = strings.ReplaceAll(description, "{{item}}", "")
+		}
+		allText.WriteString(title)
 		allText.WriteString("\n")
-		allText.WriteString(step.Description)
+		allText.WriteString(description)
 		allText.WriteString("\n")
 	}
 
@@ -0,0 +1,174 @@
+package mail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Attachment limits. Attachments are for logs and reports, not artifacts;
+// anything larger belongs in the repo or a gate artifacts directory.
+const (
+	// MaxAttachmentSize is the largest single attachment.
+	MaxAttachmentSize = 1 << 20
+
+	// MaxAttachmentsSize is the largest combined size of a message's attachments.
+	MaxAttachmentsSize = 4 << 20
+
+	// MaxAttachments is the most attachments one message may carry.
+	MaxAttachments = 16
+)
+
+// ErrAttachmentNotFound is returned when an attachment's blob is missing.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Attachment references a file stored alongside the mail beads. The
+// content lives in the attachment store, addressed by its SHA-256, so
+// fan-out copies of a message share one blob.
+type Attachment struct {
+	// Name is the file name shown to readers (no directory).
+	Name string `json:"name"`
+
+	// Size is the content length in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex digest of the content and its key in the store.
+	SHA256 string `json:"sha256"`
+
+	// MediaType is the content's MIME type, if known.
+	MediaType string `json:"media_type,omitempty"`
+}
+
+// AttachmentStore holds attachment blobs in <beadsDir>/attachments.
+type AttachmentStore struct {
+	dir string
+}
+
+// NewAttachmentStore returns the attachment store of a beads directory.
+func NewAttachmentStore(beadsDir string) *AttachmentStore {
+	return &AttachmentStore{dir: filepath.Join(beadsDir, "attachments")}
+}
+
+// Put stores data under name and returns the attachment reference.
+func (s *AttachmentStore) Put(name string, data []byte) (Attachment, error) {
+	if err := validateAttachmentName(name); err != nil {
+		return Attachment{}, err
+	}
+	if len(data) > MaxAttachmentSize {
+		return Attachment{}, fmt.Errorf("attachment %s is %d bytes (max %d)", name, len(data), MaxAttachmentSize)
+	}
+
+	sum := sha256.Sum256(data)
+	a := Attachment{
+		Name:      name,
+		Size:      int64(len(data)),
+		SHA256:    hex.EncodeToString(sum[:]),
+		MediaType: attachmentMediaType(name, data),
+	}
+	path := s.path(a.SHA256)
+	if _, err := os.Stat(path); err == nil {
+		return a, nil // same content already stored
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return Attachment{}, fmt.Errorf("creating attachment store: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, data, 0644); err != nil {
+		return Attachment{}, fmt.Errorf("storing attachment %s: %w", name, err)
+	}
+	return a, nil
+}
+
+// Get returns an attachment's content, verifying its digest.
+func (s *AttachmentStore) Get(a Attachment) ([]byte, error) {
+	if !isHexDigest(a.SHA256) {
+		return nil, fmt.Errorf("attachment %s: invalid digest %q", a.Name, a.SHA256)
+	}
+	data, err := os.ReadFile(s.path(a.SHA256))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, a.Name)
+		}
+		return nil, fmt.Errorf("reading attachment %s: %w", a.Name, err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != a.SHA256 {
+		return nil, fmt.Errorf("attachment %s is corrupt (digest mismatch)", a.Name)
+	}
+	return data, nil
+}
+
+func (s *AttachmentStore) path(digest string) string {
+	return filepath.Join(s.dir, digest)
+}
+
+// FindAttachment returns the message's attachment with the given name.
+func (m *Message) FindAttachment(name string) (Attachment, bool) {
+	for _, a := range m.Attachments {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Attachment{}, false
+}
+
+// validateAttachments checks a message's attachment references against
+// the limits.
+func validateAttachments(atts []Attachment) error {
+	if len(atts) > MaxAttachments {
+		return fmt.Errorf("message has %d attachments (max %d)", len(atts), MaxAttachments)
+	}
+	var total int64
+	seen := make(map[string]bool, len(atts))
+	for _, a := range atts {
+		if err := validateAttachmentName(a.Name); err != nil {
+			return err
+		}
+		if seen[a.Name] {
+			return fmt.Errorf("duplicate attachment name %q", a.Name)
+		}
+		seen[a.Name] = true
+		if !isHexDigest(a.SHA256) {
+			return fmt.Errorf("attachment %s: invalid digest %q", a.Name, a.SHA256)
+		}
+		if a.Size < 0 || a.Size > MaxAttachmentSize {
+			return fmt.Errorf("attachment %s is %d bytes (max %d)", a.Name, a.Size, MaxAttachmentSize)
+		}
+		total += a.Size
+	}
+	if total > MaxAttachmentsSize {
+		return fmt.Errorf("attachments total %d bytes (max %d)", total, MaxAttachmentsSize)
+	}
+	return nil
+}
+
+func validateAttachmentName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("invalid attachment name %q", name)
+	}
+	return nil
+}
+
+func isHexDigest(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// attachmentMediaType guesses a MIME type from the file extension, then
+// from the content.
+func attachmentMediaType(name string, data []byte) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
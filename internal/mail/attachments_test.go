@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentStore_PutGet(t *testing.T) {
+	store := NewAttachmentStore(t.TempDir())
+	data := []byte("FAIL TestFoo\n")
+
+	a, err := store.Put("gate.log", data)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if a.Name != "gate.log" || a.Size != int64(len(data)) || len(a.SHA256) != 64 {
+		t.Errorf("Put = %+v", a)
+	}
+	if a.MediaType == "" {
+		t.Error("MediaType not detected")
+	}
+
+	got, err := store.Get(a)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, want %q", got, data)
+	}
+
+	// Same content under another name shares the blob
+	b, err := store.Put("copy.log", data)
+	if err != nil {
+		t.Fatalf("Put copy: %v", err)
+	}
+	if b.SHA256 != a.SHA256 {
+		t.Errorf("copy digest = %s, want %s", b.SHA256, a.SHA256)
+	}
+}
+
+func TestAttachmentStore_Limits(t *testing.T) {
+	store := NewAttachmentStore(t.TempDir())
+
+	if _, err := store.Put("big.bin", make([]byte, MaxAttachmentSize+1)); err == nil {
+		t.Error("Put accepted oversized attachment")
+	}
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`} {
+		if _, err := store.Put(name, []byte("x")); err == nil {
+			t.Errorf("Put accepted name %q", name)
+		}
+	}
+}
+
+func TestAttachmentStore_GetCorruptOrMissing(t *testing.T) {
+	beadsDir := t.TempDir()
+	store := NewAttachmentStore(beadsDir)
+	a, err := store.Put("notes.txt", []byte("original"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	path := filepath.Join(beadsDir, "attachments", a.SHA256)
+	if err := os.WriteFile(path, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(a); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("Get tampered = %v, want corrupt error", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(a); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("Get missing = %v, want ErrAttachmentNotFound", err)
+	}
+}
+
+func TestValidateAttachments(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	att := func(name string, size int64) Attachment {
+		return Attachment{Name: name, Size: size, SHA256: digest}
+	}
+
+	tests := []struct {
+		name    string
+		atts    []Attachment
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"one", []Attachment{att("a.log", 10)}, false},
+		{"duplicate name", []Attachment{att("a.log", 1), att("a.log", 1)}, true},
+		{"bad digest", []Attachment{{Name: "a.log", Size: 1, SHA256: "xyz"}}, true},
+		{"too large", []Attachment{att("a.log", MaxAttachmentSize+1)}, true},
+		{"total too large", []Attachment{
+			att("a", MaxAttachmentSize), att("b", MaxAttachmentSize), att("c", MaxAttachmentSize),
+			att("d", MaxAttachmentSize), att("e", 1),
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttachments(tt.atts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAttachments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	many := make([]Attachment, MaxAttachments+1)
+	for i := range many {
+		many[i] = att(strings.Repeat("x", i+1), 1)
+	}
+	if err := validateAttachments(many); err == nil {
+		t.Error("validateAttachments accepted too many attachments")
+	}
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MaxPayloadSize limits the encoded size of a message payload. Payloads
+// carry structured data for handlers; bulky content belongs in attachments.
+const MaxPayloadSize = 64 << 10
+
+// Payload is typed JSON data carried by a message alongside its
+// human-readable body. Schema names the shape of Data (e.g.
+// "gastown.protocol.merge_failed.v1") so consumers can decode it without
+// parsing the body text.
+type Payload struct {
+	// Schema identifies the structure of Data.
+	Schema string `json:"schema"`
+
+	// Data is the payload itself.
+	Data json.RawMessage `json:"data"`
+}
+
+// NewPayload encodes v as a payload with the given schema.
+func NewPayload(schema string, v any) (*Payload, error) {
+	if schema == "" {
+		return nil, fmt.Errorf("payload schema is required")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s payload: %w", schema, err)
+	}
+	return &Payload{Schema: schema, Data: data}, nil
+}
+
+// Decode unmarshals the payload into v after checking its schema.
+func (p *Payload) Decode(schema string, v any) error {
+	if p == nil {
+		return fmt.Errorf("message has no payload")
+	}
+	if p.Schema != schema {
+		return fmt.Errorf("payload schema is %q, want %q", p.Schema, schema)
+	}
+	if err := json.Unmarshal(p.Data, v); err != nil {
+		return fmt.Errorf("decoding %s payload: %w", schema, err)
+	}
+	return nil
+}
+
+// envelopeMarker separates the human-readable body from the envelope in a
+// message bead's description. Beads have no structured metadata field for
+// messages, so the envelope travels as a single JSON line after the marker.
+const envelopeMarker = "\n\n--- gt:envelope ---\n"
+
+// envelope is the machine-readable part of a message bead's description.
+type envelope struct {
+	Payload     *Payload     `json:"payload,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// encodeDescription returns the bead description for a message: the body,
+// followed by the envelope when the message has a payload or attachments.
+func encodeDescription(msg *Message) (string, error) {
+	if msg.Payload == nil && len(msg.Attachments) == 0 {
+		return msg.Body, nil
+	}
+	if err := validateEnvelope(msg); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(envelope{Payload: msg.Payload, Attachments: msg.Attachments})
+	if err != nil {
+		return "", fmt.Errorf("encoding message envelope: %w", err)
+	}
+	return msg.Body + envelopeMarker + string(data), nil
+}
+
+// validateEnvelope checks a message's payload and attachments against the
+// limits.
+func validateEnvelope(msg *Message) error {
+	if p := msg.Payload; p != nil {
+		if p.Schema == "" {
+			return fmt.Errorf("payload must have a schema")
+		}
+		if len(p.Data) > MaxPayloadSize {
+			return fmt.Errorf("payload is %d bytes (max %d)", len(p.Data), MaxPayloadSize)
+		}
+		if !json.Valid(p.Data) {
+			return fmt.Errorf("payload %s is not valid JSON", p.Schema)
+		}
+	}
+	return validateAttachments(msg.Attachments)
+}
+
+// decodeDescription splits a bead description into the body and envelope.
+// A description without a well-formed envelope is all body.
+func decodeDescription(desc string) (string, *envelope) {
+	i := strings.LastIndex(desc, envelopeMarker)
+	if i < 0 {
+		return desc, nil
+	}
+	var env envelope
+	if err := json.Unmarshal([]byte(strings.TrimSpace(desc[i+len(envelopeMarker):])), &env); err != nil {
+		return desc, nil
+	}
+	return desc[:i], &env
+}
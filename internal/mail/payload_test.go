@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPayload_RoundTrip(t *testing.T) {
+	type report struct {
+		Failed []string `json:"failed"`
+	}
+	p, err := NewPayload("test.report.v1", report{Failed: []string{"TestFoo"}})
+	if err != nil {
+		t.Fatalf("NewPayload: %v", err)
+	}
+
+	var got report
+	if err := p.Decode("test.report.v1", &got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(got.Failed) != 1 || got.Failed[0] != "TestFoo" {
+		t.Errorf("Decode = %+v", got)
+	}
+	if err := p.Decode("other.v1", &got); err == nil {
+		t.Error("Decode accepted mismatched schema")
+	}
+
+	var nilPayload *Payload
+	if err := nilPayload.Decode("test.report.v1", &got); err == nil {
+		t.Error("Decode of nil payload should fail")
+	}
+	if _, err := NewPayload("", report{}); err == nil {
+		t.Error("NewPayload accepted empty schema")
+	}
+}
+
+func TestDescription_RoundTrip(t *testing.T) {
+	payload, err := NewPayload("test.v1", map[string]int{"n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := &Message{
+		Body:    "Hello\nworld",
+		Payload: payload,
+		Attachments: []Attachment{
+			{Name: "a.log", Size: 3, SHA256: strings.Repeat("0", 64), MediaType: "text/plain"},
+		},
+	}
+
+	desc, err := encodeDescription(msg)
+	if err != nil {
+		t.Fatalf("encodeDescription: %v", err)
+	}
+	body, env := decodeDescription(desc)
+	if body != msg.Body {
+		t.Errorf("body = %q, want %q", body, msg.Body)
+	}
+	if env == nil || env.Payload == nil || env.Payload.Schema != "test.v1" || string(env.Payload.Data) != `{"n":1}` {
+		t.Fatalf("envelope payload = %+v", env)
+	}
+	if len(env.Attachments) != 1 || env.Attachments[0] != msg.Attachments[0] {
+		t.Errorf("envelope attachments = %+v", env.Attachments)
+	}
+
+	// ToMessage strips the envelope from the body
+	bm := &BeadsMessage{ID: "hq-1", Title: "s", Description: desc}
+	got := bm.ToMessage()
+	if got.Body != msg.Body || got.Payload == nil || len(got.Attachments) != 1 {
+		t.Errorf("ToMessage = body %q payload %v attachments %v", got.Body, got.Payload, got.Attachments)
+	}
+}
+
+func TestDescription_PlainBody(t *testing.T) {
+	msg := &Message{Body: "just text"}
+	desc, err := encodeDescription(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc != "just text" {
+		t.Errorf("description = %q, want body unchanged", desc)
+	}
+
+	// A body that merely contains the marker text is not an envelope
+	odd := "before" + envelopeMarker + "not json"
+	if body, env := decodeDescription(odd); body != odd || env != nil {
+		t.Errorf("decodeDescription(%q) = %q, %v", odd, body, env)
+	}
+}
+
+func TestEncodeDescription_Limits(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *Message
+	}{
+		{"no schema", &Message{Payload: &Payload{Data: []byte(`{}`)}}},
+		{"invalid json", &Message{Payload: &Payload{Schema: "x.v1", Data: []byte(`{`)}}},
+		{"payload too large", &Message{Payload: &Payload{Schema: "x.v1", Data: []byte(`"` + strings.Repeat("a", MaxPayloadSize) + `"`)}}},
+		{"bad attachment", &Message{Attachments: []Attachment{{Name: "../x", SHA256: strings.Repeat("0", 64)}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := encodeDescription(tt.msg); err == nil {
+				t.Error("encodeDescription() succeeded, want error")
+			}
+		})
+	}
+}
@@ -207,6 +207,12 @@ func (r *Router) resolveBeadsDir() string {
 	return filepath.Join(r.townRoot, ".beads")
 }
 
+// AttachmentStore returns the store for attachments of mail sent through
+// this router. Blobs live beside the mail beads.
+func (r *Router) AttachmentStore() *AttachmentStore {
+	return NewAttachmentStore(r.resolveBeadsDir())
+}
+
 func (r *Router) ensureCustomTypes(beadsDir string) error {
 	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
 		return fmt.Errorf("ensuring custom types: %w", err)
@@ -1101,13 +1107,31 @@ func (r *Router) sendToSingle(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	// Apply the recipient's mail rules (archive, forward, nudge, ...).
+	// Rules work on a copy so fan-out callers reusing msg are unaffected.
+	if rule := r.matchRule(toIdentity, msg, labels); rule != nil {
+		ruled := *msg
+		msg = &ruled
+		consumed, err := r.applyRule(rule, toIdentity, msg, &labels)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			return nil
+		}
+	}
+
 	// Build command: bd create --assignee=<recipient> -d <body> --labels=gt:message,... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags (see web/api.go).
 	// Let bd auto-generate the ID with the correct database prefix.
+	description, err := encodeDescription(msg)
+	if err != nil {
+		return err
+	}
 	args := []string{"create",
 		"--assignee", toIdentity,
-		"-d", msg.Body,
+		"-d", description,
 	}
 
 	// Add priority flag
@@ -1137,7 +1161,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
@@ -1234,9 +1258,13 @@ func (r *Router) sendToQueue(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use queue:<name> as assignee so inbox queries can filter by queue
+	description, err := encodeDescription(msg)
+	if err != nil {
+		return err
+	}
 	args := []string{"create",
 		"--assignee", msg.To, // queue:name
-		"-d", msg.Body,
+		"-d", description,
 	}
 
 	// Add priority flag
@@ -1318,9 +1346,13 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use announce:<name> as assignee so queries can filter by channel
+	description, err := encodeDescription(msg)
+	if err != nil {
+		return err
+	}
 	args := []string{"create",
 		"--assignee", msg.To, // announce:name
-		"-d", msg.Body,
+		"-d", description,
 	}
 
 	// Add priority flag
@@ -1404,9 +1436,13 @@ func (r *Router) sendToChannel(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use channel:<name> as assignee so queries can filter by channel
+	description, err := encodeDescription(msg)
+	if err != nil {
+		return err
+	}
 	args := []string{"create",
 		"--assignee", msg.To, // channel:name
-		"-d", msg.Body,
+		"-d", description,
 	}
 
 	// Add priority flag
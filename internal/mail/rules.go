@@ -0,0 +1,208 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+// RuleActionFunc performs a mail rule action that needs more than the mail
+// package can do on its own (e.g. attaching a molecule to an agent's hook).
+// It runs before the message is delivered; on success the message is
+// delivered already read, on failure it is delivered unread as usual.
+type RuleActionFunc func(townRoot, recipient string, msg *Message) error
+
+// ruleActions maps actions to their registered implementations.
+// Registration happens via RegisterRuleAction, typically from cmd init().
+var ruleActions = make(map[string]RuleActionFunc)
+
+// RegisterRuleAction registers the implementation of a mail rule action.
+func RegisterRuleAction(action string, fn RuleActionFunc) {
+	ruleActions[action] = fn
+}
+
+// MatchRule returns the first enabled rule matching msg, or nil. labels are
+// the labels the message bead is created with.
+func MatchRule(rules []config.MailRule, msg *Message, labels []string) *config.MailRule {
+	for i := range rules {
+		if !rules[i].Disabled && ruleMatches(&rules[i].Match, msg, labels) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// ruleMatches reports whether every set field of m matches msg.
+func ruleMatches(m *config.MailRuleMatch, msg *Message, labels []string) bool {
+	if m.From != "" && !matchPattern(AddressToIdentity(m.From), AddressToIdentity(msg.From)) {
+		return false
+	}
+	if m.Subject != "" {
+		re, err := regexp.Compile("(?i)" + m.Subject)
+		if err != nil || !re.MatchString(msg.Subject) {
+			return false
+		}
+	}
+	if m.Type != "" && m.Type != string(msg.Type) {
+		return false
+	}
+	if m.Priority != "" && m.Priority != string(msg.Priority) {
+		return false
+	}
+	if m.Thread != "" && m.Thread != msg.ThreadID {
+		return false
+	}
+	for _, l := range m.Labels {
+		if !slices.Contains(labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// RulesFor returns the rules that apply to a mailbox: rules keyed by its
+// exact address first, then wildcard keys in sorted order.
+func RulesFor(cfg *config.MessagingConfig, address string) []config.MailRule {
+	if cfg == nil || len(cfg.Rules) == 0 {
+		return nil
+	}
+	identity := AddressToIdentity(address)
+	var exact, wildcard []string
+	for key := range cfg.Rules {
+		keyIdentity := AddressToIdentity(key)
+		switch {
+		case keyIdentity == identity:
+			exact = append(exact, key)
+		case strings.Contains(key, "*") && matchPattern(keyIdentity, identity):
+			wildcard = append(wildcard, key)
+		}
+	}
+	sort.Strings(exact)
+	sort.Strings(wildcard)
+
+	var rules []config.MailRule
+	for _, key := range append(exact, wildcard...) {
+		rules = append(rules, cfg.Rules[key]...)
+	}
+	return rules
+}
+
+// matchRule returns the rule to apply to msg for a recipient identity, or
+// nil. A missing or unreadable messaging config means no rules.
+func (r *Router) matchRule(identity string, msg *Message, labels []string) *config.MailRule {
+	if r.townRoot == "" || msg.skipRules {
+		return nil
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "mail rules: %v (delivering without rules)\n", err)
+		}
+		return nil
+	}
+	return MatchRule(RulesFor(cfg, identity), msg, labels)
+}
+
+// applyRule performs a rule's action on a message bound for identity.
+// It returns true when the action consumed the message, so no inbox bead
+// should be created. Actions that keep the message may adjust it and its
+// labels before delivery.
+func (r *Router) applyRule(rule *config.MailRule, identity string, msg *Message, labels *[]string) (bool, error) {
+	switch rule.Action {
+	case config.MailRuleMarkRead:
+		*labels = append(*labels, "read")
+		return false, nil
+
+	case config.MailRuleArchive:
+		mailbox, err := r.GetMailbox(msg.To)
+		if err != nil {
+			return false, err
+		}
+		archived := *msg
+		archived.Read = true
+		if err := mailbox.appendToArchive(&archived); err != nil {
+			return false, fmt.Errorf("mail rule %s: archiving: %w", rule.Name, err)
+		}
+		return true, nil
+
+	case config.MailRuleForward:
+		if err := r.Send(forwardCopy(rule, msg, rule.Target)); err != nil {
+			return false, fmt.Errorf("mail rule %s: forwarding to %s: %w", rule.Name, rule.Target, err)
+		}
+		return true, nil
+
+	case config.MailRuleEscalate:
+		msg.Priority = PriorityUrgent
+		if rule.Target != "" {
+			if err := r.Send(forwardCopy(rule, msg, rule.Target)); err != nil {
+				return false, fmt.Errorf("mail rule %s: escalating to %s: %w", rule.Name, rule.Target, err)
+			}
+		}
+		return false, nil
+
+	case config.MailRuleNudge:
+		return r.nudgeInstead(rule, msg), nil
+	}
+
+	fn := ruleActions[rule.Action]
+	if fn == nil {
+		return false, nil // not available in this process; deliver as usual
+	}
+	if err := fn(r.townRoot, identity, msg); err != nil {
+		fmt.Fprintf(os.Stderr, "mail rule %s: %s: %v (delivering unread)\n", rule.Name, rule.Action, err)
+		return false, nil
+	}
+	*labels = append(*labels, "read")
+	return false, nil
+}
+
+// forwardCopy returns a copy of msg addressed to target. Forwarded copies
+// skip rules so that rules can't forward mail in a loop.
+func forwardCopy(rule *config.MailRule, msg *Message, target string) *Message {
+	fwd := *msg
+	fwd.ID = ""
+	fwd.To = target
+	fwd.CC = nil
+	fwd.Body = fmt.Sprintf("[Forwarded from %s by mail rule %q]\n\n%s", msg.To, rule.Name, msg.Body)
+	fwd.skipRules = true
+	return &fwd
+}
+
+// nudgeInstead queues the message as a nudge to the recipient's live
+// session. It returns false, leaving the message to be delivered as mail,
+// when the recipient has no session, is muted, or is the overseer.
+func (r *Router) nudgeInstead(rule *config.MailRule, msg *Message) bool {
+	if r.townRoot == "" || msg.To == "overseer" || r.isRecipientMuted(msg.To) {
+		return false
+	}
+	text := fmt.Sprintf("📨 %s: %s", msg.From, msg.Subject)
+	if body := strings.Join(strings.Fields(msg.Body), " "); body != "" {
+		if runes := []rune(body); len(runes) > 280 {
+			body = string(runes[:280]) + "…"
+		}
+		text += " — " + body
+	}
+	for _, sessionID := range AddressToSessionIDs(msg.To) {
+		if ok, err := r.tmux.HasSession(sessionID); err != nil || !ok {
+			continue
+		}
+		err := nudge.Enqueue(r.townRoot, sessionID, nudge.QueuedNudge{
+			Sender:   msg.From,
+			Message:  text,
+			Priority: nudge.PriorityNormal,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mail rule %s: nudge failed: %v (delivering as mail)\n", rule.Name, err)
+			return false
+		}
+		return true
+	}
+	return false
+}
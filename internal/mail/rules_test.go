@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchRule(t *testing.T) {
+	rules := []config.MailRule{
+		{Name: "disabled", Match: config.MailRuleMatch{}, Action: config.MailRuleArchive, Disabled: true},
+		{Name: "patrol", Match: config.MailRuleMatch{From: "*/witness", Subject: "^patrol"}, Action: config.MailRuleArchive},
+		{Name: "low-tasks", Match: config.MailRuleMatch{Type: "task", Priority: "low"}, Action: config.MailRuleMarkRead},
+		{Name: "thread", Match: config.MailRuleMatch{Thread: "thread-1"}, Action: config.MailRuleNudge},
+		{Name: "labels", Match: config.MailRuleMatch{Labels: []string{"cc:mayor/"}}, Action: config.MailRuleEscalate},
+	}
+
+	tests := []struct {
+		name   string
+		msg    Message
+		labels []string
+		want   string
+	}{
+		{"from and subject", Message{From: "gastown/witness", Subject: "PATROL: clean"}, nil, "patrol"},
+		{"subject mismatch", Message{From: "gastown/witness", Subject: "HELP: stuck"}, nil, ""},
+		{"from mismatch", Message{From: "gastown/refinery", Subject: "PATROL: clean"}, nil, ""},
+		{"type and priority", Message{Type: TypeTask, Priority: PriorityLow}, nil, "low-tasks"},
+		{"priority mismatch", Message{Type: TypeTask, Priority: PriorityHigh}, nil, ""},
+		{"thread", Message{ThreadID: "thread-1"}, nil, "thread"},
+		{"labels", Message{}, []string{"gt:message", "cc:mayor/"}, "labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchRule(rules, &tt.msg, tt.labels)
+			name := ""
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("MatchRule() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesFor(t *testing.T) {
+	cfg := &config.MessagingConfig{Rules: map[string][]config.MailRule{
+		"*/witness":       {{Name: "all-witnesses"}},
+		"gastown/witness": {{Name: "own"}},
+		"mayor":           {{Name: "mayor"}},
+	}}
+
+	var names []string
+	for _, r := range RulesFor(cfg, "gastown/witness") {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "own,all-witnesses" {
+		t.Errorf("RulesFor(gastown/witness) = %s, want own,all-witnesses", got)
+	}
+
+	if got := RulesFor(cfg, "mayor/"); len(got) != 1 || got[0].Name != "mayor" {
+		t.Errorf("RulesFor(mayor/) = %+v, want the mayor rule", got)
+	}
+	if got := RulesFor(cfg, "deacon/"); len(got) != 0 {
+		t.Errorf("RulesFor(deacon/) = %+v, want none", got)
+	}
+}
+
+func TestApplyRule_MarkReadAndArchive(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{ID: "hq-1", From: "gastown/witness", To: "mayor/", Subject: "PATROL: clean"}
+
+	labels := []string{"gt:message"}
+	consumed, err := r.applyRule(&config.MailRule{Name: "read", Action: config.MailRuleMarkRead}, "mayor/", msg, &labels)
+	if err != nil || consumed {
+		t.Fatalf("mark-read: consumed=%v err=%v", consumed, err)
+	}
+	if labels[len(labels)-1] != "read" {
+		t.Errorf("mark-read labels = %v, want read added", labels)
+	}
+
+	consumed, err = r.applyRule(&config.MailRule{Name: "noise", Action: config.MailRuleArchive}, "mayor/", msg, &labels)
+	if err != nil || !consumed {
+		t.Fatalf("archive: consumed=%v err=%v", consumed, err)
+	}
+	mailbox, _ := r.GetMailbox("mayor/")
+	archived, err := mailbox.ListArchived()
+	if err != nil {
+		t.Fatalf("ListArchived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].Subject != msg.Subject || !archived[0].Read {
+		t.Errorf("archived = %+v", archived)
+	}
+}
+
+func TestApplyRule_RegisteredAction(t *testing.T) {
+	const action = "test-action"
+	var called string
+	RegisterRuleAction(action, func(townRoot, recipient string, msg *Message) error {
+		called = recipient
+		return nil
+	})
+	defer delete(ruleActions, action)
+
+	r := NewRouterWithTownRoot(t.TempDir(), "")
+	var labels []string
+	consumed, err := r.applyRule(&config.MailRule{Name: "x", Action: action}, "gastown/nux", &Message{}, &labels)
+	if err != nil || consumed {
+		t.Fatalf("consumed=%v err=%v", consumed, err)
+	}
+	if called != "gastown/nux" || len(labels) != 1 || labels[0] != "read" {
+		t.Errorf("called=%q labels=%v", called, labels)
+	}
+}
+
+func TestForwardCopy(t *testing.T) {
+	msg := &Message{ID: "hq-1", From: "gastown/witness", To: "mayor/", Body: "details", CC: []string{"deacon/"}}
+	fwd := forwardCopy(&config.MailRule{Name: "fwd"}, msg, "overseer")
+
+	if fwd.To != "overseer" || fwd.ID != "" || fwd.CC != nil || !fwd.skipRules {
+		t.Errorf("forwardCopy = %+v", fwd)
+	}
+	if !strings.Contains(fwd.Body, "Forwarded from mayor/") || !strings.HasSuffix(fwd.Body, "details") {
+		t.Errorf("forwarded body = %q", fwd.Body)
+	}
+	if msg.To != "mayor/" || msg.skipRules {
+		t.Error("forwardCopy modified the original")
+	}
+
+	// Forwarded copies are never ruled again
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	if r.matchRule("overseer", fwd, nil) != nil {
+		t.Error("matchRule applied rules to a forwarded copy")
+	}
+}
@@ -130,10 +130,20 @@ type Message struct {
 	// DeliveryAckedAt is when receipt was acknowledged.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
 
+	// Payload is optional typed data for handlers, alongside the Body.
+	Payload *Payload `json:"payload,omitempty"`
+
+	// Attachments reference files stored in the attachment store.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
 	SuppressNotify bool `json:"-"`
+
+	// skipRules bypasses the recipient's mail rules. Set on copies that
+	// rules forward, so rules can't forward mail in a loop.
+	skipRules bool
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -264,6 +274,11 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("claimed_at is only valid for queue messages")
 	}
 
+	// Payload and attachments within limits
+	if err := validateEnvelope(m); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -411,12 +426,18 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		ccAddrs = append(ccAddrs, identityToAddress(cc))
 	}
 
+	// Split the envelope (payload, attachments) off the description
+	body, env := decodeDescription(bm.Description)
+	if env == nil {
+		env = &envelope{}
+	}
+
 	return &Message{
 		ID:              bm.ID,
 		From:            identityToAddress(bm.sender),
 		To:              identityToAddress(bm.Assignee),
 		Subject:         bm.Title,
-		Body:            bm.Description,
+		Body:            body,
 		Timestamp:       bm.CreatedAt,
 		Read:            bm.Status == "closed" || bm.HasLabel("read"),
 		Priority:        priority,
@@ -432,6 +453,8 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		DeliveryState:   bm.deliveryState,
 		DeliveryAckedBy: bm.deliveryAckedBy,
 		DeliveryAckedAt: bm.deliveryAckedAt,
+		Payload:         env.Payload,
+		Attachments:     env.Attachments,
 	}
 }
 
@@ -0,0 +1,149 @@
+package mailbridge
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	netmail "net/mail"
+	"regexp"
+	"strings"
+)
+
+// relayTokenLen is the number of hex characters of the Message-ID token.
+const relayTokenLen = 20
+
+// newBridgeSecret returns a random secret for signing relayed Message-IDs.
+func newBridgeSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// relayToken returns the token embedded in the Message-ID of relayed mail:
+// an HMAC of the mail ID with the bridge secret, so Message-IDs can't be
+// guessed from mail IDs to forge replies into a thread.
+func relayToken(secret, mailID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(mailID))
+	return hex.EncodeToString(mac.Sum(nil))[:relayTokenLen]
+}
+
+// validRelayToken reports whether messageID carries the token for mailID.
+func validRelayToken(secret, mailID, messageID string) bool {
+	local, _, ok := strings.Cut(strings.Trim(messageID, "<>"), "@")
+	if !ok {
+		return false
+	}
+	i := strings.LastIndexByte(local, '.')
+	if i < 0 || secret == "" {
+		return false
+	}
+	return hmac.Equal([]byte(local[i+1:]), []byte(relayToken(secret, mailID)))
+}
+
+// authResult is one method result from an Authentication-Results header
+// (RFC 8601), e.g. "dkim=pass header.d=example.com".
+type authResult struct {
+	method string
+	result string
+	props  map[string]string
+}
+
+var authComment = regexp.MustCompile(`\([^()]*\)`)
+
+// parseAuthResults splits an Authentication-Results value into its
+// authserv-id and method results.
+func parseAuthResults(v string) (string, []authResult) {
+	parts := strings.Split(authComment.ReplaceAllString(v, " "), ";")
+	head := strings.Fields(parts[0])
+	if len(head) == 0 {
+		return "", nil
+	}
+	var results []authResult
+	for _, part := range parts[1:] {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		method, result, ok := strings.Cut(fields[0], "=")
+		if !ok {
+			continue
+		}
+		method, _, _ = strings.Cut(method, "/")
+		r := authResult{
+			method: strings.ToLower(method),
+			result: strings.ToLower(result),
+			props:  make(map[string]string),
+		}
+		for _, f := range fields[1:] {
+			if k, val, ok := strings.Cut(f, "="); ok {
+				r.props[strings.ToLower(k)] = strings.Trim(val, `"`)
+			}
+		}
+		results = append(results, r)
+	}
+	return head[0], results
+}
+
+// senderAuthenticated reports whether the receiving server authenticated
+// the From domain of an email: DMARC pass, or DKIM or SPF pass for an
+// aligned domain. Only Authentication-Results headers from authServID are
+// considered, or just the topmost one (added by the last hop) if it is
+// empty; headers further down may have been written by the sender.
+func senderAuthenticated(h netmail.Header, fromAddress, authServID string) bool {
+	_, fromDomain, ok := strings.Cut(strings.ToLower(fromAddress), "@")
+	if !ok || fromDomain == "" {
+		return false
+	}
+
+	headers := h["Authentication-Results"]
+	if authServID == "" && len(headers) > 1 {
+		headers = headers[:1]
+	}
+	for _, v := range headers {
+		servID, results := parseAuthResults(v)
+		if authServID != "" && !strings.EqualFold(servID, authServID) {
+			continue
+		}
+		for _, r := range results {
+			if r.result != "pass" {
+				continue
+			}
+			var domain string
+			switch r.method {
+			case "dmarc":
+				domain = r.props["header.from"]
+			case "dkim":
+				domain = r.props["header.d"]
+				if domain == "" {
+					domain = addressDomain(r.props["header.i"])
+				}
+			case "spf":
+				domain = addressDomain(r.props["smtp.mailfrom"])
+			}
+			if alignedDomain(strings.ToLower(domain), fromDomain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addressDomain returns the domain of an address, or the value itself if
+// it has no local part.
+func addressDomain(v string) string {
+	if _, d, ok := strings.Cut(v, "@"); ok {
+		return d
+	}
+	return v
+}
+
+// alignedDomain reports relaxed alignment: the From domain is the
+// authenticated domain or one of its subdomains.
+func alignedDomain(authenticated, from string) bool {
+	if authenticated == "" {
+		return false
+	}
+	return from == authenticated || strings.HasSuffix(from, "."+authenticated)
+}
@@ -0,0 +1,480 @@
+// Package mailbridge relays Gas Town mail to an external email inbox and
+// delivers email replies back into their mail threads.
+//
+// Outbound, unread mail in the bridged mailboxes (by default the overseer's)
+// is sent over SMTP, once per message. Inbound, the bridge polls an IMAP
+// mailbox for replies, matches each to the relayed mail through its
+// In-Reply-To or References headers, and sends the reply text as mail from
+// the bridged mailbox to the original sender, in the original thread. Only
+// replies from allow-listed addresses whose domain the receiving server
+// authenticated (DKIM, SPF or DMARC) are delivered, and only to threads
+// whose signed Message-ID they reference.
+package mailbridge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	netmail "net/mail"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
+)
+
+// ErrNotEnabled is returned when the town has no enabled mail bridge.
+var ErrNotEnabled = errors.New("mail bridge not enabled")
+
+// defaultTimeout bounds each SMTP and IMAP conversation.
+const defaultTimeout = 30 * time.Second
+
+// Mailer is the bridge's view of the town's mail.
+type Mailer interface {
+	ListUnread(address string) ([]*mail.Message, error)
+	Send(msg *mail.Message) error
+	MarkRead(address, id string) error
+}
+
+// routerMailer implements Mailer with a mail.Router.
+type routerMailer struct {
+	router *mail.Router
+}
+
+func (m routerMailer) ListUnread(address string) ([]*mail.Message, error) {
+	mailbox, err := m.router.GetMailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	return mailbox.ListUnread()
+}
+
+func (m routerMailer) Send(msg *mail.Message) error {
+	return m.router.Send(msg)
+}
+
+func (m routerMailer) MarkRead(address, id string) error {
+	mailbox, err := m.router.GetMailbox(address)
+	if err != nil {
+		return err
+	}
+	return mailbox.MarkReadOnly(id)
+}
+
+// Bridge relays mail between a town and an external inbox.
+type Bridge struct {
+	townRoot  string
+	cfg       *config.MailBridgeConfig
+	mailer    Mailer
+	mailboxes []string
+	emailTo   string
+	allowFrom map[string]bool
+
+	// Timeout bounds each SMTP and IMAP conversation (default 30s).
+	Timeout time.Duration
+
+	now func() time.Time
+}
+
+// Result summarizes one Sync.
+type Result struct {
+	Relayed  []string `json:"relayed,omitempty"`  // mail IDs sent as email
+	Replies  []string `json:"replies,omitempty"`  // mail IDs created from email replies
+	Rejected []string `json:"rejected,omitempty"` // emails not delivered, with the reason
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// New creates a bridge for cfg. mailboxes are the resolved mailbox
+// addresses to relay and emailTo the inbox to relay them to.
+func New(townRoot string, cfg *config.MailBridgeConfig, mailer Mailer, mailboxes []string, emailTo string) *Bridge {
+	allow := cfg.AllowFrom
+	if len(allow) == 0 {
+		allow = []string{emailTo}
+	}
+	allowFrom := make(map[string]bool, len(allow))
+	for _, addr := range allow {
+		allowFrom[strings.ToLower(strings.TrimSpace(addr))] = true
+	}
+	return &Bridge{
+		townRoot:  townRoot,
+		cfg:       cfg,
+		mailer:    mailer,
+		mailboxes: mailboxes,
+		emailTo:   emailTo,
+		allowFrom: allowFrom,
+		now:       time.Now,
+	}
+}
+
+// NewFromTown creates the bridge configured in the town's
+// config/messaging.json, expanding @group and list: entries to mailboxes.
+// It returns ErrNotEnabled when no bridge is enabled.
+func NewFromTown(townRoot string) (*Bridge, error) {
+	msgCfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, ErrNotEnabled
+		}
+		return nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	cfg := msgCfg.Bridge
+	if cfg == nil || !cfg.Enabled {
+		return nil, ErrNotEnabled
+	}
+
+	emailTo := cfg.EmailTo
+	if emailTo == "" {
+		if overseer, err := config.LoadOverseerConfig(config.OverseerConfigPath(townRoot)); err == nil {
+			emailTo = overseer.Email
+		}
+	}
+	if emailTo == "" {
+		return nil, fmt.Errorf("mail bridge: no email_to configured and the overseer has no email")
+	}
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	mailboxes, err := resolveMailboxes(router, cfg.Mailboxes)
+	if err != nil {
+		return nil, err
+	}
+	return New(townRoot, cfg, routerMailer{router: router}, mailboxes, emailTo), nil
+}
+
+// resolveMailboxes expands the configured entries to mailbox addresses.
+func resolveMailboxes(router *mail.Router, entries []string) ([]string, error) {
+	if len(entries) == 0 {
+		return []string{"overseer"}, nil
+	}
+	seen := make(map[string]bool)
+	var mailboxes []string
+	for _, entry := range entries {
+		addrs := []string{entry}
+		var err error
+		switch {
+		case strings.HasPrefix(entry, "list:"):
+			addrs, err = router.ExpandListAddress(entry)
+		case strings.HasPrefix(entry, "@"):
+			addrs, err = router.ResolveGroupAddress(entry)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mail bridge: resolving %s: %w", entry, err)
+		}
+		for _, addr := range addrs {
+			if !seen[addr] {
+				seen[addr] = true
+				mailboxes = append(mailboxes, addr)
+			}
+		}
+	}
+	return mailboxes, nil
+}
+
+// Mailboxes returns the mailbox addresses the bridge relays.
+func (b *Bridge) Mailboxes() []string { return b.mailboxes }
+
+// EmailTo returns the inbox relayed mail is sent to.
+func (b *Bridge) EmailTo() string { return b.emailTo }
+
+// Sync relays new mail and ingests new replies. Failures with single
+// messages are reported in the result; the returned error is reserved for
+// state that could not be loaded or saved.
+func (b *Bridge) Sync(ctx context.Context) (*Result, error) {
+	unlock, err := lockState(b.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := LoadState(b.townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	b.relay(ctx, state, res)
+	if b.cfg.IMAP != nil {
+		if err := b.ingest(ctx, state, res); err != nil {
+			res.Errors = append(res.Errors, err.Error())
+		}
+	}
+
+	state.LastSync = b.now()
+	if err := state.save(b.townRoot, b.now()); err != nil {
+		return res, fmt.Errorf("saving mail bridge state: %w", err)
+	}
+	return res, nil
+}
+
+func (b *Bridge) timeout() time.Duration {
+	if b.Timeout > 0 {
+		return b.Timeout
+	}
+	return defaultTimeout
+}
+
+// relay sends unread, not yet relayed mail from the bridged mailboxes.
+// Relaying stops at the first SMTP failure; the rest is retried next sync.
+func (b *Bridge) relay(ctx context.Context, state *State, res *Result) {
+	smtp := &notify.SMTPNotifier{
+		Host:     b.cfg.SMTP.Host,
+		Port:     b.cfg.SMTP.Port,
+		From:     b.cfg.SMTP.From,
+		To:       b.emailTo,
+		Username: b.cfg.SMTP.Username,
+		Password: envOrEmpty(b.cfg.SMTP.PasswordEnv),
+		Timeout:  b.timeout(),
+	}
+
+	for _, mailbox := range b.mailboxes {
+		msgs, err := b.mailer.ListUnread(mailbox)
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("listing %s: %v", mailbox, err))
+			continue
+		}
+		sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp.Before(msgs[j].Timestamp) })
+
+		for _, msg := range msgs {
+			if state.Relayed[msg.ID] != nil {
+				continue
+			}
+			record := &RelayedMessage{
+				MessageID: b.messageID(msg.ID, state.Secret),
+				Mailbox:   mailbox,
+				From:      msg.From,
+				Subject:   msg.Subject,
+				ThreadID:  msg.ThreadID,
+				RelayedAt: b.now(),
+			}
+			if err := smtp.Send(ctx, b.formatEmail(msg, record, state)); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("relaying %s: %v", msg.ID, err))
+				return
+			}
+			state.Relayed[msg.ID] = record
+			res.Relayed = append(res.Relayed, msg.ID)
+		}
+	}
+}
+
+// messageID returns the RFC 5322 Message-ID for relayed mail, in the
+// domain of the bridge's sender address. It carries a token signed with
+// the bridge secret, verified when a reply references it.
+func (b *Bridge) messageID(mailID, secret string) string {
+	domain := "gastown.local"
+	if _, d, ok := strings.Cut(b.cfg.SMTP.From, "@"); ok && d != "" {
+		domain = strings.Trim(d, "<> ")
+	}
+	return fmt.Sprintf("<gt.%s.%s@%s>", mailID, relayToken(secret, mailID), domain)
+}
+
+// formatEmail builds the email for relayed mail. When the mail replies to
+// mail relayed earlier, the email threads under it in the inbox as well.
+func (b *Bridge) formatEmail(msg *mail.Message, record *RelayedMessage, state *State) string {
+	var h strings.Builder
+	fmt.Fprintf(&h, "From: %s\r\n", b.cfg.SMTP.From)
+	fmt.Fprintf(&h, "To: %s\r\n", b.emailTo)
+	fmt.Fprintf(&h, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notify.SanitizeHeader("[gt] "+msg.Subject)))
+	fmt.Fprintf(&h, "Date: %s\r\n", b.now().Format(time.RFC1123Z))
+	fmt.Fprintf(&h, "Message-ID: %s\r\n", record.MessageID)
+	if parent := state.Relayed[msg.ReplyTo]; msg.ReplyTo != "" && parent != nil {
+		fmt.Fprintf(&h, "In-Reply-To: %s\r\n", parent.MessageID)
+		fmt.Fprintf(&h, "References: %s\r\n", parent.MessageID)
+	}
+	fmt.Fprintf(&h, "X-Gastown-Mail: %s\r\n", notify.SanitizeHeader(msg.ID))
+	if msg.ThreadID != "" {
+		fmt.Fprintf(&h, "X-Gastown-Thread: %s\r\n", notify.SanitizeHeader(msg.ThreadID))
+	}
+	fmt.Fprintf(&h, "X-Gastown-Mailbox: %s\r\n", notify.SanitizeHeader(record.Mailbox))
+	if msg.Priority == mail.PriorityUrgent {
+		h.WriteString("Importance: high\r\n")
+	}
+	h.WriteString("MIME-Version: 1.0\r\n")
+	h.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	h.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	h.WriteString("\r\n")
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\nTo: %s\n", msg.From, record.Mailbox)
+	if msg.Priority != "" && msg.Priority != mail.PriorityNormal {
+		fmt.Fprintf(&body, "Priority: %s\n", msg.Priority)
+	}
+	fmt.Fprintf(&body, "\n%s\n\n-- \nReply to this email to answer %s in Gas Town.\n", msg.Body, msg.From)
+
+	return h.String() + strings.ReplaceAll(strings.ReplaceAll(body.String(), "\r\n", "\n"), "\n", "\r\n")
+}
+
+// ingest reads new messages from the IMAP mailbox and delivers replies.
+// The mailbox position advances past every message handled, so a failed
+// delivery stops ingestion and is retried next sync.
+func (b *Bridge) ingest(ctx context.Context, state *State, res *Result) error {
+	cfg := b.cfg.IMAP
+	c, err := dialIMAP(ctx, cfg, b.timeout())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := c.login(cfg.Username, envOrEmpty(cfg.PasswordEnv)); err != nil {
+		return err
+	}
+	folder := cfg.Mailbox
+	if folder == "" {
+		folder = "INBOX"
+	}
+	status, err := c.selectMailbox(folder)
+	if err != nil {
+		return err
+	}
+	if status.UIDValidity == 0 {
+		return fmt.Errorf("IMAP server reported no UIDVALIDITY for %s", folder)
+	}
+	if status.UIDValidity != state.UIDValidity {
+		// First sync, or UIDs were reassigned: start from the current end
+		// of the mailbox. Existing mail is never ingested as new replies.
+		last, err := c.highestUID()
+		if status.UIDNext > 0 {
+			last, err = status.UIDNext-1, nil
+		}
+		if err != nil {
+			return err
+		}
+		state.UIDValidity = status.UIDValidity
+		state.LastUID = last
+		return c.logout()
+	}
+
+	uids, err := c.searchUIDsAfter(state.LastUID)
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		raw, err := c.fetch(uid)
+		if err != nil {
+			return err
+		}
+		delivered, reason, err := b.ingestOne(raw, state, res)
+		if err != nil {
+			return fmt.Errorf("delivering reply (uid %d): %w", uid, err)
+		}
+		if reason != "" {
+			res.Rejected = append(res.Rejected, fmt.Sprintf("uid %d: %s", uid, reason))
+		}
+		if delivered {
+			if err := c.markSeen(uid); err != nil {
+				res.Errors = append(res.Errors, err.Error())
+			}
+		}
+		state.LastUID = uid
+	}
+	return c.logout()
+}
+
+// ingestOne delivers one email if it is an allowed reply to relayed mail.
+// It returns a reason when the email is not delivered, and an error only
+// when delivery itself failed.
+func (b *Bridge) ingestOne(raw []byte, state *State, res *Result) (bool, string, error) {
+	m, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return false, "unparseable message", nil
+	}
+	emailID := strings.TrimSpace(m.Header.Get("Message-Id"))
+	if emailID != "" {
+		if _, ok := state.Ingested[emailID]; ok {
+			return false, "", nil
+		}
+	}
+
+	from, err := netmail.ParseAddress(m.Header.Get("From"))
+	if err != nil {
+		return false, "no valid From address", nil
+	}
+	if !b.allowFrom[strings.ToLower(from.Address)] {
+		return false, fmt.Sprintf("sender %s is not allowed", from.Address), nil
+	}
+	// The From header alone proves nothing; require the receiving server
+	// to have authenticated its domain.
+	if !b.cfg.TrustFromHeader && !senderAuthenticated(m.Header, from.Address, b.cfg.IMAP.AuthServID) {
+		return false, fmt.Sprintf("sender %s is not authenticated (no aligned DKIM/SPF/DMARC pass)", from.Address), nil
+	}
+
+	mailID, relayed := b.findRelayed(m.Header, state)
+	if relayed == nil {
+		return false, fmt.Sprintf("from %s is not a reply to relayed mail", from.Address), nil
+	}
+
+	text, err := replyText(m)
+	if err != nil {
+		return false, err.Error(), nil
+	}
+	if text == "" {
+		return false, fmt.Sprintf("reply from %s is empty", from.Address), nil
+	}
+
+	original := &mail.Message{ID: mailID, ThreadID: relayed.ThreadID}
+	reply := mail.NewReplyMessage(relayed.Mailbox, relayed.From, replySubject(relayed.Subject), text, original)
+	if err := b.mailer.Send(reply); err != nil {
+		return false, "", err
+	}
+	if err := b.mailer.MarkRead(relayed.Mailbox, mailID); err != nil {
+		res.Errors = append(res.Errors, fmt.Sprintf("marking %s read: %v", mailID, err))
+	}
+
+	if emailID != "" {
+		state.Ingested[emailID] = b.now()
+	}
+	res.Replies = append(res.Replies, reply.ID)
+	return true, "", nil
+}
+
+// findRelayed returns the relayed mail an email replies to, checking
+// In-Reply-To first and then References from newest to oldest. Only
+// Message-IDs carrying a valid token match.
+func (b *Bridge) findRelayed(h netmail.Header, state *State) (string, *RelayedMessage) {
+	refs := messageIDs(h.Get("In-Reply-To"))
+	references := messageIDs(h.Get("References"))
+	for i := len(references) - 1; i >= 0; i-- {
+		refs = append(refs, references[i])
+	}
+	for _, ref := range refs {
+		if id, r := state.relayedByMessageID(ref); r != nil && validRelayToken(state.Secret, id, ref) {
+			return id, r
+		}
+	}
+	return "", nil
+}
+
+// messageIDs extracts the <...> message IDs from a header value.
+func messageIDs(v string) []string {
+	var ids []string
+	for {
+		start := strings.IndexByte(v, '<')
+		if start < 0 {
+			return ids
+		}
+		end := strings.IndexByte(v[start:], '>')
+		if end < 0 {
+			return ids
+		}
+		ids = append(ids, v[start:start+end+1])
+		v = v[start+end+1:]
+	}
+}
+
+// replySubject prefixes subject with "Re: " unless it already has one.
+func replySubject(subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
+
+func envOrEmpty(name string) string {
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
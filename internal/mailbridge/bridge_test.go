@@ -0,0 +1,441 @@
+package mailbridge
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// fakeMailer is an in-memory Mailer.
+type fakeMailer struct {
+	unread map[string][]*mail.Message
+	sent   []*mail.Message
+	read   []string
+}
+
+func (m *fakeMailer) ListUnread(address string) ([]*mail.Message, error) {
+	return m.unread[address], nil
+}
+
+func (m *fakeMailer) Send(msg *mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *fakeMailer) MarkRead(address, id string) error {
+	m.read = append(m.read, address+":"+id)
+	return nil
+}
+
+// smtpStandIn is a local SMTP server that records the messages it receives.
+type smtpStandIn struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages []string
+}
+
+func startSMTP(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStandIn{ln: ln}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, _, _ := strings.Cut(strings.ToUpper(line), " ")
+		switch verb {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *smtpStandIn) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+// imapStandIn is a local IMAP server holding messages by UID.
+type imapStandIn struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages map[uint32]string
+	seen     map[uint32]bool
+}
+
+func startIMAP(t *testing.T) *imapStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &imapStandIn{ln: ln, messages: make(map[uint32]string), seen: make(map[uint32]bool)}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *imapStandIn) add(uid uint32, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[uid] = strings.ReplaceAll(msg, "\n", "\r\n")
+}
+
+func (s *imapStandIn) isSeen(uid uint32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[uid]
+}
+
+func (s *imapStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return
+		}
+		tag, cmd := fields[0], strings.ToUpper(fields[1])
+		if cmd == "UID" && len(fields) > 3 {
+			cmd += " " + strings.ToUpper(fields[2])
+		}
+
+		s.mu.Lock()
+		switch cmd {
+		case "SELECT":
+			var highest uint32
+			for uid := range s.messages {
+				highest = max(highest, uid)
+			}
+			fmt.Fprintf(conn, "* %d EXISTS\r\n* OK [UIDVALIDITY 7] UIDs valid\r\n* OK [UIDNEXT %d] next\r\n", len(s.messages), highest+1)
+		case "UID SEARCH":
+			from, _ := strconv.Atoi(strings.TrimSuffix(fields[4], ":*"))
+			var uids []string
+			var highest uint32
+			for uid := range s.messages {
+				if int(uid) >= from {
+					uids = append(uids, strconv.Itoa(int(uid)))
+				}
+				highest = max(highest, uid)
+			}
+			if len(uids) == 0 && highest > 0 {
+				uids = append(uids, strconv.Itoa(int(highest))) // "n:*" quirk
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case "UID FETCH":
+			uid, _ := strconv.Atoi(fields[3])
+			if msg, ok := s.messages[uint32(uid)]; ok {
+				fmt.Fprintf(conn, "* 1 FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, len(msg), msg)
+			}
+		case "UID STORE":
+			uid, _ := strconv.Atoi(fields[3])
+			s.seen[uint32(uid)] = true
+		case "LOGOUT":
+			fmt.Fprint(conn, "* BYE\r\n")
+		}
+		s.mu.Unlock()
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func port(t *testing.T, ln net.Listener) int {
+	t.Helper()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestBridge_RelayAndIngest(t *testing.T) {
+	smtpServer := startSMTP(t)
+	imapServer := startIMAP(t)
+
+	cfg := &config.MailBridgeConfig{
+		Enabled: true,
+		SMTP:    &config.SMTPConfig{Host: "127.0.0.1", Port: port(t, smtpServer.ln), From: "gastown@example.com"},
+		IMAP:    &config.IMAPConfig{Host: "127.0.0.1", Port: port(t, imapServer.ln), Username: "gastown", Insecure: true},
+	}
+	mailer := &fakeMailer{unread: map[string][]*mail.Message{
+		"overseer": {{ID: "hq-1", From: "gastown/witness", To: "overseer", Subject: "HELP: nux stuck", Body: "nux has been idle for 2h", ThreadID: "thread-1", Priority: mail.PriorityHigh}},
+	}}
+	townRoot := t.TempDir()
+	b := New(townRoot, cfg, mailer, []string{"overseer"}, "me@example.com")
+	ctx := context.Background()
+
+	// Outbound: unread mail is relayed once
+	res, err := b.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(res.Relayed) != 1 || len(res.Errors) != 0 {
+		t.Fatalf("first sync = %+v", res)
+	}
+	sent := smtpServer.received()
+	if len(sent) != 1 {
+		t.Fatalf("SMTP received %d messages, want 1", len(sent))
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgID := state.Relayed["hq-1"].MessageID
+	if !strings.HasPrefix(msgID, "<gt.hq-1.") || !strings.HasSuffix(msgID, "@example.com>") {
+		t.Errorf("Message-ID = %s, want a signed <gt.hq-1.<token>@example.com>", msgID)
+	}
+	for _, want := range []string{
+		"Message-ID: " + msgID,
+		"Subject: [gt] HELP: nux stuck",
+		"X-Gastown-Thread: thread-1",
+		"From: gastown/witness",
+		"Priority: high",
+		"nux has been idle for 2h",
+	} {
+		if !strings.Contains(sent[0], want) {
+			t.Errorf("relayed email missing %q:\n%s", want, sent[0])
+		}
+	}
+
+	res, err = b.Sync(ctx)
+	if err != nil || len(res.Relayed) != 0 {
+		t.Fatalf("second sync relayed again: %+v, %v", res, err)
+	}
+
+	// Inbound: an allowed, authenticated reply is delivered into the
+	// thread; others are not
+	const authOK = "Authentication-Results: mx.example.com; dkim=pass header.d=example.com; spf=pass smtp.mailfrom=me@example.com\n"
+	imapServer.add(1, authOK+"From: Me <me@example.com>\nTo: gastown@example.com\nSubject: Re: [gt] HELP: nux stuck\n"+
+		"Message-ID: <r1@example.com>\nIn-Reply-To: "+msgID+"\n\n"+
+		"Restart it with gt polecat nuke.\n\nOn Mon, 5 Jan 2026, gastown@example.com wrote:\n> nux has been idle for 2h\n")
+	imapServer.add(2, "From: mallory@evil.example\nSubject: Re: [gt] HELP\nMessage-ID: <r2@evil.example>\n"+
+		"In-Reply-To: "+msgID+"\n\nrm -rf everything\n")
+	imapServer.add(3, authOK+"From: me@example.com\nSubject: hello\nMessage-ID: <r3@example.com>\n\nnot a reply\n")
+	// Spoofed From: the receiving server did not authenticate example.com
+	imapServer.add(4, "Authentication-Results: mx.example.com; dkim=fail header.d=example.com; spf=pass smtp.mailfrom=evil.example\n"+
+		"From: me@example.com\nSubject: Re: [gt] HELP\nMessage-ID: <r4@evil.example>\nIn-Reply-To: "+msgID+"\n\nspoofed\n")
+	// Guessed Message-ID without the bridge's token
+	imapServer.add(5, authOK+"From: me@example.com\nSubject: Re: [gt] HELP\nMessage-ID: <r5@example.com>\n"+
+		"In-Reply-To: <gt.hq-1@example.com>\n\nforged thread\n")
+
+	res, err = b.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(res.Replies) != 1 || len(res.Rejected) != 4 || len(res.Errors) != 0 {
+		t.Fatalf("ingest sync = %+v", res)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("delivered %d replies, want 1", len(mailer.sent))
+	}
+	reply := mailer.sent[0]
+	if reply.From != "overseer" || reply.To != "gastown/witness" || reply.ThreadID != "thread-1" || reply.ReplyTo != "hq-1" {
+		t.Errorf("reply routing = from %s to %s thread %s reply-to %s", reply.From, reply.To, reply.ThreadID, reply.ReplyTo)
+	}
+	if reply.Subject != "Re: HELP: nux stuck" || reply.Body != "Restart it with gt polecat nuke." {
+		t.Errorf("reply = %q / %q", reply.Subject, reply.Body)
+	}
+	if !imapServer.isSeen(1) || imapServer.isSeen(2) {
+		t.Error("only the delivered reply should be marked seen")
+	}
+	if len(mailer.read) != 1 || mailer.read[0] != "overseer:hq-1" {
+		t.Errorf("marked read = %v, want overseer:hq-1", mailer.read)
+	}
+
+	// Nothing new: the mailbox position was saved
+	res, err = b.Sync(ctx)
+	if err != nil || len(res.Replies) != 0 || len(res.Rejected) != 0 {
+		t.Fatalf("final sync = %+v, %v", res, err)
+	}
+
+	state, err = LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.UIDValidity != 7 || state.LastUID != 5 || state.Relayed["hq-1"] == nil {
+		t.Errorf("state = %+v", state)
+	}
+}
+
+func TestBridge_FirstSyncSkipsExistingMail(t *testing.T) {
+	smtpServer := startSMTP(t)
+	imapServer := startIMAP(t)
+	imapServer.add(1, "From: me@example.com\nSubject: old\nMessage-ID: <old@example.com>\n\nold mail\n")
+	imapServer.add(2, "From: me@example.com\nSubject: older\nMessage-ID: <older@example.com>\n\nold mail\n")
+
+	cfg := &config.MailBridgeConfig{
+		Enabled:         true,
+		SMTP:            &config.SMTPConfig{Host: "127.0.0.1", Port: port(t, smtpServer.ln), From: "gastown@example.com"},
+		IMAP:            &config.IMAPConfig{Host: "127.0.0.1", Port: port(t, imapServer.ln), Username: "gastown", Insecure: true},
+		TrustFromHeader: true,
+	}
+	townRoot := t.TempDir()
+	b := New(townRoot, cfg, &fakeMailer{}, []string{"overseer"}, "me@example.com")
+
+	res, err := b.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Replies) != 0 || len(res.Rejected) != 0 {
+		t.Errorf("first sync ingested existing mail: %+v", res)
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.UIDValidity != 7 || state.LastUID != 2 {
+		t.Errorf("state = validity %d, last uid %d; want seeded to 7/2", state.UIDValidity, state.LastUID)
+	}
+}
+
+func TestSenderAuthenticated(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    []string
+		authServID string
+		want       bool
+	}{
+		{"dkim pass", []string{"mx.example.com; dkim=pass header.d=example.com"}, "", true},
+		{"dkim subdomain", []string{"mx.example.com; dkim=pass (good sig) header.i=@example.com"}, "", true},
+		{"dkim other domain", []string{"mx.example.com; dkim=pass header.d=evil.example"}, "", false},
+		{"spf aligned", []string{"mx.example.com; spf=pass smtp.mailfrom=me@example.com"}, "", true},
+		{"dmarc pass", []string{"mx.example.com 1; dmarc=pass header.from=example.com"}, "", true},
+		{"all fail", []string{"mx.example.com; dkim=fail header.d=example.com; spf=softfail smtp.mailfrom=example.com"}, "", false},
+		{"none", nil, "", false},
+		{"forged header below the receiver's", []string{"mx.example.com; dkim=fail header.d=example.com", "mx.example.com; dkim=pass header.d=example.com"}, "", false},
+		{"other authserv-id ignored", []string{"evil.example; dkim=pass header.d=example.com"}, "mx.example.com", false},
+		{"configured authserv-id", []string{"evil.example; dkim=fail", "MX.example.com; dkim=pass header.d=example.com"}, "mx.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := netmail.Header{}
+			if tt.headers != nil {
+				h["Authentication-Results"] = tt.headers
+			}
+			if got := senderAuthenticated(h, "me@example.com", tt.authServID); got != tt.want {
+				t.Errorf("senderAuthenticated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidRelayToken(t *testing.T) {
+	b := New(t.TempDir(), &config.MailBridgeConfig{SMTP: &config.SMTPConfig{From: "gt@example.com"}}, &fakeMailer{}, nil, "me@example.com")
+	id := b.messageID("hq-1.x", "secret")
+	if !validRelayToken("secret", "hq-1.x", id) {
+		t.Errorf("token of %s not valid", id)
+	}
+	for _, forged := range []string{"<gt.hq-1.x@example.com>", b.messageID("hq-1.x", "other"), b.messageID("hq-2", "secret")} {
+		if validRelayToken("secret", "hq-1.x", forged) {
+			t.Errorf("forged Message-ID %s accepted", forged)
+		}
+	}
+}
+
+func TestStripQuoted(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Sounds good.\n", "Sounds good."},
+		{"quote marks", "Yes.\n\n> original\n> text", "Yes."},
+		{"attribution", "Yes.\r\n\r\nOn Tue, Jan 6, 2026 at 9:00 AM Gas Town <gt@example.com> wrote:\r\n> x", "Yes."},
+		{"wrapped attribution", "Yes.\nOn Tue, Jan 6, 2026 at 9:00 AM Gas Town <gt@example.com>\nwrote:\n", "Yes."},
+		{"signature", "Yes.\n-- \nMe", "Yes."},
+		{"outlook", "Yes.\n-----Original Message-----\nFrom: x", "Yes."},
+		{"only quote", "> original", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripQuoted(tt.text); got != tt.want {
+				t.Errorf("stripQuoted() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText_Multipart(t *testing.T) {
+	raw := "Content-Type: multipart/alternative; boundary=b1\r\n\r\n" +
+		"--b1\r\nContent-Type: text/html\r\n\r\n<p>html</p>\r\n" +
+		"--b1\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: base64\r\n\r\n" +
+		"UGxhaW4gcmVwbHk=\r\n--b1--\r\n"
+	tp := textproto.NewReader(bufio.NewReader(strings.NewReader(raw)))
+	h, err := tp.ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := plainText(h, tp.R, 0)
+	if err != nil {
+		t.Fatalf("plainText: %v", err)
+	}
+	if got != "Plain reply" {
+		t.Errorf("plainText = %q, want %q", got, "Plain reply")
+	}
+
+	if _, err := plainText(textproto.MIMEHeader{"Content-Type": {"text/html"}}, strings.NewReader("<p>x</p>"), 0); err != errNoTextPart {
+		t.Errorf("html-only body: err = %v, want errNoTextPart", err)
+	}
+}
+
+func TestMessageIDs(t *testing.T) {
+	got := messageIDs("<a@x> <b@y>\r\n <c@z>")
+	if strings.Join(got, ",") != "<a@x>,<b@y>,<c@z>" {
+		t.Errorf("messageIDs = %v", got)
+	}
+}
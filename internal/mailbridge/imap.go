@@ -0,0 +1,275 @@
+package mailbridge
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// imapClient is a minimal IMAP4rev1 client covering what the bridge needs:
+// LOGIN, SELECT, UID SEARCH, UID FETCH and UID STORE. It is not a general
+// purpose client; responses it does not need are read and ignored.
+type imapClient struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	tag     int
+	timeout time.Duration // per command, so long syncs don't run out of time
+}
+
+// imapLine is one untagged response line with any literals it carried.
+type imapLine struct {
+	Text     string
+	Literals [][]byte
+}
+
+// maxIMAPLiteral bounds a single literal (e.g. one fetched email).
+const maxIMAPLiteral = 32 << 20
+
+// dialIMAP connects to the configured server and reads the greeting.
+// TLS is used unless cfg.Insecure is set.
+func dialIMAP(ctx context.Context, cfg *config.IMAPConfig, timeout time.Duration) (*imapClient, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 993
+		if cfg.Insecure {
+			port = 143
+		}
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to IMAP server %s: %w", addr, err)
+	}
+	if !cfg.Insecure {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("IMAP TLS handshake with %s: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+	greeting, err := c.readLine()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("reading IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", greeting)
+	}
+	return c, nil
+}
+
+// Close closes the connection without logging out.
+func (c *imapClient) Close() error {
+	return c.conn.Close()
+}
+
+// login authenticates with LOGIN.
+func (c *imapClient) login(username, password string) error {
+	_, err := c.command("LOGIN " + imapQuote(username) + " " + imapQuote(password))
+	if err != nil {
+		return fmt.Errorf("IMAP LOGIN: %w", err)
+	}
+	return nil
+}
+
+// mailboxStatus is what SELECT reports about the mailbox position.
+type mailboxStatus struct {
+	UIDValidity uint32
+	UIDNext     uint32 // 0 if the server did not report it
+}
+
+// selectMailbox opens a mailbox and returns its UIDVALIDITY and UIDNEXT.
+func (c *imapClient) selectMailbox(name string) (mailboxStatus, error) {
+	var st mailboxStatus
+	lines, err := c.command("SELECT " + imapQuote(name))
+	if err != nil {
+		return st, fmt.Errorf("IMAP SELECT %s: %w", name, err)
+	}
+	for _, l := range lines {
+		if n, ok := responseCode(l.Text, "UIDVALIDITY"); ok {
+			st.UIDValidity = n
+		}
+		if n, ok := responseCode(l.Text, "UIDNEXT"); ok {
+			st.UIDNext = n
+		}
+	}
+	return st, nil
+}
+
+// responseCode parses a numeric "[NAME n]" response code from a line.
+func responseCode(line, name string) (uint32, bool) {
+	_, rest, ok := strings.Cut(line, "["+name+" ")
+	if !ok {
+		return 0, false
+	}
+	value, _, _ := strings.Cut(rest, "]")
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(n), true
+}
+
+// highestUID returns the highest UID in the mailbox, or 0 if it is empty.
+func (c *imapClient) highestUID() (uint32, error) {
+	uids, err := c.searchUIDsAfter(0)
+	if err != nil || len(uids) == 0 {
+		return 0, err
+	}
+	return uids[len(uids)-1], nil
+}
+
+// searchUIDsAfter returns the UIDs greater than after, in ascending order.
+func (c *imapClient) searchUIDsAfter(after uint32) ([]uint32, error) {
+	lines, err := c.command(fmt.Sprintf("UID SEARCH UID %d:*", after+1))
+	if err != nil {
+		return nil, fmt.Errorf("IMAP UID SEARCH: %w", err)
+	}
+	var uids []uint32
+	for _, l := range lines {
+		fields := strings.Fields(l.Text)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, f := range fields[2:] {
+			n, err := strconv.ParseUint(f, 10, 32)
+			// "n:*" always matches the highest UID, even when it is <= after.
+			if err == nil && uint32(n) > after {
+				uids = append(uids, uint32(n))
+			}
+		}
+	}
+	slices.Sort(uids)
+	return uids, nil
+}
+
+// fetch returns the full RFC 5322 message for a UID without marking it seen.
+func (c *imapClient) fetch(uid uint32) ([]byte, error) {
+	lines, err := c.command(fmt.Sprintf("UID FETCH %d (BODY.PEEK[])", uid))
+	if err != nil {
+		return nil, fmt.Errorf("IMAP UID FETCH %d: %w", uid, err)
+	}
+	for _, l := range lines {
+		if strings.Contains(strings.ToUpper(l.Text), "FETCH") && len(l.Literals) > 0 {
+			return l.Literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("IMAP UID FETCH %d: message not found", uid)
+}
+
+// markSeen sets the \Seen flag on a UID.
+func (c *imapClient) markSeen(uid uint32) error {
+	if _, err := c.command(fmt.Sprintf(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid)); err != nil {
+		return fmt.Errorf("IMAP UID STORE %d: %w", uid, err)
+	}
+	return nil
+}
+
+// logout ends the session and closes the connection.
+func (c *imapClient) logout() error {
+	_, err := c.command("LOGOUT")
+	_ = c.conn.Close()
+	return err
+}
+
+// command sends a tagged command and collects untagged responses until the
+// tagged completion. A NO or BAD completion is returned as an error.
+func (c *imapClient) command(cmd string) ([]imapLine, error) {
+	c.tag++
+	tag := fmt.Sprintf("A%d", c.tag)
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, cmd); err != nil {
+		return nil, err
+	}
+
+	var lines []imapLine
+	for {
+		line, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(line.Text, tag+" "); ok {
+			status, _, _ := strings.Cut(rest, " ")
+			if strings.EqualFold(status, "OK") {
+				return lines, nil
+			}
+			return nil, errors.New(rest)
+		}
+		lines = append(lines, line)
+	}
+}
+
+// readResponse reads one response line, following literals ({n}\r\n
+// followed by n bytes) until the logical line ends.
+func (c *imapClient) readResponse() (imapLine, error) {
+	var resp imapLine
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return resp, err
+		}
+		resp.Text += line
+
+		size, ok := literalSize(line)
+		if !ok {
+			return resp, nil
+		}
+		if size > maxIMAPLiteral {
+			return resp, fmt.Errorf("IMAP literal too large (%d bytes)", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return resp, fmt.Errorf("reading IMAP literal: %w", err)
+		}
+		resp.Literals = append(resp.Literals, data)
+	}
+}
+
+// readLine reads a CRLF-terminated line, without the terminator.
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// literalSize reports the size of a literal announced at the end of line.
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	open := strings.LastIndexByte(line, '{')
+	if open < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[open+1:len(line)-1], "+"))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// imapQuote formats s as an IMAP quoted string.
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,107 @@
+package mailbridge
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"regexp"
+	"strings"
+)
+
+// maxReplySize bounds the text taken from one reply.
+const maxReplySize = 64 << 10
+
+// maxMIMEDepth bounds nesting of multipart bodies.
+const maxMIMEDepth = 5
+
+// errNoTextPart is returned for emails without a text/plain body.
+var errNoTextPart = errors.New("reply has no text/plain part")
+
+// header is the common interface of net/mail and MIME part headers.
+type header interface {
+	Get(key string) string
+}
+
+// replyText returns the new text of a reply: the text/plain body with the
+// quoted original and signature removed.
+func replyText(m *netmail.Message) (string, error) {
+	text, err := plainText(m.Header, m.Body, 0)
+	if err != nil {
+		return "", err
+	}
+	return stripQuoted(text), nil
+}
+
+// plainText returns the first text/plain part of a body, decoded.
+func plainText(h header, body io.Reader, depth int) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain" // RFC 2045 default
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMIMEDepth || params["boundary"] == "" {
+			return "", errNoTextPart
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return "", errNoTextPart
+			}
+			if err != nil {
+				return "", fmt.Errorf("reading MIME part: %w", err)
+			}
+			text, err := plainText(part.Header, part, depth+1)
+			if err == nil {
+				return text, nil
+			}
+			if !errors.Is(err, errNoTextPart) {
+				return "", err
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return "", errNoTextPart
+	}
+
+	switch strings.ToLower(h.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxReplySize))
+	if err != nil {
+		return "", fmt.Errorf("reading reply body: %w", err)
+	}
+	return string(data), nil
+}
+
+// quoteHeader matches the attribution line mail clients put above a quote,
+// e.g. "On Mon, 5 Jan 2026 at 10:00, Gas Town <gt@example.com> wrote:".
+var quoteHeader = regexp.MustCompile(`^On\s.*wrote:\s*$`)
+
+// stripQuoted cuts a reply at the quoted original or the signature.
+func stripQuoted(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	end := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") ||
+			line == "-- " ||
+			strings.HasPrefix(trimmed, "-----Original Message-----") ||
+			quoteHeader.MatchString(trimmed) ||
+			// Clients wrap long attribution lines: "On ... <addr>\nwrote:"
+			(strings.HasPrefix(trimmed, "On ") && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "wrote:") {
+			end = i
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[:end], "\n"))
+}
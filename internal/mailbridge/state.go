@@ -0,0 +1,119 @@
+package mailbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// stateRetention is how long relayed and ingested records are kept. Replies
+// to email older than this are no longer matched to their thread.
+const stateRetention = 30 * 24 * time.Hour
+
+// State is the bridge's persisted bookkeeping, stored in
+// <town>/.runtime/mail-bridge.json.
+type State struct {
+	// Relayed records mail sent out as email, keyed by mail message ID.
+	Relayed map[string]*RelayedMessage `json:"relayed"`
+
+	// Ingested records the Message-IDs of replies already delivered, so a
+	// reset IMAP UID sequence never delivers a reply twice.
+	Ingested map[string]time.Time `json:"ingested"`
+
+	// UIDValidity and LastUID track the IMAP mailbox position.
+	UIDValidity uint32 `json:"uid_validity,omitempty"`
+	LastUID     uint32 `json:"last_uid,omitempty"`
+
+	// Secret signs the Message-IDs of relayed mail, so replies can only
+	// thread to mail whose email the sender actually received.
+	Secret string `json:"secret,omitempty"`
+
+	LastSync time.Time `json:"last_sync,omitempty"`
+}
+
+// RelayedMessage records one mail message that was sent out as email.
+type RelayedMessage struct {
+	MessageID string    `json:"message_id"` // RFC 5322 Message-ID of the email
+	Mailbox   string    `json:"mailbox"`    // bridged mailbox the mail was in
+	From      string    `json:"from"`       // mail sender; replies go here
+	Subject   string    `json:"subject"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	RelayedAt time.Time `json:"relayed_at"`
+}
+
+// statePath returns the path of the bridge state file.
+func statePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "mail-bridge.json")
+}
+
+// lockState acquires the bridge lock so concurrent syncs (daemon patrol and
+// a manual `gt mail bridge sync`) cannot relay the same mail twice.
+func lockState(townRoot string) (func(), error) {
+	dir := constants.TownRuntimePath(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(dir, "mail-bridge.lock"))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring mail bridge lock: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// LoadState reads the bridge state, returning an empty state if none exists.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("reading mail bridge state: %w", err)
+	default:
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("parsing mail bridge state: %w", err)
+		}
+	}
+	if state.Relayed == nil {
+		state.Relayed = make(map[string]*RelayedMessage)
+	}
+	if state.Ingested == nil {
+		state.Ingested = make(map[string]time.Time)
+	}
+	if state.Secret == "" {
+		state.Secret = newBridgeSecret()
+	}
+	return state, nil
+}
+
+// save prunes expired records and writes the state atomically.
+func (s *State) save(townRoot string, now time.Time) error {
+	cutoff := now.Add(-stateRetention)
+	for id, r := range s.Relayed {
+		if r.RelayedAt.Before(cutoff) {
+			delete(s.Relayed, id)
+		}
+	}
+	for id, at := range s.Ingested {
+		if at.Before(cutoff) {
+			delete(s.Ingested, id)
+		}
+	}
+	// The state holds the bridge secret
+	return util.AtomicWriteJSONWithPerm(statePath(townRoot), s, 0600)
+}
+
+// relayedByMessageID finds the relayed mail an email Message-ID refers to.
+func (s *State) relayedByMessageID(messageID string) (string, *RelayedMessage) {
+	for id, r := range s.Relayed {
+		if r.MessageID == messageID {
+			return id, r
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// FromEscalationConfig builds notifiers for the external actions in a
+// severity route. Non-external actions ("bead", "mail:*") are ignored.
+// Actions that cannot be delivered because a contact or transport is
+// missing are returned as skipped results rather than errors, so one
+// misconfigured channel doesn't stop the others.
+//
+// Action targets resolve as follows:
+//   - email:human → contacts.human_email; email:<addr> with an "@" is used verbatim
+//   - sms:human   → contacts.human_sms; sms:<number> starting with "+" is used verbatim
+//   - slack       → contacts.slack_webhook
+//   - webhook     → contacts.webhook_url
+//   - log         → delivery.log_file (default logs/escalations.log)
+func FromEscalationConfig(townRoot string, cfg *config.EscalationConfig, actions []string) ([]Notifier, []Result) {
+	var notifiers []Notifier
+	var skipped []Result
+
+	delivery := cfg.Delivery
+	if delivery == nil {
+		delivery = &config.EscalationDelivery{}
+	}
+
+	skip := func(channel, action string, err error) {
+		skipped = append(skipped, Result{
+			Channel: channel,
+			Status:  StatusSkipped,
+			Error:   fmt.Sprintf("%s: %v", action, err),
+		})
+	}
+
+	for _, action := range actions {
+		switch {
+		case strings.HasPrefix(action, "email:"):
+			to := resolveEmail(strings.TrimPrefix(action, "email:"), cfg.Contacts)
+			switch {
+			case to == "":
+				skip(ChannelEmail, action, fmt.Errorf("%w: contacts.human_email", ErrNotConfigured))
+			case delivery.SMTP == nil:
+				skip(ChannelEmail, action, fmt.Errorf("%w: delivery.smtp", ErrNotConfigured))
+			default:
+				notifiers = append(notifiers, &SMTPNotifier{
+					Host:     delivery.SMTP.Host,
+					Port:     delivery.SMTP.Port,
+					From:     delivery.SMTP.From,
+					To:       to,
+					Username: delivery.SMTP.Username,
+					Password: envOrEmpty(delivery.SMTP.PasswordEnv),
+				})
+			}
+
+		case strings.HasPrefix(action, "sms:"):
+			to := resolveSMS(strings.TrimPrefix(action, "sms:"), cfg.Contacts)
+			switch {
+			case to == "":
+				skip(ChannelSMS, action, fmt.Errorf("%w: contacts.human_sms", ErrNotConfigured))
+			case delivery.SMSGateway == nil:
+				skip(ChannelSMS, action, fmt.Errorf("%w: delivery.sms_gateway", ErrNotConfigured))
+			default:
+				notifiers = append(notifiers, &SMSNotifier{
+					GatewayURL: delivery.SMSGateway.URL,
+					Token:      envOrEmpty(delivery.SMSGateway.TokenEnv),
+					To:         to,
+				})
+			}
+
+		case action == "slack":
+			if cfg.Contacts.SlackWebhook == "" {
+				skip(ChannelSlack, action, fmt.Errorf("%w: contacts.slack_webhook", ErrNotConfigured))
+				continue
+			}
+			notifiers = append(notifiers, &WebhookNotifier{Name: ChannelSlack, URL: cfg.Contacts.SlackWebhook})
+
+		case action == "webhook":
+			if cfg.Contacts.WebhookURL == "" {
+				skip(ChannelWebhook, action, fmt.Errorf("%w: contacts.webhook_url", ErrNotConfigured))
+				continue
+			}
+			notifiers = append(notifiers, &WebhookNotifier{Name: ChannelWebhook, URL: cfg.Contacts.WebhookURL})
+
+		case action == "log":
+			notifiers = append(notifiers, &LogNotifier{Path: cfg.GetDeliveryLogFile(townRoot)})
+		}
+	}
+
+	return notifiers, skipped
+}
+
+// NewDispatcherFromConfig creates a dispatcher using the retry and rate-limit
+// settings from the escalation config.
+func NewDispatcherFromConfig(townRoot string, cfg *config.EscalationConfig) *Dispatcher {
+	var limiter *RateLimiter
+	if cfg.Delivery != nil && len(cfg.Delivery.RateLimits) > 0 {
+		limits := make(map[string]Limit, len(cfg.Delivery.RateLimits))
+		for channel, rl := range cfg.Delivery.RateLimits {
+			window, err := time.ParseDuration(rl.Window)
+			if err != nil {
+				continue // rejected by config validation; ignore defensively
+			}
+			limits[channel] = Limit{Max: rl.Max, Window: window}
+		}
+		limiter = NewRateLimiter(RateLimitStateFile(townRoot), limits)
+	}
+	return NewDispatcher(cfg.GetDeliveryMaxAttempts(), cfg.GetDeliveryRetryBackoff(), limiter)
+}
+
+func resolveEmail(target string, contacts config.EscalationContacts) string {
+	if target == "human" {
+		return contacts.HumanEmail
+	}
+	if strings.Contains(target, "@") {
+		return target
+	}
+	return ""
+}
+
+func resolveSMS(target string, contacts config.EscalationContacts) string {
+	if target == "human" {
+		return contacts.HumanSMS
+	}
+	if strings.HasPrefix(target, "+") {
+		return target
+	}
+	return ""
+}
+
+func envOrEmpty(name string) string {
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
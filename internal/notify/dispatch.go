@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Dispatcher delivers notifications through a set of notifiers with
+// retries and optional per-channel rate limiting.
+type Dispatcher struct {
+	// MaxAttempts is the number of delivery attempts per notifier (minimum 1).
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry; it doubles after each failure.
+	Backoff time.Duration
+
+	// Limiter enforces per-channel rate limits. Nil disables rate limiting.
+	Limiter *RateLimiter
+
+	// sleep is overridable for tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher creates a dispatcher with the given retry policy.
+func NewDispatcher(maxAttempts int, backoff time.Duration, limiter *RateLimiter) *Dispatcher {
+	return &Dispatcher{
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Limiter:     limiter,
+	}
+}
+
+// Deliver sends n through each notifier in order and returns one Result per
+// notifier. Failures on one channel never prevent delivery on the others.
+func (d *Dispatcher) Deliver(ctx context.Context, notifiers []Notifier, n *Notification) []Result {
+	results := make([]Result, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		results = append(results, d.deliverOne(ctx, notifier, n))
+	}
+	return results
+}
+
+func (d *Dispatcher) deliverOne(ctx context.Context, notifier Notifier, n *Notification) Result {
+	result := Result{
+		Channel: notifier.Channel(),
+		Target:  notifier.Target(),
+	}
+
+	if d.Limiter != nil {
+		allowed, err := d.Limiter.Allow(notifier.Channel())
+		if err != nil {
+			// A broken rate-limit state file must not block a page at 3am.
+			// Deliver anyway and surface the problem in the result.
+			result.Error = "rate limiter: " + err.Error()
+		} else if !allowed {
+			result.Status = StatusRateLimited
+			return result
+		}
+	}
+
+	maxAttempts := d.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := d.Backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result.Attempts = attempt
+		lastErr = notifier.Notify(ctx, n)
+		if lastErr == nil {
+			result.Status = StatusSent
+			return result
+		}
+		if attempt == maxAttempts {
+			break
+		}
+		if err := d.wait(ctx, backoff); err != nil {
+			lastErr = err
+			break
+		}
+		backoff *= 2
+	}
+
+	result.Status = StatusFailed
+	result.Error = lastErr.Error()
+	return result
+}
+
+func (d *Dispatcher) wait(ctx context.Context, delay time.Duration) error {
+	if d.sleep != nil {
+		return d.sleep(ctx, delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LogNotifier appends notifications as JSON lines to an escalation log file.
+type LogNotifier struct {
+	Path string
+}
+
+// Channel implements Notifier.
+func (l *LogNotifier) Channel() string { return ChannelLog }
+
+// Target implements Notifier.
+func (l *LogNotifier) Target() string { return l.Path }
+
+// Notify implements Notifier. The file is opened O_APPEND so concurrent
+// writers from separate gt processes never interleave within a line.
+func (l *LogNotifier) Notify(_ context.Context, n *Notification) error {
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshaling log entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: escalation log is not secret
+	if err != nil {
+		return fmt.Errorf("opening escalation log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing escalation log: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,89 @@
+// Package notify delivers escalation notifications to humans over external
+// channels: SMTP email, Slack-compatible and generic JSON webhooks, an SMS
+// gateway, and an append-only escalation log file.
+//
+// Each channel implements Notifier. A Dispatcher wraps notifiers with
+// retries and per-channel rate limiting, and reports a Result per delivery
+// so callers can record delivery status on the escalation bead.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Channel names used for rate limiting and delivery status.
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+	ChannelLog     = "log"
+)
+
+// Delivery status values recorded per channel.
+const (
+	StatusSent        = "sent"
+	StatusFailed      = "failed"
+	StatusRateLimited = "rate_limited"
+	StatusSkipped     = "skipped"
+)
+
+// ErrNotConfigured is returned when an action's transport or contact is missing.
+var ErrNotConfigured = errors.New("not configured")
+
+// Notification is a single escalation message to deliver.
+type Notification struct {
+	ID          string    `json:"id"`               // escalation bead ID
+	Severity    string    `json:"severity"`         // critical, high, medium, low
+	Subject     string    `json:"subject"`          // one-line summary
+	Body        string    `json:"body,omitempty"`   // full text
+	Source      string    `json:"source,omitempty"` // e.g., plugin:rebuild-gt
+	From        string    `json:"from,omitempty"`   // escalating agent
+	Timestamp   time.Time `json:"timestamp"`        // when the escalation was raised
+	Reescalated bool      `json:"reescalated,omitempty"`
+}
+
+// ShortText renders the notification as a single line suitable for SMS
+// and chat messages.
+func (n *Notification) ShortText() string {
+	text := fmt.Sprintf("[%s] %s (%s)", strings.ToUpper(n.Severity), n.Subject, n.ID)
+	if n.From != "" {
+		text += " from " + n.From
+	}
+	return text
+}
+
+// Notifier delivers a notification over one channel.
+type Notifier interface {
+	// Channel returns the channel name (e.g., "email", "sms").
+	Channel() string
+
+	// Target returns a human-readable destination for status output.
+	Target() string
+
+	// Notify performs a single delivery attempt.
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// Result describes the outcome of delivering to one notifier.
+type Result struct {
+	Channel  string `json:"channel"`
+	Target   string `json:"target,omitempty"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// FormatStatus renders results as a compact "channel=status" list, suitable
+// for the delivery_status field of an escalation bead.
+func FormatStatus(results []Result) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, r.Channel+"="+r.Status)
+	}
+	return strings.Join(parts, ",")
+}
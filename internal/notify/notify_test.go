@@ -0,0 +1,412 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		ID:        "hq-esc1",
+		Severity:  "critical",
+		Subject:   "Dolt server down",
+		Body:      "Escalation ID: hq-esc1\nSeverity: critical",
+		From:      "gastown/deacon",
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// smtpSink is a minimal in-process SMTP server that records messages.
+type smtpSink struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpSink{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *smtpSink) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP sink")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var msg strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" {
+					break
+				}
+				msg.WriteString(dl)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	sink := newSMTPSink(t)
+	n := &SMTPNotifier{
+		Host: "127.0.0.1",
+		Port: sink.port(),
+		From: "gastown@example.com",
+		To:   "oncall@example.com",
+	}
+
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(sink.messages))
+	}
+	msg := sink.messages[0]
+	for _, want := range []string{
+		"To: oncall@example.com",
+		"Subject: [CRITICAL] Dolt server down",
+		"X-Gastown-Escalation: hq-esc1",
+		"Severity: critical",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+	if len(sink.rcpts) != 1 || !strings.Contains(sink.rcpts[0], "oncall@example.com") {
+		t.Errorf("rcpts = %v", sink.rcpts)
+	}
+}
+
+func TestSMTPNotifier_HeaderInjection(t *testing.T) {
+	n := &SMTPNotifier{From: "a@example.com", To: "b@example.com"}
+	notification := testNotification()
+	notification.Subject = "evil\r\nBcc: victim@example.com"
+
+	msg := n.formatMessage(notification)
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Errorf("subject newline was not sanitized:\n%s", msg)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{Name: ChannelSlack, URL: srv.URL}
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if !strings.HasPrefix(got.Text, "[CRITICAL] Dolt server down (hq-esc1)") {
+		t.Errorf("text = %q", got.Text)
+	}
+	if got.Notification == nil || got.Notification.ID != "hq-esc1" {
+		t.Errorf("escalation payload = %+v", got.Notification)
+	}
+}
+
+func TestWebhookNotifier_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{Name: ChannelWebhook, URL: srv.URL}
+	err := n.Notify(context.Background(), testNotification())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("err = %v, want HTTP 403 with body", err)
+	}
+}
+
+func TestSMSNotifier(t *testing.T) {
+	var got smsPayload
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n := &SMSNotifier{GatewayURL: srv.URL, Token: "s3cret", To: "+15551234567"}
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.To != "+15551234567" {
+		t.Errorf("to = %q", got.To)
+	}
+	if strings.Contains(got.Body, "\n") {
+		t.Errorf("SMS body should be a single line, got %q", got.Body)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestLogNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "escalations.log")
+	n := &LogNotifier{Path: path}
+
+	for i := 0; i < 2; i++ {
+		if err := n.Notify(context.Background(), testNotification()); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (append-only)", len(lines))
+	}
+	var entry Notification
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if entry.ID != "hq-esc1" || entry.Severity != "critical" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+// flakyNotifier fails the first failures attempts.
+type flakyNotifier struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyNotifier) Channel() string { return "flaky" }
+func (f *flakyNotifier) Target() string  { return "test" }
+func (f *flakyNotifier) Notify(context.Context, *Notification) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestDispatcher_Retries(t *testing.T) {
+	var delays []time.Duration
+	d := NewDispatcher(3, time.Second, nil)
+	d.sleep = func(_ context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+
+	ok := &flakyNotifier{failures: 2}
+	broken := &flakyNotifier{failures: 10}
+	results := d.Deliver(context.Background(), []Notifier{ok, broken}, testNotification())
+
+	if results[0].Status != StatusSent || results[0].Attempts != 3 {
+		t.Errorf("recovering notifier: %+v", results[0])
+	}
+	if results[1].Status != StatusFailed || results[1].Attempts != 3 || results[1].Error != "connection refused" {
+		t.Errorf("broken notifier: %+v", results[1])
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, time.Second, 2 * time.Second}
+	if len(delays) != len(want) {
+		t.Fatalf("delays = %v, want %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delay[%d] = %v, want %v (exponential backoff)", i, delays[i], want[i])
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".runtime", "notify-ratelimit.json")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limits := map[string]Limit{ChannelSMS: {Max: 2, Window: time.Hour}}
+
+	// Separate limiter instances share state through the file, like
+	// separate gt escalate processes.
+	newLimiter := func() *RateLimiter {
+		rl := NewRateLimiter(path, limits)
+		rl.now = func() time.Time { return now }
+		return rl
+	}
+
+	for i, want := range []bool{true, true, false} {
+		got, err := newLimiter().Allow(ChannelSMS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("sms delivery %d: allowed = %v, want %v", i+1, got, want)
+		}
+	}
+
+	// Unlimited channels always pass.
+	if got, _ := newLimiter().Allow(ChannelEmail); !got {
+		t.Error("email should not be rate limited")
+	}
+
+	// The window slides.
+	now = now.Add(61 * time.Minute)
+	if got, _ := newLimiter().Allow(ChannelSMS); !got {
+		t.Error("sms should be allowed after the window passes")
+	}
+}
+
+func TestDispatcher_RateLimited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rl.json")
+	limiter := NewRateLimiter(path, map[string]Limit{"flaky": {Max: 1, Window: time.Hour}})
+	d := NewDispatcher(1, 0, limiter)
+
+	n := &flakyNotifier{}
+	first := d.Deliver(context.Background(), []Notifier{n}, testNotification())
+	second := d.Deliver(context.Background(), []Notifier{n}, testNotification())
+
+	if first[0].Status != StatusSent {
+		t.Errorf("first = %+v", first[0])
+	}
+	if second[0].Status != StatusRateLimited {
+		t.Errorf("second = %+v", second[0])
+	}
+	if n.calls != 1 {
+		t.Errorf("notifier called %d times, want 1", n.calls)
+	}
+}
+
+func TestFromEscalationConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{
+			HumanEmail:   "human@example.com",
+			SlackWebhook: "https://hooks.example.com/x",
+		},
+		Delivery: &config.EscalationDelivery{
+			SMTP: &config.SMTPConfig{Host: "smtp.example.com", From: "gt@example.com"},
+		},
+	}
+
+	notifiers, skipped := FromEscalationConfig(townRoot, cfg,
+		[]string{"bead", "mail:mayor", "email:human", "email:ops@example.com", "sms:human", "slack", "webhook", "log"})
+
+	var channels []string
+	for _, n := range notifiers {
+		channels = append(channels, n.Channel()+":"+n.Target())
+	}
+	wantChannels := []string{
+		"email:human@example.com",
+		"email:ops@example.com",
+		"slack:slack webhook",
+		"log:" + filepath.Join(townRoot, "logs", "escalations.log"),
+	}
+	if strings.Join(channels, "|") != strings.Join(wantChannels, "|") {
+		t.Errorf("notifiers = %v, want %v", channels, wantChannels)
+	}
+
+	if len(skipped) != 2 {
+		t.Fatalf("skipped = %+v, want sms and webhook", skipped)
+	}
+	for _, r := range skipped {
+		if r.Status != StatusSkipped {
+			t.Errorf("skipped result status = %q", r.Status)
+		}
+	}
+	if FormatStatus(skipped) != "sms=skipped,webhook=skipped" {
+		t.Errorf("FormatStatus = %q", FormatStatus(skipped))
+	}
+}
+
+func TestNewDispatcherFromConfig(t *testing.T) {
+	maxAttempts := 5
+	cfg := &config.EscalationConfig{
+		Delivery: &config.EscalationDelivery{
+			MaxAttempts:  &maxAttempts,
+			RetryBackoff: "500ms",
+			RateLimits: map[string]config.RateLimitConfig{
+				ChannelSMS: {Max: 3, Window: "1h"},
+			},
+		},
+	}
+	d := NewDispatcherFromConfig(t.TempDir(), cfg)
+	if d.MaxAttempts != 5 || d.Backoff != 500*time.Millisecond {
+		t.Errorf("dispatcher = %+v", d)
+	}
+	if d.Limiter == nil || d.Limiter.limits[ChannelSMS].Max != 3 {
+		t.Errorf("limiter = %+v", d.Limiter)
+	}
+
+	d = NewDispatcherFromConfig(t.TempDir(), &config.EscalationConfig{})
+	if d.MaxAttempts != 3 || d.Backoff != 2*time.Second || d.Limiter != nil {
+		t.Errorf("defaults = %+v", d)
+	}
+}
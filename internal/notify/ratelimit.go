@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Limit caps deliveries on a channel to Max per Window.
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+// RateLimiter enforces per-channel delivery limits across processes.
+// Each gt escalate invocation is a separate process, so delivery timestamps
+// are persisted to a state file guarded by a file lock.
+type RateLimiter struct {
+	path   string
+	limits map[string]Limit
+	now    func() time.Time
+}
+
+// rateLimitState is the on-disk representation of recent deliveries.
+type rateLimitState struct {
+	// Channels maps channel name to delivery timestamps within the window.
+	Channels map[string][]time.Time `json:"channels"`
+}
+
+// RateLimitStateFile returns the path to the notifier rate-limit state file.
+func RateLimitStateFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "notify-ratelimit.json")
+}
+
+// NewRateLimiter creates a limiter persisting state at path.
+// Channels absent from limits are never limited.
+func NewRateLimiter(path string, limits map[string]Limit) *RateLimiter {
+	return &RateLimiter{
+		path:   path,
+		limits: limits,
+		now:    time.Now,
+	}
+}
+
+// Allow reports whether a delivery on channel is permitted right now and,
+// if so, records it against the channel's budget.
+func (r *RateLimiter) Allow(channel string) (bool, error) {
+	limit, ok := r.limits[channel]
+	if !ok || limit.Max <= 0 {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return false, fmt.Errorf("creating state directory: %w", err)
+	}
+	fl := flock.New(r.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return false, fmt.Errorf("acquiring rate-limit lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := r.load()
+	if err != nil {
+		return false, err
+	}
+
+	now := r.now()
+	cutoff := now.Add(-limit.Window)
+	var recent []time.Time
+	for _, ts := range state.Channels[channel] {
+		if ts.After(cutoff) {
+			recent = append(recent, ts)
+		}
+	}
+
+	if len(recent) >= limit.Max {
+		state.Channels[channel] = recent
+		return false, r.save(state)
+	}
+
+	state.Channels[channel] = append(recent, now)
+	return true, r.save(state)
+}
+
+func (r *RateLimiter) load() (*rateLimitState, error) {
+	data, err := os.ReadFile(r.path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &rateLimitState{Channels: make(map[string][]time.Time)}, nil
+		}
+		return nil, fmt.Errorf("reading rate-limit state: %w", err)
+	}
+
+	var state rateLimitState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing rate-limit state: %w", err)
+	}
+	if state.Channels == nil {
+		state.Channels = make(map[string][]time.Time)
+	}
+	return &state, nil
+}
+
+func (r *RateLimiter) save(state *rateLimitState) error {
+	return util.AtomicWriteJSON(r.path, state)
+}
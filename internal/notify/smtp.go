@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPNotifier sends escalation email through an SMTP relay.
+type SMTPNotifier struct {
+	Host     string
+	Port     int
+	From     string
+	To       string
+	Username string
+	Password string
+
+	// Timeout bounds connecting to and talking with the relay.
+	Timeout time.Duration
+}
+
+// Channel implements Notifier.
+func (s *SMTPNotifier) Channel() string { return ChannelEmail }
+
+// Target implements Notifier.
+func (s *SMTPNotifier) Target() string { return s.To }
+
+// Notify implements Notifier. STARTTLS is used when the server offers it,
+// and AUTH PLAIN when a username is configured.
+func (s *SMTPNotifier) Notify(ctx context.Context, n *Notification) error {
+	return s.Send(ctx, s.formatMessage(n))
+}
+
+// Send delivers a preformatted RFC 5322 message (headers and CRLF body)
+// from s.From to s.To, with the same transport behavior as Notify.
+func (s *SMTPNotifier) Send(ctx context.Context, message string) error {
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to SMTP server %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("starting SMTP session: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS: %w", err)
+		}
+	}
+
+	if s.Username != "" {
+		auth := smtp.PlainAuth("", s.Username, s.Password, s.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.From); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM: %w", err)
+	}
+	if err := client.Rcpt(s.To); err != nil {
+		return fmt.Errorf("SMTP RCPT TO: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("writing SMTP message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("finishing SMTP message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// formatMessage builds an RFC 5322 message for the notification.
+func (s *SMTPNotifier) formatMessage(n *Notification) string {
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(n.Severity), n.Subject)
+	if n.Reescalated {
+		subject = "Re-escalated: " + subject
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", s.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", SanitizeHeader(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Timestamp.Format(time.RFC1123Z))
+	if n.ID != "" {
+		fmt.Fprintf(&b, "X-Gastown-Escalation: %s\r\n", SanitizeHeader(n.ID))
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	body := n.Body
+	if body == "" {
+		body = n.ShortText()
+	}
+	// SMTP requires CRLF line endings; a lone "." line would end DATA early,
+	// but the textproto dot-writer used by smtp.Client escapes those.
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// SanitizeHeader strips CR/LF so values cannot inject extra headers.
+func SanitizeHeader(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier POSTs a JSON payload to an HTTP endpoint.
+//
+// The payload carries a top-level "text" field so Slack incoming webhooks
+// (and compatible services such as Mattermost or Discord's /slack endpoint)
+// render it directly; the structured escalation fields ride alongside for
+// generic receivers.
+type WebhookNotifier struct {
+	// Name is the channel name reported in results ("slack" or "webhook").
+	Name string
+	URL  string
+
+	// Client is the HTTP client to use. Defaults to a client with a 30s timeout.
+	Client *http.Client
+}
+
+// webhookPayload is the JSON body sent to webhook receivers.
+type webhookPayload struct {
+	Text         string        `json:"text"`
+	Notification *Notification `json:"escalation"`
+}
+
+// Channel implements Notifier.
+func (w *WebhookNotifier) Channel() string { return w.Name }
+
+// Target implements Notifier. The URL is omitted because webhook URLs
+// are bearer secrets.
+func (w *WebhookNotifier) Target() string { return w.Name + " webhook" }
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	text := n.ShortText()
+	if n.Body != "" {
+		text += "\n" + n.Body
+	}
+	return postJSON(ctx, w.Client, w.URL, "", webhookPayload{Text: text, Notification: n})
+}
+
+// SMSNotifier sends a text message through an HTTP SMS gateway.
+// The gateway receives {"to": "<number>", "body": "<text>"}.
+type SMSNotifier struct {
+	GatewayURL string
+	Token      string // optional bearer token
+	To         string
+
+	// Client is the HTTP client to use. Defaults to a client with a 30s timeout.
+	Client *http.Client
+}
+
+// smsPayload is the JSON body sent to the SMS gateway.
+type smsPayload struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+// Channel implements Notifier.
+func (s *SMSNotifier) Channel() string { return ChannelSMS }
+
+// Target implements Notifier.
+func (s *SMSNotifier) Target() string { return s.To }
+
+// Notify implements Notifier. Only the one-line summary is sent; SMS is for
+// waking someone up, the details live on the bead.
+func (s *SMSNotifier) Notify(ctx context.Context, n *Notification) error {
+	return postJSON(ctx, s.Client, s.GatewayURL, s.Token, smsPayload{To: s.To, Body: n.ShortText()})
+}
+
+// postJSON POSTs body as JSON and treats any non-2xx response as an error.
+func postJSON(ctx context.Context, client *http.Client, url, token string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if len(snippet) > 0 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return fmt.Errorf("HTTP %d", resp.StatusCode)
+}
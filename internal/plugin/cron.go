@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed 5-field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Fields accept *, single values, ranges (1-5), lists (1,15) and steps
+// (*/15, 0-30/10). Months and weekdays also accept three-letter names
+// (jan, mon); weekday 7 is Sunday. The macros @hourly, @daily (@midnight),
+// @weekly, @monthly and @yearly (@annually) are supported.
+//
+// As in standard cron, when both day-of-month and day-of-week are
+// restricted, a day matching either one fires.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// scheduleHorizon bounds the search in Next. Every valid schedule fires at
+// least once in any 8-year window (Feb 29 can skip a leap year at century
+// boundaries).
+const scheduleHorizon = 8 * 366 * 24 * time.Hour
+
+// ParseSchedule parses a 5-field cron expression or macro.
+func ParseSchedule(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if m, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule %q: expected 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+
+	s := &Schedule{domStar: strings.HasPrefix(fields[2], "*"), dowStar: strings.HasPrefix(fields[4], "*")}
+	specs := []struct {
+		name     string
+		out      *uint64
+		min, max int
+		names    map[string]int
+	}{
+		{"minute", &s.minute, 0, 59, nil},
+		{"hour", &s.hour, 0, 23, nil},
+		{"day of month", &s.dom, 1, 31, nil},
+		{"month", &s.month, 1, 12, monthNames},
+		{"day of week", &s.dow, 0, 7, weekdayNames},
+	}
+	for i, f := range specs {
+		bits, err := parseCronField(fields[i], f.min, f.max, f.names)
+		if err != nil {
+			return nil, fmt.Errorf("cron schedule %q: %s: %w", expr, f.name, err)
+		}
+		*f.out = bits
+	}
+	// 7 is an alias for Sunday.
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	ref := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if s.Next(ref).IsZero() {
+		return nil, fmt.Errorf("cron schedule %q never fires", expr)
+	}
+	return s, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bitset.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(a, names); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(b, names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := cronValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// Next returns the first time strictly after t (truncated to the minute)
+// that matches the schedule, in t's location. It returns the zero time if
+// nothing matches within the search horizon.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(scheduleHorizon)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
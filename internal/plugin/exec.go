@@ -0,0 +1,224 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultExecTimeout bounds an executable plugin when its execution section
+// sets no timeout.
+const DefaultExecTimeout = 5 * time.Minute
+
+// execStopGrace is how long a timed-out entrypoint gets between SIGTERM and
+// SIGKILL: long enough for its exit traps to undo what it started.
+var execStopGrace = 30 * time.Second
+
+// maxOutputTail is how much of a failed entrypoint's stderr is kept in the
+// run record.
+const maxOutputTail = 2000
+
+// ExecContext is written as JSON to an executable plugin's stdin.
+type ExecContext struct {
+	// TownRoot is the town root directory.
+	TownRoot string `json:"town_root"`
+
+	// Rig is the rig a rig-level plugin belongs to (empty for town-level).
+	Rig string `json:"rig,omitempty"`
+
+	// Plugin is the plugin name.
+	Plugin string `json:"plugin"`
+
+	// PluginDir is the plugin directory, also the working directory.
+	PluginDir string `json:"plugin_dir"`
+
+	// Trigger says why the plugin is running: the gate reason, or "manual".
+	Trigger string `json:"trigger,omitempty"`
+
+	// LastRun is the previous recorded run, if any.
+	LastRun *ExecLastRun `json:"last_run,omitempty"`
+}
+
+// ExecLastRun describes the previous run of a plugin.
+type ExecLastRun struct {
+	ID     string    `json:"id"`
+	Result RunResult `json:"result,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// ExecResult is the structured result an executable plugin prints on stdout.
+//
+//	{"result": "success", "summary": "snapshot=20260110-0900 archives=3"}
+type ExecResult struct {
+	// Result is success, failure or skipped.
+	Result RunResult `json:"result"`
+
+	// Summary is a one-line outcome, used as the run record body.
+	Summary string `json:"summary,omitempty"`
+
+	// Details is optional longer output appended to the run record.
+	Details string `json:"details,omitempty"`
+}
+
+// NewExecContext builds the stdin context for a plugin run. last may be nil.
+func NewExecContext(townRoot string, p *Plugin, trigger string, last *PluginRunBead) ExecContext {
+	ec := ExecContext{
+		TownRoot:  townRoot,
+		Rig:       p.RigName,
+		Plugin:    p.Name,
+		PluginDir: p.Path,
+		Trigger:   trigger,
+	}
+	if last != nil {
+		ec.LastRun = &ExecLastRun{ID: last.ID, Result: last.Result, At: last.CreatedAt}
+	}
+	return ec
+}
+
+// ExecTimeout returns the plugin's execution timeout.
+func (p *Plugin) ExecTimeout() time.Duration {
+	if p.Execution != nil && p.Execution.Timeout != "" {
+		if d, err := time.ParseDuration(p.Execution.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultExecTimeout
+}
+
+// EntrypointPath returns the absolute path of the plugin's entrypoint, or ""
+// if the plugin is not executable.
+func (p *Plugin) EntrypointPath() string {
+	if !p.IsExecutable() {
+		return ""
+	}
+	return filepath.Join(p.Path, p.Execution.Entrypoint)
+}
+
+// Exec runs an executable plugin's entrypoint in its plugin directory.
+//
+// The entrypoint gets ec as JSON on stdin and GT_TOWN_ROOT, GT_PLUGIN and
+// GT_RIG in its environment. It reports its outcome as an ExecResult JSON
+// object on stdout; when stdout has no such object (the last line is also
+// tried, so scripts may log before it) the exit code decides: 0 is success,
+// anything else failure. A non-zero exit or timeout is always a failure.
+//
+// Exec returns an error only if the entrypoint could not be started; every
+// run that started yields a result suitable for RunRecord.
+func Exec(ctx context.Context, p *Plugin, ec ExecContext) (*ExecResult, error) {
+	entrypoint := p.EntrypointPath()
+	if entrypoint == "" {
+		return nil, fmt.Errorf("plugin %s has no entrypoint", p.Name)
+	}
+	if _, err := os.Stat(entrypoint); err != nil {
+		return nil, fmt.Errorf("plugin %s entrypoint: %w", p.Name, err)
+	}
+	input, err := json.Marshal(ec)
+	if err != nil {
+		return nil, fmt.Errorf("encoding plugin context: %w", err)
+	}
+
+	timeout := p.ExecTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, entrypoint) //nolint:gosec // G204: entrypoint is confined to the plugin directory
+	cmd.Dir = p.Path
+	// Stop the whole process tree on timeout so scripts can't leave
+	// children holding the output pipes open. SIGTERM comes first so
+	// cleanup traps (e.g. restarting a server the script stopped) run.
+	util.SetProcessGroupGraceful(cmd, execStopGrace)
+	cmd.Env = append(os.Environ(),
+		"GT_TOWN_ROOT="+ec.TownRoot,
+		"GT_PLUGIN="+p.Name,
+		"GT_RIG="+p.RigName,
+	)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) && ctx.Err() == nil {
+		return nil, fmt.Errorf("running plugin %s: %w", p.Name, runErr)
+	}
+
+	res := parseExecOutput(stdout.Bytes())
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.Result = ResultFailure
+		res.Summary = joinSummary(fmt.Sprintf("timed out after %s", timeout), res.Summary)
+	case runErr != nil:
+		res.Result = ResultFailure
+		if res.Summary == "" {
+			res.Summary = runErr.Error()
+		}
+	case res.Result == "":
+		res.Result = ResultSuccess
+	}
+	if res.Result == ResultFailure && res.Details == "" {
+		res.Details = tail(strings.TrimSpace(stderr.String()), maxOutputTail)
+	}
+	return res, nil
+}
+
+// parseExecOutput reads an ExecResult from stdout: the whole output, or
+// failing that its last non-empty line. Unknown results count as failures.
+func parseExecOutput(stdout []byte) *ExecResult {
+	out := bytes.TrimSpace(stdout)
+	candidates := [][]byte{out}
+	if i := bytes.LastIndexByte(out, '\n'); i >= 0 {
+		candidates = append(candidates, bytes.TrimSpace(out[i+1:]))
+	}
+	for _, c := range candidates {
+		var res ExecResult
+		if len(c) == 0 || c[0] != '{' || json.Unmarshal(c, &res) != nil {
+			continue
+		}
+		switch res.Result {
+		case ResultSuccess, ResultFailure, ResultSkipped, "":
+		default:
+			res.Summary = joinSummary(fmt.Sprintf("unknown result %q", res.Result), res.Summary)
+			res.Result = ResultFailure
+		}
+		return &res
+	}
+	return &ExecResult{}
+}
+
+// RunRecord converts the result into a ledger record for Recorder.RecordRun.
+func (r *ExecResult) RunRecord(p *Plugin) PluginRunRecord {
+	body := strings.TrimSpace(r.Summary + "\n\n" + r.Details)
+	return PluginRunRecord{
+		PluginName: p.Name,
+		RigName:    p.RigName,
+		Result:     r.Result,
+		Body:       body,
+	}
+}
+
+func joinSummary(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + ": " + b
+}
+
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
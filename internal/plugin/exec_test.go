@@ -0,0 +1,182 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeEntrypoint creates an executable plugin whose entrypoint is script.
+func writeEntrypoint(t *testing.T, script, timeout string) *Plugin {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell entrypoints require a Unix shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &Plugin{
+		Name:      "test-plugin",
+		Path:      dir,
+		RigName:   "gastown",
+		Execution: &Execution{Entrypoint: "run.sh", Timeout: timeout},
+	}
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		timeout     string
+		wantResult  RunResult
+		wantSummary string
+		wantDetails string
+	}{
+		{
+			name:        "structured success",
+			script:      `echo '{"result":"success","summary":"backed up 3 dbs"}'`,
+			wantResult:  ResultSuccess,
+			wantSummary: "backed up 3 dbs",
+		},
+		{
+			name:        "log lines before result",
+			script:      "echo starting\necho '{\"result\":\"skipped\",\"summary\":\"fresh\"}'",
+			wantResult:  ResultSkipped,
+			wantSummary: "fresh",
+		},
+		{
+			name:       "exit code only",
+			script:     "echo done",
+			wantResult: ResultSuccess,
+		},
+		{
+			name:        "non-zero exit overrides success",
+			script:      "echo '{\"result\":\"success\",\"summary\":\"partial\"}'\necho 'disk full' >&2\nexit 3",
+			wantResult:  ResultFailure,
+			wantSummary: "partial",
+			wantDetails: "disk full",
+		},
+		{
+			name:        "unknown result",
+			script:      `echo '{"result":"maybe"}'`,
+			wantResult:  ResultFailure,
+			wantSummary: `unknown result "maybe"`,
+		},
+		{
+			name:        "timeout",
+			script:      "sleep 5",
+			timeout:     "100ms",
+			wantResult:  ResultFailure,
+			wantSummary: "timed out after 100ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := writeEntrypoint(t, tt.script, tt.timeout)
+			res, err := Exec(context.Background(), p, NewExecContext("/town", p, "manual", nil))
+			if err != nil {
+				t.Fatalf("Exec() error: %v", err)
+			}
+			if res.Result != tt.wantResult {
+				t.Errorf("Result = %q, want %q", res.Result, tt.wantResult)
+			}
+			if tt.wantSummary != "" && res.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", res.Summary, tt.wantSummary)
+			}
+			if !strings.Contains(res.Details, tt.wantDetails) {
+				t.Errorf("Details = %q, want it to contain %q", res.Details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+// A timed-out entrypoint is sent SIGTERM before SIGKILL, so its exit traps
+// get to clean up (beads-backup restarts Dolt this way).
+func TestExec_TimeoutRunsExitTrap(t *testing.T) {
+	p := writeEntrypoint(t, "trap 'echo cleaned > trap.txt; exit 1' TERM\nsleep 5 &\nwait", "200ms")
+	res, err := Exec(context.Background(), p, NewExecContext("/town", p, "manual", nil))
+	if err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+	if res.Result != ResultFailure || !strings.HasPrefix(res.Summary, "timed out") {
+		t.Errorf("result = %q %q, want timeout failure", res.Result, res.Summary)
+	}
+	if data, _ := os.ReadFile(filepath.Join(p.Path, "trap.txt")); strings.TrimSpace(string(data)) != "cleaned" {
+		t.Errorf("exit trap did not run on timeout (trap.txt = %q)", data)
+	}
+}
+
+func TestExec_Context(t *testing.T) {
+	p := writeEntrypoint(t, `cat > context.json; echo "$GT_PLUGIN $GT_RIG $GT_TOWN_ROOT" > env.txt`, "")
+	last := &PluginRunBead{ID: "hq-wisp-1", Result: ResultFailure, CreatedAt: time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)}
+	if _, err := Exec(context.Background(), p, NewExecContext("/town", p, "cooldown 1h elapsed", last)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Path, "context.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ec ExecContext
+	if err := json.Unmarshal(data, &ec); err != nil {
+		t.Fatalf("stdin is not an ExecContext: %v\n%s", err, data)
+	}
+	if ec.TownRoot != "/town" || ec.Rig != "gastown" || ec.Plugin != "test-plugin" || ec.Trigger != "cooldown 1h elapsed" {
+		t.Errorf("context = %+v", ec)
+	}
+	if ec.LastRun == nil || ec.LastRun.ID != "hq-wisp-1" || ec.LastRun.Result != ResultFailure {
+		t.Errorf("last_run = %+v", ec.LastRun)
+	}
+
+	env, _ := os.ReadFile(filepath.Join(p.Path, "env.txt"))
+	if got := strings.TrimSpace(string(env)); got != "test-plugin gastown /town" {
+		t.Errorf("entrypoint env = %q", got)
+	}
+}
+
+func TestExec_MissingEntrypoint(t *testing.T) {
+	p := &Plugin{Name: "gone", Path: t.TempDir(), Execution: &Execution{Entrypoint: "run.sh"}}
+	if _, err := Exec(context.Background(), p, ExecContext{}); err == nil {
+		t.Error("expected error for missing entrypoint")
+	}
+	if _, err := Exec(context.Background(), &Plugin{Name: "agent"}, ExecContext{}); err == nil {
+		t.Error("expected error for plugin without entrypoint")
+	}
+}
+
+func TestExecResult_RunRecord(t *testing.T) {
+	p := &Plugin{Name: "beads-backup", RigName: "gastown"}
+	rec := (&ExecResult{Result: ResultFailure, Summary: "tar failed", Details: "no space left"}).RunRecord(p)
+	if rec.PluginName != "beads-backup" || rec.RigName != "gastown" || rec.Result != ResultFailure {
+		t.Errorf("RunRecord() = %+v", rec)
+	}
+	if rec.Body != "tar failed\n\nno space left" {
+		t.Errorf("Body = %q", rec.Body)
+	}
+}
+
+func TestParsePluginMD_Entrypoint(t *testing.T) {
+	content := func(entrypoint string) []byte {
+		return []byte("+++\nname = \"x\"\n[execution]\nentrypoint = \"" + entrypoint + "\"\n+++\n")
+	}
+
+	p, err := parsePluginMD(content("bin/run.sh"), "/plugins/x", LocationTown, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsExecutable() || p.EntrypointPath() != filepath.Join("/plugins/x", "bin/run.sh") {
+		t.Errorf("entrypoint = %q, executable = %v", p.EntrypointPath(), p.IsExecutable())
+	}
+
+	for _, bad := range []string{"../escape.sh", "/usr/bin/true"} {
+		if _, err := parsePluginMD(content(bad), "/plugins/x", LocationTown, ""); err == nil {
+			t.Errorf("entrypoint %q: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultConditionTimeout bounds a condition gate's check command when the
+// gate sets no timeout.
+const DefaultConditionTimeout = 30 * time.Second
+
+// ConditionCheckBudget bounds the time one evaluator spends running
+// condition checks, so slow checks can't stall a daemon heartbeat. Checks
+// left over once it is spent wait for the next heartbeat.
+const ConditionCheckBudget = time.Minute
+
+// gateEventAliases maps event gate names to the event types that open them.
+// Names not listed here are matched against raw event types.
+var gateEventAliases = map[string][]string{
+	"startup":      {events.TypeDaemonStart},
+	"merge":        {events.TypeMerged},
+	"convoy-close": {events.TypeConvoyClosed},
+	"escalation":   {events.TypeEscalationSent},
+}
+
+// GateState is the daemon's gate bookkeeping, persisted in
+// <town>/.runtime/plugin-gates.json. Plugin runs themselves stay on the
+// ledger; this only holds what the ledger can't answer: the events feed
+// cursor and when each plugin was last dispatched.
+type GateState struct {
+	// EventsOffset is the byte offset read up to in the events log.
+	EventsOffset int64 `json:"events_offset"`
+
+	// EventSeq counts events read so far. It keeps increasing across log
+	// rotation, so it orders events where byte offsets can't.
+	EventSeq int64 `json:"event_seq"`
+
+	// LastEvent maps an event type to the EventSeq of its latest occurrence.
+	LastEvent map[string]int64 `json:"last_event,omitempty"`
+
+	// Plugins holds per-plugin gate state by plugin name.
+	Plugins map[string]*PluginGateState `json:"plugins,omitempty"`
+}
+
+// PluginGateState is the gate state of one plugin.
+type PluginGateState struct {
+	// FirstSeen is when the daemon first evaluated the plugin. Cron gates
+	// without any recorded run count their schedule from here.
+	FirstSeen time.Time `json:"first_seen"`
+
+	// Dispatched is when the daemon last dispatched the plugin.
+	Dispatched time.Time `json:"dispatched,omitempty"`
+
+	// EventSeq is the EventSeq already handled by the plugin's event gate.
+	EventSeq int64 `json:"event_seq"`
+}
+
+// GateResult is the outcome of evaluating a plugin's gate.
+type GateResult struct {
+	Open   bool
+	Reason string
+}
+
+// GateEvaluator evaluates cooldown, cron, condition and event gates.
+type GateEvaluator struct {
+	townRoot string
+	state    *GateState
+	loc      *time.Location
+
+	now       func() time.Time
+	lastRun   func(pluginName string) (time.Time, error)
+	runsSince func(pluginName, window string) (int, error)
+	runCheck  func(ctx context.Context, dir, command string) error
+
+	checkBudget time.Duration // Condition check time left for this evaluator
+}
+
+// GateStatePath returns the path of the persisted gate state.
+func GateStatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "plugin-gates.json")
+}
+
+// NewGateEvaluator loads the town's gate state. Plugin run history is read
+// from the ledger through a Recorder.
+func NewGateEvaluator(townRoot string) (*GateEvaluator, error) {
+	state := &GateState{}
+	data, err := os.ReadFile(GateStatePath(townRoot))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("parsing plugin gate state: %w", err)
+		}
+	case os.IsNotExist(err):
+		// First run: don't replay history, start at the end of the events log.
+		if info, statErr := os.Stat(filepath.Join(townRoot, events.EventsFile)); statErr == nil {
+			state.EventsOffset = info.Size()
+		}
+	default:
+		return nil, fmt.Errorf("reading plugin gate state: %w", err)
+	}
+
+	recorder := NewRecorder(townRoot)
+	return &GateEvaluator{
+		townRoot: townRoot,
+		state:    state,
+		loc:      time.Local,
+		now:      time.Now,
+		lastRun: func(name string) (time.Time, error) {
+			run, err := recorder.GetLastRun(name)
+			if err != nil || run == nil {
+				return time.Time{}, err
+			}
+			return run.CreatedAt, nil
+		},
+		runsSince:   recorder.CountRunsSince,
+		runCheck:    runGateCheck,
+		checkBudget: ConditionCheckBudget,
+	}, nil
+}
+
+// PollEvents reads events appended to the events feed since the last poll,
+// so event gates can see them.
+func (e *GateEvaluator) PollEvents() error {
+	evs, offset, err := events.ReadFrom(e.townRoot, e.state.EventsOffset)
+	if err != nil {
+		return err
+	}
+	e.state.EventsOffset = offset
+	if e.state.LastEvent == nil && len(evs) > 0 {
+		e.state.LastEvent = make(map[string]int64)
+	}
+	for _, ev := range evs {
+		e.state.EventSeq++
+		e.state.LastEvent[ev.Type] = e.state.EventSeq
+	}
+	return nil
+}
+
+// Evaluate reports whether a plugin's gate is open. Plugins without a gate
+// are manual and never open.
+func (e *GateEvaluator) Evaluate(p *Plugin) (GateResult, error) {
+	if p.Gate == nil {
+		return GateResult{Reason: "manual gate"}, nil
+	}
+	switch p.Gate.Type {
+	case GateManual:
+		return GateResult{Reason: "manual gate"}, nil
+	case GateCooldown:
+		return e.evaluateCooldown(p)
+	case GateCron:
+		return e.evaluateCron(p)
+	case GateCondition:
+		return e.evaluateCondition(p)
+	case GateEvent:
+		return e.evaluateEvent(p)
+	default:
+		return GateResult{}, fmt.Errorf("unknown gate type %q", p.Gate.Type)
+	}
+}
+
+// MarkDispatched records that a plugin was dispatched, consuming the events
+// and schedule slot that opened its gate.
+func (e *GateEvaluator) MarkDispatched(p *Plugin) {
+	ps := e.pluginState(p)
+	ps.Dispatched = e.now()
+	ps.EventSeq = e.state.EventSeq
+}
+
+// NextRun returns when a cron-gated plugin is next scheduled to run. A time
+// in the past means a run was missed and is due now. ok is false for other
+// gate types.
+func (e *GateEvaluator) NextRun(p *Plugin) (next time.Time, ok bool, err error) {
+	if p.Gate == nil || p.Gate.Type != GateCron {
+		return time.Time{}, false, nil
+	}
+	sched, err := ParseSchedule(p.Gate.Schedule)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	anchor, err := e.cronAnchor(p)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return sched.Next(anchor), true, nil
+}
+
+// Save persists the gate state.
+func (e *GateEvaluator) Save() error {
+	return util.EnsureDirAndWriteJSON(GateStatePath(e.townRoot), e.state)
+}
+
+func (e *GateEvaluator) pluginState(p *Plugin) *PluginGateState {
+	if e.state.Plugins == nil {
+		e.state.Plugins = make(map[string]*PluginGateState)
+	}
+	ps := e.state.Plugins[p.Name]
+	if ps == nil {
+		ps = &PluginGateState{FirstSeen: e.now(), EventSeq: e.state.EventSeq}
+		e.state.Plugins[p.Name] = ps
+	}
+	return ps
+}
+
+func (e *GateEvaluator) evaluateCooldown(p *Plugin) (GateResult, error) {
+	if p.Gate.Duration == "" {
+		return GateResult{Open: true, Reason: "no cooldown"}, nil
+	}
+	count, err := e.runsSince(p.Name, p.Gate.Duration)
+	if err != nil {
+		return GateResult{}, fmt.Errorf("checking cooldown: %w", err)
+	}
+	if count > 0 {
+		return GateResult{Reason: fmt.Sprintf("ran within the last %s", p.Gate.Duration)}, nil
+	}
+	return GateResult{Open: true, Reason: fmt.Sprintf("no runs in the last %s", p.Gate.Duration)}, nil
+}
+
+// cronAnchor is the time a cron gate counts its schedule from: the latest
+// of the last recorded run and the last dispatch, or when the plugin was
+// first seen if it has never run.
+func (e *GateEvaluator) cronAnchor(p *Plugin) (time.Time, error) {
+	ps := e.pluginState(p)
+	anchor := ps.Dispatched
+	last, err := e.lastRun(p.Name)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading last run: %w", err)
+	}
+	if last.After(anchor) {
+		anchor = last
+	}
+	if anchor.IsZero() {
+		anchor = ps.FirstSeen
+	}
+	return anchor.In(e.loc), nil
+}
+
+func (e *GateEvaluator) evaluateCron(p *Plugin) (GateResult, error) {
+	sched, err := ParseSchedule(p.Gate.Schedule)
+	if err != nil {
+		return GateResult{}, err
+	}
+	anchor, err := e.cronAnchor(p)
+	if err != nil {
+		return GateResult{}, err
+	}
+	now := e.now().In(e.loc)
+	next := sched.Next(anchor)
+	if next.After(now) {
+		return GateResult{Reason: "next run " + next.Format(time.RFC3339)}, nil
+	}
+	// Any number of missed slots collapse into a single catch-up run.
+	if !sched.Next(next).After(now) {
+		return GateResult{Open: true, Reason: "catching up missed run from " + next.Format(time.RFC3339)}, nil
+	}
+	return GateResult{Open: true, Reason: "scheduled for " + next.Format(time.RFC3339)}, nil
+}
+
+func (e *GateEvaluator) evaluateCondition(p *Plugin) (GateResult, error) {
+	if p.Gate.Check == "" {
+		return GateResult{}, fmt.Errorf("condition gate has no check command")
+	}
+	if p.Gate.Duration != "" {
+		if res, err := e.evaluateCooldown(p); err != nil || !res.Open {
+			return res, err
+		}
+	}
+
+	timeout := DefaultConditionTimeout
+	if p.Gate.Timeout != "" {
+		d, err := time.ParseDuration(p.Gate.Timeout)
+		if err != nil {
+			return GateResult{}, fmt.Errorf("invalid check timeout %q: %w", p.Gate.Timeout, err)
+		}
+		timeout = d
+	}
+	if e.checkBudget <= 0 {
+		return GateResult{Reason: "check deferred: check time for this cycle is used up"}, nil
+	}
+	cut := timeout > e.checkBudget
+	if cut {
+		timeout = e.checkBudget
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.runCheck(ctx, p.Path, p.Gate.Check)
+	e.checkBudget -= time.Since(start)
+	switch {
+	case err == nil:
+		return GateResult{Open: true, Reason: "check passed"}, nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && cut:
+		return GateResult{Reason: "check deferred: check time for this cycle is used up"}, nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return GateResult{Reason: fmt.Sprintf("check timed out after %s", timeout)}, nil
+	default:
+		return GateResult{Reason: "check failed: " + err.Error()}, nil
+	}
+}
+
+func (e *GateEvaluator) evaluateEvent(p *Plugin) (GateResult, error) {
+	names := gateEventNames(p.Gate.On)
+	if len(names) == 0 {
+		return GateResult{}, fmt.Errorf("event gate has no events (set on)")
+	}
+	ps := e.pluginState(p)
+	for _, name := range names {
+		types, ok := gateEventAliases[name]
+		if !ok {
+			types = []string{name}
+		}
+		for _, t := range types {
+			if e.state.LastEvent[t] > ps.EventSeq {
+				return GateResult{Open: true, Reason: "event " + t}, nil
+			}
+		}
+	}
+	return GateResult{Reason: "waiting for " + strings.Join(names, ", ")}, nil
+}
+
+// gateEventNames splits an event gate's on field into sorted, unique names.
+func gateEventNames(on string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, n := range strings.Split(on, ",") {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n != "" && !seen[n] {
+			seen[n] = true
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runGateCheck runs a condition gate's check command with sh in the plugin
+// directory. A non-zero exit or timeout returns an error.
+func runGateCheck(ctx context.Context, dir, command string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: check comes from the town's own plugin definitions
+	cmd.Dir = dir
+	cmd.WaitDelay = time.Second
+	return cmd.Run()
+}
@@ -0,0 +1,213 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// 2026-03-02 is a Monday.
+	from := time.Date(2026, 3, 2, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"*/15 * * * *", "2026-03-02T10:15"},
+		{"0 2 * * *", "2026-03-03T02:00"},
+		{"@daily", "2026-03-03T00:00"},
+		{"30 9 * * mon-fri", "2026-03-03T09:30"},
+		{"0 0 * * 7", "2026-03-08T00:00"},
+		{"0 0 1 jan,jul *", "2026-07-01T00:00"},
+		{"0 0 13 * 5", "2026-03-06T00:00"}, // dom OR dow: Friday the 6th comes first
+		{"5-10/5 10 * * *", "2026-03-02T10:10"},
+		{"0 0 29 2 *", "2028-02-29T00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule: %v", err)
+			}
+			if got := s.Next(from).Format("2006-01-02T15:04"); got != tt.want {
+				t.Errorf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *", "0 0 30 2 *"} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) should fail", expr)
+		}
+	}
+}
+
+// testEvaluator returns an evaluator over an empty town with a fixed clock.
+func testEvaluator(t *testing.T, now time.Time) *GateEvaluator {
+	t.Helper()
+	e, err := NewGateEvaluator(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.loc = time.UTC
+	e.now = func() time.Time { return now }
+	e.lastRun = func(string) (time.Time, error) { return time.Time{}, nil }
+	e.runsSince = func(string, string) (int, error) { return 0, nil }
+	return e
+}
+
+func TestGateEvaluator_Cron(t *testing.T) {
+	now := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	e := testEvaluator(t, now)
+	e.now = func() time.Time { return now }
+	p := &Plugin{Name: "beads-backup", Gate: &Gate{Type: GateCron, Schedule: "0 2 * * *"}}
+
+	res, err := e.Evaluate(p)
+	if err != nil || res.Open {
+		t.Fatalf("before first slot: %+v, %v", res, err)
+	}
+	next, ok, _ := e.NextRun(p)
+	if !ok || !next.Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextRun = %v, %v", next, ok)
+	}
+
+	// The daemon was down for two nights: one catch-up run, not two.
+	now = now.Add(49 * time.Hour)
+	res, _ = e.Evaluate(p)
+	if !res.Open || !strings.HasPrefix(res.Reason, "catching up") {
+		t.Fatalf("after missed runs: %+v", res)
+	}
+	e.MarkDispatched(p)
+	if res, _ = e.Evaluate(p); res.Open {
+		t.Errorf("gate should close once dispatched: %+v", res)
+	}
+
+	// A recorded run (e.g. gt plugin run) also counts.
+	now = now.Add(24*time.Hour + 30*time.Minute)
+	e.lastRun = func(string) (time.Time, error) { return now.Add(-10 * time.Minute), nil }
+	if res, _ = e.Evaluate(p); res.Open {
+		t.Errorf("recorded run should satisfy the slot: %+v", res)
+	}
+
+	p.Gate.Schedule = "bogus"
+	if _, err := e.Evaluate(p); err == nil {
+		t.Error("invalid schedule should error")
+	}
+}
+
+func TestGateEvaluator_Condition(t *testing.T) {
+	e := testEvaluator(t, time.Now())
+	p := &Plugin{Name: "stale", Gate: &Gate{Type: GateCondition, Check: "true"}}
+
+	if res, err := e.Evaluate(p); err != nil || !res.Open {
+		t.Errorf("passing check: %+v, %v", res, err)
+	}
+
+	p.Gate.Check = "exit 3"
+	if res, _ := e.Evaluate(p); res.Open || !strings.Contains(res.Reason, "exit status 3") {
+		t.Errorf("failing check: %+v", res)
+	}
+
+	p.Gate.Check = "sleep 5"
+	p.Gate.Timeout = "50ms"
+	start := time.Now()
+	if res, _ := e.Evaluate(p); res.Open || !strings.Contains(res.Reason, "timed out") {
+		t.Errorf("slow check: %+v", res)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("timeout did not stop the check")
+	}
+
+	p.Gate.Duration = "1h"
+	e.runsSince = func(string, string) (int, error) { return 1, nil }
+	e.runCheck = func(context.Context, string, string) error { return errors.New("should not run") }
+	if res, _ := e.Evaluate(p); res.Open || !strings.Contains(res.Reason, "last 1h") {
+		t.Errorf("cooldown should hold the check: %+v", res)
+	}
+}
+
+func TestGateEvaluator_ConditionBudget(t *testing.T) {
+	e := testEvaluator(t, time.Now())
+	e.checkBudget = 50 * time.Millisecond
+	slow := &Plugin{Name: "slow", Gate: &Gate{Type: GateCondition, Check: "sleep 5"}}
+
+	start := time.Now()
+	if res, _ := e.Evaluate(slow); res.Open || !strings.Contains(res.Reason, "deferred") {
+		t.Errorf("check over budget: %+v", res)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("budget did not stop the check")
+	}
+
+	e.runCheck = func(context.Context, string, string) error { return errors.New("should not run") }
+	next := &Plugin{Name: "next", Gate: &Gate{Type: GateCondition, Check: "true"}}
+	if res, _ := e.Evaluate(next); res.Open || !strings.Contains(res.Reason, "deferred") {
+		t.Errorf("check after budget is spent: %+v", res)
+	}
+}
+
+func TestGateEvaluator_Event(t *testing.T) {
+	e := testEvaluator(t, time.Now())
+	town := e.townRoot
+	p := &Plugin{Name: "post-merge", Gate: &Gate{Type: GateEvent, On: "merge, convoy-close"}}
+
+	if err := events.LogFeedTo(town, events.TypeMerged, "gastown/refinery", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.PollEvents(); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := e.Evaluate(p); res.Open {
+		t.Errorf("events before the plugin was first seen should not fire: %+v", res)
+	}
+
+	_ = events.LogFeedTo(town, events.TypeDaemonStart, "daemon", nil)
+	_ = events.LogFeedTo(town, events.TypeConvoyClosed, "mayor", nil)
+	_ = e.PollEvents()
+	res, _ := e.Evaluate(p)
+	if !res.Open || res.Reason != "event convoy_closed" {
+		t.Fatalf("after convoy close: %+v", res)
+	}
+	e.MarkDispatched(p)
+	if res, _ = e.Evaluate(p); res.Open {
+		t.Errorf("dispatch should consume the event: %+v", res)
+	}
+
+	// State survives a daemon restart.
+	if err := e.Save(); err != nil {
+		t.Fatal(err)
+	}
+	e2, err := NewGateEvaluator(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2.now = e.now
+	_ = events.LogFeedTo(town, events.TypeMerged, "gastown/refinery", nil)
+	_ = e2.PollEvents()
+	if res, _ = e2.Evaluate(p); !res.Open || res.Reason != "event merged" {
+		t.Errorf("after restart: %+v", res)
+	}
+
+	if _, err := e2.Evaluate(&Plugin{Name: "x", Gate: &Gate{Type: GateEvent}}); err == nil {
+		t.Error("event gate without on should error")
+	}
+}
+
+func TestGateEvaluator_ManualAndUnknown(t *testing.T) {
+	e := testEvaluator(t, time.Now())
+	if res, err := e.Evaluate(&Plugin{Name: "m"}); err != nil || res.Open {
+		t.Errorf("no gate: %+v, %v", res, err)
+	}
+	if res, _ := e.Evaluate(&Plugin{Name: "c", Gate: &Gate{Type: GateCooldown}}); !res.Open {
+		t.Errorf("cooldown without duration should be open: %+v", res)
+	}
+	if _, err := e.Evaluate(&Plugin{Name: "u", Gate: &Gate{Type: "weekly"}}); err == nil {
+		t.Error("unknown gate type should error")
+	}
+}
@@ -173,6 +173,12 @@ func parsePluginMD(content []byte, pluginDir string, location Location, rigName
 	if fm.Name == "" {
 		return nil, fmt.Errorf("missing required field: name")
 	}
+	if fm.Execution != nil && fm.Execution.Entrypoint != "" {
+		ep := filepath.Clean(fm.Execution.Entrypoint)
+		if filepath.IsAbs(ep) || ep == ".." || strings.HasPrefix(ep, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("execution.entrypoint %q must be inside the plugin directory", fm.Execution.Entrypoint)
+		}
+	}
 
 	plugin := &Plugin{
 		Name:         fm.Name,
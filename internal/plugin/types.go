@@ -1,7 +1,9 @@
 // Package plugin provides plugin discovery and management for Gas Town.
 //
 // Plugins are periodic automation tasks that run during Deacon patrol cycles.
-// Each plugin is defined by a plugin.md file with TOML frontmatter.
+// Each plugin is defined by a plugin.md file with TOML frontmatter. A plugin
+// either declares an executable entrypoint, which the daemon runs directly,
+// or is dispatched to a dog that follows its markdown instructions.
 //
 // Plugin locations:
 //   - Town-level: ~/gt/plugins/ (universal, apply everywhere)
@@ -62,16 +64,22 @@ type Gate struct {
 	// Type is the gate type: cooldown, cron, condition, event, or manual.
 	Type GateType `json:"type" toml:"type"`
 
-	// Duration is for cooldown gates (e.g., "1h", "24h").
+	// Duration is for cooldown gates (e.g., "1h", "24h"). Condition gates
+	// also honor it as a minimum interval between runs.
 	Duration string `json:"duration,omitempty" toml:"duration,omitempty"`
 
-	// Schedule is for cron gates (e.g., "0 9 * * *").
+	// Schedule is for cron gates (e.g., "0 9 * * *"), evaluated in the
+	// daemon's local time zone.
 	Schedule string `json:"schedule,omitempty" toml:"schedule,omitempty"`
 
 	// Check is for condition gates (command that returns exit 0 to run).
 	Check string `json:"check,omitempty" toml:"check,omitempty"`
 
-	// On is for event gates (e.g., "startup").
+	// Timeout bounds a condition gate's check command (default 30s).
+	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
+
+	// On is for event gates: a comma-separated list of event names
+	// (startup, merge, convoy-close, escalation) or raw event types.
 	On string `json:"on,omitempty" toml:"on,omitempty"`
 }
 
@@ -88,7 +96,7 @@ const (
 	// GateCondition runs if a check command returns exit 0.
 	GateCondition GateType = "condition"
 
-	// GateEvent runs on specific events (startup, etc).
+	// GateEvent runs when a subscribed event appears in the events feed.
 	GateEvent GateType = "event"
 
 	// GateManual never auto-runs, must be triggered explicitly.
@@ -106,6 +114,11 @@ type Tracking struct {
 
 // Execution defines plugin execution settings.
 type Execution struct {
+	// Entrypoint is a script or binary, relative to the plugin directory,
+	// that the daemon runs directly instead of dispatching a dog. See Exec
+	// for its stdin/stdout protocol.
+	Entrypoint string `json:"entrypoint,omitempty" toml:"entrypoint,omitempty"`
+
 	// Timeout is the maximum execution time (e.g., "5m").
 	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
 
@@ -133,6 +146,7 @@ type PluginSummary struct {
 	Location    Location `json:"location"`
 	RigName     string   `json:"rig_name,omitempty"`
 	GateType    GateType `json:"gate_type,omitempty"`
+	Executable  bool     `json:"executable,omitempty"`
 	Path        string   `json:"path"`
 }
 
@@ -151,10 +165,17 @@ func (p *Plugin) Summary() PluginSummary {
 		Location:    p.Location,
 		RigName:     p.RigName,
 		GateType:    gateType,
+		Executable:  p.IsExecutable(),
 		Path:        p.Path,
 	}
 }
 
+// IsExecutable reports whether the plugin declares an entrypoint the daemon
+// runs directly. Other plugins are dispatched to a dog.
+func (p *Plugin) IsExecutable() bool {
+	return p.Execution != nil && p.Execution.Entrypoint != ""
+}
+
 // FormatMailBody formats the plugin as instructions for a dog worker.
 // This is the canonical formatting used by both the daemon dispatcher
 // and the gt dog dispatch command.
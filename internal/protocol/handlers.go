@@ -85,7 +85,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMerged, func(msg *mail.Message) error {
-		payload, err := ParseMergedPayload(msg.Body)
+		payload, err := MergedPayloadFrom(msg)
 		if err != nil {
 			return err
 		}
@@ -93,7 +93,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeMergeFailed, func(msg *mail.Message) error {
-		payload, err := ParseMergeFailedPayload(msg.Body)
+		payload, err := MergeFailedPayloadFrom(msg)
 		if err != nil {
 			return err
 		}
@@ -101,7 +101,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeReworkRequest, func(msg *mail.Message) error {
-		payload, err := ParseReworkRequestPayload(msg.Body)
+		payload, err := ReworkRequestPayloadFrom(msg)
 		if err != nil {
 			return err
 		}
@@ -116,7 +116,7 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMergeReady, func(msg *mail.Message) error {
-		payload, err := ParseMergeReadyPayload(msg.Body)
+		payload, err := MergeReadyPayloadFrom(msg)
 		if err != nil {
 			return err
 		}
@@ -10,7 +10,7 @@ import (
 
 // NewMergeReadyMessage creates a MERGE_READY protocol message.
 // Sent by Witness to Refinery when a polecat's work is verified and ready.
-func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
+func NewMergeReadyMessage(rig, polecat, branch, issue string) (*mail.Message, error) {
 	payload := MergeReadyPayload{
 		Branch:    branch,
 		Issue:     issue,
@@ -31,7 +31,7 @@ func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
-	return msg
+	return msg, setPayload(msg, SchemaMergeReady, payload)
 }
 
 // formatMergeReadyBody formats the body of a MERGE_READY message.
@@ -49,7 +49,7 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 
 // NewMergedMessage creates a MERGED protocol message.
 // Sent by Refinery to Witness when a branch is successfully merged.
-func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) *mail.Message {
+func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) (*mail.Message, error) {
 	payload := MergedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -71,7 +71,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeNotification
 
-	return msg
+	return msg, setPayload(msg, SchemaMerged, payload)
 }
 
 // formatMergedBody formats the body of a MERGED message.
@@ -91,7 +91,8 @@ func formatMergedBody(p MergedPayload) string {
 
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
-func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
+// failedTests and artifacts come from the gate report and may be empty.
+func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string, failedTests []string, artifacts string) (*mail.Message, error) {
 	payload := MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -101,6 +102,8 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+		FailedTests:  failedTests,
+		Artifacts:    artifacts,
 	}
 
 	body := formatMergeFailedBody(payload)
@@ -114,7 +117,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
-	return msg
+	return msg, setPayload(msg, SchemaMergeFailed, payload)
 }
 
 // formatMergeFailedBody formats the body of a MERGE_FAILED message.
@@ -128,12 +131,18 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if len(p.FailedTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Failed-Tests: %s\n", strings.Join(p.FailedTests, ", ")))
+	}
+	if p.Artifacts != "" {
+		sb.WriteString(fmt.Sprintf("Artifacts: %s\n", p.Artifacts))
+	}
 	return sb.String()
 }
 
 // NewReworkRequestMessage creates a REWORK_REQUEST protocol message.
 // Sent by Refinery to Witness when a branch needs rebasing due to conflicts.
-func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, conflictFiles []string) *mail.Message {
+func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, conflictFiles []string) (*mail.Message, error) {
 	payload := ReworkRequestPayload{
 		Branch:        branch,
 		Issue:         issue,
@@ -156,7 +165,7 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
-	return msg
+	return msg, setPayload(msg, SchemaReworkRequest, payload)
 }
 
 // formatReworkRequestBody formats the body of a REWORK_REQUEST message.
@@ -194,7 +203,7 @@ The Refinery will retry the merge after rebase is complete.`, targetBranch, targ
 // NewConvoyNeedsFeedingMessage creates a CONVOY_NEEDS_FEEDING protocol message.
 // Sent by Refinery to Deacon after a convoy-eligible merge completes, so the
 // deacon can immediately feed the convoy instead of waiting for the next patrol.
-func NewConvoyNeedsFeedingMessage(rig, convoyID, sourceIssue string) *mail.Message {
+func NewConvoyNeedsFeedingMessage(rig, convoyID, sourceIssue string) (*mail.Message, error) {
 	payload := ConvoyNeedsFeedingPayload{
 		ConvoyID:    convoyID,
 		SourceIssue: sourceIssue,
@@ -213,7 +222,7 @@ func NewConvoyNeedsFeedingMessage(rig, convoyID, sourceIssue string) *mail.Messa
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
-	return msg
+	return msg, setPayload(msg, SchemaConvoyNeedsFeeding, payload)
 }
 
 // formatConvoyNeedsFeedingBody formats the body of a CONVOY_NEEDS_FEEDING message.
@@ -331,6 +340,11 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		Artifacts:    parseField(body, "Artifacts"),
+	}
+
+	if tests := parseField(body, "Failed-Tests"); tests != "" {
+		payload.FailedTests = strings.Split(tests, ", ")
 	}
 
 	// Parse timestamp
@@ -419,6 +433,55 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 	return payload
 }
 
+// setPayload attaches the typed payload to a protocol message. When the
+// payload can't be attached (say, a huge error message pushes it over
+// mail.MaxPayloadSize), the message is left without one and the error says
+// why; the message is still sendable, since receivers fall back to the body.
+func setPayload(msg *mail.Message, schema string, v any) error {
+	p, err := mail.NewPayload(schema, v)
+	if err != nil {
+		return err
+	}
+	if len(p.Data) > mail.MaxPayloadSize {
+		return fmt.Errorf("dropped %s payload: %d bytes (max %d)", schema, len(p.Data), mail.MaxPayloadSize)
+	}
+	msg.Payload = p
+	return nil
+}
+
+// payloadFrom decodes a message's typed payload when it has the given
+// schema, and otherwise parses the body with parse. Messages from senders
+// predating payloads only have the body.
+func payloadFrom[T any](msg *mail.Message, schema string, parse func(body string) (*T, error)) (*T, error) {
+	if msg.Payload != nil && msg.Payload.Schema == schema {
+		var payload T
+		if err := msg.Payload.Decode(schema, &payload); err == nil {
+			return &payload, nil
+		}
+	}
+	return parse(msg.Body)
+}
+
+// MergeReadyPayloadFrom returns the payload of a MERGE_READY message.
+func MergeReadyPayloadFrom(msg *mail.Message) (*MergeReadyPayload, error) {
+	return payloadFrom(msg, SchemaMergeReady, ParseMergeReadyPayload)
+}
+
+// MergedPayloadFrom returns the payload of a MERGED message.
+func MergedPayloadFrom(msg *mail.Message) (*MergedPayload, error) {
+	return payloadFrom(msg, SchemaMerged, ParseMergedPayload)
+}
+
+// MergeFailedPayloadFrom returns the payload of a MERGE_FAILED message.
+func MergeFailedPayloadFrom(msg *mail.Message) (*MergeFailedPayload, error) {
+	return payloadFrom(msg, SchemaMergeFailed, ParseMergeFailedPayload)
+}
+
+// ReworkRequestPayloadFrom returns the payload of a REWORK_REQUEST message.
+func ReworkRequestPayloadFrom(msg *mail.Message) (*ReworkRequestPayload, error) {
+	return payloadFrom(msg, SchemaReworkRequest, ParseReworkRequestPayload)
+}
+
 // parseField extracts a field value from a key-value body format.
 // Format: "Key: value"
 func parseField(body, key string) string {
@@ -89,7 +89,7 @@ func TestIsProtocolMessage(t *testing.T) {
 }
 
 func TestNewMergeReadyMessage(t *testing.T) {
-	msg := NewMergeReadyMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc")
+	msg, _ := NewMergeReadyMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc")
 
 	if msg.Subject != "MERGE_READY nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGE_READY nux")
@@ -112,7 +112,7 @@ func TestNewMergeReadyMessage(t *testing.T) {
 }
 
 func TestNewMergedMessage(t *testing.T) {
-	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	msg, _ := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
 
 	if msg.Subject != "MERGED nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGED nux")
@@ -129,7 +129,7 @@ func TestNewMergedMessage(t *testing.T) {
 }
 
 func TestNewMergeFailedMessage(t *testing.T) {
-	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
+	msg, _ := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed", nil, "")
 
 	if msg.Subject != "MERGE_FAILED nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGE_FAILED nux")
@@ -142,9 +142,35 @@ func TestNewMergeFailedMessage(t *testing.T) {
 	}
 }
 
+func TestMergeFailedMessage_GateReportRoundTrip(t *testing.T) {
+	failed := []string{"example.com/pkg.TestA", "example.com/pkg.TestB/sub"}
+	msg, _ := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "quality gates failed",
+		failed, "/town/gastown/.runtime/refinery/gates/gt-mr1")
+
+	if !strings.Contains(msg.Body, "Failed-Tests: example.com/pkg.TestA, example.com/pkg.TestB/sub") {
+		t.Errorf("Body missing failed tests: %s", msg.Body)
+	}
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(payload.FailedTests, "|") != strings.Join(failed, "|") {
+		t.Errorf("FailedTests = %v, want %v", payload.FailedTests, failed)
+	}
+	if payload.Artifacts != "/town/gastown/.runtime/refinery/gates/gt-mr1" {
+		t.Errorf("Artifacts = %q", payload.Artifacts)
+	}
+
+	plain, _ := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "build", "boom", nil, "")
+	if strings.Contains(plain.Body, "Failed-Tests:") || strings.Contains(plain.Body, "Artifacts:") {
+		t.Errorf("empty gate report should be omitted: %s", plain.Body)
+	}
+}
+
 func TestNewReworkRequestMessage(t *testing.T) {
 	conflicts := []string{"file1.go", "file2.go"}
-	msg := NewReworkRequestMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", conflicts)
+	msg, _ := NewReworkRequestMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", conflicts)
 
 	if msg.Subject != "REWORK_REQUEST nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "REWORK_REQUEST nux")
@@ -204,7 +230,7 @@ func TestParseMessageType_ConvoyNeedsFeeding(t *testing.T) {
 }
 
 func TestNewConvoyNeedsFeedingMessage(t *testing.T) {
-	msg := NewConvoyNeedsFeedingMessage("gastown", "hq-cv123", "gt-abc")
+	msg, _ := NewConvoyNeedsFeedingMessage("gastown", "hq-cv123", "gt-abc")
 
 	if msg.Subject != "CONVOY_NEEDS_FEEDING hq-cv123" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "CONVOY_NEEDS_FEEDING hq-cv123")
@@ -771,3 +797,69 @@ func TestMergeOutcome_Fields(t *testing.T) {
 		t.Errorf("MergeCommit = %q, want %q", outcome.MergeCommit, "abc123")
 	}
 }
+
+func TestNewMergeFailedMessage_Payload(t *testing.T) {
+	msg, _ := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "exit 1", []string{"TestFoo"}, "/tmp/gate")
+
+	if msg.Payload == nil || msg.Payload.Schema != SchemaMergeFailed {
+		t.Fatalf("Payload = %+v, want schema %s", msg.Payload, SchemaMergeFailed)
+	}
+
+	// The payload wins over the body when both are present
+	msg.Body = "garbage"
+	payload, err := MergeFailedPayloadFrom(msg)
+	if err != nil {
+		t.Fatalf("MergeFailedPayloadFrom: %v", err)
+	}
+	if payload.Branch != "polecat/nux/gt-abc" || payload.FailureType != "tests" ||
+		len(payload.FailedTests) != 1 || payload.Artifacts != "/tmp/gate" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestNewMergeFailedMessage_OversizedPayloadDropped(t *testing.T) {
+	huge := strings.Repeat("x", mail.MaxPayloadSize)
+	msg, err := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", huge, nil, "")
+	if err == nil {
+		t.Error("expected an error reporting the dropped payload")
+	}
+	if msg.Payload != nil {
+		t.Fatalf("oversized payload should be dropped, got %d bytes", len(msg.Payload.Data))
+	}
+	// The message still sends, and receivers fall back to the body
+	payload, err := MergeFailedPayloadFrom(msg)
+	if err != nil {
+		t.Fatalf("MergeFailedPayloadFrom: %v", err)
+	}
+	if payload.Branch != "polecat/nux/gt-abc" || payload.FailureType != "tests" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestPayloadFrom_FallsBackToBody(t *testing.T) {
+	msg, _ := NewMergeReadyMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc")
+	msg.Payload = nil // as sent by an older gt
+
+	payload, err := MergeReadyPayloadFrom(msg)
+	if err != nil {
+		t.Fatalf("MergeReadyPayloadFrom: %v", err)
+	}
+	if payload.Branch != "polecat/nux/gt-abc" || payload.Rig != "gastown" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// A payload with another schema is ignored
+	msg.Payload, _ = mail.NewPayload(SchemaMerged, MergedPayload{Branch: "other"})
+	payload, err = MergeReadyPayloadFrom(msg)
+	if err != nil {
+		t.Fatalf("MergeReadyPayloadFrom: %v", err)
+	}
+	if payload.Branch != "polecat/nux/gt-abc" {
+		t.Errorf("Branch = %q, want body value", payload.Branch)
+	}
+
+	msg.Body = ""
+	if _, err := MergeReadyPayloadFrom(msg); err == nil {
+		t.Error("expected error with no payload and empty body")
+	}
+}
@@ -73,21 +73,28 @@ func (h *DefaultRefineryHandler) HandleMergeReady(payload *MergeReadyPayload) er
 // SendMerged sends a MERGED message to the Witness.
 // Called by the Refinery after successfully merging a branch.
 func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit string) error {
-	msg := NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit)
-	return h.Router.Send(msg)
+	return h.send(NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit))
 }
 
 // SendMergeFailed sends a MERGE_FAILED message to the Witness.
 // Called by the Refinery when a merge fails.
-func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string) error {
-	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg)
-	return h.Router.Send(msg)
+func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string, failedTests []string, artifacts string) error {
+	return h.send(NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg, failedTests, artifacts))
 }
 
 // SendReworkRequest sends a REWORK_REQUEST message to the Witness.
 // Called by the Refinery when a branch has conflicts.
 func (h *DefaultRefineryHandler) SendReworkRequest(polecat, branch, issue, targetBranch string, conflictFiles []string) error {
-	msg := NewReworkRequestMessage(h.Rig, polecat, branch, issue, targetBranch, conflictFiles)
+	return h.send(NewReworkRequestMessage(h.Rig, polecat, branch, issue, targetBranch, conflictFiles))
+}
+
+// send sends a protocol message to the Witness. A payload that couldn't be
+// attached is reported but doesn't stop the send: the body carries the same
+// fields.
+func (h *DefaultRefineryHandler) send(msg *mail.Message, payloadErr error) error {
+	if payloadErr != nil {
+		_, _ = fmt.Fprintf(h.Output, "[Refinery] Warning: %v (witness will parse the body)\n", payloadErr)
+	}
 	return h.Router.Send(msg)
 }
 
@@ -111,6 +118,12 @@ type MergeOutcome struct {
 
 	// ConflictFiles lists files with conflicts (if Conflict is true).
 	ConflictFiles []string
+
+	// FailedTests lists failed test IDs from the gate report (if any).
+	FailedTests []string
+
+	// Artifacts is the directory holding full gate logs (if written).
+	Artifacts string
 }
 
 // NotifyMergeOutcome sends the appropriate protocol message based on the outcome.
@@ -123,7 +136,7 @@ func (h *DefaultRefineryHandler) NotifyMergeOutcome(polecat, branch, issue, targ
 		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles)
 	}
 
-	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error)
+	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error, outcome.FailedTests, outcome.Artifacts)
 }
 
 // Ensure DefaultRefineryHandler implements RefineryHandler.
@@ -46,6 +46,19 @@ const (
 	TypeConvoyNeedsFeeding MessageType = "CONVOY_NEEDS_FEEDING"
 )
 
+// Payload schemas. Protocol messages carry their payload as typed JSON
+// (mail.Payload) in addition to the human-readable body; receivers decode
+// the payload when present and fall back to parsing the body otherwise.
+// Message constructors return the message together with any error attaching
+// the payload; the message is sendable either way.
+const (
+	SchemaMergeReady         = "gastown.protocol.merge_ready.v1"
+	SchemaMerged             = "gastown.protocol.merged.v1"
+	SchemaMergeFailed        = "gastown.protocol.merge_failed.v1"
+	SchemaReworkRequest      = "gastown.protocol.rework_request.v1"
+	SchemaConvoyNeedsFeeding = "gastown.protocol.convoy_needs_feeding.v1"
+)
+
 // ParseMessageType extracts the protocol message type from a mail subject.
 // Returns empty string if subject doesn't match a known protocol type.
 func ParseMessageType(subject string) MessageType {
@@ -143,6 +156,12 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// FailedTests lists failed test IDs parsed from gate test reports.
+	FailedTests []string `json:"failed_tests,omitempty"`
+
+	// Artifacts is the directory holding full gate logs and report.json.
+	Artifacts string `json:"artifacts,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -0,0 +1,174 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Budget periods.
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+const (
+	// BurnWindow is the trailing window the burn rate is measured over.
+	BurnWindow = time.Hour
+
+	// DefaultBudgetLookahead is how far ahead the scheduler projects usage
+	// when budgets don't set a lookahead.
+	DefaultBudgetLookahead = time.Hour
+)
+
+// Forecast is the state of one budget: usage so far this period, the
+// current burn rate and when the budget runs out at that rate.
+type Forecast struct {
+	Scope       string    `json:"scope"` // "account" or "rig"
+	Name        string    `json:"name"`
+	Period      string    `json:"period"` // PeriodDaily or PeriodWeekly
+	Budget      int64     `json:"budget"`
+	Used        int64     `json:"used"`
+	BurnPerHour float64   `json:"burn_per_hour"`
+	ExhaustsAt  time.Time `json:"exhausts_at,omitzero"` // zero if the budget lasts the period
+	ResetsAt    time.Time `json:"resets_at"`
+}
+
+// Exceeded reports whether the budget is used up.
+func (f Forecast) Exceeded() bool {
+	return f.Used >= f.Budget
+}
+
+// Projected returns the usage expected lookahead from now at the current
+// burn rate, not projecting past the end of the period.
+func (f Forecast) Projected(now time.Time, lookahead time.Duration) int64 {
+	if remaining := f.ResetsAt.Sub(now); remaining < lookahead {
+		lookahead = max(remaining, 0)
+	}
+	return f.Used + int64(f.BurnPerHour*lookahead.Hours())
+}
+
+// PeriodBounds returns the start and end of the budget period containing
+// now, in now's location. Weeks start on Monday.
+func PeriodBounds(period string, now time.Time) (start, end time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if period == PeriodWeekly {
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		start = day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	}
+	return day, day.AddDate(0, 0, 1)
+}
+
+// UsageSince returns the cutoff CollectUsage needs to forecast every
+// budget period at now.
+func UsageSince(now time.Time) time.Time {
+	weekStart, _ := PeriodBounds(PeriodWeekly, now)
+	if burnStart := now.Add(-BurnWindow); burnStart.Before(weekStart) {
+		return burnStart
+	}
+	return weekStart
+}
+
+// ForecastUsage computes the forecast of a budget over samples.
+func ForecastUsage(samples []UsageSample, budget int64, period string, now time.Time) Forecast {
+	start, end := PeriodBounds(period, now)
+	burnStart := now.Add(-BurnWindow)
+	f := Forecast{Period: period, Budget: budget, ResetsAt: end}
+	var burned int64
+	for _, s := range samples {
+		if s.Time.After(now) {
+			continue
+		}
+		if !s.Time.Before(start) {
+			f.Used += s.Tokens
+		}
+		if s.Time.After(burnStart) {
+			burned += s.Tokens
+		}
+	}
+	f.BurnPerHour = float64(burned) / BurnWindow.Hours()
+
+	switch {
+	case f.Exceeded():
+		f.ExhaustsAt = now
+	case f.BurnPerHour > 0:
+		hours := float64(budget-f.Used) / f.BurnPerHour
+		if at := now.Add(time.Duration(hours * float64(time.Hour))); at.Before(end) {
+			f.ExhaustsAt = at
+		}
+	}
+	return f
+}
+
+// Forecasts returns a forecast for every configured budget, sorted by scope,
+// name and period.
+func (l *UsageLedger) Forecasts(budgets *config.BudgetConfig, now time.Time) []Forecast {
+	if budgets == nil {
+		return nil
+	}
+	var out []Forecast
+	add := func(scope string, limits map[string]*config.TokenBudget, samples map[string][]UsageSample) {
+		for name, b := range limits {
+			if b == nil {
+				continue
+			}
+			for _, p := range []struct {
+				period string
+				limit  int64
+			}{{PeriodDaily, b.DailyTokens}, {PeriodWeekly, b.WeeklyTokens}} {
+				if p.limit <= 0 {
+					continue
+				}
+				f := ForecastUsage(samples[name], p.limit, p.period, now)
+				f.Scope, f.Name = scope, name
+				out = append(out, f)
+			}
+		}
+	}
+	add("account", budgets.Accounts, l.Accounts)
+	add("rig", budgets.Rigs, l.Rigs)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope < out[j].Scope
+		}
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Period < out[j].Period
+	})
+	return out
+}
+
+// BudgetLookahead returns the configured lookahead or the default (1h).
+func BudgetLookahead(budgets *config.BudgetConfig) time.Duration {
+	if budgets != nil && budgets.Lookahead != "" {
+		if d, err := time.ParseDuration(budgets.Lookahead); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return DefaultBudgetLookahead
+}
+
+// CheckDispatch reports whether new work on account and rig fits the
+// budgets: no budget may be used up or projected to run out within the
+// lookahead at the current burn rate. Empty account or rig are not checked.
+// The error describes the first budget that blocks dispatch.
+func (l *UsageLedger) CheckDispatch(budgets *config.BudgetConfig, account, rig string, now time.Time) error {
+	lookahead := BudgetLookahead(budgets)
+	for _, f := range l.Forecasts(budgets, now) {
+		if (f.Scope == "account" && f.Name != account) || (f.Scope == "rig" && f.Name != rig) || f.Name == "" {
+			continue
+		}
+		if f.Exceeded() {
+			return fmt.Errorf("%s %s %s budget exhausted (%d/%d tokens)", f.Scope, f.Name, f.Period, f.Used, f.Budget)
+		}
+		if projected := f.Projected(now, lookahead); projected > f.Budget {
+			return fmt.Errorf("%s %s projected to exceed %s budget within %s (%d/%d tokens)",
+				f.Scope, f.Name, f.Period, lookahead, projected, f.Budget)
+		}
+	}
+	return nil
+}
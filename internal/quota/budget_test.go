@@ -0,0 +1,111 @@
+package quota
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	// 2026-02-18 is a Wednesday.
+	now := time.Date(2026, 2, 18, 15, 30, 0, 0, time.UTC)
+
+	start, end := PeriodBounds(PeriodDaily, now)
+	if !start.Equal(time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 2, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily = %v - %v", start, end)
+	}
+	start, end = PeriodBounds(PeriodWeekly, now)
+	if !start.Equal(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weekly = %v - %v", start, end)
+	}
+	sunday := time.Date(2026, 2, 22, 23, 0, 0, 0, time.UTC)
+	if start, _ = PeriodBounds(PeriodWeekly, sunday); start.Day() != 16 {
+		t.Errorf("Sunday belongs to the week starting Monday the 16th, got %v", start)
+	}
+}
+
+func TestForecastUsage(t *testing.T) {
+	now := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	samples := []UsageSample{
+		{Time: now.Add(-13 * time.Hour), Tokens: 5000}, // yesterday
+		{Time: now.Add(-3 * time.Hour), Tokens: 2000},
+		{Time: now.Add(-30 * time.Minute), Tokens: 1000}, // in the burn window
+	}
+
+	f := ForecastUsage(samples, 10000, PeriodDaily, now)
+	if f.Used != 3000 || f.BurnPerHour != 1000 {
+		t.Fatalf("Used = %d, BurnPerHour = %v", f.Used, f.BurnPerHour)
+	}
+	if want := now.Add(7 * time.Hour); !f.ExhaustsAt.Equal(want) {
+		t.Errorf("ExhaustsAt = %v, want %v", f.ExhaustsAt, want)
+	}
+	if got := f.Projected(now, 2*time.Hour); got != 5000 {
+		t.Errorf("Projected(2h) = %d, want 5000", got)
+	}
+
+	// Outlasts the period: no exhaustion time.
+	if f = ForecastUsage(samples, 100000, PeriodDaily, now); !f.ExhaustsAt.IsZero() {
+		t.Errorf("ExhaustsAt = %v, want zero", f.ExhaustsAt)
+	}
+
+	// Projection stops at the end of the period.
+	late := time.Date(2026, 2, 18, 23, 30, 0, 0, time.UTC)
+	f = ForecastUsage([]UsageSample{{Time: late.Add(-time.Minute), Tokens: 1000}}, 10000, PeriodDaily, late)
+	if got := f.Projected(late, 4*time.Hour); got != 1500 {
+		t.Errorf("Projected past reset = %d, want 1500", got)
+	}
+
+	if f = ForecastUsage(samples, 8000, PeriodWeekly, now); !f.Exceeded() || !f.ExhaustsAt.Equal(now) {
+		t.Errorf("weekly forecast should be exhausted: %+v", f)
+	}
+}
+
+func TestCheckDispatch(t *testing.T) {
+	now := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	ledger := &UsageLedger{
+		Accounts: map[string][]UsageSample{
+			"work":     {{Time: now.Add(-10 * time.Minute), Tokens: 900}},
+			"personal": {{Time: now.Add(-5 * time.Hour), Tokens: 100}},
+		},
+		Rigs: map[string][]UsageSample{
+			"gastown": {{Time: now.Add(-2 * time.Hour), Tokens: 5000}},
+		},
+	}
+	budgets := &config.BudgetConfig{
+		Accounts: map[string]*config.TokenBudget{
+			"work":     {DailyTokens: 1500},
+			"personal": {DailyTokens: 1500},
+		},
+		Rigs: map[string]*config.TokenBudget{"gastown": {WeeklyTokens: 5000}},
+	}
+
+	tests := []struct {
+		account, rig string
+		wantErr      string
+	}{
+		{"personal", "beads", ""},
+		{"work", "beads", "projected to exceed daily budget"},
+		{"personal", "gastown", "weekly budget exhausted"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		err := ledger.CheckDispatch(budgets, tt.account, tt.rig, now)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckDispatch(%q, %q) = %v, want nil", tt.account, tt.rig, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckDispatch(%q, %q) = %v, want %q", tt.account, tt.rig, err, tt.wantErr)
+		}
+	}
+
+	// A shorter lookahead lets the work account through.
+	budgets.Lookahead = "30m"
+	if err := ledger.CheckDispatch(budgets, "work", "", now); err != nil {
+		t.Errorf("with 30m lookahead: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -125,10 +126,11 @@ func (m *Manager) MarkLimited(handle string, resetsAt string) error {
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	state.Accounts[handle] = config.AccountQuotaState{
-		Status:    config.QuotaStatusLimited,
-		LimitedAt: now,
-		ResetsAt:  resetsAt,
-		LastUsed:  state.Accounts[handle].LastUsed,
+		Status:      config.QuotaStatusLimited,
+		LimitedAt:   now,
+		ResetsAt:    resetsAt,
+		LastUsed:    state.Accounts[handle].LastUsed,
+		LastLimited: now,
 	}
 
 	return util.EnsureDirAndWriteJSON(m.statePath(), state)
@@ -149,15 +151,17 @@ func (m *Manager) MarkAvailable(handle string) error {
 
 	existing := state.Accounts[handle]
 	state.Accounts[handle] = config.AccountQuotaState{
-		Status:   config.QuotaStatusAvailable,
-		LastUsed: existing.LastUsed,
+		Status:      config.QuotaStatusAvailable,
+		LastUsed:    existing.LastUsed,
+		LastLimited: existing.LastLimited,
 	}
 
 	return util.EnsureDirAndWriteJSON(m.statePath(), state)
 }
 
 // AvailableAccounts returns account handles that are not rate-limited,
-// sorted by least-recently-used first.
+// sorted least-recently-limited first, then least-recently-used. Accounts
+// that have never been limited come first.
 func (m *Manager) AvailableAccounts(state *config.QuotaState) []string {
 	var available []string
 	for handle, acctState := range state.Accounts {
@@ -165,8 +169,12 @@ func (m *Manager) AvailableAccounts(state *config.QuotaState) []string {
 			available = append(available, handle)
 		}
 	}
-	// Sort by LastUsed ascending (least recently used first)
+	// Sort by LastUsed ascending (least recently used first), then stably
+	// by LastLimited so recently exhausted accounts go to the back.
 	sortByLastUsed(available, state)
+	sort.SliceStable(available, func(i, j int) bool {
+		return state.Accounts[available[i]].LastLimited < state.Accounts[available[j]].LastLimited
+	})
 	return available
 }
 
@@ -208,7 +216,8 @@ func (m *Manager) EnsureAccountsTracked(state *config.QuotaState, accounts map[s
 }
 
 // ClearExpired checks all limited accounts and marks them available if their
-// ResetsAt time has passed. Returns the number of accounts cleared.
+// ResetsAt time has passed, and ends cooldowns whose CooldownUntil has
+// passed. Returns the number of accounts cleared.
 // The caller is responsible for persisting state if changes were made.
 func (m *Manager) ClearExpired(state *config.QuotaState) int {
 	return clearExpiredAt(m, state, time.Now())
@@ -218,20 +227,31 @@ func (m *Manager) ClearExpired(state *config.QuotaState) int {
 func clearExpiredAt(_ *Manager, state *config.QuotaState, now time.Time) int {
 	cleared := 0
 	for handle, acctState := range state.Accounts {
-		if acctState.Status != config.QuotaStatusLimited {
-			continue
-		}
-		if acctState.ResetsAt == "" {
+		var resetTime time.Time
+		switch acctState.Status {
+		case config.QuotaStatusLimited:
+			if acctState.ResetsAt == "" {
+				continue
+			}
+			t, err := ParseResetTime(acctState.ResetsAt, now)
+			if err != nil {
+				continue // can't parse — leave as-is
+			}
+			resetTime = t
+		case config.QuotaStatusCooldown:
+			t, err := time.Parse(time.RFC3339, acctState.CooldownUntil)
+			if err != nil {
+				continue // no deadline — leave for gt quota clear
+			}
+			resetTime = t
+		default:
 			continue
 		}
-		resetTime, err := ParseResetTime(acctState.ResetsAt, now)
-		if err != nil {
-			continue // can't parse — leave as-is
-		}
 		if now.After(resetTime) {
 			state.Accounts[handle] = config.AccountQuotaState{
-				Status:   config.QuotaStatusAvailable,
-				LastUsed: acctState.LastUsed,
+				Status:      config.QuotaStatusAvailable,
+				LastUsed:    acctState.LastUsed,
+				LastLimited: acctState.LastLimited,
 			}
 			cleared++
 		}
@@ -239,6 +259,44 @@ func clearExpiredAt(_ *Manager, state *config.QuotaState, now time.Time) int {
 	return cleared
 }
 
+// DefaultCooldown is how long an exhausted account stays in cooldown when
+// its reset time is unknown or can't be parsed. It matches the provider's
+// rolling usage window.
+const DefaultCooldown = 5 * time.Hour
+
+// CooldownDeadline returns when an account limited at limitedAt becomes
+// usable again. resetsAt is the provider's reset time as scraped from the
+// pane (e.g. "7pm (America/Los_Angeles)"); a time of day already past at
+// limitedAt means tomorrow. Unparseable or empty values fall back to
+// limitedAt + fallback.
+func CooldownDeadline(resetsAt string, limitedAt time.Time, fallback time.Duration) time.Time {
+	if resetsAt != "" {
+		if t, err := ParseResetTime(resetsAt, limitedAt); err == nil {
+			if !t.After(limitedAt) {
+				t = t.AddDate(0, 0, 1)
+			}
+			return t
+		}
+	}
+	return limitedAt.Add(fallback)
+}
+
+// StartCooldown puts an exhausted account in cooldown until its reset time,
+// mutating state in memory. The caller persists state (normally under
+// WithLock).
+func (m *Manager) StartCooldown(state *config.QuotaState, handle, resetsAt string, now time.Time, fallback time.Duration) {
+	existing := state.Accounts[handle]
+	stamp := now.UTC().Format(time.RFC3339)
+	state.Accounts[handle] = config.AccountQuotaState{
+		Status:        config.QuotaStatusCooldown,
+		LimitedAt:     stamp,
+		ResetsAt:      resetsAt,
+		LastUsed:      existing.LastUsed,
+		CooldownUntil: CooldownDeadline(resetsAt, now, fallback).UTC().Format(time.RFC3339),
+		LastLimited:   stamp,
+	}
+}
+
 // parseResetTimePattern matches formats like "7pm", "11am", "3:30pm", "7:00pm"
 var parseResetTimePattern = regexp.MustCompile(`(?i)^(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b`)
 
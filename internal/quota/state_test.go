@@ -510,3 +510,103 @@ func TestClearExpired_NoResetsAt(t *testing.T) {
 		t.Errorf("expected no_reset to remain limited")
 	}
 }
+
+func TestClearExpired_EndsCooldown(t *testing.T) {
+	now := time.Date(2026, 2, 18, 15, 0, 0, 0, time.UTC)
+	mgr := NewManager("/tmp/unused")
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"cooled": {
+				Status:        config.QuotaStatusCooldown,
+				CooldownUntil: "2026-02-18T14:00:00Z",
+				LastLimited:   "2026-02-18T09:00:00Z",
+			},
+			"cooling": {
+				Status:        config.QuotaStatusCooldown,
+				CooldownUntil: "2026-02-18T16:00:00Z",
+			},
+			"manual": {
+				Status: config.QuotaStatusCooldown, // no deadline — left for gt quota clear
+			},
+		},
+	}
+
+	if cleared := clearExpiredAt(mgr, state, now); cleared != 1 {
+		t.Errorf("expected 1 cleared, got %d", cleared)
+	}
+	if got := state.Accounts["cooled"]; got.Status != config.QuotaStatusAvailable || got.LastLimited != "2026-02-18T09:00:00Z" {
+		t.Errorf("cooled = %+v, want available with LastLimited preserved", got)
+	}
+	if state.Accounts["cooling"].Status != config.QuotaStatusCooldown {
+		t.Errorf("cooling should stay in cooldown")
+	}
+	if state.Accounts["manual"].Status != config.QuotaStatusCooldown {
+		t.Errorf("manual should stay in cooldown")
+	}
+}
+
+func TestCooldownDeadline(t *testing.T) {
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	limitedAt := time.Date(2026, 2, 18, 15, 0, 0, 0, la)
+
+	tests := []struct {
+		name     string
+		resetsAt string
+		want     time.Time
+	}{
+		{"later today", "7pm (America/Los_Angeles)", time.Date(2026, 2, 18, 19, 0, 0, 0, la)},
+		{"already past means tomorrow", "11am (America/Los_Angeles)", time.Date(2026, 2, 19, 11, 0, 0, 0, la)},
+		{"unknown", "", limitedAt.Add(DefaultCooldown)},
+		{"unparseable", "soon", limitedAt.Add(DefaultCooldown)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CooldownDeadline(tt.resetsAt, limitedAt, DefaultCooldown); !got.Equal(tt.want) {
+				t.Errorf("CooldownDeadline(%q) = %v, want %v", tt.resetsAt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartCooldown(t *testing.T) {
+	now := time.Date(2026, 2, 18, 15, 0, 0, 0, time.UTC)
+	mgr := NewManager("/tmp/unused")
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"work": {Status: config.QuotaStatusLimited, LastUsed: "2026-02-18T10:00:00Z"},
+		},
+	}
+
+	mgr.StartCooldown(state, "work", "", now, time.Hour)
+
+	got := state.Accounts["work"]
+	if got.Status != config.QuotaStatusCooldown {
+		t.Errorf("status = %s, want cooldown", got.Status)
+	}
+	if got.CooldownUntil != "2026-02-18T16:00:00Z" {
+		t.Errorf("CooldownUntil = %q", got.CooldownUntil)
+	}
+	if got.LastLimited != "2026-02-18T15:00:00Z" || got.LastUsed != "2026-02-18T10:00:00Z" {
+		t.Errorf("timestamps not recorded: %+v", got)
+	}
+	if avail := mgr.AvailableAccounts(state); len(avail) != 0 {
+		t.Errorf("account in cooldown should not be available, got %v", avail)
+	}
+}
+
+func TestAvailableAccounts_LeastRecentlyLimitedFirst(t *testing.T) {
+	mgr := NewManager("/tmp/unused")
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"recent": {Status: config.QuotaStatusAvailable, LastUsed: "2026-02-18T08:00:00Z", LastLimited: "2026-02-18T12:00:00Z"},
+			"older":  {Status: config.QuotaStatusAvailable, LastUsed: "2026-02-18T09:00:00Z", LastLimited: "2026-02-17T12:00:00Z"},
+			"never":  {Status: config.QuotaStatusAvailable, LastUsed: "2026-02-18T11:00:00Z"},
+		},
+	}
+
+	got := mgr.AvailableAccounts(state)
+	want := []string{"never", "older", "recent"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("AvailableAccounts = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package quota
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// TokenUsage is token consumption reported by Claude Code transcripts.
+type TokenUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+}
+
+// Add accumulates other into u.
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.InputTokens += other.InputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+	u.OutputTokens += other.OutputTokens
+}
+
+// Billable returns the tokens counted against budgets: input, cache
+// creation and output. Cache reads are excluded; they dwarf everything
+// else in long sessions while costing a tenth of fresh input.
+func (u TokenUsage) Billable() int64 {
+	return u.InputTokens + u.CacheCreationInputTokens + u.OutputTokens
+}
+
+// TranscriptEntry is one assistant message with usage from a transcript.
+type TranscriptEntry struct {
+	Time  time.Time // zero if the line has no timestamp
+	Model string
+	CWD   string
+	Usage TokenUsage
+}
+
+// transcriptLine is the subset of a Claude Code transcript line we read.
+type transcriptLine struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	CWD       string `json:"cwd"`
+	Message   *struct {
+		Model string      `json:"model"`
+		Usage *TokenUsage `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// maxTranscriptLine caps the transcript lines ReadTranscript parses. Longer
+// lines carry inlined tool output, never assistant usage, and are skipped.
+const maxTranscriptLine = 1024 * 1024
+
+// ReadTranscript calls fn for every assistant message with usage info in a
+// Claude Code transcript (.jsonl). Malformed and over-long lines are skipped.
+func ReadTranscript(path string, fn func(TranscriptEntry)) error {
+	file, err := os.Open(path) //nolint:gosec // G304: transcript paths come from Claude config dirs
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 256*1024)
+	var line []byte
+	tooLong := false
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+			tooLong = len(line) > maxTranscriptLine
+		}
+		if isPrefix {
+			continue
+		}
+		if tooLong || len(line) == 0 {
+			line, tooLong = line[:0], false
+			continue
+		}
+		parseTranscriptLine(line, fn)
+		line = line[:0]
+	}
+}
+
+// parseTranscriptLine calls fn if line is an assistant message with usage.
+func parseTranscriptLine(line []byte, fn func(TranscriptEntry)) {
+	var msg transcriptLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+	if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+		return
+	}
+	entry := TranscriptEntry{
+		Model: msg.Message.Model,
+		CWD:   msg.CWD,
+		Usage: *msg.Message.Usage,
+	}
+	if msg.Timestamp != "" {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, msg.Timestamp)
+	}
+	fn(entry)
+}
+
+// UsageSample is billable tokens consumed by one message.
+type UsageSample struct {
+	Time   time.Time
+	Tokens int64
+}
+
+// UsageLedger holds usage samples since a cutoff, grouped by account handle
+// and by rig name.
+type UsageLedger struct {
+	Since    time.Time
+	Accounts map[string][]UsageSample
+	Rigs     map[string][]UsageSample
+
+	// Unreadable lists transcripts that could not be read; their usage is
+	// missing from the ledger.
+	Unreadable []error
+}
+
+// CollectUsage reads the transcripts of every registered account (and the
+// default ~/.claude) and returns the usage recorded since the cutoff.
+// Messages are attributed to a rig when their working directory is inside
+// one of the named rigs under townRoot. acctCfg may be nil. Transcripts
+// (or config dirs) that can't be read are recorded in Unreadable and
+// skipped, so one bad file doesn't hide everyone else's usage.
+func CollectUsage(townRoot string, acctCfg *config.AccountsConfig, rigs []string, since time.Time) (*UsageLedger, error) {
+	ledger := &UsageLedger{
+		Since:    since,
+		Accounts: make(map[string][]UsageSample),
+		Rigs:     make(map[string][]UsageSample),
+	}
+	rigSet := make(map[string]bool, len(rigs))
+	for _, r := range rigs {
+		rigSet[r] = true
+	}
+
+	// Config dir -> account handle ("" for sessions outside any account).
+	dirs := make(map[string]string)
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs[filepath.Join(home, ".claude")] = ""
+	}
+	if acctCfg != nil {
+		for handle, acct := range acctCfg.Accounts {
+			if acct.ConfigDir != "" {
+				dirs[filepath.Clean(util.ExpandHome(acct.ConfigDir))] = handle
+			}
+		}
+	}
+
+	for dir, handle := range dirs {
+		err := walkTranscripts(filepath.Join(dir, "projects"), since, func(path string) error {
+			err := ReadTranscript(path, func(e TranscriptEntry) {
+				if e.Time.IsZero() || e.Time.Before(since) {
+					return
+				}
+				sample := UsageSample{Time: e.Time, Tokens: e.Usage.Billable()}
+				if handle != "" {
+					ledger.Accounts[handle] = append(ledger.Accounts[handle], sample)
+				}
+				if rig := rigForDir(townRoot, e.CWD, rigSet); rig != "" {
+					ledger.Rigs[rig] = append(ledger.Rigs[rig], sample)
+				}
+			})
+			if err != nil && !os.IsNotExist(err) {
+				ledger.Unreadable = append(ledger.Unreadable, fmt.Errorf("%s: %w", path, err))
+			}
+			return nil
+		})
+		if err != nil {
+			ledger.Unreadable = append(ledger.Unreadable, fmt.Errorf("%s: %w", dir, err))
+		}
+	}
+	return ledger, nil
+}
+
+// walkTranscripts calls fn for every .jsonl file under root modified at or
+// after since. A missing root is not an error.
+func walkTranscripts(root string, since time.Time, fn func(path string) error) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return nil // unreadable or too old to hold samples in range
+		}
+		if err := fn(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// rigForDir returns the rig a working directory belongs to, or "" if it is
+// outside every known rig.
+func rigForDir(townRoot, dir string, rigs map[string]bool) string {
+	if dir == "" || townRoot == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(townRoot, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	name, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	if rigs[name] {
+		return name
+	}
+	return ""
+}
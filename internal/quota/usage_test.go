@@ -0,0 +1,130 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeTranscript(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	writeTranscript(t, path,
+		`{"type":"user","timestamp":"2026-02-18T10:00:00Z","message":{"role":"user"}}`,
+		`{"type":"assistant","timestamp":"2026-02-18T10:00:05.123Z","cwd":"/town/gastown","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"cache_creation_input_tokens":20,"cache_read_input_tokens":1000,"output_tokens":5}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514"}}`,
+	)
+
+	var entries []TranscriptEntry
+	if err := ReadTranscript(path, func(e TranscriptEntry) { entries = append(entries, e) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.CWD != "/town/gastown" || e.Model != "claude-sonnet-4-20250514" || e.Time.IsZero() {
+		t.Errorf("entry = %+v", e)
+	}
+	if got := e.Usage.Billable(); got != 35 {
+		t.Errorf("Billable() = %d, want 35 (cache reads excluded)", got)
+	}
+}
+
+func TestReadTranscript_OverLongLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	huge := `{"type":"user","message":{"content":"` + strings.Repeat("x", 3*maxTranscriptLine) + `"}}`
+	writeTranscript(t, path,
+		huge,
+		`{"type":"assistant","timestamp":"2026-02-18T10:00:05Z","message":{"usage":{"input_tokens":10}}}`,
+		huge,
+	)
+
+	var entries []TranscriptEntry
+	if err := ReadTranscript(path, func(e TranscriptEntry) { entries = append(entries, e) }); err != nil {
+		t.Fatalf("over-long line should be skipped, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].Usage.InputTokens != 10 {
+		t.Errorf("entries = %+v, want the one assistant message", entries)
+	}
+}
+
+func TestCollectUsage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	town := filepath.Join(t.TempDir(), "town")
+	acctDir := filepath.Join(t.TempDir(), "work")
+	since := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+
+	line := func(ts, cwd string, input int) string {
+		return `{"type":"assistant","timestamp":"` + ts + `","cwd":"` + cwd + `","message":{"usage":{"input_tokens":` + strconv.Itoa(input) + `}}}`
+	}
+	writeTranscript(t, filepath.Join(acctDir, "projects", "p1", "a.jsonl"),
+		line("2026-02-18T10:00:00Z", filepath.Join(town, "gastown", "polecats", "toast"), 100),
+		line("2026-02-10T10:00:00Z", filepath.Join(town, "gastown"), 999), // before cutoff
+		line("2026-02-18T11:00:00Z", filepath.Join(town, "mayor"), 7),     // not a rig
+	)
+	writeTranscript(t, filepath.Join(home, ".claude", "projects", "p2", "b.jsonl"),
+		line("2026-02-18T12:00:00Z", filepath.Join(town, "beads"), 50),
+	)
+
+	acctCfg := &config.AccountsConfig{Accounts: map[string]config.Account{"work": {ConfigDir: acctDir}}}
+	ledger, err := CollectUsage(town, acctCfg, []string{"gastown", "beads"}, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sumSamples(ledger.Accounts["work"]); got != 107 {
+		t.Errorf("work usage = %d, want 107", got)
+	}
+	if _, ok := ledger.Accounts[""]; ok {
+		t.Error("default config dir should not be attributed to an account")
+	}
+	if got := sumSamples(ledger.Rigs["gastown"]); got != 100 {
+		t.Errorf("gastown usage = %d, want 100", got)
+	}
+	if got := sumSamples(ledger.Rigs["beads"]); got != 50 {
+		t.Errorf("beads usage = %d, want 50", got)
+	}
+	if len(ledger.Unreadable) != 0 {
+		t.Errorf("Unreadable = %v, want none", ledger.Unreadable)
+	}
+
+	// An unreadable transcript is recorded and skipped, not fatal.
+	if os.Geteuid() != 0 {
+		bad := filepath.Join(acctDir, "projects", "p1", "bad.jsonl")
+		writeTranscript(t, bad, line("2026-02-18T10:00:00Z", town, 1))
+		if err := os.Chmod(bad, 0); err != nil {
+			t.Fatal(err)
+		}
+		ledger, err = CollectUsage(town, acctCfg, []string{"gastown", "beads"}, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ledger.Unreadable) != 1 || sumSamples(ledger.Accounts["work"]) != 107 {
+			t.Errorf("Unreadable = %v, work usage = %d", ledger.Unreadable, sumSamples(ledger.Accounts["work"]))
+		}
+	}
+}
+
+func sumSamples(samples []UsageSample) int64 {
+	var n int64
+	for _, s := range samples {
+		n += s.Tokens
+	}
+	return n
+}
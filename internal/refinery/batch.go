@@ -87,13 +87,14 @@ func (e *Engineer) AssembleBatch(readyMRs []*MRInfo, config *BatchConfig) []*MRI
 	return batch
 }
 
-// BuildRebaseStack constructs a squash-merge stack on the target branch.
-// Each MR is squash-merged sequentially: target ← MR1 ← MR2 ← MR3.
+// BuildRebaseStack constructs a merge stack on the target branch.
+// Each MR is merged sequentially with its merge strategy (squash by
+// default): target ← MR1 ← MR2 ← MR3.
 // Returns the list of MRs that were successfully stacked, and any that
 // conflicted (which are removed from the stack and the stack is rebuilt).
 //
 // On return, the git working directory is on the target branch with all
-// successful MR squash-merges applied (but not pushed).
+// successful MR merges applied (but not pushed).
 func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target string) (stacked []*MRInfo, conflicts []*MRInfo, err error) {
 	if len(batch) == 0 {
 		return nil, nil, nil
@@ -113,7 +114,7 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 		return nil, nil, fmt.Errorf("get base SHA: %w", err)
 	}
 
-	// Try to stack each MR via squash-merge
+	// Try to stack each MR with its merge strategy
 	for _, mr := range batch {
 		_, _ = fmt.Fprintf(e.output, "[Batch] Stacking MR %s (branch %s)...\n", mr.ID, mr.Branch)
 
@@ -137,17 +138,15 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 			}
 			// Rebuild the stack with MRs stacked so far (minus the conflicting one)
 			for _, prev := range stacked {
-				msg := e.getMergeMessage(prev)
-				if mergeErr := e.git.MergeSquash(prev.Branch, msg); mergeErr != nil {
+				if mergeErr := e.applyMerge(prev, target); mergeErr != nil {
 					return nil, nil, fmt.Errorf("rebuild stack for %s: %w", prev.ID, mergeErr)
 				}
 			}
 			continue
 		}
 
-		// Squash-merge this MR onto the stack
-		msg := e.getMergeMessage(mr)
-		if mergeErr := e.git.MergeSquash(mr.Branch, msg); mergeErr != nil {
+		// Merge this MR onto the stack with its strategy
+		if mergeErr := e.applyMerge(mr, target); mergeErr != nil {
 			_, _ = fmt.Fprintf(e.output, "[Batch] MR %s: merge failed: %v, removing from batch\n", mr.ID, mergeErr)
 			conflicts = append(conflicts, mr)
 
@@ -156,8 +155,7 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 				return nil, nil, fmt.Errorf("reset after merge failure: %w", resetErr)
 			}
 			for _, prev := range stacked {
-				prevMsg := e.getMergeMessage(prev)
-				if rebuildErr := e.git.MergeSquash(prev.Branch, prevMsg); rebuildErr != nil {
+				if rebuildErr := e.applyMerge(prev, target); rebuildErr != nil {
 					return nil, nil, fmt.Errorf("rebuild stack for %s: %w", prev.ID, rebuildErr)
 				}
 			}
@@ -171,7 +169,8 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 	return stacked, conflicts, nil
 }
 
-// getMergeMessage returns the commit message for a squash-merged MR.
+// getMergeMessage returns the commit message for a squash-merged MR:
+// the branch head's message, or a generated one naming the source issue.
 func (e *Engineer) getMergeMessage(mr *MRInfo) string {
 	// Try to get the original commit message from the branch
 	msg, err := e.git.GetBranchCommitMessage(mr.Branch)
@@ -191,7 +190,8 @@ func (e *Engineer) getMergeMessage(mr *MRInfo) string {
 //  1. Build the rebase stack (target ← MR1 ← MR2 ← ... ← MRn)
 //  2. Run gates once on the stack tip
 //  3. If green: push (fast-forward all MRs to target)
-//  4. If red and RetryBatchOnFlaky: retry the full batch once
+//  4. If red and RetryBatchOnFlaky: retry the full batch once; tests that
+//     failed and then passed are recorded in the flake database
 //  5. If still red: bisect to isolate the culprit
 //  6. Re-batch good MRs for the next cycle
 func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target string, batchCfg *BatchConfig) *BatchResult {
@@ -234,7 +234,7 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 
 	// Step 2: Run gates on the stack tip
 	_, _ = fmt.Fprintf(e.output, "[Batch] Running gates on stack tip (%d MRs)...\n", len(stacked))
-	gateResult := e.runBatchGates(ctx)
+	gateResult := e.runBatchGates(ctx, stacked)
 
 	// Step 3: Happy path — all green
 	if gateResult.Success {
@@ -251,9 +251,12 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 			return result
 		}
 
-		retryResult := e.runBatchGates(ctx)
+		retryResult := e.runBatchGates(ctx, stacked)
 		if retryResult.Success {
 			_, _ = fmt.Fprintln(e.output, "[Batch] Retry succeeded (was flaky)")
+			if len(gateResult.FailedTests) > 0 {
+				e.recordFlakes(mrIDs(stacked), gateResult.FailedTests)
+			}
 			return e.fastForwardBatch(ctx, stacked, target, result)
 		}
 		_, _ = fmt.Fprintln(e.output, "[Batch] Retry also failed, proceeding to bisection")
@@ -273,7 +276,7 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 			return result
 		}
 		// Verify the good subset actually passes
-		verifyResult := e.runBatchGates(ctx)
+		verifyResult := e.runBatchGates(ctx, good)
 		if verifyResult.Success {
 			return e.fastForwardBatch(ctx, good, target, result)
 		}
@@ -288,7 +291,7 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 // processSingleMR handles the degenerate case of a batch with one MR.
 func (e *Engineer) processSingleMR(ctx context.Context, mr *MRInfo, target string) *BatchResult {
 	result := &BatchResult{}
-	processResult := e.doMerge(ctx, mr.Branch, target, mr.SourceIssue)
+	processResult := e.doMerge(ctx, mr, target)
 	if processResult.Success {
 		result.Merged = []*MRInfo{mr}
 		result.MergeCommit = processResult.MergeCommit
@@ -302,18 +305,21 @@ func (e *Engineer) processSingleMR(ctx context.Context, mr *MRInfo, target strin
 	return result
 }
 
-// runBatchGates runs quality gates (or legacy tests) on the current working tree.
-func (e *Engineer) runBatchGates(ctx context.Context) ProcessResult {
+// runBatchGates runs quality gates (or legacy tests) on the current working
+// tree, writing gate artifacts for each MR in the stack under test.
+func (e *Engineer) runBatchGates(ctx context.Context, stack []*MRInfo) ProcessResult {
 	if len(e.config.Gates) > 0 {
-		return e.runGates(ctx)
+		return e.runGates(ctx, mrIDs(stack)...)
 	}
 	if e.config.RunTests && e.config.TestCommand != "" {
-		result := e.runTests(ctx)
+		result := e.runTests(ctx, mrIDs(stack)...)
 		if !result.Success {
 			return ProcessResult{
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				FailedTests: result.FailedTests,
+				ArtifactDir: result.ArtifactDir,
 			}
 		}
 		return ProcessResult{Success: true}
@@ -326,7 +332,7 @@ func (e *Engineer) runBatchGates(ctx context.Context) ProcessResult {
 func (e *Engineer) verifyAndPush(ctx context.Context, stacked []*MRInfo, target string) *BatchResult {
 	result := &BatchResult{}
 
-	gateResult := e.runBatchGates(ctx)
+	gateResult := e.runBatchGates(ctx, stacked)
 	if !gateResult.Success {
 		if gateResult.TestsFailed {
 			result.Culprits = stacked
@@ -412,7 +418,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 		return nil, batch
 	}
 
-	leftResult := e.runBatchGates(ctx)
+	leftResult := e.runBatchGates(ctx, left)
 
 	if leftResult.Success {
 		// Left half is green — culprit is in right half
@@ -441,7 +447,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 			_, _ = fmt.Fprintf(e.output, "[Bisect] Error testing right with good left: %v\n", resetErr)
 			return leftGood, append(leftCulprits, right...)
 		}
-		combinedResult := e.runBatchGates(ctx)
+		combinedResult := e.runBatchGates(ctx, combined)
 		if combinedResult.Success {
 			return append(leftGood, right...), leftCulprits
 		}
@@ -454,7 +460,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 	if resetErr := e.resetAndRebuildStack(right, target); resetErr != nil {
 		return nil, batch
 	}
-	rightResult := e.runBatchGates(ctx)
+	rightResult := e.runBatchGates(ctx, right)
 	if rightResult.Success {
 		return right, leftCulprits
 	}
@@ -481,7 +487,7 @@ func (e *Engineer) bisectRight(ctx context.Context, knownGood []*MRInfo, right [
 		return nil, right
 	}
 
-	result := e.runBatchGates(ctx)
+	result := e.runBatchGates(ctx, testBatch)
 	if result.Success {
 		// rLeft is fine in context of knownGood — culprit is in rRight
 		_, _ = fmt.Fprintf(e.output, "[Bisect-R] knownGood+rLeft passed → culprit in rRight=%v\n", mrIDs(rRight))
@@ -501,7 +507,7 @@ func (e *Engineer) bisectRight(ctx context.Context, knownGood []*MRInfo, right [
 	if resetErr := e.resetAndRebuildStack(testBatch2, target); resetErr != nil {
 		return rLeftGood, append(rLeftCulprits, rRight...)
 	}
-	result2 := e.runBatchGates(ctx)
+	result2 := e.runBatchGates(ctx, testBatch2)
 	if result2.Success {
 		_, _ = fmt.Fprintf(e.output, "[Bisect-R] rRight passed → good=%v, culprits=%v\n", mrIDs(append(rLeftGood, rRight...)), mrIDs(rLeftCulprits))
 		return append(rLeftGood, rRight...), rLeftCulprits
@@ -519,7 +525,7 @@ func mrIDs(mrs []*MRInfo) []string {
 	return ids
 }
 
-// resetAndRebuildStack resets the target branch and rebuilds the squash-merge stack.
+// resetAndRebuildStack resets the target branch and rebuilds the merge stack.
 func (e *Engineer) resetAndRebuildStack(mrs []*MRInfo, target string) error {
 	// Reset target to origin
 	if err := e.git.Checkout(target); err != nil {
@@ -531,9 +537,8 @@ func (e *Engineer) resetAndRebuildStack(mrs []*MRInfo, target string) error {
 
 	// Rebuild the stack
 	for _, mr := range mrs {
-		msg := e.getMergeMessage(mr)
-		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
-			return fmt.Errorf("squash merge %s: %w", mr.ID, err)
+		if err := e.applyMerge(mr, target); err != nil {
+			return fmt.Errorf("merge %s (%s): %w", mr.ID, e.mergeStrategyFor(mr), err)
 		}
 	}
 	return nil
@@ -100,6 +100,8 @@ func newTestEngineer(t *testing.T, workDir string, g *gitpkg.Git) *Engineer {
 		return &beads.MergeSlotStatus{Available: true, Holder: holder}, nil
 	}
 	e.mergeSlotRelease = func(holder string) error { return nil }
+	// Keep refinery state out of the git worktree
+	e.flakes = NewFlakeStore(filepath.Join(t.TempDir(), "flakes.json"))
 	return e
 }
 
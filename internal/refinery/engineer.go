@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
@@ -54,14 +55,26 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// Report is an optional path, relative to the refinery worktree, of a
+	// JUnit XML or `go test -json` file the command writes. When empty the
+	// gate's stdout is parsed instead.
+	Report string `json:"report"`
 }
 
 // GateResult holds the outcome of a single gate execution.
 type GateResult struct {
-	Name    string
-	Success bool
-	Error   string
-	Elapsed time.Duration
+	Name        string
+	Success     bool
+	Error       string
+	Elapsed     time.Duration
+	FailedTests []FailedTest // Parsed from the gate's test report, if any
+	Quarantined []string     // Quarantined test failures ignored (skip mode)
+	Flaky       []string     // Quarantined tests that failed, then passed on retry
+
+	stdout, stderr []byte // Full captured output, written as artifacts
+	report         []byte // Contents of GateConfig.Report, if it was written
+	reportPath     string
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -77,6 +90,10 @@ type MergeQueueConfig struct {
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is the default way MRs land: "squash", "merge-commit",
+	// or "rebase". An MR's merge_strategy bead field overrides it.
+	MergeStrategy string `json:"merge_strategy"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -127,24 +144,36 @@ type MergeQueueConfig struct {
 	// Batch holds configuration for the batch-then-bisect merge queue.
 	// When nil or MaxBatchSize <= 1, batching is disabled and MRs process sequentially.
 	Batch *BatchConfig `json:"batch,omitempty"`
+
+	// QuarantineMode controls how gates treat failures that consist only of
+	// quarantined tests: "retry" (re-run the gate up to RetryFlakyTests
+	// times), "skip" (treat the gate as passed), or "off".
+	QuarantineMode string `json:"quarantine_mode"`
+
+	// FlakeQuarantineThreshold quarantines a test automatically once it has
+	// flaked this many times. Zero disables automatic quarantine.
+	FlakeQuarantineThreshold int `json:"flake_quarantine_threshold"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
-		Enabled:                 true,
-		OnConflict:              "assign_back",
-		RunTests:                true,
-		TestCommand:             "",
-		DeleteMergedBranches:    true,
-		GatesParallel:           true, // gt-8b2i: run gates concurrently (~2x speedup)
-		RetryFlakyTests:         1,
-		PollInterval:            30 * time.Second,
-		MaxConcurrent:           1,
-		StaleClaimTimeout:       DefaultStaleClaimTimeout,
-		StaleClaimWarningAfter:  2 * time.Hour,
-		StaleClaimCriticalAfter: 6 * time.Hour,
-		MaxRetryCount:           5,
+		Enabled:                  true,
+		OnConflict:               "assign_back",
+		MergeStrategy:            config.DefaultMergeStrategy,
+		RunTests:                 true,
+		TestCommand:              "",
+		DeleteMergedBranches:     true,
+		GatesParallel:            true, // gt-8b2i: run gates concurrently (~2x speedup)
+		RetryFlakyTests:          1,
+		PollInterval:             30 * time.Second,
+		MaxConcurrent:            1,
+		StaleClaimTimeout:        DefaultStaleClaimTimeout,
+		StaleClaimWarningAfter:   2 * time.Hour,
+		StaleClaimCriticalAfter:  6 * time.Hour,
+		MaxRetryCount:            5,
+		QuarantineMode:           QuarantineRetry,
+		FlakeQuarantineThreshold: 3,
 	}
 }
 
@@ -160,6 +189,7 @@ type MRInfo struct {
 	Title           string     // MR title
 	Priority        int        // Priority (lower = higher priority)
 	AgentBead       string     // Agent bead ID that created this MR
+	MergeStrategy   string     // Per-MR merge strategy override (empty = rig default)
 	RetryCount      int        // Conflict retry count
 	ConvoyID        string     // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt *time.Time // Convoy creation time
@@ -210,6 +240,7 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	flakes                *FlakeStore   // Rig-level flake history and quarantine list
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -244,6 +275,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		flakes:                NewFlakeStore(FlakeDBPath(r.Path)),
 	}
 }
 
@@ -254,7 +286,13 @@ func (e *Engineer) SetOutput(w io.Writer) {
 }
 
 // LoadConfig loads merge queue configuration from the rig's config.json.
+// The merge strategy defaults to the rig settings' merge_queue.merge_strategy.
 func (e *Engineer) LoadConfig() error {
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path)); err == nil &&
+		settings.MergeQueue != nil && settings.MergeQueue.MergeStrategy != "" {
+		e.config.MergeStrategy = settings.MergeQueue.MergeStrategy
+	}
+
 	configPath := filepath.Join(e.rig.Path, "config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -283,6 +321,7 @@ func (e *Engineer) LoadConfig() error {
 	var mqRaw struct {
 		Enabled              *bool                      `json:"enabled"`
 		OnConflict           *string                    `json:"on_conflict"`
+		MergeStrategy        *string                    `json:"merge_strategy"`
 		RunTests             *bool                      `json:"run_tests"`
 		TestCommand          *string                    `json:"test_command"`
 		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
@@ -292,6 +331,8 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		QuarantineMode       *string                    `json:"quarantine_mode"`
+		FlakeThreshold       *int                       `json:"flake_quarantine_threshold"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -305,6 +346,12 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.OnConflict != nil {
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.MergeStrategy != nil {
+		if !config.IsValidMergeStrategy(*mqRaw.MergeStrategy) {
+			return fmt.Errorf("invalid merge_strategy %q: must be squash, merge-commit, or rebase", *mqRaw.MergeStrategy)
+		}
+		e.config.MergeStrategy = *mqRaw.MergeStrategy
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -342,7 +389,7 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.Gates != nil {
 		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
 		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
+			gc := &GateConfig{Cmd: raw.Cmd, Report: raw.Report}
 			if raw.Timeout != "" {
 				dur, err := time.ParseDuration(raw.Timeout)
 				if err != nil {
@@ -359,6 +406,20 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.QuarantineMode != nil {
+		switch *mqRaw.QuarantineMode {
+		case QuarantineRetry, QuarantineSkip, QuarantineOff:
+			e.config.QuarantineMode = *mqRaw.QuarantineMode
+		default:
+			return fmt.Errorf("invalid quarantine_mode %q: must be retry, skip, or off", *mqRaw.QuarantineMode)
+		}
+	}
+	if mqRaw.FlakeThreshold != nil {
+		if *mqRaw.FlakeThreshold < 0 {
+			return fmt.Errorf("flake_quarantine_threshold must not be negative, got %d", *mqRaw.FlakeThreshold)
+		}
+		e.config.FlakeQuarantineThreshold = *mqRaw.FlakeThreshold
+	}
 
 	return nil
 }
@@ -368,6 +429,7 @@ func (e *Engineer) LoadConfig() error {
 type gateConfigRaw struct {
 	Cmd     string `json:"cmd"`
 	Timeout string `json:"timeout"`
+	Report  string `json:"report"`
 }
 
 // Config returns the current merge queue configuration.
@@ -382,11 +444,15 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
-	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	SlotTimeout bool     // Merge slot contention timeout (distinct from build/test failure)
+	FailedTests []string // IDs of failed tests parsed from gate reports
+	ArtifactDir string   // Directory holding gate logs and report.json, if written
 }
 
-// doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+// doMerge performs the actual git merge operation, landing the MR on target
+// with its merge strategy (see applyMerge).
+func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo, target string) ProcessResult {
+	branch := mr.Branch
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -469,52 +535,52 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	// Step 4: Run quality gates (or legacy tests) if configured
 	if len(e.config.Gates) > 0 {
 		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
+		// Flake history is keyed by the MR head, so a later pass of the
+		// unchanged branch marks this run's failures as flaky.
+		head, _ := e.git.Rev(branch)
+		gateResult := e.runGates(ctx, mr.ID)
 		if !gateResult.Success {
+			if err := e.flakes.RecordFailures(mr.ID, head, gateResult.FailedTests); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record test failures: %v\n", err)
+			}
 			return gateResult
 		}
+		flaky, quarantined, err := e.flakes.ResolvePending(mr.ID, head, e.config.FlakeQuarantineThreshold)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update flake history: %v\n", err)
+		} else if len(flaky) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Previously failing tests passed at the same head (flaky): %s\n", strings.Join(flaky, ", "))
+			e.logQuarantined(quarantined)
+		}
 	} else if e.config.RunTests && e.config.TestCommand != "" {
 		// Legacy test command path (backward compatible)
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
+		result := e.runTests(ctx, mr.ID)
 		if !result.Success {
 			return ProcessResult{
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				FailedTests: result.FailedTests,
+				ArtifactDir: result.ArtifactDir,
 			}
 		}
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
-	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
-		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
-		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
-		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+	// Step 5: Perform the actual merge using the MR's strategy
+	strategy := e.mergeStrategyFor(mr)
+	if err := e.applyMerge(mr, target); err != nil {
+		if errors.Is(err, errMergeConflict) {
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
-				Error:    "merge conflict during actual merge",
+				Error:    fmt.Sprintf("merge conflict during actual merge (%s)", strategy),
 			}
 		}
 		return ProcessResult{
 			Success: false,
-			Error:   fmt.Sprintf("merge failed: %v", err),
+			Error:   fmt.Sprintf("merge failed (%s): %v", strategy, err),
 		}
 	}
 
@@ -643,8 +709,13 @@ func ValidateTestCommand(cmd string) error {
 	return nil
 }
 
+// legacyTestGate names the legacy test command in gate artifacts.
+const legacyTestGate = "test"
+
 // runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+// The last attempt's output is written as gate artifacts for each of mrIDs,
+// like a single gate named "test".
+func (e *Engineer) runTests(ctx context.Context, mrIDs ...string) ProcessResult {
 	if err := ValidateTestCommand(e.config.TestCommand); err != nil {
 		return ProcessResult{
 			Success: false,
@@ -659,6 +730,8 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var failedBefore []string
+	var last GateResult
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
@@ -674,14 +747,30 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
+		start := time.Now()
 		err := cmd.Run()
+		last = GateResult{
+			Name:    legacyTestGate,
+			Success: err == nil,
+			Elapsed: time.Since(start),
+			stdout:  stdout.Bytes(),
+			stderr:  stderr.Bytes(),
+		}
 		if err == nil {
+			e.writeGateArtifacts(mrIDs, []GateResult{last})
+			if len(failedBefore) > 0 {
+				e.recordFlakes(mrIDs, failedBefore)
+			}
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
+		last.Error = err.Error()
+		last.FailedTests = ParseTestReport(stdout.Bytes())
+		failedBefore = failedTestIDs(last.FailedTests)
 
 		// Check if context was canceled
 		if ctx.Err() != nil {
+			e.writeGateArtifacts(mrIDs, []GateResult{last})
 			return ProcessResult{
 				Success: false,
 				Error:   "test run canceled",
@@ -693,11 +782,54 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		Success:     false,
 		TestsFailed: true,
 		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		FailedTests: failedBefore,
+		ArtifactDir: e.writeGateArtifacts(mrIDs, []GateResult{last}),
 	}
 }
 
-// runGate executes a single quality gate command and returns the result.
-func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+// runGate executes a single quality gate and applies the quarantine policy:
+// if every failed test is in quarantined, the gate is re-run or treated as
+// passed according to QuarantineMode. Failures involving any other test,
+// or failures that could not be attributed to tests, are returned as-is.
+func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig, quarantined map[string]bool) GateResult {
+	result := e.execGate(ctx, name, gate, quarantined)
+	if result.Success || !onlyQuarantined(result.FailedTests, quarantined) {
+		return result
+	}
+
+	ids := failedTestIDs(result.FailedTests)
+	switch e.config.QuarantineMode {
+	case QuarantineSkip:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: ignoring quarantined test failures: %s\n", name, strings.Join(ids, ", "))
+		result.Success = true
+		result.Error = ""
+		result.Quarantined = ids
+		return result
+	case QuarantineRetry:
+		retries := e.config.RetryFlakyTests
+		if retries < 1 {
+			retries = 1
+		}
+		for attempt := 1; attempt <= retries; attempt++ {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: only quarantined tests failed, retrying (%d/%d)\n", name, attempt, retries)
+			rerun := e.execGate(ctx, name, gate, quarantined)
+			rerun.Elapsed += result.Elapsed
+			if rerun.Success {
+				rerun.Flaky = ids
+				return rerun
+			}
+			result = rerun
+			if !onlyQuarantined(result.FailedTests, quarantined) {
+				break
+			}
+		}
+	}
+	return result
+}
+
+// execGate runs a gate command once, capturing its output and parsing any
+// test report.
+func (e *Engineer) execGate(ctx context.Context, name string, gate *GateConfig, quarantined map[string]bool) GateResult {
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -717,8 +849,21 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		defer cancel()
 	}
 
+	var reportPath string
+	if gate.Report != "" {
+		reportPath = gate.Report
+		if !filepath.IsAbs(reportPath) {
+			reportPath = filepath.Join(e.workDir, reportPath)
+		}
+		// Remove a stale report so a crashed run isn't blamed on old failures.
+		_ = os.Remove(reportPath)
+	}
+
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
 	cmd.Dir = e.workDir
+	if len(quarantined) > 0 {
+		cmd.Env = append(os.Environ(), QuarantineEnvVar+"="+quarantineEnv(quarantined))
+	}
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -726,14 +871,30 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 	err := cmd.Run()
 	elapsed := time.Since(start)
 
-	if err == nil {
-		return GateResult{
-			Name:    name,
-			Success: true,
-			Elapsed: elapsed,
+	result := GateResult{
+		Name:       name,
+		Elapsed:    elapsed,
+		stdout:     stdout.Bytes(),
+		stderr:     stderr.Bytes(),
+		reportPath: reportPath,
+	}
+	if reportPath != "" {
+		if data, readErr := os.ReadFile(reportPath); readErr == nil { //nolint:gosec // G304: path is from trusted rig config
+			result.report = data
 		}
 	}
 
+	if err == nil {
+		result.Success = true
+		return result
+	}
+
+	if reportPath != "" {
+		result.FailedTests = ParseTestReport(result.report)
+	} else {
+		result.FailedTests = ParseTestReport(result.stdout)
+	}
+
 	errMsg := fmt.Sprintf("%v", err)
 	if gateCtx.Err() == context.DeadlineExceeded {
 		errMsg = fmt.Sprintf("timed out after %v", gate.Timeout)
@@ -746,18 +907,17 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		errMsg = fmt.Sprintf("%s: %s", errMsg, stderrStr)
 	}
 
-	return GateResult{
-		Name:    name,
-		Success: false,
-		Error:   errMsg,
-		Elapsed: elapsed,
-	}
+	result.Error = errMsg
+	return result
 }
 
 // runGates executes all configured quality gates and returns a ProcessResult.
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
-func (e *Engineer) runGates(ctx context.Context) ProcessResult {
+// Full gate output and a report.json summary are written to the artifact
+// directory of each of mrIDs (see GateArtifactsDir): the MR being merged,
+// or every MR in the batch stack under test.
+func (e *Engineer) runGates(ctx context.Context, mrIDs ...string) ProcessResult {
 	gates := e.config.Gates
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
@@ -772,6 +932,14 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(names), e.config.GatesParallel)
 
+	var quarantined map[string]bool
+	if e.config.QuarantineMode != QuarantineOff {
+		var err error
+		if quarantined, err = e.flakes.Quarantined(); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to load quarantine list: %v\n", err)
+		}
+	}
+
 	var results []GateResult
 
 	if e.config.GatesParallel {
@@ -782,14 +950,14 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			go func(idx int, gateName string) {
 				defer wg.Done()
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
+				results[idx] = e.runGate(ctx, gateName, gates[gateName], quarantined)
 			}(i, name)
 		}
 		wg.Wait()
 	} else {
 		for _, name := range names {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
+			result := e.runGate(ctx, name, gates[name], quarantined)
 			results = append(results, result)
 			if !result.Success {
 				// Sequential mode: stop on first failure
@@ -800,20 +968,36 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 
 	// Report results
 	var failures []string
+	var failedTests []FailedTest
+	var flaky []string
 	for _, r := range results {
+		flaky = append(flaky, r.Flaky...)
 		if r.Success {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
 			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+			failedTests = append(failedTests, r.FailedTests...)
 		}
 	}
 
+	artifactDir := e.writeGateArtifacts(mrIDs, results)
+
+	if len(flaky) > 0 {
+		e.recordFlakes(mrIDs, flaky)
+	}
+
 	if len(failures) > 0 {
+		ids := failedTestIDs(failedTests)
+		if len(ids) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Failed tests (%d): %s\n", len(ids), strings.Join(ids, ", "))
+		}
 		return ProcessResult{
 			Success:     false,
 			TestsFailed: true,
 			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			FailedTests: ids,
+			ArtifactDir: artifactDir,
 		}
 	}
 
@@ -821,6 +1005,28 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	return ProcessResult{Success: true}
 }
 
+// writeGateArtifacts writes the artifacts of a gate run to the directory of
+// each MR it covered. Returns the first MR's directory, or "" if nothing
+// was written.
+func (e *Engineer) writeGateArtifacts(mrIDs []string, results []GateResult) string {
+	var first string
+	for _, id := range mrIDs {
+		if id == "" {
+			continue
+		}
+		dir := GateArtifactsDir(e.rig.Path, id)
+		if _, err := writeGateArtifacts(dir, id, results); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to write gate artifacts for %s: %v\n", id, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate artifacts: %s\n", dir)
+		if first == "" {
+			first = dir
+		}
+	}
+	return first
+}
+
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
 // This ensures crew members have access to newly merged code without manual sync.
 func (e *Engineer) syncCrewWorkspaces() {
@@ -863,7 +1069,7 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	return e.doMerge(ctx, mr, mr.Target)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -879,6 +1085,8 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Released merge slot\n")
 	}
 
+	_ = events.LogFeed(events.TypeMerged, holder, events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+
 	// Update and close the MR bead
 	if mr.ID != "" {
 		// Fetch the MR bead to update its fields
@@ -893,6 +1101,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			mrFields.FailedTests = nil
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -973,8 +1182,8 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 	polecatName := strings.TrimPrefix(mr.Worker, "polecats/")
 	nudgeTarget := fmt.Sprintf("%s/%s", e.rig.Name, polecatName)
-	nudgeMsg := fmt.Sprintf("MERGE_FAILED: branch=%s issue=%s type=%s error=%s — fix and resubmit with 'gt done'",
-		mr.Branch, mr.SourceIssue, failureType, result.Error)
+	nudgeMsg := fmt.Sprintf("MERGE_FAILED: branch=%s issue=%s type=%s error=%s%s — fix and resubmit with 'gt done'",
+		mr.Branch, mr.SourceIssue, failureType, result.Error, gateReportSuffix(result))
 	nudgeCmd := exec.Command("gt", "nudge", nudgeTarget, nudgeMsg)
 	nudgeCmd.Dir = e.workDir
 	if err := nudgeCmd.Run(); err != nil {
@@ -983,6 +1192,10 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Nudged %s about merge failure (%s)\n", polecatName, failureType)
 	}
 
+	if result.TestsFailed {
+		e.recordGateReport(mr, result)
+	}
+
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
 	if result.Conflict {
@@ -1009,6 +1222,77 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// recordFlakes records tests that failed and then passed on retry of the
+// same tree, and reports any that crossed the auto-quarantine threshold.
+func (e *Engineer) recordFlakes(mrIDs, tests []string) {
+	quarantined, err := e.flakes.RecordFlakes(FlakeRetryPass, mrIDs, tests, e.config.FlakeQuarantineThreshold)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record flaky tests: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Recorded flaky tests: %s\n", strings.Join(tests, ", "))
+	e.logQuarantined(quarantined)
+}
+
+func (e *Engineer) logQuarantined(tests []string) {
+	if len(tests) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantined flaky tests: %s (see 'gt mq flakes %s')\n", strings.Join(tests, ", "), e.rig.Name)
+	}
+}
+
+// maxNudgeFailedTests caps how many failed tests are named in a failure nudge.
+const maxNudgeFailedTests = 5
+
+// maxRecordedFailedTests caps the failed_tests field on the MR bead. The
+// full list is in the artifact directory's report.json.
+const maxRecordedFailedTests = 20
+
+// gateReportSuffix formats the failed tests and artifact directory of a gate
+// failure for the polecat nudge. Returns "" when neither is known.
+func gateReportSuffix(result ProcessResult) string {
+	var sb strings.Builder
+	if n := len(result.FailedTests); n > 0 {
+		shown := result.FailedTests
+		if n > maxNudgeFailedTests {
+			shown = shown[:maxNudgeFailedTests]
+		}
+		fmt.Fprintf(&sb, " failed_tests=%s", strings.Join(shown, ","))
+		if n > maxNudgeFailedTests {
+			fmt.Fprintf(&sb, " (+%d more)", n-maxNudgeFailedTests)
+		}
+	}
+	if result.ArtifactDir != "" {
+		fmt.Fprintf(&sb, " logs=%s", result.ArtifactDir)
+	}
+	return sb.String()
+}
+
+// recordGateReport stores the failed tests and artifact directory of a gate
+// failure on the MR bead, where gt mq status picks them up.
+func (e *Engineer) recordGateReport(mr *MRInfo, result ProcessResult) {
+	if mr.ID == "" {
+		return
+	}
+	mrBead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	mrFields.FailedTests = result.FailedTests
+	if len(mrFields.FailedTests) > maxRecordedFailedTests {
+		mrFields.FailedTests = mrFields.FailedTests[:maxRecordedFailedTests]
+	}
+	mrFields.GateArtifacts = result.ArtifactDir
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record gate report on MR %s: %v\n", mr.ID, err)
+	}
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1178,6 +1462,7 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		Title:           issue.Title,
 		Priority:        issue.Priority,
 		AgentBead:       fields.AgentBead,
+		MergeStrategy:   fields.MergeStrategy,
 		RetryCount:      fields.RetryCount,
 		ConvoyID:        fields.ConvoyID,
 		ConvoyCreatedAt: convoyCreatedAt,
@@ -1627,25 +1912,25 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 			Description: convoy.Description,
 		})
 
-		// Send convoy completion notifications (owner + notify addresses)
-		e.notifyConvoyCompletion(townRoot, convoy.ID, convoy.Title, convoy.Description)
+		// Tell the convoy's landed subscribers (owner, notify, Subscribe:)
+		e.notifyConvoyCompletion(townRoot, convoy.ID, convoy.Title)
 	}
 
 	return closed
 }
 
-// notifyConvoyCompletion sends notifications to convoy owner and notify addresses.
-func (e *Engineer) notifyConvoyCompletion(townRoot, convoyID, title, description string) {
-	// ZFC: Use typed accessor instead of parsing description text
-	fields := beads.ParseConvoyFields(&beads.Issue{Description: description})
-	for _, addr := range fields.NotificationAddresses() {
-		mailCmd := exec.Command("gt", "mail", "send", addr,
-			"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
-			"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.\n\nClosed by: %s/refinery", convoyID, e.rig.Name))
-		mailCmd.Dir = townRoot
-		if err := mailCmd.Run(); err != nil {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not notify %s: %v\n", addr, err)
-		}
+// notifyConvoyCompletion raises the convoy's landed event via gt convoy
+// notify, so subscribers, escalation routing and the Mayor nudge behave as
+// they do for convoys landed by gt convoy check.
+func (e *Engineer) notifyConvoyCompletion(townRoot, convoyID, title string) {
+	notifyCmd := exec.Command("gt", "convoy", "notify", convoyID,
+		"--event", beads.ConvoyEventLanded, "--title", title)
+	notifyCmd.Dir = townRoot
+	var stderr bytes.Buffer
+	notifyCmd.Stderr = &stderr
+	if err := notifyCmd.Run(); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not notify subscribers of convoy %s: %v (%s)\n",
+			convoyID, err, strings.TrimSpace(stderr.String()))
 	}
 }
 
@@ -361,6 +361,41 @@ func TestEngineer_LoadConfig_GateInvalidTimeout(t *testing.T) {
 	}
 }
 
+func TestEngineer_LoadConfig_Quarantine(t *testing.T) {
+	tests := []struct {
+		name          string
+		mq            map[string]interface{}
+		wantErr       bool
+		wantMode      string
+		wantThreshold int
+	}{
+		{"defaults", map[string]interface{}{}, false, QuarantineRetry, 3},
+		{"skip", map[string]interface{}{"quarantine_mode": "skip", "flake_quarantine_threshold": 0}, false, QuarantineSkip, 0},
+		{"invalid mode", map[string]interface{}{"quarantine_mode": "ignore"}, true, "", 0},
+		{"negative threshold", map[string]interface{}{"flake_quarantine_threshold": -1}, true, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			data, _ := json.Marshal(map[string]interface{}{"merge_queue": tt.mq})
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+			err := e.LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if e.config.QuarantineMode != tt.wantMode || e.config.FlakeQuarantineThreshold != tt.wantThreshold {
+				t.Errorf("got mode %q threshold %d", e.config.QuarantineMode, e.config.FlakeQuarantineThreshold)
+			}
+		})
+	}
+}
+
 func TestRunGate_Success(t *testing.T) {
 	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
 	e := NewEngineer(r)
@@ -368,7 +403,7 @@ func TestRunGate_Success(t *testing.T) {
 
 	result := e.runGate(context.Background(), "echo-test", &GateConfig{
 		Cmd: "echo hello",
-	})
+	}, nil)
 
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
@@ -385,7 +420,7 @@ func TestRunGate_Failure(t *testing.T) {
 
 	result := e.runGate(context.Background(), "fail-test", &GateConfig{
 		Cmd: "exit 1",
-	})
+	}, nil)
 
 	if result.Success {
 		t.Error("expected failure")
@@ -402,7 +437,7 @@ func TestRunGate_EmptyCmd(t *testing.T) {
 
 	result := e.runGate(context.Background(), "empty", &GateConfig{
 		Cmd: "",
-	})
+	}, nil)
 
 	if result.Success {
 		t.Error("expected failure for empty cmd")
@@ -417,7 +452,7 @@ func TestRunGate_Timeout(t *testing.T) {
 	result := e.runGate(context.Background(), "slow", &GateConfig{
 		Cmd:     "sleep 10",
 		Timeout: 100 * time.Millisecond,
-	})
+	}, nil)
 
 	if result.Success {
 		t.Error("expected timeout failure")
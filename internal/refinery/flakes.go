@@ -0,0 +1,374 @@
+package refinery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Quarantine modes for MergeQueueConfig.QuarantineMode.
+const (
+	// QuarantineRetry re-runs a gate whose only failures are quarantined
+	// tests. Failures of non-quarantined tests are never retried this way.
+	QuarantineRetry = "retry"
+
+	// QuarantineSkip treats a gate whose only failures are quarantined tests
+	// as passed.
+	QuarantineSkip = "skip"
+
+	// QuarantineOff ignores the quarantine list when evaluating gates.
+	QuarantineOff = "off"
+)
+
+// QuarantineEnvVar is set on every gate command to the comma-separated IDs
+// of quarantined tests, so gate scripts can skip them up front.
+const QuarantineEnvVar = "GT_QUARANTINED_TESTS"
+
+// Flake evidence kinds recorded in FlakeRecord.LastKind.
+const (
+	// FlakeRetryPass: the test failed, then the same tree passed on retry.
+	FlakeRetryPass = "retry-pass"
+
+	// FlakeRerunPass: the test failed for an MR, then the same MR head
+	// passed on a later run.
+	FlakeRerunPass = "rerun-pass"
+)
+
+// maxFlakeMRs caps the recent MR list kept per test.
+const maxFlakeMRs = 10
+
+// pendingFailureTTL is how long a failure waits for its MR to be re-run at
+// the same head before it is forgotten.
+const pendingFailureTTL = 7 * 24 * time.Hour
+
+// ErrUnknownTest is returned when releasing a test that is not in the store.
+var ErrUnknownTest = errors.New("test not found in flake database")
+
+// FlakeRecord is the flake history of one test.
+type FlakeRecord struct {
+	// Test is the test ID ("package.Name"), as produced by FailedTest.ID.
+	Test string `json:"test"`
+
+	// Failures counts gate runs in which the test failed.
+	Failures int `json:"failures"`
+
+	// Flakes counts failures later shown to be flaky.
+	Flakes int `json:"flakes"`
+
+	// MRs lists the most recent MRs the test flaked on (newest last).
+	MRs []string `json:"mrs,omitempty"`
+
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastFlake   time.Time `json:"last_flake,omitempty"`
+	LastKind    string    `json:"last_kind,omitempty"`
+
+	// Quarantine state. Quarantined tests may be skipped or retried by
+	// gates according to MergeQueueConfig.QuarantineMode.
+	Quarantined      bool      `json:"quarantined,omitempty"`
+	QuarantinedAt    time.Time `json:"quarantined_at,omitempty"`
+	QuarantinedBy    string    `json:"quarantined_by,omitempty"`
+	QuarantineReason string    `json:"quarantine_reason,omitempty"`
+}
+
+// pendingFailure remembers which tests failed for an MR at a given head, so
+// a later pass at the same head can be recognized as a flake.
+type pendingFailure struct {
+	MR    string    `json:"mr"`
+	Head  string    `json:"head"`
+	Tests []string  `json:"tests"`
+	At    time.Time `json:"at"`
+}
+
+// flakeState is the on-disk flake database.
+type flakeState struct {
+	Tests   map[string]*FlakeRecord `json:"tests"`
+	Pending []pendingFailure        `json:"pending,omitempty"`
+}
+
+// FlakeStore is the rig-level flake database. The refinery and gt mq flakes
+// run in separate processes, so every update holds a file lock.
+type FlakeStore struct {
+	path string
+	now  func() time.Time
+}
+
+// FlakeDBPath returns the flake database path for a rig.
+func FlakeDBPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "refinery", "flakes.json")
+}
+
+// NewFlakeStore returns a store backed by the file at path.
+func NewFlakeStore(path string) *FlakeStore {
+	return &FlakeStore{path: path, now: time.Now}
+}
+
+// List returns all records, most flaky first.
+func (s *FlakeStore) List() ([]*FlakeRecord, error) {
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*FlakeRecord, 0, len(state.Tests))
+	for _, r := range state.Tests {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Flakes != records[j].Flakes {
+			return records[i].Flakes > records[j].Flakes
+		}
+		return records[i].Test < records[j].Test
+	})
+	return records, nil
+}
+
+// Quarantined returns the set of quarantined test IDs.
+func (s *FlakeStore) Quarantined() (map[string]bool, error) {
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	for id, r := range state.Tests {
+		if r.Quarantined {
+			set[id] = true
+		}
+	}
+	return set, nil
+}
+
+// RecordFailures counts a failure for each test. When mrID and head are
+// set, the failure is also remembered so a later pass of the same MR head
+// can be recognized as a flake (see ResolvePending).
+func (s *FlakeStore) RecordFailures(mrID, head string, tests []string) error {
+	if len(tests) == 0 {
+		return nil
+	}
+	return s.update(func(state *flakeState) error {
+		now := s.now()
+		for _, id := range tests {
+			r := state.record(id)
+			r.Failures++
+			r.LastFailure = now
+		}
+		if mrID != "" && head != "" {
+			state.dropPending(mrID)
+			state.Pending = append(state.Pending, pendingFailure{
+				MR: mrID, Head: head, Tests: append([]string(nil), tests...), At: now,
+			})
+		}
+		return nil
+	})
+}
+
+// RecordFlakes marks tests as having flaked on the given MRs. A test is
+// quarantined once it has flaked autoQuarantine times (0 disables this).
+// Returns the tests that were newly quarantined.
+func (s *FlakeStore) RecordFlakes(kind string, mrIDs, tests []string, autoQuarantine int) ([]string, error) {
+	if len(tests) == 0 {
+		return nil, nil
+	}
+	var quarantined []string
+	err := s.update(func(state *flakeState) error {
+		quarantined = state.addFlakes(s.now(), kind, mrIDs, tests, autoQuarantine)
+		return nil
+	})
+	return quarantined, err
+}
+
+// ResolvePending is called when an MR's gates pass. If the MR previously
+// failed at the same head, the tests that failed then are recorded as
+// flakes. A pending failure at a different head (the branch changed) is
+// discarded. Returns the flaky tests and any newly quarantined tests.
+func (s *FlakeStore) ResolvePending(mrID, head string, autoQuarantine int) (flaky, quarantined []string, err error) {
+	if mrID == "" {
+		return nil, nil, nil
+	}
+	err = s.update(func(state *flakeState) error {
+		for _, p := range state.Pending {
+			if p.MR == mrID && p.Head == head {
+				flaky = p.Tests
+			}
+		}
+		state.dropPending(mrID)
+		if len(flaky) > 0 {
+			quarantined = state.addFlakes(s.now(), FlakeRerunPass, []string{mrID}, flaky, autoQuarantine)
+		}
+		return nil
+	})
+	return flaky, quarantined, err
+}
+
+// Quarantine marks a test as quarantined, creating its record if needed.
+func (s *FlakeStore) Quarantine(test, by, reason string) error {
+	return s.update(func(state *flakeState) error {
+		r := state.record(test)
+		if !r.Quarantined {
+			r.QuarantinedAt = s.now()
+		}
+		r.Quarantined = true
+		r.QuarantinedBy = by
+		r.QuarantineReason = reason
+		return nil
+	})
+}
+
+// Release lifts a test's quarantine. Its flake count and pending failures
+// are reset, so auto-quarantine needs a full threshold of new flakes; the
+// rest of its history is kept.
+func (s *FlakeStore) Release(test string) error {
+	return s.update(func(state *flakeState) error {
+		r, ok := state.Tests[test]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownTest, test)
+		}
+		r.Flakes = 0
+		r.Quarantined = false
+		r.QuarantinedAt = time.Time{}
+		r.QuarantinedBy = ""
+		r.QuarantineReason = ""
+		state.dropPendingTest(test)
+		return nil
+	})
+}
+
+func (s *FlakeStore) load() (*flakeState, error) {
+	state := &flakeState{Tests: make(map[string]*FlakeRecord)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("reading flake database: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing flake database: %w", err)
+	}
+	if state.Tests == nil {
+		state.Tests = make(map[string]*FlakeRecord)
+	}
+	return state, nil
+}
+
+// update applies fn to the database under the file lock and saves it.
+func (s *FlakeStore) update(fn func(*flakeState) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating flake database directory: %w", err)
+	}
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring flake database lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	state.prunePending(s.now())
+	return util.AtomicWriteJSON(s.path, state)
+}
+
+func (st *flakeState) record(id string) *FlakeRecord {
+	r, ok := st.Tests[id]
+	if !ok {
+		r = &FlakeRecord{Test: id}
+		st.Tests[id] = r
+	}
+	return r
+}
+
+func (st *flakeState) addFlakes(now time.Time, kind string, mrIDs, tests []string, threshold int) []string {
+	var quarantined []string
+	for _, id := range tests {
+		r := st.record(id)
+		r.Flakes++
+		r.LastFlake = now
+		r.LastKind = kind
+		for _, mr := range mrIDs {
+			if mr != "" {
+				r.MRs = append(r.MRs, mr)
+			}
+		}
+		if len(r.MRs) > maxFlakeMRs {
+			r.MRs = r.MRs[len(r.MRs)-maxFlakeMRs:]
+		}
+		if threshold > 0 && !r.Quarantined && r.Flakes >= threshold {
+			r.Quarantined = true
+			r.QuarantinedAt = now
+			r.QuarantinedBy = "refinery"
+			r.QuarantineReason = fmt.Sprintf("auto: flaked %d times", r.Flakes)
+			quarantined = append(quarantined, id)
+		}
+	}
+	return quarantined
+}
+
+func (st *flakeState) dropPending(mrID string) {
+	kept := st.Pending[:0]
+	for _, p := range st.Pending {
+		if p.MR != mrID {
+			kept = append(kept, p)
+		}
+	}
+	st.Pending = kept
+}
+
+// dropPendingTest forgets test's pending failures, dropping entries left
+// without tests.
+func (st *flakeState) dropPendingTest(test string) {
+	kept := st.Pending[:0]
+	for _, p := range st.Pending {
+		p.Tests = slices.DeleteFunc(p.Tests, func(t string) bool { return t == test })
+		if len(p.Tests) > 0 {
+			kept = append(kept, p)
+		}
+	}
+	st.Pending = kept
+}
+
+func (st *flakeState) prunePending(now time.Time) {
+	kept := st.Pending[:0]
+	for _, p := range st.Pending {
+		if now.Sub(p.At) < pendingFailureTTL {
+			kept = append(kept, p)
+		}
+	}
+	st.Pending = kept
+}
+
+// onlyQuarantined reports whether every failed test is quarantined. It is
+// false when there are no parsed failures: an unexplained gate failure is
+// never excused by the quarantine list.
+func onlyQuarantined(failed []FailedTest, quarantined map[string]bool) bool {
+	if len(failed) == 0 || len(quarantined) == 0 {
+		return false
+	}
+	for _, ft := range failed {
+		if ft.Name == "" || !quarantined[ft.ID()] {
+			return false
+		}
+	}
+	return true
+}
+
+// quarantineEnv formats the quarantined set for QuarantineEnvVar.
+func quarantineEnv(quarantined map[string]bool) string {
+	ids := make([]string, 0, len(quarantined))
+	for id := range quarantined {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,296 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func newTestFlakeStore(t *testing.T) *FlakeStore {
+	t.Helper()
+	s := NewFlakeStore(filepath.Join(t.TempDir(), "flakes.json"))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func flakeRecord(t *testing.T, s *FlakeStore, id string) *FlakeRecord {
+	t.Helper()
+	records, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		if r.Test == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestFlakeStore_ResolvePending(t *testing.T) {
+	s := newTestFlakeStore(t)
+
+	if err := s.RecordFailures("mr-1", "abc", []string{"p.TestA", "p.TestB"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordFailures("mr-2", "def", []string{"p.TestA"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// mr-1 passes at the same head: both failures were flakes.
+	flaky, _, err := s.ResolvePending("mr-1", "abc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(flaky, ",") != "p.TestA,p.TestB" {
+		t.Errorf("flaky = %v", flaky)
+	}
+
+	// mr-2 was fixed (new head): its failure was real.
+	flaky, _, err = s.ResolvePending("mr-2", "fixed", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flaky) != 0 {
+		t.Errorf("changed head should not count as a flake, got %v", flaky)
+	}
+
+	a := flakeRecord(t, s, "p.TestA")
+	if a.Failures != 2 || a.Flakes != 1 || a.LastKind != FlakeRerunPass || strings.Join(a.MRs, ",") != "mr-1" {
+		t.Errorf("TestA record = %+v", a)
+	}
+
+	// Pending entries are consumed.
+	if flaky, _, _ := s.ResolvePending("mr-1", "abc", 0); len(flaky) != 0 {
+		t.Errorf("pending failure resolved twice: %v", flaky)
+	}
+}
+
+func TestFlakeStore_AutoQuarantine(t *testing.T) {
+	s := newTestFlakeStore(t)
+
+	for i := 1; i <= 3; i++ {
+		q, err := s.RecordFlakes(FlakeRetryPass, []string{fmt.Sprintf("mr-%d", i)}, []string{"p.TestFlaky"}, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (i == 3) != (len(q) == 1) {
+			t.Errorf("flake %d: newly quarantined = %v", i, q)
+		}
+	}
+
+	set, err := s.Quarantined()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set["p.TestFlaky"] {
+		t.Fatal("test should be quarantined after 3 flakes")
+	}
+	r := flakeRecord(t, s, "p.TestFlaky")
+	if r.QuarantinedBy != "refinery" || !strings.Contains(r.QuarantineReason, "3 times") {
+		t.Errorf("record = %+v", r)
+	}
+
+	// Already quarantined: not reported again.
+	if q, _ := s.RecordFlakes(FlakeRetryPass, nil, []string{"p.TestFlaky"}, 3); len(q) != 0 {
+		t.Errorf("re-quarantined: %v", q)
+	}
+}
+
+func TestFlakeStore_QuarantineRelease(t *testing.T) {
+	s := newTestFlakeStore(t)
+
+	if err := s.Quarantine("p.TestSlow", "mayor/", "races on CI"); err != nil {
+		t.Fatal(err)
+	}
+	if set, _ := s.Quarantined(); !set["p.TestSlow"] {
+		t.Fatal("manual quarantine not recorded")
+	}
+	if err := s.Release("p.TestSlow"); err != nil {
+		t.Fatal(err)
+	}
+	r := flakeRecord(t, s, "p.TestSlow")
+	if r == nil || r.Quarantined || r.QuarantineReason != "" {
+		t.Errorf("release should clear quarantine but keep the record: %+v", r)
+	}
+	if err := s.Release("p.TestNope"); !errors.Is(err, ErrUnknownTest) {
+		t.Errorf("Release(unknown) = %v, want ErrUnknownTest", err)
+	}
+
+	// A released test needs a full threshold of new flakes to be
+	// quarantined again.
+	if q, _ := s.RecordFlakes(FlakeRetryPass, nil, []string{"p.TestFlaky", "p.TestFlaky"}, 2); len(q) != 1 {
+		t.Fatalf("auto-quarantine = %v", q)
+	}
+	if err := s.RecordFailures("mr-3", "abc", []string{"p.TestFlaky"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Release("p.TestFlaky"); err != nil {
+		t.Fatal(err)
+	}
+	if flaky, _, _ := s.ResolvePending("mr-3", "abc", 2); len(flaky) != 0 {
+		t.Errorf("pending failure should be dropped on release, got flaky %v", flaky)
+	}
+	if q, _ := s.RecordFlakes(FlakeRetryPass, nil, []string{"p.TestFlaky"}, 2); len(q) != 0 {
+		t.Errorf("one flake after release re-quarantined: %v", q)
+	}
+	if r := flakeRecord(t, s, "p.TestFlaky"); r == nil || r.Flakes != 1 || r.Failures == 0 {
+		t.Errorf("release should reset the flake count and keep failures: %+v", r)
+	}
+}
+
+func TestOnlyQuarantined(t *testing.T) {
+	q := map[string]bool{"p.TestA": true, "p.TestB": true}
+	tests := []struct {
+		name   string
+		failed []FailedTest
+		want   bool
+	}{
+		{"all quarantined", []FailedTest{{Package: "p", Name: "TestA"}, {Package: "p", Name: "TestB"}}, true},
+		{"one real failure", []FailedTest{{Package: "p", Name: "TestA"}, {Package: "p", Name: "TestC"}}, false},
+		{"no parsed failures", nil, false},
+		{"package failure", []FailedTest{{Package: "p"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onlyQuarantined(tt.failed, q); got != tt.want {
+				t.Errorf("onlyQuarantined() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// flakyGateCmd emits a go test -json failure for test on its first run and
+// passes afterwards, tracking runs in counterFile.
+func flakyGateCmd(counterFile, test string) string {
+	event := fmt.Sprintf(`{"Action":"fail","Package":"p","Test":"%s"}`, test)
+	return fmt.Sprintf(`count=$(cat %s 2>/dev/null || echo 0); count=$((count + 1)); echo $count > %s; if [ $count -lt 2 ]; then echo '%s'; exit 1; fi`,
+		counterFile, counterFile, event)
+}
+
+func TestRunGate_QuarantineModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	quarantined := map[string]bool{"p.TestFlaky": true}
+
+	tests := []struct {
+		name       string
+		mode       string
+		test       string
+		wantPass   bool
+		wantRuns   string
+		wantFlaky  bool
+		wantIgnore bool
+	}{
+		{"retry quarantined", QuarantineRetry, "TestFlaky", true, "2", true, false},
+		{"retry skips real failures", QuarantineRetry, "TestReal", false, "1", false, false},
+		{"skip quarantined", QuarantineSkip, "TestFlaky", true, "1", false, true},
+		{"off", QuarantineOff, "TestFlaky", false, "1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+			e.workDir = t.TempDir()
+			e.output = io.Discard
+			e.config.QuarantineMode = tt.mode
+
+			counter := filepath.Join(t.TempDir(), "count")
+			q := quarantined
+			if tt.mode == QuarantineOff {
+				q = nil // runGates doesn't load the list when off
+			}
+			result := e.runGate(context.Background(), "test", &GateConfig{Cmd: flakyGateCmd(counter, tt.test)}, q)
+
+			if result.Success != tt.wantPass {
+				t.Errorf("Success = %v, want %v (%s)", result.Success, tt.wantPass, result.Error)
+			}
+			if runs, _ := os.ReadFile(counter); strings.TrimSpace(string(runs)) != tt.wantRuns {
+				t.Errorf("gate ran %s times, want %s", strings.TrimSpace(string(runs)), tt.wantRuns)
+			}
+			if (len(result.Flaky) > 0) != tt.wantFlaky {
+				t.Errorf("Flaky = %v", result.Flaky)
+			}
+			if (len(result.Quarantined) > 0) != tt.wantIgnore {
+				t.Errorf("Quarantined = %v", result.Quarantined)
+			}
+		})
+	}
+}
+
+func TestRunGate_QuarantineEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+	out := filepath.Join(t.TempDir(), "env")
+
+	e.runGate(context.Background(), "env", &GateConfig{Cmd: "echo $" + QuarantineEnvVar + " > " + out},
+		map[string]bool{"p.TestB": true, "p.TestA": true})
+
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "p.TestA,p.TestB" {
+		t.Errorf("%s = %q", QuarantineEnvVar, got)
+	}
+}
+
+func TestRunGates_RecordsQuarantinedRetryAsFlake(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	if err := e.flakes.Quarantine("p.TestFlaky", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: flakyGateCmd(filepath.Join(t.TempDir(), "count"), "TestFlaky")},
+	}
+
+	if result := e.runGates(context.Background(), "mr-1"); !result.Success {
+		t.Fatalf("quarantined flake should pass on retry: %s", result.Error)
+	}
+	r := flakeRecord(t, e.flakes, "p.TestFlaky")
+	if r.Flakes != 1 || r.LastKind != FlakeRetryPass || strings.Join(r.MRs, ",") != "mr-1" {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestProcessBatch_RetryRecordsFlakes(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "hello a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "hello b\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.FlakeQuarantineThreshold = 1
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: flakyGateCmd(filepath.Join(t.TempDir(), "count"), "TestNetwork")},
+	}
+
+	batch := []*MRInfo{makeMR("mr-a", "feature-a", "main"), makeMR("mr-b", "feature-b", "main")}
+	result := e.ProcessBatch(context.Background(), batch, "main", &BatchConfig{MaxBatchSize: 5, RetryBatchOnFlaky: true})
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("expected both MRs merged after retry: err=%v merged=%d", result.Error, len(result.Merged))
+	}
+
+	r := flakeRecord(t, e.flakes, "p.TestNetwork")
+	if r == nil || r.Flakes != 1 || strings.Join(r.MRs, ",") != "mr-a,mr-b" {
+		t.Fatalf("record = %+v", r)
+	}
+	if !r.Quarantined {
+		t.Error("threshold 1 should quarantine on first flake")
+	}
+}
@@ -0,0 +1,337 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// gateReportFile is the per-MR summary written next to the gate logs.
+const gateReportFile = "report.json"
+
+// failedTestOutputLen caps the output excerpt kept for each failed test.
+const failedTestOutputLen = 2000
+
+// FailedTest is a single failing test extracted from a gate's test report.
+type FailedTest struct {
+	// Package is the Go package or JUnit classname/suite. May be empty.
+	Package string `json:"package,omitempty"`
+
+	// Name is the test name. Empty for package-level failures such as a
+	// build error or a panic in TestMain.
+	Name string `json:"name,omitempty"`
+
+	// Output is the tail of the test's output.
+	Output string `json:"output,omitempty"`
+}
+
+// ID returns the test's identity as "package.Name", or whichever half is set.
+func (f FailedTest) ID() string {
+	switch {
+	case f.Package == "":
+		return f.Name
+	case f.Name == "":
+		return f.Package
+	default:
+		return f.Package + "." + f.Name
+	}
+}
+
+// GateSummary is the recorded outcome of one gate in a GateReport.
+type GateSummary struct {
+	Name        string        `json:"name"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	Elapsed     time.Duration `json:"elapsed"`
+	StdoutLog   string        `json:"stdout_log,omitempty"`
+	StderrLog   string        `json:"stderr_log,omitempty"`
+	ReportFile  string        `json:"report_file,omitempty"`
+	FailedTests []FailedTest  `json:"failed_tests,omitempty"`
+}
+
+// GateReport summarizes a gate run for one MR. It is written to
+// report.json in the MR's artifact directory.
+type GateReport struct {
+	MRID      string        `json:"mr_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Gates     []GateSummary `json:"gates"`
+}
+
+// FailedTestIDs returns the sorted IDs of all failed tests across gates.
+func (r *GateReport) FailedTestIDs() []string {
+	var all []FailedTest
+	for _, g := range r.Gates {
+		all = append(all, g.FailedTests...)
+	}
+	return failedTestIDs(all)
+}
+
+// GateArtifactsDir returns the directory holding gate logs and the report
+// for an MR: <rig>/.runtime/refinery/gates/<mr-id>.
+func GateArtifactsDir(rigPath, mrID string) string {
+	return filepath.Join(rigPath, ".runtime", "refinery", "gates", mrID)
+}
+
+// LoadGateReport reads report.json from an MR's artifact directory.
+func LoadGateReport(dir string) (*GateReport, error) {
+	data, err := os.ReadFile(filepath.Join(dir, gateReportFile))
+	if err != nil {
+		return nil, err
+	}
+	var report GateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing gate report: %w", err)
+	}
+	return &report, nil
+}
+
+// writeGateArtifacts replaces the contents of dir with the stdout/stderr
+// logs of each gate and a report.json summary. Logs from a previous attempt
+// are removed so the directory always reflects the latest run.
+func writeGateArtifacts(dir, mrID string, results []GateResult) (*GateReport, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("clearing gate artifacts: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating gate artifacts dir: %w", err)
+	}
+
+	report := &GateReport{MRID: mrID, CreatedAt: time.Now().UTC()}
+	for _, r := range results {
+		summary := GateSummary{
+			Name:        r.Name,
+			Success:     r.Success,
+			Error:       r.Error,
+			Elapsed:     r.Elapsed,
+			FailedTests: r.FailedTests,
+		}
+		var err error
+		if summary.StdoutLog, err = writeGateLog(dir, r.Name+".stdout.log", r.stdout); err != nil {
+			return nil, err
+		}
+		if summary.StderrLog, err = writeGateLog(dir, r.Name+".stderr.log", r.stderr); err != nil {
+			return nil, err
+		}
+		if len(r.report) > 0 {
+			name := r.Name + ".report" + filepath.Ext(r.reportPath)
+			if summary.ReportFile, err = writeGateLog(dir, name, r.report); err != nil {
+				return nil, err
+			}
+		}
+		report.Gates = append(report.Gates, summary)
+	}
+
+	if err := util.AtomicWriteJSON(filepath.Join(dir, gateReportFile), report); err != nil {
+		return nil, fmt.Errorf("writing gate report: %w", err)
+	}
+	return report, nil
+}
+
+// writeGateLog writes data to dir/name and returns the path, or "" if data
+// is empty.
+func writeGateLog(dir, name string, data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: logs are not secrets
+		return "", fmt.Errorf("writing %s: %w", name, err)
+	}
+	return path, nil
+}
+
+// ParseTestReport extracts failed tests from gate output or a report file.
+// It recognizes `go test -json` event streams and JUnit XML; anything else
+// yields no failures.
+func ParseTestReport(data []byte) []FailedTest {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	if trimmed[0] == '<' {
+		failed, err := ParseJUnitXML(trimmed)
+		if err != nil {
+			return nil
+		}
+		return failed
+	}
+	return ParseGoTestJSON(data)
+}
+
+// goTestEvent is one line of `go test -json` (test2json) output.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+// ParseGoTestJSON extracts failed tests from a `go test -json` stream.
+// Non-JSON lines (e.g. build output interleaved on stdout) are ignored.
+// When a subtest fails only the subtest is reported, not its parents.
+// A package that fails without any failing test (build error, panic in
+// TestMain) is reported as a package-level failure.
+func ParseGoTestJSON(data []byte) []FailedTest {
+	type key struct{ pkg, test string }
+	output := make(map[key]*strings.Builder)
+	var failed []key
+	pkgFailed := make(map[string]bool)
+	var pkgOrder []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Action == "" {
+			continue
+		}
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			sb := output[k]
+			if sb == nil {
+				sb = &strings.Builder{}
+				output[k] = sb
+			}
+			sb.WriteString(ev.Output)
+		case "fail":
+			if ev.Test != "" {
+				failed = append(failed, k)
+			} else if !pkgFailed[ev.Package] {
+				pkgFailed[ev.Package] = true
+				pkgOrder = append(pkgOrder, ev.Package)
+			}
+		}
+	}
+
+	// Drop parents of failed subtests: "TestA" fails whenever "TestA/sub" does.
+	isParent := make(map[key]bool)
+	for _, k := range failed {
+		for i := strings.LastIndex(k.test, "/"); i > 0; i = strings.LastIndex(k.test[:i], "/") {
+			isParent[key{k.pkg, k.test[:i]}] = true
+		}
+	}
+
+	var result []FailedTest
+	pkgHasTests := make(map[string]bool)
+	for _, k := range failed {
+		pkgHasTests[k.pkg] = true
+		if isParent[k] {
+			continue
+		}
+		result = append(result, FailedTest{Package: k.pkg, Name: k.test, Output: outputTail(output[k])})
+	}
+	for _, pkg := range pkgOrder {
+		if !pkgHasTests[pkg] {
+			result = append(result, FailedTest{Package: pkg, Output: outputTail(output[key{pkg, ""}])})
+		}
+	}
+	return result
+}
+
+// junitSuite matches both <testsuites> and <testsuite> elements, which may
+// nest arbitrarily.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failures  []junitResult `xml:"failure"`
+	Errors    []junitResult `xml:"error"`
+	SystemOut string        `xml:"system-out"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ParseJUnitXML extracts failed and errored test cases from a JUnit XML
+// report. The root may be <testsuites> or a single <testsuite>.
+func ParseJUnitXML(data []byte) ([]FailedTest, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing JUnit XML: %w", err)
+	}
+	var result []FailedTest
+	collectJUnitFailures(root, &result)
+	return result, nil
+}
+
+func collectJUnitFailures(suite junitSuite, out *[]FailedTest) {
+	for _, tc := range suite.Cases {
+		problems := append(append([]junitResult{}, tc.Failures...), tc.Errors...)
+		if len(problems) == 0 {
+			continue
+		}
+		pkg := tc.ClassName
+		if pkg == "" {
+			pkg = suite.Name
+		}
+		var sb strings.Builder
+		for _, p := range problems {
+			if p.Message != "" {
+				sb.WriteString(p.Message)
+				sb.WriteString("\n")
+			}
+			if body := strings.TrimSpace(p.Body); body != "" {
+				sb.WriteString(body)
+				sb.WriteString("\n")
+			}
+		}
+		*out = append(*out, FailedTest{Package: pkg, Name: tc.Name, Output: outputTail(&sb)})
+	}
+	for _, child := range suite.Suites {
+		collectJUnitFailures(child, out)
+	}
+}
+
+// outputTail returns the last failedTestOutputLen bytes of sb, cut on a line
+// boundary where possible.
+func outputTail(sb *strings.Builder) string {
+	if sb == nil {
+		return ""
+	}
+	s := strings.TrimSpace(sb.String())
+	if len(s) <= failedTestOutputLen {
+		return s
+	}
+	s = s[len(s)-failedTestOutputLen:]
+	if idx := strings.IndexByte(s, '\n'); idx != -1 && idx < len(s)-1 {
+		s = s[idx+1:]
+	}
+	return "…\n" + s
+}
+
+// failedTestIDs returns the sorted, de-duplicated IDs of failed tests.
+func failedTestIDs(tests []FailedTest) []string {
+	seen := make(map[string]bool, len(tests))
+	var ids []string
+	for _, ft := range tests {
+		id := ft.ID()
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
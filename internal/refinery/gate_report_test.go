@@ -0,0 +1,282 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+const goTestJSONSample = `{"Action":"start","Package":"example.com/calc"}
+{"Action":"run","Package":"example.com/calc","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/calc","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Action":"pass","Package":"example.com/calc","Test":"TestAdd","Elapsed":0}
+{"Action":"run","Package":"example.com/calc","Test":"TestDiv"}
+{"Action":"run","Package":"example.com/calc","Test":"TestDiv/by_zero"}
+{"Action":"output","Package":"example.com/calc","Test":"TestDiv/by_zero","Output":"    calc_test.go:20: expected error, got nil\n"}
+{"Action":"fail","Package":"example.com/calc","Test":"TestDiv/by_zero","Elapsed":0}
+{"Action":"fail","Package":"example.com/calc","Test":"TestDiv","Elapsed":0}
+{"Action":"run","Package":"example.com/calc","Test":"TestMul"}
+{"Action":"output","Package":"example.com/calc","Test":"TestMul","Output":"    calc_test.go:31: 2*3 = 5\n"}
+{"Action":"fail","Package":"example.com/calc","Test":"TestMul","Elapsed":0}
+{"Action":"fail","Package":"example.com/calc","Elapsed":0.01}
+# example.com/broken
+broken.go:3:1: syntax error
+{"Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0}
+{"Action":"pass","Package":"example.com/ok","Elapsed":0}
+`
+
+const junitSample = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api" tests="3" failures="1" errors="1">
+    <testcase classname="api.UserTest" name="testCreate"/>
+    <testcase classname="api.UserTest" name="testDelete">
+      <failure message="expected 204, got 500">AssertionError at UserTest.java:42</failure>
+    </testcase>
+    <testcase name="testTimeout">
+      <error message="timed out"/>
+    </testcase>
+    <testcase classname="api.UserTest" name="testSkip"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+
+func TestParseGoTestJSON(t *testing.T) {
+	got := ParseGoTestJSON([]byte(goTestJSONSample))
+
+	var ids []string
+	for _, ft := range got {
+		ids = append(ids, ft.ID())
+	}
+	want := "example.com/calc.TestDiv/by_zero,example.com/calc.TestMul,example.com/broken"
+	if strings.Join(ids, ",") != want {
+		t.Fatalf("failed tests = %v, want %s", ids, want)
+	}
+	if !strings.Contains(got[0].Output, "expected error, got nil") {
+		t.Errorf("subtest output not captured: %q", got[0].Output)
+	}
+	if !strings.Contains(got[2].Output, "build failed") {
+		t.Errorf("package-level output not captured: %q", got[2].Output)
+	}
+}
+
+func TestParseJUnitXML(t *testing.T) {
+	got, err := ParseJUnitXML([]byte(junitSample))
+	if err != nil {
+		t.Fatalf("ParseJUnitXML: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d failures, want 2: %+v", len(got), got)
+	}
+	if got[0].ID() != "api.UserTest.testDelete" {
+		t.Errorf("first failure = %q", got[0].ID())
+	}
+	if !strings.Contains(got[0].Output, "expected 204, got 500") || !strings.Contains(got[0].Output, "UserTest.java:42") {
+		t.Errorf("failure output = %q", got[0].Output)
+	}
+	if got[1].ID() != "api.testTimeout" {
+		t.Errorf("error without classname should use suite name, got %q", got[1].ID())
+	}
+
+	if _, err := ParseJUnitXML([]byte("<testsuite><testcase")); err == nil {
+		t.Error("expected error for truncated XML")
+	}
+}
+
+func TestParseTestReport(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"go test -json", goTestJSONSample, 3},
+		{"junit", junitSample, 2},
+		{"single testsuite root", `<testsuite name="s"><testcase name="a"><failure/></testcase></testsuite>`, 1},
+		{"plain text", "--- FAIL: TestX\nFAIL\n", 0},
+		{"empty", "", 0},
+		{"malformed xml", "<not xml", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTestReport([]byte(tt.data)); len(got) != tt.want {
+				t.Errorf("ParseTestReport() = %d failures, want %d: %+v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutputTail(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 200; i++ {
+		sb.WriteString("some test output line\n")
+	}
+	got := outputTail(&sb)
+	if !strings.HasPrefix(got, "…\nsome test output line") {
+		t.Errorf("tail should start on a line boundary, got %q", got[:40])
+	}
+	if len(got) > failedTestOutputLen+len("…\n") {
+		t.Errorf("tail is %d bytes, cap is %d", len(got), failedTestOutputLen)
+	}
+}
+
+func TestRunGates_WritesArtifacts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	rigPath := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	sample := filepath.Join(t.TempDir(), "events.json")
+	if err := os.WriteFile(sample, []byte(goTestJSONSample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.config.Gates = map[string]*GateConfig{
+		"lint": {Cmd: "echo lint ok"},
+		"test": {Cmd: "cat " + sample + "; echo oops >&2; exit 1"},
+	}
+
+	result := e.runGates(context.Background(), "gt-mr1")
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("expected test failure, got %+v", result)
+	}
+	wantIDs := "example.com/broken,example.com/calc.TestDiv/by_zero,example.com/calc.TestMul"
+	if strings.Join(result.FailedTests, ",") != wantIDs {
+		t.Errorf("FailedTests = %v, want %s", result.FailedTests, wantIDs)
+	}
+	dir := GateArtifactsDir(rigPath, "gt-mr1")
+	if result.ArtifactDir != dir {
+		t.Errorf("ArtifactDir = %q, want %q", result.ArtifactDir, dir)
+	}
+
+	report, err := LoadGateReport(dir)
+	if err != nil {
+		t.Fatalf("LoadGateReport: %v", err)
+	}
+	if report.MRID != "gt-mr1" || len(report.Gates) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	lint, test := report.Gates[0], report.Gates[1]
+	if !lint.Success || lint.StdoutLog == "" || lint.StderrLog != "" {
+		t.Errorf("lint summary = %+v", lint)
+	}
+	if test.Success || len(test.FailedTests) != 3 {
+		t.Errorf("test summary = %+v", test)
+	}
+	stdout, err := os.ReadFile(test.StdoutLog)
+	if err != nil || string(stdout) != goTestJSONSample {
+		t.Errorf("stdout log not captured in full (err=%v)", err)
+	}
+	if stderr, _ := os.ReadFile(test.StderrLog); strings.TrimSpace(string(stderr)) != "oops" {
+		t.Errorf("stderr log = %q", stderr)
+	}
+
+	// A later passing run replaces the previous attempt's artifacts.
+	e.config.Gates = map[string]*GateConfig{"lint": {Cmd: "true"}}
+	if result := e.runGates(context.Background(), "gt-mr1"); !result.Success {
+		t.Fatalf("expected success: %s", result.Error)
+	}
+	if _, err := os.Stat(test.StdoutLog); !os.IsNotExist(err) {
+		t.Error("stale gate log from previous run should be removed")
+	}
+}
+
+func TestRunTests_WritesArtifacts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command runs via sh -c")
+	}
+	rigPath := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	sample := filepath.Join(t.TempDir(), "events.json")
+	if err := os.WriteFile(sample, []byte(goTestJSONSample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.config.RunTests = true
+	e.config.TestCommand = "cat " + sample + "; exit 1"
+
+	// A batch run covers every MR in the stack.
+	result := e.runBatchGates(context.Background(), []*MRInfo{{ID: "gt-mr1"}, {ID: "gt-mr2"}})
+	if result.Success || !result.TestsFailed || len(result.FailedTests) != 3 {
+		t.Fatalf("expected test failure with parsed tests, got %+v", result)
+	}
+	if result.ArtifactDir != GateArtifactsDir(rigPath, "gt-mr1") {
+		t.Errorf("ArtifactDir = %q", result.ArtifactDir)
+	}
+	for _, id := range []string{"gt-mr1", "gt-mr2"} {
+		report, err := LoadGateReport(GateArtifactsDir(rigPath, id))
+		if err != nil {
+			t.Fatalf("LoadGateReport(%s): %v", id, err)
+		}
+		if report.MRID != id || len(report.Gates) != 1 || report.Gates[0].Name != legacyTestGate {
+			t.Fatalf("report for %s = %+v", id, report)
+		}
+		if got := report.FailedTestIDs(); len(got) != 3 {
+			t.Errorf("report for %s failed tests = %v", id, got)
+		}
+	}
+}
+
+func TestRunGate_ReportFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+
+	junit := filepath.Join(t.TempDir(), "junit.xml")
+	if err := os.WriteFile(junit, []byte(junitSample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := e.runGate(context.Background(), "test", &GateConfig{
+		Cmd:    "cp " + junit + " out/junit.xml; exit 1",
+		Report: "out/junit.xml",
+	}, nil)
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	// out/ doesn't exist, so the report was never written.
+	if len(result.FailedTests) != 0 || result.report != nil {
+		t.Errorf("missing report should yield no failed tests, got %+v", result.FailedTests)
+	}
+
+	if err := os.MkdirAll(filepath.Join(e.workDir, "out"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	result = e.runGate(context.Background(), "test", &GateConfig{
+		Cmd:    "cp " + junit + " out/junit.xml; echo not a report; exit 1",
+		Report: "out/junit.xml",
+	}, nil)
+	if len(result.FailedTests) != 2 || result.FailedTests[0].ID() != "api.UserTest.testDelete" {
+		t.Errorf("FailedTests = %+v", result.FailedTests)
+	}
+}
+
+func TestGateReportSuffix(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ProcessResult
+		want   string
+	}{
+		{"nothing", ProcessResult{}, ""},
+		{"tests and logs", ProcessResult{FailedTests: []string{"p.TestA", "p.TestB"}, ArtifactDir: "/gates/mr1"},
+			" failed_tests=p.TestA,p.TestB logs=/gates/mr1"},
+		{"capped", ProcessResult{FailedTests: []string{"a", "b", "c", "d", "e", "f", "g"}},
+			" failed_tests=a,b,c,d,e (+2 more)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gateReportSuffix(tt.result); got != tt.want {
+				t.Errorf("gateReportSuffix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
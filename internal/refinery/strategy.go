@@ -0,0 +1,148 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// rebaseScratchBranch is the local branch used to rebase an MR before
+// fast-forwarding the target with the rebase strategy.
+const rebaseScratchBranch = "refinery/rebase-scratch"
+
+// errMergeConflict is returned by applyMerge when the strategy stopped on
+// conflicting files. The working tree has already been restored.
+var errMergeConflict = errors.New("merge conflict")
+
+// mergeStrategyFor resolves the strategy for an MR: the MR bead's
+// merge_strategy field, then the rig's merge_queue.merge_strategy, then
+// config.DefaultMergeStrategy. Unknown per-MR values fall back to the rig
+// default.
+func (e *Engineer) mergeStrategyFor(mr *MRInfo) string {
+	if mr != nil && config.IsValidMergeStrategy(mr.MergeStrategy) {
+		return mr.MergeStrategy
+	}
+	if config.IsValidMergeStrategy(e.config.MergeStrategy) {
+		return e.config.MergeStrategy
+	}
+	return config.DefaultMergeStrategy
+}
+
+// applyMerge lands an MR's branch on the currently checked-out target
+// branch using the MR's merge strategy. Nothing is pushed. On conflict the
+// merge or rebase is aborted and errMergeConflict is returned.
+func (e *Engineer) applyMerge(mr *MRInfo, target string) error {
+	strategy := e.mergeStrategyFor(mr)
+	switch strategy {
+	case config.MergeStrategyMergeCommit:
+		msg := e.mergeCommitMessage(mr, target)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merging %s with merge commit\n", mr.Branch)
+		if err := e.git.MergeNoFF(mr.Branch, msg); err != nil {
+			return e.abortOnConflict(err, e.git.AbortMerge)
+		}
+	case config.MergeStrategyRebase:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Rebasing %s onto %s\n", mr.Branch, target)
+		return e.rebaseMerge(mr.Branch, target)
+	default:
+		msg := e.getMergeMessage(mr)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", firstLine(msg))
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			return e.abortOnConflict(err, e.git.AbortMerge)
+		}
+	}
+	return nil
+}
+
+// rebaseMerge replays branch onto target on a scratch branch, then
+// fast-forwards target to it, so every commit on the branch lands as-is.
+// The MR branch itself is left untouched.
+func (e *Engineer) rebaseMerge(branch, target string) error {
+	_ = e.git.DeleteBranch(rebaseScratchBranch, true)
+	if err := e.git.CheckoutNewBranch(rebaseScratchBranch, branch); err != nil {
+		return fmt.Errorf("creating rebase branch from %s: %w", branch, err)
+	}
+	cleanup := func() {
+		_ = e.git.Checkout(target)
+		_ = e.git.DeleteBranch(rebaseScratchBranch, true)
+	}
+
+	if err := e.git.Rebase(target); err != nil {
+		err = e.abortOnConflict(err, e.git.AbortRebase)
+		cleanup()
+		return err
+	}
+	if err := e.git.Checkout(target); err != nil {
+		cleanup()
+		return fmt.Errorf("checkout %s: %w", target, err)
+	}
+	if err := e.git.MergeFFOnly(rebaseScratchBranch); err != nil {
+		cleanup()
+		return fmt.Errorf("fast-forward %s: %w", target, err)
+	}
+	_ = e.git.DeleteBranch(rebaseScratchBranch, true)
+	return nil
+}
+
+// abortOnConflict classifies a failed merge or rebase. If git left
+// conflicting files, the operation is aborted and errMergeConflict is
+// returned; otherwise err is returned unchanged.
+func (e *Engineer) abortOnConflict(err error, abort func() error) error {
+	// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
+	conflicts, conflictErr := e.git.GetConflictingFiles()
+	if conflictErr == nil && len(conflicts) > 0 {
+		_ = abort()
+		return fmt.Errorf("%w in %s", errMergeConflict, strings.Join(conflicts, ", "))
+	}
+	return err
+}
+
+// mergeCommitMessage builds the message for the merge-commit strategy:
+// a merge subject, the branch's head commit subject, and trailers naming
+// the source issue and polecat.
+func (e *Engineer) mergeCommitMessage(mr *MRInfo, target string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Merge branch '%s' into %s\n", mr.Branch, target)
+
+	if head, err := e.git.GetBranchCommitMessage(mr.Branch); err == nil {
+		if subject := firstLine(head); subject != "" {
+			fmt.Fprintf(&sb, "\n%s\n", subject)
+		}
+	}
+
+	var trailers []string
+	if mr.SourceIssue != "" {
+		trailers = append(trailers, "Issue: "+mr.SourceIssue)
+	}
+	if polecat := mrPolecatName(mr); polecat != "" {
+		trailers = append(trailers, "Polecat: "+polecat)
+	}
+	if len(trailers) > 0 {
+		fmt.Fprintf(&sb, "\n%s\n", strings.Join(trailers, "\n"))
+	}
+	return sb.String()
+}
+
+// mrPolecatName returns the polecat that did the work, from the MR's
+// worker field ("nux" or "gastown/polecats/nux") or its branch name
+// ("polecat/nux/gt-abc").
+func mrPolecatName(mr *MRInfo) string {
+	if mr.Worker != "" {
+		parts := strings.Split(strings.TrimSuffix(mr.Worker, "/"), "/")
+		return parts[len(parts)-1]
+	}
+	if rest, ok := strings.CutPrefix(mr.Branch, "polecat/"); ok {
+		name, _, _ := strings.Cut(rest, "/")
+		return name
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		s = s[:idx]
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,195 @@
+package refinery
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestMergeStrategyFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		rigDefault string
+		mrStrategy string
+		want       string
+	}{
+		{"built-in default", "", "", config.DefaultMergeStrategy},
+		{"rig default", config.MergeStrategyRebase, "", config.MergeStrategyRebase},
+		{"MR overrides rig", config.MergeStrategyRebase, config.MergeStrategyMergeCommit, config.MergeStrategyMergeCommit},
+		{"unknown MR value ignored", config.MergeStrategyMergeCommit, "octopus", config.MergeStrategyMergeCommit},
+		{"unknown rig value ignored", "octopus", "", config.DefaultMergeStrategy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engineer{config: &MergeQueueConfig{MergeStrategy: tt.rigDefault}}
+			if got := e.mergeStrategyFor(&MRInfo{MergeStrategy: tt.mrStrategy}); got != tt.want {
+				t.Errorf("mergeStrategyFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// The patrol formula tells the refinery agent which strategy to use when
+// the rig sets none; it must agree with the Go engineer.
+func TestPatrolFormulaMergeStrategyDefault(t *testing.T) {
+	content, err := formula.GetEmbeddedFormulaContent("mol-refinery-patrol")
+	if err != nil {
+		t.Fatalf("reading patrol formula: %v", err)
+	}
+	f, err := formula.Parse(content)
+	if err != nil {
+		t.Fatalf("parsing patrol formula: %v", err)
+	}
+	v, ok := f.Vars["merge_strategy"]
+	if !ok {
+		t.Fatal("patrol formula has no merge_strategy var")
+	}
+	if v.Default != config.DefaultMergeStrategy {
+		t.Errorf("patrol formula merge_strategy default = %q, want %q (config.DefaultMergeStrategy)", v.Default, config.DefaultMergeStrategy)
+	}
+	if got := DefaultMergeQueueConfig().MergeStrategy; got != config.DefaultMergeStrategy {
+		t.Errorf("engineer default merge strategy = %q, want %q", got, config.DefaultMergeStrategy)
+	}
+}
+
+func TestMRPolecatName(t *testing.T) {
+	tests := []struct {
+		mr   MRInfo
+		want string
+	}{
+		{MRInfo{Worker: "nux"}, "nux"},
+		{MRInfo{Worker: "gastown/polecats/nux"}, "nux"},
+		{MRInfo{Branch: "polecat/furiosa/gt-abc"}, "furiosa"},
+		{MRInfo{Branch: "feature-x"}, ""},
+	}
+	for _, tt := range tests {
+		if got := mrPolecatName(&tt.mr); got != tt.want {
+			t.Errorf("mrPolecatName(%+v) = %q, want %q", tt.mr, got, tt.want)
+		}
+	}
+}
+
+// setupStrategyRepo creates a two-commit polecat branch and advances main
+// with an unrelated commit, so every strategy has real work to do.
+func setupStrategyRepo(t *testing.T) (string, *Engineer, *MRInfo) {
+	t.Helper()
+	workDir, g, _ := testGitRepo(t)
+
+	branch := "polecat/nux/gt-abc"
+	run(t, workDir, "git", "checkout", "-b", branch, "main")
+	writeFile(t, workDir, "a.txt", "a\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "feat: add a")
+	writeFile(t, workDir, "b.txt", "b\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "feat: add b")
+	run(t, workDir, "git", "checkout", "main")
+
+	writeFile(t, workDir, "main.txt", "main\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "chore: main moves on")
+	run(t, workDir, "git", "push", "origin", "main")
+
+	e := newTestEngineer(t, workDir, g)
+	mr := makeMR("mr-1", branch, "main")
+	mr.SourceIssue = "gt-abc"
+	mr.Worker = "gastown/polecats/nux"
+	return workDir, e, mr
+}
+
+func TestProcessMRInfo_Squash(t *testing.T) {
+	workDir, e, mr := setupStrategyRepo(t)
+	mr.MergeStrategy = config.MergeStrategySquash
+
+	result := e.ProcessMRInfo(context.Background(), mr)
+	if !result.Success {
+		t.Fatalf("merge failed: %s", result.Error)
+	}
+
+	if got := run(t, workDir, "git", "log", "--format=%s", "origin/main~1..origin/main"); got != "feat: add b" {
+		t.Errorf("squash should land one commit with the branch head message, got %q", got)
+	}
+	if parents := run(t, workDir, "git", "log", "-1", "--format=%P", "origin/main"); strings.Contains(parents, " ") {
+		t.Errorf("squash commit should have one parent, got %q", parents)
+	}
+	run(t, workDir, "git", "cat-file", "-e", "origin/main:a.txt")
+}
+
+func TestProcessMRInfo_MergeCommit(t *testing.T) {
+	workDir, e, mr := setupStrategyRepo(t)
+	mr.MergeStrategy = config.MergeStrategyMergeCommit
+
+	result := e.ProcessMRInfo(context.Background(), mr)
+	if !result.Success {
+		t.Fatalf("merge failed: %s", result.Error)
+	}
+
+	if parents := strings.Fields(run(t, workDir, "git", "log", "-1", "--format=%P", "origin/main")); len(parents) != 2 {
+		t.Errorf("merge commit should have two parents, got %v", parents)
+	}
+	msg := run(t, workDir, "git", "log", "-1", "--format=%B", "origin/main")
+	for _, want := range []string{"Merge branch 'polecat/nux/gt-abc' into main", "feat: add b", "Issue: gt-abc", "Polecat: nux"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("merge message missing %q:\n%s", want, msg)
+		}
+	}
+	if trailers := run(t, workDir, "git", "log", "-1", "--format=%(trailers:only)", "origin/main"); !strings.Contains(trailers, "Issue: gt-abc") {
+		t.Errorf("Issue should be a git trailer, got %q", trailers)
+	}
+}
+
+func TestProcessMRInfo_Rebase(t *testing.T) {
+	workDir, e, mr := setupStrategyRepo(t)
+	e.config.MergeStrategy = config.MergeStrategyRebase // rig default, no MR override
+	branchHead := run(t, workDir, "git", "rev-parse", mr.Branch)
+
+	result := e.ProcessMRInfo(context.Background(), mr)
+	if !result.Success {
+		t.Fatalf("merge failed: %s", result.Error)
+	}
+
+	got := run(t, workDir, "git", "log", "--format=%s", "-3", "origin/main")
+	if got != "feat: add b\nfeat: add a\nchore: main moves on" {
+		t.Errorf("rebase should preserve branch commits on top of main, got:\n%s", got)
+	}
+	if parents := run(t, workDir, "git", "log", "-1", "--format=%P", "origin/main"); strings.Contains(parents, " ") {
+		t.Errorf("rebase should not create a merge commit, got parents %q", parents)
+	}
+	if head := run(t, workDir, "git", "rev-parse", mr.Branch); head != branchHead {
+		t.Error("rebase strategy should not rewrite the MR branch itself")
+	}
+	if out := run(t, workDir, "git", "branch", "--list", rebaseScratchBranch); out != "" {
+		t.Errorf("scratch branch left behind: %q", out)
+	}
+	if cur := run(t, workDir, "git", "branch", "--show-current"); cur != "main" {
+		t.Errorf("expected to finish on main, on %q", cur)
+	}
+}
+
+func TestProcessMRInfo_RebaseConflict(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createConflictingBranch(t, workDir, "feature-x", "README.md", "# from branch\n")
+	writeFile(t, workDir, "README.md", "# from main\n")
+	run(t, workDir, "git", "commit", "-am", "docs: main edit")
+	run(t, workDir, "git", "push", "origin", "main")
+
+	e := newTestEngineer(t, workDir, g)
+	mr := makeMR("mr-x", "feature-x", "main")
+	mr.MergeStrategy = config.MergeStrategyRebase
+
+	// Exercise applyMerge directly: doMerge's pre-check would catch this
+	// conflict before the strategy runs.
+	err := e.applyMerge(mr, "main")
+	if err == nil || !strings.Contains(err.Error(), errMergeConflict.Error()) {
+		t.Fatalf("applyMerge() = %v, want merge conflict", err)
+	}
+	if cur := run(t, workDir, "git", "branch", "--show-current"); cur != "main" {
+		t.Errorf("expected to be back on main, on %q", cur)
+	}
+	if status := run(t, workDir, "git", "status", "--porcelain"); status != "" {
+		t.Errorf("working tree not clean after aborted rebase:\n%s", status)
+	}
+}
@@ -589,20 +589,20 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		_, _ = typesCmd.CombinedOutput()
 	}
 
-	// Auto-create DoltHub remote for the rig's beads database.
-	// Requires DOLTHUB_TOKEN and DOLTHUB_ORG environment variables.
+	// Auto-create a remote for the rig's beads database on the configured
+	// backend: GT_DOLT_REMOTE, or DoltHub with DOLTHUB_TOKEN and DOLTHUB_ORG.
 	// Non-fatal: sync will work without a remote; user can add one manually later.
-	if token := doltserver.DoltHubToken(); token != "" {
-		if org := doltserver.DoltHubOrg(); org != "" {
-			dbName := "beads_" + opts.Name
-			dbDir := doltserver.RigDatabaseDir(m.townRoot, dbName)
-			fmt.Printf("  Setting up DoltHub remote for %s/%s...\n", org, doltserver.DoltHubRepoName(dbName))
-			if err := doltserver.SetupDoltHubRemote(dbDir, org, dbName, token); err != nil {
-				fmt.Printf("  Warning: DoltHub remote setup failed: %v\n", err)
-				fmt.Printf("  You can set up the remote manually later with 'gt dolt sync'.\n")
-			} else {
-				fmt.Printf("   ✓ DoltHub remote configured and initial push complete\n")
-			}
+	if remote, org, token, ok, err := doltserver.AutoRemote(); err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	} else if ok {
+		dbName := "beads_" + opts.Name
+		dbDir := doltserver.RigDatabaseDir(m.townRoot, dbName)
+		fmt.Printf("  Setting up remote for %s/%s on %s...\n", org, doltserver.DoltHubRepoName(dbName), remote.Describe())
+		if err := doltserver.SetupRemote(remote, dbDir, org, dbName, token); err != nil {
+			fmt.Printf("  Warning: remote setup failed: %v\n", err)
+			fmt.Printf("  You can set up the remote manually later with 'gt dolt sync'.\n")
+		} else {
+			fmt.Printf("   ✓ Remote configured and initial push complete\n")
 		}
 	}
 
@@ -3,7 +3,10 @@
 // resolution) stays in cmd but uses types and pure functions from this package.
 package capacity
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // SchedulerConfig configures the capacity scheduler for polecat dispatch.
 // This is a town-wide setting (not per-rig) because capacity control is host-wide:
@@ -28,6 +31,22 @@ type SchedulerConfig struct {
 	// SpawnDelay is the delay between spawns to prevent Dolt lock contention.
 	// Default: "0s".
 	SpawnDelay string `json:"spawn_delay,omitempty"`
+
+	// Policies orders ready beads for dispatch: any of "priority",
+	// "deadline", "convoy-fair" and "aging". Empty = enqueue order (FIFO).
+	Policies []string `json:"policies,omitempty"`
+
+	// AgingInterval is how long a bead waits to gain one priority level
+	// under the "aging" policy. Default: "4h".
+	AgingInterval string `json:"aging_interval,omitempty"`
+
+	// DueSoon is how close a due date must be for the "deadline" policy to
+	// move a bead ahead. Default: "24h".
+	DueSoon string `json:"due_soon,omitempty"`
+
+	// RigQuotas caps concurrent polecats per rig, keyed by rig name.
+	// Rigs without an entry are limited only by MaxPolecats.
+	RigQuotas map[string]int `json:"rig_quotas,omitempty"`
 }
 
 // DefaultSchedulerConfig returns a SchedulerConfig with sensible defaults.
@@ -66,6 +85,38 @@ func (c *SchedulerConfig) GetSpawnDelay() time.Duration {
 	return ParseDurationOrDefault(c.SpawnDelay, 0)
 }
 
+// HasPolicy reports whether a scheduling policy is enabled.
+func (c *SchedulerConfig) HasPolicy(policy string) bool {
+	return c != nil && slices.Contains(c.Policies, policy)
+}
+
+// GetAgingInterval returns AgingInterval as a duration, defaulting to 4h.
+func (c *SchedulerConfig) GetAgingInterval() time.Duration {
+	if c == nil {
+		return DefaultAgingInterval
+	}
+	if d := ParseDurationOrDefault(c.AgingInterval, DefaultAgingInterval); d > 0 {
+		return d
+	}
+	return DefaultAgingInterval
+}
+
+// GetDueSoon returns DueSoon as a duration, defaulting to 24h.
+func (c *SchedulerConfig) GetDueSoon() time.Duration {
+	if c == nil {
+		return DefaultDueSoon
+	}
+	return ParseDurationOrDefault(c.DueSoon, DefaultDueSoon)
+}
+
+// GetRigQuota returns the polecat quota for a rig, or 0 for no quota.
+func (c *SchedulerConfig) GetRigQuota(rig string) int {
+	if c == nil {
+		return 0
+	}
+	return c.RigQuotas[rig]
+}
+
 // IsDeferred returns true when the scheduler is configured for deferred dispatch
 // (max_polecats > 0). Returns false for direct dispatch (-1) and disabled (0).
 func (c *SchedulerConfig) IsDeferred() bool {
@@ -1,6 +1,9 @@
 package capacity
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // PendingBead represents a bead that is scheduled and ready for dispatch evaluation.
 type PendingBead struct {
@@ -11,6 +14,8 @@ type PendingBead struct {
 	Description string
 	Labels      []string
 	Context     *SlingContextFields // Parsed sling params from context bead
+	Priority    int                 // Work bead priority (0 = highest)
+	DueAt       time.Time           // Work bead due date; zero if none
 }
 
 // SlingContextFields holds scheduling parameters stored on a sling context bead.
@@ -0,0 +1,230 @@
+package capacity
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Scheduling policies. SchedulerConfig.Policies enables any combination;
+// with none, ready beads dispatch in enqueue order (FIFO).
+const (
+	// PolicyPriority dispatches higher-priority work beads first (P0 before P4).
+	PolicyPriority = "priority"
+
+	// PolicyDeadline dispatches overdue and soon-due beads before all others,
+	// earliest due date first.
+	PolicyDeadline = "deadline"
+
+	// PolicyConvoyFair round-robins across convoys, so one large convoy
+	// cannot starve other convoys or unconvoyed work.
+	PolicyConvoyFair = "convoy-fair"
+
+	// PolicyAging raises a waiting bead's priority by one level per aging
+	// interval, so low-priority work is not starved. Used with PolicyPriority.
+	PolicyAging = "aging"
+)
+
+// Policies lists the valid scheduling policies.
+var Policies = []string{PolicyPriority, PolicyDeadline, PolicyConvoyFair, PolicyAging}
+
+const (
+	// DefaultAgingInterval is how long a bead waits to gain one priority level.
+	DefaultAgingInterval = 4 * time.Hour
+
+	// DefaultDueSoon is how close a due date must be for PolicyDeadline to
+	// move a bead ahead.
+	DefaultDueSoon = 24 * time.Hour
+)
+
+// Decision explains where one ready bead landed in the dispatch order.
+type Decision struct {
+	WorkBeadID        string   `json:"work_bead_id"`
+	TargetRig         string   `json:"target_rig"`
+	Rank              int      `json:"rank,omitempty"` // 1-based dispatch order; 0 when held
+	Priority          int      `json:"priority"`
+	EffectivePriority int      `json:"effective_priority"`
+	DueAt             string   `json:"due_at,omitempty"`
+	Convoy            string   `json:"convoy,omitempty"`
+	Held              string   `json:"held,omitempty"` // why the bead is held back this cycle
+	Reasons           []string `json:"reasons,omitempty"`
+}
+
+// ranked is a pending bead with its computed ordering keys.
+type ranked struct {
+	bead      PendingBead
+	index     int // enqueue order
+	effective int
+	urgent    bool
+	decision  Decision
+}
+
+// Schedule orders ready beads by the configured policies and holds back
+// beads whose rig is at its quota. activeByRig counts running polecats per
+// rig. It returns the beads to offer PlanDispatch, in order, and a decision
+// per input bead: dispatch order first, then held beads.
+func Schedule(cfg *SchedulerConfig, pending []PendingBead, activeByRig map[string]int, now time.Time) ([]PendingBead, []Decision) {
+	entries := make([]*ranked, len(pending))
+	for i, b := range pending {
+		entries[i] = rankBead(cfg, b, i, now)
+	}
+
+	byPriority := cfg.HasPolicy(PolicyPriority)
+	byDeadline := cfg.HasPolicy(PolicyDeadline)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if byDeadline && a.urgent != b.urgent {
+			return a.urgent
+		}
+		if byDeadline && a.urgent && !a.bead.DueAt.Equal(b.bead.DueAt) {
+			return a.bead.DueAt.Before(b.bead.DueAt)
+		}
+		if byPriority && a.effective != b.effective {
+			return a.effective < b.effective
+		}
+		return a.index < b.index
+	})
+
+	if cfg.HasPolicy(PolicyConvoyFair) {
+		entries = interleaveConvoys(entries)
+	}
+
+	var ordered []PendingBead
+	var decisions, held []Decision
+	admitted := make(map[string]int)
+	for _, e := range entries {
+		rig := e.bead.TargetRig
+		if quota := cfg.GetRigQuota(rig); quota > 0 {
+			if _, seen := admitted[rig]; !seen {
+				admitted[rig] = activeByRig[rig]
+			}
+			if admitted[rig] >= quota {
+				e.decision.Held = fmt.Sprintf("rig quota %d/%d", admitted[rig], quota)
+				held = append(held, e.decision)
+				continue
+			}
+			admitted[rig]++
+		}
+		ordered = append(ordered, e.bead)
+		e.decision.Rank = len(ordered)
+		decisions = append(decisions, e.decision)
+	}
+	return ordered, append(decisions, held...)
+}
+
+// rankBead computes a bead's ordering keys and the reasons behind them.
+func rankBead(cfg *SchedulerConfig, b PendingBead, index int, now time.Time) *ranked {
+	e := &ranked{bead: b, index: index, effective: b.Priority}
+	e.decision = Decision{
+		WorkBeadID: b.WorkBeadID,
+		TargetRig:  b.TargetRig,
+		Priority:   b.Priority,
+		Convoy:     beadConvoy(b),
+	}
+	var reasons []string
+
+	if cfg.HasPolicy(PolicyPriority) {
+		reasons = append(reasons, fmt.Sprintf("P%d", b.Priority))
+	}
+	if cfg.HasPolicy(PolicyAging) {
+		if enqueued := beadEnqueuedAt(b); !enqueued.IsZero() {
+			waited := now.Sub(enqueued)
+			if levels := int(waited / cfg.GetAgingInterval()); levels > 0 && e.effective > 0 {
+				e.effective = max(0, e.effective-levels)
+				reasons = append(reasons, fmt.Sprintf("aged to P%d (waiting %s)", e.effective, waited.Round(time.Minute)))
+			}
+		}
+	}
+	if !b.DueAt.IsZero() {
+		e.decision.DueAt = b.DueAt.UTC().Format(time.RFC3339)
+		if cfg.HasPolicy(PolicyDeadline) && b.DueAt.Before(now.Add(cfg.GetDueSoon())) {
+			e.urgent = true
+			if b.DueAt.Before(now) {
+				reasons = append(reasons, fmt.Sprintf("overdue by %s", now.Sub(b.DueAt).Round(time.Minute)))
+			} else {
+				reasons = append(reasons, fmt.Sprintf("due in %s", b.DueAt.Sub(now).Round(time.Minute)))
+			}
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "enqueue order")
+	}
+
+	e.decision.EffectivePriority = e.effective
+	e.decision.Reasons = reasons
+	return e
+}
+
+// interleaveConvoys round-robins entries across convoys, keeping each
+// convoy's internal order. Beads without a convoy each form their own
+// group, and urgent (deadline) beads keep their place at the front.
+func interleaveConvoys(entries []*ranked) []*ranked {
+	var result []*ranked
+	var groups [][]*ranked
+	groupIndex := make(map[string]int)
+	for _, e := range entries {
+		if e.urgent {
+			result = append(result, e)
+			continue
+		}
+		key := "bead:" + e.bead.WorkBeadID
+		if e.decision.Convoy != "" {
+			key = "convoy:" + e.decision.Convoy
+		}
+		i, ok := groupIndex[key]
+		if !ok {
+			i = len(groups)
+			groupIndex[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+	}
+
+	for round := 0; ; round++ {
+		took := false
+		for _, g := range groups {
+			if round < len(g) {
+				e := g[round]
+				if e.decision.Convoy != "" && len(g) > 1 {
+					e.decision.Reasons = append(e.decision.Reasons, fmt.Sprintf("convoy %s round %d", e.decision.Convoy, round+1))
+				}
+				result = append(result, e)
+				took = true
+			}
+		}
+		if !took {
+			return result
+		}
+	}
+}
+
+// beadConvoy returns the convoy a pending bead was slung with, if any.
+func beadConvoy(b PendingBead) string {
+	if b.Context == nil {
+		return ""
+	}
+	return b.Context.Convoy
+}
+
+// beadEnqueuedAt returns when a bead was scheduled, or zero if unknown.
+func beadEnqueuedAt(b PendingBead) time.Time {
+	if b.Context == nil || b.Context.EnqueuedAt == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, b.Context.EnqueuedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ValidatePolicies checks that every policy name is known.
+func ValidatePolicies(policies []string) error {
+	for _, p := range policies {
+		if !slices.Contains(Policies, p) {
+			return fmt.Errorf("unknown scheduling policy %q (valid: priority, deadline, convoy-fair, aging)", p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	enqueued := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+	bead := func(id, rig string, priority int, convoy string, ago time.Duration) PendingBead {
+		return PendingBead{
+			ID: "ctx-" + id, WorkBeadID: id, TargetRig: rig, Priority: priority,
+			Context: &SlingContextFields{WorkBeadID: id, Convoy: convoy, EnqueuedAt: enqueued(ago)},
+		}
+	}
+
+	// A big convoy slung first, then a hotfix and a low-priority chore.
+	pending := []PendingBead{
+		bead("cv-1", "gastown", 2, "hq-cv", 3*time.Hour),
+		bead("cv-2", "gastown", 2, "hq-cv", 3*time.Hour),
+		bead("cv-3", "gastown", 2, "hq-cv", 3*time.Hour),
+		bead("hotfix", "gastown", 0, "", time.Hour),
+		bead("chore", "beads", 4, "", 20*time.Hour),
+	}
+	due := bead("due", "beads", 3, "", time.Hour)
+	due.DueAt = now.Add(2 * time.Hour)
+	pending = append(pending, due)
+
+	tests := []struct {
+		name     string
+		policies []string
+		want     string
+	}{
+		{"fifo", nil, "cv-1,cv-2,cv-3,hotfix,chore,due"},
+		{"priority", []string{PolicyPriority}, "hotfix,cv-1,cv-2,cv-3,due,chore"},
+		{"priority with aging", []string{PolicyPriority, PolicyAging}, "hotfix,chore,cv-1,cv-2,cv-3,due"},
+		{"deadline", []string{PolicyDeadline, PolicyPriority}, "due,hotfix,cv-1,cv-2,cv-3,chore"},
+		{"convoy fair", []string{PolicyConvoyFair}, "cv-1,hotfix,chore,due,cv-2,cv-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &SchedulerConfig{Policies: tt.policies}
+			ordered, decisions := Schedule(cfg, pending, nil, now)
+			var ids []string
+			for _, b := range ordered {
+				ids = append(ids, b.WorkBeadID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+			if len(decisions) != len(pending) {
+				t.Fatalf("got %d decisions, want %d", len(decisions), len(pending))
+			}
+			for i, d := range decisions {
+				if d.Rank != i+1 || d.WorkBeadID != ids[i] || len(d.Reasons) == 0 {
+					t.Errorf("decision %d = %+v", i, d)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_Reasons(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &SchedulerConfig{Policies: []string{PolicyPriority, PolicyAging, PolicyDeadline}, AgingInterval: "1h"}
+	b := PendingBead{
+		WorkBeadID: "gt-1", TargetRig: "gastown", Priority: 3, DueAt: now.Add(-30 * time.Minute),
+		Context: &SlingContextFields{EnqueuedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+	}
+	_, decisions := Schedule(cfg, []PendingBead{b}, nil, now)
+	d := decisions[0]
+	if d.EffectivePriority != 1 {
+		t.Errorf("EffectivePriority = %d, want 1 (aged two levels)", d.EffectivePriority)
+	}
+	got := strings.Join(d.Reasons, " | ")
+	for _, want := range []string{"P3", "aged to P1 (waiting 2h0m0s)", "overdue by 30m0s"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("reasons %q missing %q", got, want)
+		}
+	}
+}
+
+func TestSchedule_RigQuotas(t *testing.T) {
+	cfg := &SchedulerConfig{RigQuotas: map[string]int{"gastown": 2}}
+	pending := []PendingBead{
+		{WorkBeadID: "a", TargetRig: "gastown"},
+		{WorkBeadID: "b", TargetRig: "beads"},
+		{WorkBeadID: "c", TargetRig: "gastown"},
+	}
+
+	ordered, decisions := Schedule(cfg, pending, map[string]int{"gastown": 1}, time.Now())
+	if len(ordered) != 2 || ordered[0].WorkBeadID != "a" || ordered[1].WorkBeadID != "b" {
+		t.Fatalf("ordered = %+v, want a,b", ordered)
+	}
+	last := decisions[len(decisions)-1]
+	if last.WorkBeadID != "c" || last.Rank != 0 || last.Held != "rig quota 2/2" {
+		t.Errorf("held decision = %+v", last)
+	}
+
+	// Plenty of room: nothing held
+	if ordered, _ := Schedule(cfg, pending, nil, time.Now()); len(ordered) != 3 {
+		t.Errorf("with no active polecats got %d beads, want 3", len(ordered))
+	}
+}
+
+func TestValidatePolicies(t *testing.T) {
+	if err := ValidatePolicies([]string{PolicyPriority, PolicyConvoyFair}); err != nil {
+		t.Errorf("ValidatePolicies(valid) = %v", err)
+	}
+	if err := ValidatePolicies([]string{"lifo"}); err == nil {
+		t.Error("ValidatePolicies accepted unknown policy")
+	}
+}
@@ -0,0 +1,212 @@
+// Package telemetry — prometheus.go
+// Pull-based Prometheus text exposition of the process's OTel metrics, for
+// monitoring stacks that scrape instead of running an OTLP collector.
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// PrometheusContentType is the Prometheus text exposition format, version 0.0.4.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler serves the provider's metrics in Prometheus text format.
+// Returns nil if the provider was not initialized WithPrometheus.
+func (p *Provider) MetricsHandler() http.Handler {
+	if p == nil || p.promReader == nil {
+		return nil
+	}
+	return prometheusHandler(p.promReader)
+}
+
+// prometheusHandler collects from reader on every scrape.
+func prometheusHandler(reader *sdkmetric.ManualReader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(r.Context(), &rm); err != nil {
+			http.Error(w, fmt.Sprintf("collecting metrics: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", PrometheusContentType)
+		_ = WritePrometheus(w, &rm)
+	})
+}
+
+// promFamily is one metric name with its samples, possibly merged from
+// several instrumentation scopes.
+type promFamily struct {
+	name    string
+	help    string
+	typ     string // counter, gauge, histogram
+	samples []promSample
+}
+
+type promSample struct {
+	suffix string // "", "_bucket", "_sum", "_count"
+	labels []string
+	value  float64
+}
+
+// WritePrometheus renders collected metrics in the Prometheus text format.
+// OTel names are sanitized ("gastown.bd.calls.total" → gastown_bd_calls_total),
+// monotonic sums become counters with a _total suffix, and each series is
+// labeled with otel_scope_name so instruments of the same name registered by
+// different packages stay distinct. Families are sorted by name.
+func WritePrometheus(w io.Writer, rm *metricdata.ResourceMetrics) error {
+	families := make(map[string]*promFamily)
+	family := func(name, help, typ string) *promFamily {
+		f, ok := families[name]
+		if !ok {
+			f = &promFamily{name: name, help: help, typ: typ}
+			families[name] = f
+		}
+		return f
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		scope := promLabel("otel_scope_name", sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			name := promName(m.Name)
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				addSum(family, name, m.Description, scope, data.IsMonotonic, data.DataPoints)
+			case metricdata.Sum[float64]:
+				addSum(family, name, m.Description, scope, data.IsMonotonic, data.DataPoints)
+			case metricdata.Gauge[int64]:
+				addGauge(family(name, m.Description, "gauge"), scope, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				addGauge(family(name, m.Description, "gauge"), scope, data.DataPoints)
+			case metricdata.Histogram[int64]:
+				addHistogram(family(name, m.Description, "histogram"), scope, data.DataPoints)
+			case metricdata.Histogram[float64]:
+				addHistogram(family(name, m.Description, "histogram"), scope, data.DataPoints)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := families[name]
+		if f.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range f.samples {
+			bw.WriteString(f.name + s.suffix)
+			if len(s.labels) > 0 {
+				bw.WriteString("{" + strings.Join(s.labels, ",") + "}")
+			}
+			bw.WriteString(" " + formatPromValue(s.value) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+func addSum[N int64 | float64](family func(name, help, typ string) *promFamily, name, help, scope string, monotonic bool, points []metricdata.DataPoint[N]) {
+	typ := "gauge" // non-monotonic sums (up/down counters) are gauges
+	if monotonic {
+		typ = "counter"
+		if !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+	}
+	addGauge(family(name, help, typ), scope, points)
+}
+
+func addGauge[N int64 | float64](f *promFamily, scope string, points []metricdata.DataPoint[N]) {
+	for _, dp := range points {
+		f.samples = append(f.samples, promSample{labels: promLabels(dp.Attributes, scope), value: float64(dp.Value)})
+	}
+}
+
+func addHistogram[N int64 | float64](f *promFamily, scope string, points []metricdata.HistogramDataPoint[N]) {
+	for _, dp := range points {
+		labels := promLabels(dp.Attributes, scope)
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			if i < len(dp.BucketCounts) {
+				cumulative += dp.BucketCounts[i]
+			}
+			le := append(append([]string{}, labels...), promLabel("le", formatPromValue(bound)))
+			f.samples = append(f.samples, promSample{suffix: "_bucket", labels: le, value: float64(cumulative)})
+		}
+		inf := append(append([]string{}, labels...), promLabel("le", "+Inf"))
+		f.samples = append(f.samples,
+			promSample{suffix: "_bucket", labels: inf, value: float64(dp.Count)},
+			promSample{suffix: "_sum", labels: labels, value: float64(dp.Sum)},
+			promSample{suffix: "_count", labels: labels, value: float64(dp.Count)},
+		)
+	}
+}
+
+// promLabels renders data point attributes as sorted name="value" pairs,
+// followed by the scope label.
+func promLabels(set attribute.Set, scope string) []string {
+	labels := make([]string, 0, set.Len()+1)
+	for _, kv := range set.ToSlice() { // ToSlice is sorted by key
+		labels = append(labels, promLabel(promName(string(kv.Key)), kv.Value.Emit()))
+	}
+	return append(labels, scope)
+}
+
+func promLabel(name, value string) string {
+	return name + `="` + escapeLabelValue(value) + `"`
+}
+
+// promName maps an OTel instrument or attribute name to a valid Prometheus
+// name: characters outside [a-zA-Z0-9_:] become '_', and a leading digit is
+// prefixed with '_'.
+func promName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatPromValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
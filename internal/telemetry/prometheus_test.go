@@ -0,0 +1,116 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	m := mp.Meter("github.com/steveyegge/gastown/daemon")
+	ctx := context.Background()
+
+	spawns, _ := m.Int64Counter("gastown.polecat.spawns.total", metric.WithDescription("Total polecat spawns"))
+	spawns.Add(ctx, 2, metric.WithAttributes(attribute.String("rig", "gastown")))
+	spawns.Add(ctx, 1, metric.WithAttributes(attribute.String("rig", `we"ird`)))
+
+	heartbeats, _ := m.Int64Counter("gastown.daemon.heartbeat")
+	heartbeats.Add(ctx, 5)
+
+	depth, _ := m.Int64ObservableGauge("gastown.merge_queue.depth", metric.WithDescription("Queued merge requests"))
+	_, _ = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(depth, 3, metric.WithAttributes(attribute.String("rig", "gastown")))
+		return nil
+	}, depth)
+
+	latency, _ := m.Float64Histogram("gastown.bd.duration_ms", metric.WithExplicitBucketBoundaries(10, 100))
+	latency.Record(ctx, 5, metric.WithAttributes(attribute.String("sub.command", "show")))
+	latency.Record(ctx, 50, metric.WithAttributes(attribute.String("sub.command", "show")))
+	latency.Record(ctx, 500, metric.WithAttributes(attribute.String("sub.command", "show")))
+
+	handler := prometheusHandler(reader)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != PrometheusContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	got := string(body)
+
+	scope := `otel_scope_name="github.com/steveyegge/gastown/daemon"`
+	for _, want := range []string{
+		"# HELP gastown_polecat_spawns_total Total polecat spawns\n",
+		"# TYPE gastown_polecat_spawns_total counter\n",
+		`gastown_polecat_spawns_total{rig="gastown",` + scope + "} 2\n",
+		`gastown_polecat_spawns_total{rig="we\"ird",` + scope + "} 1\n",
+		"# TYPE gastown_daemon_heartbeat_total counter\n",
+		"gastown_daemon_heartbeat_total{" + scope + "} 5\n",
+		"# TYPE gastown_merge_queue_depth gauge\n",
+		`gastown_merge_queue_depth{rig="gastown",` + scope + "} 3\n",
+		"# TYPE gastown_bd_duration_ms histogram\n",
+		`gastown_bd_duration_ms_bucket{sub_command="show",` + scope + `,le="10"} 1` + "\n",
+		`gastown_bd_duration_ms_bucket{sub_command="show",` + scope + `,le="100"} 2` + "\n",
+		`gastown_bd_duration_ms_bucket{sub_command="show",` + scope + `,le="+Inf"} 3` + "\n",
+		`gastown_bd_duration_ms_sum{sub_command="show",` + scope + "} 555\n",
+		`gastown_bd_duration_ms_count{sub_command="show",` + scope + "} 3\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("exposition missing %q\n---\n%s", want, got)
+		}
+	}
+
+	// Families are sorted by name
+	if strings.Index(got, "gastown_bd_duration_ms") > strings.Index(got, "gastown_polecat_spawns_total") {
+		t.Errorf("families not sorted:\n%s", got)
+	}
+}
+
+func TestPromName(t *testing.T) {
+	tests := map[string]string{
+		"gastown.bd.calls.total": "gastown_bd_calls_total",
+		"agent.type":             "agent_type",
+		"9lives":                 "_9lives",
+		"ok_name:sub":            "ok_name:sub",
+	}
+	for in, want := range tests {
+		if got := promName(in); got != want {
+			t.Errorf("promName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInit_PrometheusOnly(t *testing.T) {
+	resetInitState(t)
+	t.Setenv(EnvMetricsURL, "")
+	t.Setenv(EnvLogsURL, "")
+
+	p, err := Init(context.Background(), "test-svc", "0.0.1", WithPrometheus())
+	if err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+	if p == nil || p.MetricsHandler() == nil {
+		t.Fatal("expected a provider with a metrics handler")
+	}
+	if p.OTLPEnabled() {
+		t.Error("OTLPEnabled() = true with no OTLP URLs set")
+	}
+}
+
+func TestMetricsHandler_NilWithoutPrometheus(t *testing.T) {
+	var p *Provider
+	if p.MetricsHandler() != nil {
+		t.Error("nil provider returned a handler")
+	}
+	if (&Provider{}).MetricsHandler() != nil {
+		t.Error("provider without WithPrometheus returned a handler")
+	}
+}
@@ -8,6 +8,9 @@
 //	GT_OTEL_METRICS_URL  (default: http://localhost:8428/opentelemetry/api/v1/push)
 //	GT_OTEL_LOGS_URL     (default: http://localhost:9428/insert/opentelemetry/v1/logs)
 //
+// or by passing WithPrometheus, which serves metrics for scraping through
+// Provider.MetricsHandler instead of (or as well as) pushing them.
+//
 // Telemetry is best-effort: initialization errors are returned but do not
 // affect normal gt operation — callers should log and continue.
 //
@@ -60,6 +63,31 @@ type Provider struct {
 	shutdowns    []func(context.Context) error
 	shutdownMu   sync.Mutex
 	shutdownDone bool
+
+	// otlp reports whether metrics and logs are pushed over OTLP.
+	otlp bool
+
+	// promReader is collected on each scrape when WithPrometheus is set.
+	promReader *sdkmetric.ManualReader
+}
+
+// OTLPEnabled reports whether metrics and logs are pushed over OTLP.
+func (p *Provider) OTLPEnabled() bool {
+	return p != nil && p.otlp
+}
+
+// Option configures Init.
+type Option func(*initOptions)
+
+type initOptions struct {
+	prometheus bool
+}
+
+// WithPrometheus enables pull-based metrics: Provider.MetricsHandler serves
+// every instrument in the Prometheus text format. It works with or without
+// an OTLP endpoint configured. Logs still require OTLP.
+func WithPrometheus() Option {
+	return func(o *initOptions) { o.prometheus = true }
 }
 
 // Shutdown flushes all pending data and stops the OTel providers.
@@ -94,25 +122,31 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 // issue. If multiple packages call Init, ensure the entry-point (main or
 // cobra root) calls it first with the correct service name.
 //
-// Returns (nil, nil) if neither GT_OTEL_METRICS_URL nor GT_OTEL_LOGS_URL is set,
-// so that telemetry is strictly opt-in. Set either variable to activate.
+// Returns (nil, nil) if neither GT_OTEL_METRICS_URL nor GT_OTEL_LOGS_URL is set
+// and WithPrometheus is not given, so that telemetry is strictly opt-in.
 //
 // When active, defaults are used for any unset endpoint:
 //
 //	metrics → http://localhost:8428/opentelemetry/api/v1/push
 //	logs    → http://localhost:9428/insert/opentelemetry/v1/logs
-func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, error) {
+func Init(ctx context.Context, serviceName, serviceVersion string, opts ...Option) (*Provider, error) {
 	initMu.Lock()
 	defer initMu.Unlock()
 	if initDone {
 		return globalProvider, nil
 	}
 
+	var o initOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	metricsURL := os.Getenv(EnvMetricsURL)
 	logsURL := os.Getenv(EnvLogsURL)
+	otlp := metricsURL != "" || logsURL != ""
 
-	// Both unset → telemetry disabled, not an error.
-	if metricsURL == "" && logsURL == "" {
+	// Both unset and nothing to scrape → telemetry disabled, not an error.
+	if !otlp && !o.prometheus {
 		initDone = true
 		globalProvider = nil
 		return nil, nil
@@ -136,27 +170,41 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 		return nil, fmt.Errorf("creating OTel resource: %w", err)
 	}
 
-	p := &Provider{}
+	p := &Provider{otlp: otlp}
+	mpOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
 
 	// Metrics → VictoriaMetrics
-	metricExp, err := otlpmetrichttp.New(ctx,
-		otlpmetrichttp.WithEndpointURL(metricsURL),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("creating OTLP metric exporter: %w", err)
-	}
-	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithResource(res),
-		sdkmetric.WithReader(
+	if otlp {
+		metricExp, err := otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpointURL(metricsURL),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating OTLP metric exporter: %w", err)
+		}
+		mpOpts = append(mpOpts, sdkmetric.WithReader(
 			sdkmetric.NewPeriodicReader(metricExp,
 				sdkmetric.WithInterval(ExportInterval),
 			),
-		),
-	)
+		))
+	}
+
+	// Metrics → Prometheus scrape
+	if o.prometheus {
+		p.promReader = sdkmetric.NewManualReader()
+		mpOpts = append(mpOpts, sdkmetric.WithReader(p.promReader))
+	}
+
+	mp := sdkmetric.NewMeterProvider(mpOpts...)
 	otel.SetMeterProvider(mp)
 	p.shutdowns = append(p.shutdowns, mp.Shutdown)
 	initInstruments()
 
+	if !otlp {
+		initDone = true
+		globalProvider = p
+		return p, nil
+	}
+
 	// Logs → VictoriaLogs
 	logExp, err := otlploghttp.New(ctx,
 		otlploghttp.WithEndpointURL(logsURL),
@@ -5,6 +5,7 @@ package util
 import (
 	"os/exec"
 	"syscall"
+	"time"
 )
 
 // SetProcessGroup configures a command to run in its own process group so that
@@ -18,3 +19,19 @@ func SetProcessGroup(cmd *exec.Cmd) {
 		return nil
 	}
 }
+
+// SetProcessGroupGraceful is like SetProcessGroup, but context cancellation
+// first sends SIGTERM to the process group so scripts can run their cleanup
+// traps, and only sends SIGKILL if the group outlives grace.
+func SetProcessGroupGraceful(cmd *exec.Cmd, grace time.Duration) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		pgid := cmd.Process.Pid
+		time.AfterFunc(grace, func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+		return syscall.Kill(-pgid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = grace
+}
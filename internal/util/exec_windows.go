@@ -2,8 +2,17 @@
 
 package util
 
-import "os/exec"
+import (
+	"os/exec"
+	"time"
+)
 
 // SetProcessGroup is a no-op on Windows.
 // Process group management is not supported on Windows.
 func SetProcessGroup(cmd *exec.Cmd) {}
+
+// SetProcessGroupGraceful is a no-op on Windows, apart from bounding how
+// long Wait waits for output after cancellation.
+func SetProcessGroupGraceful(cmd *exec.Cmd, grace time.Duration) {
+	cmd.WaitDelay = grace
+}
@@ -0,0 +1,141 @@
+package wasteland
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// RemoteBackend returns the remote backend the town joined its wasteland
+// through. Configs without one predate self-hosted remotes and mean DoltHub.
+func (c *Config) RemoteBackend() (doltserver.Remote, error) {
+	return doltserver.ParseRemote(c.Remote)
+}
+
+// NewServiceForRemote creates a Service with production dependencies that
+// forks, clones and pushes through remote instead of DoltHub.
+func NewServiceForRemote(remote doltserver.Remote) *Service {
+	svc := NewService()
+	svc.Remote = remote
+	if !remote.IsDoltHub() {
+		svc.API = &remoteForkAPI{remote: remote}
+	}
+	svc.CLI = &execDoltCLI{remote: remote}
+	return svc
+}
+
+// remoteForkAPI implements DoltHubAPI for self-hosted remotes, which have no
+// fork endpoint: file remotes are forked by copying the remote directory,
+// other remotes by cloning upstream and pushing it to the fork's URL. The
+// token is unused; the dolt CLI picks up server credentials itself.
+type remoteForkAPI struct {
+	remote doltserver.Remote
+}
+
+func (r *remoteForkAPI) ForkRepo(fromOrg, fromDB, toOrg, token string) error {
+	if r.remote.Kind == doltserver.RemoteFile {
+		return forkFileRemote(r.remote.Path(fromOrg, fromDB), r.remote.Path(toOrg, fromDB))
+	}
+	return forkByPush(r.remote.URL(fromOrg, fromDB), r.remote.URL(toOrg, fromDB))
+}
+
+// forkFileRemote copies a file remote directory. A fork that already exists
+// is left alone, matching DoltHub's "already exists" behavior.
+func forkFileRemote(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("upstream file remote %s: %w", src, err)
+	}
+	if entries, err := os.ReadDir(dst); err == nil && len(entries) > 0 {
+		return nil
+	}
+
+	// Copy to a sibling temp dir and rename, so an interrupted fork
+	// doesn't leave a half-copied remote that looks forked.
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("creating fork directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dst), ".fork-*")
+	if err != nil {
+		return fmt.Errorf("creating fork directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := copyTree(src, tmp); err != nil {
+		return fmt.Errorf("copying file remote: %w", err)
+	}
+	_ = os.Remove(dst) // empty dir from an earlier Create, if any
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("creating fork: %w", err)
+	}
+	return nil
+}
+
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// forkByPush forks by cloning fromURL into a temporary directory and pushing
+// it to toURL. A push rejected because the fork already has history is
+// treated as an existing fork.
+func forkByPush(fromURL, toURL string) error {
+	tmpDir, err := os.MkdirTemp("", "wl-fork-*")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cloneDir := filepath.Join(tmpDir, "db")
+	if err := CloneURL(fromURL, cloneDir); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"remote", "add", "fork", toURL},
+		{"push", "fork", "main"},
+	} {
+		cmd := exec.Command("dolt", args...)
+		cmd.Dir = cloneDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(output))
+			lower := strings.ToLower(msg)
+			if args[0] == "push" && (strings.Contains(lower, "non-fast-forward") || strings.Contains(lower, "up to date")) {
+				return nil // fork already exists
+			}
+			return fmt.Errorf("dolt %s: %w (%s)", args[0], err, msg)
+		}
+	}
+	return nil
+}
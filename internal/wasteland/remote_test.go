@@ -0,0 +1,96 @@
+package wasteland
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestForkFileRemote(t *testing.T) {
+	base := t.TempDir()
+	remote := doltserver.Remote{Kind: doltserver.RemoteFile, Base: base}
+	src := remote.Path("corp", "wl-commons")
+	if err := os.MkdirAll(filepath.Join(src, "chunks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "chunks", "manifest"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &remoteForkAPI{remote: remote}
+	if err := api.ForkRepo("corp", "wl-commons", "team-a", ""); err != nil {
+		t.Fatalf("ForkRepo() error: %v", err)
+	}
+	forked := filepath.Join(remote.Path("team-a", "wl-commons"), "chunks", "manifest")
+	if data, err := os.ReadFile(forked); err != nil || string(data) != "v1" {
+		t.Fatalf("fork content = %q, %v", data, err)
+	}
+
+	// Forking again leaves the existing fork alone.
+	if err := os.WriteFile(forked, []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ForkRepo("corp", "wl-commons", "team-a", ""); err != nil {
+		t.Fatalf("second ForkRepo() error: %v", err)
+	}
+	if data, _ := os.ReadFile(forked); string(data) != "v2" {
+		t.Error("existing fork was overwritten")
+	}
+
+	if err := api.ForkRepo("corp", "missing", "team-a", ""); err == nil {
+		t.Error("expected error forking a missing upstream")
+	}
+}
+
+func TestJoin_FileRemote(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	remote := doltserver.Remote{Kind: doltserver.RemoteFile, Base: base}
+	if err := os.MkdirAll(remote.Path("corp", "wl-commons"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(remote.Path("corp", "wl-commons"), "manifest"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := NewFakeDoltCLI()
+	cfgStore := NewFakeConfigStore()
+	svc := &Service{API: &remoteForkAPI{remote: remote}, CLI: cli, Config: cfgStore, Remote: remote}
+
+	cfg, err := svc.Join("corp/wl-commons", "team-a", "", "team-a", "Team A", "", "dev", "/tmp/town")
+	if err != nil {
+		t.Fatalf("Join() error: %v", err)
+	}
+	if cfg.Remote != "file://"+filepath.ToSlash(base) {
+		t.Errorf("Remote = %q, want file remote recorded", cfg.Remote)
+	}
+	if _, err := os.Stat(remote.Path("team-a", "wl-commons")); err != nil {
+		t.Errorf("fork not created: %v", err)
+	}
+
+	backend, err := cfg.RemoteBackend()
+	if err != nil || backend != remote {
+		t.Errorf("RemoteBackend() = %+v, %v; want %+v", backend, err, remote)
+	}
+}
+
+func TestConfigRemoteBackend_DefaultsToDoltHub(t *testing.T) {
+	t.Parallel()
+	backend, err := (&Config{Upstream: "hop/wl-commons"}).RemoteBackend()
+	if err != nil || !backend.IsDoltHub() {
+		t.Errorf("RemoteBackend() = %+v, %v; want DoltHub", backend, err)
+	}
+}
+
+func TestExecDoltCLI_URLs(t *testing.T) {
+	t.Parallel()
+	svc := NewServiceForRemote(doltserver.Remote{Kind: doltserver.RemoteServer, Base: "http://doltsrv:50051"})
+	if _, ok := svc.API.(*remoteForkAPI); !ok {
+		t.Errorf("API = %T, want remoteForkAPI for self-hosted remotes", svc.API)
+	}
+	if _, ok := NewServiceForRemote(doltserver.DoltHubRemote).API.(*httpDoltHubAPI); !ok {
+		t.Error("DoltHub service should use the DoltHub API")
+	}
+}
@@ -0,0 +1,190 @@
+package wasteland
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// ValidatorTrustLevel is the trust level at which a rig may validate
+// completions of wanted items it didn't post.
+const ValidatorTrustLevel = 2
+
+// Badge types awarded by ComputeReputation. Skill badges are
+// BadgeSkillPrefix followed by the skill tag.
+const (
+	BadgeFirstValidation = "first-validation"
+	BadgeValidated10     = "validated-10"
+	BadgeValidated50     = "validated-50"
+	BadgeSkillPrefix     = "skill:"
+)
+
+// skillBadgeThreshold is how many positive stamps carrying a skill tag earn
+// the rig that skill's badge.
+const skillBadgeThreshold = 3
+
+// severityWeight scales a stamp's contribution to the subject's score.
+var severityWeight = map[string]float64{
+	SeverityLeaf:   1,
+	SeverityBranch: 2,
+	SeverityRoot:   4,
+}
+
+// trustRule is the score and number of distinct vouching rigs needed for a
+// trust level; any trust also needs a positive score. Requiring several
+// authors keeps one friendly rig from minting validators on its own.
+type trustRule struct {
+	level   int
+	score   float64
+	authors int
+}
+
+var trustRules = []trustRule{
+	{level: 3, score: 20, authors: 3},
+	{level: 2, score: 5, authors: 2},
+	{level: 1, score: 0, authors: 1},
+}
+
+// Reputation is a rig's standing derived from the verified stamps it has
+// received.
+type Reputation struct {
+	Handle     string
+	Score      float64
+	Positive   int // stamps with positive mean valence
+	Negative   int // stamps with negative mean valence
+	Authors    int // distinct authors of positive stamps
+	TrustLevel int
+	Badges     []*doltserver.Badge
+}
+
+// ComputeReputation derives handle's reputation from stamps. Only stamps
+// the chain report marks valid count; report may be nil to trust them all.
+//
+// Each stamp adds mean(valence) × confidence × severity weight to the
+// score. Trust level and badges follow from the score and stamp counts.
+func ComputeReputation(handle string, stamps []*doltserver.Stamp, report *ChainReport) *Reputation {
+	rep := &Reputation{Handle: handle}
+	authors := make(map[string]bool)
+	skills := make(map[string]int)
+
+	for _, s := range stamps {
+		if s.Subject != handle || s.Author == handle {
+			continue
+		}
+		if report != nil && !report.Valid(s.ID) {
+			continue
+		}
+		v := meanValence(s.Valence)
+		weight := severityWeight[s.Severity]
+		if weight == 0 {
+			weight = 1
+		}
+		rep.Score += v * clamp(s.Confidence, 0, 1) * weight
+		switch {
+		case v > 0:
+			rep.Positive++
+			authors[s.Author] = true
+			for _, tag := range s.SkillTags {
+				skills[tag]++
+			}
+		case v < 0:
+			rep.Negative++
+		}
+	}
+	rep.Authors = len(authors)
+
+	for _, r := range trustRules {
+		if rep.Score > 0 && rep.Score >= r.score && rep.Authors >= r.authors {
+			rep.TrustLevel = r.level
+			break
+		}
+	}
+
+	award := func(badgeType, evidence string) {
+		rep.Badges = append(rep.Badges, &doltserver.Badge{
+			ID:        BadgeID(handle, badgeType),
+			RigHandle: handle,
+			BadgeType: badgeType,
+			Evidence:  evidence,
+		})
+	}
+	for _, b := range []struct {
+		typ string
+		n   int
+	}{{BadgeFirstValidation, 1}, {BadgeValidated10, 10}, {BadgeValidated50, 50}} {
+		if rep.Positive >= b.n {
+			award(b.typ, fmt.Sprintf("%d positive stamps", rep.Positive))
+		}
+	}
+	tags := make([]string, 0, len(skills))
+	for tag, n := range skills {
+		if n >= skillBadgeThreshold {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		award(BadgeSkillPrefix+tag, fmt.Sprintf("%d positive stamps tagged %s", skills[tag], tag))
+	}
+	return rep
+}
+
+// BadgeID returns the deterministic ID of a rig's badge, so re-awarding a
+// badge the rig already holds is a no-op.
+func BadgeID(handle, badgeType string) string {
+	h := sha256.Sum256([]byte(handle + "|" + badgeType))
+	return fmt.Sprintf("b-%x", h[:8])
+}
+
+// BadgeTypes returns the types of badges, for display.
+func BadgeTypes(badges []*doltserver.Badge) string {
+	types := make([]string, len(badges))
+	for i, b := range badges {
+		types[i] = b.BadgeType
+	}
+	return strings.Join(types, ", ")
+}
+
+// NewCompletionStamp builds the unsealed stamp a validator issues for a
+// completion. Valence dimensions are quality and reliability, each in
+// [-1, 1]; a rejection should pass negative values.
+func NewCompletionStamp(validator string, c *doltserver.Completion, valence map[string]float64, confidence float64, severity string, skills []string, message string, now time.Time) *doltserver.Stamp {
+	return &doltserver.Stamp{
+		ID:          GenerateStampID(validator, c.ID, now),
+		Author:      validator,
+		Subject:     c.CompletedBy,
+		Valence:     valence,
+		Confidence:  confidence,
+		Severity:    severity,
+		ContextID:   c.ID,
+		ContextType: ContextCompletion,
+		SkillTags:   skills,
+		Message:     message,
+		CreatedAt:   now,
+	}
+}
+
+func meanValence(valence map[string]float64) float64 {
+	if len(valence) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range valence {
+		sum += clamp(v, -1, 1)
+	}
+	return sum / float64(len(valence))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	}
+	return v
+}
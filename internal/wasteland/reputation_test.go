@@ -0,0 +1,138 @@
+package wasteland
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func stampFor(author, subject string, v float64, severity string, tags ...string) *doltserver.Stamp {
+	return &doltserver.Stamp{
+		ID:         GenerateStampID(author, subject, time.Now()),
+		Author:     author,
+		Subject:    subject,
+		Valence:    map[string]float64{"quality": v, "reliability": v},
+		Confidence: 1,
+		Severity:   severity,
+		SkillTags:  tags,
+	}
+}
+
+func TestComputeReputation_TrustLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		stamps    []*doltserver.Stamp
+		wantTrust int
+		wantScore float64
+	}{
+		{name: "no stamps", wantTrust: 0},
+		{
+			name:      "one positive",
+			stamps:    []*doltserver.Stamp{stampFor("a", "w", 1, SeverityLeaf)},
+			wantTrust: 1,
+			wantScore: 1,
+		},
+		{
+			name: "high score from one rig",
+			stamps: []*doltserver.Stamp{
+				stampFor("a", "w", 1, SeverityRoot),
+				stampFor("a", "w", 1, SeverityRoot),
+			},
+			wantTrust: 1,
+			wantScore: 8,
+		},
+		{
+			name: "two rigs",
+			stamps: []*doltserver.Stamp{
+				stampFor("a", "w", 1, SeverityRoot),
+				stampFor("b", "w", 1, SeverityLeaf),
+			},
+			wantTrust: 2,
+			wantScore: 5,
+		},
+		{
+			name: "rejections cancel",
+			stamps: []*doltserver.Stamp{
+				stampFor("a", "w", 1, SeverityLeaf),
+				stampFor("b", "w", -1, SeverityBranch),
+			},
+			wantTrust: 0,
+			wantScore: -1,
+		},
+		{
+			name: "self stamps and other subjects ignored",
+			stamps: []*doltserver.Stamp{
+				stampFor("w", "w", 1, SeverityRoot),
+				stampFor("a", "x", 1, SeverityRoot),
+			},
+			wantTrust: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := ComputeReputation("w", tt.stamps, nil)
+			if rep.TrustLevel != tt.wantTrust {
+				t.Errorf("TrustLevel = %d, want %d", rep.TrustLevel, tt.wantTrust)
+			}
+			if rep.Score != tt.wantScore {
+				t.Errorf("Score = %g, want %g", rep.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestComputeReputation_Level3(t *testing.T) {
+	var stamps []*doltserver.Stamp
+	for i := 0; i < 5; i++ {
+		stamps = append(stamps, stampFor(fmt.Sprintf("rig-%d", i%3), "w", 1, SeverityRoot))
+	}
+	if rep := ComputeReputation("w", stamps, nil); rep.TrustLevel != 3 {
+		t.Errorf("TrustLevel = %d (score %g, authors %d), want 3", rep.TrustLevel, rep.Score, rep.Authors)
+	}
+}
+
+func TestComputeReputation_Badges(t *testing.T) {
+	var stamps []*doltserver.Stamp
+	for i := 0; i < 10; i++ {
+		tags := []string{"docs"}
+		if i < 3 {
+			tags = append(tags, "go")
+		}
+		stamps = append(stamps, stampFor("a", "w", 1, SeverityLeaf, tags...))
+	}
+	stamps = append(stamps, stampFor("b", "w", -1, SeverityLeaf, "rust", "rust", "rust"))
+
+	rep := ComputeReputation("w", stamps, nil)
+	want := "first-validation, validated-10, skill:docs, skill:go"
+	if got := BadgeTypes(rep.Badges); got != want {
+		t.Errorf("badges = %q, want %q", got, want)
+	}
+	for _, b := range rep.Badges {
+		if b.ID != BadgeID("w", b.BadgeType) || b.RigHandle != "w" {
+			t.Errorf("badge %+v", b)
+		}
+	}
+}
+
+func TestComputeReputation_OnlyVerifiedStamps(t *testing.T) {
+	key := testKey(1)
+	chain := buildChain("alice", key, 3)
+	chain[1].Confidence = 0.5 // tampered: 1 and 2 no longer count
+
+	report := VerifyChains(chain, map[string]string{"alice": PublicKeyHex(key)})
+	rep := ComputeReputation("worker", chain, report)
+	if rep.Positive != 1 {
+		t.Errorf("Positive = %d, want 1 (only the untampered first stamp)", rep.Positive)
+	}
+}
+
+func TestBadgeID_Deterministic(t *testing.T) {
+	if BadgeID("w", "validated-10") != BadgeID("w", "validated-10") {
+		t.Error("BadgeID not deterministic")
+	}
+	if BadgeID("w", "validated-10") == BadgeID("x", "validated-10") {
+		t.Error("BadgeID collides across rigs")
+	}
+}
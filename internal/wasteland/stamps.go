@@ -0,0 +1,310 @@
+package wasteland
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// Stamp severities, in increasing weight. A leaf stamp covers one piece of
+// work; branch and root stamps vouch for broader or foundational work.
+const (
+	SeverityLeaf   = "leaf"
+	SeverityBranch = "branch"
+	SeverityRoot   = "root"
+)
+
+// ContextCompletion is the stamp context type for completion validations.
+const ContextCompletion = "completion"
+
+// stampHashVersion prefixes the canonical stamp encoding so the hash
+// format can evolve without old chains becoming ambiguous.
+const stampHashVersion = "wl-stamp-v1"
+
+// SigningKeyPath returns the path of the town's stamp-signing key.
+func SigningKeyPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "wasteland.key")
+}
+
+// LoadOrCreateSigningKey returns the town's ed25519 stamp-signing key,
+// generating and saving one (mode 0600) on first use.
+func LoadOrCreateSigningKey(townRoot string) (ed25519.PrivateKey, error) {
+	path := SigningKeyPath(townRoot)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid signing key in %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("saving signing key: %w", err)
+	}
+	return key, nil
+}
+
+// LockStampChain serializes stamp issuing for the town's rig, from reading
+// the chain tip to recording the new stamp. Without it, two validations at
+// once would link to the same previous stamp and fork the chain, which
+// VerifyChains rejects for good. A rig's signing key lives in one town, so
+// a local lock covers every writer of its chain.
+func LockStampChain(townRoot string) (func(), error) {
+	path := SigningKeyPath(townRoot) + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	fl := flock.New(path)
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring stamp chain lock: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// PublicKeyHex returns the hex encoding of a signing key's public half, as
+// published in rigs.public_key.
+func PublicKeyHex(key ed25519.PrivateKey) string {
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// GenerateStampID generates a stamp ID in the format s-<16-char-hash>.
+func GenerateStampID(author, contextID string, at time.Time) string {
+	randomBytes := make([]byte, 8)
+	_, _ = rand.Read(randomBytes)
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%x", author, contextID, at.UnixNano(), randomBytes)))
+	return fmt.Sprintf("s-%x", h[:8])
+}
+
+// StampHash returns the hex SHA-256 of a stamp's canonical encoding. It
+// covers every assessed field and the previous stamp's hash, so changing
+// any stamp breaks its own hash and every later link in the chain.
+// CreatedAt is hashed at second precision, as the database stores it.
+func StampHash(s *doltserver.Stamp) string {
+	valence := make([]string, 0, len(s.Valence))
+	for k, v := range s.Valence {
+		valence = append(valence, k+"="+strconv.FormatFloat(v, 'f', 2, 64))
+	}
+	sort.Strings(valence)
+	tags := append([]string(nil), s.SkillTags...)
+	sort.Strings(tags)
+
+	fields := []string{
+		stampHashVersion,
+		s.ID,
+		s.Author,
+		s.Subject,
+		strings.Join(valence, ","),
+		strconv.FormatFloat(s.Confidence, 'f', 2, 64),
+		s.Severity,
+		s.ContextType,
+		s.ContextID,
+		strings.Join(tags, ","),
+		s.Message,
+		s.PrevStampHash,
+		s.CreatedAt.UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+	// JSON-encode the field list so no separator inside a field (such as a
+	// newline in the message) can make two different stamps collide.
+	data, _ := json.Marshal(fields)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SealStamp links s to its author's previous stamp, then sets its block
+// hash and signature. prev is nil for the author's first stamp.
+func SealStamp(s *doltserver.Stamp, prev *doltserver.Stamp, key ed25519.PrivateKey) {
+	s.CreatedAt = s.CreatedAt.UTC().Truncate(time.Second)
+	s.PrevStampHash = ""
+	if prev != nil {
+		s.PrevStampHash = prev.BlockHash
+	}
+	s.BlockHash = StampHash(s)
+	s.Signature = hex.EncodeToString(ed25519.Sign(key, []byte(s.BlockHash)))
+}
+
+// ChainTip returns the stamp a new stamp should link to: the latest of an
+// author's stamps that no other stamp links to. Returns nil for an empty
+// chain.
+func ChainTip(chain []*doltserver.Stamp) *doltserver.Stamp {
+	linked := make(map[string]bool, len(chain))
+	for _, s := range chain {
+		linked[s.PrevStampHash] = true
+	}
+	var tip *doltserver.Stamp
+	for _, s := range chain {
+		if !linked[s.BlockHash] {
+			tip = s
+		}
+	}
+	return tip
+}
+
+// ChainProblem is an integrity failure found by VerifyChains.
+type ChainProblem struct {
+	Author  string
+	StampID string
+	Problem string
+}
+
+func (p ChainProblem) String() string {
+	if p.StampID == "" {
+		return fmt.Sprintf("%s: %s", p.Author, p.Problem)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Author, p.StampID, p.Problem)
+}
+
+// ChainReport is the result of verifying every author's stamp chain.
+type ChainReport struct {
+	// Stamps counts stamps per author.
+	Stamps map[string]int
+
+	// Problems lists integrity failures, ordered by author.
+	Problems []ChainProblem
+
+	// invalid holds IDs of stamps that failed verification themselves or
+	// sit after a broken link.
+	invalid map[string]bool
+}
+
+// Valid reports whether a stamp passed verification. Only valid stamps
+// count toward reputation.
+func (r *ChainReport) Valid(stampID string) bool {
+	return !r.invalid[stampID]
+}
+
+// ErrChainBroken is returned by ChainReport.Err when problems were found.
+var ErrChainBroken = errors.New("stamp chain verification failed")
+
+// Err returns ErrChainBroken if any problem was found.
+func (r *ChainReport) Err() error {
+	if len(r.Problems) > 0 {
+		return fmt.Errorf("%w: %d problem(s)", ErrChainBroken, len(r.Problems))
+	}
+	return nil
+}
+
+// VerifyChains checks every author's stamp chain: each stamp's hash must
+// match its content and its signature the author's published key, and the
+// stamps must form one unbroken, unforked chain from a single first stamp.
+// keys maps rig handles to hex public keys.
+func VerifyChains(stamps []*doltserver.Stamp, keys map[string]string) *ChainReport {
+	report := &ChainReport{Stamps: make(map[string]int), invalid: make(map[string]bool)}
+
+	byAuthor := make(map[string][]*doltserver.Stamp)
+	for _, s := range stamps {
+		byAuthor[s.Author] = append(byAuthor[s.Author], s)
+	}
+	authors := make([]string, 0, len(byAuthor))
+	for a := range byAuthor {
+		authors = append(authors, a)
+	}
+	sort.Strings(authors)
+
+	for _, author := range authors {
+		chain := byAuthor[author]
+		report.Stamps[author] = len(chain)
+		report.verifyAuthor(author, chain, keys[author])
+	}
+	return report
+}
+
+func (r *ChainReport) problem(author, stampID, format string, args ...any) {
+	r.Problems = append(r.Problems, ChainProblem{Author: author, StampID: stampID, Problem: fmt.Sprintf(format, args...)})
+	if stampID != "" {
+		r.invalid[stampID] = true
+	}
+}
+
+func (r *ChainReport) verifyAuthor(author string, chain []*doltserver.Stamp, keyHex string) {
+	var pub ed25519.PublicKey
+	if raw, err := hex.DecodeString(keyHex); err == nil && len(raw) == ed25519.PublicKeySize {
+		pub = raw
+	} else {
+		r.problem(author, "", "no valid public key published")
+	}
+
+	// Per-stamp checks.
+	children := make(map[string][]*doltserver.Stamp) // prev hash → stamps
+	hashes := make(map[string]bool)
+	for _, s := range chain {
+		if got := StampHash(s); got != s.BlockHash {
+			r.problem(author, s.ID, "block hash mismatch (content altered)")
+		}
+		if pub != nil {
+			sig, err := hex.DecodeString(s.Signature)
+			if err != nil || !ed25519.Verify(pub, []byte(s.BlockHash), sig) {
+				r.problem(author, s.ID, "bad signature")
+			}
+		} else {
+			r.invalid[s.ID] = true
+		}
+		children[s.PrevStampHash] = append(children[s.PrevStampHash], s)
+		hashes[s.BlockHash] = true
+	}
+
+	// Linkage: every prev hash must be another stamp of this author, and
+	// no two stamps may claim the same predecessor.
+	for prev, kids := range children {
+		if prev != "" && !hashes[prev] {
+			for _, s := range kids {
+				r.problem(author, s.ID, "previous stamp %s not found (broken link)", shortHash(prev))
+			}
+		}
+		if len(kids) > 1 {
+			what := "forked chain"
+			if prev == "" {
+				what = "multiple first stamps"
+			}
+			for _, s := range kids {
+				r.problem(author, s.ID, "%s", what)
+			}
+		}
+	}
+
+	// Anything after an invalid stamp is untrusted too.
+	var taint func(hash string)
+	taint = func(hash string) {
+		for _, s := range children[hash] {
+			if !r.invalid[s.ID] {
+				r.invalid[s.ID] = true
+			}
+			taint(s.BlockHash)
+		}
+	}
+	for _, s := range chain {
+		if r.invalid[s.ID] {
+			taint(s.BlockHash)
+		}
+	}
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
@@ -0,0 +1,254 @@
+package wasteland
+
+import (
+	"crypto/ed25519"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func testKey(seed byte) ed25519.PrivateKey {
+	s := make([]byte, ed25519.SeedSize)
+	s[0] = seed
+	return ed25519.NewKeyFromSeed(s)
+}
+
+// buildChain returns n sealed stamps by author, each linked to the last.
+func buildChain(author string, key ed25519.PrivateKey, n int) []*doltserver.Stamp {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var chain []*doltserver.Stamp
+	var prev *doltserver.Stamp
+	for i := 0; i < n; i++ {
+		s := &doltserver.Stamp{
+			ID:          GenerateStampID(author, "c-1", base),
+			Author:      author,
+			Subject:     "worker",
+			Valence:     map[string]float64{"quality": 1, "reliability": 0.5},
+			Confidence:  1,
+			Severity:    SeverityLeaf,
+			ContextID:   "c-1",
+			ContextType: ContextCompletion,
+			SkillTags:   []string{"go"},
+			CreatedAt:   base.Add(time.Duration(i) * time.Minute),
+		}
+		SealStamp(s, prev, key)
+		chain = append(chain, s)
+		prev = s
+	}
+	return chain
+}
+
+func TestStampHash_CoversFields(t *testing.T) {
+	s := buildChain("alice", testKey(1), 1)[0]
+	orig := StampHash(s)
+	if orig != s.BlockHash {
+		t.Fatalf("StampHash() = %s, sealed %s", orig, s.BlockHash)
+	}
+
+	edits := map[string]func(*doltserver.Stamp){
+		"valence":    func(s *doltserver.Stamp) { s.Valence["quality"] = -1 },
+		"confidence": func(s *doltserver.Stamp) { s.Confidence = 0.5 },
+		"subject":    func(s *doltserver.Stamp) { s.Subject = "other" },
+		"message":    func(s *doltserver.Stamp) { s.Message = "edited" },
+		"skills":     func(s *doltserver.Stamp) { s.SkillTags = append(s.SkillTags, "dolt") },
+		"prev":       func(s *doltserver.Stamp) { s.PrevStampHash = "abc" },
+		"created_at": func(s *doltserver.Stamp) { s.CreatedAt = s.CreatedAt.Add(time.Second) },
+	}
+	for name, edit := range edits {
+		c := *s
+		c.Valence = map[string]float64{"quality": 1, "reliability": 0.5}
+		c.SkillTags = append([]string(nil), s.SkillTags...)
+		edit(&c)
+		if StampHash(&c) == orig {
+			t.Errorf("editing %s did not change the hash", name)
+		}
+	}
+
+	// Sub-second precision and tag order are not significant.
+	c := *s
+	c.CreatedAt = s.CreatedAt.Add(300 * time.Millisecond)
+	c.SkillTags = []string{"go"}
+	if StampHash(&c) != orig {
+		t.Error("sub-second CreatedAt changed the hash")
+	}
+}
+
+func TestVerifyChains_Intact(t *testing.T) {
+	alice, bob := testKey(1), testKey(2)
+	stamps := append(buildChain("alice", alice, 3), buildChain("bob", bob, 2)...)
+	keys := map[string]string{"alice": PublicKeyHex(alice), "bob": PublicKeyHex(bob)}
+
+	report := VerifyChains(stamps, keys)
+	if err := report.Err(); err != nil {
+		t.Fatalf("VerifyChains() = %v: %v", err, report.Problems)
+	}
+	if report.Stamps["alice"] != 3 || report.Stamps["bob"] != 2 {
+		t.Errorf("Stamps = %v", report.Stamps)
+	}
+	for _, s := range stamps {
+		if !report.Valid(s.ID) {
+			t.Errorf("stamp %s not valid", s.ID)
+		}
+	}
+}
+
+func TestVerifyChains_Problems(t *testing.T) {
+	key := testKey(1)
+	keys := map[string]string{"alice": PublicKeyHex(key)}
+
+	tests := []struct {
+		name        string
+		tamper      func(chain []*doltserver.Stamp) []*doltserver.Stamp
+		keys        map[string]string
+		wantProblem string
+		wantInvalid []int // indexes into the original chain
+	}{
+		{
+			name: "edited content",
+			tamper: func(c []*doltserver.Stamp) []*doltserver.Stamp {
+				c[1].Valence = map[string]float64{"quality": 1, "reliability": 1}
+				return c
+			},
+			wantProblem: "block hash mismatch",
+			wantInvalid: []int{1, 2},
+		},
+		{
+			name: "rehashed without key",
+			tamper: func(c []*doltserver.Stamp) []*doltserver.Stamp {
+				c[2].Message = "forged"
+				c[2].BlockHash = StampHash(c[2])
+				return c
+			},
+			wantProblem: "bad signature",
+			wantInvalid: []int{2},
+		},
+		{
+			name: "deleted stamp",
+			tamper: func(c []*doltserver.Stamp) []*doltserver.Stamp {
+				return []*doltserver.Stamp{c[0], c[2]}
+			},
+			wantProblem: "broken link",
+			wantInvalid: []int{2},
+		},
+		{
+			name: "fork",
+			tamper: func(c []*doltserver.Stamp) []*doltserver.Stamp {
+				fork := &doltserver.Stamp{ID: "s-fork", Author: "alice", Subject: "worker", CreatedAt: c[1].CreatedAt}
+				SealStamp(fork, c[0], key)
+				return append(c, fork)
+			},
+			wantProblem: "forked chain",
+			wantInvalid: []int{1, 2},
+		},
+		{
+			name:        "no published key",
+			tamper:      func(c []*doltserver.Stamp) []*doltserver.Stamp { return c },
+			keys:        map[string]string{},
+			wantProblem: "no valid public key",
+			wantInvalid: []int{0, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := buildChain("alice", key, 3)
+			orig := append([]*doltserver.Stamp(nil), chain...)
+			k := keys
+			if tt.keys != nil {
+				k = tt.keys
+			}
+
+			report := VerifyChains(tt.tamper(chain), k)
+			if report.Err() == nil {
+				t.Fatal("expected problems")
+			}
+			found := false
+			for _, p := range report.Problems {
+				if strings.Contains(p.Problem, tt.wantProblem) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Problems = %v, want one containing %q", report.Problems, tt.wantProblem)
+			}
+			for _, i := range tt.wantInvalid {
+				if report.Valid(orig[i].ID) {
+					t.Errorf("stamp %d should be invalid", i)
+				}
+			}
+		})
+	}
+}
+
+func TestChainTip(t *testing.T) {
+	if ChainTip(nil) != nil {
+		t.Error("ChainTip(nil) should be nil")
+	}
+	chain := buildChain("alice", testKey(1), 3)
+	// Order must not matter: the tip is the stamp nothing links to.
+	shuffled := []*doltserver.Stamp{chain[2], chain[0], chain[1]}
+	if got := ChainTip(shuffled); got != chain[2] {
+		t.Errorf("ChainTip() = %s, want %s", got.ID, chain[2].ID)
+	}
+}
+
+func TestLoadOrCreateSigningKey(t *testing.T) {
+	townRoot := t.TempDir()
+	key, err := LoadOrCreateSigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadOrCreateSigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Equal(again) {
+		t.Error("second load returned a different key")
+	}
+
+	info, err := os.Stat(SigningKeyPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := os.WriteFile(SigningKeyPath(townRoot), []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateSigningKey(townRoot); err == nil {
+		t.Error("expected error for corrupt key")
+	}
+}
+
+func TestLockStampChain(t *testing.T) {
+	townRoot := t.TempDir()
+	unlock, err := LockStampChain(townRoot)
+	if err != nil {
+		t.Fatalf("LockStampChain() error: %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		second, err := LockStampChain(townRoot)
+		if err == nil {
+			second()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after unlock")
+	}
+}
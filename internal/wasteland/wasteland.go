@@ -1,9 +1,10 @@
 // Package wasteland implements the Wasteland federation protocol for Gas Town.
 //
-// The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
-// sovereign fork of a shared commons database. Rigs register by writing
-// to the commons' rigs table, and contribute wanted work items and
-// completions through DoltHub's fork/PR/merge primitives.
+// The Wasteland is a federation of Gas Towns via DoltHub, or a self-hosted
+// dolt remote (see doltserver.Remote). Each rig has a sovereign fork of a
+// shared commons database. Rigs register by writing to the commons' rigs
+// table, and contribute wanted work items and completions through
+// fork/PR/merge primitives.
 //
 // See ~/hop/docs/wasteland/design.md for the full design.
 package wasteland
@@ -19,6 +20,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 // ErrNotJoined indicates the rig has not joined a wasteland.
@@ -26,10 +29,14 @@ var ErrNotJoined = errors.New("rig has not joined a wasteland")
 
 // Config holds the wasteland configuration for a rig.
 type Config struct {
-	// Upstream is the DoltHub path of the upstream commons (e.g., "steveyegge/wl-commons").
+	// Upstream is the org/db path of the upstream commons (e.g., "steveyegge/wl-commons").
 	Upstream string `json:"upstream"`
 
-	// ForkOrg is the DoltHub org where the fork lives (e.g., "alice-dev").
+	// Remote is the remote backend spec (see doltserver.ParseRemote).
+	// Empty means DoltHub.
+	Remote string `json:"remote,omitempty"`
+
+	// ForkOrg is the org where the fork lives (e.g., "alice-dev").
 	ForkOrg string `json:"fork_org"`
 
 	// ForkDB is the database name of the fork (e.g., "wl-commons").
@@ -141,10 +148,12 @@ func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
 }
 
 // CloneLocally clones a DoltHub database to a local directory.
-// Returns the absolute path to the clone.
 func CloneLocally(org, db, targetDir string) error {
-	remoteURL := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db)
+	return CloneURL(fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db), targetDir)
+}
 
+// CloneURL clones the database at a dolt remote URL to a local directory.
+func CloneURL(remoteURL, targetDir string) error {
 	if err := os.MkdirAll(filepath.Dir(targetDir), 0755); err != nil {
 		return fmt.Errorf("creating parent directory: %w", err)
 	}
@@ -217,10 +226,13 @@ func PushToOrigin(localDir string) error {
 	return nil
 }
 
-// AddUpstreamRemote adds the upstream commons as a remote named "upstream".
+// AddUpstreamRemote adds the upstream DoltHub commons as a remote named "upstream".
 func AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	url := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB)
+	return AddUpstreamRemoteURL(localDir, fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB))
+}
 
+// AddUpstreamRemoteURL adds a dolt remote URL as the remote named "upstream".
+func AddUpstreamRemoteURL(localDir, url string) error {
 	// Check if upstream remote already exists
 	checkCmd := exec.Command("dolt", "remote", "-v")
 	checkCmd.Dir = localDir
@@ -262,12 +274,14 @@ func escapeSQLString(s string) string {
 	return strings.ReplaceAll(s, "'", "''")
 }
 
-// DoltHubAPI abstracts DoltHub REST API operations.
+// DoltHubAPI abstracts DoltHub REST API operations. Self-hosted remotes
+// implement it without DoltHub (see NewServiceForRemote).
 type DoltHubAPI interface {
 	ForkRepo(fromOrg, fromDB, toOrg, token string) error
 }
 
-// DoltCLI abstracts dolt CLI subprocess operations.
+// DoltCLI abstracts dolt CLI subprocess operations. Org and db name a
+// database on the service's remote backend.
 type DoltCLI interface {
 	Clone(org, db, targetDir string) error
 	RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion string) error
@@ -287,6 +301,10 @@ type Service struct {
 	CLI        DoltCLI
 	Config     ConfigStore
 	OnProgress func(step string) // optional callback for progress reporting
+
+	// Remote is the backend API and CLI talk to, recorded in the saved
+	// config. The zero value is DoltHub.
+	Remote doltserver.Remote
 }
 
 // Join orchestrates the wasteland join workflow: fork -> clone -> add upstream -> register -> push -> save config.
@@ -340,6 +358,7 @@ func (s *Service) Join(upstream, forkOrg, token, handle, displayName, ownerEmail
 
 	cfg := &Config{
 		Upstream:  upstream,
+		Remote:    s.Remote.String(),
 		ForkOrg:   forkOrg,
 		ForkDB:    upstreamDB,
 		LocalDir:  localDir,
@@ -360,11 +379,14 @@ func (h *httpDoltHubAPI) ForkRepo(fromOrg, fromDB, toOrg, token string) error {
 	return ForkDoltHubRepo(fromOrg, fromDB, toOrg, token)
 }
 
-// execDoltCLI implements DoltCLI using real dolt subprocess calls.
-type execDoltCLI struct{}
+// execDoltCLI implements DoltCLI using real dolt subprocess calls against
+// a remote backend.
+type execDoltCLI struct {
+	remote doltserver.Remote
+}
 
 func (e *execDoltCLI) Clone(org, db, targetDir string) error {
-	return CloneLocally(org, db, targetDir)
+	return CloneURL(e.remote.URL(org, db), targetDir)
 }
 func (e *execDoltCLI) RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion string) error {
 	return RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion)
@@ -373,7 +395,7 @@ func (e *execDoltCLI) Push(localDir string) error {
 	return PushToOrigin(localDir)
 }
 func (e *execDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	return AddUpstreamRemote(localDir, upstreamOrg, upstreamDB)
+	return AddUpstreamRemoteURL(localDir, e.remote.URL(upstreamOrg, upstreamDB))
 }
 
 // fileConfigStore implements ConfigStore using filesystem persistence.
@@ -386,11 +408,13 @@ func (f *fileConfigStore) Save(townRoot string, cfg *Config) error {
 	return SaveConfig(townRoot, cfg)
 }
 
-// NewService creates a Service with real (production) dependencies.
+// NewService creates a Service with real (production) dependencies, using
+// DoltHub.
 func NewService() *Service {
 	return &Service{
 		API:    &httpDoltHubAPI{},
-		CLI:    &execDoltCLI{},
+		CLI:    &execDoltCLI{remote: doltserver.DoltHubRemote},
 		Config: &fileConfigStore{},
+		Remote: doltserver.DoltHubRemote,
 	}
 }
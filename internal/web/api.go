@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 	"os/exec"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -223,6 +225,35 @@ func (h *APIHandler) handleCommands(w http.ResponseWriter, _ *http.Request) {
 
 // runGtCommand executes a gt command with the given args.
 func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
+	stdout, stderr, err := h.execGt(ctx, timeout, args)
+
+	// Combine stdout and stderr for output
+	output := string(stdout)
+	if len(stderr) > 0 {
+		if output != "" {
+			output += "\n"
+		}
+		output += string(stderr)
+	}
+	return output, err
+}
+
+// runGtCommandStdout executes a gt command and returns only its stdout, for
+// output that must reach the client byte for byte (attachment content,
+// JSON). Stderr is folded into the error on failure.
+func (h *APIHandler) runGtCommandStdout(ctx context.Context, timeout time.Duration, args []string) ([]byte, error) {
+	stdout, stderr, err := h.execGt(ctx, timeout, args)
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return stdout, fmt.Errorf("%w: %s", err, msg)
+		}
+	}
+	return stdout, err
+}
+
+// execGt runs gt with the given args, bounded by timeout and the command
+// semaphore.
+func (h *APIHandler) execGt(ctx context.Context, timeout time.Duration, args []string) ([]byte, []byte, error) {
 	// Apply timeout first so it bounds both semaphore wait and command execution.
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -232,7 +263,7 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	case h.cmdSem <- struct{}{}:
 		defer func() { <-h.cmdSem }()
 	case <-ctx.Done():
-		return "", fmt.Errorf("command slot unavailable: %w", ctx.Err())
+		return nil, nil, fmt.Errorf("command slot unavailable: %w", ctx.Err())
 	}
 
 	cmd := exec.CommandContext(ctx, h.gtPath, args...)
@@ -248,24 +279,15 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 
 	err := cmd.Run()
 
-	// Combine stdout and stderr for output
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if output != "" {
-			output += "\n"
-		}
-		output += stderr.String()
-	}
-
 	if ctx.Err() == context.DeadlineExceeded {
-		return output, fmt.Errorf("command timed out after %v", timeout)
+		return stdout.Bytes(), stderr.Bytes(), fmt.Errorf("command timed out after %v", timeout)
 	}
 
 	if err != nil {
-		return output, fmt.Errorf("command failed: %v", err)
+		return stdout.Bytes(), stderr.Bytes(), fmt.Errorf("command failed: %v", err)
 	}
 
-	return output, nil
+	return stdout.Bytes(), stderr.Bytes(), nil
 }
 
 // sendError sends a JSON error response.
@@ -290,6 +312,10 @@ type MailMessage struct {
 	Priority  string `json:"priority,omitempty"`
 	ThreadID  string `json:"thread_id,omitempty"`
 	ReplyTo   string `json:"reply_to,omitempty"`
+
+	// Payload and Attachments are only filled in by /api/mail/read.
+	Payload     *mail.Payload     `json:"payload,omitempty"`
+	Attachments []mail.Attachment `json:"attachments,omitempty"`
 }
 
 // MailInboxResponse is the response for /api/mail/inbox.
@@ -503,19 +529,68 @@ func (h *APIHandler) handleMailRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", msgID})
+	// ?attachment=<name> downloads one attachment instead of the message
+	if name := r.URL.Query().Get("attachment"); name != "" {
+		h.handleMailAttachment(w, r, msgID, name)
+		return
+	}
+
+	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", "--json", msgID})
 	if err != nil {
 		h.sendError(w, "Failed to read message: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Parse the message output
-	msg := parseMailReadOutput(output, msgID)
+	msg := parseMailReadJSON(output, msgID)
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(msg)
 }
 
+// handleMailAttachment serves a message attachment's content.
+func (h *APIHandler) handleMailAttachment(w http.ResponseWriter, r *http.Request, msgID, name string) {
+	if !isValidAttachmentName(name) {
+		h.sendError(w, "Invalid attachment name", http.StatusBadRequest)
+		return
+	}
+
+	// Look up the media type first; this also reports unknown attachments
+	// as 404 rather than a command failure.
+	output, err := h.runGtCommandStdout(r.Context(), 10*time.Second, []string{"mail", "read", "--json", msgID})
+	if err != nil {
+		h.sendError(w, "Failed to read message: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var att *mail.Attachment
+	for _, a := range parseMailReadJSON(string(output), msgID).Attachments {
+		if a.Name == name {
+			att = &a
+			break
+		}
+	}
+	if att == nil {
+		h.sendError(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+
+	// Stdout only: a warning on stderr must not end up in the file.
+	content, err := h.runGtCommandStdout(r.Context(), 10*time.Second, []string{"mail", "read", msgID, "--attachment=" + name})
+	if err != nil {
+		h.sendError(w, "Failed to read attachment: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mediaType := att.MediaType
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = w.Write(content)
+}
+
 // MailSendRequest is the request body for /api/mail/send.
 type MailSendRequest struct {
 	To      string `json:"to"`
@@ -637,6 +712,33 @@ func parseMailInboxText(output string) []MailMessage {
 	return messages
 }
 
+// parseMailReadJSON parses the output of "gt mail read --json". The JSON
+// message comes first (stderr warnings are appended after it); output that
+// isn't JSON falls back to parsing the human-readable format.
+func parseMailReadJSON(output string, msgID string) MailMessage {
+	var m mail.Message
+	if err := json.NewDecoder(strings.NewReader(output)).Decode(&m); err != nil || m.ID == "" {
+		return parseMailReadOutput(output, msgID)
+	}
+	msg := MailMessage{
+		ID:          m.ID,
+		From:        m.From,
+		To:          m.To,
+		Subject:     m.Subject,
+		Body:        m.Body,
+		Read:        m.Read,
+		Priority:    string(m.Priority),
+		ThreadID:    m.ThreadID,
+		ReplyTo:     m.ReplyTo,
+		Payload:     m.Payload,
+		Attachments: m.Attachments,
+	}
+	if !m.Timestamp.IsZero() {
+		msg.Timestamp = m.Timestamp.Format(time.RFC3339)
+	}
+	return msg
+}
+
 // parseMailReadOutput parses the output from "gt mail read <id>".
 func parseMailReadOutput(output string, msgID string) MailMessage {
 	msg := MailMessage{ID: msgID}
@@ -1095,6 +1095,30 @@ func TestRunGtCommandSemaphoreTimeoutBudget(t *testing.T) {
 	}
 }
 
+// TestRunGtCommandStdout verifies that stderr stays out of the returned
+// output (attachment bytes must not pick up warnings) but is reported on
+// failure.
+func TestRunGtCommandStdout(t *testing.T) {
+	h := &APIHandler{
+		gtPath:  "sh",
+		workDir: t.TempDir(),
+		cmdSem:  make(chan struct{}, 1),
+	}
+
+	out, err := h.runGtCommandStdout(context.Background(), 5*time.Second, []string{"-c", "printf data; echo warning >&2"})
+	if err != nil {
+		t.Fatalf("runGtCommandStdout() error = %v", err)
+	}
+	if string(out) != "data" {
+		t.Errorf("output = %q, want %q", out, "data")
+	}
+
+	_, err = h.runGtCommandStdout(context.Background(), 5*time.Second, []string{"-c", "echo no such attachment >&2; exit 1"})
+	if err == nil || !strings.Contains(err.Error(), "no such attachment") {
+		t.Errorf("error = %v, want stderr in error", err)
+	}
+}
+
 // TestHandleSessionPreviewPrefixValidation verifies that handleSessionPreview
 // accepts session names with known rig prefixes and rejects invalid prefixes.
 func TestHandleSessionPreviewPrefixValidation(t *testing.T) {
@@ -1158,3 +1182,31 @@ func TestHandleSessionPreviewPrefixValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMailReadJSON(t *testing.T) {
+	output := `{"id":"hq-1","from":"gastown/refinery","to":"gastown/witness","subject":"MERGE_FAILED nux",` +
+		`"body":"Branch: b","timestamp":"2026-01-02T03:04:05Z","read":false,"priority":"high","type":"task",` +
+		`"payload":{"schema":"gastown.protocol.merge_failed.v1","data":{"branch":"b"}},` +
+		`"attachments":[{"name":"gate.log","size":12,"sha256":"` + strings.Repeat("0", 64) + `","media_type":"text/plain"}]}
+gt mail read: delivery ack failed: boom`
+
+	msg := parseMailReadJSON(output, "hq-1")
+	if msg.Subject != "MERGE_FAILED nux" || msg.Body != "Branch: b" || msg.Priority != "high" {
+		t.Errorf("msg = %+v", msg)
+	}
+	if msg.Timestamp != "2026-01-02T03:04:05Z" {
+		t.Errorf("Timestamp = %q", msg.Timestamp)
+	}
+	if msg.Payload == nil || msg.Payload.Schema != "gastown.protocol.merge_failed.v1" {
+		t.Errorf("Payload = %+v", msg.Payload)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Name != "gate.log" {
+		t.Errorf("Attachments = %+v", msg.Attachments)
+	}
+
+	// Non-JSON output falls back to the text parser
+	text := parseMailReadJSON("Subject: Hi\nFrom: mayor/\n\nbody", "hq-2")
+	if text.Subject != "Hi" || text.Body != "body" {
+		t.Errorf("text fallback = %+v", text)
+	}
+}
@@ -0,0 +1,525 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Pagination limits for /api/v1 list endpoints.
+const (
+	v1DefaultLimit = 50
+	v1MaxLimit     = 500
+)
+
+// v1CacheTTL is how long a read from the town is shared between requests.
+// Pollers hitting the API every few seconds then cost one read per resource
+// per TTL instead of one per request.
+const v1CacheTTL = 2 * time.Second
+
+// v1CacheMaxEntries bounds the cache; stale entries are dropped past it.
+const v1CacheMaxEntries = 256
+
+// V1Page is the envelope of every /api/v1 list response.
+// NextCursor is empty on the last page; pass it back as ?cursor= to get the
+// next one.
+type V1Page struct {
+	Items      any    `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// V1Error is the body of /api/v1 error responses.
+type V1Error struct {
+	Error string `json:"error"`
+}
+
+// V1Convoy is a convoy in /api/v1/convoys.
+type V1Convoy struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+// V1MailMessage is a message in /api/v1/mail.
+type V1MailMessage struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Read      bool      `json:"read"`
+	Priority  string    `json:"priority,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	ReplyTo   string    `json:"reply_to,omitempty"`
+}
+
+// V1Rig is a rig in /api/v1/rigs.
+type V1Rig struct {
+	Name        string   `json:"name"`
+	GitURL      string   `json:"git_url,omitempty"`
+	Polecats    []string `json:"polecats,omitempty"`
+	Crew        []string `json:"crew,omitempty"`
+	HasWitness  bool     `json:"has_witness"`
+	HasRefinery bool     `json:"has_refinery"`
+}
+
+// V1Polecat is a polecat in /api/v1/polecats.
+type V1Polecat struct {
+	Name      string    `json:"name"`
+	Rig       string    `json:"rig"`
+	State     string    `json:"state"`
+	Branch    string    `json:"branch,omitempty"`
+	Issue     string    `json:"issue,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// V1MergeRequest is a queued merge request in /api/v1/merge-queue.
+type V1MergeRequest struct {
+	Position     int       `json:"position"`
+	ID           string    `json:"id"`
+	Branch       string    `json:"branch"`
+	Worker       string    `json:"worker,omitempty"`
+	IssueID      string    `json:"issue_id,omitempty"`
+	TargetBranch string    `json:"target_branch,omitempty"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	Age          string    `json:"age,omitempty"`
+}
+
+// V1Escalation is an open escalation in /api/v1/escalations.
+type V1Escalation struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	EscalatedBy string `json:"escalated_by,omitempty"`
+	EscalatedAt string `json:"escalated_at,omitempty"`
+	AckedBy     string `json:"acked_by,omitempty"`
+	RelatedBead string `json:"related_bead,omitempty"`
+}
+
+// V1Hook is a bead on an agent's hook in /api/v1/hooks.
+type V1Hook struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Assignee  string `json:"assignee,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// V1Handler serves the versioned read API under /api/v1. Unlike APIHandler
+// it never runs gt or bd subcommands itself; everything is read through a
+// TownReader. Responses carry an ETag, and a matching If-None-Match gets
+// 304 Not Modified.
+type V1Handler struct {
+	reader    TownReader
+	csrfToken string
+
+	cacheMu sync.Mutex
+	cache   map[string]*v1CacheEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type v1CacheEntry struct {
+	mu    sync.Mutex
+	at    time.Time
+	items any
+}
+
+// NewV1Handler creates the /api/v1 handler. csrfToken is enforced on any
+// non-GET request, as for the rest of the dashboard API.
+func NewV1Handler(reader TownReader, csrfToken string) *V1Handler {
+	return &V1Handler{
+		reader:    reader,
+		csrfToken: csrfToken,
+		cache:     make(map[string]*v1CacheEntry),
+		ttl:       v1CacheTTL,
+		now:       time.Now,
+	}
+}
+
+// ServeHTTP routes /api/v1 requests.
+func (h *V1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// No CORS headers — same-origin only, like APIHandler.
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if h.csrfToken != "" && r.Header.Get("X-Dashboard-Token") != h.csrfToken {
+			h.sendError(w, "Invalid or missing dashboard token", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		h.sendError(w, "The v1 API is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	key := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch key {
+	case "/convoys":
+		status := q.Get("status")
+		if status == "" {
+			status = "open"
+		}
+		if status != "open" && status != "closed" && status != "all" {
+			h.sendError(w, "status must be open, closed or all", http.StatusBadRequest)
+			return
+		}
+		serveV1List(h, w, r, key+"?status="+status, func() ([]V1Convoy, error) { return h.convoys(status) })
+	case "/mail":
+		address := q.Get("address")
+		if address == "" {
+			address = "overseer"
+		}
+		if !isValidMailAddress(address) {
+			h.sendError(w, "Invalid address", http.StatusBadRequest)
+			return
+		}
+		unread := q.Get("unread") == "true"
+		serveV1List(h, w, r, key+"?address="+address+"&unread="+strconv.FormatBool(unread),
+			func() ([]V1MailMessage, error) { return h.mail(address, unread) })
+	case "/rigs":
+		serveV1List(h, w, r, key, h.rigs)
+	case "/polecats", "/merge-queue":
+		rigName := q.Get("rig")
+		if rigName != "" && !isValidRigName(rigName) {
+			h.sendError(w, "Invalid rig name", http.StatusBadRequest)
+			return
+		}
+		if key == "/polecats" {
+			serveV1List(h, w, r, key+"?rig="+rigName, func() ([]V1Polecat, error) { return h.polecats(rigName) })
+		} else {
+			serveV1List(h, w, r, key+"?rig="+rigName, func() ([]V1MergeRequest, error) { return h.mergeQueue(rigName) })
+		}
+	case "/escalations":
+		serveV1List(h, w, r, key, h.escalations)
+	case "/hooks":
+		serveV1List(h, w, r, key, h.hooks)
+	default:
+		h.sendError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// serveV1List writes one page of a list resource. key identifies the
+// resource and its filters for caching.
+func serveV1List[T any](h *V1Handler, w http.ResponseWriter, r *http.Request, key string, list func() ([]T, error)) {
+	limit, offset, err := parseV1Page(r.URL.Query().Get("limit"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.cached(key, func() (any, error) { return list() })
+	if err != nil {
+		if errors.Is(err, rig.ErrRigNotFound) {
+			h.sendError(w, "Rig not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("api/v1: %s: %v", key, err)
+		h.sendError(w, "Failed to read town state", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, r, paginate(items.([]T), limit, offset))
+}
+
+// cached returns the items for key, reading them at most once per TTL.
+// Concurrent requests for the same key wait for a single read.
+func (h *V1Handler) cached(key string, list func() (any, error)) (any, error) {
+	h.cacheMu.Lock()
+	e := h.cache[key]
+	if e == nil {
+		if len(h.cache) >= v1CacheMaxEntries {
+			h.evictStale()
+		}
+		e = &v1CacheEntry{}
+		h.cache[key] = e
+	}
+	h.cacheMu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.items != nil && h.now().Sub(e.at) < h.ttl {
+		return e.items, nil
+	}
+	items, err := list()
+	if err != nil {
+		return nil, err
+	}
+	e.items, e.at = items, h.now()
+	return items, nil
+}
+
+// evictStale drops expired cache entries. Entries being read right now are
+// kept. Caller holds cacheMu.
+func (h *V1Handler) evictStale() {
+	for key, e := range h.cache {
+		if !e.mu.TryLock() {
+			continue
+		}
+		stale := h.now().Sub(e.at) >= h.ttl
+		e.mu.Unlock()
+		if stale {
+			delete(h.cache, key)
+		}
+	}
+}
+
+// sendJSON writes v with a content ETag, answering 304 when the client
+// already has this version.
+func (h *V1Handler) sendJSON(w http.ResponseWriter, r *http.Request, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		h.sendError(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (h *V1Handler) sendError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(V1Error{Error: message})
+}
+
+// etagMatches implements If-None-Match's weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseV1Page parses ?limit= and the opaque ?cursor= into a page size and
+// offset.
+func parseV1Page(limitParam, cursor string) (limit, offset int, err error) {
+	limit = v1DefaultLimit
+	if limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > v1MaxLimit {
+			return 0, 0, errors.New("limit must be between 1 and " + strconv.Itoa(v1MaxLimit))
+		}
+	}
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return 0, 0, errors.New("invalid cursor")
+		}
+		offset, err = strconv.Atoi(strings.TrimPrefix(string(raw), "o:"))
+		if err != nil || offset < 0 || !strings.HasPrefix(string(raw), "o:") {
+			return 0, 0, errors.New("invalid cursor")
+		}
+	}
+	return limit, offset, nil
+}
+
+func encodeV1Cursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+// paginate returns the page of items starting at offset.
+func paginate[T any](items []T, limit, offset int) V1Page {
+	lo := min(offset, len(items))
+	hi := min(lo+limit, len(items))
+	p := V1Page{Items: items[lo:hi], Total: len(items)}
+	if hi < len(items) {
+		p.NextCursor = encodeV1Cursor(hi)
+	}
+	return p
+}
+
+func (h *V1Handler) convoys(status string) ([]V1Convoy, error) {
+	issues, err := h.reader.Convoys(status)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1Convoy, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, V1Convoy{
+			ID:        is.ID,
+			Title:     is.Title,
+			Status:    is.Status,
+			Assignee:  is.Assignee,
+			Labels:    is.Labels,
+			CreatedAt: is.CreatedAt,
+			UpdatedAt: is.UpdatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (h *V1Handler) mail(address string, unreadOnly bool) ([]V1MailMessage, error) {
+	msgs, err := h.reader.Mail(address)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1MailMessage, 0, len(msgs))
+	for _, m := range msgs {
+		if unreadOnly && m.Read {
+			continue
+		}
+		out = append(out, v1MailMessage(m))
+	}
+	return out, nil
+}
+
+func v1MailMessage(m *mail.Message) V1MailMessage {
+	return V1MailMessage{
+		ID:        m.ID,
+		From:      m.From,
+		To:        m.To,
+		Subject:   m.Subject,
+		Body:      m.Body,
+		Timestamp: m.Timestamp,
+		Read:      m.Read,
+		Priority:  string(m.Priority),
+		Type:      string(m.Type),
+		ThreadID:  m.ThreadID,
+		ReplyTo:   m.ReplyTo,
+	}
+}
+
+func (h *V1Handler) rigs() ([]V1Rig, error) {
+	rigs, err := h.reader.Rigs()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1Rig, 0, len(rigs))
+	for _, r := range rigs {
+		out = append(out, V1Rig{
+			Name:        r.Name,
+			GitURL:      r.GitURL,
+			Polecats:    r.Polecats,
+			Crew:        r.Crew,
+			HasWitness:  r.HasWitness,
+			HasRefinery: r.HasRefinery,
+		})
+	}
+	return out, nil
+}
+
+func (h *V1Handler) polecats(rigName string) ([]V1Polecat, error) {
+	polecats, err := h.reader.Polecats(rigName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1Polecat, 0, len(polecats))
+	for _, p := range polecats {
+		out = append(out, v1Polecat(p))
+	}
+	return out, nil
+}
+
+func v1Polecat(p *polecat.Polecat) V1Polecat {
+	return V1Polecat{
+		Name:      p.Name,
+		Rig:       p.Rig,
+		State:     string(p.State),
+		Branch:    p.Branch,
+		Issue:     p.Issue,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func (h *V1Handler) mergeQueue(rigName string) ([]V1MergeRequest, error) {
+	items, err := h.reader.MergeQueue(rigName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1MergeRequest, 0, len(items))
+	for _, item := range items {
+		out = append(out, v1MergeRequest(item))
+	}
+	return out, nil
+}
+
+func v1MergeRequest(item refinery.QueueItem) V1MergeRequest {
+	mr := V1MergeRequest{Position: item.Position, Age: item.Age}
+	if item.MR != nil {
+		mr.ID = item.MR.ID
+		mr.Branch = item.MR.Branch
+		mr.Worker = item.MR.Worker
+		mr.IssueID = item.MR.IssueID
+		mr.TargetBranch = item.MR.TargetBranch
+		mr.Status = string(item.MR.Status)
+		mr.CreatedAt = item.MR.CreatedAt
+	}
+	return mr
+}
+
+func (h *V1Handler) escalations() ([]V1Escalation, error) {
+	issues, err := h.reader.Escalations()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1Escalation, 0, len(issues))
+	for _, is := range issues {
+		f := beads.ParseEscalationFields(is.Description)
+		out = append(out, V1Escalation{
+			ID:          is.ID,
+			Title:       is.Title,
+			Severity:    f.Severity,
+			Reason:      f.Reason,
+			EscalatedBy: f.EscalatedBy,
+			EscalatedAt: f.EscalatedAt,
+			AckedBy:     f.AckedBy,
+			RelatedBead: f.RelatedBead,
+		})
+	}
+	return out, nil
+}
+
+func (h *V1Handler) hooks() ([]V1Hook, error) {
+	issues, err := h.reader.Hooks()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]V1Hook, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, V1Hook{ID: is.ID, Title: is.Title, Assignee: is.Assignee, UpdatedAt: is.UpdatedAt})
+	}
+	return out, nil
+}
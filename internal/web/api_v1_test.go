@@ -0,0 +1,169 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeTownReader serves canned town state and counts reads.
+type fakeTownReader struct {
+	convoys []*beads.Issue
+	mail    []*mail.Message
+	reads   int
+}
+
+func (f *fakeTownReader) Convoys(status string) ([]*beads.Issue, error) {
+	f.reads++
+	var out []*beads.Issue
+	for _, c := range f.convoys {
+		if status == "all" || c.Status == status {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTownReader) Mail(string) ([]*mail.Message, error) { f.reads++; return f.mail, nil }
+func (f *fakeTownReader) Rigs() ([]*rig.Rig, error)            { return []*rig.Rig{{Name: "gastown"}}, nil }
+func (f *fakeTownReader) Escalations() ([]*beads.Issue, error) { return nil, nil }
+func (f *fakeTownReader) Hooks() ([]*beads.Issue, error)       { return nil, nil }
+
+func (f *fakeTownReader) Polecats(rigName string) ([]*polecat.Polecat, error) {
+	if rigName != "" && rigName != "gastown" {
+		return nil, fmt.Errorf("rig %q: %w", rigName, rig.ErrRigNotFound)
+	}
+	return []*polecat.Polecat{{Name: "toast", Rig: "gastown", State: polecat.StateWorking}}, nil
+}
+
+func (f *fakeTownReader) MergeQueue(string) ([]refinery.QueueItem, error) {
+	return []refinery.QueueItem{{Position: 1, MR: &refinery.MergeRequest{ID: "gt-mr1", Branch: "polecat/toast", Status: refinery.MROpen}}}, nil
+}
+
+func newTestV1Handler(reader TownReader) *V1Handler {
+	h := NewV1Handler(reader, "test-token")
+	h.ttl = time.Hour
+	return h
+}
+
+func getV1(t *testing.T, h http.Handler, url string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestV1Handler_Pagination(t *testing.T) {
+	reader := &fakeTownReader{}
+	for i := 1; i <= 5; i++ {
+		reader.convoys = append(reader.convoys, &beads.Issue{ID: fmt.Sprintf("hq-cv-%d", i), Status: "open"})
+	}
+	reader.convoys = append(reader.convoys, &beads.Issue{ID: "hq-cv-closed", Status: "closed"})
+	h := newTestV1Handler(reader)
+
+	var ids []string
+	url := "/api/v1/convoys?limit=2"
+	for pages := 0; url != ""; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		w := getV1(t, h, url, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", url, w.Code, w.Body.String())
+		}
+		var page struct {
+			Items      []V1Convoy `json:"items"`
+			Total      int        `json:"total"`
+			NextCursor string     `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 5 {
+			t.Errorf("total = %d, want 5 open convoys", page.Total)
+		}
+		for _, c := range page.Items {
+			ids = append(ids, c.ID)
+		}
+		url = ""
+		if page.NextCursor != "" {
+			url = "/api/v1/convoys?limit=2&cursor=" + page.NextCursor
+		}
+	}
+	if got := strings.Join(ids, ","); got != "hq-cv-1,hq-cv-2,hq-cv-3,hq-cv-4,hq-cv-5" {
+		t.Errorf("paged ids = %s", got)
+	}
+	if reader.reads != 1 {
+		t.Errorf("reads = %d, want pages served from one cached read", reader.reads)
+	}
+}
+
+func TestV1Handler_ETag(t *testing.T) {
+	reader := &fakeTownReader{mail: []*mail.Message{{ID: "hq-m1", From: "mayor/", Subject: "hi"}}}
+	h := newTestV1Handler(reader)
+
+	w := getV1(t, h, "/api/v1/mail?address=mayor/", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first GET = %d, etag %q", w.Code, etag)
+	}
+
+	w = getV1(t, h, "/api/v1/mail?address=mayor/", http.Header{"If-None-Match": {`W/` + etag}})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("conditional GET = %d with %d bytes, want 304 and no body", w.Code, w.Body.Len())
+	}
+
+	reader.mail = append(reader.mail, &mail.Message{ID: "hq-m2"})
+	h.ttl = 0
+	w = getV1(t, h, "/api/v1/mail?address=mayor/", http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("changed data should produce 200 with a new ETag, got %d", w.Code)
+	}
+}
+
+func TestV1Handler_Errors(t *testing.T) {
+	h := newTestV1Handler(&fakeTownReader{})
+	tests := []struct {
+		method string
+		url    string
+		token  string
+		want   int
+	}{
+		{http.MethodGet, "/api/v1/nope", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/convoys?limit=0", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/convoys?cursor=!!", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/convoys?status=weird", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/polecats?rig=../etc", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/polecats?rig=missing", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/merge-queue", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/convoys", "", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/convoys", "test-token", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("X-Dashboard-Token", tt.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -127,6 +127,11 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}, nil
 }
 
+// TownRoot returns the root of the town the fetcher reads.
+func (f *LiveConvoyFetcher) TownRoot() string {
+	return f.townRoot
+}
+
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	// List all open convoy issues
@@ -369,6 +369,9 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 	staticHandler := http.FileServer(http.FS(staticFS))
 
 	mux := http.NewServeMux()
+	if tr, ok := fetcher.(interface{ TownRoot() string }); ok && tr.TownRoot() != "" {
+		mux.Handle("/api/v1/", NewV1Handler(NewLiveTownReader(tr.TownRoot()), csrfToken))
+	}
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
 	mux.Handle("/", convoyHandler)
@@ -0,0 +1,159 @@
+package web
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// TownReader reads town state for the /api/v1 endpoints.
+// Implementations return items in a stable order so pages don't shift
+// between polls.
+type TownReader interface {
+	Convoys(status string) ([]*beads.Issue, error)
+	Mail(address string) ([]*mail.Message, error)
+	Rigs() ([]*rig.Rig, error)
+	Polecats(rigName string) ([]*polecat.Polecat, error)
+	MergeQueue(rigName string) ([]refinery.QueueItem, error)
+	Escalations() ([]*beads.Issue, error)
+	Hooks() ([]*beads.Issue, error)
+}
+
+// LiveTownReader reads a town through the beads, mail, rig, polecat and
+// refinery packages instead of exec'ing gt.
+type LiveTownReader struct {
+	townRoot string
+}
+
+// NewLiveTownReader creates a reader for the town at townRoot.
+func NewLiveTownReader(townRoot string) *LiveTownReader {
+	return &LiveTownReader{townRoot: townRoot}
+}
+
+func (t *LiveTownReader) townBeads() *beads.Beads {
+	return beads.New(filepath.Join(t.townRoot, ".beads"))
+}
+
+// Convoys lists convoys with the given status ("open", "closed" or "all").
+func (t *LiveTownReader) Convoys(status string) ([]*beads.Issue, error) {
+	issues, err := t.townBeads().List(beads.ListOptions{Type: "convoy", Status: status, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+	sortIssuesByID(issues)
+	return issues, nil
+}
+
+// Mail lists the messages in an address's mailbox, newest first.
+func (t *LiveTownReader) Mail(address string) ([]*mail.Message, error) {
+	msgs, err := mail.NewMailboxFromAddress(address, t.townRoot).List()
+	if err != nil {
+		return nil, fmt.Errorf("listing mail for %s: %w", address, err)
+	}
+	sort.SliceStable(msgs, func(i, j int) bool {
+		if !msgs[i].Timestamp.Equal(msgs[j].Timestamp) {
+			return msgs[i].Timestamp.After(msgs[j].Timestamp)
+		}
+		return msgs[i].ID < msgs[j].ID
+	})
+	return msgs, nil
+}
+
+// Rigs lists the town's rigs by name.
+func (t *LiveTownReader) Rigs() ([]*rig.Rig, error) {
+	rigs, err := t.rigManager().DiscoverRigs()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	return rigs, nil
+}
+
+// Polecats lists polecats in one rig, or in every rig if rigName is empty.
+func (t *LiveTownReader) Polecats(rigName string) ([]*polecat.Polecat, error) {
+	rigs, err := t.selectRigs(rigName)
+	if err != nil {
+		return nil, err
+	}
+	var out []*polecat.Polecat
+	tm := tmux.NewTmux()
+	for _, r := range rigs {
+		polecats, err := polecat.NewManager(r, git.NewGit(r.Path), tm).List()
+		if err != nil {
+			return nil, fmt.Errorf("listing polecats in %s: %w", r.Name, err)
+		}
+		sort.Slice(polecats, func(i, j int) bool { return polecats[i].Name < polecats[j].Name })
+		out = append(out, polecats...)
+	}
+	return out, nil
+}
+
+// MergeQueue lists queued merge requests in one rig, or in every rig if
+// rigName is empty, each rig in refinery processing order.
+func (t *LiveTownReader) MergeQueue(rigName string) ([]refinery.QueueItem, error) {
+	rigs, err := t.selectRigs(rigName)
+	if err != nil {
+		return nil, err
+	}
+	var out []refinery.QueueItem
+	for _, r := range rigs {
+		items, err := refinery.NewManager(r).Queue()
+		if err != nil {
+			return nil, fmt.Errorf("reading merge queue of %s: %w", r.Name, err)
+		}
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+// Escalations lists open escalations.
+func (t *LiveTownReader) Escalations() ([]*beads.Issue, error) {
+	issues, err := t.townBeads().ListEscalations()
+	if err != nil {
+		return nil, fmt.Errorf("listing escalations: %w", err)
+	}
+	sortIssuesByID(issues)
+	return issues, nil
+}
+
+// Hooks lists beads hooked to agents.
+func (t *LiveTownReader) Hooks() ([]*beads.Issue, error) {
+	issues, err := t.townBeads().List(beads.ListOptions{Status: beads.StatusHooked, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing hooked beads: %w", err)
+	}
+	sortIssuesByID(issues)
+	return issues, nil
+}
+
+func (t *LiveTownReader) rigManager() *rig.Manager {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(t.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	return rig.NewManager(t.townRoot, rigsConfig, git.NewGit(t.townRoot))
+}
+
+func (t *LiveTownReader) selectRigs(rigName string) ([]*rig.Rig, error) {
+	if rigName == "" {
+		return t.Rigs()
+	}
+	r, err := t.rigManager().GetRig(rigName)
+	if err != nil {
+		return nil, fmt.Errorf("rig %q: %w", rigName, err)
+	}
+	return []*rig.Rig{r}, nil
+}
+
+func sortIssuesByID(issues []*beads.Issue) {
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+}
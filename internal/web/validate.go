@@ -31,6 +31,12 @@ func isValidID(s string) bool {
 	return len(s) > 0 && len(s) <= 200 && idPattern.MatchString(s)
 }
 
+// isValidAttachmentName checks if a string is a plausible mail attachment
+// name: a bare file name with no path separators.
+func isValidAttachmentName(s string) bool {
+	return len(s) > 0 && len(s) <= 255 && s != "." && s != ".." && !strings.ContainsAny(s, "/\\\x00")
+}
+
 // isValidRigName checks if a string is a valid rig name.
 // Rig names allow only alphanumeric + underscore (no hyphens, dots, or spaces),
 // matching the constraint in internal/rig/manager.go:AddRig.
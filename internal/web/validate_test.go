@@ -657,3 +657,15 @@ func TestExpandHomePath_RootUser(t *testing.T) {
 		t.Errorf("expandHomePath(\"~/projects\") = %q, want suffix %q", result, wantSuffix)
 	}
 }
+
+func TestHandler_MailRead_InvalidAttachmentName(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, "test-token")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/mail/read?id=hq-1&attachment=../etc/passwd", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/mail/read with attachment=../etc/passwd status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -1622,7 +1622,9 @@ func getBeadStatus(workDir, beadID string) string {
 // 1. Records the respawn in the witness spawn-count ledger
 // 2. Resets status to open
 // 3. Clears assignee
-// 4. Sends mail to deacon for re-dispatch (includes respawn count; SPAWN_STORM
+// 4. Records the polecat's agent as failed, so the next sling escalates
+//    past it when model routing is configured
+// 5. Sends mail to deacon for re-dispatch (includes respawn count; SPAWN_STORM
 //    prefix and Urgent priority when count exceeds defaultMaxBeadRespawns)
 // Returns true if the bead was recovered.
 func resetAbandonedBead(workDir, rigName, hookBead, polecatName string, router *mail.Router) bool {
@@ -1641,6 +1643,7 @@ func resetAbandonedBead(workDir, rigName, hookBead, polecatName string, router *
 	if err := bdRun(workDir, "update", hookBead, "--status=open", "--assignee="); err != nil {
 		return false
 	}
+	recordFailedDispatch(workDir, hookBead) // Best-effort
 
 	// Send mail to deacon for re-dispatch
 	if router != nil {
@@ -1677,6 +1680,25 @@ Please re-dispatch to an available polecat.`,
 	return true
 }
 
+// recordFailedDispatch marks the bead's last dispatched agent as failed.
+func recordFailedDispatch(workDir, beadID string) {
+	output, err := bdExec(workDir, "show", beadID, "--json")
+	if err != nil || output == "" {
+		return
+	}
+
+	var issues []struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+		return
+	}
+
+	if desc := beads.RecordFailedDispatch(&beads.Issue{Description: issues[0].Description}); desc != "" {
+		_ = bdRun(workDir, "update", beadID, "--description", desc)
+	}
+}
+
 // OrphanedBeadResult contains a single detected orphaned bead.
 type OrphanedBeadResult struct {
 	BeadID        string
@@ -0,0 +1,78 @@
+#!/usr/bin/env bash
+# beads-backup entrypoint, run directly by the daemon (see plugin.md).
+# Reads the plugin context as JSON on stdin and prints a JSON result on stdout.
+# Progress and warnings go to stderr.
+set -euo pipefail
+
+fail() {
+  printf '{"result":"failure","summary":"%s"}\n' "$1"
+  exit 1
+}
+
+TOWN_ROOT="${GT_TOWN_ROOT:-${GT_ROOT:-}}"
+if [ -z "$TOWN_ROOT" ] || [ ! -d "$TOWN_ROOT" ]; then
+  printf '{"result":"skipped","summary":"town root not set or missing"}\n'
+  exit 0
+fi
+
+BACKUP_ROOT="$TOWN_ROOT/backups/beads"
+TIMESTAMP="$(date -u +%Y%m%d-%H%M%S)"
+SNAPSHOT_DIR="$BACKUP_ROOT/$TIMESTAMP"
+mkdir -p "$SNAPSHOT_DIR" || fail "cannot create $SNAPSHOT_DIR"
+
+# Quiesce Dolt writes (best-effort) and restart it on exit.
+DOLT_WAS_RUNNING=0
+if gt dolt status 2>/dev/null | grep -q "is running"; then
+  if gt dolt stop >/dev/null 2>&1; then
+    DOLT_WAS_RUNNING=1
+  else
+    echo "WARN: could not stop Dolt server cleanly; continuing with live snapshot" >&2
+  fi
+fi
+restart_dolt() {
+  if [ "$DOLT_WAS_RUNNING" = "1" ] && ! gt dolt start >/dev/null 2>&1; then
+    echo "WARN: Dolt server was running before backup but failed to restart" >&2
+  fi
+}
+trap restart_dolt EXIT
+# The daemon sends SIGTERM on timeout (SIGKILL only after a grace period);
+# exit through the EXIT trap so Dolt comes back up.
+trap 'exit 143' TERM INT
+
+if [ -d "$TOWN_ROOT/.dolt-data" ]; then
+  tar -C "$TOWN_ROOT" -czf "$SNAPSHOT_DIR/dolt-data.tgz" ".dolt-data" || fail "archiving .dolt-data"
+else
+  echo "WARN: no .dolt-data directory at $TOWN_ROOT/.dolt-data" >&2
+fi
+if [ -d "$TOWN_ROOT/.beads" ]; then
+  tar -C "$TOWN_ROOT" -czf "$SNAPSHOT_DIR/town-beads.tgz" ".beads" || fail "archiving town .beads"
+else
+  echo "WARN: no town .beads directory at $TOWN_ROOT/.beads" >&2
+fi
+
+RIG_COUNT=0
+RIG_BACKUPS=0
+for RIG in $(gt rig list --json 2>/dev/null | jq -r '.[].name // empty' || true); do
+  RIG_COUNT=$((RIG_COUNT + 1))
+  if [ -d "$TOWN_ROOT/$RIG/mayor/rig/.beads" ]; then
+    tar -C "$TOWN_ROOT/$RIG/mayor/rig" -czf "$SNAPSHOT_DIR/${RIG}-beads.tgz" ".beads" || fail "archiving $RIG .beads"
+    RIG_BACKUPS=$((RIG_BACKUPS + 1))
+  fi
+done
+
+{
+  echo "timestamp=$TIMESTAMP"
+  echo "town_root=$TOWN_ROOT"
+  echo "created_at_utc=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+  echo "rigs_seen=$RIG_COUNT"
+  echo "rig_backups=$RIG_BACKUPS"
+} > "$SNAPSHOT_DIR/manifest.txt"
+(cd "$SNAPSHOT_DIR" && sha256sum ./*.tgz > SHA256SUMS.txt 2>/dev/null) || true
+
+# Keep the most recent 48 snapshots.
+find "$BACKUP_ROOT" -mindepth 1 -maxdepth 1 -type d | sort -r | awk 'NR>48' | xargs -r rm -rf
+
+ARCHIVE_COUNT=$(find "$SNAPSHOT_DIR" -maxdepth 1 -name '*.tgz' | wc -l | tr -d ' ')
+TOTAL_SIZE=$(du -sh "$SNAPSHOT_DIR" | awk '{print $1}')
+printf '{"result":"success","summary":"snapshot=%s archives=%s size=%s rig_backups=%s"}\n' \
+  "$TIMESTAMP" "$ARCHIVE_COUNT" "$TOTAL_SIZE" "$RIG_BACKUPS"